@@ -68,7 +68,7 @@ export const commandDefinitions: CommandDefinition[] = [
 	},
 	{
 		id: 'importData',
-		label: 'Import CSV or JSON',
+		label: 'Import CSV, JSON, Parquet, or Excel',
 		description: 'Load a file into an existing or new table',
 		group: 'Workspace'
 	},
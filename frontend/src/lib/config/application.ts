@@ -21,17 +21,21 @@ export const UI_RUNTIME = Object.freeze({
 	connectionHealthRefreshMs: 15_000,
 	exportProgressPollMs: 150,
 	maintenanceProgressPollMs: 1_000,
+	rowCountPollMs: 500,
+	dryRunSampleRows: 5,
 	persistenceDebounceMs: 180,
 	sqlLintDebounceMs: 180,
 	copyFeedbackMs: 1_600,
-	consoleHistoryLimit: 100
+	consoleHistoryLimit: 100,
+	queryCursorPageRows: 1_000
 });
 
 export const TIME = Object.freeze({
 	millisecondsPerSecond: 1_000
 });
 
-export type ProviderId = 'postgres' | 'mysql' | 'sqlite' | 'oracle' | 'sqlserver';
+// Provider ids are registry driver names, so in-house engines are plain strings too.
+export type ProviderId = string;
 export type ConnectionEnvironment = 'unclassified' | 'development' | 'staging' | 'production';
 export type ConnectionAccessMode = 'read-write' | 'read-only';
 export type OracleConnectionMode = 'direct' | 'tns';
@@ -45,7 +49,7 @@ export type SQLServerAuthMode =
 	| 'entra-managed-identity'
 	| 'entra-azure-cli';
 
-export const DATABASE_PROVIDERS: ReadonlyArray<{
+export interface DatabaseProvider {
 	id: ProviderId;
 	name: string;
 	description: string;
@@ -54,9 +58,14 @@ export const DATABASE_PROVIDERS: ReadonlyArray<{
 	defaultUser: string;
 	databaseLabel: string;
 	supportsClientCertificates: boolean;
+	fileDatabase: boolean;
 	available: boolean;
 	mark: string;
-}> = [
+}
+
+// Presentation defaults for engines the app ships with. Which engines are
+// offered comes from GetAvailableDrivers; see registeredProviders.
+export const DATABASE_PROVIDERS: ReadonlyArray<DatabaseProvider> = [
 	{
 		id: 'postgres',
 		name: 'PostgreSQL',
@@ -66,6 +75,7 @@ export const DATABASE_PROVIDERS: ReadonlyArray<{
 		defaultUser: 'postgres',
 		databaseLabel: 'Database name',
 		supportsClientCertificates: true,
+		fileDatabase: false,
 		available: true,
 		mark: 'PG'
 	},
@@ -78,21 +88,75 @@ export const DATABASE_PROVIDERS: ReadonlyArray<{
 		defaultUser: 'root',
 		databaseLabel: 'Database name',
 		supportsClientCertificates: true,
+		fileDatabase: false,
 		available: true,
 		mark: 'MY'
 	},
+	{
+		id: 'mariadb',
+		name: 'MariaDB',
+		description: 'MariaDB server connections.',
+		defaultPort: '3306',
+		defaultDatabase: 'app',
+		defaultUser: 'root',
+		databaseLabel: 'Database name',
+		supportsClientCertificates: true,
+		fileDatabase: false,
+		available: true,
+		mark: 'MA'
+	},
+	{
+		id: 'cockroachdb',
+		name: 'CockroachDB',
+		description: 'Distributed PostgreSQL-compatible clusters.',
+		defaultPort: '26257',
+		defaultDatabase: 'defaultdb',
+		defaultUser: 'root',
+		databaseLabel: 'Database name',
+		supportsClientCertificates: true,
+		fileDatabase: false,
+		available: true,
+		mark: 'CR'
+	},
+	{
+		id: 'yugabytedb',
+		name: 'YugabyteDB',
+		description: 'YugabyteDB YSQL connections.',
+		defaultPort: '5433',
+		defaultDatabase: 'yugabyte',
+		defaultUser: 'yugabyte',
+		databaseLabel: 'Database name',
+		supportsClientCertificates: true,
+		fileDatabase: false,
+		available: true,
+		mark: 'YB'
+	},
 	{
 		id: 'sqlite',
 		name: 'SQLite',
 		description: 'Open a local SQLite database file.',
 		defaultPort: '',
-		defaultDatabase: '',
+		defaultDatabase: '/path/to/database.sqlite3',
 		defaultUser: '',
 		databaseLabel: 'SQLite file path',
 		supportsClientCertificates: false,
+		fileDatabase: true,
 		available: true,
 		mark: 'SQ'
 	},
+	{
+		id: 'duckdb',
+		name: 'DuckDB',
+		description: 'Open a DuckDB file, or :memory:, and query Parquet and CSV files.',
+		defaultPort: '',
+		defaultDatabase: '/path/to/analytics.duckdb or :memory:',
+		defaultUser: '',
+		databaseLabel: 'DuckDB file path',
+		supportsClientCertificates: false,
+		fileDatabase: true,
+		available: true,
+		mark: 'DK'
+	},
 	{
 		id: 'oracle',
 		name: 'Oracle Database',
@@ -102,6 +166,7 @@ export const DATABASE_PROVIDERS: ReadonlyArray<{
 		defaultUser: 'system',
 		databaseLabel: 'Service name',
 		supportsClientCertificates: true,
+		fileDatabase: false,
 		available: true,
 		mark: 'OR'
 	},
@@ -114,11 +179,71 @@ export const DATABASE_PROVIDERS: ReadonlyArray<{
 		defaultUser: 'sa',
 		databaseLabel: 'Database name',
 		supportsClientCertificates: false,
+		fileDatabase: false,
 		available: true,
 		mark: 'MS'
+	},
+	{
+		id: 'clickhouse',
+		name: 'ClickHouse',
+		description: 'ClickHouse databases over the native protocol.',
+		defaultPort: '9000',
+		defaultDatabase: 'default',
+		defaultUser: 'default',
+		databaseLabel: 'Database name',
+		supportsClientCertificates: false,
+		fileDatabase: false,
+		available: true,
+		mark: 'CH'
 	}
 ];
 
+const registeredProviderList: DatabaseProvider[] = [];
+
+function genericProvider(id: string, name = id, fileDatabase = false): DatabaseProvider {
+	return {
+		id,
+		name,
+		description: fileDatabase ? `Open a local ${name} database file.` : `${name} connections.`,
+		defaultPort: '',
+		defaultDatabase: '',
+		defaultUser: '',
+		databaseLabel: fileDatabase ? `${name} file path` : 'Database name',
+		supportsClientCertificates: false,
+		fileDatabase,
+		available: true,
+		mark: name.replace(/[^a-z0-9]/gi, '').slice(0, 2).toUpperCase() || '??'
+	};
+}
+
+// registerDriverProbes replaces the registered provider list with the engines
+// GetAvailableDrivers reported, keeping the presentation defaults above for
+// engines the app knows and deriving the rest from each probe.
+export function registerDriverProbes(
+	probes: ReadonlyArray<{
+		driver: string;
+		capabilities?: { displayName?: string; fileDatabase?: boolean };
+	}>
+): ReadonlyArray<DatabaseProvider> {
+	registeredProviderList.length = 0;
+	for (const probe of probes) {
+		const known = DATABASE_PROVIDERS.find((provider) => provider.id === probe.driver);
+		registeredProviderList.push(
+			known ??
+				genericProvider(
+					probe.driver,
+					probe.capabilities?.displayName?.trim() || probe.driver,
+					Boolean(probe.capabilities?.fileDatabase)
+				)
+		);
+	}
+	return registeredProviders();
+}
+
+export function registeredProviders(): ReadonlyArray<DatabaseProvider> {
+	return [...registeredProviderList];
+}
+
 export const CONNECTION_DEFAULTS = Object.freeze({
 	host: '127.0.0.1',
 	sshPort: '22',
@@ -290,15 +415,13 @@ export function normalizeConnectionAccessMode(
 		: CONNECTION_DEFAULTS.accessMode;
 }
 
-export function providerOption(value?: string) {
+export function providerOption(value?: string): DatabaseProvider {
+	const lowered = value?.trim().toLowerCase();
 	const normalized =
-		value?.trim().toLowerCase() === 'mariadb'
-			? 'mysql'
-			: value?.trim().toLowerCase() === 'postgresql'
-				? 'postgres'
-				: value?.trim().toLowerCase();
+		lowered === 'postgresql' ? 'postgres' : lowered || CONNECTION_DEFAULTS.provider;
 	return (
+		registeredProviderList.find((provider) => provider.id === normalized) ??
 		DATABASE_PROVIDERS.find((provider) => provider.id === normalized) ??
-		DATABASE_PROVIDERS.find((provider) => provider.id === CONNECTION_DEFAULTS.provider)!
+		genericProvider(normalized)
 	);
 }
@@ -1,6 +1,7 @@
 export type ExportScope = 'page' | 'all' | 'loaded' | 'selected';
-export type ExportFormat = 'csv' | 'json' | 'sql';
+export type ExportFormat = 'csv' | 'json' | 'ndjson' | 'sql' | 'parquet' | 'xlsx';
 export type CSVEncoding = 'utf-8' | 'utf-8-bom' | 'utf-16le';
+export type ParquetCompression = 'snappy' | 'zstd' | 'none';
 
 export interface ExportSettings {
 	scope: ExportScope;
@@ -13,6 +14,8 @@ export interface ExportSettings {
 	sqlBatchSize: number;
 	includeTransaction: boolean;
 	upsert: boolean;
+	parquetCompression: ParquetCompression;
+	xlsxStyleHeader: boolean;
 }
 
 export function buildExportOptions(settings: ExportSettings) {
@@ -27,6 +30,29 @@ export function buildExportOptions(settings: ExportSettings) {
 		};
 	}
 
+	if (settings.format === 'parquet') {
+		return {
+			format: 'parquet',
+			parquet: {
+				compression: settings.parquetCompression
+			}
+		};
+	}
+
+	if (settings.format === 'xlsx') {
+		return {
+			format: 'xlsx',
+			xlsx: {
+				includeHeader: settings.includeHeader,
+				styleHeader: settings.xlsxStyleHeader
+			}
+		};
+	}
+
+	if (settings.format === 'ndjson') {
+		return { format: 'ndjson' };
+	}
+
 	if (settings.format === 'json') {
 		return {
 			format: 'json',
@@ -47,7 +73,7 @@ export function buildExportOptions(settings: ExportSettings) {
 	};
 }
 
-export function getExportExtension(format: ExportFormat): 'csv' | 'json' | 'sql' {
+export function getExportExtension(format: ExportFormat): ExportFormat {
 	return format;
 }
 
@@ -19,6 +19,7 @@ export interface Tab {
 	sqlFileName?: string;
 	sqlFileSavedContent?: string;
 	savedQueryId?: string;
+	serverOutput?: boolean;
 	status?: string;
 	level?: 'info' | 'warn' | 'error';
 	activeSubTab?: 'structure' | 'data' | 'ddl';
@@ -10,3 +10,141 @@ export function getQueryResultPage<T>(
 	const start = safePage * safePageSize;
 	return rows.slice(start, start + safePageSize);
 }
+
+export interface QueryResultColumnInput {
+	name: string;
+	type?: string;
+	nullable?: boolean;
+	length?: number;
+	precision?: number;
+	scale?: number;
+}
+
+export interface QueryResultColumn {
+	name: string;
+	data_type: string;
+	nullable: boolean;
+	length?: number;
+}
+
+/**
+ * Result rows arrive as value arrays so repeated column names survive. The
+ * grid addresses cells by key, so later repeats are suffixed: id, id_2.
+ */
+export function getQueryResultColumnKeys(names: string[]): string[] {
+	const used = new Set<string>();
+	return names.map((name) => {
+		let key = name;
+		for (let suffix = 2; used.has(key); suffix++) key = `${name}_${suffix}`;
+		used.add(key);
+		return key;
+	});
+}
+
+export function getQueryResultRecords(
+	keys: string[],
+	rows: unknown[][]
+): Record<string, unknown>[] {
+	return rows.map((values) => {
+		const record: Record<string, unknown> = {};
+		keys.forEach((key, index) => {
+			record[key] = values[index];
+		});
+		return record;
+	});
+}
+
+export function getQueryResultColumns(
+	columns: QueryResultColumnInput[],
+	keys: string[],
+	rows: unknown[][]
+): QueryResultColumn[] {
+	return columns.map((column, index) => {
+		let dataType = column.type?.toLowerCase() || '';
+		if (dataType && column.precision !== undefined) {
+			dataType += `(${column.precision},${column.scale ?? 0})`;
+		}
+		if (!dataType) dataType = typeof rows[0]?.[index] === 'number' ? 'number' : 'text';
+		return {
+			name: keys[index],
+			data_type: dataType,
+			nullable: column.nullable ?? true,
+			length: column.length
+		};
+	});
+}
+
+export interface QueryResultSetSummaryInput {
+	rows?: unknown[][];
+	rowsAffected?: number;
+	commandTag?: string;
+	wallClockMs?: number;
+}
+
+/**
+ * One-line outcome of a statement: its command tag, row count, and timing.
+ * The timing is wall clock measured by the app, not server execution time,
+ * which the drivers do not report.
+ */
+export function getQueryResultSummary(set: QueryResultSetSummaryInput): string {
+	const count =
+		set.rowsAffected !== undefined && set.rowsAffected !== null
+			? `${set.rowsAffected.toLocaleString()} ${set.rowsAffected === 1 ? 'row' : 'rows'} affected`
+			: `${(set.rows?.length || 0).toLocaleString()} ${set.rows?.length === 1 ? 'row' : 'rows'}`;
+	const timing = set.wallClockMs !== undefined ? `${set.wallClockMs}ms wall clock` : '';
+	return [set.commandTag, count, timing].filter(Boolean).join(' · ');
+}
+
+export type QueryMessageLogLevel = 'info' | 'warn' | 'error';
+
+export function getQueryMessageLogLevel(severity: string | undefined): QueryMessageLogLevel {
+	if (severity === 'error') return 'error';
+	if (severity === 'warning') return 'warn';
+	return 'info';
+}
+
+export interface QueryCursorPageInput {
+	rows?: unknown[][];
+	done: boolean;
+}
+
+/**
+ * Appends a page read from an open result cursor. The set stays truncated
+ * until the cursor reports that nothing is left.
+ */
+export function appendQueryCursorPage<T extends { rows?: unknown[][]; truncated?: boolean }>(
+	set: T,
+	page: QueryCursorPageInput
+): T {
+	return { ...set, rows: [...(set.rows ?? []), ...(page.rows ?? [])], truncated: !page.done };
+}
+
+export interface DryRunStatementInput {
+	rowsAffected?: number | null;
+	before?: unknown[][] | null;
+	after?: unknown[][] | null;
+}
+
+/** What a dry-run statement would have changed before it was rolled back. */
+export function getDryRunChangeSummary(statement: DryRunStatementInput): string {
+	const count = statement.rowsAffected;
+	if (count === undefined || count === null) return 'No row count reported';
+	return `${count.toLocaleString()} ${count === 1 ? 'row' : 'rows'} would change`;
+}
+
+export interface DryRunSampleRow {
+	before?: unknown[];
+	after?: unknown[];
+}
+
+/**
+ * Pairs the sampled before and after images by position. Deletes only
+ * have a before image and inserts only an after image.
+ */
+export function getDryRunSampleRows(statement: DryRunStatementInput): DryRunSampleRow[] {
+	const length = Math.max(statement.before?.length || 0, statement.after?.length || 0);
+	return Array.from({ length }, (_, index) => ({
+		before: statement.before?.[index],
+		after: statement.after?.[index]
+	}));
+}
@@ -0,0 +1,48 @@
+import { GetAvailableDrivers } from '$lib/wailsjs/go/db/Service';
+import { createServiceError } from '$lib/errors/service';
+import { BACKEND_RESTART_MESSAGE, hasBackendMethod } from '$lib/wails/backendCompatibility';
+import {
+	providerOption,
+	registerDriverProbes,
+	type DatabaseProvider
+} from '$lib/config/application';
+
+// The connection form offers exactly the engines the backend registered,
+// including in-house and plugin drivers.
+export const providerState = $state({
+	providers: [] as DatabaseProvider[],
+	loading: false,
+	loaded: false,
+	error: ''
+});
+
+export async function loadProviders(): Promise<void> {
+	if (providerState.loading) return;
+	if (!hasBackendMethod('GetAvailableDrivers')) {
+		providerState.error = BACKEND_RESTART_MESSAGE;
+		return;
+	}
+	providerState.loading = true;
+	providerState.error = '';
+	try {
+		const response = await GetAvailableDrivers();
+		if (response.errors?.length) {
+			throw createServiceError(response.errors[0], 'Could not load database engines');
+		}
+		providerState.providers = [...registerDriverProbes(response.data ?? [])];
+		providerState.loaded = true;
+	} catch (error: any) {
+		providerState.error = error?.message ?? 'Could not load database engines.';
+	} finally {
+		providerState.loading = false;
+	}
+}
+
+// registeredProvider is providerOption that re-renders once the registry has
+// loaded, so in-house engines show their display names.
+export function registeredProvider(id?: string): DatabaseProvider {
+	const normalized = id?.trim().toLowerCase();
+	return (
+		providerState.providers.find((provider) => provider.id === normalized) ?? providerOption(id)
+	);
+}
@@ -6,21 +6,51 @@ export type FilterOperator =
 	| 'gte'
 	| 'lte'
 	| 'contains'
+	| 'not_contains'
+	| 'starts_with'
+	| 'ends_with'
+	| 'ilike'
+	| 'regex'
+	| 'in'
+	| 'not_in'
+	| 'between'
 	| 'is_null'
 	| 'is_not_null';
 
+export type FilterLogic = 'and' | 'or';
+
 export interface FilterCondition {
 	id: string;
 	column: string;
 	operator: FilterOperator;
 	value: string;
+	// Upper bound of a between filter; value holds the lower bound.
+	valueTo?: string;
+	// JSON path inside the column, e.g. customer.id or items[0].sku.
+	path?: string;
 	enabled: boolean;
 }
 
+export interface FilterGroupState {
+	id: string;
+	logic: FilterLogic;
+	conditions: FilterCondition[];
+	groups: FilterGroupState[];
+}
+
+export type DatabaseFilterValue = string | number;
+
 export interface DatabaseFilter {
 	Column: string;
+	Path?: string;
 	Operator: FilterOperator;
-	Value: string | null;
+	Value: DatabaseFilterValue | DatabaseFilterValue[] | null;
+}
+
+export interface DatabaseFilterGroup {
+	Logic: FilterLogic;
+	Filters: DatabaseFilter[];
+	Groups: DatabaseFilterGroup[];
 }
 
 export const FILTER_OPERATORS: Array<{ value: FilterOperator; label: string }> = [
@@ -30,26 +60,173 @@ export const FILTER_OPERATORS: Array<{ value: FilterOperator; label: string }> =
 	{ value: 'lt', label: 'less than' },
 	{ value: 'gte', label: 'greater or equal' },
 	{ value: 'lte', label: 'less or equal' },
+	{ value: 'between', label: 'between' },
+	{ value: 'in', label: 'is one of' },
+	{ value: 'not_in', label: 'is none of' },
 	{ value: 'contains', label: 'contains' },
+	{ value: 'not_contains', label: 'does not contain' },
+	{ value: 'starts_with', label: 'starts with' },
+	{ value: 'ends_with', label: 'ends with' },
+	{ value: 'ilike', label: 'matches pattern (any case)' },
+	{ value: 'regex', label: 'matches regex' },
 	{ value: 'is_null', label: 'is null' },
 	{ value: 'is_not_null', label: 'is not null' }
 ];
 
+export const FILTER_LOGIC_OPTIONS: Array<{ value: FilterLogic; label: string }> = [
+	{ value: 'and', label: 'Match all' },
+	{ value: 'or', label: 'Match any' }
+];
+
+export function filterOperatorsFor(
+	capabilities: { regexFilters?: boolean } | null
+): Array<{ value: FilterOperator; label: string }> {
+	return capabilities?.regexFilters
+		? FILTER_OPERATORS
+		: FILTER_OPERATORS.filter((operator) => operator.value !== 'regex');
+}
+
 export function filterNeedsValue(operator: FilterOperator): boolean {
 	return operator !== 'is_null' && operator !== 'is_not_null';
 }
 
+export function filterTakesList(operator: FilterOperator): boolean {
+	return operator === 'in' || operator === 'not_in';
+}
+
+// List values are typed comma-separated; blanks around each item are
+// dropped so "a, b" and "a,b" send the same list.
+export function splitFilterList(value: string): string[] {
+	return value
+		.split(',')
+		.map((item) => item.trim())
+		.filter(Boolean);
+}
+
+export function isJSONColumn(column: { data_type?: string } | null | undefined): boolean {
+	return /json/i.test(column?.data_type ?? '');
+}
+
+const PATTERN_OPERATORS = new Set<FilterOperator>([
+	'contains',
+	'not_contains',
+	'starts_with',
+	'ends_with',
+	'ilike',
+	'regex'
+]);
+
+// A JSON value has its own type, so path filters send numbers as numbers;
+// the backend then compares numerically instead of as text.
+export function jsonFilterValue(operator: FilterOperator, value: string): DatabaseFilterValue {
+	if (PATTERN_OPERATORS.has(operator)) return value;
+	const trimmed = value.trim();
+	return /^-?\d+(\.\d+)?([eE][+-]?\d+)?$/.test(trimmed) ? Number(trimmed) : value;
+}
+
+function buildDatabaseFilter(filter: FilterCondition): DatabaseFilter | null {
+	const column = filter.column.trim();
+	if (!filter.enabled || !column) return null;
+	const path = filter.path?.trim() ?? '';
+	const base = path
+		? { Column: column, Path: path, Operator: filter.operator }
+		: { Column: column, Operator: filter.operator };
+	const typed = (value: string): DatabaseFilterValue =>
+		path ? jsonFilterValue(filter.operator, value) : value;
+	if (!filterNeedsValue(filter.operator)) {
+		return { ...base, Value: null };
+	}
+	if (filterTakesList(filter.operator)) {
+		const values = splitFilterList(filter.value);
+		return values.length ? { ...base, Value: values.map(typed) } : null;
+	}
+	if (filter.operator === 'between') {
+		const upper = filter.valueTo ?? '';
+		return filter.value && upper ? { ...base, Value: [typed(filter.value), typed(upper)] } : null;
+	}
+	return filter.value ? { ...base, Value: typed(filter.value) } : null;
+}
+
 export function buildDatabaseFilters(filters: FilterCondition[]): DatabaseFilter[] {
 	return filters
-		.filter(
-			(filter) =>
-				filter.enabled &&
-				Boolean(filter.column.trim()) &&
-				(!filterNeedsValue(filter.operator) || Boolean(filter.value))
-		)
-		.map((filter) => ({
-			Column: filter.column.trim(),
-			Operator: filter.operator,
-			Value: filterNeedsValue(filter.operator) ? filter.value : null
-		}));
+		.map(buildDatabaseFilter)
+		.filter((filter): filter is DatabaseFilter => filter !== null);
+}
+
+export function createFilterGroup(logic: FilterLogic = 'and'): FilterGroupState {
+	return { id: crypto.randomUUID(), logic, conditions: [], groups: [] };
+}
+
+// buildDatabaseFilterGroup drops disabled and incomplete conditions, and
+// then any group left without conditions, so the backend never receives an
+// empty group.
+export function buildDatabaseFilterGroup(group: FilterGroupState): DatabaseFilterGroup | null {
+	const filters = buildDatabaseFilters(group.conditions);
+	const groups = group.groups
+		.map(buildDatabaseFilterGroup)
+		.filter((child): child is DatabaseFilterGroup => child !== null);
+	if (!filters.length && !groups.length) return null;
+	return { Logic: group.logic, Filters: filters, Groups: groups };
+}
+
+// buildTableFilters keeps a plain "match all" list in Table.Filters, which
+// every driver understands, and only sends a Where tree when the filters
+// need OR or nesting.
+export function buildTableFilters(root: FilterGroupState): {
+	Filters: DatabaseFilter[];
+	Where: DatabaseFilterGroup | null;
+} {
+	const tree = buildDatabaseFilterGroup(root);
+	if (!tree) return { Filters: [], Where: null };
+	if (tree.Logic === 'and' && !tree.Groups.length) {
+		return { Filters: tree.Filters, Where: null };
+	}
+	return { Filters: [], Where: tree };
+}
+
+export function countFilterConditions(group: FilterGroupState, enabledOnly = false): number {
+	return (
+		group.conditions.filter((condition) => !enabledOnly || condition.enabled).length +
+		group.groups.reduce((total, child) => total + countFilterConditions(child, enabledOnly), 0)
+	);
+}
+
+export function updateFilterGroup(
+	group: FilterGroupState,
+	id: string,
+	update: (group: FilterGroupState) => FilterGroupState
+): FilterGroupState {
+	if (group.id === id) return update(group);
+	return { ...group, groups: group.groups.map((child) => updateFilterGroup(child, id, update)) };
+}
+
+export function updateFilterConditions(
+	group: FilterGroupState,
+	update: (conditions: FilterCondition[]) => FilterCondition[]
+): FilterGroupState {
+	return {
+		...group,
+		conditions: update(group.conditions),
+		groups: group.groups.map((child) => updateFilterConditions(child, update))
+	};
+}
+
+export interface JSONSortState {
+	column: string;
+	path: string;
+	desc: boolean;
+}
+
+export interface DatabaseJSONSort {
+	Column: string;
+	Path: string;
+	Direction: 'asc' | 'desc';
+	Nulls: 'last';
+}
+
+export function buildJSONSort(sort: JSONSortState | null): DatabaseJSONSort | null {
+	const column = sort?.column.trim() ?? '';
+	const path = sort?.path.trim() ?? '';
+	if (!sort || !column || !path) return null;
+	return { Column: column, Path: path, Direction: sort.desc ? 'desc' : 'asc', Nulls: 'last' };
 }
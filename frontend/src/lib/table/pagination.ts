@@ -0,0 +1,31 @@
+// The backend can hand back a continuation token with each table page so
+// the next page seeks past the last row instead of skipping OFFSET rows.
+// Tokens belong to one view of the table (its filters and sorts), so a new
+// view starts with no tokens and falls back to offsets until it has some.
+export interface PageTokens {
+	view: string;
+	tokens: Record<number, string>;
+}
+
+export function emptyPageTokens(): PageTokens {
+	return { view: '', tokens: {} };
+}
+
+export function pageTokenFor(state: PageTokens, view: string, page: number): string {
+	return state.view === view ? (state.tokens[page] ?? '') : '';
+}
+
+export function rememberPageToken(
+	state: PageTokens,
+	view: string,
+	page: number,
+	nextPage: string | undefined
+): PageTokens {
+	const tokens = state.view === view ? { ...state.tokens } : {};
+	if (nextPage) {
+		tokens[page + 1] = nextPage;
+	} else {
+		delete tokens[page + 1];
+	}
+	return { view, tokens };
+}
@@ -0,0 +1,29 @@
+// Table totals come from engine statistics when they exist, so a large table
+// opens without waiting for COUNT(*). An estimate can be off either way, so
+// paging stays open while pages keep coming back full, and stops at the
+// first short page whatever the estimate said.
+export interface RowTotal {
+	rows: number;
+	estimated: boolean;
+}
+
+export function formatRowTotal(total: RowTotal): string {
+	const rows = total.rows.toLocaleString();
+	return total.estimated ? `≈${rows}` : rows;
+}
+
+export function rowTotalPageCount(
+	total: RowTotal,
+	pageSize: number,
+	currentPage: number,
+	pageRows: number
+): number {
+	const counted = Math.ceil(total.rows / pageSize) || 1;
+	if (!total.estimated) return counted;
+	if (pageRows < pageSize) return currentPage + 1;
+	return Math.max(counted, currentPage + 2);
+}
+
+export function isRowCountRunning(job: { status: string } | null | undefined): boolean {
+	return job?.status === 'running';
+}
@@ -10,6 +10,7 @@ export interface PersistedQueryTab {
 	kind: 'query';
 	sql: string;
 	savedQueryId?: string;
+	serverOutput?: boolean;
 }
 
 export interface PersistedWorkspace {
@@ -80,7 +81,8 @@ export function parseWorkspaceEnvelope(raw: string | null): PersistedWorkspaceEn
 								title: typeof tab.title === 'string' && tab.title ? tab.title : 'SQL Query',
 								kind: 'query',
 								sql: tab.sql,
-								savedQueryId: typeof tab.savedQueryId === 'string' ? tab.savedQueryId : undefined
+								savedQueryId: typeof tab.savedQueryId === 'string' ? tab.savedQueryId : undefined,
+								serverOutput: tab.serverOutput === true || undefined
 							})
 						)
 				: [];
@@ -109,7 +111,8 @@ export function persistableWorkspace(tabs: Tab[], activeTabId: string | null): P
 				title: tab.title || 'SQL Query',
 				kind: 'query',
 				sql: tab.sql || '',
-				savedQueryId: tab.savedQueryId
+				savedQueryId: tab.savedQueryId,
+				serverOutput: tab.serverOutput || undefined
 			})
 		);
 	return {
@@ -32,10 +32,15 @@ export function CancelMaintenance(arg1:string):Promise<response.BaseResponse_boo
 
 export function CancelQuery(arg1:string):Promise<response.BaseResponse_bool_>;
 
+export function CancelRowCount(arg1:string):Promise<response.BaseResponse_bool_>;
+
 export function CheckConnection(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_ConnectionHealth_>;
 
 export function CheckForUpdates():Promise<response.BaseResponse_rollingthunder_internal_updater_CheckResult_>;
 
+export function ChooseCatalogRestoreFile(arg1:string,arg2:string):Promise<response.BaseResponse_rollingthunder_pkg_database_RestoreFileSelection_>;
+export function ChooseDuckDBDataFile():Promise<response.BaseResponse_rollingthunder_pkg_database_duckdb_DataFile_>;
+
 export function ChooseImportFile():Promise<response.BaseResponse_rollingthunder_pkg_database_ImportFileSelection_>;
 
 export function ChooseOracleTNSFile():Promise<response.BaseResponse_rollingthunder_pkg_database_OracleTNSSelection_>;
@@ -54,6 +59,8 @@ export function ClearConnectionSSHCredentials(arg1:string):Promise<response.Base
 
 export function ClearDiagnostics():Promise<response.BaseResponse_bool_>;
 
+export function CloseQueryCursor(arg1:string):Promise<response.BaseResponse_bool_>;
+
 export function CloseSQLFile(arg1:string):Promise<response.BaseResponse_bool_>;
 
 export function CommitTransaction(arg1:string):Promise<response.BaseResponse_rollingthunder_internal_db_TransactionInfo_>;
@@ -68,28 +75,47 @@ export function CountCollectionData(arg1:string,arg2:database.Table):Promise<res
 
 export function CreateTable(arg1:string,arg2:database.Table,arg3:Array<database.ColumnDefinition>):Promise<response.BaseResponse_bool_>;
 
+export function DeleteBackupSchedule(arg1:string):Promise<response.BaseResponse_bool_>;
 export function DeleteConnection(arg1:string):Promise<response.BaseResponse_bool_>;
 
 export function DeleteRow(arg1:string,arg2:database.Table,arg3:string,arg4:any):Promise<response.BaseResponse_bool_>;
 
+export function DescribeQuerySources(arg1:database.QueryRequest):Promise<response.BaseResponse___rollingthunder_pkg_database_QueryColumn_>;
+
 export function DisconnectConnection(arg1:string):Promise<response.BaseResponse_bool_>;
 
 export function DropTable(arg1:string,arg2:database.Table):Promise<response.BaseResponse_bool_>;
 
+export function DryRunQuery(arg1:database.DryRunRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_DryRunResult_>;
+
+export function EstimateCollectionData(arg1:string,arg2:database.Table):Promise<response.BaseResponse_rollingthunder_pkg_database_RowCount_>;
+
 export function ExecuteQuery(arg1:database.QueryRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_QueryResult_>;
 
 export function ExplainQuery(arg1:database.QueryRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ExplainPlan_>;
 
+export function ExportAuditLog(arg1:database.AuditExportRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ExportResult_>;
+
 export function ExportDiagnostics():Promise<response.BaseResponse_rollingthunder_internal_diagnostics_ExportResult_>;
 
 export function ExportQueryResults(arg1:database.RowsExportRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ExportResult_>;
 
+export function ExportTablesArchive(arg1:string,arg2:database.ArchiveExportRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ExportResult_>;
+
 export function ExportTableData(arg1:string,arg2:database.TableExportRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ExportResult_>;
 
+export function FetchQueryCursor(arg1:string,arg2:number):Promise<response.BaseResponse_rollingthunder_pkg_database_QueryCursorPage_>;
+
 export function GetActiveConnections():Promise<response.BaseResponse___rollingthunder_internal_db_ConnectionInfo_>;
 
+export function GetAuditLog(arg1:database.AuditLogFilter):Promise<response.BaseResponse_rollingthunder_pkg_database_AuditLogPage_>;
+
+export function GetAvailableDrivers():Promise<response.BaseResponse___rollingthunder_pkg_database_DriverProbe_>;
+
 export function GetBackupCapabilities(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_BackupCapabilities_>;
 
+export function GetBackupCatalog(arg1:string):Promise<response.BaseResponse___rollingthunder_pkg_database_BackupCatalogEntry_>;
+export function GetBackupSchedules():Promise<response.BaseResponse___rollingthunder_pkg_database_BackupScheduleStatus_>;
 export function GetCapabilities(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_Capabilities_>;
 
 export function GetCollectionData(arg1:string,arg2:database.Table):Promise<response.BaseResponse_rollingthunder_pkg_database_TableData_>;
@@ -116,12 +142,18 @@ export function GetDatabaseObjects(arg1:string,arg2:database.ObjectFilter):Promi
 
 export function GetDiagnosticsSettings():Promise<response.BaseResponse_rollingthunder_internal_diagnostics_Settings_>;
 
+export function GetDriverPlugins():Promise<response.BaseResponse_rollingthunder_internal_db_DriverPlugins_>;
+
 export function GetExportProgress(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_ExportProgress_>;
 
 export function GetIndices(arg1:string,arg2:database.Table):Promise<response.BaseResponse_rollingthunder_pkg_database_Indices_>;
 
+export function GetLocalAPIStatus():Promise<response.BaseResponse_rollingthunder_internal_db_LocalAPIStatus_>;
+
 export function GetMaintenanceProgress(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_MaintenanceProgress_>;
 
+export function GetRowCount(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_RowCountJob_>;
+
 export function GetSavedConnections():Promise<response.BaseResponse___rollingthunder_internal_db_SavedConnection_>;
 
 export function GetSchemas(arg1:string):Promise<response.BaseResponse___string_>;
@@ -136,6 +168,8 @@ export function InsertRow(arg1:string,arg2:database.Table,arg3:Record<string, an
 
 export function InspectImportFile(arg1:database.ImportPreviewRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_ImportPreview_>;
 
+export function OpenQueryCursor(arg1:database.QueryRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_QueryCursorPage_>;
+
 export function OpenSQLFile():Promise<response.BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_>;
 
 export function PreviewDataSync(arg1:database.DataSyncRequest):Promise<response.BaseResponse_rollingthunder_pkg_database_DataSyncPreview_>;
@@ -154,6 +188,8 @@ export function RecordFrontendError(arg1:diagnostics.FrontendReport):Promise<res
 
 export function RollbackTransaction(arg1:string):Promise<response.BaseResponse_rollingthunder_internal_db_TransactionInfo_>;
 
+export function RunBackupSchedule(arg1:string):Promise<response.BaseResponse_rollingthunder_pkg_database_BackupCatalogEntry_>;
+export function SaveBackupSchedule(arg1:database.BackupSchedule):Promise<response.BaseResponse_rollingthunder_pkg_database_BackupSchedule_>;
 export function SaveConnection(arg1:database.Config):Promise<response.BaseResponse_rollingthunder_internal_db_SavedConnection_>;
 
 export function SaveSQLFile(arg1:db.SaveSQLFileRequest):Promise<response.BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_>;
@@ -164,6 +200,12 @@ export function Shutdown(arg1:context.Context):Promise<void>;
 
 export function Start(arg1:context.Context):Promise<void>;
 
+export function StartLocalAPI():Promise<response.BaseResponse_rollingthunder_internal_db_LocalAPIStatus_>;
+
+export function StartRowCount(arg1:string,arg2:database.Table):Promise<response.BaseResponse_rollingthunder_pkg_database_RowCountJob_>;
+
+export function StopLocalAPI():Promise<response.BaseResponse_bool_>;
+
 export function SwitchConnection(arg1:string):Promise<response.BaseResponse_bool_>;
 
 export function TruncateTable(arg1:string,arg2:database.Table):Promise<response.BaseResponse_bool_>;
@@ -173,3 +215,5 @@ export function UpdateConnection(arg1:string,arg2:database.Config):Promise<respo
 export function UpdateDiagnosticsSettings(arg1:diagnostics.Settings):Promise<response.BaseResponse_rollingthunder_internal_diagnostics_Settings_>;
 
 export function UpdateRow(arg1:string,arg2:database.Table,arg3:Record<string, any>,arg4:string):Promise<response.BaseResponse_bool_>;
+
+export function VerifyAuditLog():Promise<response.BaseResponse_rollingthunder_pkg_database_AuditVerification_>;
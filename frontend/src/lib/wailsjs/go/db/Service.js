@@ -54,6 +54,10 @@ export function CancelQuery(arg1) {
   return window['go']['db']['Service']['CancelQuery'](arg1);
 }
 
+export function CancelRowCount(arg1) {
+  return window['go']['db']['Service']['CancelRowCount'](arg1);
+}
+
 export function CheckConnection(arg1) {
   return window['go']['db']['Service']['CheckConnection'](arg1);
 }
@@ -62,6 +66,14 @@ export function CheckForUpdates() {
   return window['go']['db']['Service']['CheckForUpdates']();
 }
 
+export function ChooseCatalogRestoreFile(arg1,arg2) {
+  return window['go']['db']['Service']['ChooseCatalogRestoreFile'](arg1,arg2);
+}
+
+export function ChooseDuckDBDataFile() {
+  return window['go']['db']['Service']['ChooseDuckDBDataFile']();
+}
+
 export function ChooseImportFile() {
   return window['go']['db']['Service']['ChooseImportFile']();
 }
@@ -98,6 +110,10 @@ export function ClearDiagnostics() {
   return window['go']['db']['Service']['ClearDiagnostics']();
 }
 
+export function CloseQueryCursor(arg1) {
+  return window['go']['db']['Service']['CloseQueryCursor'](arg1);
+}
+
 export function CloseSQLFile(arg1) {
   return window['go']['db']['Service']['CloseSQLFile'](arg1);
 }
@@ -126,6 +142,10 @@ export function CreateTable(arg1, arg2, arg3) {
   return window['go']['db']['Service']['CreateTable'](arg1, arg2, arg3);
 }
 
+export function DeleteBackupSchedule(arg1) {
+  return window['go']['db']['Service']['DeleteBackupSchedule'](arg1);
+}
+
 export function DeleteConnection(arg1) {
   return window['go']['db']['Service']['DeleteConnection'](arg1);
 }
@@ -134,6 +154,10 @@ export function DeleteRow(arg1, arg2, arg3, arg4) {
   return window['go']['db']['Service']['DeleteRow'](arg1, arg2, arg3, arg4);
 }
 
+export function DescribeQuerySources(arg1) {
+  return window['go']['db']['Service']['DescribeQuerySources'](arg1);
+}
+
 export function DisconnectConnection(arg1) {
   return window['go']['db']['Service']['DisconnectConnection'](arg1);
 }
@@ -142,6 +166,14 @@ export function DropTable(arg1, arg2) {
   return window['go']['db']['Service']['DropTable'](arg1, arg2);
 }
 
+export function DryRunQuery(arg1) {
+  return window['go']['db']['Service']['DryRunQuery'](arg1);
+}
+
+export function EstimateCollectionData(arg1, arg2) {
+  return window['go']['db']['Service']['EstimateCollectionData'](arg1, arg2);
+}
+
 export function ExecuteQuery(arg1) {
   return window['go']['db']['Service']['ExecuteQuery'](arg1);
 }
@@ -150,6 +182,10 @@ export function ExplainQuery(arg1) {
   return window['go']['db']['Service']['ExplainQuery'](arg1);
 }
 
+export function ExportAuditLog(arg1) {
+  return window['go']['db']['Service']['ExportAuditLog'](arg1);
+}
+
 export function ExportDiagnostics() {
   return window['go']['db']['Service']['ExportDiagnostics']();
 }
@@ -162,14 +198,38 @@ export function ExportTableData(arg1, arg2) {
   return window['go']['db']['Service']['ExportTableData'](arg1, arg2);
 }
 
+export function ExportTablesArchive(arg1, arg2) {
+  return window['go']['db']['Service']['ExportTablesArchive'](arg1, arg2);
+}
+
+export function FetchQueryCursor(arg1, arg2) {
+  return window['go']['db']['Service']['FetchQueryCursor'](arg1, arg2);
+}
+
 export function GetActiveConnections() {
   return window['go']['db']['Service']['GetActiveConnections']();
 }
 
+export function GetAuditLog(arg1) {
+  return window['go']['db']['Service']['GetAuditLog'](arg1);
+}
+
+export function GetAvailableDrivers() {
+  return window['go']['db']['Service']['GetAvailableDrivers']();
+}
+
 export function GetBackupCapabilities(arg1) {
   return window['go']['db']['Service']['GetBackupCapabilities'](arg1);
 }
 
+export function GetBackupCatalog(arg1) {
+  return window['go']['db']['Service']['GetBackupCatalog'](arg1);
+}
+
+export function GetBackupSchedules() {
+  return window['go']['db']['Service']['GetBackupSchedules']();
+}
+
 export function GetCapabilities(arg1) {
   return window['go']['db']['Service']['GetCapabilities'](arg1);
 }
@@ -222,6 +282,10 @@ export function GetDiagnosticsSettings() {
   return window['go']['db']['Service']['GetDiagnosticsSettings']();
 }
 
+export function GetDriverPlugins() {
+  return window['go']['db']['Service']['GetDriverPlugins']();
+}
+
 export function GetExportProgress(arg1) {
   return window['go']['db']['Service']['GetExportProgress'](arg1);
 }
@@ -230,10 +294,18 @@ export function GetIndices(arg1, arg2) {
   return window['go']['db']['Service']['GetIndices'](arg1, arg2);
 }
 
+export function GetLocalAPIStatus() {
+  return window['go']['db']['Service']['GetLocalAPIStatus']();
+}
+
 export function GetMaintenanceProgress(arg1) {
   return window['go']['db']['Service']['GetMaintenanceProgress'](arg1);
 }
 
+export function GetRowCount(arg1) {
+  return window['go']['db']['Service']['GetRowCount'](arg1);
+}
+
 export function GetSavedConnections() {
   return window['go']['db']['Service']['GetSavedConnections']();
 }
@@ -262,6 +334,10 @@ export function InspectImportFile(arg1) {
   return window['go']['db']['Service']['InspectImportFile'](arg1);
 }
 
+export function OpenQueryCursor(arg1) {
+  return window['go']['db']['Service']['OpenQueryCursor'](arg1);
+}
+
 export function OpenSQLFile() {
   return window['go']['db']['Service']['OpenSQLFile']();
 }
@@ -298,6 +374,14 @@ export function RollbackTransaction(arg1) {
   return window['go']['db']['Service']['RollbackTransaction'](arg1);
 }
 
+export function RunBackupSchedule(arg1) {
+  return window['go']['db']['Service']['RunBackupSchedule'](arg1);
+}
+
+export function SaveBackupSchedule(arg1) {
+  return window['go']['db']['Service']['SaveBackupSchedule'](arg1);
+}
+
 export function SaveConnection(arg1) {
   return window['go']['db']['Service']['SaveConnection'](arg1);
 }
@@ -318,6 +402,18 @@ export function Start(arg1) {
   return window['go']['db']['Service']['Start'](arg1);
 }
 
+export function StartLocalAPI() {
+  return window['go']['db']['Service']['StartLocalAPI']();
+}
+
+export function StartRowCount(arg1, arg2) {
+  return window['go']['db']['Service']['StartRowCount'](arg1, arg2);
+}
+
+export function StopLocalAPI() {
+  return window['go']['db']['Service']['StopLocalAPI']();
+}
+
 export function SwitchConnection(arg1) {
   return window['go']['db']['Service']['SwitchConnection'](arg1);
 }
@@ -337,3 +433,7 @@ export function UpdateDiagnosticsSettings(arg1) {
 export function UpdateRow(arg1, arg2, arg3, arg4) {
   return window['go']['db']['Service']['UpdateRow'](arg1, arg2, arg3, arg4);
 }
+
+export function VerifyAuditLog() {
+  return window['go']['db']['Service']['VerifyAuditLog']();
+}
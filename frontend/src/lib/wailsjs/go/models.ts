@@ -22,8 +22,43 @@ export namespace database {
 	        this.unique = source["unique"];
 	    }
 	}
+	export class DriverProbe {
+	    driver: string;
+	    capabilities: Capabilities;
+	    backup: BackupCapabilities;
+	
+	    static createFrom(source: any = {}) {
+	        return new DriverProbe(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.driver = source["driver"];
+	        this.capabilities = this.convertValues(source["capabilities"], Capabilities);
+	        this.backup = this.convertValues(source["backup"], BackupCapabilities);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Sort {
 	    Column: string;
+	    Path?: string;
 	    Direction: string;
 	    Nulls: string;
 	
@@ -34,12 +69,14 @@ export namespace database {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.Column = source["Column"];
+	        this.Path = source["Path"];
 	        this.Direction = source["Direction"];
 	        this.Nulls = source["Nulls"];
 	    }
 	}
 	export class Filter {
 	    Column: string;
+	    Path?: string;
 	    Operator: string;
 	    Value: any;
 	
@@ -50,17 +87,54 @@ export namespace database {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.Column = source["Column"];
+	        this.Path = source["Path"];
 	        this.Operator = source["Operator"];
 	        this.Value = source["Value"];
 	    }
 	}
+	export class FilterGroup {
+	    Logic: string;
+	    Filters: Filter[];
+	    Groups: FilterGroup[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FilterGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Logic = source["Logic"];
+	        this.Filters = this.convertValues(source["Filters"], Filter);
+	        this.Groups = this.convertValues(source["Groups"], FilterGroup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Table {
 	    Schema: string;
 	    Name: string;
 	    Offset: number;
 	    Limit: number;
 	    Filters: Filter[];
+	    Where?: FilterGroup;
 	    Sorts: Sort[];
+	    After?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Table(source);
@@ -73,7 +147,9 @@ export namespace database {
 	        this.Offset = source["Offset"];
 	        this.Limit = source["Limit"];
 	        this.Filters = this.convertValues(source["Filters"], Filter);
+	        this.Where = this.convertValues(source["Where"], FilterGroup);
 	        this.Sorts = this.convertValues(source["Sorts"], Sort);
+	        this.After = source["After"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -684,6 +760,218 @@ export namespace database {
 		    return a;
 		}
 	}
+	export class AuditStatement {
+	    sql: string;
+	    rowsAffected?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditStatement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sql = source["sql"];
+	        this.rowsAffected = source["rowsAffected"];
+	    }
+	}
+	export class AuditEntry {
+	    sequence: number;
+	    id: string;
+	    operation: string;
+	    startedAt: any;
+	    recordedAt: any;
+	    durationMs: number;
+	    connectionId: string;
+	    profileId?: string;
+	    profileName: string;
+	    environment?: string;
+	    engine: string;
+	    database?: string;
+	    databaseUser?: string;
+	    osUser?: string;
+	    transactionId?: string;
+	    target?: string;
+	    summary: string;
+	    statements: AuditStatement[];
+	    rowsAffected?: number;
+	    previousHash: string;
+	    hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sequence = source["sequence"];
+	        this.id = source["id"];
+	        this.operation = source["operation"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.recordedAt = this.convertValues(source["recordedAt"], null);
+	        this.durationMs = source["durationMs"];
+	        this.connectionId = source["connectionId"];
+	        this.profileId = source["profileId"];
+	        this.profileName = source["profileName"];
+	        this.environment = source["environment"];
+	        this.engine = source["engine"];
+	        this.database = source["database"];
+	        this.databaseUser = source["databaseUser"];
+	        this.osUser = source["osUser"];
+	        this.transactionId = source["transactionId"];
+	        this.target = source["target"];
+	        this.summary = source["summary"];
+	        this.statements = this.convertValues(source["statements"], AuditStatement);
+	        this.rowsAffected = source["rowsAffected"];
+	        this.previousHash = source["previousHash"];
+	        this.hash = source["hash"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditLogFilter {
+	    profileId?: string;
+	    environment?: string;
+	    operations?: string[];
+	    since?: any;
+	    until?: any;
+	    search?: string;
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditLogFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profileId = source["profileId"];
+	        this.environment = source["environment"];
+	        this.operations = source["operations"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.until = this.convertValues(source["until"], null);
+	        this.search = source["search"];
+	        this.limit = source["limit"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditExportRequest {
+	    filter: AuditLogFilter;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filter = this.convertValues(source["filter"], AuditLogFilter);
+	        this.format = source["format"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditLogPage {
+	    entries: AuditEntry[];
+	    total: number;
+	    writeError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditLogPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], AuditEntry);
+	        this.total = source["total"];
+	        this.writeError = source["writeError"];
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditVerification {
+	    valid: boolean;
+	    entries: number;
+	    head?: string;
+	    brokenAt?: number;
+	    problem?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditVerification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.valid = source["valid"];
+	        this.entries = source["entries"];
+	        this.head = source["head"];
+	        this.brokenAt = source["brokenAt"];
+	        this.problem = source["problem"];
+	    }
+	}
 	export class BackupDirectory {
 	    name: string;
 	    path?: string;
@@ -712,6 +1000,7 @@ export namespace database {
 	    requiresDirectory: boolean;
 	    serverSideFiles: boolean;
 	    directories: BackupDirectory[];
+	    logicalAvailable: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new BackupCapabilities(source);
@@ -732,6 +1021,7 @@ export namespace database {
 	        this.requiresDirectory = source["requiresDirectory"];
 	        this.serverSideFiles = source["serverSideFiles"];
 	        this.directories = this.convertValues(source["directories"], BackupDirectory);
+	        this.logicalAvailable = source["logicalAvailable"];
 	    }
 
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -761,6 +1051,7 @@ export namespace database {
 	    serverPath?: string;
 	    schemaOnly: boolean;
 	    dataOnly: boolean;
+	    format?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new BackupRequest(source);
@@ -775,6 +1066,7 @@ export namespace database {
 	        this.serverPath = source["serverPath"];
 	        this.schemaOnly = source["schemaOnly"];
 	        this.dataOnly = source["dataOnly"];
+	        this.format = source["format"];
 	    }
 	}
 	export class BackupResult {
@@ -782,6 +1074,8 @@ export namespace database {
 	    bytes: number;
 	    format: string;
 	    cancelled: boolean;
+	    catalogId?: string;
+	    warning?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new BackupResult(source);
@@ -793,30 +1087,192 @@ export namespace database {
 	        this.bytes = source["bytes"];
 	        this.format = source["format"];
 	        this.cancelled = source["cancelled"];
+	        this.catalogId = source["catalogId"];
+	        this.warning = source["warning"];
 	    }
 	}
-	export class CSVOptions {
-	    delimiter: string;
-	    includeHeader: boolean;
-	    nullValue: string;
-	    encoding: string;
+	export class BackupCatalogEntry {
+	    id: string;
+	    profileId?: string;
+	    profileName: string;
+	    scheduleId?: string;
+	    trigger: string;
+	    engine: string;
+	    format: string;
+	    path?: string;
+	    bytes: number;
+	    sha256?: string;
+	    serverSide?: boolean;
+	    startedAt: any;
+	    durationMs: number;
+	    outcome: string;
+	    error?: string;
+	    prunedAt?: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new CSVOptions(source);
+	        return new BackupCatalogEntry(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.delimiter = source["delimiter"];
-	        this.includeHeader = source["includeHeader"];
-	        this.nullValue = source["nullValue"];
-	        this.encoding = source["encoding"];
+	        this.id = source["id"];
+	        this.profileId = source["profileId"];
+	        this.profileName = source["profileName"];
+	        this.scheduleId = source["scheduleId"];
+	        this.trigger = source["trigger"];
+	        this.engine = source["engine"];
+	        this.format = source["format"];
+	        this.path = source["path"];
+	        this.bytes = source["bytes"];
+	        this.sha256 = source["sha256"];
+	        this.serverSide = source["serverSide"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.durationMs = source["durationMs"];
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.prunedAt = this.convertValues(source["prunedAt"], null);
 	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class CancelSessionRequest {
-	    connectionId: string;
-	    sessionId: string;
-	    terminate: boolean;
+	export class BackupRetention {
+	    keepLast: number;
+	    keepDailyDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupRetention(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keepLast = source["keepLast"];
+	        this.keepDailyDays = source["keepDailyDays"];
+	    }
+	}
+	export class BackupSchedule {
+	    id: string;
+	    profileId: string;
+	    name: string;
+	    cron: string;
+	    enabled: boolean;
+	    destination: string;
+	    format?: string;
+	    schema?: string;
+	    directory?: string;
+	    schemaOnly: boolean;
+	    dataOnly: boolean;
+	    retention: BackupRetention;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupSchedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.profileId = source["profileId"];
+	        this.name = source["name"];
+	        this.cron = source["cron"];
+	        this.enabled = source["enabled"];
+	        this.destination = source["destination"];
+	        this.format = source["format"];
+	        this.schema = source["schema"];
+	        this.directory = source["directory"];
+	        this.schemaOnly = source["schemaOnly"];
+	        this.dataOnly = source["dataOnly"];
+	        this.retention = this.convertValues(source["retention"], BackupRetention);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupScheduleStatus {
+	    schedule: BackupSchedule;
+	    nextRunAt?: any;
+	    lastRun?: BackupCatalogEntry;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupScheduleStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schedule = this.convertValues(source["schedule"], BackupSchedule);
+	        this.nextRunAt = this.convertValues(source["nextRunAt"], null);
+	        this.lastRun = this.convertValues(source["lastRun"], BackupCatalogEntry);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CSVOptions {
+	    delimiter: string;
+	    includeHeader: boolean;
+	    nullValue: string;
+	    encoding: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CSVOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.delimiter = source["delimiter"];
+	        this.includeHeader = source["includeHeader"];
+	        this.nullValue = source["nullValue"];
+	        this.encoding = source["encoding"];
+	    }
+	}
+	export class CancelSessionRequest {
+	    connectionId: string;
+	    sessionId: string;
+	    terminate: boolean;
 	    confirmed: boolean;
 	
 	    static createFrom(source: any = {}) {
@@ -898,6 +1354,7 @@ export namespace database {
 	    transactions: boolean;
 	    transactionalDDL: boolean;
 	    atomicTableChanges: boolean;
+	    rowMutations: boolean;
 	    sqlInsertExport: boolean;
 	    fileDatabase: boolean;
 	    attachedDatabases: boolean;
@@ -906,6 +1363,14 @@ export namespace database {
 	    manageSecurity: boolean;
 	    activityMonitor: boolean;
 	    sshConnections: boolean;
+	    serverOutput: boolean;
+	    queryCursors: boolean;
+	    filterGroups: boolean;
+	    regexFilters: boolean;
+	    jsonPaths: boolean;
+	    keysetPagination: boolean;
+	    rowEstimates: boolean;
+	    returningRows: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Capabilities(source);
@@ -941,6 +1406,7 @@ export namespace database {
 	        this.transactions = source["transactions"];
 	        this.transactionalDDL = source["transactionalDDL"];
 	        this.atomicTableChanges = source["atomicTableChanges"];
+	        this.rowMutations = source["rowMutations"];
 	        this.sqlInsertExport = source["sqlInsertExport"];
 	        this.fileDatabase = source["fileDatabase"];
 	        this.attachedDatabases = source["attachedDatabases"];
@@ -949,6 +1415,14 @@ export namespace database {
 	        this.manageSecurity = source["manageSecurity"];
 	        this.activityMonitor = source["activityMonitor"];
 	        this.sshConnections = source["sshConnections"];
+	        this.serverOutput = source["serverOutput"];
+	        this.queryCursors = source["queryCursors"];
+	        this.filterGroups = source["filterGroups"];
+	        this.regexFilters = source["regexFilters"];
+	        this.jsonPaths = source["jsonPaths"];
+	        this.keysetPagination = source["keysetPagination"];
+	        this.rowEstimates = source["rowEstimates"];
+	        this.returningRows = source["returningRows"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1539,11 +2013,39 @@ export namespace database {
 	        this.pretty = source["pretty"];
 	    }
 	}
+	export class ParquetOptions {
+	    compression: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ParquetOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.compression = source["compression"];
+	    }
+	}
+	export class XLSXOptions {
+	    includeHeader: boolean;
+	    styleHeader: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new XLSXOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includeHeader = source["includeHeader"];
+	        this.styleHeader = source["styleHeader"];
+	    }
+	}
 	export class ExportOptions {
 	    format: string;
 	    csv: CSVOptions;
 	    json: JSONOptions;
 	    sql: SQLInsertOptions;
+	    parquet: ParquetOptions;
+	    xlsx: XLSXOptions;
 	
 	    static createFrom(source: any = {}) {
 	        return new ExportOptions(source);
@@ -1555,6 +2057,50 @@ export namespace database {
 	        this.csv = this.convertValues(source["csv"], CSVOptions);
 	        this.json = this.convertValues(source["json"], JSONOptions);
 	        this.sql = this.convertValues(source["sql"], SQLInsertOptions);
+	        this.parquet = this.convertValues(source["parquet"], ParquetOptions);
+	        this.xlsx = this.convertValues(source["xlsx"], XLSXOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ArchiveExportRequest {
+	    tables?: Table[];
+	    schema?: string;
+	    archive: string;
+	    includeDdl: boolean;
+	    jobId: string;
+	    suggestedName: string;
+	    options: ExportOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveExportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tables = this.convertValues(source["tables"], Table);
+	        this.schema = source["schema"];
+	        this.archive = source["archive"];
+	        this.includeDdl = source["includeDdl"];
+	        this.jobId = source["jobId"];
+	        this.suggestedName = source["suggestedName"];
+	        this.options = this.convertValues(source["options"], ExportOptions);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1583,6 +2129,9 @@ export namespace database {
 	    totalRows: number;
 	    elapsedMs: number;
 	    cancellable: boolean;
+	    table?: string;
+	    tableIndex?: number;
+	    tableCount?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ExportProgress(source);
@@ -1596,7 +2145,11 @@ export namespace database {
 	        this.bytes = source["bytes"];
 	        this.totalRows = source["totalRows"];
 	        this.elapsedMs = source["elapsedMs"];
+	        this.messages = this.convertValues(source["messages"], QueryMessage);
 	        this.cancellable = source["cancellable"];
+	        this.table = source["table"];
+	        this.tableIndex = source["tableIndex"];
+	        this.tableCount = source["tableCount"];
 	    }
 	}
 	export class ExportResult {
@@ -1605,6 +2158,7 @@ export namespace database {
 	    bytes: number;
 	    cancelled: boolean;
 	    format: string;
+	    tables?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ExportResult(source);
@@ -1617,6 +2171,7 @@ export namespace database {
 	        this.bytes = source["bytes"];
 	        this.cancelled = source["cancelled"];
 	        this.format = source["format"];
+	        this.tables = source["tables"];
 	    }
 	}
 	
@@ -1664,6 +2219,7 @@ export namespace database {
 	    delimiter?: string;
 	    header: boolean;
 	    emptyAsNull: boolean;
+	    sheet?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ImportOptions(source);
@@ -1675,6 +2231,7 @@ export namespace database {
 	        this.delimiter = source["delimiter"];
 	        this.header = source["header"];
 	        this.emptyAsNull = source["emptyAsNull"];
+	        this.sheet = source["sheet"];
 	    }
 	}
 	export class ImportPreview {
@@ -1682,6 +2239,7 @@ export namespace database {
 	    columns: ImportColumn[];
 	    rows: any[];
 	    sampled: number;
+	    sheets?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ImportPreview(source);
@@ -1693,6 +2251,7 @@ export namespace database {
 	        this.columns = this.convertValues(source["columns"], ImportColumn);
 	        this.rows = source["rows"];
 	        this.sampled = source["sampled"];
+	        this.sheets = source["sheets"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1795,6 +2354,7 @@ export namespace database {
 	    rowsInserted: number;
 	    tableCreated: boolean;
 	    warnings: string[];
+	    bulkLoaded?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ImportResult(source);
@@ -1807,6 +2367,7 @@ export namespace database {
 	        this.rowsInserted = source["rowsInserted"];
 	        this.tableCreated = source["tableCreated"];
 	        this.warnings = source["warnings"];
+	        this.bulkLoaded = source["bulkLoaded"];
 	    }
 	}
 	export class Index {
@@ -2148,6 +2709,7 @@ export namespace database {
 	    transactionId?: string;
 	    allowUnfilteredMutation: boolean;
 	    variables?: QueryVariable[];
+	    serverOutput?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new QueryRequest(source);
@@ -2161,6 +2723,73 @@ export namespace database {
 	        this.transactionId = source["transactionId"];
 	        this.allowUnfilteredMutation = source["allowUnfilteredMutation"];
 	        this.variables = this.convertValues(source["variables"], QueryVariable);
+	        this.serverOutput = source["serverOutput"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class QueryColumn {
+	    name: string;
+	    type?: string;
+	    nullable?: boolean;
+	    length?: number;
+	    precision?: number;
+	    scale?: number;
+	    sourceSchema?: string;
+	    sourceTable?: string;
+	    sourceColumn?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.nullable = source["nullable"];
+	        this.length = source["length"];
+	        this.precision = source["precision"];
+	        this.scale = source["scale"];
+	        this.sourceSchema = source["sourceSchema"];
+	        this.sourceTable = source["sourceTable"];
+	        this.sourceColumn = source["sourceColumn"];
+	    }
+	}
+	export class QueryCursorPage {
+	    cursorId: string;
+	    columns: QueryColumn[];
+	    rows: any[][];
+	    offset: number;
+	    done: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryCursorPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cursorId = source["cursorId"];
+	        this.columns = this.convertValues(source["columns"], QueryColumn);
+	        this.rows = source["rows"];
+	        this.offset = source["offset"];
+	        this.done = source["done"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2181,13 +2810,35 @@ export namespace database {
 		    return a;
 		}
 	}
+	export class QueryMessage {
+	    sequence: number;
+	    severity: string;
+	    code?: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sequence = source["sequence"];
+	        this.severity = source["severity"];
+	        this.code = source["code"];
+	        this.text = source["text"];
+	    }
+	}
 	export class QueryResultSet {
 	    index: number;
 	    statement: string;
-	    columns: string[];
-	    rows: any[];
+	    columns: QueryColumn[];
+	    rows: any[][];
 	    truncated: boolean;
 	    rowLimit: number;
+	    rowsAffected?: number;
+	    commandTag?: string;
+	    wallClockMs: number;
+	    messages?: QueryMessage[];
 	
 	    static createFrom(source: any = {}) {
 	        return new QueryResultSet(source);
@@ -2197,17 +2848,41 @@ export namespace database {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.index = source["index"];
 	        this.statement = source["statement"];
-	        this.columns = source["columns"];
+	        this.columns = this.convertValues(source["columns"], QueryColumn);
 	        this.rows = source["rows"];
 	        this.truncated = source["truncated"];
 	        this.rowLimit = source["rowLimit"];
+	        this.rowsAffected = source["rowsAffected"];
+	        this.commandTag = source["commandTag"];
+	        this.wallClockMs = source["wallClockMs"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class QueryResult {
-	    rows: any[];
+	    rows: any[][];
 	    truncated: boolean;
 	    rowLimit: number;
-	    columns: string[];
+	    columns: QueryColumn[];
+	    rowsAffected?: number;
+	    commandTag?: string;
+	    messages?: QueryMessage[];
 	    resultSets: QueryResultSet[];
 	    statementCount: number;
 	
@@ -2220,7 +2895,10 @@ export namespace database {
 	        this.rows = source["rows"];
 	        this.truncated = source["truncated"];
 	        this.rowLimit = source["rowLimit"];
-	        this.columns = source["columns"];
+	        this.columns = this.convertValues(source["columns"], QueryColumn);
+	        this.rowsAffected = source["rowsAffected"];
+	        this.commandTag = source["commandTag"];
+	        this.messages = this.convertValues(source["messages"], QueryMessage);
 	        this.resultSets = this.convertValues(source["resultSets"], QueryResultSet);
 	        this.statementCount = source["statementCount"];
 	    }
@@ -2243,30 +2921,150 @@ export namespace database {
 		    return a;
 		}
 	}
-	
-	
-	export class RestoreFileSelection {
-	    token: string;
-	    name: string;
-	    size: number;
-	    format: string;
+	export class DryRunRequest {
+	    connectionId: string;
+	    query: string;
+	    attemptId: string;
+	    variables?: QueryVariable[];
+	    sampleRows?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new RestoreFileSelection(source);
+	        return new DryRunRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.token = source["token"];
-	        this.name = source["name"];
-	        this.size = source["size"];
-	        this.format = source["format"];
+	        this.connectionId = source["connectionId"];
+	        this.query = source["query"];
+	        this.attemptId = source["attemptId"];
+	        this.variables = this.convertValues(source["variables"], QueryVariable);
+	        this.sampleRows = source["sampleRows"];
 	    }
-	}
-	export class RestorePreview {
-	    connectionId: string;
-	    database: string;
-	    engine: string;
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DryRunStatement {
+	    index: number;
+	    statement: string;
+	    rowsAffected?: number;
+	    commandTag?: string;
+	    elapsedMs: number;
+	    columns?: QueryColumn[];
+	    before?: any[][];
+	    after?: any[][];
+	    sampleTruncated?: boolean;
+	    messages?: QueryMessage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DryRunStatement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.statement = source["statement"];
+	        this.rowsAffected = source["rowsAffected"];
+	        this.commandTag = source["commandTag"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.columns = this.convertValues(source["columns"], QueryColumn);
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.sampleTruncated = source["sampleTruncated"];
+	        this.messages = this.convertValues(source["messages"], QueryMessage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DryRunResult {
+	    statements: DryRunStatement[];
+	    rolledBack: boolean;
+	    unfilteredMutations?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DryRunResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.statements = this.convertValues(source["statements"], DryRunStatement);
+	        this.rolledBack = source["rolledBack"];
+	        this.unfilteredMutations = source["unfilteredMutations"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class RestoreFileSelection {
+	    token: string;
+	    name: string;
+	    size: number;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RestoreFileSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token = source["token"];
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.format = source["format"];
+	    }
+	}
+	export class RestorePreview {
+	    connectionId: string;
+	    database: string;
+	    engine: string;
 	    file: string;
 	    size: number;
 	    format: string;
@@ -2314,6 +3112,44 @@ export namespace database {
 	        this.cancelled = source["cancelled"];
 	    }
 	}
+	export class RowCount {
+	    rows: number;
+	    estimated: boolean;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RowCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rows = source["rows"];
+	        this.estimated = source["estimated"];
+	        this.source = source["source"];
+	    }
+	}
+	export class RowCountJob {
+	    jobId: string;
+	    status: string;
+	    rows: number;
+	    error?: string;
+	    elapsedMs: number;
+	    cancellable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RowCountJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.status = source["status"];
+	        this.rows = source["rows"];
+	        this.error = source["error"];
+	        this.elapsedMs = source["elapsedMs"];
+	        this.cancellable = source["cancellable"];
+	    }
+	}
 	export class RowUpdate {
 	    original: Record<string, any>;
 	    values: Record<string, any>;
@@ -2332,7 +3168,9 @@ export namespace database {
 	}
 	export class RowsExportRequest {
 	    columns: string[];
+	    structures?: Structure[];
 	    rows: any[];
+	    cursorId?: string;
 	    jobId: string;
 	    expectedRows: number;
 	    suggestedName: string;
@@ -2345,7 +3183,9 @@ export namespace database {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.columns = source["columns"];
+	        this.structures = this.convertValues(source["structures"], Structure);
 	        this.rows = source["rows"];
+	        this.cursorId = source["cursorId"];
 	        this.jobId = source["jobId"];
 	        this.expectedRows = source["expectedRows"];
 	        this.suggestedName = source["suggestedName"];
@@ -2611,6 +3451,7 @@ export namespace database {
 	export class TableData {
 	    structures: Structure[];
 	    data: any[];
+	    next_page?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new TableData(source);
@@ -2620,6 +3461,7 @@ export namespace database {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.structures = this.convertValues(source["structures"], Structure);
 	        this.data = source["data"];
+	        this.next_page = source["next_page"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -2811,6 +3653,76 @@ export namespace db {
 	        this.confirmation = source["confirmation"];
 	    }
 	}
+	export class DriverPlugins {
+	    directory: string;
+	    plugins: plugin.Status[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DriverPlugins(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.directory = source["directory"];
+	        this.plugins = this.convertValues(source["plugins"], plugin.Status);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LocalAPIStatus {
+	    running: boolean;
+	    url?: string;
+	    token?: string;
+	    startedAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocalAPIStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.url = source["url"];
+	        this.token = source["token"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SQLWorkspaceFile {
 	    token: string;
 	    name: string;
@@ -3013,6 +3925,70 @@ export namespace diagnostics {
 
 }
 
+export namespace duckdb {
+	
+	export class DataFile {
+	    path: string;
+	    format: string;
+	    query: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.query = source["query"];
+	    }
+	}
+
+}
+
+export namespace plugin {
+	
+	export class Status {
+	    driver: string;
+	    file: string;
+	    loaded: boolean;
+	    error?: string;
+	    capabilities?: database.Capabilities;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.driver = source["driver"];
+	        this.file = source["file"];
+	        this.loaded = source["loaded"];
+	        this.error = source["error"];
+	        this.capabilities = this.convertValues(source["capabilities"], database.Capabilities);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace response {
 	
 	export class BaseErrorResponse {
@@ -3072,13 +4048,333 @@ export namespace response {
 	    data?: db.SavedConnection[];
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse___rollingthunder_internal_db_SavedConnection_(source);
+	        return new BaseResponse___rollingthunder_internal_db_SavedConnection_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], db.SavedConnection);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_BackupCatalogEntry_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.BackupCatalogEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_BackupCatalogEntry_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.BackupCatalogEntry);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_BackupScheduleStatus_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.BackupScheduleStatus[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_BackupScheduleStatus_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.BackupScheduleStatus);
+	    }
+
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_ConnectionHealth_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.ConnectionHealth[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_ConnectionHealth_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.ConnectionHealth);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_DataType_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.DataType[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_DataType_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.DataType);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_DatabaseObject_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.DatabaseObject[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_DatabaseObject_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.DatabaseObject);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_DriverProbe_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.DriverProbe[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_DriverProbe_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.DriverProbe);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___rollingthunder_pkg_database_QueryColumn_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.QueryColumn[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___rollingthunder_pkg_database_QueryColumn_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.QueryColumn);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse___string_ {
+	    errors?: BaseErrorResponse[];
+	    data?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse___string_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = source["data"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse_bool_ {
+	    errors?: BaseErrorResponse[];
+	    data?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_bool_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = source["data"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse_int_ {
+	    errors?: BaseErrorResponse[];
+	    data?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_int_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.SavedConnection);
+	        this.data = source["data"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3099,18 +4395,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse___rollingthunder_pkg_database_ConnectionHealth_ {
+	export class BaseResponse_rollingthunder_internal_db_ConnectResponse_ {
 	    errors?: BaseErrorResponse[];
-	    data?: database.ConnectionHealth[];
+	    data?: db.ConnectResponse;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse___rollingthunder_pkg_database_ConnectionHealth_(source);
+	        return new BaseResponse_rollingthunder_internal_db_ConnectResponse_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], database.ConnectionHealth);
+	        this.data = this.convertValues(source["data"], db.ConnectResponse);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3131,18 +4427,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse___rollingthunder_pkg_database_DataType_ {
+	export class BaseResponse_rollingthunder_internal_db_ConnectionWriteAccess_ {
 	    errors?: BaseErrorResponse[];
-	    data?: database.DataType[];
+	    data?: db.ConnectionWriteAccess;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse___rollingthunder_pkg_database_DataType_(source);
+	        return new BaseResponse_rollingthunder_internal_db_ConnectionWriteAccess_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], database.DataType);
+	        this.data = this.convertValues(source["data"], db.ConnectionWriteAccess);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3163,18 +4459,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse___rollingthunder_pkg_database_DatabaseObject_ {
+	export class BaseResponse_rollingthunder_internal_db_DriverPlugins_ {
 	    errors?: BaseErrorResponse[];
-	    data?: database.DatabaseObject[];
+	    data?: db.DriverPlugins;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse___rollingthunder_pkg_database_DatabaseObject_(source);
+	        return new BaseResponse_rollingthunder_internal_db_DriverPlugins_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], database.DatabaseObject);
+	        this.data = this.convertValues(source["data"], db.DriverPlugins);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3195,18 +4491,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse___string_ {
+	export class BaseResponse_rollingthunder_internal_db_LocalAPIStatus_ {
 	    errors?: BaseErrorResponse[];
-	    data?: string[];
+	    data?: db.LocalAPIStatus;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse___string_(source);
+	        return new BaseResponse_rollingthunder_internal_db_LocalAPIStatus_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = source["data"];
+	        this.data = this.convertValues(source["data"], db.LocalAPIStatus);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3227,18 +4523,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_bool_ {
+	export class BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_ {
 	    errors?: BaseErrorResponse[];
-	    data?: boolean;
+	    data?: db.SQLWorkspaceFile;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_bool_(source);
+	        return new BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = source["data"];
+	        this.data = this.convertValues(source["data"], db.SQLWorkspaceFile);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3259,18 +4555,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_int_ {
+	export class BaseResponse_rollingthunder_internal_db_SavedConnection_ {
 	    errors?: BaseErrorResponse[];
-	    data?: number;
+	    data?: db.SavedConnection;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_int_(source);
+	        return new BaseResponse_rollingthunder_internal_db_SavedConnection_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = source["data"];
+	        this.data = this.convertValues(source["data"], db.SavedConnection);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3291,18 +4587,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_db_ConnectResponse_ {
+	export class BaseResponse_rollingthunder_internal_db_TransactionInfo_ {
 	    errors?: BaseErrorResponse[];
-	    data?: db.ConnectResponse;
+	    data?: db.TransactionInfo;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_db_ConnectResponse_(source);
+	        return new BaseResponse_rollingthunder_internal_db_TransactionInfo_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.ConnectResponse);
+	        this.data = this.convertValues(source["data"], db.TransactionInfo);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3323,18 +4619,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_db_ConnectionWriteAccess_ {
+	export class BaseResponse_rollingthunder_internal_diagnostics_ExportResult_ {
 	    errors?: BaseErrorResponse[];
-	    data?: db.ConnectionWriteAccess;
+	    data?: diagnostics.ExportResult;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_db_ConnectionWriteAccess_(source);
+	        return new BaseResponse_rollingthunder_internal_diagnostics_ExportResult_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.ConnectionWriteAccess);
+	        this.data = this.convertValues(source["data"], diagnostics.ExportResult);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3355,18 +4651,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_ {
+	export class BaseResponse_rollingthunder_internal_diagnostics_Settings_ {
 	    errors?: BaseErrorResponse[];
-	    data?: db.SQLWorkspaceFile;
+	    data?: diagnostics.Settings;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_db_SQLWorkspaceFile_(source);
+	        return new BaseResponse_rollingthunder_internal_diagnostics_Settings_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.SQLWorkspaceFile);
+	        this.data = this.convertValues(source["data"], diagnostics.Settings);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3387,18 +4683,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_db_SavedConnection_ {
+	export class BaseResponse_rollingthunder_internal_updater_CheckResult_ {
 	    errors?: BaseErrorResponse[];
-	    data?: db.SavedConnection;
+	    data?: updater.CheckResult;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_db_SavedConnection_(source);
+	        return new BaseResponse_rollingthunder_internal_updater_CheckResult_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.SavedConnection);
+	        this.data = this.convertValues(source["data"], updater.CheckResult);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3419,20 +4715,20 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_db_TransactionInfo_ {
+	export class BaseResponse_rollingthunder_pkg_database_AuditLogPage_ {
 	    errors?: BaseErrorResponse[];
-	    data?: db.TransactionInfo;
+	    data?: database.AuditLogPage;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_db_TransactionInfo_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_AuditLogPage_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], db.TransactionInfo);
+	        this.data = this.convertValues(source["data"], database.AuditLogPage);
 	    }
-	
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -3451,20 +4747,20 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_diagnostics_ExportResult_ {
+	export class BaseResponse_rollingthunder_pkg_database_AuditVerification_ {
 	    errors?: BaseErrorResponse[];
-	    data?: diagnostics.ExportResult;
+	    data?: database.AuditVerification;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_diagnostics_ExportResult_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_AuditVerification_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], diagnostics.ExportResult);
+	        this.data = this.convertValues(source["data"], database.AuditVerification);
 	    }
-	
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -3483,18 +4779,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_diagnostics_Settings_ {
+	export class BaseResponse_rollingthunder_pkg_database_BackupCapabilities_ {
 	    errors?: BaseErrorResponse[];
-	    data?: diagnostics.Settings;
+	    data?: database.BackupCapabilities;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_diagnostics_Settings_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_BackupCapabilities_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], diagnostics.Settings);
+	        this.data = this.convertValues(source["data"], database.BackupCapabilities);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3515,20 +4811,20 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_internal_updater_CheckResult_ {
+	export class BaseResponse_rollingthunder_pkg_database_BackupCatalogEntry_ {
 	    errors?: BaseErrorResponse[];
-	    data?: updater.CheckResult;
+	    data?: database.BackupCatalogEntry;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_internal_updater_CheckResult_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_BackupCatalogEntry_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], updater.CheckResult);
+	        this.data = this.convertValues(source["data"], database.BackupCatalogEntry);
 	    }
-	
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -3547,18 +4843,18 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_pkg_database_BackupCapabilities_ {
+	export class BaseResponse_rollingthunder_pkg_database_BackupResult_ {
 	    errors?: BaseErrorResponse[];
-	    data?: database.BackupCapabilities;
+	    data?: database.BackupResult;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_pkg_database_BackupCapabilities_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_BackupResult_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], database.BackupCapabilities);
+	        this.data = this.convertValues(source["data"], database.BackupResult);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -3579,20 +4875,20 @@ export namespace response {
 		    return a;
 		}
 	}
-	export class BaseResponse_rollingthunder_pkg_database_BackupResult_ {
+	export class BaseResponse_rollingthunder_pkg_database_BackupSchedule_ {
 	    errors?: BaseErrorResponse[];
-	    data?: database.BackupResult;
+	    data?: database.BackupSchedule;
 	
 	    static createFrom(source: any = {}) {
-	        return new BaseResponse_rollingthunder_pkg_database_BackupResult_(source);
+	        return new BaseResponse_rollingthunder_pkg_database_BackupSchedule_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
-	        this.data = this.convertValues(source["data"], database.BackupResult);
+	        this.data = this.convertValues(source["data"], database.BackupSchedule);
 	    }
-	
+
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
 		    if (!a) {
 		        return a;
@@ -3835,6 +5131,38 @@ export namespace response {
 		    return a;
 		}
 	}
+	export class BaseResponse_rollingthunder_pkg_database_DryRunResult_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.DryRunResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_rollingthunder_pkg_database_DryRunResult_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.DryRunResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class BaseResponse_rollingthunder_pkg_database_ExportProgress_ {
 	    errors?: BaseErrorResponse[];
 	    data?: database.ExportProgress;
@@ -4251,6 +5579,38 @@ export namespace response {
 		    return a;
 		}
 	}
+	export class BaseResponse_rollingthunder_pkg_database_QueryCursorPage_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.QueryCursorPage;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_rollingthunder_pkg_database_QueryCursorPage_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.QueryCursorPage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class BaseResponse_rollingthunder_pkg_database_QueryResult_ {
 	    errors?: BaseErrorResponse[];
 	    data?: database.QueryResult;
@@ -4379,6 +5739,70 @@ export namespace response {
 		    return a;
 		}
 	}
+	export class BaseResponse_rollingthunder_pkg_database_RowCount_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.RowCount;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_rollingthunder_pkg_database_RowCount_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.RowCount);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BaseResponse_rollingthunder_pkg_database_RowCountJob_ {
+	    errors?: BaseErrorResponse[];
+	    data?: database.RowCountJob;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_rollingthunder_pkg_database_RowCountJob_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], database.RowCountJob);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class BaseResponse_rollingthunder_pkg_database_SchemaMigrationPreview_ {
 	    errors?: BaseErrorResponse[];
 	    data?: database.SchemaMigrationPreview;
@@ -4635,6 +6059,38 @@ export namespace response {
 		    return a;
 		}
 	}
+	export class BaseResponse_rollingthunder_pkg_database_duckdb_DataFile_ {
+	    errors?: BaseErrorResponse[];
+	    data?: duckdb.DataFile;
+	
+	    static createFrom(source: any = {}) {
+	        return new BaseResponse_rollingthunder_pkg_database_duckdb_DataFile_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.errors = this.convertValues(source["errors"], BaseErrorResponse);
+	        this.data = this.convertValues(source["data"], duckdb.DataFile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class BaseResponse_string_ {
 	    errors?: BaseErrorResponse[];
 	    data?: string;
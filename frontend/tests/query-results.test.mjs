@@ -1,7 +1,18 @@
 import assert from 'node:assert/strict';
 import test from 'node:test';
 
-import { getQueryResultPage, QUERY_RESULT_PAGE_SIZE } from '../src/lib/query/results.ts';
+import {
+	appendQueryCursorPage,
+	getDryRunChangeSummary,
+	getDryRunSampleRows,
+	getQueryMessageLogLevel,
+	getQueryResultColumnKeys,
+	getQueryResultColumns,
+	getQueryResultPage,
+	getQueryResultRecords,
+	getQueryResultSummary,
+	QUERY_RESULT_PAGE_SIZE
+} from '../src/lib/query/results.ts';
 
 test('renders query results in bounded client-side pages', () => {
 	const rows = Array.from({ length: 1000 }, (_, index) => ({ id: index + 1 }));
@@ -26,3 +37,73 @@ test('normalizes invalid page and page-size input', () => {
 	assert.deepEqual(getQueryResultPage(rows, 1.9, 2.8), [3, 4]);
 	assert.deepEqual(getQueryResultPage(rows, 0, 0), [1]);
 });
+
+test('keeps repeated query result column names as distinct keys', () => {
+	const columns = [
+		{ name: 'id', type: 'INT4', nullable: false },
+		{ name: 'id', type: 'INT4' },
+		{ name: 'total', type: 'NUMERIC', precision: 10, scale: 2 }
+	];
+	const keys = getQueryResultColumnKeys(columns.map((column) => column.name));
+	const rows = [[1, 7, '12.50']];
+
+	assert.deepEqual(keys, ['id', 'id_2', 'total']);
+	assert.deepEqual(getQueryResultRecords(keys, rows), [{ id: 1, id_2: 7, total: '12.50' }]);
+	assert.deepEqual(
+		getQueryResultColumns(columns, keys, rows).map((column) => [
+			column.name,
+			column.data_type,
+			column.nullable
+		]),
+		[
+			['id', 'int4', false],
+			['id_2', 'int4', true],
+			['total', 'numeric(10,2)', true]
+		]
+	);
+});
+
+test('summarizes affected rows and command tags for statements', () => {
+	assert.equal(
+		getQueryResultSummary({ rows: [], rowsAffected: 3, commandTag: 'UPDATE 3', elapsedMs: 12 }),
+		'UPDATE 3 · 3 rows affected · 12ms'
+	);
+	assert.equal(getQueryResultSummary({ rows: [[1]], elapsedMs: 0 }), '1 row · 0ms');
+});
+
+test('maps server message severities onto console levels', () => {
+	assert.equal(getQueryMessageLogLevel('error'), 'error');
+	assert.equal(getQueryMessageLogLevel('warning'), 'warn');
+	assert.equal(getQueryMessageLogLevel('notice'), 'info');
+	assert.equal(getQueryMessageLogLevel(undefined), 'info');
+});
+
+test('appends cursor pages until the cursor is exhausted', () => {
+	const first = { statement: 'select id from events', rows: [[1], [2]], truncated: true };
+
+	const middle = appendQueryCursorPage(first, { rows: [[3]], done: false });
+	const last = appendQueryCursorPage(middle, { rows: [[4]], done: true });
+
+	assert.deepEqual(middle.rows, [[1], [2], [3]]);
+	assert.equal(middle.truncated, true);
+	assert.deepEqual(last.rows, [[1], [2], [3], [4]]);
+	assert.equal(last.truncated, false);
+	assert.equal(last.statement, 'select id from events');
+	assert.deepEqual(first.rows, [[1], [2]]);
+});
+
+test('summarizes dry-run statements and pairs sampled row images', () => {
+	assert.equal(getDryRunChangeSummary({ rowsAffected: 1 }), '1 row would change');
+	assert.equal(getDryRunChangeSummary({ rowsAffected: 1200 }), '1,200 rows would change');
+	assert.equal(getDryRunChangeSummary({ rowsAffected: null }), 'No row count reported');
+
+	const updated = getDryRunSampleRows({
+		before: [[1, 'old']],
+		after: [[1, 'new']]
+	});
+	assert.deepEqual(updated, [{ before: [1, 'old'], after: [1, 'new'] }]);
+	const deleted = getDryRunSampleRows({ before: [[1], [2]] });
+	assert.equal(deleted.length, 2);
+	assert.equal(deleted[1].after, undefined);
+	assert.deepEqual(getDryRunSampleRows({}), []);
+});
@@ -1,7 +1,13 @@
 import assert from 'node:assert/strict';
 import test from 'node:test';
 
-import { buildDatabaseFilters, filterNeedsValue } from '../src/lib/table/filters.ts';
+import {
+	buildDatabaseFilters,
+	buildJSONSort,
+	buildTableFilters,
+	filterNeedsValue,
+	filterOperatorsFor
+} from '../src/lib/table/filters.ts';
 
 test('builds typed filters without assembling SQL in the frontend', () => {
 	const filters = buildDatabaseFilters([
@@ -63,3 +69,87 @@ test('null operators do not require a value', () => {
 	assert.equal(filterNeedsValue('is_null'), false);
 	assert.equal(filterNeedsValue('is_not_null'), false);
 });
+
+test('sends list and range operators as value arrays', () => {
+	assert.deepEqual(
+		buildDatabaseFilters([
+			{ id: 'in', column: 'status', operator: 'in', value: 'open, closed,', enabled: true },
+			{ id: 'range', column: 'total', operator: 'between', value: '10', valueTo: '', enabled: true },
+			{ id: 'range-full', column: 'total', operator: 'between', value: '10', valueTo: '20', enabled: true }
+		]),
+		[
+			{ Column: 'status', Operator: 'in', Value: ['open', 'closed'] },
+			{ Column: 'total', Operator: 'between', Value: ['10', '20'] }
+		]
+	);
+});
+
+test('keeps plain match-all filters flat and sends a tree only for groups', () => {
+	const condition = { id: 'a', column: 'id', operator: 'eq', value: '1', enabled: true };
+	assert.deepEqual(
+		buildTableFilters({ id: 'root', logic: 'and', conditions: [condition], groups: [] }),
+		{ Filters: [{ Column: 'id', Operator: 'eq', Value: '1' }], Where: null }
+	);
+
+	const tree = buildTableFilters({
+		id: 'root',
+		logic: 'or',
+		conditions: [condition],
+		groups: [
+			{ id: 'empty', logic: 'and', conditions: [], groups: [] },
+			{
+				id: 'names',
+				logic: 'and',
+				conditions: [
+					{ id: 'b', column: 'name', operator: 'starts_with', value: 'Ada', enabled: true },
+					{ id: 'c', column: 'name', operator: 'regex', value: '', enabled: true }
+				],
+				groups: []
+			}
+		]
+	});
+	assert.deepEqual(tree, {
+		Filters: [],
+		Where: {
+			Logic: 'or',
+			Filters: [{ Column: 'id', Operator: 'eq', Value: '1' }],
+			Groups: [
+				{
+					Logic: 'and',
+					Filters: [{ Column: 'name', Operator: 'starts_with', Value: 'Ada' }],
+					Groups: []
+				}
+			]
+		}
+	});
+});
+
+test('offers regex only to engines that evaluate it', () => {
+	const values = (capabilities) => filterOperatorsFor(capabilities).map((option) => option.value);
+	assert.equal(values({ regexFilters: true }).includes('regex'), true);
+	assert.equal(values({ regexFilters: false }).includes('regex'), false);
+	assert.equal(values(null).includes('regex'), false);
+});
+
+test('sends JSON paths with numeric values typed as numbers', () => {
+	const filters = buildDatabaseFilters([
+		{ id: 'a', column: 'doc', path: ' items[0].qty ', operator: 'gt', value: '2', enabled: true },
+		{ id: 'b', column: 'doc', path: 'sku', operator: 'in', value: 'A1, 42', enabled: true },
+		{ id: 'c', column: 'doc', path: 'sku', operator: 'contains', value: '42', enabled: true },
+		{ id: 'd', column: 'total', path: '', operator: 'eq', value: '42', enabled: true }
+	]);
+
+	assert.deepEqual(filters, [
+		{ Column: 'doc', Path: 'items[0].qty', Operator: 'gt', Value: 2 },
+		{ Column: 'doc', Path: 'sku', Operator: 'in', Value: ['A1', 42] },
+		{ Column: 'doc', Path: 'sku', Operator: 'contains', Value: '42' },
+		{ Column: 'total', Operator: 'eq', Value: '42' }
+	]);
+	assert.deepEqual(buildJSONSort({ column: 'doc', path: 'total', desc: true }), {
+		Column: 'doc',
+		Path: 'total',
+		Direction: 'desc',
+		Nulls: 'last'
+	});
+	assert.equal(buildJSONSort({ column: 'doc', path: ' ', desc: false }), null);
+});
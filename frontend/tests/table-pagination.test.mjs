@@ -0,0 +1,26 @@
+import assert from 'node:assert/strict';
+import test from 'node:test';
+
+import {
+	emptyPageTokens,
+	pageTokenFor,
+	rememberPageToken
+} from '../src/lib/table/pagination.ts';
+
+test('uses the token issued by the previous page of the same view', () => {
+	let tokens = rememberPageToken(emptyPageTokens(), 'events:sort=id', 0, 'after-page-0');
+	tokens = rememberPageToken(tokens, 'events:sort=id', 1, 'after-page-1');
+
+	assert.equal(pageTokenFor(tokens, 'events:sort=id', 1), 'after-page-0');
+	assert.equal(pageTokenFor(tokens, 'events:sort=id', 2), 'after-page-1');
+	assert.equal(pageTokenFor(tokens, 'events:sort=id', 0), '');
+	assert.equal(pageTokenFor(tokens, 'events:sort=id', 7), '');
+});
+
+test('drops tokens when the filters or sorts change', () => {
+	const tokens = rememberPageToken(emptyPageTokens(), 'events:sort=id', 0, 'after-page-0');
+	assert.equal(pageTokenFor(tokens, 'events:sort=name', 1), '');
+
+	const changed = rememberPageToken(tokens, 'events:sort=name', 0, undefined);
+	assert.deepEqual(changed, { view: 'events:sort=name', tokens: {} });
+});
@@ -0,0 +1,34 @@
+import assert from 'node:assert/strict';
+import test from 'node:test';
+
+import {
+	formatRowTotal,
+	isRowCountRunning,
+	rowTotalPageCount
+} from '../src/lib/table/rowCount.ts';
+
+test('marks estimated totals as approximate', () => {
+	assert.equal(formatRowTotal({ rows: 1200, estimated: true }), `≈${(1200).toLocaleString()}`);
+	assert.equal(formatRowTotal({ rows: 1200, estimated: false }), (1200).toLocaleString());
+});
+
+test('counts pages from an exact total', () => {
+	assert.equal(rowTotalPageCount({ rows: 250, estimated: false }, 100, 0, 100), 3);
+	assert.equal(rowTotalPageCount({ rows: 0, estimated: false }, 100, 0, 0), 1);
+});
+
+test('keeps paging past a low estimate while pages come back full', () => {
+	assert.equal(rowTotalPageCount({ rows: 150, estimated: true }, 100, 1, 100), 3);
+	assert.equal(rowTotalPageCount({ rows: 900, estimated: true }, 100, 0, 100), 9);
+});
+
+test('stops at the first short page even when the estimate is higher', () => {
+	assert.equal(rowTotalPageCount({ rows: 900, estimated: true }, 100, 2, 40), 3);
+	assert.equal(rowTotalPageCount({ rows: 900, estimated: true }, 100, 0, 0), 1);
+});
+
+test('recognizes running row count jobs', () => {
+	assert.equal(isRowCountRunning({ status: 'running' }), true);
+	assert.equal(isRowCountRunning({ status: 'done' }), false);
+	assert.equal(isRowCountRunning(null), false);
+});
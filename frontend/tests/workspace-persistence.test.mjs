@@ -43,7 +43,8 @@ test('persists and restores only query tabs', () => {
 				title: 'Active customers',
 				kind: 'query',
 				sql: 'SELECT * FROM customers',
-				savedQueryId: 'saved-1'
+				savedQueryId: 'saved-1',
+				serverOutput: true
 			}
 		],
 		'query'
@@ -55,6 +56,7 @@ test('persists and restores only query tabs', () => {
 	assert.equal(restored.length, 1);
 	assert.equal(restored[0].connectionId, 'runtime-b');
 	assert.equal(restored[0].savedQueryId, 'saved-1');
+	assert.equal(restored[0].serverOutput, true);
 });
 
 test('rejects unknown workspace storage versions and malformed tabs', () => {
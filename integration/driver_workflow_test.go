@@ -106,7 +106,7 @@ func exerciseDriverWorkflow(
 		MaxRows: 10,
 		Args:    []interface{}{1},
 	})
-	if err != nil || len(result.Rows) != 1 || result.Rows[0]["name"] != "storm" {
+	if err != nil || len(result.Rows) != 1 || result.RowMaps()[0]["name"] != "storm" {
 		t.Fatalf("query result = %+v, %v", result, err)
 	}
 
@@ -0,0 +1,623 @@
+// Package cli runs Rolling Thunder workflows from a terminal or CI job. Every
+// command goes through internal/db.Service, so saved profiles, credential
+// hydration, write-access rules, and reviewed fingerprints behave exactly as
+// they do in the desktop application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"rollingthunder/internal/db"
+	"rollingthunder/internal/mcp"
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitUsage   = 2
+	maxSQLBytes = 8 << 20
+)
+
+// HeadlessFactory builds the service used by one command invocation.
+type HeadlessFactory func() *db.Headless
+
+type command struct {
+	name    string
+	summary string
+	run     func(*runner, []string) int
+}
+
+type runner struct {
+	ctx      context.Context
+	headless *db.Headless
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+var commands = []command{
+	{"profiles", "List saved connection profiles", (*runner).profiles},
+	{"query", "Run SQL against a saved profile", (*runner).query},
+	{"export", "Stream table rows to a CSV, JSON, NDJSON, SQL, Parquet, or XLSX file", (*runner).export},
+	{"backup", "Create a database backup file", (*runner).backup},
+	{"restore", "Preview or apply a reviewed database restore", (*runner).restore},
+	{"schema", "Compare or migrate schemas (schema diff)", (*runner).schema},
+	{"data", "Compare or synchronize table rows (data diff)", (*runner).data},
+	{"audit", "List, verify, or export the write audit log", (*runner).audit},
+	{"mcp", "Serve MCP tools for AI assistants over stdio", (*runner).mcp},
+}
+
+// IsCommand reports whether args select a headless command instead of the
+// desktop window. Platform launch flags such as -psn_* are never commands.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "help", "-h", "--help":
+		return true
+	}
+	for _, command := range commands {
+		if args[0] == command.name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes one command and returns the process exit code. Results are
+// written to stdout as a response.BaseResponse JSON document so scripts can
+// branch on the same error codes returned to the desktop frontend.
+func Run(
+	ctx context.Context,
+	args []string,
+	stdin io.Reader,
+	stdout io.Writer,
+	stderr io.Writer,
+	newHeadless HeadlessFactory,
+) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		writeUsage(stderr)
+		return exitOK
+	}
+	for _, command := range commands {
+		if args[0] != command.name {
+			continue
+		}
+		headless := newHeadless()
+		headless.Start(ctx)
+		defer headless.Shutdown(context.Background())
+		return command.run(&runner{
+			ctx:      ctx,
+			headless: headless,
+			stdin:    stdin,
+			stdout:   stdout,
+			stderr:   stderr,
+		}, args[1:])
+	}
+	writeUsage(stderr)
+	return emit(stdout, db.HeadlessRequestError[bool](
+		"Unknown command",
+		fmt.Sprintf("%q is not a %s command.", args[0], application.Identifier),
+		"Run "+application.Identifier+" help to list the available commands.",
+	))
+}
+
+func writeUsage(output io.Writer) {
+	fmt.Fprintf(output, "Usage: %s <command> [flags]\n\nCommands:\n", application.Identifier)
+	for _, command := range commands {
+		fmt.Fprintf(output, "  %-9s %s\n", command.name, command.summary)
+	}
+	fmt.Fprintf(
+		output,
+		"\nRun %s <command> -h for command flags. Results are JSON on stdout.\n",
+		application.Identifier,
+	)
+}
+
+func emit[T any](output io.Writer, result response.BaseResponse[T]) int {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return exitFailed
+	}
+	if len(result.Errors) > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+func usageError(output io.Writer, detail string) int {
+	emit(output, db.HeadlessRequestError[bool](
+		"Invalid command arguments",
+		detail,
+		"Run the command with -h to list its flags.",
+	))
+	return exitUsage
+}
+
+func (r *runner) flags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(application.Identifier+" "+name, flag.ContinueOnError)
+	flags.SetOutput(r.stderr)
+	return flags
+}
+
+// parse returns a non-negative exit code when the command must stop.
+func (r *runner) parse(flags *flag.FlagSet, args []string) int {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return usageError(r.stdout, err.Error())
+	}
+	if flags.NArg() > 0 {
+		return usageError(
+			r.stdout,
+			fmt.Sprintf("unexpected argument %q", flags.Arg(0)),
+		)
+	}
+	return -1
+}
+
+// connect returns a connection ID, or a non-negative exit code after writing
+// the connection failure.
+func (r *runner) connect(profile string) (string, int) {
+	connected := r.headless.ConnectProfile(profile)
+	if len(connected.Errors) > 0 || !connected.Data.Connected {
+		return "", emit(r.stdout, connected)
+	}
+	return connected.Data.ConnectionID, -1
+}
+
+type variableFlags []database.QueryVariable
+
+func (variables *variableFlags) String() string {
+	names := make([]string, 0, len(*variables))
+	for _, variable := range *variables {
+		names = append(names, variable.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (variables *variableFlags) Set(value string) error {
+	name, raw, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("query variables use name=value")
+	}
+	*variables = append(*variables, database.QueryVariable{
+		Name:  strings.TrimSpace(name),
+		Value: raw,
+	})
+	return nil
+}
+
+type listFlag []string
+
+func (values *listFlag) String() string {
+	return strings.Join(*values, ",")
+}
+
+func (values *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*values = append(*values, item)
+		}
+	}
+	return nil
+}
+
+func (r *runner) profiles(args []string) int {
+	flags := r.flags("profiles")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	return emit(r.stdout, r.headless.SavedConnections())
+}
+
+func readSQLFile(stdin io.Reader, path string) (string, error) {
+	if path == "-" {
+		contents, err := io.ReadAll(io.LimitReader(stdin, maxSQLBytes+1))
+		if err != nil {
+			return "", err
+		}
+		if len(contents) > maxSQLBytes {
+			return "", fmt.Errorf("SQL input exceeds %d bytes", maxSQLBytes)
+		}
+		return string(contents), nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxSQLBytes {
+		return "", fmt.Errorf("SQL file exceeds %d bytes", maxSQLBytes)
+	}
+	contents, err := os.ReadFile(path)
+	return string(contents), err
+}
+
+func (r *runner) query(args []string) int {
+	flags := r.flags("query")
+	profile := flags.String("profile", "", "saved profile ID or name")
+	sql := flags.String("sql", "", "SQL text to execute")
+	file := flags.String("file", "", "read SQL from a file, or - for stdin")
+	allowUnfiltered := flags.Bool(
+		"allow-unfiltered-mutation",
+		false,
+		"confirm UPDATE or DELETE statements without a WHERE clause",
+	)
+	serverOutput := flags.Bool(
+		"server-output",
+		false,
+		"collect session output such as Oracle DBMS_OUTPUT into each statement's messages",
+	)
+	dryRun := flags.Bool(
+		"dry-run",
+		false,
+		"run the batch in a transaction that is rolled back and report affected rows",
+	)
+	sample := flags.Int("sample", 0, "with --dry-run, changed rows to return per statement")
+	var variables variableFlags
+	flags.Var(&variables, "var", "bind a {{name}} query variable as name=value (repeatable)")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	if (*sql == "") == (*file == "") {
+		return usageError(r.stdout, "pass exactly one of --sql or --file")
+	}
+	if *sample != 0 && !*dryRun {
+		return usageError(r.stdout, "--sample requires --dry-run")
+	}
+	query := *sql
+	if *file != "" {
+		contents, err := readSQLFile(r.stdin, *file)
+		if err != nil {
+			return usageError(r.stdout, err.Error())
+		}
+		query = contents
+	}
+	connectionID, code := r.connect(*profile)
+	if code >= 0 {
+		return code
+	}
+	if *dryRun {
+		return emit(r.stdout, r.headless.Service().DryRunQuery(database.DryRunRequest{
+			ConnectionID: connectionID,
+			Query:        query,
+			Variables:    variables,
+			SampleRows:   *sample,
+		}))
+	}
+	return emit(r.stdout, r.headless.Service().ExecuteQuery(database.QueryRequest{
+		ConnectionID:            connectionID,
+		Query:                   query,
+		AllowUnfilteredMutation: *allowUnfiltered,
+		Variables:               variables,
+		ServerOutput:            *serverOutput,
+	}))
+}
+
+func (r *runner) export(args []string) int {
+	flags := r.flags("export")
+	profile := flags.String("profile", "", "saved profile ID or name")
+	schema := flags.String("schema", "", "table schema or database")
+	table := flags.String("table", "", "table name")
+	output := flags.String("output", "", "destination file")
+	format := flags.String("format", string(database.ExportFormatCSV), "csv, json, ndjson, sql, parquet, or xlsx")
+	scope := flags.String("scope", string(database.ExportScopeAll), "all or page")
+	limit := flags.Int("limit", 0, "page size for --scope page")
+	offset := flags.Int("offset", 0, "page offset for --scope page")
+	delimiter := flags.String("delimiter", "", "CSV delimiter")
+	header := flags.Bool("header", true, "write a CSV or Excel header row")
+	nullValue := flags.String("null", "", "CSV text written for NULL values")
+	encoding := flags.String("encoding", "", "CSV encoding: utf-8, utf-8-bom, or utf-16le")
+	pretty := flags.Bool("pretty", false, "indent JSON array output")
+	batchSize := flags.Int("batch-size", 0, "rows per SQL INSERT statement")
+	transaction := flags.Bool("transaction", false, "wrap SQL INSERT output in a transaction")
+	compression := flags.String("compression", "", "Parquet compression: snappy, zstd, or none")
+	styleHeader := flags.Bool("style-header", false, "bold and freeze the Excel header row")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	if strings.TrimSpace(*table) == "" {
+		return usageError(r.stdout, "--table is required")
+	}
+	exportScope := database.ExportScope(*scope)
+	if exportScope != database.ExportScopeAll && exportScope != database.ExportScopePage {
+		return usageError(r.stdout, "--scope must be all or page")
+	}
+	connectionID, code := r.connect(*profile)
+	if code >= 0 {
+		return code
+	}
+	return emit(r.stdout, r.headless.ExportTableData(
+		connectionID,
+		database.TableExportRequest{
+			Table: database.Table{
+				Schema: *schema,
+				Name:   *table,
+				Limit:  *limit,
+				Offset: *offset,
+			},
+			Scope: exportScope,
+			Options: database.ExportOptions{
+				Format: database.ExportFormat(*format),
+				CSV: database.CSVOptions{
+					Delimiter:     *delimiter,
+					IncludeHeader: *header,
+					NullValue:     *nullValue,
+					Encoding:      database.CSVEncoding(*encoding),
+				},
+				JSON: database.JSONOptions{Pretty: *pretty},
+				SQL: database.SQLInsertOptions{
+					BatchSize:          *batchSize,
+					IncludeTransaction: *transaction,
+				},
+				Parquet: database.ParquetOptions{
+					Compression: database.ParquetCompression(*compression),
+				},
+				XLSX: database.XLSXOptions{
+					IncludeHeader: *header,
+					StyleHeader:   *styleHeader,
+				},
+			},
+		},
+		*output,
+	))
+}
+
+func (r *runner) backup(args []string) int {
+	flags := r.flags("backup")
+	profile := flags.String("profile", "", "saved profile ID or name")
+	output := flags.String("output", "", "destination backup file")
+	schema := flags.String("schema", "", "limit the backup to one schema")
+	directory := flags.String("directory", "", "Oracle Data Pump directory object")
+	serverPath := flags.String("server-path", "", "SQL Server .bak path on the database server")
+	schemaOnly := flags.Bool("schema-only", false, "back up definitions without rows")
+	dataOnly := flags.Bool("data-only", false, "back up rows without definitions")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	connectionID, code := r.connect(*profile)
+	if code >= 0 {
+		return code
+	}
+	return emit(r.stdout, r.headless.BackupDatabase(database.BackupRequest{
+		ConnectionID: connectionID,
+		Schema:       *schema,
+		Directory:    *directory,
+		ServerPath:   *serverPath,
+		SchemaOnly:   *schemaOnly,
+		DataOnly:     *dataOnly,
+	}, *output))
+}
+
+// restore previews by default. Passing the fingerprint printed by a preview
+// applies exactly that reviewed restore and fails if anything changed since.
+func (r *runner) restore(args []string) int {
+	flags := r.flags("restore")
+	profile := flags.String("profile", "", "saved profile ID or name")
+	input := flags.String("input", "", "local backup file")
+	schema := flags.String("schema", "", "restore into one schema")
+	directory := flags.String("directory", "", "Oracle Data Pump directory object")
+	serverPath := flags.String("server-path", "", "SQL Server .bak path on the database server")
+	fingerprint := flags.String("fingerprint", "", "apply the reviewed preview with this fingerprint")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	if (*input == "") == (*serverPath == "") {
+		return usageError(r.stdout, "pass exactly one of --input or --server-path")
+	}
+	connectionID, code := r.connect(*profile)
+	if code >= 0 {
+		return code
+	}
+	request := database.RestorePreviewRequest{
+		ConnectionID: connectionID,
+		Schema:       *schema,
+		Directory:    *directory,
+		ServerPath:   *serverPath,
+	}
+	if *input != "" {
+		selection := r.headless.ChooseRestoreFile(connectionID, *input)
+		if len(selection.Errors) > 0 {
+			return emit(r.stdout, selection)
+		}
+		request.Token = selection.Data.Token
+	}
+	service := r.headless.Service()
+	if strings.TrimSpace(*fingerprint) == "" {
+		return emit(r.stdout, service.PreviewDatabaseRestore(request))
+	}
+	return emit(r.stdout, service.ApplyDatabaseRestore(database.ApplyRestoreRequest{
+		Restore:     request,
+		Fingerprint: *fingerprint,
+	}))
+}
+
+func (r *runner) schema(args []string) int {
+	if len(args) == 0 || args[0] != "diff" {
+		return usageError(r.stdout, "usage: schema diff --source PROFILE --target PROFILE")
+	}
+	flags := r.flags("schema diff")
+	source := flags.String("source", "", "source profile ID or name")
+	target := flags.String("target", "", "target profile ID or name")
+	sourceSchema := flags.String("source-schema", "", "source schema")
+	targetSchema := flags.String("target-schema", "", "target schema")
+	destructive := flags.Bool("include-destructive", false, "include drops in the plan")
+	fingerprint := flags.String("fingerprint", "", "apply the reviewed plan with this fingerprint")
+	if code := r.parse(flags, args[1:]); code >= 0 {
+		return code
+	}
+	sourceID, code := r.connect(*source)
+	if code >= 0 {
+		return code
+	}
+	targetID, code := r.connect(*target)
+	if code >= 0 {
+		return code
+	}
+	request := database.SchemaMigrationRequest{
+		SourceConnectionID: sourceID,
+		SourceSchema:       *sourceSchema,
+		TargetConnectionID: targetID,
+		TargetSchema:       *targetSchema,
+		IncludeDestructive: *destructive,
+	}
+	service := r.headless.Service()
+	if strings.TrimSpace(*fingerprint) == "" {
+		return emit(r.stdout, service.PreviewSchemaMigration(request))
+	}
+	return emit(r.stdout, service.ApplySchemaMigration(database.ApplySchemaMigrationRequest{
+		Migration:   request,
+		Fingerprint: *fingerprint,
+	}))
+}
+
+func (r *runner) data(args []string) int {
+	if len(args) == 0 || args[0] != "diff" {
+		return usageError(r.stdout, "usage: data diff --source PROFILE --target PROFILE --table NAME")
+	}
+	flags := r.flags("data diff")
+	source := flags.String("source", "", "source profile ID or name")
+	target := flags.String("target", "", "target profile ID or name")
+	sourceSchema := flags.String("source-schema", "", "source schema")
+	targetSchema := flags.String("target-schema", "", "target schema")
+	table := flags.String("table", "", "table name on both sides")
+	sourceTable := flags.String("source-table", "", "source table name")
+	targetTable := flags.String("target-table", "", "target table name")
+	maxRows := flags.Int("max-rows", 0, "rows compared per side, up to "+strconv.Itoa(database.MaxDataSyncRowLimit))
+	fingerprint := flags.String("fingerprint", "", "apply the reviewed changes with this fingerprint")
+	var keyColumns, compareColumns, selected listFlag
+	flags.Var(&keyColumns, "key", "comma-separated key columns")
+	flags.Var(&compareColumns, "compare", "comma-separated compared columns")
+	flags.Var(&selected, "change", "apply only these change IDs (repeatable)")
+	if code := r.parse(flags, args[1:]); code >= 0 {
+		return code
+	}
+	if *sourceTable == "" {
+		*sourceTable = *table
+	}
+	if *targetTable == "" {
+		*targetTable = *table
+	}
+	sourceID, code := r.connect(*source)
+	if code >= 0 {
+		return code
+	}
+	targetID, code := r.connect(*target)
+	if code >= 0 {
+		return code
+	}
+	request := database.DataSyncRequest{
+		SourceConnectionID: sourceID,
+		SourceSchema:       *sourceSchema,
+		SourceTable:        *sourceTable,
+		TargetConnectionID: targetID,
+		TargetSchema:       *targetSchema,
+		TargetTable:        *targetTable,
+		KeyColumns:         keyColumns,
+		CompareColumns:     compareColumns,
+		MaxRows:            *maxRows,
+	}
+	service := r.headless.Service()
+	if strings.TrimSpace(*fingerprint) == "" {
+		return emit(r.stdout, service.PreviewDataSync(request))
+	}
+	return emit(r.stdout, service.ApplyDataSync(database.ApplyDataSyncRequest{
+		Sync:              request,
+		Fingerprint:       *fingerprint,
+		SelectedChangeIDs: selected,
+	}))
+}
+
+// audit lists the newest matching entries by default. --output exports every
+// match instead, and --verify checks the whole hash chain.
+func (r *runner) audit(args []string) int {
+	flags := r.flags("audit")
+	profile := flags.String("profile", "", "only entries for this saved profile ID or name")
+	environment := flags.String("environment", "", "only entries for this environment")
+	since := flags.String("since", "", "only entries recorded at or after this RFC 3339 time")
+	until := flags.String("until", "", "only entries recorded at or before this RFC 3339 time")
+	search := flags.String("search", "", "text to find in summaries, targets, and statements")
+	limit := flags.Int("limit", 0, "entries to list, newest first")
+	output := flags.String("output", "", "export matching entries to this file")
+	format := flags.String("format", string(database.ExportFormatCSV), "export format: csv or json")
+	verify := flags.Bool("verify", false, "check the hash chain instead of listing entries")
+	var operations listFlag
+	flags.Var(&operations, "operation", "comma-separated operations, such as query,restore")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	if *verify {
+		return emit(r.stdout, r.headless.Service().VerifyAuditLog())
+	}
+	filter := database.AuditLogFilter{
+		Environment: *environment,
+		Search:      *search,
+		Limit:       *limit,
+	}
+	for _, operation := range operations {
+		filter.Operations = append(filter.Operations, database.AuditOperation(operation))
+	}
+	for _, bound := range []struct {
+		flag  string
+		value string
+		into  **time.Time
+	}{{"--since", *since, &filter.Since}, {"--until", *until, &filter.Until}} {
+		if bound.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return usageError(r.stdout, bound.flag+" must be an RFC 3339 time")
+		}
+		*bound.into = &parsed
+	}
+	if *profile != "" {
+		resolved := r.headless.ProfileID(*profile)
+		if len(resolved.Errors) > 0 {
+			return emit(r.stdout, resolved)
+		}
+		filter.ProfileID = resolved.Data
+	}
+	if *output != "" {
+		return emit(r.stdout, r.headless.ExportAuditLog(database.AuditExportRequest{
+			Filter: filter,
+			Format: database.ExportFormat(*format),
+		}, *output))
+	}
+	return emit(r.stdout, r.headless.Service().GetAuditLog(filter))
+}
+
+// mcp serves tools until stdin closes. Only profiles named with --profile are
+// reachable, and stdout carries protocol messages only.
+func (r *runner) mcp(args []string) int {
+	flags := r.flags("mcp")
+	var profiles listFlag
+	flags.Var(&profiles, "profile", "saved profile ID or name to expose (repeatable)")
+	if code := r.parse(flags, args); code >= 0 {
+		return code
+	}
+	if len(profiles) == 0 {
+		return usageError(r.stdout, "pass at least one --profile to expose")
+	}
+	err := mcp.NewServer(r.headless, profiles).Serve(r.ctx, r.stdin, r.stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(r.stderr, "mcp: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
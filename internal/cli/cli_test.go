@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"rollingthunder/internal/db"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+type memoryCredentialStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (store *memoryCredentialStore) Set(profileID string, password string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.values[profileID] = password
+	return nil
+}
+
+func (store *memoryCredentialStore) Get(profileID string) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	password, ok := store.values[profileID]
+	if !ok {
+		return "", db.ErrCredentialNotFound
+	}
+	return password, nil
+}
+
+func (store *memoryCredentialStore) Delete(profileID string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.values, profileID)
+	return nil
+}
+
+type cliFixture struct {
+	storage     *db.ConnectionStorage
+	credentials *memoryCredentialStore
+	dir         string
+}
+
+func newCLIFixture(t *testing.T) *cliFixture {
+	t.Helper()
+	dir := t.TempDir()
+	return &cliFixture{
+		storage: &db.ConnectionStorage{
+			FilePath: filepath.Join(dir, "connections.json"),
+		},
+		credentials: &memoryCredentialStore{values: make(map[string]string)},
+		dir:         dir,
+	}
+}
+
+func (fixture *cliFixture) headless() *db.Headless {
+	return db.NewHeadless(db.HeadlessOptions{
+		ConnectionStorage: fixture.storage,
+		AuditLog:          &db.AuditLogStorage{FilePath: filepath.Join(fixture.dir, "audit.jsonl")},
+		CredentialStore:   fixture.credentials,
+		Version:           "test",
+	})
+}
+
+func (fixture *cliFixture) saveSQLiteProfile(t *testing.T, name string) string {
+	t.Helper()
+	saved := fixture.headless().Service().SaveConnection(database.Config{
+		Name:   name,
+		Driver: database.DriverSQLite,
+		Db:     filepath.Join(fixture.dir, name+".sqlite3"),
+	})
+	if len(saved.Errors) > 0 {
+		t.Fatalf("SaveConnection() = %+v", saved)
+	}
+	return saved.Data.ID
+}
+
+func (fixture *cliFixture) run(t *testing.T, args ...string) (int, []byte) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, strings.NewReader(""), &stdout, &stderr, fixture.headless)
+	return code, stdout.Bytes()
+}
+
+func decode[T any](t *testing.T, output []byte) response.BaseResponse[T] {
+	t.Helper()
+	var result response.BaseResponse[T]
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("decode %s: %v", output, err)
+	}
+	return result
+}
+
+func TestIsCommandIgnoresDesktopLaunchArguments(t *testing.T) {
+	if IsCommand(nil) || IsCommand([]string{"-psn_0_12345"}) {
+		t.Fatal("desktop launch arguments must start the window")
+	}
+	for _, args := range [][]string{{"query"}, {"schema", "diff"}, {"help"}} {
+		if !IsCommand(args) {
+			t.Fatalf("IsCommand(%v) = false", args)
+		}
+	}
+}
+
+func TestQueryAndExportUseSavedProfile(t *testing.T) {
+	fixture := newCLIFixture(t)
+	fixture.saveSQLiteProfile(t, "inventory")
+
+	code, output := fixture.run(
+		t,
+		"query",
+		"--profile", "Inventory",
+		"--sql", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT); "+
+			"INSERT INTO items VALUES (1, 'bolt'), (2, 'nut')",
+	)
+	if code != exitOK {
+		t.Fatalf("query exit = %d, output = %s", code, output)
+	}
+
+	code, output = fixture.run(
+		t,
+		"query",
+		"--profile", "inventory",
+		"--sql", "SELECT name FROM items WHERE id = {{id}}",
+		"--var", "id=2",
+	)
+	selected := decode[database.QueryResult](t, output)
+	if code != exitOK {
+		t.Fatalf("select exit = %d, output = %s", code, output)
+	}
+	rows := selected.Data.RowMaps()
+	if len(rows) != 1 || rows[0]["name"] != "nut" {
+		t.Fatalf("rows = %+v", rows)
+	}
+
+	code, output = fixture.run(
+		t,
+		"query",
+		"--profile", "inventory",
+		"--sql", "DELETE FROM items",
+		"--dry-run",
+		"--sample", "1",
+	)
+	dryRun := decode[database.DryRunResult](t, output)
+	if code != exitOK || len(dryRun.Data.Statements) != 1 {
+		t.Fatalf("dry run exit = %d, output = %s", code, output)
+	}
+	if deleted := dryRun.Data.Statements[0]; deleted.RowsAffected == nil ||
+		*deleted.RowsAffected != 2 || len(deleted.Before) != 1 {
+		t.Fatalf("dry run statement = %+v", deleted)
+	}
+
+	destination := filepath.Join(fixture.dir, "items.csv")
+	code, output = fixture.run(
+		t,
+		"export",
+		"--profile", "inventory",
+		"--schema", "main",
+		"--table", "items",
+		"--output", destination,
+	)
+	exported := decode[database.ExportResult](t, output)
+	if code != exitOK || exported.Data.Rows != 2 {
+		t.Fatalf("export exit = %d, output = %s", code, output)
+	}
+	contents, err := os.ReadFile(exported.Data.Path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(contents), "bolt") {
+		t.Fatalf("export contents = %q", contents)
+	}
+
+	code, output = fixture.run(t, "audit", "--profile", "inventory", "--operation", "query")
+	audited := decode[database.AuditLogPage](t, output)
+	if code != exitOK || audited.Data.Total != 1 ||
+		audited.Data.Entries[0].ProfileName != "inventory" {
+		t.Fatalf("audit exit = %d, output = %s", code, output)
+	}
+	code, output = fixture.run(t, "audit", "--verify")
+	verified := decode[database.AuditVerification](t, output)
+	if code != exitOK || !verified.Data.Valid || verified.Data.Entries != 1 {
+		t.Fatalf("audit verify exit = %d, output = %s", code, output)
+	}
+}
+
+func TestSchemaDiffFingerprintAppliesAcrossInvocations(t *testing.T) {
+	fixture := newCLIFixture(t)
+	fixture.saveSQLiteProfile(t, "source")
+	fixture.saveSQLiteProfile(t, "target")
+	if code, output := fixture.run(
+		t,
+		"query",
+		"--profile", "source",
+		"--sql", "CREATE TABLE widgets (id INTEGER PRIMARY KEY, label TEXT)",
+	); code != exitOK {
+		t.Fatalf("create exit = %d, output = %s", code, output)
+	}
+
+	code, output := fixture.run(
+		t,
+		"schema", "diff",
+		"--source", "source", "--source-schema", "main",
+		"--target", "target", "--target-schema", "main",
+	)
+	preview := decode[database.SchemaMigrationPreview](t, output)
+	if code != exitOK || preview.Data.Fingerprint == "" {
+		t.Fatalf("preview exit = %d, output = %s", code, output)
+	}
+
+	code, output = fixture.run(
+		t,
+		"schema", "diff",
+		"--source", "source", "--source-schema", "main",
+		"--target", "target", "--target-schema", "main",
+		"--fingerprint", preview.Data.Fingerprint,
+	)
+	if code != exitOK {
+		t.Fatalf("apply exit = %d, output = %s", code, output)
+	}
+	code, output = fixture.run(
+		t,
+		"query",
+		"--profile", "target",
+		"--sql", "SELECT COUNT(*) AS total FROM widgets",
+	)
+	if code != exitOK {
+		t.Fatalf("target query exit = %d, output = %s", code, output)
+	}
+}
+
+func TestUsageErrorsUseResponseEnvelope(t *testing.T) {
+	fixture := newCLIFixture(t)
+	code, output := fixture.run(t, "query", "--profile", "missing")
+	result := decode[bool](t, output)
+	if code != exitUsage || len(result.Errors) != 1 ||
+		result.Errors[0].Code != "INVALID_REQUEST" {
+		t.Fatalf("exit = %d, output = %s", code, output)
+	}
+
+	code, output = fixture.run(t, "query", "--profile", "missing", "--sql", "SELECT 1")
+	connected := decode[db.ConnectResponse](t, output)
+	if code != exitFailed || len(connected.Errors) != 1 ||
+		connected.Errors[0].Status != 404 {
+		t.Fatalf("exit = %d, output = %s", code, output)
+	}
+}
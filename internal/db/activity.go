@@ -87,6 +87,7 @@ func (s *Service) CancelDatabaseSession(
 	}
 	ctx, cancel := s.activityContext()
 	defer cancel()
+	startedAt := time.Now()
 	if err := activityDriver.CancelDatabaseSession(
 		ctx,
 		request.SessionID,
@@ -100,6 +101,17 @@ func (s *Service) CancelDatabaseSession(
 			"Verify session ownership and server privileges, then refresh activity.",
 		)
 	}
+	summary := "Cancelled the running query of session " + request.SessionID
+	if request.Terminate {
+		summary = "Terminated session " + request.SessionID
+	}
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationSessionCancel,
+		StartedAt:    startedAt,
+		ConnectionID: request.ConnectionID,
+		Target:       request.SessionID,
+		Summary:      summary,
+	})
 	return response.BaseResponse[database.CancelSessionResult]{
 		Data: database.CancelSessionResult{
 			Cancelled:  true,
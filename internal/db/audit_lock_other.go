@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package db
+
+import "os"
+
+// Platforms without file locking rely on the in-process audit mutex alone.
+func lockAuditFile(*os.File) error { return nil }
+
+func unlockAuditFile(*os.File) error { return nil }
@@ -0,0 +1,26 @@
+//go:build windows
+
+package db
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockAuditFile blocks until this process holds the exclusive lock on the
+// first byte of file, which stands for the whole audit log.
+func lockAuditFile(file *os.File) error {
+	return windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK,
+		0,
+		1,
+		0,
+		new(windows.Overlapped),
+	)
+}
+
+func unlockAuditFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
@@ -0,0 +1,575 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"rollingthunder/internal/diagnostics"
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	"github.com/google/uuid"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// auditTailChunk is how much of the log is read at a time while looking
+// for the newest entry.
+const auditTailChunk = 64 * 1024
+
+var errAuditLogCorrupt = errors.New("audit log entry is unreadable")
+
+// AuditLogStorage keeps the write audit log as JSON Lines next to the saved
+// connections. The file is only ever opened for appending; the service
+// never rewrites or truncates it.
+type AuditLogStorage struct {
+	FilePath string
+	initErr  error
+}
+
+func NewAuditLogStorage() *AuditLogStorage {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return &AuditLogStorage{
+			initErr: fmt.Errorf("resolve user configuration directory: %w", err),
+		}
+	}
+	return &AuditLogStorage{
+		FilePath: filepath.Join(configDir, application.SettingsDirectoryName, "audit.jsonl"),
+	}
+}
+
+// Append links entry to the newest entry in the file, fills in its
+// sequence and hashes, and appends it. The desktop app and the CLI can
+// append to the same log, so the read of the newest entry and the write
+// happen under a lock on a sibling file that every process takes.
+func (storage *AuditLogStorage) Append(
+	entry database.AuditEntry,
+) (database.AuditEntry, error) {
+	if storage.initErr != nil {
+		return entry, storage.initErr
+	}
+	if err := os.MkdirAll(filepath.Dir(storage.FilePath), 0o700); err != nil {
+		return entry, fmt.Errorf("create audit log directory: %w", err)
+	}
+	lock, err := os.OpenFile(storage.FilePath+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return entry, fmt.Errorf("open audit log lock: %w", err)
+	}
+	defer lock.Close()
+	if err := lockAuditFile(lock); err != nil {
+		return entry, fmt.Errorf("lock audit log: %w", err)
+	}
+	defer unlockAuditFile(lock)
+	file, err := os.OpenFile(storage.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return entry, fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	line, err := lastAuditLine(file)
+	if err != nil {
+		return entry, fmt.Errorf("read audit log: %w", err)
+	}
+	entry.Sequence, entry.PreviousHash = 1, ""
+	if len(line) > 0 {
+		var previous database.AuditEntry
+		if err := json.Unmarshal(line, &previous); err != nil {
+			return entry, fmt.Errorf("%w: newest entry: %v", errAuditLogCorrupt, err)
+		}
+		entry.Sequence, entry.PreviousHash = previous.Sequence+1, previous.Hash
+	}
+	if entry.Hash, err = entry.ComputeHash(); err != nil {
+		return entry, err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return entry, fmt.Errorf("encode audit entry: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return entry, fmt.Errorf("append audit entry: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return entry, fmt.Errorf("sync audit log: %w", err)
+	}
+	return entry, nil
+}
+
+// lastAuditLine returns the final non-empty line of the log, reading
+// backwards so appends stay cheap as the log grows.
+func lastAuditLine(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := min(int64(auditTailChunk), offset)
+		offset -= size
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if index := bytes.LastIndexByte(trimmed, '\n'); index >= 0 {
+			return trimmed[index+1:], nil
+		}
+		if offset == 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// Scan calls visit with every entry, oldest first. A line that does not
+// decode stops the scan with an error wrapping errAuditLogCorrupt.
+func (storage *AuditLogStorage) Scan(visit func(database.AuditEntry) error) error {
+	if storage.initErr != nil {
+		return storage.initErr
+	}
+	file, err := os.Open(storage.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("read audit log: %w", readErr)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var entry database.AuditEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return fmt.Errorf("%w: line %d: %v", errAuditLogCorrupt, lineNumber, err)
+			}
+			if err := visit(entry); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+var currentOSUser = sync.OnceValue(func() string {
+	current, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return current.Username
+})
+
+// recordAudit completes entry with the connection's profile and the local
+// user, redacts its text, and appends it to the audit log. The write it
+// describes has already succeeded, so a failure to record it is kept for
+// GetAuditLog instead of failing the caller.
+func (s *Service) recordAudit(entry database.AuditEntry) {
+	s.mu.RLock()
+	connection := s.connections[entry.ConnectionID]
+	s.mu.RUnlock()
+	if connection != nil {
+		entry.ProfileID = connection.ProfileID
+		entry.ProfileName = connection.Name
+		entry.Environment = connection.Environment
+		entry.Engine = connection.Driver.Capabilities().Engine
+		entry.Database = connection.Config.Db
+		entry.DatabaseUser = connection.Config.User
+	}
+	entry.ID = uuid.NewString()
+	entry.OSUser = currentOSUser()
+	entry.StartedAt = entry.StartedAt.UTC()
+	entry.RecordedAt = time.Now().UTC()
+	entry.DurationMS = entry.RecordedAt.Sub(entry.StartedAt).Milliseconds()
+	entry.Summary = diagnostics.Redact(entry.Summary)
+	entry.Target = diagnostics.Redact(entry.Target)
+	if entry.Statements == nil {
+		entry.Statements = []database.AuditStatement{}
+	}
+	for index := range entry.Statements {
+		entry.Statements[index].SQL = diagnostics.Redact(entry.Statements[index].SQL)
+	}
+
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	if _, err := s.auditLog.Append(entry); err != nil {
+		s.auditWriteError = fmt.Sprintf(
+			"%s: %s was not recorded: %v",
+			entry.RecordedAt.Format(time.RFC3339),
+			entry.Summary,
+			err,
+		)
+	}
+}
+
+func auditStatements(statements []string) []database.AuditStatement {
+	audited := make([]database.AuditStatement, len(statements))
+	for index, statement := range statements {
+		audited[index] = database.AuditStatement{SQL: statement}
+	}
+	return audited
+}
+
+func auditRowCount(count int) *int64 {
+	rows := int64(count)
+	return &rows
+}
+
+// auditTarget joins the non-empty parts of a qualified object name.
+func auditTarget(parts ...string) string {
+	named := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			named = append(named, part)
+		}
+	}
+	return strings.Join(named, ".")
+}
+
+// auditQuery records an auto-commit or transactional batch that contains
+// a write. Statements inside a transaction are also counted on the session
+// so its commit or rollback is recorded too. A non-empty outcome is added
+// to the summary of a batch that stopped early.
+func (s *Service) auditQuery(
+	request database.QueryRequest,
+	result database.QueryResult,
+	startedAt time.Time,
+	keyword string,
+	outcome string,
+) {
+	statements := make([]database.AuditStatement, len(result.ResultSets))
+	var total *int64
+	for index, set := range result.ResultSets {
+		statements[index] = database.AuditStatement{
+			SQL:          set.Statement,
+			RowsAffected: set.RowsAffected,
+		}
+		if set.RowsAffected != nil {
+			if total == nil {
+				total = new(int64)
+			}
+			*total += *set.RowsAffected
+		}
+	}
+	summary := "Ran " + keyword
+	if len(statements) > 1 {
+		summary = fmt.Sprintf("Ran %d statements including %s", len(statements), keyword)
+	}
+	transactionID := strings.TrimSpace(request.TransactionID)
+	if transactionID != "" {
+		summary += " in a transaction"
+		s.transactionMu.RLock()
+		if session := s.transactions[transactionID]; session != nil {
+			session.auditedWrites.Add(1)
+		}
+		s.transactionMu.RUnlock()
+	}
+	if outcome != "" {
+		summary += "; " + outcome
+	}
+	s.recordAudit(database.AuditEntry{
+		Operation:     database.AuditOperationQuery,
+		StartedAt:     startedAt,
+		ConnectionID:  request.ConnectionID,
+		TransactionID: transactionID,
+		Summary:       summary,
+		Statements:    statements,
+		RowsAffected:  total,
+	})
+}
+
+// auditFailedQueryBatch records the statements of a failed batch that ran
+// before the failure. In auto-commit mode each of them has already
+// committed, so a write among them is audited like a successful batch.
+func (s *Service) auditFailedQueryBatch(
+	request database.QueryRequest,
+	result database.QueryResult,
+	startedAt time.Time,
+) {
+	// The last result set belongs to the statement that failed.
+	if len(result.ResultSets) < 2 {
+		return
+	}
+	completed := result.ResultSets[:len(result.ResultSets)-1]
+	ran := make([]string, len(completed))
+	for index, set := range completed {
+		ran[index] = set.Statement
+	}
+	keyword := database.FindWriteStatement(strings.Join(ran, ";\n"))
+	if keyword == "" {
+		return
+	}
+	result.ResultSets = completed
+	s.auditQuery(
+		request,
+		result,
+		startedAt,
+		keyword,
+		fmt.Sprintf("statement %d failed", len(completed)+1),
+	)
+}
+
+func auditLogError[T any](summary string, err error) response.BaseResponse[T] {
+	return serviceErrorWithCode[T](
+		http.StatusInternalServerError,
+		errorCodeDatabaseOperationFailed,
+		summary,
+		err.Error(),
+		"Check access to the Rolling Thunder settings directory.",
+	)
+}
+
+func normalizeAuditLogFilter(filter database.AuditLogFilter) database.AuditLogFilter {
+	filter.ProfileID = strings.TrimSpace(filter.ProfileID)
+	filter.Environment = strings.TrimSpace(filter.Environment)
+	if filter.Limit <= 0 {
+		filter.Limit = database.DefaultAuditLogLimit
+	}
+	filter.Limit = min(filter.Limit, database.MaxAuditLogLimit)
+	return filter
+}
+
+// GetAuditLog lists matching audit entries newest first.
+func (s *Service) GetAuditLog(
+	filter database.AuditLogFilter,
+) response.BaseResponse[database.AuditLogPage] {
+	filter = normalizeAuditLogFilter(filter)
+	page := database.AuditLogPage{Entries: []database.AuditEntry{}}
+	err := s.auditLog.Scan(func(entry database.AuditEntry) error {
+		if !filter.Matches(entry) {
+			return nil
+		}
+		page.Total++
+		page.Entries = append(page.Entries, entry)
+		if len(page.Entries) > filter.Limit {
+			page.Entries = page.Entries[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return auditLogError[database.AuditLogPage]("Could not load the audit log", err)
+	}
+	sort.SliceStable(page.Entries, func(left, right int) bool {
+		return page.Entries[left].Sequence > page.Entries[right].Sequence
+	})
+	s.auditMu.Lock()
+	page.WriteError = s.auditWriteError
+	s.auditMu.Unlock()
+	return response.BaseResponse[database.AuditLogPage]{Data: page}
+}
+
+// VerifyAuditLog walks the whole log and reports the first entry that was
+// edited, removed, reordered, or cannot be read.
+func (s *Service) VerifyAuditLog() response.BaseResponse[database.AuditVerification] {
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	var (
+		verification database.AuditVerification
+		previous     *database.AuditEntry
+	)
+	err := s.auditLog.Scan(func(entry database.AuditEntry) error {
+		if err := database.VerifyAuditLink(previous, entry); err != nil {
+			verification.BrokenAt = entry.Sequence
+			return err
+		}
+		verification.Entries++
+		verification.Head = entry.Hash
+		previous = &entry
+		return nil
+	})
+	switch {
+	case err == nil:
+		verification.Valid = true
+	case errors.Is(err, errAuditLogCorrupt):
+		verification.BrokenAt = verification.Entries + 1
+		verification.Problem = err.Error()
+	case verification.BrokenAt != 0:
+		verification.Problem = err.Error()
+	default:
+		return auditLogError[database.AuditVerification]("Could not read the audit log", err)
+	}
+	return response.BaseResponse[database.AuditVerification]{Data: verification}
+}
+
+// ExportAuditLog writes the matching entries to a file chosen in the native
+// save dialog.
+func (s *Service) ExportAuditLog(
+	request database.AuditExportRequest,
+) response.BaseResponse[database.ExportResult] {
+	if request.Format != database.ExportFormatCSV && request.Format != database.ExportFormatJSON {
+		return serviceErrorWithCode[database.ExportResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Unsupported audit export format",
+			fmt.Sprintf("The audit log cannot be exported as %q.", request.Format),
+			"Export the audit log as CSV or JSON.",
+		)
+	}
+	if s.ctx == nil {
+		return serviceErrorWithCode[database.ExportResult](
+			http.StatusServiceUnavailable,
+			errorCodeDatabaseOperationFailed,
+			"Application is not ready",
+			"The native destination picker is unavailable.",
+			"Wait for Rolling Thunder to finish starting and try again.",
+		)
+	}
+	extension, pattern, display := ".csv", "*.csv", "CSV file (*.csv)"
+	if request.Format == database.ExportFormatJSON {
+		extension, pattern, display = ".jsonl", "*.jsonl", "JSON Lines (*.jsonl)"
+	}
+	path, err := s.saveDialog(s.ctx, wailsruntime.SaveDialogOptions{
+		Title:                "Export " + application.Name + " audit log",
+		DefaultFilename:      application.Identifier + "-audit" + extension,
+		CanCreateDirectories: true,
+		Filters:              []wailsruntime.FileFilter{{DisplayName: display, Pattern: pattern}},
+	})
+	if err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	if path == "" {
+		return response.BaseResponse[database.ExportResult]{
+			Data: database.ExportResult{Cancelled: true, Format: request.Format},
+		}
+	}
+	if filepath.Ext(path) == "" {
+		path += extension
+	}
+	result, err := s.writeAuditExport(path, request)
+	if err != nil {
+		return auditLogError[database.ExportResult]("Could not export the audit log", err)
+	}
+	return response.BaseResponse[database.ExportResult]{Data: result}
+}
+
+func (s *Service) writeAuditExport(
+	path string,
+	request database.AuditExportRequest,
+) (database.ExportResult, error) {
+	result := database.ExportResult{Path: path, Format: request.Format}
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+application.Identifier+"-audit-*")
+	if err != nil {
+		return result, fmt.Errorf("create audit export: %w", err)
+	}
+	tempPath := temp.Name()
+	defer func() {
+		_ = temp.Close()
+		_ = os.Remove(tempPath)
+	}()
+
+	buffered := bufio.NewWriter(temp)
+	counter := &countingWriter{writer: buffered}
+	var csvWriter *csv.Writer
+	if request.Format == database.ExportFormatCSV {
+		csvWriter = csv.NewWriter(counter)
+		if err := csvWriter.Write(auditCSVHeader); err != nil {
+			return result, err
+		}
+	}
+	encoder := json.NewEncoder(counter)
+	encoder.SetEscapeHTML(false)
+	err = s.auditLog.Scan(func(entry database.AuditEntry) error {
+		if !request.Filter.Matches(entry) {
+			return nil
+		}
+		result.Rows++
+		if csvWriter != nil {
+			return csvWriter.Write(auditCSVRecord(entry))
+		}
+		return encoder.Encode(entry)
+	})
+	if err != nil {
+		return result, err
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return result, err
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return result, fmt.Errorf("write audit export: %w", err)
+	}
+	if err := temp.Sync(); err != nil {
+		return result, fmt.Errorf("sync audit export: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return result, fmt.Errorf("close audit export: %w", err)
+	}
+	if err := replaceExportFile(tempPath, path); err != nil {
+		return result, err
+	}
+	result.Bytes = counter.bytes
+	return result, nil
+}
+
+var auditCSVHeader = []string{
+	"sequence",
+	"recorded_at",
+	"started_at",
+	"duration_ms",
+	"operation",
+	"profile",
+	"environment",
+	"engine",
+	"database",
+	"database_user",
+	"os_user",
+	"transaction_id",
+	"target",
+	"summary",
+	"rows_affected",
+	"statements",
+	"previous_hash",
+	"hash",
+}
+
+func auditCSVRecord(entry database.AuditEntry) []string {
+	rows := ""
+	if entry.RowsAffected != nil {
+		rows = strconv.FormatInt(*entry.RowsAffected, 10)
+	}
+	statements := make([]string, len(entry.Statements))
+	for index, statement := range entry.Statements {
+		statements[index] = statement.SQL
+	}
+	return []string{
+		strconv.FormatInt(entry.Sequence, 10),
+		entry.RecordedAt.Format(time.RFC3339Nano),
+		entry.StartedAt.Format(time.RFC3339Nano),
+		strconv.FormatInt(entry.DurationMS, 10),
+		string(entry.Operation),
+		entry.ProfileName,
+		entry.Environment,
+		entry.Engine,
+		entry.Database,
+		entry.DatabaseUser,
+		entry.OSUser,
+		entry.TransactionID,
+		entry.Target,
+		entry.Summary,
+		rows,
+		strings.Join(statements, ";\n"),
+		entry.PreviousHash,
+		entry.Hash,
+	}
+}
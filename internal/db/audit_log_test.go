@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"rollingthunder/pkg/database"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func TestAuditLogRecordsWritesAndDetectsTampering(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	service.auditLog = &AuditLogStorage{FilePath: logPath}
+	connected := service.Connect(ConnectRequest{
+		Driver: "sqlite",
+		Config: database.Config{
+			Name:        "ledger",
+			Driver:      "sqlite",
+			Db:          filepath.Join(t.TempDir(), "ledger.sqlite3"),
+			Environment: database.ConnectionEnvironmentStaging,
+		},
+	})
+	if len(connected.Errors) > 0 {
+		t.Fatalf("Connect() errors = %+v", connected.Errors)
+	}
+	connectionID := connected.Data.ConnectionID
+	t.Cleanup(func() {
+		_ = service.DisconnectConnection(connectionID)
+	})
+	run := func(transactionID string, query string) {
+		t.Helper()
+		result := service.ExecuteQuery(database.QueryRequest{
+			ConnectionID:  connectionID,
+			TransactionID: transactionID,
+			Query:         query,
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("ExecuteQuery(%q) errors = %+v", query, result.Errors)
+		}
+	}
+
+	run("", "CREATE TABLE main.accounts (id INTEGER PRIMARY KEY, token TEXT); "+
+		"INSERT INTO main.accounts VALUES (1, 'a'), (2, 'b')")
+	run("", "SELECT * FROM main.accounts")
+	run("", "UPDATE main.accounts SET token = 'hunter2' WHERE id = 1")
+	if begin := service.BeginTransaction(connectionID, "audited"); len(begin.Errors) > 0 {
+		t.Fatalf("BeginTransaction() errors = %+v", begin.Errors)
+	}
+	run("audited", "DELETE FROM main.accounts WHERE id = 2")
+	if committed := service.CommitTransaction("audited"); len(committed.Errors) > 0 {
+		t.Fatalf("CommitTransaction() errors = %+v", committed.Errors)
+	}
+
+	page := service.GetAuditLog(database.AuditLogFilter{})
+	if len(page.Errors) > 0 || page.Data.Total != 4 || page.Data.WriteError != "" {
+		t.Fatalf("GetAuditLog() = %+v", page)
+	}
+	entries := page.Data.Entries
+	if entries[0].Operation != database.AuditOperationTransaction ||
+		entries[0].TransactionID != "audited" || entries[1].TransactionID != "audited" {
+		t.Fatalf("transaction entries = %+v", entries[:2])
+	}
+	updated := entries[2]
+	if updated.Environment != database.ConnectionEnvironmentStaging ||
+		updated.ProfileName != "ledger" || updated.Engine == "" ||
+		updated.RowsAffected == nil || *updated.RowsAffected != 1 {
+		t.Fatalf("update entry = %+v", updated)
+	}
+	if sql := updated.Statements[0].SQL; strings.Contains(sql, "hunter2") {
+		t.Fatalf("update statement was not redacted: %q", sql)
+	}
+	if first := entries[3]; len(first.Statements) != 2 || first.Sequence != 1 || first.PreviousHash != "" {
+		t.Fatalf("first entry = %+v", first)
+	}
+	deletes := service.GetAuditLog(database.AuditLogFilter{Search: "delete", Limit: 1})
+	if deletes.Data.Total != 1 || deletes.Data.Entries[0].Sequence != 3 {
+		t.Fatalf("filtered audit log = %+v", deletes.Data)
+	}
+
+	verified := service.VerifyAuditLog()
+	if !verified.Data.Valid || verified.Data.Entries != 4 || verified.Data.Head != entries[0].Hash {
+		t.Fatalf("VerifyAuditLog() = %+v", verified)
+	}
+
+	destination := filepath.Join(t.TempDir(), "audit")
+	service.saveDialog = func(context.Context, wailsruntime.SaveDialogOptions) (string, error) {
+		return destination, nil
+	}
+	exported := service.ExportAuditLog(database.AuditExportRequest{
+		Filter: database.AuditLogFilter{Operations: []database.AuditOperation{database.AuditOperationQuery}},
+		Format: database.ExportFormatCSV,
+	})
+	if len(exported.Errors) > 0 || exported.Data.Rows != 3 || exported.Data.Path != destination+".csv" {
+		t.Fatalf("ExportAuditLog() = %+v", exported)
+	}
+	contents, err := os.ReadFile(exported.Data.Path)
+	if err != nil || !strings.HasPrefix(string(contents), "sequence,recorded_at,") {
+		t.Fatalf("audit export = %q, %v", contents, err)
+	}
+
+	original, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	edited := strings.Replace(string(original), `"operation":"query"`, `"operation":"restore"`, 1)
+	if err := os.WriteFile(logPath, []byte(edited), 0o600); err != nil {
+		t.Fatalf("edit audit log: %v", err)
+	}
+	if tampered := service.VerifyAuditLog().Data; tampered.Valid || tampered.BrokenAt != 1 {
+		t.Fatalf("VerifyAuditLog() after an edit = %+v", tampered)
+	}
+	lines := strings.SplitAfter(string(original), "\n")
+	removed := strings.Join(append(lines[:1:1], lines[2:]...), "")
+	if err := os.WriteFile(logPath, []byte(removed), 0o600); err != nil {
+		t.Fatalf("remove audit entry: %v", err)
+	}
+	if tampered := service.VerifyAuditLog().Data; tampered.Valid || tampered.BrokenAt != 3 {
+		t.Fatalf("VerifyAuditLog() after a removal = %+v", tampered)
+	}
+}
+
+func TestAuditLogRecordsWritesBeforeAFailedStatement(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	service.auditLog = &AuditLogStorage{FilePath: filepath.Join(t.TempDir(), "audit.jsonl")}
+	connected := service.Connect(ConnectRequest{
+		Driver: "sqlite",
+		Config: database.Config{
+			Name:   "ledger",
+			Driver: "sqlite",
+			Db:     filepath.Join(t.TempDir(), "ledger.sqlite3"),
+		},
+	})
+	if len(connected.Errors) > 0 {
+		t.Fatalf("Connect() errors = %+v", connected.Errors)
+	}
+	connectionID := connected.Data.ConnectionID
+	t.Cleanup(func() {
+		_ = service.DisconnectConnection(connectionID)
+	})
+	if _, err := service.connections[connectionID].Driver.ExecuteQuery(
+		context.Background(),
+		"CREATE TABLE main.accounts (id INTEGER PRIMARY KEY); INSERT INTO main.accounts VALUES (1), (2)",
+		database.QueryOptions{},
+	); err != nil {
+		t.Fatalf("create fixture: %v", err)
+	}
+
+	failed := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "DELETE FROM main.accounts WHERE id = 1; INSERT INTO main.missing VALUES (1)",
+	})
+	if len(failed.Errors) == 0 {
+		t.Fatal("ExecuteQuery() with a missing table succeeded")
+	}
+
+	page := service.GetAuditLog(database.AuditLogFilter{})
+	if len(page.Errors) > 0 || page.Data.Total != 1 {
+		t.Fatalf("GetAuditLog() = %+v", page)
+	}
+	entry := page.Data.Entries[0]
+	if len(entry.Statements) != 1 ||
+		!strings.HasPrefix(entry.Statements[0].SQL, "DELETE") ||
+		entry.RowsAffected == nil || *entry.RowsAffected != 1 ||
+		!strings.Contains(entry.Summary, "statement 2 failed") {
+		t.Fatalf("partial batch entry = %+v", entry)
+	}
+}
+
+func TestAuditLogAppendsFromSeparateWritersKeepOneChain(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	// Each storage stands in for another process: they share no mutex.
+	writers := make([]*AuditLogStorage, 8)
+	for index := range writers {
+		writers[index] = &AuditLogStorage{FilePath: logPath}
+	}
+	var wait sync.WaitGroup
+	for _, writer := range writers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for range 25 {
+				if _, err := writer.Append(database.AuditEntry{Summary: "Ran UPDATE"}); err != nil {
+					t.Errorf("Append() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wait.Wait()
+
+	var previous database.AuditEntry
+	if err := writers[0].Scan(func(entry database.AuditEntry) error {
+		if entry.Sequence != previous.Sequence+1 || entry.PreviousHash != previous.Hash {
+			t.Fatalf("entry %d follows %d with previous hash %q", entry.Sequence, previous.Sequence, entry.PreviousHash)
+		}
+		previous = entry
+		return nil
+	}); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if previous.Sequence != 200 {
+		t.Fatalf("entries = %d, want 200", previous.Sequence)
+	}
+}
@@ -0,0 +1,762 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+const (
+	backupCatalogVersion = 1
+	// maxBackupCatalogEntries bounds the history of failed, cancelled, and
+	// pruned attempts. Entries that still name a backup file are never
+	// dropped to make room.
+	maxBackupCatalogEntries        = 500
+	defaultBackupScheduleInterval  = 30 * time.Second
+	backupSchedulerShutdownTimeout = 2 * time.Second
+)
+
+type backupCatalogEnvelope struct {
+	Version   int                           `json:"version"`
+	Schedules []database.BackupSchedule     `json:"schedules"`
+	Entries   []database.BackupCatalogEntry `json:"entries"`
+}
+
+// BackupCatalogStorage persists backup schedules and the record of every
+// backup next to the saved connections. It holds no secrets; schedules
+// name a saved profile by ID.
+type BackupCatalogStorage struct {
+	FilePath string
+	initErr  error
+}
+
+func NewBackupCatalogStorage() *BackupCatalogStorage {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return &BackupCatalogStorage{
+			initErr: fmt.Errorf("resolve user configuration directory: %w", err),
+		}
+	}
+	return &BackupCatalogStorage{
+		FilePath: filepath.Join(configDir, application.SettingsDirectoryName, "backups.json"),
+	}
+}
+
+func (storage *BackupCatalogStorage) Load() (backupCatalogEnvelope, error) {
+	empty := backupCatalogEnvelope{
+		Version:   backupCatalogVersion,
+		Schedules: []database.BackupSchedule{},
+		Entries:   []database.BackupCatalogEntry{},
+	}
+	if storage.initErr != nil {
+		return empty, storage.initErr
+	}
+	data, err := os.ReadFile(storage.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return empty, nil
+		}
+		return empty, fmt.Errorf("read backup catalog: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return empty, nil
+	}
+	var envelope backupCatalogEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return empty, fmt.Errorf("decode backup catalog: %w", err)
+	}
+	if envelope.Version <= 0 || envelope.Version > backupCatalogVersion {
+		return empty, fmt.Errorf("unsupported backup catalog version %d", envelope.Version)
+	}
+	if envelope.Schedules == nil {
+		envelope.Schedules = []database.BackupSchedule{}
+	}
+	if envelope.Entries == nil {
+		envelope.Entries = []database.BackupCatalogEntry{}
+	}
+	return envelope, nil
+}
+
+// Save replaces the catalog atomically with a 0600 file.
+func (storage *BackupCatalogStorage) Save(envelope backupCatalogEnvelope) error {
+	if storage.initErr != nil {
+		return storage.initErr
+	}
+	envelope.Version = backupCatalogVersion
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode backup catalog: %w", err)
+	}
+	directory := filepath.Dir(storage.FilePath)
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return fmt.Errorf("create backup catalog directory: %w", err)
+	}
+	temp, err := os.CreateTemp(directory, ".backups-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary backup catalog: %w", err)
+	}
+	tempPath := temp.Name()
+	defer func() {
+		_ = temp.Close()
+		_ = os.Remove(tempPath)
+	}()
+	if err := temp.Chmod(0o600); err != nil {
+		return fmt.Errorf("secure temporary backup catalog: %w", err)
+	}
+	if _, err := temp.Write(data); err != nil {
+		return fmt.Errorf("write temporary backup catalog: %w", err)
+	}
+	if err := temp.Sync(); err != nil {
+		return fmt.Errorf("sync temporary backup catalog: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("close temporary backup catalog: %w", err)
+	}
+	if err := os.Rename(tempPath, storage.FilePath); err != nil {
+		return fmt.Errorf("replace backup catalog: %w", err)
+	}
+	return nil
+}
+
+// updateBackupCatalog serializes read-modify-write cycles on the catalog
+// file between the scheduler and bound service calls.
+func (s *Service) updateBackupCatalog(
+	update func(*backupCatalogEnvelope) error,
+) error {
+	s.backupCatalogMu.Lock()
+	defer s.backupCatalogMu.Unlock()
+	envelope, err := s.backupCatalog.Load()
+	if err != nil {
+		return err
+	}
+	if err := update(&envelope); err != nil {
+		return err
+	}
+	return s.backupCatalog.Save(envelope)
+}
+
+func (s *Service) readBackupCatalog() (backupCatalogEnvelope, error) {
+	s.backupCatalogMu.Lock()
+	defer s.backupCatalogMu.Unlock()
+	return s.backupCatalog.Load()
+}
+
+func backupCatalogError[T any](summary string, err error) response.BaseResponse[T] {
+	return serviceErrorWithCode[T](
+		http.StatusInternalServerError,
+		errorCodeDatabaseOperationFailed,
+		summary,
+		err.Error(),
+		"Check access to the Rolling Thunder settings directory.",
+	)
+}
+
+// backupRecord is a catalog entry for a backup that is still running.
+type backupRecord struct {
+	database.BackupCatalogEntry
+}
+
+func (s *Service) newBackupCatalogEntry(
+	connection *Connection,
+	capabilities database.BackupCapabilities,
+	trigger database.BackupTrigger,
+) *backupRecord {
+	return &backupRecord{database.BackupCatalogEntry{
+		ID:          uuid.NewString(),
+		ProfileID:   connection.ProfileID,
+		ProfileName: connection.Name,
+		Trigger:     trigger,
+		Engine:      capabilities.Engine,
+		Format:      capabilities.Format,
+		StartedAt:   time.Now().UTC(),
+	}}
+}
+
+func (record *backupRecord) finish(file backupFile, err error) {
+	record.DurationMS = time.Since(record.StartedAt).Milliseconds()
+	switch {
+	case err == nil:
+		record.Outcome = database.BackupOutcomeSucceeded
+		record.Path = file.path
+		record.Bytes = file.bytes
+		record.SHA256 = file.sha256
+	case errors.Is(err, context.Canceled):
+		record.Outcome = database.BackupOutcomeCancelled
+	default:
+		record.Outcome = database.BackupOutcomeFailed
+		record.Error = err.Error()
+	}
+}
+
+func (s *Service) recordBackup(record *backupRecord) (string, error) {
+	err := s.updateBackupCatalog(func(envelope *backupCatalogEnvelope) error {
+		envelope.Entries = trimBackupCatalog(
+			append(envelope.Entries, record.BackupCatalogEntry),
+		)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return record.ID, nil
+}
+
+func (s *Service) catalogBackupResult(
+	record *backupRecord,
+	result *database.BackupResult,
+) {
+	catalogID, err := s.recordBackup(record)
+	if err != nil {
+		result.Warning = "The backup was saved but could not be added to the backup catalog: " +
+			err.Error()
+		return
+	}
+	result.CatalogID = catalogID
+}
+
+// trimBackupCatalog drops the oldest entries that no longer name a backup
+// file once the catalog exceeds maxBackupCatalogEntries.
+func trimBackupCatalog(
+	entries []database.BackupCatalogEntry,
+) []database.BackupCatalogEntry {
+	excess := len(entries) - maxBackupCatalogEntries
+	if excess <= 0 {
+		return entries
+	}
+	trimmed := make([]database.BackupCatalogEntry, 0, len(entries)-excess)
+	for _, entry := range entries {
+		present := entry.Outcome == database.BackupOutcomeSucceeded && entry.PrunedAt == nil
+		if excess > 0 && !present {
+			excess--
+			continue
+		}
+		trimmed = append(trimmed, entry)
+	}
+	return trimmed
+}
+
+// GetBackupCatalog lists recorded backups newest first, optionally for one
+// saved profile.
+func (s *Service) GetBackupCatalog(
+	profileID string,
+) response.BaseResponse[[]database.BackupCatalogEntry] {
+	envelope, err := s.readBackupCatalog()
+	if err != nil {
+		return backupCatalogError[[]database.BackupCatalogEntry](
+			"Could not load the backup catalog",
+			err,
+		)
+	}
+	profileID = strings.TrimSpace(profileID)
+	entries := make([]database.BackupCatalogEntry, 0, len(envelope.Entries))
+	for _, entry := range envelope.Entries {
+		if profileID == "" || entry.ProfileID == profileID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(left, right int) bool {
+		return entries[left].StartedAt.After(entries[right].StartedAt)
+	})
+	return response.BaseResponse[[]database.BackupCatalogEntry]{Data: entries}
+}
+
+// ChooseCatalogRestoreFile grants a restore token for a cataloged backup.
+// Preview and apply re-hash the file and refuse it unless it still matches
+// the recorded SHA-256.
+func (s *Service) ChooseCatalogRestoreFile(
+	connectionID string,
+	entryID string,
+) response.BaseResponse[database.RestoreFileSelection] {
+	connection, release, err := s.pinnedConnection(connectionID)
+	if err != nil {
+		return serviceError[database.RestoreFileSelection](err.Error())
+	}
+	engine := connection.Driver.Capabilities().Engine
+	release()
+	envelope, err := s.readBackupCatalog()
+	if err != nil {
+		return backupCatalogError[database.RestoreFileSelection](
+			"Could not load the backup catalog",
+			err,
+		)
+	}
+	for _, entry := range envelope.Entries {
+		if entry.ID != strings.TrimSpace(entryID) {
+			continue
+		}
+		if !entry.Restorable() {
+			return serviceErrorWithCode[database.RestoreFileSelection](
+				http.StatusBadRequest,
+				errorCodeInvalidRequest,
+				"Backup cannot be restored from the catalog",
+				"The catalog entry did not produce a local backup file, or its file was removed by retention.",
+				"Choose a successful backup from the catalog or pick a file instead.",
+			)
+		}
+		if entry.Engine != engine && entry.Format != database.BackupFormatLogical {
+			return serviceErrorWithCode[database.RestoreFileSelection](
+				http.StatusBadRequest,
+				errorCodeInvalidRequest,
+				"Backup engine does not match",
+				fmt.Sprintf("The backup was taken from %s and cannot be restored into %s.", entry.Engine, engine),
+				"Choose a backup created for the selected database engine.",
+			)
+		}
+		return s.grantRestoreFile(connectionID, engine, entry.Path, entry.SHA256)
+	}
+	return serviceErrorWithCode[database.RestoreFileSelection](
+		http.StatusNotFound,
+		errorCodeInvalidRequest,
+		"Backup not found in the catalog",
+		"The selected catalog entry no longer exists.",
+		"Refresh the backup catalog and choose another backup.",
+	)
+}
+
+// GetBackupSchedules lists saved schedules with their next run time and the
+// newest catalog entry each one produced.
+func (s *Service) GetBackupSchedules() response.BaseResponse[[]database.BackupScheduleStatus] {
+	envelope, err := s.readBackupCatalog()
+	if err != nil {
+		return backupCatalogError[[]database.BackupScheduleStatus](
+			"Could not load backup schedules",
+			err,
+		)
+	}
+	statuses := make([]database.BackupScheduleStatus, 0, len(envelope.Schedules))
+	for _, schedule := range envelope.Schedules {
+		status := database.BackupScheduleStatus{Schedule: schedule}
+		if next, ok := s.nextBackupRun(schedule, time.Now()); ok {
+			status.NextRunAt = &next
+		}
+		for index := range envelope.Entries {
+			entry := envelope.Entries[index]
+			if entry.ScheduleID != schedule.ID {
+				continue
+			}
+			if status.LastRun == nil || entry.StartedAt.After(status.LastRun.StartedAt) {
+				status.LastRun = &entry
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return response.BaseResponse[[]database.BackupScheduleStatus]{Data: statuses}
+}
+
+// SaveBackupSchedule creates a schedule when ID is empty and replaces the
+// schedule with the same ID otherwise.
+func (s *Service) SaveBackupSchedule(
+	schedule database.BackupSchedule,
+) response.BaseResponse[database.BackupSchedule] {
+	schedule.ProfileID = strings.TrimSpace(schedule.ProfileID)
+	schedule.Cron = strings.TrimSpace(schedule.Cron)
+	schedule.Destination = filepath.Clean(strings.TrimSpace(schedule.Destination))
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if err := schedule.Validate(); err != nil {
+		return serviceErrorWithCode[database.BackupSchedule](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid backup schedule",
+			err.Error(),
+			"Use a five-field cron expression and an absolute destination directory.",
+		)
+	}
+	connections, err := s.loadSavedConnections()
+	if err != nil {
+		return connectionStorageError[database.BackupSchedule](
+			"Could not load saved connections",
+			err,
+		)
+	}
+	var profile *SavedConnection
+	for index := range connections {
+		if connections[index].ID == schedule.ProfileID {
+			profile = &connections[index]
+			break
+		}
+	}
+	if profile == nil {
+		return serviceErrorWithCode[database.BackupSchedule](
+			http.StatusNotFound,
+			errorCodeInvalidRequest,
+			"Connection profile not found",
+			"Backup schedules need a saved connection profile.",
+			"Save the connection first, then schedule its backups.",
+		)
+	}
+	if schedule.Name == "" {
+		schedule.Name = profile.Config.Name
+	}
+	if info, statErr := os.Stat(schedule.Destination); statErr != nil || !info.IsDir() {
+		return serviceErrorWithCode[database.BackupSchedule](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Backup destination not found",
+			fmt.Sprintf("%s is not an existing directory.", schedule.Destination),
+			"Create the destination directory or choose another one.",
+		)
+	}
+	if schedule.ID == "" {
+		schedule.ID = uuid.NewString()
+	}
+	err = s.updateBackupCatalog(func(envelope *backupCatalogEnvelope) error {
+		for index := range envelope.Schedules {
+			if envelope.Schedules[index].ID == schedule.ID {
+				envelope.Schedules[index] = schedule
+				return nil
+			}
+		}
+		envelope.Schedules = append(envelope.Schedules, schedule)
+		return nil
+	})
+	if err != nil {
+		return backupCatalogError[database.BackupSchedule](
+			"Could not save the backup schedule",
+			err,
+		)
+	}
+	s.forgetBackupRun(schedule.ID)
+	return response.BaseResponse[database.BackupSchedule]{Data: schedule}
+}
+
+// DeleteBackupSchedule removes a schedule. Its catalog entries and backup
+// files are kept.
+func (s *Service) DeleteBackupSchedule(scheduleID string) response.BaseResponse[bool] {
+	scheduleID = strings.TrimSpace(scheduleID)
+	removed := false
+	err := s.updateBackupCatalog(func(envelope *backupCatalogEnvelope) error {
+		kept := envelope.Schedules[:0]
+		for _, schedule := range envelope.Schedules {
+			if schedule.ID == scheduleID {
+				removed = true
+				continue
+			}
+			kept = append(kept, schedule)
+		}
+		envelope.Schedules = kept
+		return nil
+	})
+	if err != nil {
+		return backupCatalogError[bool]("Could not delete the backup schedule", err)
+	}
+	s.forgetBackupRun(scheduleID)
+	return response.BaseResponse[bool]{Data: removed}
+}
+
+// RunBackupSchedule runs a schedule immediately, whether or not it is
+// enabled, and applies its retention policy.
+func (s *Service) RunBackupSchedule(
+	scheduleID string,
+) response.BaseResponse[database.BackupCatalogEntry] {
+	envelope, err := s.readBackupCatalog()
+	if err != nil {
+		return backupCatalogError[database.BackupCatalogEntry](
+			"Could not load backup schedules",
+			err,
+		)
+	}
+	for _, schedule := range envelope.Schedules {
+		if schedule.ID != strings.TrimSpace(scheduleID) {
+			continue
+		}
+		parent := s.ctx
+		if parent == nil {
+			parent = context.Background()
+		}
+		entry, err := s.runBackupSchedule(parent, schedule, time.Now())
+		if err != nil {
+			return backupCatalogError[database.BackupCatalogEntry](
+				"Could not record the scheduled backup",
+				err,
+			)
+		}
+		return response.BaseResponse[database.BackupCatalogEntry]{Data: entry}
+	}
+	return serviceErrorWithCode[database.BackupCatalogEntry](
+		http.StatusNotFound,
+		errorCodeInvalidRequest,
+		"Backup schedule not found",
+		"The backup schedule no longer exists.",
+		"Refresh backup schedules and choose another one.",
+	)
+}
+
+type scheduledBackupRun struct {
+	cron string
+	next time.Time
+}
+
+// nextBackupRun reports when an enabled schedule is due. The first run is
+// computed from when the scheduler first saw the schedule; runs missed
+// while the application was closed are not caught up.
+func (s *Service) nextBackupRun(
+	schedule database.BackupSchedule,
+	now time.Time,
+) (time.Time, bool) {
+	if !schedule.Enabled {
+		return time.Time{}, false
+	}
+	s.backupScheduleMu.Lock()
+	defer s.backupScheduleMu.Unlock()
+	if run, ok := s.backupRuns[schedule.ID]; ok && run.cron == schedule.Cron {
+		return run.next, !run.next.IsZero()
+	}
+	cron, err := database.ParseCronSchedule(schedule.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	next := cron.Next(now)
+	s.backupRuns[schedule.ID] = scheduledBackupRun{cron: schedule.Cron, next: next}
+	return next, !next.IsZero()
+}
+
+func (s *Service) forgetBackupRun(scheduleID string) {
+	s.backupScheduleMu.Lock()
+	delete(s.backupRuns, scheduleID)
+	s.backupScheduleMu.Unlock()
+}
+
+func (s *Service) startBackupScheduler(parent context.Context) {
+	if parent == nil || parent.Done() == nil || s.backupScheduleInterval <= 0 {
+		return
+	}
+	if s.backupSchedulerCancel != nil {
+		s.backupSchedulerCancel()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	s.backupSchedulerCancel = cancel
+	s.backupSchedulerDone = done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.backupScheduleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.runDueBackupSchedules(ctx, time.Now())
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *Service) stopBackupScheduler() {
+	if s.backupSchedulerCancel != nil {
+		s.backupSchedulerCancel()
+	}
+	if s.backupSchedulerDone != nil {
+		select {
+		case <-s.backupSchedulerDone:
+		case <-time.After(backupSchedulerShutdownTimeout):
+		}
+	}
+}
+
+// runDueBackupSchedules runs every enabled schedule whose next run is at or
+// before now, one at a time.
+func (s *Service) runDueBackupSchedules(ctx context.Context, now time.Time) {
+	envelope, err := s.readBackupCatalog()
+	if err != nil {
+		return
+	}
+	for _, schedule := range envelope.Schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		next, ok := s.nextBackupRun(schedule, now)
+		if !ok || next.After(now) {
+			continue
+		}
+		_, _ = s.runBackupSchedule(ctx, schedule, now)
+		s.forgetBackupRun(schedule.ID)
+		s.nextBackupRun(schedule, now)
+	}
+}
+
+var unsafeBackupNameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func scheduledBackupPath(
+	schedule database.BackupSchedule,
+	capabilities database.BackupCapabilities,
+	now time.Time,
+) string {
+	name := strings.Trim(unsafeBackupNameCharacters.ReplaceAllString(schedule.Name, "-"), "-.")
+	if name == "" {
+		name = "backup"
+	}
+	return filepath.Join(
+		schedule.Destination,
+		name+"-"+now.UTC().Format("20060102T150405Z")+capabilities.Extension,
+	)
+}
+
+// runBackupSchedule backs up the schedule's profile, records the outcome,
+// and applies retention. A failed backup is recorded and returned as an
+// entry; the error reports only catalog failures.
+func (s *Service) runBackupSchedule(
+	ctx context.Context,
+	schedule database.BackupSchedule,
+	now time.Time,
+) (database.BackupCatalogEntry, error) {
+	record := &backupRecord{database.BackupCatalogEntry{
+		ID:          uuid.NewString(),
+		ProfileID:   schedule.ProfileID,
+		ProfileName: schedule.Name,
+		ScheduleID:  schedule.ID,
+		Trigger:     database.BackupTriggerScheduled,
+		Format:      schedule.Format,
+		StartedAt:   time.Now().UTC(),
+	}}
+	backupErr := s.runScheduledBackup(ctx, schedule, now, record)
+	if backupErr != nil {
+		record.finish(backupFile{}, backupErr)
+	}
+	if _, err := s.recordBackup(record); err != nil {
+		return record.BackupCatalogEntry, err
+	}
+	if record.Outcome == database.BackupOutcomeSucceeded {
+		if err := s.applyBackupRetention(schedule, now); err != nil {
+			return record.BackupCatalogEntry, err
+		}
+	}
+	return record.BackupCatalogEntry, nil
+}
+
+func (s *Service) runScheduledBackup(
+	ctx context.Context,
+	schedule database.BackupSchedule,
+	now time.Time,
+	record *backupRecord,
+) error {
+	connectionID, disconnect, err := s.scheduledBackupConnection(schedule.ProfileID)
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+	connection, release, err := s.pinnedConnection(connectionID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	capabilityCtx, capabilityCancel := s.structuralChangeContext()
+	capabilities := s.backupCapabilitiesForConnection(capabilityCtx, connection)
+	capabilityCancel()
+	if schedule.Format == database.BackupFormatLogical &&
+		capabilities.Format != database.BackupFormatLogical {
+		capabilities = logicalBackupCapabilities(capabilities.Engine)
+	}
+	record.Engine = capabilities.Engine
+	record.Format = capabilities.Format
+	if !capabilities.Available {
+		return fmt.Errorf("%s", capabilities.Message)
+	}
+	if capabilities.ServerSideFiles {
+		return fmt.Errorf(
+			"native %s backups are written on the database server; schedule the logical format instead",
+			capabilities.Engine,
+		)
+	}
+	request := database.BackupRequest{
+		ConnectionID: connectionID,
+		Schema:       strings.TrimSpace(schedule.Schema),
+		Directory:    schedule.Directory,
+		SchemaOnly:   schedule.SchemaOnly,
+		DataOnly:     schedule.DataOnly,
+		Format:       schedule.Format,
+	}
+	written, err := s.writeBackupFile(
+		ctx,
+		connection,
+		request,
+		capabilities,
+		scheduledBackupPath(schedule, capabilities, now),
+	)
+	if err != nil {
+		return err
+	}
+	record.finish(written, nil)
+	return nil
+}
+
+// scheduledBackupConnection reuses an open connection to the profile. When
+// none is open it connects the profile for the duration of the backup
+// without making it the active connection.
+func (s *Service) scheduledBackupConnection(
+	profileID string,
+) (string, func(), error) {
+	s.mu.RLock()
+	for _, connection := range s.connections {
+		if connection.ProfileID == profileID {
+			connectionID := connection.ID
+			s.mu.RUnlock()
+			return connectionID, func() {}, nil
+		}
+	}
+	previousActiveID := s.activeID
+	s.mu.RUnlock()
+	connected := s.ConnectSavedConnection(profileID, "")
+	if len(connected.Errors) > 0 {
+		return "", nil, fmt.Errorf("%s: %s", connected.Errors[0].Title, connected.Errors[0].Detail)
+	}
+	if !connected.Data.Connected {
+		return "", nil, fmt.Errorf("the saved profile could not be connected")
+	}
+	connectionID := connected.Data.ConnectionID
+	s.mu.Lock()
+	if _, open := s.connections[previousActiveID]; open && s.activeID == connectionID {
+		s.activeID = previousActiveID
+	}
+	s.mu.Unlock()
+	return connectionID, func() { _ = s.DisconnectConnection(connectionID) }, nil
+}
+
+// applyBackupRetention deletes the files of the schedule's backups that its
+// policy no longer keeps and marks their entries as pruned.
+func (s *Service) applyBackupRetention(
+	schedule database.BackupSchedule,
+	now time.Time,
+) error {
+	var removeErr error
+	err := s.updateBackupCatalog(func(envelope *backupCatalogEnvelope) error {
+		owned := make([]database.BackupCatalogEntry, 0)
+		for _, entry := range envelope.Entries {
+			if entry.ScheduleID == schedule.ID {
+				owned = append(owned, entry)
+			}
+		}
+		expired := make(map[string]struct{})
+		for _, id := range schedule.Retention.Expired(owned, now) {
+			expired[id] = struct{}{}
+		}
+		for index := range envelope.Entries {
+			entry := &envelope.Entries[index]
+			if _, ok := expired[entry.ID]; !ok {
+				continue
+			}
+			if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+				removeErr = errors.Join(removeErr, err)
+				continue
+			}
+			pruned := now.UTC()
+			entry.PrunedAt = &pruned
+		}
+		return nil
+	})
+	return errors.Join(err, removeErr)
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rollingthunder/pkg/database"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func backupCatalogTestService(t *testing.T) (*Service, string) {
+	t.Helper()
+	service, _ := credentialTestService(t)
+	service.backupCatalog = &BackupCatalogStorage{
+		FilePath: filepath.Join(t.TempDir(), "backups.json"),
+	}
+	saved := service.SaveConnection(database.Config{
+		Name:   "Ledger",
+		Driver: "sqlite",
+		Db:     filepath.Join(t.TempDir(), "ledger.sqlite3"),
+	})
+	if len(saved.Errors) > 0 {
+		t.Fatalf("SaveConnection() errors = %+v", saved.Errors)
+	}
+	return service, saved.Data.ID
+}
+
+func TestScheduledBackupsAreCatalogedAndPruned(t *testing.T) {
+	service, profileID := backupCatalogTestService(t)
+	destination := t.TempDir()
+	saved := service.SaveBackupSchedule(database.BackupSchedule{
+		ProfileID:   profileID,
+		Cron:        "0 * * * *",
+		Enabled:     true,
+		Destination: destination,
+		Retention:   database.BackupRetention{KeepLast: 1},
+	})
+	if len(saved.Errors) > 0 || saved.Data.ID == "" || saved.Data.Name != "Ledger" {
+		t.Fatalf("SaveBackupSchedule() = %+v", saved)
+	}
+
+	now := time.Date(2026, time.May, 10, 11, 30, 0, 0, time.Local)
+	if next, ok := service.nextBackupRun(saved.Data, now); !ok || next.Hour() != 12 || next.Minute() != 0 {
+		t.Fatalf("next run = %s, %v", next, ok)
+	}
+	service.runDueBackupSchedules(context.Background(), now)
+	if catalog := service.GetBackupCatalog(profileID); len(catalog.Data) != 0 {
+		t.Fatalf("schedule ran before it was due: %+v", catalog.Data)
+	}
+	service.runDueBackupSchedules(context.Background(), now.Add(30*time.Minute))
+	second := service.RunBackupSchedule(saved.Data.ID)
+	if len(second.Errors) > 0 || second.Data.Outcome != database.BackupOutcomeSucceeded {
+		t.Fatalf("RunBackupSchedule() = %+v", second)
+	}
+
+	catalog := service.GetBackupCatalog(profileID)
+	if len(catalog.Errors) > 0 || len(catalog.Data) != 2 {
+		t.Fatalf("GetBackupCatalog() = %+v", catalog)
+	}
+	newest, oldest := catalog.Data[0], catalog.Data[1]
+	if newest.ID != second.Data.ID ||
+		newest.Trigger != database.BackupTriggerScheduled ||
+		newest.Engine != database.DriverSQLite ||
+		len(newest.SHA256) != 64 ||
+		newest.PrunedAt != nil {
+		t.Fatalf("newest entry = %+v", newest)
+	}
+	if oldest.PrunedAt == nil {
+		t.Fatalf("oldest entry was not pruned: %+v", oldest)
+	}
+	if _, err := os.Stat(oldest.Path); !os.IsNotExist(err) {
+		t.Fatalf("pruned backup still exists: %v", err)
+	}
+	if _, err := os.Stat(newest.Path); err != nil {
+		t.Fatalf("kept backup is missing: %v", err)
+	}
+	if len(service.connections) != 0 {
+		t.Fatalf("scheduled backup left %d connections open", len(service.connections))
+	}
+
+	schedules := service.GetBackupSchedules()
+	if len(schedules.Data) != 1 || schedules.Data[0].LastRun == nil ||
+		schedules.Data[0].LastRun.ID != newest.ID || schedules.Data[0].NextRunAt == nil {
+		t.Fatalf("GetBackupSchedules() = %+v", schedules)
+	}
+}
+
+func TestCatalogRestoreVerifiesRecordedChecksum(t *testing.T) {
+	service, profileID := backupCatalogTestService(t)
+	connected := service.ConnectSavedConnection(profileID, "")
+	if len(connected.Errors) > 0 {
+		t.Fatalf("ConnectSavedConnection() errors = %+v", connected.Errors)
+	}
+	connectionID := connected.Data.ConnectionID
+	t.Cleanup(func() { _ = service.DisconnectConnection(connectionID) })
+	execLogicalBackupSQL(t, service, connectionID, "CREATE TABLE entries (id INTEGER PRIMARY KEY)")
+
+	backupPath := filepath.Join(t.TempDir(), "ledger.sqlite3")
+	service.saveDialog = func(context.Context, wailsruntime.SaveDialogOptions) (string, error) {
+		return backupPath, nil
+	}
+	backup := service.BackupDatabase(database.BackupRequest{ConnectionID: connectionID})
+	if len(backup.Errors) > 0 || backup.Data.CatalogID == "" || backup.Data.Warning != "" {
+		t.Fatalf("BackupDatabase() = %+v", backup)
+	}
+	catalog := service.GetBackupCatalog("")
+	if len(catalog.Data) != 1 ||
+		catalog.Data[0].Trigger != database.BackupTriggerManual ||
+		catalog.Data[0].ProfileID != profileID {
+		t.Fatalf("catalog = %+v", catalog.Data)
+	}
+
+	selected := service.ChooseCatalogRestoreFile(connectionID, backup.Data.CatalogID)
+	if len(selected.Errors) > 0 {
+		t.Fatalf("ChooseCatalogRestoreFile() = %+v", selected)
+	}
+	restore := database.RestorePreviewRequest{ConnectionID: connectionID, Token: selected.Data.Token}
+	if preview := service.PreviewDatabaseRestore(restore); len(preview.Errors) > 0 {
+		t.Fatalf("PreviewDatabaseRestore() = %+v", preview)
+	}
+
+	// Same size and modification time, different content.
+	info, err := os.Stat(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(backupPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(backupPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	preview := service.PreviewDatabaseRestore(restore)
+	if len(preview.Errors) == 0 || !strings.Contains(preview.Errors[0].Detail, "checksum recorded in the backup catalog") {
+		t.Fatalf("tampered preview = %+v", preview)
+	}
+}
@@ -36,6 +36,9 @@ type restoreFileGrant struct {
 	path       string
 	connection string
 	modified   time.Time
+	// checksum is the SHA-256 recorded in the backup catalog, when the
+	// file was chosen from it.
+	checksum string
 }
 
 type maintenanceJob struct {
@@ -148,122 +151,37 @@ func lookupExecutable(
 	lookup executableLookup,
 	names ...string,
 ) (string, string) {
-	for _, name := range names {
-		path, err := lookup(name)
-		if err == nil && strings.TrimSpace(path) != "" {
-			return path, filepath.Base(path)
-		}
-	}
-	return "", ""
+	return database.LookupExecutable(database.ExecutableLookup(lookup), names...)
 }
 
+// backupCapabilitiesFor asks the registered driver for its backup workflow.
+// Engines without a registration, or whose probe is invalid, get none.
 func backupCapabilitiesFor(
 	lookup executableLookup,
 	engine string,
 ) database.BackupCapabilities {
-	switch engine {
-	case "sqlite":
-		return database.BackupCapabilities{
-			Available:     true,
-			Engine:        engine,
-			Format:        database.BackupFormatSQLiteNative,
-			Extension:     ".sqlite3",
-			BackupTool:    "Built in",
-			RestoreTool:   "Built in",
-			RestoreReady:  true,
-			BuiltIn:       true,
-			SupportsScope: false,
-		}
-	case "postgres":
-		_, backupTool := lookupExecutable(lookup, "pg_dump")
-		_, restoreTool := lookupExecutable(lookup, "pg_restore")
-		available := backupTool != ""
-		message := ""
-		if !available {
-			message = "Install PostgreSQL client tools so pg_dump is available in PATH."
-		} else if restoreTool == "" {
-			message = "Backups are available, but pg_restore is required to restore them."
-		}
-		return database.BackupCapabilities{
-			Available:     available,
-			Engine:        engine,
-			Format:        database.BackupFormatPostgresCustom,
-			Extension:     ".dump",
-			BackupTool:    backupTool,
-			RestoreTool:   restoreTool,
-			RestoreReady:  restoreTool != "",
-			Message:       message,
-			SupportsScope: true,
-		}
-	case "mysql":
-		_, backupTool := lookupExecutable(
-			lookup,
-			"mysqldump",
-			"mariadb-dump",
-		)
-		_, restoreTool := lookupExecutable(lookup, "mysql", "mariadb")
-		available := backupTool != ""
-		message := ""
-		if !available {
-			message = "Install MySQL or MariaDB client tools so mysqldump is available in PATH."
-		} else if restoreTool == "" {
-			message = "Backups are available, but the mysql or mariadb client is required to restore them."
-		}
-		return database.BackupCapabilities{
-			Available:     available,
-			Engine:        engine,
-			Format:        database.BackupFormatMySQLSQL,
-			Extension:     ".sql",
-			BackupTool:    backupTool,
-			RestoreTool:   restoreTool,
-			RestoreReady:  restoreTool != "",
-			Message:       message,
-			SupportsScope: true,
-		}
-	case "sqlserver":
-		return database.BackupCapabilities{
-			Available:       true,
-			Engine:          engine,
-			Format:          database.BackupFormatSQLServerNative,
-			Extension:       ".bak",
-			BackupTool:      "BACKUP DATABASE",
-			RestoreTool:     "RESTORE DATABASE",
-			RestoreReady:    true,
-			BuiltIn:         true,
-			SupportsScope:   false,
-			ServerSideFiles: true,
-			Directories:     []database.BackupDirectory{},
-			Message: "Native .bak files stay on the SQL Server host. " +
-				"The configured database account and SQL Server service account " +
-				"must be allowed to use the selected server path.",
-		}
-	case "oracle":
-		return database.BackupCapabilities{
-			Available:         true,
-			Engine:            engine,
-			Format:            database.BackupFormatOracleDataPump,
-			Extension:         ".dmp",
-			BackupTool:        "DBMS_DATAPUMP",
-			RestoreTool:       "DBMS_DATAPUMP",
-			RestoreReady:      true,
-			BuiltIn:           true,
-			SupportsScope:     false,
-			RequiresDirectory: true,
-			Directories:       []database.BackupDirectory{},
-			Message: "Oracle Data Pump stages encrypted-at-rest responsibility " +
-				"with the configured database server directory.",
-		}
-	default:
+	probe, err := database.ProbeDriver(engine, database.ExecutableLookup(lookup))
+	if err != nil || probe.Backup.Format == "" {
 		return database.BackupCapabilities{
 			Engine:  engine,
 			Message: "This database engine does not expose a backup workflow.",
 		}
 	}
+	return probe.Backup
 }
 
 func (s *Service) backupCapabilitiesForConnection(
 	ctx context.Context,
 	connection *Connection,
+) database.BackupCapabilities {
+	return withLogicalBackup(
+		s.nativeBackupCapabilitiesForConnection(ctx, connection),
+	)
+}
+
+func (s *Service) nativeBackupCapabilitiesForConnection(
+	ctx context.Context,
+	connection *Connection,
 ) database.BackupCapabilities {
 	capabilities := backupCapabilitiesFor(
 		s.lookPath,
@@ -349,6 +267,8 @@ func backupDialogConfiguration(
 		displayName = "MySQL / MariaDB backups (*.sql)"
 	case database.BackupFormatOracleDataPump:
 		displayName = "Oracle Data Pump backups (*.dmp)"
+	case database.BackupFormatLogical:
+		displayName = "Rolling Thunder logical backups (*.rtbackup)"
 	case database.BackupFormatSQLServerNative:
 		return wailsruntime.SaveDialogOptions{}, fmt.Errorf(
 			"SQL Server native backups use a path on the database server",
@@ -697,6 +617,14 @@ func (s *Service) createBackup(
 			return backupErr
 		}
 		return closeErr
+	case database.BackupFormatLogical:
+		return createLogicalBackup(
+			ctx,
+			connection.Driver,
+			config.Db,
+			tempPath,
+			request,
+		)
 	default:
 		return fmt.Errorf("unsupported backup format %q", capabilities.Format)
 	}
@@ -725,6 +653,10 @@ func (s *Service) BackupDatabase(
 		connection,
 	)
 	capabilityCancel()
+	if request.Format == database.BackupFormatLogical &&
+		capabilities.Format != database.BackupFormatLogical {
+		capabilities = logicalBackupCapabilities(capabilities.Engine)
+	}
 	if !capabilities.Available {
 		return serviceErrorWithCode[database.BackupResult](
 			http.StatusNotImplemented,
@@ -759,10 +691,14 @@ func (s *Service) BackupDatabase(
 			return serviceError[database.BackupResult](startErr.Error())
 		}
 		defer s.finishMaintenanceJob(job)
+		record := s.newBackupCatalogEntry(connection, capabilities, database.BackupTriggerManual)
+		record.ServerSide = true
 		metadata, backupErr := driver.BackupDatabaseToServer(ctx, request)
 		if backupErr != nil {
 			if errors.Is(backupErr, context.Canceled) ||
 				job.cancelled.Load() {
+				record.finish(backupFile{}, context.Canceled)
+				_, _ = s.recordBackup(record)
 				return response.BaseResponse[database.BackupResult]{
 					Data: database.BackupResult{
 						Format:    capabilities.Format,
@@ -770,6 +706,8 @@ func (s *Service) BackupDatabase(
 					},
 				}
 			}
+			record.finish(backupFile{}, backupErr)
+			_, _ = s.recordBackup(record)
 			return serviceErrorWithCode[database.BackupResult](
 				http.StatusBadRequest,
 				errorCodeBackupFailed,
@@ -778,13 +716,14 @@ func (s *Service) BackupDatabase(
 				"Verify BACKUP DATABASE permission and SQL Server service-account access to the server path.",
 			)
 		}
-		return response.BaseResponse[database.BackupResult]{
-			Data: database.BackupResult{
-				Path:   metadata.Path,
-				Bytes:  metadata.Bytes,
-				Format: capabilities.Format,
-			},
+		record.finish(backupFile{path: metadata.Path, bytes: metadata.Bytes}, nil)
+		result := database.BackupResult{
+			Path:   metadata.Path,
+			Bytes:  metadata.Bytes,
+			Format: capabilities.Format,
 		}
+		s.catalogBackupResult(record, &result)
+		return response.BaseResponse[database.BackupResult]{Data: result}
 	}
 	dialog, err := backupDialogConfiguration(
 		capabilities,
@@ -827,64 +766,126 @@ func (s *Service) BackupDatabase(
 	}
 	defer s.finishMaintenanceJob(job)
 
+	record := s.newBackupCatalogEntry(connection, capabilities, database.BackupTriggerManual)
+	written, err := s.writeBackupFile(ctx, connection, request, capabilities, targetPath)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || job.cancelled.Load() {
+			record.finish(backupFile{}, context.Canceled)
+			_, _ = s.recordBackup(record)
+			return response.BaseResponse[database.BackupResult]{
+				Data: database.BackupResult{
+					Format:    capabilities.Format,
+					Cancelled: true,
+				},
+			}
+		}
+		record.finish(backupFile{}, err)
+		_, _ = s.recordBackup(record)
+		var toolErr backupToolError
+		switch {
+		case errors.As(err, &toolErr):
+			return serviceErrorWithCode[database.BackupResult](
+				http.StatusBadRequest,
+				errorCodeBackupFailed,
+				"Database backup failed",
+				toolErr.err.Error(),
+				"The destination was not replaced. Check database client tooling and permissions.",
+			)
+		case errors.Is(err, errBackupEmpty):
+			return serviceErrorWithCode[database.BackupResult](
+				http.StatusInternalServerError,
+				errorCodeBackupFailed,
+				"Database backup is empty",
+				"The backup tool produced a zero-byte file.",
+				"The destination was not replaced. Check the database client output.",
+			)
+		default:
+			return serviceError[database.BackupResult](err.Error())
+		}
+	}
+	record.finish(written, nil)
+	result := database.BackupResult{
+		Path:   written.path,
+		Bytes:  written.bytes,
+		Format: capabilities.Format,
+	}
+	s.catalogBackupResult(record, &result)
+	return response.BaseResponse[database.BackupResult]{Data: result}
+}
+
+// backupToolError marks a failure reported by the backup tool or driver,
+// as opposed to a local file error around it.
+type backupToolError struct {
+	err error
+}
+
+func (err backupToolError) Error() string { return err.err.Error() }
+
+func (err backupToolError) Unwrap() error { return err.err }
+
+var errBackupEmpty = errors.New("the backup tool produced a zero-byte file")
+
+type backupFile struct {
+	path   string
+	bytes  int64
+	sha256 string
+}
+
+// writeBackupFile creates a backup next to targetPath and replaces
+// targetPath only after the backup completed and was hashed for the
+// catalog. A failed backup never touches an existing file.
+func (s *Service) writeBackupFile(
+	ctx context.Context,
+	connection *Connection,
+	request database.BackupRequest,
+	capabilities database.BackupCapabilities,
+	targetPath string,
+) (backupFile, error) {
 	tempFile, err := os.CreateTemp(
 		filepath.Dir(targetPath),
 		"."+application.Identifier+"-database-backup-*",
 	)
 	if err != nil {
-		return serviceError[database.BackupResult](err.Error())
+		return backupFile{}, err
 	}
 	tempPath := tempFile.Name()
 	if err := tempFile.Close(); err != nil {
 		_ = os.Remove(tempPath)
-		return serviceError[database.BackupResult](err.Error())
+		return backupFile{}, err
 	}
 	defer os.Remove(tempPath)
 
-	err = s.createBackup(ctx, connection, tempPath, request, capabilities)
-	if err != nil {
-		if errors.Is(err, context.Canceled) || job.cancelled.Load() {
-			return response.BaseResponse[database.BackupResult]{
-				Data: database.BackupResult{
-					Format:    capabilities.Format,
-					Cancelled: true,
-				},
-			}
-		}
-		return serviceErrorWithCode[database.BackupResult](
-			http.StatusBadRequest,
-			errorCodeBackupFailed,
-			"Database backup failed",
-			err.Error(),
-			"The destination was not replaced. Check database client tooling and permissions.",
-		)
+	if err := s.createBackup(ctx, connection, tempPath, request, capabilities); err != nil {
+		return backupFile{}, backupToolError{err: err}
 	}
 	info, err := os.Stat(tempPath)
 	if err != nil {
-		return serviceError[database.BackupResult](err.Error())
+		return backupFile{}, err
 	}
 	if info.Size() == 0 {
-		return serviceErrorWithCode[database.BackupResult](
-			http.StatusInternalServerError,
-			errorCodeBackupFailed,
-			"Database backup is empty",
-			"The backup tool produced a zero-byte file.",
-			"The destination was not replaced. Check the database client output.",
-		)
+		return backupFile{}, errBackupEmpty
 	}
-	if err := replaceExportFile(tempPath, targetPath); err != nil {
-		return serviceError[database.BackupResult](err.Error())
+	digest, err := hashFile(ctx, tempPath)
+	if err != nil {
+		return backupFile{}, err
 	}
-	return response.BaseResponse[database.BackupResult]{
-		Data: database.BackupResult{
-			Path:   targetPath,
-			Bytes:  info.Size(),
-			Format: capabilities.Format,
-		},
+	if err := replaceExportFile(tempPath, targetPath); err != nil {
+		return backupFile{}, err
 	}
+	return backupFile{path: targetPath, bytes: info.Size(), sha256: digest}, nil
 }
 
 func restoreFileFilters(engine string) []wailsruntime.FileFilter {
+	return append(
+		nativeRestoreFileFilters(engine),
+		wailsruntime.FileFilter{
+			DisplayName: "Rolling Thunder logical backups (*.rtbackup)",
+			Pattern:     "*" + database.LogicalBackupExtension,
+		},
+	)
+}
+
+func nativeRestoreFileFilters(engine string) []wailsruntime.FileFilter {
 	switch engine {
 	case "sqlite":
 		return []wailsruntime.FileFilter{{
@@ -913,6 +914,9 @@ func restoreFileFilters(engine string) []wailsruntime.FileFilter {
 
 func restoreFormatFor(engine string, path string) (database.BackupFormat, error) {
 	extension := strings.ToLower(filepath.Ext(path))
+	if extension == database.LogicalBackupExtension {
+		return database.BackupFormatLogical, nil
+	}
 	switch engine {
 	case "sqlite":
 		switch extension {
@@ -945,15 +949,6 @@ func (s *Service) ChooseRestoreFile(
 	}
 	engine := connection.Driver.Capabilities().Engine
 	release()
-	if engine == database.DriverSQLServer {
-		return serviceErrorWithCode[database.RestoreFileSelection](
-			http.StatusBadRequest,
-			errorCodeInvalidRequest,
-			"SQL Server uses server-side backup paths",
-			"Native .bak files are read by SQL Server and cannot be selected from the desktop filesystem.",
-			"Enter an absolute path visible to the SQL Server service in Database tools.",
-		)
-	}
 	parent := s.ctx
 	if parent == nil {
 		parent = context.Background()
@@ -970,6 +965,15 @@ func (s *Service) ChooseRestoreFile(
 	if strings.TrimSpace(path) == "" {
 		return response.BaseResponse[database.RestoreFileSelection]{}
 	}
+	return s.grantRestoreFile(connectionID, engine, path, "")
+}
+
+func (s *Service) grantRestoreFile(
+	connectionID string,
+	engine string,
+	path string,
+	checksum string,
+) response.BaseResponse[database.RestoreFileSelection] {
 	absolute, err := filepath.Abs(path)
 	if err != nil {
 		return serviceError[database.RestoreFileSelection](err.Error())
@@ -991,6 +995,17 @@ func (s *Service) ChooseRestoreFile(
 		)
 	}
 	format, err := restoreFormatFor(engine, absolute)
+	if err != nil && engine == database.DriverSQLServer {
+		// Only logical backups are chosen on the desktop; native .bak
+		// files are read by the SQL Server service.
+		return serviceErrorWithCode[database.RestoreFileSelection](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"SQL Server uses server-side backup paths",
+			"Native .bak files are read by SQL Server and cannot be selected from the desktop filesystem.",
+			"Enter an absolute path visible to the SQL Server service in Database tools.",
+		)
+	}
 	if err != nil {
 		return serviceErrorWithCode[database.RestoreFileSelection](
 			http.StatusBadRequest,
@@ -1012,6 +1027,7 @@ func (s *Service) ChooseRestoreFile(
 		path:       filepath.Clean(absolute),
 		connection: connectionID,
 		modified:   info.ModTime(),
+		checksum:   checksum,
 	}
 	s.restoreFileMu.Unlock()
 	return response.BaseResponse[database.RestoreFileSelection]{
@@ -1038,7 +1054,11 @@ func hashRestoreFile(
 	ctx context.Context,
 	grant restoreFileGrant,
 ) (string, error) {
-	file, err := os.Open(grant.path)
+	return hashFile(ctx, grant.path)
+}
+
+func hashFile(ctx context.Context, path string) (string, error) {
+	file, err := os.Open(path)
 	if err != nil {
 		return "", err
 	}
@@ -1093,6 +1113,10 @@ func validateRestoreHeader(grant restoreFileGrant) error {
 	case database.BackupFormatOracleDataPump:
 		// Oracle Data Pump headers vary by database release. The native import
 		// API performs authoritative format and compatibility validation.
+	case database.BackupFormatLogical:
+		if !bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+			return fmt.Errorf("file is not a Rolling Thunder logical backup")
+		}
 	}
 	return nil
 }
@@ -1165,6 +1189,16 @@ func (s *Service) buildRestorePreview(
 	databaseName := connection.Config.Db
 	transactional := engine == "sqlite"
 	capabilities := s.backupCapabilitiesForConnection(ctx, connection)
+	logical := false
+	if strings.TrimSpace(request.ServerPath) == "" {
+		if grant, grantErr := s.restoreFile(request.ConnectionID, request.Token); grantErr == nil {
+			logical = grant.selection.Format == database.BackupFormatLogical
+		}
+	}
+	if logical {
+		capabilities = logicalBackupCapabilities(engine)
+		transactional = logicalRestoreTransactional(connection.Driver)
+	}
 	if capabilities.ServerSideFiles {
 		if !capabilities.RestoreReady {
 			release()
@@ -1277,6 +1311,42 @@ func (s *Service) buildRestorePreview(
 	if err != nil {
 		return database.RestorePreview{}, restoreFileGrant{}, err
 	}
+	if grant.checksum != "" && !strings.EqualFold(fileHash, grant.checksum) {
+		return database.RestorePreview{}, restoreFileGrant{}, fmt.Errorf(
+			"the backup file no longer matches the SHA-256 checksum recorded in the backup catalog",
+		)
+	}
+	if logical {
+		manifest, tables, inspectErr := inspectLogicalBackup(
+			ctx,
+			grant.path,
+			engine,
+			request.Schema,
+		)
+		if inspectErr != nil {
+			return database.RestorePreview{}, restoreFileGrant{}, inspectErr
+		}
+		return database.RestorePreview{
+			ConnectionID:  request.ConnectionID,
+			Database:      databaseName,
+			Engine:        engine,
+			File:          grant.selection.Name,
+			Size:          grant.selection.Size,
+			Format:        grant.selection.Format,
+			Schema:        request.Schema,
+			Destructive:   true,
+			Transactional: transactional,
+			Warnings:      logicalRestoreWarnings(manifest, tables, transactional),
+			Fingerprint: restoreFingerprint(
+				request.ConnectionID,
+				engine,
+				databaseName,
+				request,
+				grant,
+				fileHash,
+			),
+		}, grant, nil
+	}
 	warnings := []string{
 		"Restore replaces existing objects and data in the selected database.",
 		"Any explicit Rolling Thunder transaction on the target connection is rolled back before restore starts.",
@@ -1475,6 +1545,8 @@ func (s *Service) runRestore(
 		}
 		defer source.Close()
 		return driver.RestoreDatabaseFromReader(ctx, source, request)
+	case database.BackupFormatLogical:
+		return restoreLogicalBackup(ctx, connection.Driver, request, grant.path)
 	default:
 		return fmt.Errorf("unsupported restore format %q", grant.selection.Format)
 	}
@@ -1552,6 +1624,9 @@ func (s *Service) ApplyDatabaseRestore(
 	}
 	defer release()
 	s.rollbackTransactionsForConnection(request.Restore.ConnectionID)
+	s.closeQueryCursorsForConnection(request.Restore.ConnectionID)
+	s.forgetKeysetPages(request.Restore.ConnectionID)
+	startedAt := time.Now()
 	if err := s.runRestore(ctx, connection, request.Restore, grant); err != nil {
 		if errors.Is(err, context.Canceled) || job.cancelled.Load() {
 			return response.BaseResponse[database.RestoreResult]{
@@ -1574,6 +1649,17 @@ func (s *Service) ApplyDatabaseRestore(
 		delete(s.restoreFiles, request.Restore.Token)
 		s.restoreFileMu.Unlock()
 	}
+	source := preview.File
+	if strings.TrimSpace(request.Restore.ServerPath) != "" {
+		source = request.Restore.ServerPath
+	}
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationRestore,
+		StartedAt:    startedAt,
+		ConnectionID: request.Restore.ConnectionID,
+		Target:       auditTarget(preview.Database, preview.Schema),
+		Summary:      fmt.Sprintf("Restored a %s backup from %s", preview.Format, source),
+	})
 	return response.BaseResponse[database.RestoreResult]{
 		Data: database.RestoreResult{
 			Restored:    true,
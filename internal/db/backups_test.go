@@ -334,3 +334,29 @@ func TestPostgresPasswordFileRejectsLineBreaks(t *testing.T) {
 		t.Fatal("expected password-file line break validation")
 	}
 }
+
+func TestAvailableDriversComeFromRegistry(t *testing.T) {
+	service := NewService()
+	service.lookPath = executableFixture("pg_dump", "pg_restore")
+	drivers := service.GetAvailableDrivers().Data
+	byName := make(map[string]database.DriverProbe, len(drivers))
+	for _, driver := range drivers {
+		byName[driver.Driver] = driver
+	}
+	for _, name := range []string{
+		database.DriverPostgres,
+		database.DriverMySQL,
+		database.DriverMariaDB,
+		database.DriverSQLite,
+		database.DriverOracle,
+		database.DriverSQLServer,
+	} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("driver %q missing from %+v", name, drivers)
+		}
+	}
+	if !byName[database.DriverPostgres].Backup.RestoreReady ||
+		!byName[database.DriverSQLite].Capabilities.FileDatabase {
+		t.Fatalf("probes = %+v", byName)
+	}
+}
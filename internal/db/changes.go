@@ -1,7 +1,9 @@
 package db
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/response"
@@ -56,6 +58,7 @@ func (s *Service) ApplyTableChanges(
 		)
 	}
 
+	startedAt := time.Now()
 	result, err := changeDriver.ApplyTableChanges(s.ctx, changes)
 	if err != nil {
 		return serviceErrorWithCode[database.TableChangeResult](
@@ -66,5 +69,18 @@ func (s *Service) ApplyTableChanges(
 			"The complete change set was rolled back. Review the highlighted rows and try again.",
 		)
 	}
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationTableChanges,
+		StartedAt:    startedAt,
+		ConnectionID: connectionID,
+		Target:       auditTarget(changes.Table.Schema, changes.Table.Name),
+		Summary: fmt.Sprintf(
+			"Applied row changes: %d inserted, %d updated, %d deleted",
+			result.Inserted,
+			result.Updated,
+			result.Deleted,
+		),
+		RowsAffected: auditRowCount(result.Inserted + result.Updated + result.Deleted),
+	})
 	return response.BaseResponse[database.TableChangeResult]{Data: result}
 }
@@ -26,6 +26,12 @@ type ConnectResponse struct {
 	ConnectionID string `json:"connectionId,omitempty"`
 }
 
+type connectionIDFactory func(ConnectRequest) string
+
+func randomConnectionID(ConnectRequest) string {
+	return uuid.New().String()
+}
+
 type connectionAttempt struct {
 	id        string
 	ctx       context.Context
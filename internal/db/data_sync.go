@@ -282,7 +282,7 @@ func readDataSyncRows(
 	if len(result.Rows) > limit {
 		result.Rows = result.Rows[:limit]
 	}
-	return result.Rows, truncated, nil
+	return result.RowMaps(), truncated, nil
 }
 
 func indexDataSyncRows(
@@ -666,6 +666,7 @@ func (s *Service) ApplyDataSync(
 			"Use a connected target whose driver advertises atomic table changes.",
 		)
 	}
+	startedAt := time.Now()
 	result, err := changeDriver.ApplyTableChanges(ctx, changes)
 	if err != nil {
 		return serviceErrorWithCode[database.DataSyncResult](
@@ -676,6 +677,20 @@ func (s *Service) ApplyDataSync(
 			"The complete change set was rolled back. Refresh both tables before retrying.",
 		)
 	}
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationDataSync,
+		StartedAt:    startedAt,
+		ConnectionID: request.Sync.TargetConnectionID,
+		Target:       auditTarget(request.Sync.TargetSchema, request.Sync.TargetTable),
+		Summary: fmt.Sprintf(
+			"Synchronized rows from %s: %d inserted, %d updated, %d deleted",
+			auditTarget(request.Sync.SourceSchema, request.Sync.SourceTable),
+			result.Inserted,
+			result.Updated,
+			result.Deleted,
+		),
+		RowsAffected: auditRowCount(result.Inserted + result.Updated + result.Deleted),
+	})
 	return response.BaseResponse[database.DataSyncResult]{
 		Data: database.DataSyncResult{
 			Applied:     true,
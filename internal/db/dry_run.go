@@ -0,0 +1,319 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+// DryRunQuery runs a batch inside a fresh transaction and rolls it back,
+// reporting how many rows each statement changed. Unfiltered mutations do
+// not need confirmation here; they are listed in the result instead.
+func (s *Service) DryRunQuery(
+	request database.DryRunRequest,
+) response.BaseResponse[database.DryRunResult] {
+	request.Query = strings.TrimSpace(request.Query)
+	if request.Query == "" {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Query is empty",
+			"Enter a SQL statement before running it.",
+			"Select a statement or place the cursor inside the statement to dry-run.",
+		)
+	}
+	if request.SampleRows < 0 || request.SampleRows > database.MaxDryRunSampleRows {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid sample size",
+			fmt.Sprintf(
+				"A dry run samples between 0 and %d changed rows per statement.",
+				database.MaxDryRunSampleRows,
+			),
+			"Choose a smaller sample.",
+		)
+	}
+	if control := database.FindTransactionControl(request.Query); control != "" {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusConflict,
+			errorCodeTransactionControl,
+			"Transaction control cannot be dry-run",
+			fmt.Sprintf(
+				"%s would end the transaction the dry run rolls back.",
+				control,
+			),
+			"Remove transaction control statements from the batch.",
+		)
+	}
+	statements, err := database.SplitSQLStatements(request.Query)
+	if err != nil {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid query batch",
+			err.Error(),
+			"Run fewer statements at once or select one statement in the editor.",
+		)
+	}
+	if len(statements) == 0 {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Query is empty",
+			"The selected SQL contains no executable statement.",
+			"Select a statement or place the cursor inside one before running it.",
+		)
+	}
+
+	var (
+		driver  database.Driver
+		release func()
+	)
+	if database.FindWriteStatement(request.Query) != "" {
+		driver, release, err = s.writeDriverFor(request.ConnectionID)
+	} else {
+		driver, release, err = s.driverFor(request.ConnectionID)
+	}
+	if errors.Is(err, errConnectionReadOnly) {
+		return readOnlyConnectionError[database.DryRunResult]()
+	}
+	if err != nil {
+		return serviceError[database.DryRunResult](err.Error())
+	}
+	defer release()
+
+	capabilities := driver.Capabilities()
+	transactional, ok := driver.(database.TransactionalDriver)
+	if !ok || !capabilities.Transactions {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusNotImplemented,
+			errorCodeTransactionUnsupported,
+			"Dry runs are not supported",
+			"The active database driver cannot roll back a transaction.",
+			"Review the statements carefully before running them.",
+		)
+	}
+	if keyword := database.FindDDLStatement(request.Query); keyword != "" &&
+		!capabilities.TransactionalDDL {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusConflict,
+			errorCodeDryRunDDL,
+			"DDL cannot be dry-run",
+			fmt.Sprintf(
+				"%s commits implicitly on %s, so rolling back would not undo it.",
+				keyword,
+				capabilities.DisplayName,
+			),
+			"Dry-run the data changes on their own, or try the DDL on a copy of the database.",
+		)
+	}
+
+	ctx, attempt, err := s.startQueryAttempt(request.AttemptID)
+	if err != nil {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid query request",
+			err.Error(),
+			"Create a unique query attempt and try again.",
+		)
+	}
+	defer s.finishQueryAttempt(attempt)
+
+	if storage, ok := driver.(database.NonTransactionalTableDriver); ok {
+		tables, err := storage.NonTransactionalTables(ctx, statements)
+		if err != nil {
+			return queryFailure[database.DryRunResult](err, false)
+		}
+		if len(tables) > 0 {
+			return serviceErrorWithCode[database.DryRunResult](
+				http.StatusConflict,
+				errorCodeDryRunStorage,
+				"Tables cannot be rolled back",
+				fmt.Sprintf(
+					"%s use storage that ignores ROLLBACK, so a dry run would change them for real.",
+					strings.Join(tables, ", "),
+				),
+				"Dry-run against a copy of the tables, or convert them to a transactional engine such as InnoDB.",
+			)
+		}
+	}
+
+	transaction, err := transactional.BeginTransaction(ctx)
+	if err != nil {
+		return queryFailure[database.DryRunResult](err, false)
+	}
+	var returning database.ReturningDriver
+	if request.SampleRows > 0 && capabilities.ReturningRows {
+		returning, _ = driver.(database.ReturningDriver)
+	}
+	result, runErr := runDryRun(ctx, transaction, driver, returning, request, statements)
+	rollbackErr := transaction.Rollback()
+
+	if runErr != nil {
+		if attempt.cancelled.Load() {
+			runErr = context.Canceled
+		}
+		return queryFailure[database.DryRunResult](runErr, false)
+	}
+	if rollbackErr != nil {
+		return serviceErrorWithCode[database.DryRunResult](
+			http.StatusInternalServerError,
+			errorCodeTransactionFailed,
+			"Dry run could not be rolled back",
+			rollbackErr.Error(),
+			"Nothing was committed. Reconnect the database so the server discards the open transaction.",
+		)
+	}
+	result.RolledBack = true
+	result.UnfilteredMutations = database.AnalyzeQuerySafety(request.Query).UnfilteredMutations
+	return response.BaseResponse[database.DryRunResult]{Data: result}
+}
+
+func runDryRun(
+	ctx context.Context,
+	transaction database.Transaction,
+	driver database.Driver,
+	returning database.ReturningDriver,
+	request database.DryRunRequest,
+	statements []string,
+) (database.DryRunResult, error) {
+	result := database.DryRunResult{
+		Statements: make([]database.DryRunStatement, 0, len(statements)),
+	}
+	for index, statement := range statements {
+		boundQuery, args, err := database.BindQueryVariables(
+			statement,
+			driver,
+			request.Variables,
+		)
+		if err != nil {
+			return database.DryRunResult{}, fmt.Errorf("statement %d: %w", index+1, err)
+		}
+		sampled, sample := database.ReturningQuery{}, false
+		if returning != nil {
+			sampled, sample = returning.ReturningQuery(boundQuery)
+		}
+		options := database.QueryOptions{MaxRows: 1, Args: args}
+		if sample {
+			boundQuery = sampled.Query
+			options.MaxRows = request.SampleRows
+		}
+
+		startedAt := time.Now()
+		executed, err := transaction.ExecuteQuery(ctx, boundQuery, options)
+		if err == nil && sample {
+			err = countSampledRows(ctx, transaction, sampled, &executed)
+		}
+		if err != nil {
+			return database.DryRunResult{}, fmt.Errorf(
+				"statement %d of %d failed: %w",
+				index+1,
+				len(statements),
+				err,
+			)
+		}
+		report := database.DryRunStatement{
+			Index:        index,
+			Statement:    statement,
+			RowsAffected: executed.RowsAffected,
+			CommandTag:   executed.CommandTag,
+			ElapsedMS:    time.Since(startedAt).Milliseconds(),
+			Messages:     executed.Messages,
+		}
+		if sample {
+			splitSampledRows(&report, sampled, executed)
+		}
+		result.Statements = append(result.Statements, report)
+	}
+	return result, nil
+}
+
+// countSampledRows fills in the affected-row count of a statement whose
+// changed rows were read back. When the sample holds every row its length
+// is the count; otherwise the driver's count query is asked.
+func countSampledRows(
+	ctx context.Context,
+	transaction database.Transaction,
+	sampled database.ReturningQuery,
+	executed *database.QueryResult,
+) error {
+	if executed.RowsAffected != nil {
+		return nil
+	}
+	if !executed.Truncated {
+		count := int64(len(executed.Rows))
+		executed.RowsAffected = &count
+		return nil
+	}
+	if sampled.CountQuery == "" {
+		return nil
+	}
+	counted, err := transaction.ExecuteQuery(ctx, sampled.CountQuery, database.QueryOptions{MaxRows: 1})
+	if err != nil {
+		return fmt.Errorf("count changed rows: %w", err)
+	}
+	if len(counted.Rows) == 1 && len(counted.Rows[0]) == 1 {
+		if count, ok := dryRunCount(counted.Rows[0][0]); ok {
+			executed.RowsAffected = &count
+		}
+	}
+	return nil
+}
+
+func splitSampledRows(
+	report *database.DryRunStatement,
+	sampled database.ReturningQuery,
+	executed database.QueryResult,
+) {
+	report.SampleTruncated = executed.Truncated
+	report.Columns = executed.Columns
+	rows := executed.Rows
+	if rows == nil {
+		rows = make([][]interface{}, 0)
+	}
+	switch {
+	case sampled.Before && sampled.After:
+		half := len(executed.Columns) / 2
+		report.Columns = executed.Columns[:half]
+		report.Before = make([][]interface{}, len(rows))
+		report.After = make([][]interface{}, len(rows))
+		for index, row := range rows {
+			report.Before[index] = row[:half]
+			report.After[index] = row[half:]
+		}
+	case sampled.Before:
+		report.Before = rows
+	default:
+		report.After = rows
+	}
+}
+
+func dryRunCount(value interface{}) (int64, bool) {
+	switch count := value.(type) {
+	case int64:
+		return count, true
+	case int32:
+		return int64(count), true
+	case int:
+		return int64(count), true
+	case float64:
+		return int64(count), true
+	case []byte:
+		var parsed int64
+		_, err := fmt.Sscan(string(count), &parsed)
+		return parsed, err == nil
+	case string:
+		var parsed int64
+		_, err := fmt.Sscan(count, &parsed)
+		return parsed, err == nil
+	}
+	return 0, false
+}
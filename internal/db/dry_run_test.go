@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rollingthunder/pkg/database"
+)
+
+func TestSQLiteDryRunReportsChangesAndRollsBack(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	connected := service.Connect(ConnectRequest{
+		Driver: "sqlite",
+		Config: database.Config{
+			Name:   "dry-run-test",
+			Driver: "sqlite",
+			Db:     filepath.Join(t.TempDir(), "dry-run.sqlite3"),
+		},
+	})
+	if len(connected.Errors) > 0 {
+		t.Fatalf("Connect() errors = %+v", connected.Errors)
+	}
+	connectionID := connected.Data.ConnectionID
+	t.Cleanup(func() {
+		_ = service.DisconnectConnection(connectionID)
+	})
+	seeded := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query: `
+			CREATE TABLE main.events (id INTEGER PRIMARY KEY, name TEXT);
+			INSERT INTO main.events (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e');
+		`,
+	})
+	if len(seeded.Errors) > 0 {
+		t.Fatalf("seed errors = %+v", seeded.Errors)
+	}
+
+	result := service.DryRunQuery(database.DryRunRequest{
+		ConnectionID: connectionID,
+		Query: `
+			UPDATE main.events SET name = 'renamed' WHERE id > {{after}};
+			DELETE FROM main.events;
+			CREATE TABLE main.scratch (id INTEGER);
+		`,
+		Variables:  []database.QueryVariable{{Name: "after", Value: 1, Type: "number"}},
+		SampleRows: 2,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("DryRunQuery() errors = %+v", result.Errors)
+	}
+	if !result.Data.RolledBack || len(result.Data.Statements) != 3 {
+		t.Fatalf("DryRunQuery() = %+v", result.Data)
+	}
+	if len(result.Data.UnfilteredMutations) != 1 || result.Data.UnfilteredMutations[0] != "DELETE" {
+		t.Fatalf("unfiltered mutations = %v", result.Data.UnfilteredMutations)
+	}
+	updated := result.Data.Statements[0]
+	if updated.RowsAffected == nil || *updated.RowsAffected != 4 {
+		t.Fatalf("update rows affected = %v, want 4", updated.RowsAffected)
+	}
+	if len(updated.After) != 2 || !updated.SampleTruncated || updated.Before != nil ||
+		len(updated.Columns) != 2 || updated.After[0][1] != "renamed" {
+		t.Fatalf("update sample = %+v", updated)
+	}
+	deleted := result.Data.Statements[1]
+	if deleted.RowsAffected == nil || *deleted.RowsAffected != 5 || len(deleted.Before) != 2 {
+		t.Fatalf("delete report = %+v", deleted)
+	}
+
+	remaining := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "SELECT COUNT(*) AS total, SUM(name = 'renamed') AS renamed FROM main.events",
+	})
+	if len(remaining.Errors) > 0 {
+		t.Fatalf("count errors = %+v", remaining.Errors)
+	}
+	rows := remaining.Data.RowMaps()
+	if rows[0]["total"] != int64(5) || rows[0]["renamed"] != int64(0) {
+		t.Fatalf("rows after dry run = %+v", rows)
+	}
+	tables := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "SELECT name FROM main.sqlite_schema WHERE name = 'scratch'",
+	})
+	if len(tables.Errors) > 0 || len(tables.Data.Rows) != 0 {
+		t.Fatalf("scratch table after dry run = %+v", tables)
+	}
+}
+
+type dryRunTestDriver struct {
+	*routingTestDriver
+}
+
+func (d *dryRunTestDriver) Capabilities() database.Capabilities {
+	capabilities := d.routingTestDriver.Capabilities()
+	capabilities.Transactions = true
+	return capabilities
+}
+
+type nonTransactionalDryRunDriver struct {
+	*dryRunTestDriver
+	statements []string
+}
+
+func (d *nonTransactionalDryRunDriver) NonTransactionalTables(
+	_ context.Context,
+	statements []string,
+) ([]string, error) {
+	d.statements = statements
+	return []string{"shop.events (MyISAM)"}, nil
+}
+
+func TestDryRunRefusesNonTransactionalTables(t *testing.T) {
+	driver := &nonTransactionalDryRunDriver{
+		dryRunTestDriver: &dryRunTestDriver{routingTestDriver: &routingTestDriver{name: "alpha"}},
+	}
+	service := newRoutingTestService(nil, "alpha")
+	service.connections["alpha"] = &Connection{ID: "alpha", Name: "alpha", Driver: driver}
+
+	result := service.DryRunQuery(database.DryRunRequest{
+		ConnectionID: "alpha",
+		Query:        "UPDATE events SET name = 'x' WHERE id = 1",
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Code != errorCodeDryRunStorage {
+		t.Fatalf("DryRunQuery() = %+v, want a storage refusal", result)
+	}
+	if !strings.Contains(result.Errors[0].Detail, "shop.events (MyISAM)") {
+		t.Fatalf("detail = %q, want the MyISAM table", result.Errors[0].Detail)
+	}
+	if len(driver.statements) != 1 {
+		t.Fatalf("checked statements = %q, want the batch", driver.statements)
+	}
+	if driver.transactionContext() != nil {
+		t.Fatal("DryRunQuery() opened a transaction before refusing the tables")
+	}
+}
+
+func TestDryRunRefusesDDLWithoutTransactionalDDL(t *testing.T) {
+	driver := &dryRunTestDriver{routingTestDriver: &routingTestDriver{name: "alpha"}}
+	service := newRoutingTestService(nil, "alpha")
+	service.connections["alpha"] = &Connection{ID: "alpha", Name: "alpha", Driver: driver}
+
+	result := service.DryRunQuery(database.DryRunRequest{
+		ConnectionID: "alpha",
+		Query:        "UPDATE events SET name = 'x' WHERE id = 1; ALTER TABLE events ADD note TEXT",
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Code != errorCodeDryRunDDL {
+		t.Fatalf("DryRunQuery() = %+v, want a DDL refusal", result)
+	}
+	if driver.transactionContext() != nil {
+		t.Fatal("DryRunQuery() opened a transaction before refusing DDL")
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"strings"
+
+	"rollingthunder/pkg/database/duckdb"
+	"rollingthunder/pkg/response"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var duckDBDataFileFilters = []wailsruntime.FileFilter{
+	{
+		DisplayName: "Parquet and CSV files (*.parquet, *.csv, *.tsv)",
+		Pattern:     "*.parquet;*.csv;*.tsv;*.csv.gz;*.tsv.gz",
+	},
+	{
+		DisplayName: "All files",
+		Pattern:     "*",
+	},
+}
+
+// ChooseDuckDBDataFile opens the native file chooser for a Parquet or CSV
+// file and returns a read_parquet or read_csv query for the SQL editor. The
+// file is read by whichever DuckDB connection runs the query.
+func (s *Service) ChooseDuckDBDataFile() response.BaseResponse[duckdb.DataFile] {
+	if s.ctx == nil {
+		return serviceErrorWithCode[duckdb.DataFile](
+			500,
+			errorCodeDatabaseOperationFailed,
+			"Application is not ready",
+			"The native file picker is unavailable before application startup.",
+			"Wait for Rolling Thunder to finish starting and try again.",
+		)
+	}
+	path, err := s.dataFileOpenDialog(s.ctx, wailsruntime.OpenDialogOptions{
+		Title:                "Choose Parquet or CSV file",
+		Filters:              duckDBDataFileFilters,
+		CanCreateDirectories: false,
+		ResolvesAliases:      true,
+	})
+	if err != nil {
+		return serviceErrorWithCode[duckdb.DataFile](
+			500,
+			errorCodeDatabaseOperationFailed,
+			"Could not choose data file",
+			err.Error(),
+			"Check file permissions and try the native file picker again.",
+		)
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return response.BaseResponse[duckdb.DataFile]{}
+	}
+	file, err := duckdb.InspectDataFile(path)
+	if err != nil {
+		return serviceErrorWithCode[duckdb.DataFile](
+			400,
+			errorCodeInvalidRequest,
+			"Invalid data file",
+			err.Error(),
+			"Choose a .parquet, .csv, or .tsv file.",
+		)
+	}
+	return response.BaseResponse[duckdb.DataFile]{Data: file}
+}
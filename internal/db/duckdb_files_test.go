@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func TestChooseDuckDBDataFileReturnsReadQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "o'brien.parquet")
+	if err := os.WriteFile(path, []byte("PAR1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	service := NewService()
+	service.ctx = context.Background()
+	service.dataFileOpenDialog = func(
+		context.Context,
+		wailsruntime.OpenDialogOptions,
+	) (string, error) {
+		return path, nil
+	}
+
+	selected := service.ChooseDuckDBDataFile()
+	if len(selected.Errors) > 0 {
+		t.Fatalf("ChooseDuckDBDataFile() = %+v", selected)
+	}
+	if selected.Data.Path != path ||
+		selected.Data.Format != "parquet" ||
+		!strings.Contains(selected.Data.Query, "read_parquet(") ||
+		!strings.Contains(selected.Data.Query, "o''brien.parquet") {
+		t.Fatalf("selection = %+v", selected.Data)
+	}
+
+	service.dataFileOpenDialog = func(
+		context.Context,
+		wailsruntime.OpenDialogOptions,
+	) (string, error) {
+		return filepath.Join(filepath.Dir(path), "notes.md"), nil
+	}
+	if rejected := service.ChooseDuckDBDataFile(); len(rejected.Errors) == 0 {
+		t.Fatalf("unsupported file was accepted: %+v", rejected)
+	}
+}
@@ -28,6 +28,9 @@ const (
 	errorCodeQueryFailed                = "QUERY_FAILED"
 	errorCodeQueryCancelled             = "QUERY_CANCELLED"
 	errorCodeQueryNotRunning            = "QUERY_NOT_RUNNING"
+	errorCodeQueryCursorNotFound        = "QUERY_CURSOR_NOT_FOUND"
+	errorCodeRowCountNotFound           = "ROW_COUNT_NOT_FOUND"
+	errorCodeRowCountUnsupported        = "ROW_COUNT_UNSUPPORTED"
 	errorCodeQuerySyntax                = "QUERY_SYNTAX_ERROR"
 	errorCodeQueryConstraint            = "QUERY_CONSTRAINT_VIOLATION"
 	errorCodeQueryPermission            = "QUERY_PERMISSION_DENIED"
@@ -37,6 +40,8 @@ const (
 	errorCodeTransactionFailed          = "TRANSACTION_FAILED"
 	errorCodeTransactionControl         = "TRANSACTION_CONTROL_REQUIRES_MODE"
 	errorCodeUnsafeMutation             = "UNFILTERED_MUTATION_REQUIRES_CONFIRMATION"
+	errorCodeDryRunDDL                  = "DRY_RUN_DDL_NOT_TRANSACTIONAL"
+	errorCodeDryRunStorage              = "DRY_RUN_STORAGE_NOT_TRANSACTIONAL"
 	errorCodeReadOnlyConnection         = "READ_ONLY_CONNECTION"
 	errorCodeSQLFileFailed              = "SQL_FILE_FAILED"
 	errorCodeDataSyncFailed             = "DATA_SYNC_FAILED"
@@ -62,6 +67,8 @@ const (
 	errorCodeSecurityReview             = "SECURITY_CHANGE_REVIEW_REQUIRED"
 	errorCodeActivityUnsupported        = "ACTIVITY_MONITOR_UNSUPPORTED"
 	errorCodeActivityFailed             = "ACTIVITY_MONITOR_FAILED"
+	errorCodeLocalAPIFailed             = "LOCAL_API_FAILED"
+	errorCodeLocalAPIUnauthorized       = "LOCAL_API_UNAUTHORIZED"
 	errorCodeSessionCancellationFailed  = "SESSION_CANCELLATION_FAILED"
 	errorCodeInvalidRequest             = "INVALID_REQUEST"
 )
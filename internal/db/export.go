@@ -42,6 +42,14 @@ type exportJob struct {
 	rows      atomic.Int64
 	bytes     atomic.Int64
 	status    atomic.Value
+	table     atomic.Pointer[exportJobTable]
+}
+
+// exportJobTable is the table an archive export is currently writing.
+type exportJobTable struct {
+	name  string
+	index int
+	count int
 }
 
 func newExportJob(id string, totalRows int64, cancel context.CancelFunc) *exportJob {
@@ -57,7 +65,7 @@ func newExportJob(id string, totalRows int64, cancel context.CancelFunc) *export
 
 func (job *exportJob) progress() database.ExportProgress {
 	status, _ := job.status.Load().(string)
-	return database.ExportProgress{
+	progress := database.ExportProgress{
 		JobID:       job.id,
 		Status:      status,
 		Rows:        job.rows.Load(),
@@ -66,6 +74,12 @@ func (job *exportJob) progress() database.ExportProgress {
 		ElapsedMS:   time.Since(job.startedAt).Milliseconds(),
 		Cancellable: status != exportStatusCancelling,
 	}
+	if table := job.table.Load(); table != nil {
+		progress.Table = table.name
+		progress.TableIndex = table.index
+		progress.TableCount = table.count
+	}
+	return progress
 }
 
 type exportProgressWriter struct {
@@ -108,6 +122,36 @@ func exportFileConfiguration(format database.ExportFormat) (exportFileConfig, er
 				Pattern:     "*.json",
 			},
 		}, nil
+	case database.ExportFormatNDJSON:
+		return exportFileConfig{
+			title:           "Export NDJSON",
+			defaultFilename: application.Identifier + "-export.ndjson",
+			extension:       ".ndjson",
+			filter: wailsruntime.FileFilter{
+				DisplayName: "NDJSON files (*.ndjson)",
+				Pattern:     "*.ndjson",
+			},
+		}, nil
+	case database.ExportFormatParquet:
+		return exportFileConfig{
+			title:           "Export Parquet",
+			defaultFilename: application.Identifier + "-export.parquet",
+			extension:       ".parquet",
+			filter: wailsruntime.FileFilter{
+				DisplayName: "Parquet files (*.parquet)",
+				Pattern:     "*.parquet",
+			},
+		}, nil
+	case database.ExportFormatXLSX:
+		return exportFileConfig{
+			title:           "Export Excel workbook",
+			defaultFilename: application.Identifier + "-export.xlsx",
+			extension:       ".xlsx",
+			filter: wailsruntime.FileFilter{
+				DisplayName: "Excel workbooks (*.xlsx)",
+				Pattern:     "*.xlsx",
+			},
+		}, nil
 	case database.ExportFormatSQL:
 		return exportFileConfig{
 			title:           "Export SQL",
@@ -152,14 +196,20 @@ func ensureExportExtension(path string, format database.ExportFormat) string {
 	if err != nil {
 		return path
 	}
-	extension := filepath.Ext(path)
-	if strings.EqualFold(extension, config.extension) {
+	return withExportExtension(path, config.extension)
+}
+
+// withExportExtension compares whole suffixes so that compound extensions
+// such as .tar.zst are kept intact.
+func withExportExtension(path string, wanted string) string {
+	if strings.HasSuffix(strings.ToLower(path), strings.ToLower(wanted)) {
 		return path
 	}
+	extension := filepath.Ext(path)
 	if extension == "" {
-		return path + config.extension
+		return path + wanted
 	}
-	return strings.TrimSuffix(path, extension) + config.extension
+	return strings.TrimSuffix(path, extension) + wanted
 }
 
 func replaceExportFile(tempPath string, targetPath string) error {
@@ -287,6 +337,23 @@ func (s *Service) writeExport(
 	if err := database.ValidateExportOptions(options); err != nil {
 		return database.ExportResult{}, err
 	}
+	fileConfig, err := exportFileConfiguration(options.Format)
+	if err != nil {
+		return database.ExportResult{}, err
+	}
+	return s.writeExportFile(ctx, job, suggestedName, options, fileConfig, write)
+}
+
+// writeExportFile asks for the destination and writes it through a temporary
+// file, so a cancelled or failed export never replaces the target.
+func (s *Service) writeExportFile(
+	ctx context.Context,
+	job *exportJob,
+	suggestedName string,
+	options database.ExportOptions,
+	fileConfig exportFileConfig,
+	write exportWriterFunc,
+) (database.ExportResult, error) {
 	if err := database.CheckExportContext(ctx); err != nil {
 		return database.ExportResult{
 			Cancelled: true,
@@ -294,13 +361,9 @@ func (s *Service) writeExport(
 		}, nil
 	}
 
-	fileConfig, err := exportFileConfiguration(options.Format)
-	if err != nil {
-		return database.ExportResult{}, err
-	}
-	defaultName := ensureExportExtension(
+	defaultName := withExportExtension(
 		sanitizeSuggestedFilename(suggestedName, fileConfig.defaultFilename),
-		options.Format,
+		fileConfig.extension,
 	)
 	path, err := s.saveDialog(ctx, wailsruntime.SaveDialogOptions{
 		Title:                fileConfig.title,
@@ -323,7 +386,7 @@ func (s *Service) writeExport(
 			Format:    options.Format,
 		}, nil
 	}
-	path = ensureExportExtension(path, options.Format)
+	path = withExportExtension(path, fileConfig.extension)
 	if err := database.CheckExportContext(ctx); err != nil {
 		return database.ExportResult{
 			Cancelled: true,
@@ -440,8 +503,13 @@ func (s *Service) ExportQueryResults(
 		)
 	}
 
+	// Result metadata cannot rule out NULLs the way a table definition can,
+	// for example under an outer join, so every result column stays nullable.
+	for index := range request.Structures {
+		request.Structures[index].Nullable = true
+	}
 	expectedRows := request.ExpectedRows
-	if expectedRows <= 0 {
+	if expectedRows <= 0 && request.CursorID == "" {
 		expectedRows = int64(len(request.Rows))
 	}
 	ctx, job, err := s.startExportJob(request.JobID, expectedRows)
@@ -454,11 +522,15 @@ func (s *Service) ExportQueryResults(
 		ctx context.Context,
 		writer io.Writer,
 	) (database.ExportStats, error) {
-		return database.WriteExportRowsContext(
+		if request.CursorID != "" {
+			return s.exportQueryCursor(ctx, writer, request)
+		}
+		return database.WriteTypedExportRowsContext(
 			ctx,
 			writer,
 			request.Columns,
 			request.Rows,
+			request.Structures,
 			request.Options,
 		)
 	})
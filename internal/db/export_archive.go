@@ -0,0 +1,346 @@
+package db
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	"github.com/klauspost/compress/zstd"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// exportArchive collects the files of a multi-table export.
+type exportArchive interface {
+	add(name string, write func(io.Writer) error) error
+	close() error
+}
+
+type zipExportArchive struct {
+	writer *zip.Writer
+}
+
+func (archive *zipExportArchive) add(name string, write func(io.Writer) error) error {
+	member, err := archive.writer.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return write(member)
+}
+
+func (archive *zipExportArchive) close() error {
+	return archive.writer.Close()
+}
+
+// tarExportArchive spools each member to a temporary file first, because a
+// tar header states the member size before its content.
+type tarExportArchive struct {
+	encoder *zstd.Encoder
+	writer  *tar.Writer
+}
+
+func (archive *tarExportArchive) add(name string, write func(io.Writer) error) error {
+	spool, err := os.CreateTemp("", "."+application.Identifier+"-archive-*")
+	if err != nil {
+		return fmt.Errorf("create archive spool: %w", err)
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}()
+	buffered := bufio.NewWriter(spool)
+	if err := write(buffered); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("write archive spool: %w", err)
+	}
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := archive.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     size,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(archive.writer, spool)
+	return err
+}
+
+func (archive *tarExportArchive) close() error {
+	if err := archive.writer.Close(); err != nil {
+		_ = archive.encoder.Close()
+		return err
+	}
+	return archive.encoder.Close()
+}
+
+func newExportArchive(
+	format database.ExportArchiveFormat,
+	writer io.Writer,
+) (exportArchive, error) {
+	switch format {
+	case database.ExportArchiveZip:
+		return &zipExportArchive{writer: zip.NewWriter(writer)}, nil
+	case database.ExportArchiveTarZstd:
+		encoder, err := zstd.NewWriter(writer)
+		if err != nil {
+			return nil, err
+		}
+		return &tarExportArchive{encoder: encoder, writer: tar.NewWriter(encoder)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export archive %q", format)
+	}
+}
+
+func exportArchiveFileConfiguration(
+	format database.ExportArchiveFormat,
+) (exportFileConfig, error) {
+	switch format {
+	case database.ExportArchiveZip:
+		return exportFileConfig{
+			title:           "Export tables as ZIP archive",
+			defaultFilename: application.Identifier + "-export.zip",
+			extension:       ".zip",
+			filter: wailsruntime.FileFilter{
+				DisplayName: "ZIP archives (*.zip)",
+				Pattern:     "*.zip",
+			},
+		}, nil
+	case database.ExportArchiveTarZstd:
+		return exportFileConfig{
+			title:           "Export tables as tar.zst archive",
+			defaultFilename: application.Identifier + "-export.tar.zst",
+			extension:       ".tar.zst",
+			filter: wailsruntime.FileFilter{
+				DisplayName: "Zstandard tar archives (*.tar.zst)",
+				Pattern:     "*.tar.zst",
+			},
+		}, nil
+	default:
+		return exportFileConfig{}, fmt.Errorf("unsupported export archive %q", format)
+	}
+}
+
+// archiveExportTables returns the requested tables, or every table of the
+// requested schema in name order.
+func archiveExportTables(
+	driver database.Driver,
+	request database.ArchiveExportRequest,
+) ([]database.Table, error) {
+	if len(request.Tables) > 0 {
+		return request.Tables, nil
+	}
+	schema := strings.TrimSpace(request.Schema)
+	var (
+		names []string
+		err   error
+	)
+	if schema == "" {
+		names, err = driver.GetCollections()
+	} else {
+		names, err = driver.GetCollections(schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("there are no tables to export")
+	}
+	sort.Strings(names)
+	tables := make([]database.Table, len(names))
+	for index, name := range names {
+		tables[index] = database.Table{Schema: schema, Name: name}
+	}
+	return tables, nil
+}
+
+// exportArchiveNames gives every table a distinct member name. Names are
+// compared without case so the archive extracts cleanly on Windows and
+// macOS.
+type exportArchiveNames map[string]struct{}
+
+func (names exportArchiveNames) unique(table database.Table) string {
+	base := sanitizeSuggestedFilename(table.Name, "table")
+	if table.Schema != "" {
+		base = sanitizeSuggestedFilename(table.Schema, "schema") + "/" + base
+	}
+	name := base
+	for suffix := 2; ; suffix++ {
+		if _, taken := names[strings.ToLower(name)]; !taken {
+			names[strings.ToLower(name)] = struct{}{}
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", base, suffix)
+	}
+}
+
+func exportArchiveTableLabel(table database.Table) string {
+	if table.Schema == "" {
+		return table.Name
+	}
+	return table.Schema + "." + table.Name
+}
+
+// writeExportArchive streams each table through the driver's table export
+// and records its row count in a manifest written after the last table.
+func writeExportArchive(
+	ctx context.Context,
+	job *exportJob,
+	driver database.Driver,
+	writer io.Writer,
+	tables []database.Table,
+	request database.ArchiveExportRequest,
+	extension string,
+) (database.ExportStats, error) {
+	archive, err := newExportArchive(request.Archive, writer)
+	if err != nil {
+		return database.ExportStats{}, err
+	}
+	manifest := database.ExportArchiveManifest{
+		Engine:    driver.Capabilities().Engine,
+		Format:    request.Options.Format,
+		CreatedAt: time.Now().UTC(),
+		Tables:    make([]database.ExportArchiveManifestTable, 0, len(tables)),
+	}
+	names := make(exportArchiveNames, len(tables))
+	var total int64
+	writeErr := func() error {
+		for index, table := range tables {
+			if err := database.CheckExportContext(ctx); err != nil {
+				return err
+			}
+			label := exportArchiveTableLabel(table)
+			job.table.Store(&exportJobTable{name: label, index: index + 1, count: len(tables)})
+			base := names.unique(table)
+			entry := database.ExportArchiveManifestTable{
+				Schema: table.Schema,
+				Name:   table.Name,
+				Path:   base + extension,
+			}
+			if request.IncludeDDL {
+				ddl, err := driver.GetTableDDL(table)
+				if err != nil {
+					return fmt.Errorf("read DDL of %s: %w", label, err)
+				}
+				entry.DDL = base + ".ddl.sql"
+				if err := archive.add(entry.DDL, func(writer io.Writer) error {
+					_, err := io.WriteString(writer, ddl)
+					return err
+				}); err != nil {
+					return fmt.Errorf("archive DDL of %s: %w", label, err)
+				}
+			}
+			done := total
+			tableCtx := database.WithExportProgressReporter(ctx, func(rows int64) {
+				job.rows.Store(done + rows)
+			})
+			var stats database.ExportStats
+			if err := archive.add(entry.Path, func(writer io.Writer) error {
+				var exportErr error
+				stats, exportErr = driver.ExportTable(
+					tableCtx,
+					database.TableExportRequest{
+						Table:   table,
+						Scope:   database.ExportScopeAll,
+						Options: request.Options,
+					},
+					writer,
+				)
+				return exportErr
+			}); err != nil {
+				return fmt.Errorf("export %s: %w", label, err)
+			}
+			entry.Rows = stats.Rows
+			total += stats.Rows
+			job.rows.Store(total)
+			manifest.Tables = append(manifest.Tables, entry)
+		}
+		return archive.add(database.ExportArchiveManifestPath, func(writer io.Writer) error {
+			encoder := json.NewEncoder(writer)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(manifest)
+		})
+	}()
+	if writeErr != nil {
+		_ = archive.close()
+		return database.ExportStats{}, writeErr
+	}
+	if err := archive.close(); err != nil {
+		return database.ExportStats{}, fmt.Errorf("finish export archive: %w", err)
+	}
+	return database.ExportStats{Rows: total}, nil
+}
+
+// ExportTablesArchive exports several tables, or a whole schema, into one
+// ZIP or tar.zst archive with a manifest of row counts.
+func (s *Service) ExportTablesArchive(
+	connectionID string,
+	request database.ArchiveExportRequest,
+) response.BaseResponse[database.ExportResult] {
+	if err := database.ValidateArchiveExportRequest(request); err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	dataConfig, err := exportFileConfiguration(request.Options.Format)
+	if err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	archiveConfig, err := exportArchiveFileConfiguration(request.Archive)
+	if err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	ctx, job, err := s.startExportJob(request.JobID, 0)
+	if err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	defer s.finishExportJob(job)
+
+	var tableCount int
+	result, err := s.writeExportFile(ctx, job, request.SuggestedName, request.Options, archiveConfig, func(
+		ctx context.Context,
+		writer io.Writer,
+	) (database.ExportStats, error) {
+		driver, release, err := s.driverFor(connectionID)
+		if err != nil {
+			return database.ExportStats{}, err
+		}
+		defer release()
+
+		tables, err := archiveExportTables(driver, request)
+		if err != nil {
+			return database.ExportStats{}, err
+		}
+		tableCount = len(tables)
+		return writeExportArchive(ctx, job, driver, writer, tables, request, dataConfig.extension)
+	})
+	if err != nil {
+		return serviceError[database.ExportResult](err.Error())
+	}
+	if !result.Cancelled {
+		result.Tables = tableCount
+	}
+	return response.BaseResponse[database.ExportResult]{Data: result}
+}
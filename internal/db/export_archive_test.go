@@ -0,0 +1,221 @@
+package db
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rollingthunder/pkg/database"
+
+	"github.com/klauspost/compress/zstd"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func newArchiveExportService(t *testing.T) (*Service, string) {
+	t.Helper()
+	service, connectionID := newSQLiteImportService(t)
+	for _, query := range []string{
+		"CREATE TABLE main.orders (id INTEGER NOT NULL, total REAL)",
+		"INSERT INTO main.orders VALUES (1, 9.5), (2, 12), (3, NULL)",
+		"CREATE TABLE main.customers (id INTEGER NOT NULL, name TEXT)",
+		"INSERT INTO main.customers VALUES (1, 'Ada')",
+	} {
+		result := service.ExecuteQuery(database.QueryRequest{
+			ConnectionID: connectionID,
+			Query:        query,
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %+v", query, result.Errors)
+		}
+	}
+	return service, connectionID
+}
+
+func readArchiveMembers(
+	t *testing.T,
+	path string,
+	format database.ExportArchiveFormat,
+) map[string]string {
+	t.Helper()
+	members := map[string]string{}
+	switch format {
+	case database.ExportArchiveZip:
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("open zip: %v", err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			member, err := file.Open()
+			if err != nil {
+				t.Fatalf("open %s: %v", file.Name, err)
+			}
+			content, err := io.ReadAll(member)
+			_ = member.Close()
+			if err != nil {
+				t.Fatalf("read %s: %v", file.Name, err)
+			}
+			members[file.Name] = string(content)
+		}
+	case database.ExportArchiveTarZstd:
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		decoder, err := zstd.NewReader(file)
+		if err != nil {
+			t.Fatalf("open zstd: %v", err)
+		}
+		defer decoder.Close()
+		reader := tar.NewReader(decoder)
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("read tar: %v", err)
+			}
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("read %s: %v", header.Name, err)
+			}
+			members[header.Name] = string(content)
+		}
+	}
+	return members
+}
+
+func TestExportTablesArchiveWritesEveryTableWithManifest(t *testing.T) {
+	for _, format := range []database.ExportArchiveFormat{
+		database.ExportArchiveZip,
+		database.ExportArchiveTarZstd,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			service, connectionID := newArchiveExportService(t)
+			target := filepath.Join(t.TempDir(), "main")
+			service.saveDialog = func(
+				context.Context,
+				wailsruntime.SaveDialogOptions,
+			) (string, error) {
+				return target, nil
+			}
+
+			response := service.ExportTablesArchive(connectionID, database.ArchiveExportRequest{
+				Schema:     "main",
+				Archive:    format,
+				IncludeDDL: true,
+				Options:    csvExportOptions(),
+			})
+			if len(response.Errors) != 0 {
+				t.Fatalf("archive export errors: %+v", response.Errors)
+			}
+			wantPath := target + "." + string(format)
+			if response.Data.Path != wantPath {
+				t.Fatalf("path = %q, want %q", response.Data.Path, wantPath)
+			}
+			if response.Data.Rows != 4 || response.Data.Tables != 2 {
+				t.Fatalf("result = %+v, want 4 rows in 2 tables", response.Data)
+			}
+
+			members := readArchiveMembers(t, wantPath, format)
+			var manifest database.ExportArchiveManifest
+			if err := json.Unmarshal(
+				[]byte(members[database.ExportArchiveManifestPath]),
+				&manifest,
+			); err != nil {
+				t.Fatalf("decode manifest: %v", err)
+			}
+			if manifest.Engine != "sqlite" || manifest.Format != database.ExportFormatCSV {
+				t.Fatalf("manifest = %+v", manifest)
+			}
+			if len(manifest.Tables) != 2 ||
+				manifest.Tables[0].Name != "customers" ||
+				manifest.Tables[0].Rows != 1 ||
+				manifest.Tables[1].Name != "orders" ||
+				manifest.Tables[1].Rows != 3 {
+				t.Fatalf("manifest tables = %+v", manifest.Tables)
+			}
+			for _, table := range manifest.Tables {
+				if table.Path != "main/"+table.Name+".csv" {
+					t.Fatalf("%s data path = %q", table.Name, table.Path)
+				}
+				if !strings.Contains(members[table.DDL], "CREATE TABLE") {
+					t.Fatalf("%s DDL member %q = %q", table.Name, table.DDL, members[table.DDL])
+				}
+			}
+			if got := members["main/orders.csv"]; got != "id,total\n1,9.5\n2,12\n3,NULL\n" {
+				t.Fatalf("orders.csv = %q", got)
+			}
+		})
+	}
+}
+
+func TestFailedArchiveExportLeavesNoArchive(t *testing.T) {
+	service, connectionID := newArchiveExportService(t)
+	target := filepath.Join(t.TempDir(), "tables.zip")
+	service.saveDialog = func(
+		context.Context,
+		wailsruntime.SaveDialogOptions,
+	) (string, error) {
+		return target, nil
+	}
+
+	response := service.ExportTablesArchive(connectionID, database.ArchiveExportRequest{
+		Tables: []database.Table{
+			{Schema: "main", Name: "orders"},
+			{Schema: "main", Name: "missing"},
+		},
+		Archive: database.ExportArchiveZip,
+		Options: csvExportOptions(),
+	})
+	if len(response.Errors) == 0 {
+		t.Fatal("archive export of a missing table succeeded")
+	}
+	if _, err := os.Stat(target); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("failed export left %s behind: %v", target, err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("failed export left temporary files: %v", entries)
+	}
+}
+
+func TestExportArchiveNamesAreUniqueWithoutCase(t *testing.T) {
+	names := exportArchiveNames{}
+	got := []string{
+		names.unique(database.Table{Schema: "sales", Name: "Orders"}),
+		names.unique(database.Table{Schema: "sales", Name: "orders"}),
+		names.unique(database.Table{Name: "orders"}),
+	}
+	want := []string{"sales/Orders", "sales/orders-2", "orders"}
+	for index := range want {
+		if got[index] != want[index] {
+			t.Fatalf("names = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWithExportExtensionKeepsCompoundArchiveExtension(t *testing.T) {
+	tests := map[string]string{
+		"tables":         "tables.tar.zst",
+		"tables.TAR.ZST": "tables.TAR.ZST",
+		"tables.zip":     "tables.tar.zst",
+	}
+	for path, want := range tests {
+		if got := withExportExtension(path, ".tar.zst"); got != want {
+			t.Fatalf("withExportExtension(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"rollingthunder/internal/diagnostics"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+var errHeadlessDialog = fmt.Errorf(
+	"native file dialogs are unavailable without a window; pass an explicit path",
+)
+
+// HeadlessOptions overrides the persistent stores used by a headless service.
+// Nil values keep the same settings file and operating-system credential
+// store as the desktop application.
+type HeadlessOptions struct {
+	ConnectionStorage *ConnectionStorage
+	BackupCatalog     *BackupCatalogStorage
+	AuditLog          *AuditLogStorage
+	CredentialStore   CredentialStore
+	Diagnostics       *diagnostics.Manager
+	Version           string
+}
+
+// Headless runs Service workflows without a Wails window. Native dialogs are
+// replaced by explicit paths supplied per call, so every guarded export,
+// backup, and restore path stays identical to the desktop application.
+//
+// Headless is intentionally not a Service method set: Wails binds every
+// exported Service method to the webview, and the webview must never be able
+// to name arbitrary filesystem paths.
+type Headless struct {
+	service *Service
+	version string
+
+	// mu serializes calls that hand a path to a dialog replacement.
+	mu          sync.Mutex
+	destination string
+	source      string
+
+	profileMu sync.Mutex
+	profiles  map[string]string
+}
+
+func NewHeadless(options HeadlessOptions) *Headless {
+	version := strings.TrimSpace(options.Version)
+	if version == "" {
+		version = defaultServiceVersion
+	}
+	service := NewServiceWithDiagnosticsAndVersion(options.Diagnostics, version)
+	if options.ConnectionStorage != nil {
+		service.connectionStorage = options.ConnectionStorage
+	}
+	if options.BackupCatalog != nil {
+		service.backupCatalog = options.BackupCatalog
+	}
+	if options.AuditLog != nil {
+		service.auditLog = options.AuditLog
+	}
+	if options.CredentialStore != nil {
+		service.credentialStore = options.CredentialStore
+	}
+	// Backups made from the command line are cataloged, but schedules only
+	// run inside the desktop application.
+	service.backupScheduleInterval = 0
+
+	headless := &Headless{
+		service:  service,
+		version:  version,
+		profiles: make(map[string]string),
+	}
+	// Reviewed fingerprints include connection IDs. Deriving them from the
+	// saved profile lets a preview from one invocation be applied by the next.
+	service.newConnectionID = headlessConnectionID
+	service.saveDialog = headless.saveDestination
+	service.sqliteSaveDialog = headless.saveDestination
+	service.restoreOpenDialog = headless.openSource
+	service.importOpenDialog = headless.openSource
+	service.sqlOpenDialog = headlessOpenDialog
+	service.sqliteOpenDialog = headlessOpenDialog
+	service.oracleTNSOpenDialog = headlessOpenDialog
+	service.oracleWalletDialog = headlessOpenDialog
+	service.dataFileOpenDialog = headlessOpenDialog
+	return headless
+}
+
+func headlessConnectionID(request ConnectRequest) string {
+	if strings.TrimSpace(request.ProfileID) == "" {
+		return randomConnectionID(request)
+	}
+	return "profile:" + request.ProfileID
+}
+
+func headlessOpenDialog(
+	context.Context,
+	wailsruntime.OpenDialogOptions,
+) (string, error) {
+	return "", errHeadlessDialog
+}
+
+func (h *Headless) saveDestination(
+	context.Context,
+	wailsruntime.SaveDialogOptions,
+) (string, error) {
+	if h.destination == "" {
+		return "", errHeadlessDialog
+	}
+	return h.destination, nil
+}
+
+func (h *Headless) openSource(
+	context.Context,
+	wailsruntime.OpenDialogOptions,
+) (string, error) {
+	if h.source == "" {
+		return "", errHeadlessDialog
+	}
+	return h.source, nil
+}
+
+// Service exposes the wrapped service for workflows that do not touch local
+// files, such as queries, schema diffs, and data diffs.
+func (h *Headless) Service() *Service {
+	return h.service
+}
+
+func (h *Headless) Version() string {
+	return h.version
+}
+
+func (h *Headless) Start(ctx context.Context) {
+	h.service.Start(ctx)
+}
+
+func (h *Headless) Shutdown(ctx context.Context) {
+	h.service.Shutdown(ctx)
+}
+
+func (h *Headless) withDestination(path string, run func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.destination = strings.TrimSpace(path)
+	defer func() { h.destination = "" }()
+	run()
+}
+
+func (h *Headless) withSource(path string, run func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.source = strings.TrimSpace(path)
+	defer func() { h.source = "" }()
+	run()
+}
+
+// ConnectProfile connects a saved profile by ID or by its exact display name.
+// Secrets are hydrated from the credential store exactly like the desktop
+// connection manager; they are never accepted on the command line.
+func (h *Headless) ConnectProfile(
+	profile string,
+) response.BaseResponse[ConnectResponse] {
+	resolved := h.ProfileID(profile)
+	if len(resolved.Errors) > 0 {
+		return response.BaseResponse[ConnectResponse]{Errors: resolved.Errors}
+	}
+	return h.connectSavedProfile(resolved.Data)
+}
+
+// ProfileID resolves a saved profile ID or exact display name to its ID.
+func (h *Headless) ProfileID(profile string) response.BaseResponse[string] {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		return HeadlessRequestError[string](
+			"Connection profile required",
+			"A saved profile ID or name is required.",
+			"List saved profiles and pass one with --profile.",
+		)
+	}
+	connections, err := h.service.loadSavedConnections()
+	if err != nil {
+		return connectionStorageError[string](
+			"Could not load saved connections",
+			err,
+		)
+	}
+	matches := make([]SavedConnection, 0, 1)
+	for _, connection := range connections {
+		if connection.ID == profile {
+			matches = []SavedConnection{connection}
+			break
+		}
+		if strings.EqualFold(strings.TrimSpace(connection.Config.Name), profile) {
+			matches = append(matches, connection)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return serviceErrorWithCode[string](
+			http.StatusNotFound,
+			errorCodeInvalidRequest,
+			"Connection profile not found",
+			fmt.Sprintf("No saved profile has the ID or name %q.", profile),
+			"List saved profiles and pass an existing ID or name.",
+		)
+	case 1:
+		return response.BaseResponse[string]{Data: matches[0].ID}
+	default:
+		return serviceErrorWithCode[string](
+			http.StatusConflict,
+			errorCodeInvalidRequest,
+			"Connection profile is ambiguous",
+			fmt.Sprintf("%d saved profiles are named %q.", len(matches), profile),
+			"Pass the profile ID instead of its name.",
+		)
+	}
+}
+
+// connectSavedProfile reuses the live connection for a profile, so a diff
+// between two schemas of one profile does not open a second session under the
+// same stable connection ID.
+func (h *Headless) connectSavedProfile(
+	profileID string,
+) response.BaseResponse[ConnectResponse] {
+	h.profileMu.Lock()
+	defer h.profileMu.Unlock()
+	if connectionID, exists := h.profiles[profileID]; exists {
+		if _, release, err := h.service.pinnedConnection(connectionID); err == nil {
+			release()
+			return response.BaseResponse[ConnectResponse]{
+				Data: ConnectResponse{
+					Connected:    true,
+					ConnectionID: connectionID,
+				},
+			}
+		}
+		delete(h.profiles, profileID)
+	}
+	connected := h.service.ConnectSavedConnection(profileID, "")
+	if len(connected.Errors) == 0 && connected.Data.Connected {
+		h.profiles[profileID] = connected.Data.ConnectionID
+	}
+	return connected
+}
+
+func (h *Headless) SavedConnections() response.BaseResponse[[]SavedConnection] {
+	return h.service.GetSavedConnections()
+}
+
+// ExportTableData streams a table export to path through the same temp-file
+// replacement, progress, and cancellation path used by the desktop dialog.
+func (h *Headless) ExportTableData(
+	connectionID string,
+	request database.TableExportRequest,
+	path string,
+) response.BaseResponse[database.ExportResult] {
+	if strings.TrimSpace(path) == "" {
+		return HeadlessRequestError[database.ExportResult](
+			"Export destination required",
+			"An output path is required for headless exports.",
+			"Pass a destination file with --output.",
+		)
+	}
+	var result response.BaseResponse[database.ExportResult]
+	h.withDestination(path, func() {
+		result = h.service.ExportTableData(connectionID, request)
+	})
+	return result
+}
+
+func (h *Headless) BackupDatabase(
+	request database.BackupRequest,
+	path string,
+) response.BaseResponse[database.BackupResult] {
+	var result response.BaseResponse[database.BackupResult]
+	h.withDestination(path, func() {
+		result = h.service.BackupDatabase(request)
+	})
+	return result
+}
+
+// ExportAuditLog writes the matching audit entries to path.
+func (h *Headless) ExportAuditLog(
+	request database.AuditExportRequest,
+	path string,
+) response.BaseResponse[database.ExportResult] {
+	if strings.TrimSpace(path) == "" {
+		return HeadlessRequestError[database.ExportResult](
+			"Export destination required",
+			"An output path is required for headless audit exports.",
+			"Pass a destination file with --output.",
+		)
+	}
+	var result response.BaseResponse[database.ExportResult]
+	h.withDestination(path, func() {
+		result = h.service.ExportAuditLog(request)
+	})
+	return result
+}
+
+// ChooseRestoreFile grants a restore token for path. The token is validated
+// and fingerprinted by PreviewDatabaseRestore and ApplyDatabaseRestore.
+func (h *Headless) ChooseRestoreFile(
+	connectionID string,
+	path string,
+) response.BaseResponse[database.RestoreFileSelection] {
+	if strings.TrimSpace(path) == "" {
+		return HeadlessRequestError[database.RestoreFileSelection](
+			"Restore file required",
+			"A backup file path is required for headless restores.",
+			"Pass the backup file with --input.",
+		)
+	}
+	var result response.BaseResponse[database.RestoreFileSelection]
+	h.withSource(path, func() {
+		result = h.service.ChooseRestoreFile(connectionID)
+	})
+	return result
+}
+
+// HeadlessRequestError reports invalid command input with the same envelope
+// and INVALID_REQUEST code returned by the Service.
+func HeadlessRequestError[T any](
+	title string,
+	detail string,
+	hint string,
+) response.BaseResponse[T] {
+	return serviceErrorWithCode[T](
+		http.StatusBadRequest,
+		errorCodeInvalidRequest,
+		title,
+		detail,
+		hint,
+	)
+}
@@ -429,6 +429,8 @@ func (s *Service) ReconnectConnection(
 }
 
 func (s *Service) Shutdown(_ context.Context) {
+	s.stopLocalAPI()
+	s.stopBackupScheduler()
 	if s.healthCancel != nil {
 		s.healthCancel()
 	}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/csv"
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -30,8 +32,8 @@ const importBatchSize = 200
 
 var importFileFilters = []wailsruntime.FileFilter{
 	{
-		DisplayName: "CSV and JSON data (*.csv, *.json, *.jsonl, *.ndjson)",
-		Pattern:     "*.csv;*.json;*.jsonl;*.ndjson",
+		DisplayName: "Data files (*.csv, *.json, *.jsonl, *.ndjson, *.parquet, *.xlsx)",
+		Pattern:     "*.csv;*.json;*.jsonl;*.ndjson;*.parquet;*.xlsx",
 	},
 	{
 		DisplayName: "CSV files (*.csv)",
@@ -41,6 +43,14 @@ var importFileFilters = []wailsruntime.FileFilter{
 		DisplayName: "JSON files (*.json, *.jsonl, *.ndjson)",
 		Pattern:     "*.json;*.jsonl;*.ndjson",
 	},
+	{
+		DisplayName: "Parquet files (*.parquet)",
+		Pattern:     "*.parquet",
+	},
+	{
+		DisplayName: "Excel workbooks (*.xlsx)",
+		Pattern:     "*.xlsx",
+	},
 }
 
 type importFileGrant struct {
@@ -54,8 +64,12 @@ func importFormatFromPath(path string) (string, error) {
 		return "csv", nil
 	case ".json", ".jsonl", ".ndjson":
 		return "json", nil
+	case ".parquet":
+		return "parquet", nil
+	case ".xlsx":
+		return "xlsx", nil
 	default:
-		return "", fmt.Errorf("only CSV, JSON, JSONL, and NDJSON files are supported")
+		return "", fmt.Errorf("only CSV, JSON, JSONL, NDJSON, Parquet, and Excel files are supported")
 	}
 }
 
@@ -259,15 +273,67 @@ func (reader *csvImportReader) Next() (map[string]interface{}, error) {
 	return row, nil
 }
 
+// jsonImportSource keeps the bytes the decoder has read ahead but not yet
+// consumed, so the line of a record can be counted without holding the file.
+type jsonImportSource struct {
+	source  io.Reader
+	pending []byte
+	start   int
+	offset  int64
+	line    int
+}
+
+func (source *jsonImportSource) Read(buffer []byte) (int, error) {
+	read, err := source.source.Read(buffer)
+	source.pending = append(source.pending, buffer[:read]...)
+	return read, err
+}
+
+// advance moves the position to offset, which the decoder has consumed.
+func (source *jsonImportSource) advance(offset int64) {
+	consumed := int(offset - source.offset)
+	source.line += bytes.Count(source.pending[source.start:source.start+consumed], []byte{'\n'})
+	source.start += consumed
+	source.offset = offset
+	if source.start > len(source.pending)/2 {
+		source.pending = append(source.pending[:0], source.pending[source.start:]...)
+		source.start = 0
+	}
+}
+
+// recordStart returns the line and byte offset where the next record
+// begins, past whitespace and, inside an array, the separating comma.
+func (source *jsonImportSource) recordStart(array bool) (int, int64) {
+	line, offset := source.line, source.offset
+	for _, character := range source.pending[source.start:] {
+		switch {
+		case character == '\n':
+			line++
+		case character == ' ' || character == '\t' || character == '\r' || array && character == ',':
+		default:
+			return line, offset
+		}
+		offset++
+	}
+	return line, offset
+}
+
+// jsonImportReader streams records from a top-level JSON array or from
+// NDJSON, one object per line. Either way only the current record is
+// decoded into memory.
 type jsonImportReader struct {
+	source  *jsonImportSource
 	decoder *json.Decoder
+	skipped int64
 	array   bool
 	closed  bool
 	columns map[string]struct{}
 }
 
-func newJSONImportReader(source io.Reader) (*jsonImportReader, error) {
+func newJSONImportReader(input io.Reader) (*jsonImportReader, error) {
+	source := &jsonImportSource{source: input, line: 1}
 	buffered := bufio.NewReader(source)
+	var skipped int64
 	for {
 		peek, err := buffered.Peek(1)
 		if err != nil {
@@ -280,11 +346,14 @@ func newJSONImportReader(source io.Reader) (*jsonImportReader, error) {
 			break
 		}
 		_, _ = buffered.ReadByte()
+		skipped++
 	}
 	decoder := json.NewDecoder(buffered)
 	decoder.UseNumber()
 	reader := &jsonImportReader{
+		source:  source,
 		decoder: decoder,
+		skipped: skipped,
 		columns: make(map[string]struct{}),
 	}
 	first, _ := buffered.Peek(1)
@@ -326,9 +395,10 @@ func (reader *jsonImportReader) Next() (map[string]interface{}, error) {
 	if reader.closed {
 		return nil, io.EOF
 	}
+	reader.source.advance(reader.skipped + reader.decoder.InputOffset())
 	if reader.array && !reader.decoder.More() {
 		if _, err := reader.decoder.Token(); err != nil {
-			return nil, fmt.Errorf("close JSON array: %w", err)
+			return nil, reader.recordError("does not close the JSON array", err)
 		}
 		reader.closed = true
 		return nil, io.EOF
@@ -339,16 +409,16 @@ func (reader *jsonImportReader) Next() (map[string]interface{}, error) {
 			reader.closed = true
 			return nil, io.EOF
 		}
-		return nil, fmt.Errorf("decode JSON object: %w", err)
+		return nil, reader.recordError("is not valid JSON", err)
 	}
 	if raw == nil {
-		return nil, fmt.Errorf("each JSON record must be an object")
+		return nil, reader.recordError("must be an object", nil)
 	}
 	row := make(map[string]interface{}, len(raw))
 	for key, value := range raw {
 		name := strings.TrimSpace(key)
 		if name == "" {
-			return nil, fmt.Errorf("JSON object keys cannot be empty")
+			return nil, reader.recordError("has an empty key", nil)
 		}
 		reader.columns[name] = struct{}{}
 		row[name] = normalizeJSONImportValue(value)
@@ -356,6 +426,16 @@ func (reader *jsonImportReader) Next() (map[string]interface{}, error) {
 	return row, nil
 }
 
+// recordError locates the record the decoder just failed on. Its bytes have
+// been read by then, even when the decoder had not read ahead to it before.
+func (reader *jsonImportReader) recordError(problem string, err error) error {
+	line, offset := reader.source.recordStart(reader.array)
+	if err == nil {
+		return fmt.Errorf("JSON record at line %d (byte offset %d) %s", line, offset, problem)
+	}
+	return fmt.Errorf("JSON record at line %d (byte offset %d) %s: %w", line, offset, problem, err)
+}
+
 func (reader *jsonImportReader) Columns() []string {
 	columns := make([]string, 0, len(reader.columns))
 	for column := range reader.columns {
@@ -396,6 +476,21 @@ func openImportRecordReader(
 			return nil, nil, readerErr
 		}
 		return reader, file, nil
+	case "parquet":
+		reader, readerErr := newParquetImportReader(file)
+		if readerErr != nil {
+			_ = file.Close()
+			return nil, nil, readerErr
+		}
+		// Closing the Parquet reader also closes the file.
+		return reader, reader, nil
+	case "xlsx":
+		reader, readerErr := newXLSXImportReader(file, options)
+		if readerErr != nil {
+			_ = file.Close()
+			return nil, nil, readerErr
+		}
+		return reader, reader, nil
 	default:
 		_ = file.Close()
 		return nil, nil, fmt.Errorf("unsupported import format %q", format)
@@ -412,6 +507,11 @@ func inferredImportType(value interface{}) string {
 		return "integer"
 	case float32, float64:
 		return "number"
+	case time.Time:
+		if hour, minute, second := typed.Clock(); hour == 0 && minute == 0 && second == 0 && typed.Nanosecond() == 0 {
+			return "date"
+		}
+		return "datetime"
 	case string:
 		text := strings.TrimSpace(typed)
 		if text == "" {
@@ -451,6 +551,10 @@ func mergeImportTypes(current, next string) string {
 		(current == "number" && next == "integer") {
 		return "number"
 	}
+	if (current == "date" && next == "datetime") ||
+		(current == "datetime" && next == "date") {
+		return "datetime"
+	}
 	return "text"
 }
 
@@ -483,6 +587,9 @@ func (s *Service) InspectImportFile(
 	rows := make([]map[string]interface{}, 0, limit)
 	types := make(map[string]string)
 	seen := make(map[string]int)
+	// Workbook cells carry their own type, so a text cell stays text even
+	// when it looks like a number.
+	workbook, typedCells := reader.(importSheetReader)
 	for len(rows) < limit {
 		row, readErr := reader.Next()
 		if errors.Is(readErr, io.EOF) {
@@ -500,7 +607,11 @@ func (s *Service) InspectImportFile(
 		rows = append(rows, row)
 		for column, value := range row {
 			seen[column]++
-			types[column] = mergeImportTypes(types[column], inferredImportType(value))
+			inferred := inferredImportType(value)
+			if _, text := value.(string); text && typedCells {
+				inferred = "text"
+			}
+			types[column] = mergeImportTypes(types[column], inferred)
 		}
 	}
 	columns := reader.Columns()
@@ -513,6 +624,21 @@ func (s *Service) InspectImportFile(
 			"Choose another source or enable the correct CSV header setting.",
 		)
 	}
+	var sheets []string
+	if typedCells {
+		sheets = workbook.Sheets()
+	}
+	if declared, ok := reader.(importSchemaReader); ok {
+		return response.BaseResponse[database.ImportPreview]{
+			Data: database.ImportPreview{
+				File:    grant.selection,
+				Columns: declared.DeclaredColumns(),
+				Rows:    rows,
+				Sampled: len(rows),
+				Sheets:  sheets,
+			},
+		}
+	}
 	previewColumns := make([]database.ImportColumn, 0, len(columns))
 	for _, column := range columns {
 		inferred := types[column]
@@ -542,6 +668,7 @@ func (s *Service) InspectImportFile(
 			Columns: previewColumns,
 			Rows:    rows,
 			Sampled: len(rows),
+			Sheets:  sheets,
 		},
 	}
 }
@@ -567,7 +694,7 @@ func validateImportColumns(columns []database.ImportColumn) ([]database.ImportCo
 			return nil, fmt.Errorf("source column %q is mapped more than once", column.SourceName)
 		}
 		switch column.InferredType {
-		case "text", "integer", "number", "boolean", "datetime":
+		case "text", "integer", "number", "decimal", "boolean", "date", "datetime", "binary":
 		default:
 			return nil, fmt.Errorf(
 				"unsupported inferred type %q for %s",
@@ -587,16 +714,22 @@ func validateImportColumns(columns []database.ImportColumn) ([]database.ImportCo
 
 func importColumnType(engine string, inferred string) string {
 	switch strings.ToLower(engine) {
-	case "postgres", "postgresql":
+	case "postgres", "postgresql", database.DriverCockroachDB, database.DriverYugabyteDB:
 		switch inferred {
 		case "integer":
 			return "BIGINT"
 		case "number":
 			return "DOUBLE PRECISION"
+		case "decimal":
+			return "NUMERIC"
 		case "boolean":
 			return "BOOLEAN"
+		case "date":
+			return "DATE"
 		case "datetime":
 			return "TIMESTAMPTZ"
+		case "binary":
+			return "BYTEA"
 		default:
 			return "TEXT"
 		}
@@ -606,10 +739,16 @@ func importColumnType(engine string, inferred string) string {
 			return "BIGINT"
 		case "number":
 			return "DOUBLE"
+		case "decimal":
+			return "DECIMAL(65,30)"
 		case "boolean":
 			return "BOOLEAN"
+		case "date":
+			return "DATE"
 		case "datetime":
 			return "DATETIME"
+		case "binary":
+			return "LONGBLOB"
 		default:
 			return "TEXT"
 		}
@@ -619,10 +758,16 @@ func importColumnType(engine string, inferred string) string {
 			return "NUMBER(19)"
 		case "number":
 			return "BINARY_DOUBLE"
+		case "decimal":
+			return "NUMBER"
 		case "boolean":
 			return "NUMBER(1)"
+		case "date":
+			return "DATE"
 		case "datetime":
 			return "TIMESTAMP WITH TIME ZONE"
+		case "binary":
+			return "BLOB"
 		default:
 			return "CLOB"
 		}
@@ -632,10 +777,16 @@ func importColumnType(engine string, inferred string) string {
 			return "BIGINT"
 		case "number":
 			return "FLOAT"
+		case "decimal":
+			return "DECIMAL(38,10)"
 		case "boolean":
 			return "BIT"
+		case "date":
+			return "DATE"
 		case "datetime":
 			return "DATETIMEOFFSET"
+		case "binary":
+			return "VARBINARY(MAX)"
 		default:
 			return "NVARCHAR(MAX)"
 		}
@@ -645,7 +796,10 @@ func importColumnType(engine string, inferred string) string {
 			return "INTEGER"
 		case "number":
 			return "REAL"
+		case "binary":
+			return "BLOB"
 		default:
+			// Exact decimals stay text so REAL affinity cannot round them.
 			return "TEXT"
 		}
 	}
@@ -709,6 +863,12 @@ func coerceImportedValue(value interface{}, inferred string) (interface{}, error
 			return nil, fmt.Errorf("expected finite number, got %q", text)
 		}
 		return number, nil
+	case "decimal":
+		trimmed := strings.TrimSpace(text)
+		if _, ok := new(big.Rat).SetString(trimmed); !ok || strings.Contains(trimmed, "/") {
+			return nil, fmt.Errorf("expected decimal number, got %q", text)
+		}
+		return trimmed, nil
 	case "boolean":
 		value, err := strconv.ParseBool(strings.TrimSpace(text))
 		if err != nil {
@@ -721,6 +881,14 @@ func coerceImportedValue(value interface{}, inferred string) (interface{}, error
 			return nil, fmt.Errorf("expected RFC 3339 date/time, got %q", text)
 		}
 		return value, nil
+	case "date":
+		value, err := time.Parse(time.DateOnly, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("expected YYYY-MM-DD date, got %q", text)
+		}
+		return value, nil
+	case "binary":
+		return []byte(text), nil
 	default:
 		return text, nil
 	}
@@ -746,9 +914,84 @@ func importBatchRowLimit(engine string, columnCount int) int {
 	return max(1, min(importBatchSize, parameterLimit/columnCount))
 }
 
+// coerceImportRow converts one source record to the mapped columns' values,
+// in column order. Every write path uses it so bulk loads and INSERT batches
+// accept exactly the same input.
+func coerceImportRow(
+	engine string,
+	columns []database.ImportColumn,
+	row map[string]interface{},
+) ([]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	for index, column := range columns {
+		value, err := coerceImportedValue(row[column.SourceName], column.InferredType)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.SourceName, err)
+		}
+		if engine == database.DriverOracle && column.InferredType == "boolean" {
+			if boolean, ok := value.(bool); ok {
+				if boolean {
+					value = int64(1)
+				} else {
+					value = int64(0)
+				}
+			}
+		}
+		values[index] = value
+	}
+	return values, nil
+}
+
+// importBulkRows feeds a BulkLoader from the source file. Parse failures are
+// kept apart from coercion failures so they are reported as a bad file.
+type importBulkRows struct {
+	reader  importRecordReader
+	columns []database.ImportColumn
+	engine  string
+	read    int
+	readErr error
+}
+
+func (rows *importBulkRows) Next() ([]interface{}, error) {
+	record, err := rows.reader.Next()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			rows.readErr = err
+		}
+		return nil, err
+	}
+	rows.read++
+	values, err := coerceImportRow(rows.engine, rows.columns, record)
+	if err != nil {
+		return nil, fmt.Errorf("row %d %w", rows.read, err)
+	}
+	return values, nil
+}
+
+func importBulkColumns(columns []database.ImportColumn) []database.BulkLoadColumn {
+	bulkColumns := make([]database.BulkLoadColumn, len(columns))
+	for index, column := range columns {
+		bulkColumns[index] = database.BulkLoadColumn{
+			Name: column.TargetName,
+			Type: column.InferredType,
+		}
+	}
+	return bulkColumns
+}
+
+// importExecutor is the transaction rows are written through. Logical
+// restores on engines without transactions pass the driver itself.
+type importExecutor interface {
+	ExecuteQuery(
+		ctx context.Context,
+		query string,
+		options database.QueryOptions,
+	) (database.QueryResult, error)
+}
+
 func flushImportRows(
 	ctx context.Context,
-	transaction database.Transaction,
+	transaction importExecutor,
 	driver database.CapabilityDriver,
 	schema string,
 	table string,
@@ -767,28 +1010,13 @@ func flushImportRows(
 	engine := strings.ToLower(driver.Capabilities().Engine)
 	position := 1
 	for rowIndex, row := range rows {
+		values, err := coerceImportRow(engine, columns, row)
+		if err != nil {
+			return fmt.Errorf("row %d %w", rowIndex+1, err)
+		}
 		placeholders := make([]string, len(columns))
-		for columnIndex, column := range columns {
-			value, err := coerceImportedValue(row[column.SourceName], column.InferredType)
-			if err != nil {
-				return fmt.Errorf(
-					"row %d column %s: %w",
-					rowIndex+1,
-					column.SourceName,
-					err,
-				)
-			}
-			if engine == database.DriverOracle &&
-				column.InferredType == "boolean" {
-				if boolean, ok := value.(bool); ok {
-					if boolean {
-						value = int64(1)
-					} else {
-						value = int64(0)
-					}
-				}
-			}
-			args = append(args, value)
+		for columnIndex := range columns {
+			args = append(args, values[columnIndex])
 			placeholders[columnIndex] = driver.Placeholder(position)
 			position++
 		}
@@ -957,9 +1185,47 @@ func (s *Service) ImportData(
 	}
 
 	inserted := 0
+	bulkLoaded := false
+	if loader, ok := transaction.(database.BulkLoader); ok {
+		rows := &importBulkRows{
+			reader:  reader,
+			columns: columns,
+			engine:  strings.ToLower(capabilities.Engine),
+		}
+		loaded, err := loader.BulkLoad(
+			ctx,
+			database.Table{Schema: request.Schema, Name: request.Table},
+			importBulkColumns(columns),
+			rows,
+		)
+		switch {
+		case errors.Is(err, database.ErrBulkLoadUnavailable) && rows.read == 0 && rows.readErr == nil:
+			// The server refused the fast path before any row was sent, so
+			// the batched INSERT loop below reads the file from the start.
+		case rows.readErr != nil:
+			return serviceErrorWithCode[database.ImportResult](
+				http.StatusBadRequest,
+				errorCodeInvalidRequest,
+				"Could not parse import file",
+				rows.readErr.Error(),
+				"No rows were committed. Check the source format and try again.",
+			)
+		case err != nil:
+			return serviceErrorWithCode[database.ImportResult](
+				http.StatusConflict,
+				errorCodeDatabaseOperationFailed,
+				"Could not import rows",
+				err.Error(),
+				"No imported rows were committed. Review type mappings and constraints.",
+			)
+		default:
+			inserted = int(loaded)
+			bulkLoaded = true
+		}
+	}
 	batchSize := importBatchRowLimit(capabilities.Engine, len(columns))
 	batch := make([]map[string]interface{}, 0, batchSize)
-	for {
+	for !bulkLoaded {
 		row, readErr := reader.Next()
 		if errors.Is(readErr, io.EOF) {
 			break
@@ -1030,6 +1296,7 @@ func (s *Service) ImportData(
 			RowsInserted: inserted,
 			TableCreated: tableCreated,
 			Warnings:     warnings,
+			BulkLoaded:   bulkLoaded,
 		},
 	}
 }
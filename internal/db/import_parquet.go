@@ -0,0 +1,326 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"github.com/google/uuid"
+)
+
+const parquetImportBatch = 1024
+
+// importSchemaReader is implemented by sources that declare their column
+// types. The preview reports those columns instead of inferring types from
+// the sampled values.
+type importSchemaReader interface {
+	DeclaredColumns() []database.ImportColumn
+}
+
+type parquetImportColumn struct {
+	descriptor *schema.Column
+	importType string
+}
+
+// parquetImportReader decodes one row group at a time, so memory follows the
+// row group size rather than the file size.
+type parquetImportReader struct {
+	reader  *file.Reader
+	columns []parquetImportColumn
+	names   []string
+	group   int
+	values  [][]interface{}
+	row     int
+}
+
+func newParquetImportReader(source *os.File) (*parquetImportReader, error) {
+	reader, err := file.NewParquetReader(source)
+	if err != nil {
+		return nil, fmt.Errorf("read Parquet footer: %w", err)
+	}
+	fileSchema := reader.MetaData().Schema
+	columns := make([]parquetImportColumn, fileSchema.NumColumns())
+	names := make([]string, len(columns))
+	for index := range columns {
+		descriptor := fileSchema.Column(index)
+		if descriptor.MaxRepetitionLevel() > 0 || strings.Contains(descriptor.Path(), ".") {
+			_ = reader.Close()
+			return nil, fmt.Errorf(
+				"nested Parquet column %q cannot be imported into a table",
+				descriptor.Path(),
+			)
+		}
+		columns[index] = parquetImportColumn{
+			descriptor: descriptor,
+			importType: parquetImportType(descriptor),
+		}
+		names[index] = descriptor.Name()
+	}
+	return &parquetImportReader{reader: reader, columns: columns, names: names}, nil
+}
+
+func parquetImportType(descriptor *schema.Column) string {
+	switch logical := descriptor.LogicalType().(type) {
+	case schema.DecimalLogicalType:
+		return "decimal"
+	case schema.DateLogicalType:
+		return "date"
+	case schema.TimestampLogicalType:
+		return "datetime"
+	case schema.TimeLogicalType, schema.StringLogicalType, schema.JSONLogicalType,
+		schema.EnumLogicalType, schema.UUIDLogicalType:
+		return "text"
+	case schema.IntLogicalType:
+		// Unsigned 64-bit values can exceed every signed BIGINT column.
+		if !logical.IsSigned() && logical.BitWidth() == 64 {
+			return "decimal"
+		}
+	}
+	switch descriptor.PhysicalType() {
+	case parquet.Types.Boolean:
+		return "boolean"
+	case parquet.Types.Int32, parquet.Types.Int64:
+		return "integer"
+	case parquet.Types.Int96:
+		return "datetime"
+	case parquet.Types.Float, parquet.Types.Double:
+		return "number"
+	default:
+		return "binary"
+	}
+}
+
+func (reader *parquetImportReader) Columns() []string {
+	return append([]string(nil), reader.names...)
+}
+
+func (reader *parquetImportReader) DeclaredColumns() []database.ImportColumn {
+	columns := make([]database.ImportColumn, len(reader.columns))
+	for index, column := range reader.columns {
+		columns[index] = database.ImportColumn{
+			SourceName:   reader.names[index],
+			TargetName:   reader.names[index],
+			InferredType: column.importType,
+			Nullable:     column.descriptor.MaxDefinitionLevel() > 0,
+			Included:     true,
+		}
+	}
+	return columns
+}
+
+func (reader *parquetImportReader) Close() error {
+	return reader.reader.Close()
+}
+
+func (reader *parquetImportReader) Next() (map[string]interface{}, error) {
+	for len(reader.values) == 0 || reader.row >= len(reader.values[0]) {
+		if reader.group >= reader.reader.NumRowGroups() {
+			return nil, io.EOF
+		}
+		if err := reader.readGroup(); err != nil {
+			return nil, err
+		}
+	}
+	row := make(map[string]interface{}, len(reader.names))
+	for index, name := range reader.names {
+		row[name] = reader.values[index][reader.row]
+	}
+	reader.row++
+	return row, nil
+}
+
+func (reader *parquetImportReader) readGroup() error {
+	group := reader.reader.RowGroup(reader.group)
+	reader.group++
+	reader.row = 0
+	rows := group.NumRows()
+	reader.values = make([][]interface{}, len(reader.columns))
+	for index, column := range reader.columns {
+		chunk, err := group.Column(index)
+		if err != nil {
+			return fmt.Errorf("open Parquet column %q: %w", reader.names[index], err)
+		}
+		values, err := readParquetImportChunk(chunk, column.descriptor, rows)
+		if err != nil {
+			return fmt.Errorf(
+				"read Parquet column %q in row group %d: %w",
+				reader.names[index],
+				reader.group,
+				err,
+			)
+		}
+		reader.values[index] = values
+	}
+	return nil
+}
+
+func readParquetImportChunk(
+	chunk file.ColumnChunkReader,
+	descriptor *schema.Column,
+	rows int64,
+) ([]interface{}, error) {
+	maxDefinition := descriptor.MaxDefinitionLevel()
+	switch typed := chunk.(type) {
+	case *file.BooleanColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value bool) interface{} {
+			return value
+		})
+	case *file.Int32ColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value int32) interface{} {
+			return parquetImportInteger(descriptor, int64(value))
+		})
+	case *file.Int64ColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value int64) interface{} {
+			return parquetImportInteger(descriptor, value)
+		})
+	case *file.Int96ColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value parquet.Int96) interface{} {
+			return value.ToTime().UTC()
+		})
+	case *file.Float32ColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value float32) interface{} {
+			return float64(value)
+		})
+	case *file.Float64ColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value float64) interface{} {
+			return value
+		})
+	case *file.ByteArrayColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value parquet.ByteArray) interface{} {
+			return parquetImportBytes(descriptor, value)
+		})
+	case *file.FixedLenByteArrayColumnChunkReader:
+		return readParquetValues(rows, maxDefinition, typed.ReadBatch, func(value parquet.FixedLenByteArray) interface{} {
+			return parquetImportBytes(descriptor, value)
+		})
+	default:
+		return nil, fmt.Errorf("unsupported Parquet column reader %T", chunk)
+	}
+}
+
+// readParquetValues reads rows values from a column chunk. Null entries
+// have no physical value, so values and definition levels advance
+// separately.
+func readParquetValues[T any](
+	rows int64,
+	maxDefinition int16,
+	read func(int64, []T, []int16, []int16) (int64, int, error),
+	convert func(T) interface{},
+) ([]interface{}, error) {
+	result := make([]interface{}, 0, rows)
+	values := make([]T, parquetImportBatch)
+	levels := make([]int16, parquetImportBatch)
+	for int64(len(result)) < rows {
+		total, _, err := read(parquetImportBatch, values, levels, nil)
+		if err != nil {
+			return nil, err
+		}
+		if total == 0 {
+			break
+		}
+		next := 0
+		for index := int64(0); index < total; index++ {
+			if maxDefinition > 0 && levels[index] < maxDefinition {
+				result = append(result, nil)
+				continue
+			}
+			result = append(result, convert(values[next]))
+			next++
+		}
+	}
+	if int64(len(result)) != rows {
+		return nil, fmt.Errorf("column has %d of %d rows", len(result), rows)
+	}
+	return result, nil
+}
+
+func parquetImportInteger(descriptor *schema.Column, value int64) interface{} {
+	switch logical := descriptor.LogicalType().(type) {
+	case schema.DecimalLogicalType:
+		return formatParquetDecimal(big.NewInt(value), logical.Scale())
+	case schema.DateLogicalType:
+		return time.Unix(0, 0).UTC().AddDate(0, 0, int(value))
+	case schema.TimestampLogicalType:
+		return parquetImportTime(value, logical.TimeUnit())
+	case schema.TimeLogicalType:
+		return parquetImportTimeOfDay(value, logical.TimeUnit())
+	case schema.IntLogicalType:
+		return parquetImportUnsigned(logical.IsSigned(), logical.BitWidth(), value)
+	}
+	return value
+}
+
+func parquetImportUnsigned(signed bool, width int8, value int64) interface{} {
+	switch {
+	case signed:
+		return value
+	case width == 64:
+		return new(big.Int).SetUint64(uint64(value)).String()
+	case width == 32:
+		return int64(uint32(value))
+	default:
+		return value
+	}
+}
+
+func parquetImportTime(value int64, unit schema.TimeUnitType) time.Time {
+	switch unit {
+	case schema.TimeUnitMillis:
+		return time.UnixMilli(value).UTC()
+	case schema.TimeUnitNanos:
+		return time.Unix(0, value).UTC()
+	default:
+		return time.UnixMicro(value).UTC()
+	}
+}
+
+func parquetImportTimeOfDay(value int64, unit schema.TimeUnitType) string {
+	return parquetImportTime(value, unit).Format("15:04:05.999999999")
+}
+
+func parquetImportBytes(descriptor *schema.Column, value []byte) interface{} {
+	switch logical := descriptor.LogicalType().(type) {
+	case schema.DecimalLogicalType:
+		return formatParquetDecimal(parquetUnscaledDecimal(value), logical.Scale())
+	case schema.UUIDLogicalType:
+		if id, err := uuid.FromBytes(value); err == nil {
+			return id.String()
+		}
+	case schema.StringLogicalType, schema.JSONLogicalType, schema.EnumLogicalType:
+		return string(value)
+	}
+	// Byte slices point into the page buffer, which the next read reuses.
+	return bytes.Clone(value)
+}
+
+// parquetUnscaledDecimal decodes a big-endian two's complement integer.
+func parquetUnscaledDecimal(value []byte) *big.Int {
+	unscaled := new(big.Int).SetBytes(value)
+	if len(value) > 0 && value[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(value)*8)))
+	}
+	return unscaled
+}
+
+func formatParquetDecimal(unscaled *big.Int, scale int32) string {
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if padding := int(scale) + 1 - len(digits); padding > 0 {
+			digits = strings.Repeat("0", padding) + digits
+		}
+		digits = digits[:len(digits)-int(scale)] + "." + digits[len(digits)-int(scale):]
+	}
+	if unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
@@ -2,14 +2,21 @@ package db
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"rollingthunder/pkg/database"
 	oracledriver "rollingthunder/pkg/database/oracle"
+	"rollingthunder/pkg/response"
+
+	"github.com/xuri/excelize/v2"
 
 	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -134,7 +141,7 @@ func TestCSVImportPreviewAndCreateTable(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("select errors = %+v", result.Errors)
 	}
-	if len(result.Data.Rows) != 2 || result.Data.Rows[1]["name"] != "Linus" {
+	if len(result.Data.Rows) != 2 || result.Data.RowMaps()[1]["name"] != "Linus" {
 		t.Fatalf("rows = %+v", result.Data.Rows)
 	}
 }
@@ -287,8 +294,8 @@ func TestJSONLinesImportIntoExistingTable(t *testing.T) {
 	if len(result.Errors) > 0 || len(result.Data.Rows) != 2 {
 		t.Fatalf("select = %+v", result)
 	}
-	if result.Data.Rows[0]["payload"] != `{"kind":"rain"}` {
-		t.Fatalf("normalized JSON payload = %#v", result.Data.Rows[0]["payload"])
+	if result.Data.RowMaps()[0]["payload"] != `{"kind":"rain"}` {
+		t.Fatalf("normalized JSON payload = %#v", result.Data.RowMaps()[0]["payload"])
 	}
 }
 
@@ -329,7 +336,383 @@ func TestImportRollsBackAllRowsWhenAValueFailsCoercion(t *testing.T) {
 		ConnectionID: connectionID,
 		Query:        "SELECT COUNT(*) AS count FROM main.numbers",
 	})
-	if len(count.Errors) > 0 || count.Data.Rows[0]["count"] != int64(0) {
+	if len(count.Errors) > 0 || count.Data.RowMaps()[0]["count"] != int64(0) {
+		t.Fatalf("rolled-back count = %+v", count)
+	}
+}
+
+// bulkLoadTestDriver gives the SQLite driver a bulk-load transaction that
+// records the coerced rows before inserting them one by one.
+type bulkLoadTestDriver struct {
+	database.Driver
+	unavailable bool
+	loaded      [][]interface{}
+}
+
+func (driver *bulkLoadTestDriver) BeginTransaction(
+	ctx context.Context,
+) (database.Transaction, error) {
+	transaction, err := driver.Driver.(database.TransactionalDriver).BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkLoadTestTransaction{Transaction: transaction, driver: driver}, nil
+}
+
+type bulkLoadTestTransaction struct {
+	database.Transaction
+	driver *bulkLoadTestDriver
+}
+
+func (transaction *bulkLoadTestTransaction) BulkLoad(
+	ctx context.Context,
+	table database.Table,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	if transaction.driver.unavailable {
+		return 0, database.ErrBulkLoadUnavailable
+	}
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.Name
+		placeholders[index] = "?"
+	}
+	statement := "INSERT INTO " + table.Schema + "." + table.Name +
+		" (" + strings.Join(names, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+	var loaded int64
+	for {
+		values, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return loaded, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		transaction.driver.loaded = append(transaction.driver.loaded, values)
+		if _, err := transaction.ExecuteQuery(ctx, statement, database.QueryOptions{
+			Args: values,
+		}); err != nil {
+			return 0, err
+		}
+		loaded++
+	}
+}
+
+func newBulkLoadImportService(t *testing.T) (*Service, string, *bulkLoadTestDriver) {
+	t.Helper()
+	service, connectionID := newSQLiteImportService(t)
+	created := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "CREATE TABLE main.readings (id INTEGER NOT NULL, taken_on TEXT, note TEXT)",
+	})
+	if len(created.Errors) > 0 {
+		t.Fatalf("create errors = %+v", created.Errors)
+	}
+	connection := service.connections[connectionID]
+	driver := &bulkLoadTestDriver{Driver: connection.Driver}
+	connection.Driver = driver
+	t.Cleanup(func() { connection.Driver = driver.Driver })
+	return service, connectionID, driver
+}
+
+func importReadings(
+	t *testing.T,
+	service *Service,
+	connectionID string,
+	content string,
+) response.BaseResponse[database.ImportResult] {
+	t.Helper()
+	source := filepath.Join(t.TempDir(), "readings.csv")
+	if err := os.WriteFile(source, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	selected := selectImportTestFile(t, service, source)
+	return service.ImportData(database.ImportRequest{
+		ConnectionID: connectionID,
+		Token:        selected.Token,
+		Options: database.ImportOptions{
+			Format:      "csv",
+			Delimiter:   ",",
+			Header:      true,
+			EmptyAsNull: true,
+		},
+		Schema: "main",
+		Table:  "readings",
+		Columns: []database.ImportColumn{
+			{SourceName: "id", TargetName: "id", InferredType: "integer", Included: true},
+			{SourceName: "day", TargetName: "taken_on", InferredType: "date", Nullable: true, Included: true},
+			{SourceName: "note", TargetName: "note", InferredType: "text", Nullable: true, Included: true},
+		},
+	})
+}
+
+func TestImportUsesBulkLoaderWithTheSameCoercion(t *testing.T) {
+	service, connectionID, driver := newBulkLoadImportService(t)
+	imported := importReadings(t, service, connectionID, "id,day,note\n1,2026-05-01,dry\n2,,\n")
+	if len(imported.Errors) > 0 {
+		t.Fatalf("import errors = %+v", imported.Errors)
+	}
+	if !imported.Data.BulkLoaded || imported.Data.RowsInserted != 2 {
+		t.Fatalf("import result = %+v", imported.Data)
+	}
+	want := [][]interface{}{
+		{int64(1), time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), "dry"},
+		{int64(2), nil, nil},
+	}
+	if !reflect.DeepEqual(driver.loaded, want) {
+		t.Fatalf("bulk-loaded rows = %#v, want %#v", driver.loaded, want)
+	}
+}
+
+func TestBulkLoadCoercionFailureNamesTheRowAndRollsBack(t *testing.T) {
+	service, connectionID, _ := newBulkLoadImportService(t)
+	imported := importReadings(t, service, connectionID, "id,day,note\n1,,\n2,,\nthree,,\n")
+	if len(imported.Errors) == 0 {
+		t.Fatal("ImportData() unexpectedly succeeded")
+	}
+	if detail := imported.Errors[0].Detail; !strings.Contains(detail, "row 3 column id") {
+		t.Fatalf("error detail = %q", detail)
+	}
+	count := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "SELECT COUNT(*) AS count FROM main.readings",
+	})
+	if len(count.Errors) > 0 || count.Data.RowMaps()[0]["count"] != int64(0) {
 		t.Fatalf("rolled-back count = %+v", count)
 	}
 }
+
+func TestImportFallsBackToInsertsWhenBulkLoadIsUnavailable(t *testing.T) {
+	service, connectionID, driver := newBulkLoadImportService(t)
+	driver.unavailable = true
+	imported := importReadings(t, service, connectionID, "id,day,note\n1,2026-05-01,dry\n2,,\n")
+	if len(imported.Errors) > 0 {
+		t.Fatalf("import errors = %+v", imported.Errors)
+	}
+	if imported.Data.BulkLoaded || imported.Data.RowsInserted != 2 {
+		t.Fatalf("import result = %+v", imported.Data)
+	}
+}
+
+func TestParquetImportUsesFileSchemaTypes(t *testing.T) {
+	service, connectionID := newSQLiteImportService(t)
+	source := filepath.Join(t.TempDir(), "orders.parquet")
+	output, err := os.Create(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = database.WriteTypedExportRowsContext(
+		context.Background(),
+		output,
+		[]string{"id", "total", "placed_on", "note"},
+		[]map[string]interface{}{
+			{"id": int64(1), "total": "12.50", "placed_on": "2026-05-01", "note": nil},
+			{"id": int64(2), "total": "0.05", "placed_on": "2026-05-02", "note": "007"},
+		},
+		database.Structures{
+			{Name: "id", DataType: "bigint"},
+			{Name: "total", DataType: "numeric(10,2)"},
+			{Name: "placed_on", DataType: "date"},
+			{Name: "note", DataType: "text", Nullable: true},
+		},
+		database.ExportOptions{Format: database.ExportFormatParquet},
+	)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.Fatalf("write Parquet source: %v", err)
+	}
+
+	selected := selectImportTestFile(t, service, source)
+	preview := service.InspectImportFile(database.ImportPreviewRequest{Token: selected.Token})
+	if len(preview.Errors) > 0 {
+		t.Fatalf("InspectImportFile() errors = %+v", preview.Errors)
+	}
+	wantTypes := []string{"integer", "decimal", "datetime", "text"}
+	if len(preview.Data.Columns) != len(wantTypes) || preview.Data.Sampled != 2 {
+		t.Fatalf("preview = %+v", preview.Data)
+	}
+	for index, column := range preview.Data.Columns {
+		if column.InferredType != wantTypes[index] || column.Nullable != (index == 3) {
+			t.Fatalf("column %d = %+v", index, column)
+		}
+	}
+	if preview.Data.Rows[1]["total"] != "0.05" {
+		t.Fatalf("decimal preview value = %#v", preview.Data.Rows[1]["total"])
+	}
+
+	imported := service.ImportData(database.ImportRequest{
+		ConnectionID: connectionID,
+		Token:        selected.Token,
+		Schema:       "main",
+		Table:        "orders",
+		CreateTable:  true,
+		Columns:      preview.Data.Columns,
+	})
+	if len(imported.Errors) > 0 || imported.Data.RowsInserted != 2 {
+		t.Fatalf("ImportData() = %+v", imported)
+	}
+	result := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "SELECT total, note FROM main.orders ORDER BY id",
+	})
+	if len(result.Errors) > 0 || len(result.Data.Rows) != 2 {
+		t.Fatalf("select = %+v", result)
+	}
+	if rows := result.Data.RowMaps(); rows[0]["total"] != "12.50" || rows[1]["note"] != "007" {
+		t.Fatalf("rows = %+v", rows)
+	}
+}
+
+func TestXLSXImportReadsSelectedSheetWithCellTypes(t *testing.T) {
+	service, connectionID := newSQLiteImportService(t)
+	workbook := excelize.NewFile()
+	if err := workbook.SetSheetName("Sheet1", "Notes"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workbook.NewSheet("Orders"); err != nil {
+		t.Fatal(err)
+	}
+	for cell, value := range map[string]interface{}{
+		"A1":        "note",
+		"A2":        "ignored",
+		"A1@Orders": "id", "B1@Orders": "code", "C1@Orders": "placed_on",
+		"D1@Orders": "paid", "E1@Orders": "amount",
+		"A2@Orders": 1, "B2@Orders": "007",
+		"C2@Orders": time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC),
+		"D2@Orders": true, "E2@Orders": 12.5,
+		"A4@Orders": 2, "B4@Orders": "",
+		"C4@Orders": time.Date(2026, time.May, 2, 0, 0, 0, 0, time.UTC),
+		"D4@Orders": false, "E4@Orders": 3,
+	} {
+		sheet := "Notes"
+		if name, target, ok := strings.Cut(cell, "@"); ok {
+			cell, sheet = name, target
+		}
+		if err := workbook.SetCellValue(sheet, cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := filepath.Join(t.TempDir(), "orders.xlsx")
+	if err := workbook.SaveAs(source); err != nil {
+		t.Fatal(err)
+	}
+	_ = workbook.Close()
+
+	selected := selectImportTestFile(t, service, source)
+	options := database.ImportOptions{Header: true, EmptyAsNull: true, Sheet: "Orders"}
+	preview := service.InspectImportFile(database.ImportPreviewRequest{
+		Token:   selected.Token,
+		Options: options,
+	})
+	if len(preview.Errors) > 0 {
+		t.Fatalf("InspectImportFile() errors = %+v", preview.Errors)
+	}
+	if strings.Join(preview.Data.Sheets, ",") != "Notes,Orders" {
+		t.Fatalf("sheets = %v", preview.Data.Sheets)
+	}
+	wantTypes := []string{"integer", "text", "date", "boolean", "number"}
+	if len(preview.Data.Columns) != len(wantTypes) || preview.Data.Sampled != 2 {
+		t.Fatalf("preview = %+v", preview.Data)
+	}
+	for index, column := range preview.Data.Columns {
+		if column.InferredType != wantTypes[index] {
+			t.Fatalf("column %d = %+v", index, column)
+		}
+	}
+	first := preview.Data.Rows[0]
+	if first["code"] != "007" || first["id"] != int64(1) || first["paid"] != true {
+		t.Fatalf("first row = %#v", first)
+	}
+	if placed, ok := first["placed_on"].(time.Time); !ok ||
+		!placed.Equal(time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("placed_on = %#v", first["placed_on"])
+	}
+	if preview.Data.Rows[1]["code"] != nil {
+		t.Fatalf("empty cell = %#v", preview.Data.Rows[1]["code"])
+	}
+
+	imported := service.ImportData(database.ImportRequest{
+		ConnectionID: connectionID,
+		Token:        selected.Token,
+		Options:      options,
+		Schema:       "main",
+		Table:        "orders",
+		CreateTable:  true,
+		Columns:      preview.Data.Columns,
+	})
+	if len(imported.Errors) > 0 || imported.Data.RowsInserted != 2 {
+		t.Fatalf("ImportData() = %+v", imported)
+	}
+	result := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "SELECT code FROM main.orders ORDER BY id",
+	})
+	if len(result.Errors) > 0 || len(result.Data.Rows) != 2 {
+		t.Fatalf("select = %+v", result)
+	}
+	if rows := result.Data.RowMaps(); rows[0]["code"] != "007" || rows[1]["code"] != nil {
+		t.Fatalf("rows = %+v", rows)
+	}
+}
+
+func TestXLSXDateFormatDetection(t *testing.T) {
+	for code, want := range map[string]bool{
+		"yyyy-mm-dd":                 true,
+		"[$-409]d-mmm-yy":            true,
+		"hh:mm:ss":                   true,
+		"[h]:mm":                     false,
+		"0.00":                       false,
+		`"days "0`:                   false,
+		`#,##0.00_);[Red](#,##0.00)`: false,
+	} {
+		if got := isXLSXDateFormat(code); got != want {
+			t.Fatalf("isXLSXDateFormat(%q) = %v, want %v", code, got, want)
+		}
+	}
+	if got := xlsxSerialTime(45000.5, false); !got.Equal(time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("serial 45000.5 = %s", got)
+	}
+}
+
+func TestJSONImportReportsWhereTheFirstMalformedRecordStarts(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+		rows  int
+		want  string
+	}{
+		{
+			name:  "ndjson",
+			input: "{\"id\":1}\n\n  {\"id\":2,}\n{\"id\":3}\n",
+			rows:  1,
+			want:  "line 3 (byte offset 12)",
+		},
+		{
+			name:  "array",
+			input: "[\n  {\"id\":1},\n  {\"id\":2},\n  \"oops\"\n]",
+			rows:  2,
+			want:  "line 4 (byte offset 28)",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// One byte per read keeps the decoder's read-ahead small, so
+			// positions are tracked across many refills.
+			reader, err := newJSONImportReader(iotest.OneByteReader(strings.NewReader(test.input)))
+			if err != nil {
+				t.Fatalf("newJSONImportReader() error = %v", err)
+			}
+			for row := 0; row < test.rows; row++ {
+				if _, err := reader.Next(); err != nil {
+					t.Fatalf("row %d error = %v", row+1, err)
+				}
+			}
+			if _, err := reader.Next(); err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("malformed record error = %v, want %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,490 @@
+package db
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+)
+
+// xlsxMaxColumns is the widest worksheet Excel can produce. Cell references
+// beyond it are rejected rather than allocated.
+const xlsxMaxColumns = 16384
+
+// importSheetReader is implemented by workbook sources so the preview can
+// offer their worksheets.
+type importSheetReader interface {
+	Sheets() []string
+}
+
+type xlsxRelationship struct {
+	ID     string `xml:"Id,attr"`
+	Type   string `xml:"Type,attr"`
+	Target string `xml:"Target,attr"`
+}
+
+type xlsxWorkbookPart struct {
+	Properties struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxStylesPart struct {
+	NumberFormats []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellFormats []struct {
+		NumberFormat int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// xlsxText is a shared or inline string: plain text or rich-text runs.
+// Phonetic runs are not part of the value and are left out.
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (text xlsxText) String() string {
+	if len(text.Runs) == 0 {
+		return text.Text
+	}
+	var builder strings.Builder
+	builder.WriteString(text.Text)
+	for _, run := range text.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+type xlsxCell struct {
+	Ref    string    `xml:"r,attr"`
+	Style  int       `xml:"s,attr"`
+	Type   string    `xml:"t,attr"`
+	Value  string    `xml:"v"`
+	Inline *xlsxText `xml:"is"`
+}
+
+// xlsxImportReader streams one worksheet of a workbook. excelize's row
+// iterator returns formatted text without the cell type, which would make a
+// text cell holding 007 indistinguishable from the number 7, so the
+// worksheet XML is decoded here directly.
+type xlsxImportReader struct {
+	file       *os.File
+	sheets     []string
+	strings    []string
+	dateStyles map[int]bool
+	date1904   bool
+	part       io.ReadCloser
+	decoder    *xml.Decoder
+	options    database.ImportOptions
+	columns    []string
+	pending    []interface{}
+	done       bool
+}
+
+func newXLSXImportReader(
+	file *os.File,
+	options database.ImportOptions,
+) (*xlsxImportReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("the file is not an Excel workbook: %w", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, entry := range archive.File {
+		parts[strings.TrimPrefix(entry.Name, "/")] = entry
+	}
+
+	var workbook xlsxWorkbookPart
+	if err := decodeXLSXPart(parts, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var relationships struct {
+		Items []xlsxRelationship `xml:"Relationship"`
+	}
+	if err := decodeXLSXPart(parts, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]xlsxRelationship, len(relationships.Items))
+	reader := &xlsxImportReader{
+		file:       file,
+		dateStyles: make(map[int]bool),
+		date1904:   workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true",
+		options:    options,
+	}
+	for _, relationship := range relationships.Items {
+		target := relationship.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		relationship.Target = target
+		targets[relationship.ID] = relationship
+		switch {
+		case strings.HasSuffix(relationship.Type, "/sharedStrings"):
+			if reader.strings, err = readXLSXSharedStrings(parts[target]); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(relationship.Type, "/styles"):
+			var styles xlsxStylesPart
+			if err := decodeXLSXPart(parts, target, &styles); err != nil {
+				return nil, err
+			}
+			custom := make(map[int]string, len(styles.NumberFormats))
+			for _, format := range styles.NumberFormats {
+				custom[format.ID] = format.Code
+			}
+			for index, format := range styles.CellFormats {
+				code, ok := custom[format.NumberFormat]
+				if ok && isXLSXDateFormat(code) || !ok && isXLSXBuiltInDateFormat(format.NumberFormat) {
+					reader.dateStyles[index] = true
+				}
+			}
+		}
+	}
+
+	selected := ""
+	for _, sheet := range workbook.Sheets {
+		relationship, ok := targets[sheet.ID]
+		if !ok || !strings.HasSuffix(relationship.Type, "/worksheet") {
+			continue
+		}
+		reader.sheets = append(reader.sheets, sheet.Name)
+		if selected == "" && (options.Sheet == "" || options.Sheet == sheet.Name) {
+			selected = relationship.Target
+		}
+	}
+	if len(reader.sheets) == 0 {
+		return nil, fmt.Errorf("the workbook has no worksheets")
+	}
+	if selected == "" {
+		return nil, fmt.Errorf("the workbook has no worksheet named %q", options.Sheet)
+	}
+	entry, ok := parts[selected]
+	if !ok {
+		return nil, fmt.Errorf("the workbook is missing worksheet part %s", selected)
+	}
+	if reader.part, err = entry.Open(); err != nil {
+		return nil, fmt.Errorf("open worksheet: %w", err)
+	}
+	reader.decoder = xml.NewDecoder(reader.part)
+
+	first, err := reader.nextRow()
+	if err != nil {
+		_ = reader.part.Close()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the worksheet is empty")
+		}
+		return nil, err
+	}
+	if options.Header {
+		names := make([]string, len(first))
+		for index, value := range first {
+			if value != nil {
+				names[index] = fmt.Sprint(value)
+			}
+		}
+		reader.columns = uniqueImportColumns(names)
+	} else {
+		reader.columns = make([]string, len(first))
+		for index := range first {
+			reader.columns[index] = fmt.Sprintf("column_%d", index+1)
+		}
+		reader.pending = first
+	}
+	return reader, nil
+}
+
+func decodeXLSXPart(parts map[string]*zip.File, name string, target interface{}) error {
+	entry, ok := parts[name]
+	if !ok {
+		return fmt.Errorf("the workbook is missing %s", name)
+	}
+	part, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer part.Close()
+	if err := xml.NewDecoder(part).Decode(target); err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+	return nil
+}
+
+func readXLSXSharedStrings(entry *zip.File) ([]string, error) {
+	if entry == nil {
+		return nil, nil
+	}
+	part, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open shared strings: %w", err)
+	}
+	defer part.Close()
+	decoder := xml.NewDecoder(part)
+	var values []string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read shared strings: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "si" {
+			var text xlsxText
+			if err := decoder.DecodeElement(&text, &start); err != nil {
+				return nil, fmt.Errorf("read shared strings: %w", err)
+			}
+			values = append(values, text.String())
+		}
+	}
+}
+
+// isXLSXBuiltInDateFormat reports the built-in number formats that display
+// dates or times, including the East Asian ones.
+func isXLSXBuiltInDateFormat(id int) bool {
+	return (id >= 14 && id <= 22) || (id >= 27 && id <= 36) ||
+		(id >= 45 && id <= 47) || (id >= 50 && id <= 58)
+}
+
+// isXLSXDateFormat reports whether a custom number format displays a date or
+// time. Quoted text, escaped characters, and bracketed colours or locales are
+// skipped; elapsed-time formats such as [h]:mm are durations, not dates.
+func isXLSXDateFormat(code string) bool {
+	code = strings.ToLower(code)
+	if section := strings.IndexByte(code, ';'); section >= 0 {
+		code = code[:section]
+	}
+	for index := 0; index < len(code); index++ {
+		switch character := code[index]; character {
+		case '"':
+			if end := strings.IndexByte(code[index+1:], '"'); end >= 0 {
+				index += end + 1
+			} else {
+				return false
+			}
+		case '\\', '_', '*':
+			index++
+		case '[':
+			end := strings.IndexByte(code[index:], ']')
+			if end < 0 {
+				return false
+			}
+			if elapsed := strings.Trim(code[index+1:index+end], "hms"); elapsed == "" {
+				return false
+			}
+			index += end
+		case 'y', 'm', 'd', 'h', 's':
+			return true
+		}
+	}
+	return false
+}
+
+// xlsxSerialTime converts a worksheet date serial number. The 1900 date
+// system counts a nonexistent 29 February 1900, so serials before 1 March
+// 1900 are shifted by a day.
+func xlsxSerialTime(serial float64, date1904 bool) time.Time {
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+	} else if serial < 61 {
+		epoch = epoch.AddDate(0, 0, 1)
+	}
+	days := math.Floor(serial)
+	milliseconds := math.Round((serial - days) * 24 * 60 * 60 * 1000)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(milliseconds) * time.Millisecond)
+}
+
+var xlsxISOLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+func (reader *xlsxImportReader) cellValue(cell xlsxCell) (interface{}, error) {
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+		if err != nil || index < 0 || index >= len(reader.strings) {
+			return nil, fmt.Errorf("cell %s refers to missing shared string %q", cell.Ref, cell.Value)
+		}
+		return reader.strings[index], nil
+	case "inlineStr":
+		if cell.Inline == nil {
+			return "", nil
+		}
+		return cell.Inline.String(), nil
+	case "str", "e":
+		return cell.Value, nil
+	case "b":
+		return strings.TrimSpace(cell.Value) == "1", nil
+	case "d":
+		for _, layout := range xlsxISOLayouts {
+			if moment, err := time.Parse(layout, strings.TrimSpace(cell.Value)); err == nil {
+				return moment, nil
+			}
+		}
+		return cell.Value, nil
+	}
+	text := strings.TrimSpace(cell.Value)
+	if text == "" {
+		return nil, nil
+	}
+	if integer, err := strconv.ParseInt(text, 10, 64); err == nil && !reader.dateStyles[cell.Style] {
+		return integer, nil
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cell %s has invalid number %q", cell.Ref, cell.Value)
+	}
+	if reader.dateStyles[cell.Style] {
+		return xlsxSerialTime(number, reader.date1904), nil
+	}
+	return number, nil
+}
+
+// xlsxColumnIndex returns the zero-based column of a cell reference such as
+// AB12, or -1 when the reference has no column letters.
+func xlsxColumnIndex(reference string) int {
+	column := 0
+	for index := 0; index < len(reference); index++ {
+		character := reference[index]
+		if character >= 'a' && character <= 'z' {
+			character -= 'a' - 'A'
+		}
+		if character < 'A' || character > 'Z' {
+			break
+		}
+		column = column*26 + int(character-'A'+1)
+		if column > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return column - 1
+}
+
+// nextRow returns the next row that has at least one value. Rows missing
+// from the worksheet, and cells missing from a row, are empty.
+func (reader *xlsxImportReader) nextRow() ([]interface{}, error) {
+	if reader.done {
+		return nil, io.EOF
+	}
+	var values []interface{}
+	inRow := false
+	for {
+		token, err := reader.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			reader.done = true
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read worksheet: %w", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "row":
+				inRow, values = true, values[:0]
+			case "c":
+				if !inRow {
+					continue
+				}
+				var cell xlsxCell
+				if err := reader.decoder.DecodeElement(&cell, &element); err != nil {
+					return nil, fmt.Errorf("read worksheet cell: %w", err)
+				}
+				column := len(values)
+				if cell.Ref != "" {
+					column = xlsxColumnIndex(cell.Ref)
+				}
+				if column < 0 || column >= xlsxMaxColumns {
+					return nil, fmt.Errorf("cell reference %q is outside the worksheet", cell.Ref)
+				}
+				value, err := reader.cellValue(cell)
+				if err != nil {
+					return nil, err
+				}
+				for len(values) <= column {
+					values = append(values, nil)
+				}
+				values[column] = value
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "row":
+				inRow = false
+				for _, value := range values {
+					if value != nil {
+						return values, nil
+					}
+				}
+			case "sheetData":
+				reader.done = true
+				return nil, io.EOF
+			}
+		}
+	}
+}
+
+func (reader *xlsxImportReader) Sheets() []string {
+	return append([]string(nil), reader.sheets...)
+}
+
+func (reader *xlsxImportReader) Columns() []string {
+	return append([]string(nil), reader.columns...)
+}
+
+func (reader *xlsxImportReader) Next() (map[string]interface{}, error) {
+	values := reader.pending
+	reader.pending = nil
+	if values == nil {
+		var err error
+		if values, err = reader.nextRow(); err != nil {
+			return nil, err
+		}
+	}
+	row := make(map[string]interface{}, len(reader.columns))
+	for index, column := range reader.columns {
+		var value interface{}
+		if index < len(values) {
+			value = values[index]
+		}
+		if text, ok := value.(string); ok && text == "" && reader.options.EmptyAsNull {
+			value = nil
+		}
+		row[column] = value
+	}
+	return row, nil
+}
+
+func (reader *xlsxImportReader) Close() error {
+	partErr := reader.part.Close()
+	if err := reader.file.Close(); err != nil {
+		return err
+	}
+	return partErr
+}
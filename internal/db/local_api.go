@@ -0,0 +1,352 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+const (
+	localAPIAddress      = "127.0.0.1:0"
+	localAPIMaxBodyBytes = 8 << 20
+	localAPITokenBytes   = 32
+)
+
+// LocalAPIStatus describes the opt-in loopback API. The token is regenerated
+// every time the API starts and is only valid for that session.
+type LocalAPIStatus struct {
+	Running   bool       `json:"running"`
+	URL       string     `json:"url,omitempty"`
+	Token     string     `json:"token,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+type localAPIServer struct {
+	server    *http.Server
+	host      string
+	token     string
+	startedAt time.Time
+	done      chan struct{}
+}
+
+func (server *localAPIServer) status() LocalAPIStatus {
+	startedAt := server.startedAt
+	return LocalAPIStatus{
+		Running:   true,
+		URL:       "http://" + server.host,
+		Token:     server.token,
+		StartedAt: &startedAt,
+	}
+}
+
+type localAPIConnectionRequest struct {
+	ConnectionID string `json:"connectionId"`
+}
+
+type localAPIObjectsRequest struct {
+	ConnectionID string                `json:"connectionId"`
+	Filter       database.ObjectFilter `json:"filter"`
+}
+
+type localAPIObjectRequest struct {
+	ConnectionID string                   `json:"connectionId"`
+	Object       database.ObjectReference `json:"object"`
+}
+
+type localAPITableRequest struct {
+	ConnectionID string         `json:"connectionId"`
+	Table        database.Table `json:"table"`
+}
+
+type localAPITableChangesRequest struct {
+	ConnectionID string                  `json:"connectionId"`
+	Changes      database.TableChangeSet `json:"changes"`
+}
+
+type localAPIObjectChangeRequest struct {
+	ConnectionID string                            `json:"connectionId"`
+	Change       database.ApplyObjectChangeRequest `json:"change"`
+}
+
+// StartLocalAPI opts the running session into a loopback HTTP/JSON API for
+// local automation. Callers authenticate with the returned bearer token and
+// reuse the session's live connections; credentials never cross the API.
+func (s *Service) StartLocalAPI() response.BaseResponse[LocalAPIStatus] {
+	s.localAPIMu.Lock()
+	defer s.localAPIMu.Unlock()
+	if s.localAPI != nil {
+		return response.BaseResponse[LocalAPIStatus]{Data: s.localAPI.status()}
+	}
+
+	token, err := newLocalAPIToken()
+	if err != nil {
+		return serviceErrorWithCode[LocalAPIStatus](
+			http.StatusInternalServerError,
+			errorCodeLocalAPIFailed,
+			"Could not start the local API",
+			err.Error(),
+			"Try again. The operating system could not provide secure random data.",
+		)
+	}
+	listener, err := net.Listen("tcp", localAPIAddress)
+	if err != nil {
+		return serviceErrorWithCode[LocalAPIStatus](
+			http.StatusInternalServerError,
+			errorCodeLocalAPIFailed,
+			"Could not start the local API",
+			err.Error(),
+			"Check that loopback networking is available and try again.",
+		)
+	}
+	server := &localAPIServer{
+		host:      listener.Addr().String(),
+		token:     token,
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+	server.server = &http.Server{
+		Handler:           s.localAPIHandler(server.host, server.token),
+		ReadHeaderTimeout: localAPIHeaderTimeout,
+	}
+	go func() {
+		defer close(server.done)
+		_ = server.server.Serve(listener)
+	}()
+	s.localAPI = server
+	return response.BaseResponse[LocalAPIStatus]{Data: server.status()}
+}
+
+// StopLocalAPI closes the listener and revokes the session token.
+func (s *Service) StopLocalAPI() response.BaseResponse[bool] {
+	s.stopLocalAPI()
+	return response.BaseResponse[bool]{Data: true}
+}
+
+func (s *Service) GetLocalAPIStatus() response.BaseResponse[LocalAPIStatus] {
+	s.localAPIMu.Lock()
+	defer s.localAPIMu.Unlock()
+	if s.localAPI == nil {
+		return response.BaseResponse[LocalAPIStatus]{Data: LocalAPIStatus{}}
+	}
+	return response.BaseResponse[LocalAPIStatus]{Data: s.localAPI.status()}
+}
+
+func (s *Service) stopLocalAPI() {
+	s.localAPIMu.Lock()
+	server := s.localAPI
+	s.localAPI = nil
+	s.localAPIMu.Unlock()
+	if server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), localAPIShutdownTimeout)
+	defer cancel()
+	if err := server.server.Shutdown(ctx); err != nil {
+		_ = server.server.Close()
+	}
+	<-server.done
+}
+
+func newLocalAPIToken() (string, error) {
+	token := make([]byte, localAPITokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func (s *Service) localAPIHandler(host string, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/connections", func(w http.ResponseWriter, _ *http.Request) {
+		writeLocalAPIResult(w, s.GetActiveConnections())
+	})
+	mux.HandleFunc("POST /v1/objects", localAPIRoute(
+		func(request localAPIObjectsRequest) response.BaseResponse[[]database.DatabaseObject] {
+			return s.GetDatabaseObjects(request.ConnectionID, request.Filter)
+		},
+	))
+	mux.HandleFunc("POST /v1/object", localAPIRoute(
+		func(request localAPIObjectRequest) response.BaseResponse[database.ObjectDetail] {
+			return s.GetDatabaseObject(request.ConnectionID, request.Object)
+		},
+	))
+	mux.HandleFunc("POST /v1/table-data", s.localAPITableData)
+	mux.HandleFunc("POST /v1/explain", localAPIRoute(s.ExplainQuery))
+	mux.HandleFunc("POST /v1/activity", localAPIRoute(
+		func(request localAPIConnectionRequest) response.BaseResponse[database.DatabaseActivity] {
+			return s.GetDatabaseActivity(request.ConnectionID)
+		},
+	))
+
+	// ExecuteQuery classifies each batch itself and only requires write access
+	// when it contains a write statement, including inside open transactions.
+	mux.HandleFunc("POST /v1/query", localAPIRoute(s.ExecuteQuery))
+	mux.HandleFunc("POST /v1/table-changes", localAPIRoute(
+		func(request localAPITableChangesRequest) response.BaseResponse[database.TableChangeResult] {
+			if blocked, ok := localAPIWriteGuard[database.TableChangeResult](
+				s,
+				request.ConnectionID,
+			); !ok {
+				return blocked
+			}
+			return s.ApplyTableChanges(request.ConnectionID, request.Changes)
+		},
+	))
+	mux.HandleFunc("POST /v1/object-changes", localAPIRoute(
+		func(request localAPIObjectChangeRequest) response.BaseResponse[database.ObjectChangeResult] {
+			if blocked, ok := localAPIWriteGuard[database.ObjectChangeResult](
+				s,
+				request.ConnectionID,
+			); !ok {
+				return blocked
+			}
+			return s.ApplyDatabaseObjectChange(request.ConnectionID, request.Change)
+		},
+	))
+	mux.HandleFunc("POST /v1/sessions/cancel", localAPIRoute(
+		func(request database.CancelSessionRequest) response.BaseResponse[database.CancelSessionResult] {
+			if blocked, ok := localAPIWriteGuard[database.CancelSessionResult](
+				s,
+				request.ConnectionID,
+			); !ok {
+				return blocked
+			}
+			return s.CancelDatabaseSession(request)
+		},
+	))
+	return localAPIAuthenticate(host, token, mux)
+}
+
+// localAPIAuthenticate rejects requests that do not carry the session token
+// or that arrive under another Host name, which blocks DNS-rebinding pages
+// from reaching the loopback listener through a browser.
+func localAPIAuthenticate(host string, token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != host {
+			response.Respond(response.BaseResponse[bool]{
+				Errors: []response.BaseErrorResponse{{
+					Title:  "Host not allowed",
+					Status: http.StatusForbidden,
+					Code:   errorCodeInvalidRequest,
+					Detail: "The local API only answers requests addressed to " + host + ".",
+				}},
+			}, http.StatusForbidden, w)
+			return
+		}
+		presented, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			response.Unauthorized[bool](w, response.BaseErrorResponse{
+				Title:  "Local API token required",
+				Status: http.StatusUnauthorized,
+				Code:   errorCodeLocalAPIUnauthorized,
+				Detail: "The request did not include the current session token.",
+				Hint:   "Copy the token shown when the local API was enabled and send it as a bearer token.",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func decodeLocalAPIRequest[R any](w http.ResponseWriter, r *http.Request) (R, bool) {
+	var request R
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		response.BadRequest[bool](w, response.BaseErrorResponse{
+			Title:  "JSON body required",
+			Status: http.StatusBadRequest,
+			Code:   errorCodeInvalidRequest,
+			Detail: "Requests must use Content-Type application/json.",
+		})
+		return request, false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, localAPIMaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		response.BadRequest[bool](w, response.BaseErrorResponse{
+			Title:  "Invalid request body",
+			Status: http.StatusBadRequest,
+			Code:   errorCodeInvalidRequest,
+			Detail: err.Error(),
+			Hint:   "Send a single JSON object matching the endpoint request shape.",
+		})
+		return request, false
+	}
+	return request, true
+}
+
+func localAPIRoute[R, T any](call func(R) response.BaseResponse[T]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		request, ok := decodeLocalAPIRequest[R](w, r)
+		if !ok {
+			return
+		}
+		writeLocalAPIResult(w, call(request))
+	}
+}
+
+// writeLocalAPIResult maps a Service envelope to HTTP. Service errors already
+// carry the status the desktop frontend uses, so automation sees the same
+// codes and hints.
+func writeLocalAPIResult[T any](w http.ResponseWriter, result response.BaseResponse[T]) {
+	if len(result.Errors) == 0 {
+		response.OK(w, result.Data)
+		return
+	}
+	status := result.Errors[0].Status
+	if status < http.StatusBadRequest || status > 599 {
+		status = http.StatusInternalServerError
+	}
+	response.Respond(response.BaseResponse[T]{Errors: result.Errors}, status, w)
+}
+
+func (s *Service) localAPITableData(w http.ResponseWriter, r *http.Request) {
+	request, ok := decodeLocalAPIRequest[localAPITableRequest](w, r)
+	if !ok {
+		return
+	}
+	result := s.GetCollectionData(request.ConnectionID, request.Table)
+	if len(result.Errors) > 0 {
+		writeLocalAPIResult(w, result)
+		return
+	}
+	size := int64(len(result.Data.Data))
+	meta := response.BaseMetaItems[int64]{Size: &size}
+	if request.Table.Limit > 0 {
+		page := int64(request.Table.Offset/request.Table.Limit) + 1
+		meta.Page = &page
+	}
+	response.OKWithMeta(w, result.Data, meta)
+}
+
+// localAPIWriteGuard applies the write-access rules before a mutation route
+// reaches the Service, so API callers cannot unlock a read-only profile.
+func localAPIWriteGuard[T any](
+	s *Service,
+	connectionID string,
+) (response.BaseResponse[T], bool) {
+	connection, release, err := s.pinnedConnection(connectionID)
+	if err != nil {
+		return serviceErrorWithCode[T](
+			http.StatusNotFound,
+			errorCodeInvalidRequest,
+			"Connection unavailable",
+			err.Error(),
+			"List active connections and pass one of their IDs.",
+		), false
+	}
+	defer release()
+	if !connectionWriteAccessLocked(connection).WriteEnabled {
+		return readOnlyConnectionError[T](), false
+	}
+	return response.BaseResponse[T]{}, true
+}
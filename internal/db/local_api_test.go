@@ -0,0 +1,171 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+func connectLocalAPISQLite(t *testing.T, service *Service, accessMode string) string {
+	t.Helper()
+	connected := service.Connect(ConnectRequest{
+		Driver: "sqlite",
+		Config: database.Config{
+			Name:       "Local API",
+			Driver:     "sqlite",
+			Db:         filepath.Join(t.TempDir(), "api.sqlite3"),
+			AccessMode: accessMode,
+		},
+	})
+	if len(connected.Errors) > 0 || !connected.Data.Connected {
+		t.Fatalf("Connect() = %+v", connected)
+	}
+	return connected.Data.ConnectionID
+}
+
+func localAPIPost(
+	t *testing.T,
+	status LocalAPIStatus,
+	path string,
+	token string,
+	body interface{},
+) (*http.Response, []byte) {
+	t.Helper()
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	request, err := http.NewRequest(http.MethodPost, status.URL+path, bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	result, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	defer result.Body.Close()
+	var buffer bytes.Buffer
+	if _, err := buffer.ReadFrom(result.Body); err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return result, buffer.Bytes()
+}
+
+func TestLocalAPIRequiresSessionTokenAndServesReadPaths(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+	connectionID := connectLocalAPISQLite(t, service, database.ConnectionAccessReadWrite)
+	if result := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT); INSERT INTO notes VALUES (1, 'first')",
+	}); len(result.Errors) > 0 {
+		t.Fatalf("seed = %+v", result.Errors)
+	}
+
+	started := service.StartLocalAPI()
+	if len(started.Errors) > 0 || !started.Data.Running || len(started.Data.Token) != 64 {
+		t.Fatalf("StartLocalAPI() = %+v", started)
+	}
+	status := started.Data
+
+	unauthorized, _ := localAPIPost(t, status, "/v1/objects", "wrong", localAPIObjectsRequest{
+		ConnectionID: connectionID,
+	})
+	if unauthorized.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d", unauthorized.StatusCode)
+	}
+
+	objects, body := localAPIPost(t, status, "/v1/objects", status.Token, localAPIObjectsRequest{
+		ConnectionID: connectionID,
+		Filter:       database.ObjectFilter{Schema: "main"},
+	})
+	if objects.StatusCode != http.StatusOK {
+		t.Fatalf("objects status = %d, body = %s", objects.StatusCode, body)
+	}
+
+	rows, body := localAPIPost(t, status, "/v1/table-data", status.Token, localAPITableRequest{
+		ConnectionID: connectionID,
+		Table:        database.Table{Schema: "main", Name: "notes", Limit: 10},
+	})
+	var page response.BaseWithMeta[database.TableData, int64]
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("decode table data: %v", err)
+	}
+	if rows.StatusCode != http.StatusOK || len(page.Data.Data) != 1 ||
+		page.Items.Size == nil || *page.Items.Size != 1 {
+		t.Fatalf("table data status = %d, body = %s", rows.StatusCode, body)
+	}
+
+	stopped := service.StopLocalAPI()
+	if len(stopped.Errors) > 0 || service.GetLocalAPIStatus().Data.Running {
+		t.Fatalf("StopLocalAPI() = %+v", stopped)
+	}
+	restarted := service.StartLocalAPI()
+	if restarted.Data.Token == status.Token {
+		t.Fatal("restarting the local API must issue a new session token")
+	}
+}
+
+func TestLocalAPIRejectsForeignHostNames(t *testing.T) {
+	service := NewService()
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+	status := service.StartLocalAPI().Data
+
+	request, err := http.NewRequest(http.MethodGet, status.URL+"/v1/connections", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	request.Host = "attacker.example"
+	request.Header.Set("Authorization", "Bearer "+status.Token)
+	result, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("GET connections: %v", err)
+	}
+	result.Body.Close()
+	if result.StatusCode != http.StatusForbidden {
+		t.Fatalf("foreign host status = %d", result.StatusCode)
+	}
+}
+
+func TestLocalAPIMutationsFollowWriteAccess(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+	connectionID := connectLocalAPISQLite(t, service, database.ConnectionAccessReadOnly)
+	status := service.StartLocalAPI().Data
+
+	blocked, body := localAPIPost(t, status, "/v1/table-changes", status.Token, localAPITableChangesRequest{
+		ConnectionID: connectionID,
+		Changes: database.TableChangeSet{
+			Table: database.Table{Schema: "main", Name: "notes"},
+			Added: []map[string]interface{}{{"id": 1}},
+		},
+	})
+	var result response.BaseResponse[database.TableChangeResult]
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if blocked.StatusCode != http.StatusLocked || len(result.Errors) != 1 ||
+		result.Errors[0].Code != errorCodeReadOnlyConnection {
+		t.Fatalf("read-only mutation status = %d, body = %s", blocked.StatusCode, body)
+	}
+
+	query, body := localAPIPost(t, status, "/v1/query", status.Token, database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        "CREATE TABLE notes (id INTEGER PRIMARY KEY)",
+	})
+	if query.StatusCode != http.StatusLocked {
+		t.Fatalf("read-only query status = %d, body = %s", query.StatusCode, body)
+	}
+}
@@ -0,0 +1,905 @@
+package db
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+)
+
+// maxLogicalManifestBytes bounds the manifest read before it is validated;
+// the manifest only lists tables and columns.
+const maxLogicalManifestBytes = 64 << 20
+
+func logicalBackupCapabilities(engine string) database.BackupCapabilities {
+	return database.BackupCapabilities{
+		Available:        true,
+		Engine:           engine,
+		Format:           database.BackupFormatLogical,
+		Extension:        database.LogicalBackupExtension,
+		RestoreReady:     true,
+		BuiltIn:          true,
+		SupportsScope:    true,
+		LogicalAvailable: true,
+	}
+}
+
+// withLogicalBackup keeps a usable native workflow and offers the logical
+// format next to it. When the native workflow is missing its tools or
+// server access, the logical format takes its place.
+func withLogicalBackup(
+	native database.BackupCapabilities,
+) database.BackupCapabilities {
+	if native.Available {
+		native.LogicalAvailable = true
+		return native
+	}
+	capabilities := logicalBackupCapabilities(native.Engine)
+	capabilities.Message = "Backups use the built-in Rolling Thunder logical format."
+	if native.Message != "" {
+		capabilities.Message = native.Message + " " + capabilities.Message
+	}
+	return capabilities
+}
+
+type logicalBackupSource struct {
+	table      database.Table
+	structures database.Structures
+}
+
+func logicalTableKey(schema string, name string) string {
+	return schema + "\x00" + name
+}
+
+// logicalBackupSources lists the tables in one schema, or in the driver's
+// default namespace when schema is empty, ordered so that referenced
+// tables are restored before the tables that reference them.
+func logicalBackupSources(
+	driver database.Driver,
+	schema string,
+) ([]logicalBackupSource, error) {
+	var (
+		names []string
+		err   error
+	)
+	if schema == "" {
+		names, err = driver.GetCollections()
+	} else {
+		names, err = driver.GetCollections(schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	sort.Strings(names)
+	sources := make([]logicalBackupSource, 0, len(names))
+	for _, name := range names {
+		table := database.Table{Schema: schema, Name: name}
+		structures, err := driver.GetCollectionStructures(table)
+		if err != nil {
+			return nil, fmt.Errorf("read columns of %s: %w", name, err)
+		}
+		sources = append(sources, logicalBackupSource{
+			table:      table,
+			structures: structures,
+		})
+	}
+	return orderLogicalBackupSources(sources), nil
+}
+
+// orderLogicalBackupSources sorts tables by foreign-key dependency. Tables
+// in a reference cycle keep their name order after the acyclic ones; their
+// DDL still has to be restorable in that order.
+func orderLogicalBackupSources(
+	sources []logicalBackupSource,
+) []logicalBackupSource {
+	index := make(map[string]int, len(sources))
+	for position, source := range sources {
+		index[logicalTableKey(source.table.Schema, source.table.Name)] = position
+	}
+	dependencies := make([]map[int]struct{}, len(sources))
+	for position, source := range sources {
+		dependencies[position] = make(map[int]struct{})
+		for _, column := range source.structures {
+			if column.ForeignTable == nil {
+				continue
+			}
+			schema := source.table.Schema
+			if column.ForeignSchema != nil && *column.ForeignSchema != "" {
+				schema = *column.ForeignSchema
+			}
+			parent, found := index[logicalTableKey(schema, *column.ForeignTable)]
+			if !found && schema != source.table.Schema {
+				parent, found = index[logicalTableKey(source.table.Schema, *column.ForeignTable)]
+			}
+			if found && parent != position {
+				dependencies[position][parent] = struct{}{}
+			}
+		}
+	}
+	ordered := make([]logicalBackupSource, 0, len(sources))
+	placed := make([]bool, len(sources))
+	for len(ordered) < len(sources) {
+		progressed := false
+		for position := range sources {
+			if placed[position] {
+				continue
+			}
+			ready := true
+			for parent := range dependencies[position] {
+				if !placed[parent] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, sources[position])
+				placed[position] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			for position := range sources {
+				if !placed[position] {
+					ordered = append(ordered, sources[position])
+					placed[position] = true
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+func logicalBackupColumns(
+	structures database.Structures,
+) []database.LogicalBackupColumn {
+	columns := make([]database.LogicalBackupColumn, 0, len(structures))
+	for _, structure := range structures {
+		dataType := structure.DataType
+		if strings.TrimSpace(dataType) == "" {
+			dataType = structure.NativeType
+		}
+		columns = append(columns, database.LogicalBackupColumn{
+			Name:          structure.Name,
+			DataType:      dataType,
+			Generated:     structure.IsGenerated,
+			AutoIncrement: structure.IsAutoInc,
+		})
+	}
+	return columns
+}
+
+func writeLogicalBackupEntry(
+	archive *zip.Writer,
+	path string,
+	write func(io.Writer) error,
+) (*database.LogicalBackupEntry, error) {
+	member, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.New()
+	counter := &countingWriter{writer: io.MultiWriter(member, digest)}
+	if err := write(counter); err != nil {
+		return nil, err
+	}
+	return &database.LogicalBackupEntry{
+		Path:   path,
+		Bytes:  counter.bytes,
+		SHA256: hex.EncodeToString(digest.Sum(nil)),
+	}, nil
+}
+
+type countingWriter struct {
+	writer io.Writer
+	bytes  int64
+}
+
+func (writer *countingWriter) Write(value []byte) (int, error) {
+	written, err := writer.writer.Write(value)
+	writer.bytes += int64(written)
+	return written, err
+}
+
+// createLogicalBackup writes table DDL from GetTableDDL and rows from the
+// driver's JSON table export. The manifest is written last, once every
+// row count and checksum is known.
+func createLogicalBackup(
+	ctx context.Context,
+	driver database.Driver,
+	databaseName string,
+	path string,
+	request database.BackupRequest,
+) error {
+	sources, err := logicalBackupSources(driver, strings.TrimSpace(request.Schema))
+	if err != nil {
+		return err
+	}
+	target, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	archive := zip.NewWriter(target)
+	manifest := database.LogicalBackupManifest{
+		Format:     database.BackupFormatLogical,
+		Version:    database.LogicalBackupVersion,
+		Engine:     driver.Capabilities().Engine,
+		Database:   databaseName,
+		CreatedAt:  time.Now().UTC(),
+		SchemaOnly: request.SchemaOnly,
+		DataOnly:   request.DataOnly,
+		Tables:     make([]database.LogicalBackupTable, 0, len(sources)),
+	}
+	writeErr := func() error {
+		for position, source := range sources {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			directory := fmt.Sprintf("tables/%04d/", position+1)
+			table := database.LogicalBackupTable{
+				Schema:  source.table.Schema,
+				Name:    source.table.Name,
+				Columns: logicalBackupColumns(source.structures),
+			}
+			if !request.DataOnly {
+				ddl, err := driver.GetTableDDL(source.table)
+				if err != nil {
+					return fmt.Errorf("read DDL of %s: %w", source.table.Name, err)
+				}
+				table.DDL, err = writeLogicalBackupEntry(
+					archive,
+					directory+"ddl.sql",
+					func(writer io.Writer) error {
+						_, err := io.WriteString(writer, ddl)
+						return err
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+			if !request.SchemaOnly {
+				var stats database.ExportStats
+				table.Rows, err = writeLogicalBackupEntry(
+					archive,
+					directory+"rows.json",
+					func(writer io.Writer) error {
+						var exportErr error
+						stats, exportErr = driver.ExportTable(
+							ctx,
+							database.TableExportRequest{
+								Table: source.table,
+								Scope: database.ExportScopeAll,
+								Options: database.ExportOptions{
+									Format: database.ExportFormatJSON,
+								},
+							},
+							writer,
+						)
+						return exportErr
+					},
+				)
+				if err != nil {
+					return fmt.Errorf("export rows of %s: %w", source.table.Name, err)
+				}
+				table.RowCount = stats.Rows
+			}
+			manifest.Tables = append(manifest.Tables, table)
+		}
+		member, err := archive.Create(database.LogicalBackupManifestPath)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(member)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(manifest); err != nil {
+			return err
+		}
+		return archive.Close()
+	}()
+	closeErr := target.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return closeErr
+}
+
+type logicalBackupArchive struct {
+	closer   io.Closer
+	manifest database.LogicalBackupManifest
+	files    map[string]*zip.File
+}
+
+func (archive *logicalBackupArchive) Close() error {
+	return archive.closer.Close()
+}
+
+func openLogicalBackup(path string) (*logicalBackupArchive, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open logical backup: %w", err)
+	}
+	archive := &logicalBackupArchive{
+		closer: reader,
+		files:  make(map[string]*zip.File, len(reader.File)),
+	}
+	for _, file := range reader.File {
+		archive.files[file.Name] = file
+	}
+	manifestFile := archive.files[database.LogicalBackupManifestPath]
+	if manifestFile == nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("logical backup has no %s", database.LogicalBackupManifestPath)
+	}
+	source, err := manifestFile.Open()
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	decodeErr := json.NewDecoder(
+		io.LimitReader(source, maxLogicalManifestBytes),
+	).Decode(&archive.manifest)
+	_ = source.Close()
+	if decodeErr != nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("read logical backup manifest: %w", decodeErr)
+	}
+	if err := archive.manifest.Validate(); err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	for _, table := range archive.manifest.Tables {
+		for _, entry := range []*database.LogicalBackupEntry{table.DDL, table.Rows} {
+			if entry == nil {
+				continue
+			}
+			file := archive.files[entry.Path]
+			if file == nil || file.UncompressedSize64 != uint64(entry.Bytes) {
+				_ = reader.Close()
+				return nil, fmt.Errorf("logical backup entry %s is missing or truncated", entry.Path)
+			}
+		}
+	}
+	return archive, nil
+}
+
+// checkedEntryReader fails at end of input instead of returning io.EOF
+// when the entry does not match the manifest checksum.
+type checkedEntryReader struct {
+	source io.ReadCloser
+	digest hash.Hash
+	entry  database.LogicalBackupEntry
+}
+
+func (reader *checkedEntryReader) Read(value []byte) (int, error) {
+	count, err := reader.source.Read(value)
+	_, _ = reader.digest.Write(value[:count])
+	if errors.Is(err, io.EOF) &&
+		hex.EncodeToString(reader.digest.Sum(nil)) != reader.entry.SHA256 {
+		return count, fmt.Errorf(
+			"logical backup entry %s does not match its checksum",
+			reader.entry.Path,
+		)
+	}
+	return count, err
+}
+
+func (reader *checkedEntryReader) Close() error {
+	return reader.source.Close()
+}
+
+func (archive *logicalBackupArchive) open(
+	entry database.LogicalBackupEntry,
+) (io.ReadCloser, error) {
+	source, err := archive.files[entry.Path].Open()
+	if err != nil {
+		return nil, err
+	}
+	return &checkedEntryReader{
+		source: source,
+		digest: sha256.New(),
+		entry:  entry,
+	}, nil
+}
+
+func (archive *logicalBackupArchive) read(
+	entry database.LogicalBackupEntry,
+) (string, error) {
+	source, err := archive.open(entry)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+	content, err := io.ReadAll(source)
+	return string(content), err
+}
+
+func (archive *logicalBackupArchive) verify(
+	ctx context.Context,
+	tables []database.LogicalBackupTable,
+) error {
+	for _, table := range tables {
+		for _, entry := range []*database.LogicalBackupEntry{table.DDL, table.Rows} {
+			if entry == nil {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			source, err := archive.open(*entry)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(io.Discard, source)
+			_ = source.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// logicalRestoreTables keeps the manifest order. A restore schema limits
+// the restore to that schema, as pg_restore --schema does.
+func logicalRestoreTables(
+	manifest database.LogicalBackupManifest,
+	schema string,
+) ([]database.LogicalBackupTable, error) {
+	schema = strings.TrimSpace(schema)
+	if schema == "" {
+		if len(manifest.Tables) == 0 {
+			return nil, fmt.Errorf("the logical backup contains no tables")
+		}
+		return manifest.Tables, nil
+	}
+	tables := make([]database.LogicalBackupTable, 0, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		if strings.EqualFold(table.Schema, schema) {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("the logical backup contains no tables in %s", schema)
+	}
+	return tables, nil
+}
+
+// inspectLogicalBackup backs the restore preview. Restores are limited to
+// the engine that wrote the backup, but not to the same connection or
+// database name.
+func inspectLogicalBackup(
+	ctx context.Context,
+	path string,
+	engine string,
+	schema string,
+) (database.LogicalBackupManifest, []database.LogicalBackupTable, error) {
+	archive, err := openLogicalBackup(path)
+	if err != nil {
+		return database.LogicalBackupManifest{}, nil, err
+	}
+	defer archive.Close()
+	if !strings.EqualFold(archive.manifest.Engine, engine) {
+		return database.LogicalBackupManifest{}, nil, fmt.Errorf(
+			"the logical backup was taken from %s and cannot be restored into %s",
+			archive.manifest.Engine,
+			engine,
+		)
+	}
+	tables, err := logicalRestoreTables(archive.manifest, schema)
+	if err != nil {
+		return database.LogicalBackupManifest{}, nil, err
+	}
+	if err := archive.verify(ctx, tables); err != nil {
+		return database.LogicalBackupManifest{}, nil, err
+	}
+	return archive.manifest, tables, nil
+}
+
+func logicalRestoreTransactional(driver database.Driver) bool {
+	capabilities := driver.Capabilities()
+	_, ok := driver.(database.TransactionalDriver)
+	return ok && capabilities.Transactions && capabilities.TransactionalDDL
+}
+
+func logicalRestoreWarnings(
+	manifest database.LogicalBackupManifest,
+	tables []database.LogicalBackupTable,
+	transactional bool,
+) []string {
+	var rows int64
+	for _, table := range tables {
+		rows += table.RowCount
+	}
+	warnings := make([]string, 0, 6)
+	switch {
+	case manifest.DataOnly:
+		warnings = append(warnings, fmt.Sprintf(
+			"Restore deletes every row in %d existing tables and reloads %d rows from the backup.",
+			len(tables),
+			rows,
+		))
+	case manifest.SchemaOnly:
+		warnings = append(warnings, fmt.Sprintf(
+			"Restore drops and recreates %d tables from the backup without rows.",
+			len(tables),
+		))
+	default:
+		warnings = append(warnings, fmt.Sprintf(
+			"Restore drops and recreates %d tables from the backup, then reloads %d rows.",
+			len(tables),
+			rows,
+		))
+	}
+	warnings = append(
+		warnings,
+		fmt.Sprintf(
+			"The backup was taken from database %q at %s.",
+			manifest.Database,
+			manifest.CreatedAt.UTC().Format(time.RFC3339),
+		),
+		"Only tables are restored. Views, routines, triggers, grants, and sequence positions are left unchanged.",
+		"Any explicit Rolling Thunder transaction on the target connection is rolled back before restore starts.",
+	)
+	if !transactional {
+		warnings = append(
+			warnings,
+			"Table DDL commits immediately on this engine; a failed restore can leave tables dropped or partially loaded.",
+		)
+	}
+	return append(
+		warnings,
+		"Keep an independent backup until the restored database has been verified.",
+	)
+}
+
+// restoreLogicalBackup replaces the tables in one transaction when the
+// engine has transactional DDL. Otherwise the DDL commits first and the
+// rows are loaded in a transaction of their own when one is available.
+func restoreLogicalBackup(
+	ctx context.Context,
+	driver database.Driver,
+	request database.RestorePreviewRequest,
+	path string,
+) error {
+	archive, err := openLogicalBackup(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	tables, err := logicalRestoreTables(archive.manifest, request.Schema)
+	if err != nil {
+		return err
+	}
+	capabilities := driver.Capabilities()
+	transactional, canBegin := driver.(database.TransactionalDriver)
+	canBegin = canBegin && capabilities.Transactions
+	ddlInTransaction := logicalRestoreTransactional(driver)
+	// Existing tables are listed before the transaction starts; drivers
+	// with a single pooled connection would otherwise wait on themselves.
+	existing, err := existingLogicalRestoreTables(driver, tables)
+	if err != nil {
+		return err
+	}
+
+	if !archive.manifest.DataOnly && !ddlInTransaction {
+		if err := replaceLogicalBackupTables(ctx, driver, driver, archive, tables, existing); err != nil {
+			return err
+		}
+	}
+	var executor importExecutor = driver
+	var transaction database.Transaction
+	committed := false
+	if canBegin {
+		transaction, err = transactional.BeginTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if !committed {
+				_ = transaction.Rollback()
+			}
+		}()
+		executor = transaction
+	}
+	if !archive.manifest.DataOnly && ddlInTransaction {
+		if err := replaceLogicalBackupTables(ctx, executor, driver, archive, tables, existing); err != nil {
+			return err
+		}
+	}
+	if archive.manifest.DataOnly {
+		for index := len(tables) - 1; index >= 0; index-- {
+			if _, err := executor.ExecuteQuery(
+				ctx,
+				"DELETE FROM "+qualifiedImportTable(driver, tables[index].Schema, tables[index].Name),
+				database.QueryOptions{},
+			); err != nil {
+				return fmt.Errorf("clear %s: %w", tables[index].Name, err)
+			}
+		}
+	}
+	if !archive.manifest.SchemaOnly {
+		for _, table := range tables {
+			if err := loadLogicalBackupRows(ctx, executor, driver, archive, table); err != nil {
+				return fmt.Errorf("restore rows of %s: %w", table.Name, err)
+			}
+		}
+	}
+	if transaction != nil {
+		if err := transaction.Commit(); err != nil {
+			return err
+		}
+		committed = true
+	}
+	return nil
+}
+
+func existingLogicalRestoreTables(
+	driver database.Driver,
+	tables []database.LogicalBackupTable,
+) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	listed := make(map[string]bool)
+	for _, table := range tables {
+		if listed[table.Schema] {
+			continue
+		}
+		listed[table.Schema] = true
+		var (
+			names []string
+			err   error
+		)
+		if table.Schema == "" {
+			names, err = driver.GetCollections()
+		} else {
+			names, err = driver.GetCollections(table.Schema)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list existing tables: %w", err)
+		}
+		for _, name := range names {
+			existing[logicalTableKey(table.Schema, name)] = struct{}{}
+		}
+	}
+	return existing, nil
+}
+
+func replaceLogicalBackupTables(
+	ctx context.Context,
+	executor importExecutor,
+	driver database.Driver,
+	archive *logicalBackupArchive,
+	tables []database.LogicalBackupTable,
+	existing map[string]struct{},
+) error {
+	for index := len(tables) - 1; index >= 0; index-- {
+		table := tables[index]
+		if _, exists := existing[logicalTableKey(table.Schema, table.Name)]; !exists {
+			continue
+		}
+		if _, err := executor.ExecuteQuery(
+			ctx,
+			"DROP TABLE "+qualifiedImportTable(driver, table.Schema, table.Name),
+			database.QueryOptions{},
+		); err != nil {
+			return fmt.Errorf("drop %s: %w", table.Name, err)
+		}
+	}
+	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ddl, err := archive.read(*table.DDL)
+		if err != nil {
+			return err
+		}
+		statements, err := database.SplitSQLStatements(ddl)
+		if err != nil {
+			return fmt.Errorf("split DDL of %s: %w", table.Name, err)
+		}
+		for _, statement := range statements {
+			if _, err := executor.ExecuteQuery(
+				ctx,
+				statement,
+				database.QueryOptions{},
+			); err != nil {
+				return fmt.Errorf("create %s: %w", table.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+type logicalColumnKind int
+
+const (
+	logicalColumnText logicalColumnKind = iota
+	logicalColumnBoolean
+	logicalColumnBinary
+	logicalColumnTemporal
+)
+
+// logicalColumnKindFor recognizes the types whose values the JSON export
+// changes: booleans, binary values written as base64, and date/time values
+// written as RFC 3339 strings.
+func logicalColumnKindFor(dataType string) logicalColumnKind {
+	dataType = strings.ToLower(dataType)
+	switch {
+	case strings.Contains(dataType, "bool"):
+		return logicalColumnBoolean
+	case strings.Contains(dataType, "bytea"),
+		strings.Contains(dataType, "blob"),
+		strings.Contains(dataType, "binary"),
+		strings.Contains(dataType, "raw"),
+		dataType == "image":
+		return logicalColumnBinary
+	case strings.Contains(dataType, "date"),
+		strings.Contains(dataType, "time"):
+		return logicalColumnTemporal
+	default:
+		return logicalColumnText
+	}
+}
+
+// logicalRestoreValue converts a decoded JSON value back to a driver
+// argument. Numbers that do not fit an int64 stay in their exact decimal
+// text so NUMERIC and DECIMAL columns do not lose precision.
+func logicalRestoreValue(
+	value interface{},
+	kind logicalColumnKind,
+) (interface{}, error) {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer, nil
+		}
+		return typed.String(), nil
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	case string:
+		switch kind {
+		case logicalColumnBinary:
+			if encoded, ok := strings.CutPrefix(typed, "base64:"); ok {
+				return base64.StdEncoding.DecodeString(encoded)
+			}
+			return []byte(typed), nil
+		case logicalColumnTemporal:
+			if parsed, err := time.Parse(time.RFC3339Nano, typed); err == nil {
+				return parsed, nil
+			}
+		}
+		return typed, nil
+	default:
+		return typed, nil
+	}
+}
+
+func loadLogicalBackupRows(
+	ctx context.Context,
+	executor importExecutor,
+	driver database.Driver,
+	archive *logicalBackupArchive,
+	table database.LogicalBackupTable,
+) error {
+	engine := driver.Capabilities().Engine
+	columns := make([]database.ImportColumn, 0, len(table.Columns))
+	kinds := make([]logicalColumnKind, 0, len(table.Columns))
+	identity := false
+	for _, column := range table.Columns {
+		if column.Generated {
+			continue
+		}
+		kind := logicalColumnKindFor(column.DataType)
+		inferred := "text"
+		if kind == logicalColumnBoolean {
+			inferred = "boolean"
+		}
+		columns = append(columns, database.ImportColumn{
+			SourceName:   column.Name,
+			TargetName:   column.Name,
+			InferredType: inferred,
+			Nullable:     true,
+			Included:     true,
+		})
+		kinds = append(kinds, kind)
+		identity = identity || column.AutoIncrement
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	target := qualifiedImportTable(driver, table.Schema, table.Name)
+	// SQL Server rejects explicit identity values unless the session
+	// allows them for this table.
+	identity = identity && engine == database.DriverSQLServer
+	if identity {
+		if _, err := executor.ExecuteQuery(
+			ctx,
+			"SET IDENTITY_INSERT "+target+" ON",
+			database.QueryOptions{},
+		); err != nil {
+			return err
+		}
+	}
+
+	source, err := archive.open(*table.Rows)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return fmt.Errorf("rows entry is not a JSON array")
+	}
+	batchSize := importBatchRowLimit(engine, len(columns))
+	batch := make([]map[string]interface{}, 0, batchSize)
+	var loaded int64
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("decode row %d: %w", loaded+1, err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for index, column := range columns {
+			value, err := logicalRestoreValue(raw[column.SourceName], kinds[index])
+			if err != nil {
+				return fmt.Errorf("row %d column %s: %w", loaded+1, column.SourceName, err)
+			}
+			row[column.SourceName] = value
+		}
+		batch = append(batch, row)
+		loaded++
+		if len(batch) < batchSize {
+			continue
+		}
+		if err := flushImportRows(ctx, executor, driver, table.Schema, table.Name, columns, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("close rows array: %w", err)
+	}
+	if err := flushImportRows(ctx, executor, driver, table.Schema, table.Name, columns, batch); err != nil {
+		return err
+	}
+	// Read to the end so the checksum covers the whole entry.
+	if _, err := io.Copy(io.Discard, io.MultiReader(decoder.Buffered(), source)); err != nil {
+		return err
+	}
+	if loaded != table.RowCount {
+		return fmt.Errorf("restored %d rows, but the manifest lists %d", loaded, table.RowCount)
+	}
+	if identity {
+		if _, err := executor.ExecuteQuery(
+			ctx,
+			"SET IDENTITY_INSERT "+target+" OFF",
+			database.QueryOptions{},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
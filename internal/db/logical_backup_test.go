@@ -0,0 +1,257 @@
+package db
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rollingthunder/pkg/database"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+func connectLogicalBackupSQLite(t *testing.T, service *Service, name string) string {
+	t.Helper()
+	connected := service.Connect(ConnectRequest{
+		Driver: "sqlite",
+		Config: database.Config{
+			Name:   name,
+			Driver: "sqlite",
+			Db:     filepath.Join(t.TempDir(), name+".sqlite3"),
+		},
+	})
+	if len(connected.Errors) > 0 {
+		t.Fatalf("Connect(%s) errors = %+v", name, connected.Errors)
+	}
+	t.Cleanup(func() {
+		_ = service.DisconnectConnection(connected.Data.ConnectionID)
+	})
+	return connected.Data.ConnectionID
+}
+
+func execLogicalBackupSQL(t *testing.T, service *Service, connectionID string, statements ...string) {
+	t.Helper()
+	driver, release, err := service.driverFor(connectionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	for _, statement := range statements {
+		if _, err := driver.ExecuteQuery(
+			context.Background(),
+			statement,
+			database.QueryOptions{},
+		); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+}
+
+func queryLogicalBackupRows(
+	t *testing.T,
+	service *Service,
+	connectionID string,
+	query string,
+) []map[string]interface{} {
+	t.Helper()
+	driver, release, err := service.driverFor(connectionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	result, err := driver.ExecuteQuery(context.Background(), query, database.QueryOptions{})
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return result.RowMaps()
+}
+
+func TestLogicalBackupRestoresIntoAnotherConnection(t *testing.T) {
+	service := NewService()
+	service.Start(context.Background())
+	source := connectLogicalBackupSQLite(t, service, "source")
+	target := connectLogicalBackupSQLite(t, service, "target")
+	execLogicalBackupSQL(t, service, source,
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers(id), total NUMERIC, note TEXT)",
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL, avatar BLOB)",
+		"INSERT INTO customers VALUES (1, 'Ada', x'00ff10'), (2, 'Grace', NULL)",
+		"INSERT INTO orders VALUES (10, 1, 12.5, 'first'), (11, 2, 99999999999, NULL)",
+	)
+	execLogicalBackupSQL(t, service, target,
+		"CREATE TABLE customers (id INTEGER PRIMARY KEY, legacy TEXT)",
+		"INSERT INTO customers VALUES (7, 'replaced')",
+	)
+
+	backupPath := filepath.Join(t.TempDir(), "shop")
+	service.saveDialog = func(
+		context.Context,
+		wailsruntime.SaveDialogOptions,
+	) (string, error) {
+		return backupPath, nil
+	}
+	backup := service.BackupDatabase(database.BackupRequest{
+		ConnectionID: source,
+		Format:       database.BackupFormatLogical,
+	})
+	if len(backup.Errors) > 0 ||
+		backup.Data.Format != database.BackupFormatLogical ||
+		filepath.Ext(backup.Data.Path) != database.LogicalBackupExtension {
+		t.Fatalf("BackupDatabase() = %+v", backup)
+	}
+
+	archive, err := openLogicalBackup(backup.Data.Path)
+	if err != nil {
+		t.Fatalf("openLogicalBackup() error = %v", err)
+	}
+	manifest := archive.manifest
+	_ = archive.Close()
+	if manifest.Engine != database.DriverSQLite ||
+		len(manifest.Tables) != 2 ||
+		manifest.Tables[0].Name != "customers" ||
+		manifest.Tables[1].Name != "orders" ||
+		manifest.Tables[1].RowCount != 2 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+
+	service.restoreOpenDialog = func(
+		context.Context,
+		wailsruntime.OpenDialogOptions,
+	) (string, error) {
+		return backup.Data.Path, nil
+	}
+	selected := service.ChooseRestoreFile(target)
+	if len(selected.Errors) > 0 || selected.Data.Format != database.BackupFormatLogical {
+		t.Fatalf("ChooseRestoreFile() = %+v", selected)
+	}
+	restore := database.RestorePreviewRequest{
+		ConnectionID: target,
+		Token:        selected.Data.Token,
+	}
+	preview := service.PreviewDatabaseRestore(restore)
+	if len(preview.Errors) > 0 || !preview.Data.Transactional || preview.Data.Fingerprint == "" {
+		t.Fatalf("PreviewDatabaseRestore() = %+v", preview)
+	}
+	applied := service.ApplyDatabaseRestore(database.ApplyRestoreRequest{
+		Restore:     restore,
+		Fingerprint: preview.Data.Fingerprint,
+	})
+	if len(applied.Errors) > 0 || !applied.Data.Restored {
+		t.Fatalf("ApplyDatabaseRestore() = %+v", applied)
+	}
+
+	customers := queryLogicalBackupRows(t, service, target,
+		"SELECT id, name, hex(avatar) AS avatar FROM customers ORDER BY id")
+	if len(customers) != 2 ||
+		customers[0]["name"] != "Ada" ||
+		customers[0]["avatar"] != "00FF10" ||
+		customers[1]["avatar"] != "" {
+		t.Fatalf("restored customers = %+v", customers)
+	}
+	orders := queryLogicalBackupRows(t, service, target,
+		"SELECT id, customer_id, total, note FROM orders ORDER BY id")
+	if len(orders) != 2 || orders[0]["note"] != "first" || orders[1]["note"] != nil {
+		t.Fatalf("restored orders = %+v", orders)
+	}
+}
+
+func TestLogicalBackupRejectsTamperedRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tampered.rtbackup")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	rows, err := writeLogicalBackupEntry(archive, "tables/0001/rows.json", func(writer io.Writer) error {
+		_, err := io.WriteString(writer, `[{"id":1}]`)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.SHA256 = strings.Repeat("0", 64)
+	member, err := archive.Create(database.LogicalBackupManifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(member).Encode(database.LogicalBackupManifest{
+		Format:   database.BackupFormatLogical,
+		Version:  database.LogicalBackupVersion,
+		Engine:   database.DriverSQLite,
+		DataOnly: true,
+		Tables: []database.LogicalBackupTable{{
+			Name:     "items",
+			Columns:  []database.LogicalBackupColumn{{Name: "id", DataType: "INTEGER"}},
+			Rows:     rows,
+			RowCount: 1,
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = inspectLogicalBackup(context.Background(), path, database.DriverSQLite, "")
+	if err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("inspectLogicalBackup() error = %v, want checksum mismatch", err)
+	}
+	_, _, err = inspectLogicalBackup(context.Background(), path, database.DriverPostgres, "")
+	if err == nil || !strings.Contains(err.Error(), "cannot be restored into postgres") {
+		t.Fatalf("cross-engine restore error = %v", err)
+	}
+}
+
+func TestOrderLogicalBackupSourcesPutsParentsFirst(t *testing.T) {
+	parent := "accounts"
+	sources := orderLogicalBackupSources([]logicalBackupSource{
+		{
+			table: database.Table{Name: "invoices"},
+			structures: database.Structures{
+				{Name: "account_id", ForeignTable: &parent},
+			},
+		},
+		{table: database.Table{Name: "accounts"}},
+	})
+	if sources[0].table.Name != "accounts" || sources[1].table.Name != "invoices" {
+		t.Fatalf("order = %s, %s", sources[0].table.Name, sources[1].table.Name)
+	}
+}
+
+func TestLogicalRestoreValueRecoversExportedTypes(t *testing.T) {
+	binary, err := logicalRestoreValue("base64:AP8Q", logicalColumnBinary)
+	if err != nil || string(binary.([]byte)) != "\x00\xff\x10" {
+		t.Fatalf("binary value = %#v, %v", binary, err)
+	}
+	decimal, _ := logicalRestoreValue(json.Number("12345678901234567890.25"), logicalColumnText)
+	if decimal != "12345678901234567890.25" {
+		t.Fatalf("decimal value = %#v", decimal)
+	}
+	if stamp, _ := logicalRestoreValue("2026-03-01T10:00:00.5Z", logicalColumnTemporal); stamp == "2026-03-01T10:00:00.5Z" {
+		t.Fatal("temporal value was not parsed")
+	}
+	if text, _ := logicalRestoreValue("2026-03-01 10:00:00", logicalColumnTemporal); text != "2026-03-01 10:00:00" {
+		t.Fatalf("non-RFC 3339 temporal value = %#v", text)
+	}
+}
+
+func TestLogicalBackupFallsBackWhenNativeToolsAreMissing(t *testing.T) {
+	capabilities := withLogicalBackup(backupCapabilitiesFor(executableFixture(), "postgres"))
+	if !capabilities.Available ||
+		capabilities.Format != database.BackupFormatLogical ||
+		!capabilities.RestoreReady ||
+		capabilities.Extension != database.LogicalBackupExtension {
+		t.Fatalf("fallback capabilities = %+v", capabilities)
+	}
+	native := withLogicalBackup(backupCapabilitiesFor(executableFixture("pg_dump", "pg_restore"), "postgres"))
+	if native.Format != database.BackupFormatPostgresCustom || !native.LogicalAvailable {
+		t.Fatalf("native capabilities = %+v", native)
+	}
+}
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain points the user configuration directory at a temporary one so
+// services created with NewService never write settings, such as the
+// backup catalog, into the developer's real profile.
+func TestMain(m *testing.M) {
+	directory, err := os.MkdirTemp("", "rollingthunder-db-test-")
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME", "AppData"} {
+		_ = os.Setenv(name, directory)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(directory)
+	os.Exit(code)
+}
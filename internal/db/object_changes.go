@@ -5,6 +5,7 @@ import (
 	"crypto/subtle"
 	"net/http"
 	"strings"
+	"time"
 
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/response"
@@ -160,6 +161,7 @@ func (s *Service) ApplyDatabaseObjectChange(
 		)
 	}
 
+	startedAt := time.Now()
 	if err := changeDriver.ApplyObjectChange(ctx, plan); err != nil {
 		return serviceErrorWithCode[database.ObjectChangeResult](
 			http.StatusBadRequest,
@@ -169,6 +171,15 @@ func (s *Service) ApplyDatabaseObjectChange(
 			"The object list was not updated. Inspect database errors and review the SQL again.",
 		)
 	}
+	reference := request.Change.Reference
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationObjectChange,
+		StartedAt:    startedAt,
+		ConnectionID: connectionID,
+		Target:       auditTarget(reference.Schema, reference.Name),
+		Summary:      plan.Summary,
+		Statements:   auditStatements(plan.Statements),
+	})
 	return response.BaseResponse[database.ObjectChangeResult]{
 		Data: database.ObjectChangeResult{
 			Applied:        true,
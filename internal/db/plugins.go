@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database/plugin"
+	"rollingthunder/pkg/response"
+)
+
+// DriverPlugins reports the plugins directory and the outcome for every
+// allowlisted plugin, so a rejected checksum is visible instead of silent.
+type DriverPlugins struct {
+	Directory string          `json:"directory"`
+	Plugins   []plugin.Status `json:"plugins"`
+	Error     string          `json:"error,omitempty"`
+}
+
+var (
+	driverPluginsOnce   sync.Once
+	driverPluginsStatus DriverPlugins
+)
+
+// LoadDriverPlugins registers the allowlisted plugins from the settings
+// directory. It runs once per process, before any profile is opened, because
+// registered driver names cannot be replaced afterwards.
+func LoadDriverPlugins() DriverPlugins {
+	driverPluginsOnce.Do(func() {
+		driverPluginsStatus = loadDriverPlugins()
+	})
+	return driverPluginsStatus
+}
+
+func loadDriverPlugins() DriverPlugins {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return DriverPlugins{Error: fmt.Sprintf("resolve user configuration directory: %v", err)}
+	}
+	status := DriverPlugins{
+		Directory: filepath.Join(configDir, application.SettingsDirectoryName, "plugins"),
+	}
+	statuses, err := plugin.Discover(status.Directory)
+	if err != nil {
+		status.Error = err.Error()
+	}
+	status.Plugins = statuses
+	return status
+}
+
+// GetDriverPlugins returns the plugin discovery result for the settings view.
+func (s *Service) GetDriverPlugins() response.BaseResponse[DriverPlugins] {
+	return response.BaseResponse[DriverPlugins]{Data: LoadDriverPlugins()}
+}
@@ -173,9 +173,11 @@ func (s *Service) ExecuteQuery(
 	defer s.finishQueryAttempt(attempt)
 
 	inTransaction := strings.TrimSpace(request.TransactionID) != ""
+	startedAt := time.Now()
 	result, err := s.executeQueryBatch(ctx, request, statements)
 
 	if err != nil {
+		s.auditFailedQueryBatch(request, result, startedAt)
 		if attempt.cancelled.Load() {
 			return queryFailure[database.QueryResult](
 				context.Canceled,
@@ -210,11 +212,29 @@ func (s *Service) ExecuteQuery(
 		case errors.Is(err, errConnectionReadOnly):
 			return readOnlyConnectionError[database.QueryResult]()
 		}
-		return queryFailure[database.QueryResult](err, inTransaction)
+		failure := queryFailure[database.QueryResult](err, inTransaction)
+		if queryResultHasMessages(result) {
+			failure.Data = result
+		}
+		return failure
+	}
+	if keyword := database.FindWriteStatement(request.Query); keyword != "" {
+		s.auditQuery(request, result, startedAt, keyword, "")
 	}
 	return response.BaseResponse[database.QueryResult]{Data: result}
 }
 
+// queryResultHasMessages reports whether any statement of a failed batch
+// left server messages worth returning alongside the error.
+func queryResultHasMessages(result database.QueryResult) bool {
+	for _, set := range result.ResultSets {
+		if len(set.Messages) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 type queryStatementRunner func(
 	context.Context,
 	string,
@@ -288,8 +308,8 @@ func (s *Service) executeQueryBatch(
 	defer release()
 
 	batch := database.QueryResult{
-		Rows:           make([]map[string]interface{}, 0),
-		Columns:        make([]string, 0),
+		Rows:           make([][]interface{}, 0),
+		Columns:        make([]database.QueryColumn, 0),
 		RowLimit:       database.DefaultQueryResultLimit,
 		ResultSets:     make([]database.QueryResultSet, 0, len(statements)),
 		StatementCount: len(statements),
@@ -308,12 +328,25 @@ func (s *Service) executeQueryBatch(
 				err,
 			)
 		}
+		startedAt := time.Now()
 		result, err := run(ctx, boundQuery, database.QueryOptions{
-			MaxRows: database.DefaultQueryResultLimit,
-			Args:    args,
+			MaxRows:      database.DefaultQueryResultLimit,
+			Args:         args,
+			ServerOutput: request.ServerOutput,
 		})
+		elapsed := time.Since(startedAt)
 		if err != nil {
-			return database.QueryResult{}, fmt.Errorf(
+			// The sets that already ran and the failed statement's
+			// messages go back with the error.
+			batch.ResultSets = append(batch.ResultSets, database.QueryResultSet{
+				Index:       index,
+				Statement:   statement,
+				Columns:     make([]database.QueryColumn, 0),
+				Rows:        make([][]interface{}, 0),
+				WallClockMS: elapsed.Milliseconds(),
+				Messages:    result.Messages,
+			})
+			return batch, fmt.Errorf(
 				"statement %d of %d failed: %w",
 				index+1,
 				len(statements),
@@ -321,18 +354,22 @@ func (s *Service) executeQueryBatch(
 			)
 		}
 		set := database.QueryResultSet{
-			Index:     index,
-			Statement: statement,
-			Columns:   result.Columns,
-			Rows:      result.Rows,
-			Truncated: result.Truncated,
-			RowLimit:  result.RowLimit,
+			Index:        index,
+			Statement:    statement,
+			Columns:      result.Columns,
+			Rows:         result.Rows,
+			Truncated:    result.Truncated,
+			RowLimit:     result.RowLimit,
+			RowsAffected: result.RowsAffected,
+			CommandTag:   result.CommandTag,
+			WallClockMS:  elapsed.Milliseconds(),
+			Messages:     result.Messages,
 		}
 		if set.Columns == nil {
-			set.Columns = make([]string, 0)
+			set.Columns = make([]database.QueryColumn, 0)
 		}
 		if set.Rows == nil {
-			set.Rows = make([]map[string]interface{}, 0)
+			set.Rows = make([][]interface{}, 0)
 		}
 		batch.ResultSets = append(batch.ResultSets, set)
 	}
@@ -343,6 +380,9 @@ func (s *Service) executeQueryBatch(
 		batch.Columns = first.Columns
 		batch.Truncated = first.Truncated
 		batch.RowLimit = first.RowLimit
+		batch.RowsAffected = first.RowsAffected
+		batch.CommandTag = first.CommandTag
+		batch.Messages = first.Messages
 	}
 	return batch, nil
 }
@@ -356,6 +396,9 @@ type transactionSession struct {
 	startedAt    time.Time
 	mu           sync.Mutex
 	closed       bool
+	// auditedWrites counts the audited write batches run in the
+	// transaction; only those transactions record their outcome.
+	auditedWrites atomic.Int64
 }
 
 type TransactionInfo struct {
@@ -570,6 +613,22 @@ func (s *Service) finishTransaction(
 			"If the transaction was aborted, start a new transaction before retrying.",
 		)
 	}
+	if writes := session.auditedWrites.Load(); writes > 0 {
+		summary, batches := "Rolled back", "write batches"
+		if commit {
+			summary = "Committed"
+		}
+		if writes == 1 {
+			batches = "write batch"
+		}
+		s.recordAudit(database.AuditEntry{
+			Operation:     database.AuditOperationTransaction,
+			StartedAt:     session.startedAt,
+			ConnectionID:  session.connectionID,
+			TransactionID: session.id,
+			Summary:       fmt.Sprintf("%s a transaction with %d %s", summary, writes, batches),
+		})
+	}
 	return response.BaseResponse[TransactionInfo]{Data: info}
 }
 
@@ -46,13 +46,20 @@ func TestSQLiteQueryBatchVariablesAndExplain(t *testing.T) {
 	if result.Data.StatementCount != 3 || len(result.Data.ResultSets) != 3 {
 		t.Fatalf("batch result = %+v", result.Data)
 	}
-	rows := result.Data.ResultSets[2].Rows
+	rows := result.Data.ResultSets[2].RowMaps()
 	if len(rows) != 1 || rows[0]["name"] != "storm" {
 		t.Fatalf("select rows = %+v", rows)
 	}
 	if len(result.Data.ResultSets[2].Columns) != 2 {
 		t.Fatalf("select columns = %+v", result.Data.ResultSets[2].Columns)
 	}
+	inserted := result.Data.ResultSets[1]
+	if inserted.RowsAffected == nil || *inserted.RowsAffected != 1 {
+		t.Fatalf("insert rows affected = %v", inserted.RowsAffected)
+	}
+	if result.Data.ResultSets[2].RowsAffected != nil {
+		t.Fatalf("select reported rows affected = %d", *result.Data.ResultSets[2].RowsAffected)
+	}
 
 	plan := service.ExplainQuery(database.QueryRequest{
 		ConnectionID: connectionID,
@@ -136,6 +143,9 @@ func TestSQLiteRollbackRemovesWritesAndExplainRemainsAvailable(t *testing.T) {
 	if len(inserted.Errors) > 0 {
 		t.Fatalf("transaction insert errors = %+v", inserted.Errors)
 	}
+	if affected := inserted.Data.ResultSets[0].RowsAffected; affected == nil || *affected != 1 {
+		t.Fatalf("transaction insert rows affected = %v", affected)
+	}
 	rolledBack := service.RollbackTransaction(transactionID)
 	if len(rolledBack.Errors) > 0 || rolledBack.Data.State != "rolled_back" {
 		t.Fatalf("RollbackTransaction() = %+v", rolledBack)
@@ -148,7 +158,7 @@ func TestSQLiteRollbackRemovesWritesAndExplainRemainsAvailable(t *testing.T) {
 	if len(count.Errors) > 0 {
 		t.Fatalf("count query errors = %+v", count.Errors)
 	}
-	if len(count.Data.Rows) != 1 || fmt.Sprint(count.Data.Rows[0]["total"]) != "0" {
+	if len(count.Data.Rows) != 1 || fmt.Sprint(count.Data.RowMaps()[0]["total"]) != "0" {
 		t.Fatalf("rows after rollback = %+v", count.Data.Rows)
 	}
 
@@ -0,0 +1,475 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultQueryCursorIdleTimeout releases a cursor nobody has fetched
+	// from, since each one keeps a database connection checked out.
+	defaultQueryCursorIdleTimeout = 5 * time.Minute
+	maxQueryCursorsPerConnection  = 4
+	maxQueryCursorFetchRows       = 10 * database.DefaultQueryResultLimit
+)
+
+type queryCursorSession struct {
+	id           string
+	connectionID string
+	connection   *Connection
+	cursor       database.QueryCursor
+	ctx          context.Context
+	cancel       context.CancelFunc
+	idle         *time.Timer
+	mu           sync.Mutex
+	offset       int64
+	closed       bool
+
+	// fetchMu guards the running fetch's cancel function apart from mu,
+	// which the fetch holds, so closing can stop it without waiting.
+	fetchMu     sync.Mutex
+	fetchCancel context.CancelFunc
+	closing     bool
+}
+
+// fetchContext returns the context for one fetch. The caller holds
+// session.mu and calls the returned function when the fetch is done.
+func (session *queryCursorSession) fetchContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(session.ctx)
+	session.fetchMu.Lock()
+	if session.closing {
+		cancel()
+	}
+	session.fetchCancel = cancel
+	session.fetchMu.Unlock()
+	return ctx, func() {
+		session.fetchMu.Lock()
+		session.fetchCancel = nil
+		session.fetchMu.Unlock()
+		cancel()
+	}
+}
+
+// stopFetch cancels the fetch in flight, if any, and any fetch that starts
+// after it.
+func (session *queryCursorSession) stopFetch() {
+	session.fetchMu.Lock()
+	defer session.fetchMu.Unlock()
+	session.closing = true
+	if session.fetchCancel != nil {
+		session.fetchCancel()
+	}
+}
+
+// close releases the cursor's connection. The caller holds session.mu and
+// has already removed the session from s.queryCursors.
+func (session *queryCursorSession) close() {
+	if session.closed {
+		return
+	}
+	session.closed = true
+	session.idle.Stop()
+	_ = session.cursor.Close()
+	session.cancel()
+}
+
+func (s *Service) OpenQueryCursor(
+	request database.QueryRequest,
+) response.BaseResponse[database.QueryCursorPage] {
+	request.Query = strings.TrimSpace(request.Query)
+	statements, err := database.SplitSQLStatements(request.Query)
+	if err != nil {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid query",
+			err.Error(),
+			"Select the statement to open as a cursor.",
+		)
+	}
+	if len(statements) != 1 {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Cursors run one statement",
+			fmt.Sprintf("The selection contains %d statements.", len(statements)),
+			"Select a single SELECT statement or place the cursor inside one.",
+		)
+	}
+	if statement := database.FindWriteStatement(request.Query); statement != "" {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusConflict,
+			errorCodeInvalidRequest,
+			"Cursors are read-only",
+			fmt.Sprintf("%s cannot be held open as a result cursor.", statement),
+			"Run the statement normally, or open a cursor on a query that only reads.",
+		)
+	}
+	if strings.TrimSpace(request.TransactionID) != "" {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusConflict,
+			errorCodeInvalidRequest,
+			"Cursors run outside transactions",
+			"A result cursor uses its own connection and cannot see the open transaction.",
+			"Commit or roll back the transaction, or run the query without a cursor.",
+		)
+	}
+
+	attemptContext, attempt, err := s.startQueryAttempt(request.AttemptID)
+	if err != nil {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid query request",
+			err.Error(),
+			"Create a unique query attempt and try again.",
+		)
+	}
+	defer s.finishQueryAttempt(attempt)
+
+	connection, release, err := s.pinnedConnection(request.ConnectionID)
+	if err != nil {
+		return serviceError[database.QueryCursorPage](err.Error())
+	}
+	defer release()
+	cursorDriver, ok := connection.Driver.(database.CursorDriver)
+	if !ok || !connection.Driver.Capabilities().QueryCursors {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusNotImplemented,
+			errorCodeQueryFailed,
+			"Result cursors are not supported",
+			"The active database driver cannot hold a query result open.",
+			"Run the query normally; results stop at the row limit.",
+		)
+	}
+	if s.queryCursorCount(request.ConnectionID) >= maxQueryCursorsPerConnection {
+		return serviceErrorWithCode[database.QueryCursorPage](
+			http.StatusConflict,
+			errorCodeInvalidRequest,
+			"Too many open cursors",
+			fmt.Sprintf(
+				"This connection already has %d result cursors open.",
+				maxQueryCursorsPerConnection,
+			),
+			"Close a result cursor in another tab, or wait for an idle one to be released.",
+		)
+	}
+	boundQuery, args, err := database.BindQueryVariables(
+		statements[0],
+		connection.Driver,
+		request.Variables,
+	)
+	if err != nil {
+		return queryFailure[database.QueryCursorPage](err, false)
+	}
+
+	// The cursor outlives this call, so it gets its own context; the
+	// attempt context only cancels it while it is still being opened.
+	cursorContext, cancel := context.WithCancel(s.ctx)
+	stopCancel := context.AfterFunc(attemptContext, cancel)
+	cursor, err := cursorDriver.OpenQueryCursor(
+		cursorContext,
+		boundQuery,
+		database.QueryOptions{Args: args},
+	)
+	var batch database.QueryCursorBatch
+	if err == nil {
+		batch, err = cursor.Fetch(cursorContext, database.DefaultQueryResultLimit)
+		if err != nil {
+			_ = cursor.Close()
+		}
+	}
+	if !stopCancel() && err == nil {
+		_ = cursor.Close()
+		err = context.Canceled
+	}
+	if err != nil {
+		cancel()
+		if attempt.cancelled.Load() {
+			err = context.Canceled
+		}
+		return queryFailure[database.QueryCursorPage](err, false)
+	}
+
+	page := database.QueryCursorPage{
+		Columns: cursor.Columns(),
+		Rows:    batch.Rows,
+		Done:    batch.Done,
+	}
+	if batch.Done {
+		// Everything fit in the first page; there is nothing to hold open.
+		_ = cursor.Close()
+		cancel()
+		return response.BaseResponse[database.QueryCursorPage]{Data: page}
+	}
+
+	session := &queryCursorSession{
+		id:           uuid.NewString(),
+		connectionID: request.ConnectionID,
+		connection:   connection,
+		cursor:       cursor,
+		ctx:          cursorContext,
+		cancel:       cancel,
+		offset:       int64(len(batch.Rows)),
+	}
+	session.idle = time.AfterFunc(s.queryCursorIdleTimeout, func() {
+		s.expireQueryCursor(session)
+	})
+	s.queryCursorMu.Lock()
+	s.queryCursors[session.id] = session
+	s.queryCursorMu.Unlock()
+
+	page.CursorID = session.id
+	return response.BaseResponse[database.QueryCursorPage]{Data: page}
+}
+
+// FetchQueryCursor returns the next page of an open cursor. The cursor is
+// released once it reports that the result is exhausted.
+func (s *Service) FetchQueryCursor(
+	cursorID string,
+	maxRows int,
+) response.BaseResponse[database.QueryCursorPage] {
+	if maxRows <= 0 {
+		maxRows = database.DefaultQueryResultLimit
+	}
+	maxRows = min(maxRows, maxQueryCursorFetchRows)
+
+	session, release, err := s.pinnedQueryCursor(cursorID)
+	if err != nil {
+		return queryCursorNotFound[database.QueryCursorPage](err)
+	}
+	defer release()
+
+	session.idle.Stop()
+	ctx, done := session.fetchContext()
+	batch, err := session.cursor.Fetch(ctx, maxRows)
+	done()
+	if err != nil {
+		s.releaseQueryCursor(session)
+		return queryFailure[database.QueryCursorPage](err, false)
+	}
+
+	page := database.QueryCursorPage{
+		CursorID: session.id,
+		Columns:  session.cursor.Columns(),
+		Rows:     batch.Rows,
+		Offset:   session.offset,
+		Done:     batch.Done,
+	}
+	session.offset += int64(len(batch.Rows))
+	if batch.Done {
+		s.releaseQueryCursor(session)
+	} else {
+		session.idle.Reset(s.queryCursorIdleTimeout)
+	}
+	return response.BaseResponse[database.QueryCursorPage]{Data: page}
+}
+
+// CloseQueryCursor releases a cursor. A fetch still running holds the
+// cursor, so it is cancelled first rather than waited for; the cancelled
+// fetch releases the cursor itself.
+func (s *Service) CloseQueryCursor(cursorID string) response.BaseResponse[bool] {
+	s.queryCursorMu.Lock()
+	open := s.queryCursors[strings.TrimSpace(cursorID)]
+	s.queryCursorMu.Unlock()
+	if open != nil {
+		open.stopFetch()
+	}
+
+	session, release, err := s.pinnedQueryCursor(cursorID)
+	if err != nil {
+		if open != nil {
+			return response.BaseResponse[bool]{Data: true}
+		}
+		return queryCursorNotFound[bool](err)
+	}
+	defer release()
+	s.releaseQueryCursor(session)
+	return response.BaseResponse[bool]{Data: true}
+}
+
+// pinnedQueryCursor locks an open cursor and its connection for one
+// operation, in the same order Disconnect takes them.
+func (s *Service) pinnedQueryCursor(
+	cursorID string,
+) (*queryCursorSession, func(), error) {
+	s.queryCursorMu.Lock()
+	session := s.queryCursors[strings.TrimSpace(cursorID)]
+	s.queryCursorMu.Unlock()
+	if session == nil {
+		return nil, nil, fmt.Errorf("result cursor is closed or does not exist")
+	}
+
+	session.connection.mu.RLock()
+	if session.connection.closed {
+		session.connection.mu.RUnlock()
+		return nil, nil, fmt.Errorf("result cursor connection is disconnected")
+	}
+	session.mu.Lock()
+	if session.closed {
+		session.mu.Unlock()
+		session.connection.mu.RUnlock()
+		return nil, nil, fmt.Errorf("result cursor is closed or does not exist")
+	}
+	return session, func() {
+		session.mu.Unlock()
+		session.connection.mu.RUnlock()
+	}, nil
+}
+
+// releaseQueryCursor closes a cursor pinned by pinnedQueryCursor.
+func (s *Service) releaseQueryCursor(session *queryCursorSession) {
+	s.queryCursorMu.Lock()
+	if s.queryCursors[session.id] == session {
+		delete(s.queryCursors, session.id)
+	}
+	s.queryCursorMu.Unlock()
+	session.close()
+}
+
+func (s *Service) expireQueryCursor(session *queryCursorSession) {
+	pinned, release, err := s.pinnedQueryCursor(session.id)
+	if err != nil || pinned != session {
+		if err == nil {
+			release()
+		}
+		return
+	}
+	defer release()
+	s.releaseQueryCursor(session)
+}
+
+func (s *Service) queryCursorCount(connectionID string) int {
+	s.queryCursorMu.Lock()
+	defer s.queryCursorMu.Unlock()
+	count := 0
+	for _, session := range s.queryCursors {
+		if session.connectionID == connectionID {
+			count++
+		}
+	}
+	return count
+}
+
+// closeQueryCursorsForConnection is called while the connection write lock
+// is held, so no fetch or export can be reading from these cursors.
+func (s *Service) closeQueryCursorsForConnection(connectionID string) {
+	s.queryCursorMu.Lock()
+	sessions := make([]*queryCursorSession, 0)
+	for id, session := range s.queryCursors {
+		if session.connectionID == connectionID {
+			delete(s.queryCursors, id)
+			sessions = append(sessions, session)
+		}
+	}
+	s.queryCursorMu.Unlock()
+
+	for _, session := range sessions {
+		session.mu.Lock()
+		session.close()
+		session.mu.Unlock()
+	}
+}
+
+func queryCursorNotFound[T any](err error) response.BaseResponse[T] {
+	return serviceErrorWithCode[T](
+		http.StatusNotFound,
+		errorCodeQueryCursorNotFound,
+		"Result cursor is not open",
+		err.Error(),
+		"Run the query again to open a new cursor.",
+	)
+}
+
+// queryCursorRows streams the rows the grid already holds, followed by
+// whatever the cursor has not delivered yet, so an export covers the whole
+// result without running the query a second time.
+type queryCursorRows struct {
+	ctx      context.Context
+	columns  []string
+	buffered []map[string]interface{}
+	cursor   database.QueryCursor
+	page     [][]interface{}
+	done     bool
+	current  []interface{}
+	err      error
+}
+
+func (rows *queryCursorRows) Columns() ([]string, error) {
+	return append([]string(nil), rows.columns...), nil
+}
+
+func (rows *queryCursorRows) Next() bool {
+	if len(rows.buffered) > 0 {
+		row := rows.buffered[0]
+		rows.buffered = rows.buffered[1:]
+		rows.current = make([]interface{}, len(rows.columns))
+		for index, column := range rows.columns {
+			rows.current[index] = row[column]
+		}
+		return true
+	}
+	for len(rows.page) == 0 {
+		if rows.done || rows.err != nil {
+			return false
+		}
+		batch, err := rows.cursor.Fetch(rows.ctx, database.DefaultQueryResultLimit)
+		if err != nil {
+			rows.err = err
+			return false
+		}
+		rows.page = batch.Rows
+		rows.done = batch.Done
+	}
+	rows.current = rows.page[0]
+	rows.page = rows.page[1:]
+	return true
+}
+
+func (rows *queryCursorRows) Values() ([]interface{}, error) {
+	return rows.current, nil
+}
+
+func (rows *queryCursorRows) Err() error {
+	return rows.err
+}
+
+// exportQueryCursor writes the grid rows and the rest of the cursor. The
+// cursor is consumed by the export, so it is closed however the export ends.
+func (s *Service) exportQueryCursor(
+	ctx context.Context,
+	writer io.Writer,
+	request database.RowsExportRequest,
+) (database.ExportStats, error) {
+	session, release, err := s.pinnedQueryCursor(request.CursorID)
+	if err != nil {
+		return database.ExportStats{}, err
+	}
+	defer release()
+	defer s.releaseQueryCursor(session)
+	session.idle.Stop()
+
+	if width := len(session.cursor.Columns()); width != len(request.Columns) {
+		return database.ExportStats{}, fmt.Errorf(
+			"result cursor has %d columns but the export lists %d",
+			width,
+			len(request.Columns),
+		)
+	}
+	return database.WriteTypedExportStreamContext(ctx, writer, &queryCursorRows{
+		ctx:      ctx,
+		columns:  request.Columns,
+		buffered: request.Rows,
+		cursor:   session.cursor,
+	}, request.Structures, request.Options)
+}
@@ -0,0 +1,316 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rollingthunder/pkg/database"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+type cursorTestDriver struct {
+	*routingTestDriver
+	rows int
+
+	mu      sync.Mutex
+	cursors []*cursorTestCursor
+}
+
+func (d *cursorTestDriver) Capabilities() database.Capabilities {
+	capabilities := d.routingTestDriver.Capabilities()
+	capabilities.QueryCursors = true
+	return capabilities
+}
+
+func (d *cursorTestDriver) OpenQueryCursor(
+	ctx context.Context,
+	_ string,
+	_ database.QueryOptions,
+) (database.QueryCursor, error) {
+	cursor := &cursorTestCursor{ctx: ctx, total: d.rows}
+	d.mu.Lock()
+	d.cursors = append(d.cursors, cursor)
+	d.mu.Unlock()
+	return cursor, nil
+}
+
+func (d *cursorTestDriver) cursor(t *testing.T) *cursorTestCursor {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.cursors) != 1 {
+		t.Fatalf("opened cursors = %d, want 1", len(d.cursors))
+	}
+	return d.cursors[0]
+}
+
+type cursorTestCursor struct {
+	ctx   context.Context
+	total int
+	// fetchStarted, when set, makes the next fetch signal it and then wait
+	// until its context is cancelled.
+	fetchStarted chan struct{}
+
+	mu     sync.Mutex
+	next   int
+	closed bool
+}
+
+func (c *cursorTestCursor) Columns() []database.QueryColumn {
+	return []database.QueryColumn{{Name: "id"}}
+}
+
+func (c *cursorTestCursor) Fetch(
+	ctx context.Context,
+	maxRows int,
+) (database.QueryCursorBatch, error) {
+	if c.fetchStarted != nil {
+		close(c.fetchStarted)
+		<-ctx.Done()
+		return database.QueryCursorBatch{}, ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	batch := database.QueryCursorBatch{Rows: make([][]interface{}, 0)}
+	for len(batch.Rows) < maxRows && c.next < c.total {
+		batch.Rows = append(batch.Rows, []interface{}{c.next})
+		c.next++
+	}
+	batch.Done = c.next == c.total
+	return batch, nil
+}
+
+func (c *cursorTestCursor) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *cursorTestCursor) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed && c.ctx.Err() != nil
+}
+
+func newCursorTestService(rows int) (*Service, *cursorTestDriver) {
+	driver := &cursorTestDriver{
+		routingTestDriver: &routingTestDriver{name: "alpha"},
+		rows:              rows,
+	}
+	service := newRoutingTestService(nil, "alpha")
+	service.connections["alpha"] = &Connection{
+		ID:          "alpha",
+		Name:        "alpha",
+		Driver:      driver,
+		ConnectedAt: time.Now(),
+	}
+	return service, driver
+}
+
+func TestQueryCursorPagesPastTheRowLimit(t *testing.T) {
+	service, driver := newCursorTestService(2500)
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 {
+		t.Fatalf("OpenQueryCursor errors = %+v", opened.Errors)
+	}
+	if opened.Data.CursorID == "" || opened.Data.Done ||
+		len(opened.Data.Rows) != database.DefaultQueryResultLimit {
+		t.Fatalf("first page = id:%q rows:%d done:%t", opened.Data.CursorID, len(opened.Data.Rows), opened.Data.Done)
+	}
+
+	second := service.FetchQueryCursor(opened.Data.CursorID, 1000)
+	if len(second.Errors) != 0 || second.Data.Offset != 1000 ||
+		len(second.Data.Rows) != 1000 || second.Data.Done {
+		t.Fatalf("second page = %+v offset:%d rows:%d", second.Errors, second.Data.Offset, len(second.Data.Rows))
+	}
+	last := service.FetchQueryCursor(opened.Data.CursorID, 1000)
+	if len(last.Errors) != 0 || last.Data.Offset != 2000 ||
+		len(last.Data.Rows) != 500 || !last.Data.Done {
+		t.Fatalf("last page = %+v offset:%d rows:%d", last.Errors, last.Data.Offset, len(last.Data.Rows))
+	}
+	if last.Data.Rows[499][0] != 2499 {
+		t.Fatalf("last row = %v, want 2499", last.Data.Rows[499])
+	}
+	if !driver.cursor(t).isClosed() {
+		t.Fatal("exhausted cursor was not closed")
+	}
+	after := service.FetchQueryCursor(opened.Data.CursorID, 1000)
+	if len(after.Errors) == 0 || after.Errors[0].Code != errorCodeQueryCursorNotFound {
+		t.Fatalf("fetch after exhaustion = %+v", after.Errors)
+	}
+}
+
+func TestQueryCursorClosesWhenFirstPageHoldsEverything(t *testing.T) {
+	service, driver := newCursorTestService(3)
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 || opened.Data.CursorID != "" ||
+		!opened.Data.Done || len(opened.Data.Rows) != 3 {
+		t.Fatalf("OpenQueryCursor = %+v", opened)
+	}
+	if !driver.cursor(t).isClosed() {
+		t.Fatal("cursor was held open for a complete result")
+	}
+}
+
+func TestOpenQueryCursorRejectsWritesAndBatches(t *testing.T) {
+	service, _ := newCursorTestService(1)
+
+	for _, query := range []string{
+		"delete from events where id = 1",
+		"select 1; select 2",
+	} {
+		result := service.OpenQueryCursor(database.QueryRequest{
+			ConnectionID: "alpha",
+			Query:        query,
+		})
+		if len(result.Errors) == 0 {
+			t.Fatalf("OpenQueryCursor(%q) accepted the statement", query)
+		}
+	}
+}
+
+func TestIdleQueryCursorReleasesItsConnection(t *testing.T) {
+	service, driver := newCursorTestService(2500)
+	service.queryCursorIdleTimeout = 20 * time.Millisecond
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 {
+		t.Fatalf("OpenQueryCursor errors = %+v", opened.Errors)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !driver.cursor(t).isClosed() {
+		if time.Now().After(deadline) {
+			t.Fatal("idle cursor was not closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count := service.queryCursorCount("alpha"); count != 0 {
+		t.Fatalf("open cursors = %d, want 0", count)
+	}
+}
+
+func TestDisconnectClosesQueryCursors(t *testing.T) {
+	service, driver := newCursorTestService(2500)
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 {
+		t.Fatalf("OpenQueryCursor errors = %+v", opened.Errors)
+	}
+	if result := service.DisconnectConnection("alpha"); len(result.Errors) != 0 {
+		t.Fatalf("DisconnectConnection errors = %+v", result.Errors)
+	}
+	if !driver.cursor(t).isClosed() {
+		t.Fatal("disconnect left the cursor open")
+	}
+}
+
+func TestCloseQueryCursorCancelsARunningFetch(t *testing.T) {
+	service, driver := newCursorTestService(2500)
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 {
+		t.Fatalf("OpenQueryCursor errors = %+v", opened.Errors)
+	}
+	cursor := driver.cursor(t)
+	cursor.fetchStarted = make(chan struct{})
+	fetched := make(chan []string, 1)
+	go func() {
+		result := service.FetchQueryCursor(opened.Data.CursorID, 500)
+		codes := make([]string, 0, len(result.Errors))
+		for _, serviceErr := range result.Errors {
+			codes = append(codes, serviceErr.Code)
+		}
+		fetched <- codes
+	}()
+	<-cursor.fetchStarted
+
+	closed := make(chan bool, 1)
+	go func() {
+		closed <- service.CloseQueryCursor(opened.Data.CursorID).Data
+	}()
+	select {
+	case ok := <-closed:
+		if !ok {
+			t.Fatal("CloseQueryCursor did not report the cursor closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CloseQueryCursor waited for the running fetch")
+	}
+	if codes := <-fetched; len(codes) != 1 || codes[0] != errorCodeQueryCancelled {
+		t.Fatalf("cancelled fetch errors = %v", codes)
+	}
+	if !cursor.isClosed() {
+		t.Fatal("closing left the cursor open")
+	}
+}
+
+func TestExportQueryResultsStreamsTheRestOfACursor(t *testing.T) {
+	service, driver := newCursorTestService(2500)
+	target := filepath.Join(t.TempDir(), "events.csv")
+	service.saveDialog = func(
+		context.Context,
+		wailsruntime.SaveDialogOptions,
+	) (string, error) {
+		return target, nil
+	}
+
+	opened := service.OpenQueryCursor(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "select id from events",
+	})
+	if len(opened.Errors) != 0 {
+		t.Fatalf("OpenQueryCursor errors = %+v", opened.Errors)
+	}
+	rows := make([]map[string]interface{}, len(opened.Data.Rows))
+	for index, row := range opened.Data.Rows {
+		rows[index] = map[string]interface{}{"id": row[0]}
+	}
+
+	exported := service.ExportQueryResults(database.RowsExportRequest{
+		Columns:  []string{"id"},
+		Rows:     rows,
+		CursorID: opened.Data.CursorID,
+		Options:  csvExportOptions(),
+	})
+	if len(exported.Errors) != 0 {
+		t.Fatalf("ExportQueryResults errors = %+v", exported.Errors)
+	}
+	if exported.Data.Rows != 2500 {
+		t.Fatalf("exported rows = %d, want 2500", exported.Data.Rows)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2501 || lines[1] != "0" || lines[1001] != "1000" || lines[2500] != "2499" {
+		t.Fatalf("export has %d lines, first %q", len(lines), lines[1])
+	}
+	if !driver.cursor(t).isClosed() {
+		t.Fatal("export left the cursor open")
+	}
+}
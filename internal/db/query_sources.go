@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+// DescribeQuerySources resolves the source table and column of each result
+// column of one statement without running it. Query results leave these
+// empty on engines that can only learn them this way, so callers ask only
+// when they need them.
+func (s *Service) DescribeQuerySources(
+	request database.QueryRequest,
+) response.BaseResponse[[]database.QueryColumn] {
+	statements, err := database.SplitSQLStatements(strings.TrimSpace(request.Query))
+	if err != nil || len(statements) != 1 {
+		detail := "Column sources are described for exactly one SQL statement."
+		if err != nil {
+			detail = err.Error()
+		}
+		return serviceErrorWithCode[[]database.QueryColumn](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Cannot describe query batch",
+			detail,
+			"Select one statement in the editor and try again.",
+		)
+	}
+
+	driver, release, err := s.driverFor(request.ConnectionID)
+	if err != nil {
+		return serviceError[[]database.QueryColumn](err.Error())
+	}
+	defer release()
+	sourceDriver, ok := driver.(database.QuerySourceDriver)
+	if !ok {
+		return serviceErrorWithCode[[]database.QueryColumn](
+			http.StatusNotImplemented,
+			errorCodeObjectMetadataUnsupported,
+			"Column sources are unavailable",
+			"The active database driver reports column sources with query results, if at all.",
+			"Use the columns returned with the query result instead.",
+		)
+	}
+	boundQuery, _, err := database.BindQueryVariables(
+		statements[0],
+		driver,
+		request.Variables,
+	)
+	if err != nil {
+		return serviceErrorWithCode[[]database.QueryColumn](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Query variables are incomplete",
+			err.Error(),
+			"Provide a value for every {{variable}} before describing the query.",
+		)
+	}
+
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, querySourcesTimeout)
+	defer cancel()
+	columns, err := sourceDriver.DescribeQuerySources(ctx, boundQuery)
+	if err != nil {
+		return queryFailure[[]database.QueryColumn](err, false)
+	}
+	return response.BaseResponse[[]database.QueryColumn]{Data: columns}
+}
@@ -20,6 +20,8 @@ type routingTestTransaction struct {
 	queryRelease chan struct{}
 	startOnce    sync.Once
 	queryErr     error
+	messages     []database.QueryMessage
+	serverOutput bool
 	commitErr    error
 	rollbackErr  error
 	commits      int
@@ -33,6 +35,7 @@ func (transaction *routingTestTransaction) ExecuteQuery(
 ) (database.QueryResult, error) {
 	transaction.mu.Lock()
 	transaction.queries = append(transaction.queries, query)
+	transaction.serverOutput = options.ServerOutput
 	queryErr := transaction.queryErr
 	transaction.mu.Unlock()
 
@@ -49,11 +52,13 @@ func (transaction *routingTestTransaction) ExecuteQuery(
 		}
 	}
 	if queryErr != nil {
-		return database.QueryResult{}, queryErr
+		return database.QueryResult{Messages: transaction.messages}, queryErr
 	}
 	return database.QueryResult{
-		Rows:     []map[string]interface{}{{"transaction": true}},
+		Columns:  []database.QueryColumn{{Name: "transaction"}},
+		Rows:     [][]interface{}{{true}},
 		RowLimit: options.MaxRows,
+		Messages: transaction.messages,
 	}, nil
 }
 
@@ -181,6 +186,27 @@ func TestExecuteQueryRejectsRawTransactionControl(t *testing.T) {
 	}
 }
 
+func TestDescribeQuerySourcesRequiresASourceDriver(t *testing.T) {
+	driver := &routingTestDriver{name: "alpha"}
+	service := newRoutingTestService(
+		map[string]*routingTestDriver{"alpha": driver},
+		"alpha",
+	)
+
+	result := service.DescribeQuerySources(database.QueryRequest{
+		ConnectionID: "alpha",
+		Query:        "SELECT 1",
+	})
+
+	if len(result.Errors) != 1 ||
+		result.Errors[0].Code != errorCodeObjectMetadataUnsupported {
+		t.Fatalf("describe errors = %+v", result.Errors)
+	}
+	if driver.queryCount() != 0 {
+		t.Fatal("describing column sources ran the query")
+	}
+}
+
 func TestExecuteQueryReportsMissingTransactionWithStableCode(t *testing.T) {
 	driver := &routingTestDriver{name: "alpha"}
 	service := newRoutingTestService(
@@ -288,6 +314,84 @@ func TestExplicitTransactionRoutesQueriesAndCommits(t *testing.T) {
 	}
 }
 
+func TestTransactionQueriesReturnServerMessagesPerStatement(t *testing.T) {
+	transaction := &routingTestTransaction{
+		messages: []database.QueryMessage{
+			{Sequence: 1, Severity: database.QueryMessageInfo, Text: "first line"},
+			{Sequence: 2, Severity: database.QueryMessageInfo, Text: "second line"},
+		},
+	}
+	driver := &routingTestDriver{
+		name:        "alpha",
+		transaction: transaction,
+	}
+	service := newRoutingTestService(
+		map[string]*routingTestDriver{"alpha": driver},
+		"alpha",
+	)
+	if begin := service.BeginTransaction("alpha", "output-transaction"); len(begin.Errors) != 0 {
+		t.Fatalf("BeginTransaction errors = %+v", begin.Errors)
+	}
+
+	result := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID:  "alpha",
+		Query:         "select 1; select 2",
+		TransactionID: "output-transaction",
+		ServerOutput:  true,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("transaction query errors = %+v", result.Errors)
+	}
+	transaction.mu.Lock()
+	serverOutput := transaction.serverOutput
+	transaction.mu.Unlock()
+	if !serverOutput {
+		t.Fatal("server output option did not reach the transaction")
+	}
+	if len(result.Data.ResultSets) != 2 {
+		t.Fatalf("result sets = %d, want 2", len(result.Data.ResultSets))
+	}
+	for _, set := range result.Data.ResultSets {
+		if len(set.Messages) != 2 || set.Messages[1].Text != "second line" {
+			t.Fatalf("statement %d messages = %+v", set.Index, set.Messages)
+		}
+	}
+	_ = service.RollbackTransaction("output-transaction")
+}
+
+func TestFailedQueryReturnsServerMessagesWithTheError(t *testing.T) {
+	transaction := &routingTestTransaction{
+		queryErr: errors.New("Error 1366: Incorrect integer value"),
+		messages: []database.QueryMessage{
+			{Sequence: 1, Severity: database.QueryMessageWarning, Code: "1265", Text: "Data truncated"},
+		},
+	}
+	driver := &routingTestDriver{
+		name:        "alpha",
+		transaction: transaction,
+	}
+	service := newRoutingTestService(
+		map[string]*routingTestDriver{"alpha": driver},
+		"alpha",
+	)
+	if begin := service.BeginTransaction("alpha", "warning-transaction"); len(begin.Errors) != 0 {
+		t.Fatalf("BeginTransaction errors = %+v", begin.Errors)
+	}
+
+	result := service.ExecuteQuery(database.QueryRequest{
+		ConnectionID:  "alpha",
+		Query:         "insert into events values ('x')",
+		TransactionID: "warning-transaction",
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("failed query errors = %+v", result.Errors)
+	}
+	sets := result.Data.ResultSets
+	if len(sets) != 1 || len(sets[0].Messages) != 1 || sets[0].Messages[0].Code != "1265" {
+		t.Fatalf("failed query result sets = %+v", sets)
+	}
+}
+
 func TestTransactionContextLivesUntilTransactionFinishes(t *testing.T) {
 	transaction := &routingTestTransaction{}
 	driver := &routingTestDriver{
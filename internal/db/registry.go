@@ -2,14 +2,20 @@ package db
 
 import (
 	"context"
-	"fmt"
 
 	"rollingthunder/pkg/database"
-	"rollingthunder/pkg/database/mysql"
-	"rollingthunder/pkg/database/oracle"
-	"rollingthunder/pkg/database/postgres"
-	sqlitedriver "rollingthunder/pkg/database/sqlite"
-	"rollingthunder/pkg/database/sqlserver"
+	"rollingthunder/pkg/response"
+
+	// Built-in engines register themselves with database.RegisterDriver.
+	// In-house engines only need their own import in the application binary.
+	// DuckDB registers only in cgo builds.
+	_ "rollingthunder/pkg/database/clickhouse"
+	_ "rollingthunder/pkg/database/duckdb"
+	_ "rollingthunder/pkg/database/mysql"
+	_ "rollingthunder/pkg/database/oracle"
+	_ "rollingthunder/pkg/database/postgres"
+	_ "rollingthunder/pkg/database/sqlite"
+	_ "rollingthunder/pkg/database/sqlserver"
 )
 
 type driverFactory func(
@@ -19,72 +25,20 @@ type driverFactory func(
 ) (database.Driver, error)
 
 func NewDriver(ctx context.Context, driver string, cfg database.Config) (database.Driver, error) {
-	switch driver {
-	case database.DriverPostgres:
-		return postgres.NewPostgres(ctx, postgres.Config{
-			Host:          cfg.Host,
-			Port:          cfg.Port,
-			User:          cfg.User,
-			Password:      cfg.Password,
-			Db:            cfg.Db,
-			SSLMode:       cfg.SSLMode,
-			SSLRootCert:   cfg.SSLRootCert,
-			SSLCert:       cfg.SSLCert,
-			SSLKey:        cfg.SSLKey,
-			TLSServerName: cfg.TLSServerName,
-		}), nil
-	case database.DriverMySQL, database.DriverMariaDB:
-		return mysql.NewMySQL(ctx, mysql.Config{
-			Host:          cfg.Host,
-			Port:          cfg.Port,
-			User:          cfg.User,
-			Password:      cfg.Password,
-			Db:            cfg.Db,
-			SSLMode:       cfg.SSLMode,
-			SSLRootCert:   cfg.SSLRootCert,
-			SSLCert:       cfg.SSLCert,
-			SSLKey:        cfg.SSLKey,
-			TLSServerName: cfg.TLSServerName,
-		}), nil
-	case database.DriverSQLite:
-		return sqlitedriver.NewSQLite(ctx, sqlitedriver.Config{
-			Db: cfg.Db,
-		}), nil
-	case database.DriverOracle:
-		return oracle.NewOracle(ctx, oracle.Config{
-			Host:           cfg.Host,
-			Port:           cfg.Port,
-			User:           cfg.User,
-			Password:       cfg.Password,
-			Db:             cfg.Db,
-			SSLMode:        cfg.SSLMode,
-			SSLRootCert:    cfg.SSLRootCert,
-			SSLCert:        cfg.SSLCert,
-			SSLKey:         cfg.SSLKey,
-			TLSServerName:  cfg.TLSServerName,
-			ConnectionMode: cfg.OracleConnectionMode,
-			TNSConfigPath:  cfg.OracleTNSConfigPath,
-			TNSAlias:       cfg.OracleTNSAlias,
-			WalletPath:     cfg.OracleWalletPath,
-			WalletPassword: cfg.OracleWalletPassword,
-		}), nil
-	case database.DriverSQLServer:
-		return sqlserver.NewSQLServer(ctx, sqlserver.Config{
-			Host:          cfg.Host,
-			Port:          cfg.Port,
-			User:          cfg.User,
-			Password:      cfg.Password,
-			Db:            cfg.Db,
-			SSLMode:       cfg.SSLMode,
-			SSLRootCert:   cfg.SSLRootCert,
-			SSLCert:       cfg.SSLCert,
-			SSLKey:        cfg.SSLKey,
-			TLSServerName: cfg.TLSServerName,
-			AuthMode:      cfg.SQLServerAuthMode,
-			EntraClientID: cfg.SQLServerEntraClientID,
-			EntraTenantID: cfg.SQLServerEntraTenantID,
-		}), nil
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", driver)
+	return database.OpenDriver(ctx, driver, cfg)
+}
+
+// GetAvailableDrivers lists every registered engine for the connection form,
+// including in-house drivers, with the capabilities each one advertises.
+func (s *Service) GetAvailableDrivers() response.BaseResponse[[]database.DriverProbe] {
+	names := database.RegisteredDrivers()
+	drivers := make([]database.DriverProbe, 0, len(names))
+	for _, name := range names {
+		probe, err := database.ProbeDriver(name, database.ExecutableLookup(s.lookPath))
+		if err != nil {
+			continue
+		}
+		drivers = append(drivers, probe)
 	}
+	return response.BaseResponse[[]database.DriverProbe]{Data: drivers}
 }
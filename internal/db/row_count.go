@@ -0,0 +1,267 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// rowEstimateTimeout bounds the statistics or planner lookup. Past it
+	// the estimate is abandoned for an exact count.
+	rowEstimateTimeout        = 5 * time.Second
+	maxRowCountsPerConnection = 4
+	// defaultRowCountRetention is how long a finished count stays
+	// readable, so a late poll still sees the result.
+	defaultRowCountRetention = 10 * time.Minute
+)
+
+const (
+	rowCountRunning   = "running"
+	rowCountDone      = "done"
+	rowCountFailed    = "failed"
+	rowCountCancelled = "cancelled"
+)
+
+type rowCountJob struct {
+	id           string
+	connectionID string
+	cancel       context.CancelFunc
+	startedAt    time.Time
+
+	mu       sync.Mutex
+	status   string
+	rows     int64
+	err      string
+	finished time.Time
+}
+
+func (job *rowCountJob) progress() database.RowCountJob {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	end := job.finished
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return database.RowCountJob{
+		JobID:       job.id,
+		Status:      job.status,
+		Rows:        job.rows,
+		Error:       job.err,
+		ElapsedMS:   end.Sub(job.startedAt).Milliseconds(),
+		Cancellable: job.status == rowCountRunning,
+	}
+}
+
+// finish records the outcome unless the job was cancelled first, in which
+// case a count that still completed is discarded.
+func (job *rowCountJob) finish(status string, rows int64, err error) bool {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.status != rowCountRunning {
+		return false
+	}
+	job.status = status
+	job.rows = rows
+	if err != nil {
+		job.err = err.Error()
+	}
+	job.finished = time.Now()
+	return true
+}
+
+// EstimateCollectionData returns a row count for the grid without scanning
+// the table when the engine keeps statistics. Without an estimate it falls
+// back to an exact count, which is flagged as such.
+func (s *Service) EstimateCollectionData(
+	connectionID string,
+	table database.Table,
+) response.BaseResponse[database.RowCount] {
+	driver, release, err := s.driverFor(connectionID)
+	if err != nil {
+		return serviceError[database.RowCount](err.Error())
+	}
+	defer release()
+
+	if estimator, ok := driver.(database.RowEstimateDriver); ok &&
+		driver.Capabilities().RowEstimates {
+		parent := s.ctx
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, rowEstimateTimeout)
+		estimate, ok, err := estimator.EstimateCollectionData(ctx, table)
+		cancel()
+		if err == nil && ok {
+			return response.BaseResponse[database.RowCount]{Data: estimate}
+		}
+	}
+
+	count, err := driver.CountCollectionData(table)
+	if err != nil {
+		return serviceError[database.RowCount](err.Error())
+	}
+	return response.BaseResponse[database.RowCount]{
+		Data: database.RowCount{Rows: int64(count), Source: database.RowCountExact},
+	}
+}
+
+// StartRowCount runs COUNT(*) for table in the background. The job holds
+// the connection until it finishes, and its result is read by polling
+// GetRowCount. Only drivers that can cancel the count run it, so cancelling
+// or disconnecting never waits for a count that cannot be stopped.
+func (s *Service) StartRowCount(
+	connectionID string,
+	table database.Table,
+) response.BaseResponse[database.RowCountJob] {
+	connection, release, err := s.pinnedConnection(connectionID)
+	if err != nil {
+		return serviceError[database.RowCountJob](err.Error())
+	}
+	counter, ok := connection.Driver.(database.ContextCountDriver)
+	if !ok {
+		release()
+		return serviceErrorWithCode[database.RowCountJob](
+			http.StatusNotImplemented,
+			errorCodeRowCountUnsupported,
+			"Background counts are not supported",
+			"The active database driver cannot cancel a running row count.",
+			"Run SELECT COUNT(*) from the query editor instead.",
+		)
+	}
+
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	job := &rowCountJob{
+		id:           uuid.NewString(),
+		connectionID: connectionID,
+		cancel:       cancel,
+		startedAt:    time.Now(),
+		status:       rowCountRunning,
+	}
+	// The limit is checked and the job registered under one lock, so
+	// concurrent starts cannot all pass the check.
+	s.rowCountMu.Lock()
+	if s.runningRowCountsLocked(connectionID) >= maxRowCountsPerConnection {
+		s.rowCountMu.Unlock()
+		cancel()
+		release()
+		return serviceErrorWithCode[database.RowCountJob](
+			http.StatusTooManyRequests,
+			errorCodeInvalidRequest,
+			"Too many row counts",
+			fmt.Sprintf(
+				"This connection is already running %d row counts.",
+				maxRowCountsPerConnection,
+			),
+			"Wait for a count to finish or cancel one.",
+		)
+	}
+	s.rowCountJobs[job.id] = job
+	s.rowCountMu.Unlock()
+
+	go func() {
+		defer release()
+		defer cancel()
+		count, err := counter.CountCollectionDataContext(ctx, table)
+		status := rowCountDone
+		if err != nil {
+			status = rowCountFailed
+		}
+		if job.finish(status, int64(count), err) {
+			s.expireRowCount(job)
+		}
+	}()
+
+	return response.BaseResponse[database.RowCountJob]{Data: job.progress()}
+}
+
+func (s *Service) GetRowCount(jobID string) response.BaseResponse[database.RowCountJob] {
+	job := s.rowCountJob(jobID)
+	if job == nil {
+		return rowCountNotFound[database.RowCountJob]()
+	}
+	return response.BaseResponse[database.RowCountJob]{Data: job.progress()}
+}
+
+// CancelRowCount stops a running count and discards any result it still
+// returns.
+func (s *Service) CancelRowCount(jobID string) response.BaseResponse[bool] {
+	job := s.rowCountJob(jobID)
+	if job == nil {
+		return rowCountNotFound[bool]()
+	}
+	if job.finish(rowCountCancelled, 0, nil) {
+		job.cancel()
+		s.expireRowCount(job)
+	}
+	return response.BaseResponse[bool]{Data: true}
+}
+
+func rowCountNotFound[T any]() response.BaseResponse[T] {
+	return serviceErrorWithCode[T](
+		http.StatusNotFound,
+		errorCodeRowCountNotFound,
+		"Row count not found",
+		"The row count was cancelled, has expired, or does not exist.",
+		"Start a new exact count.",
+	)
+}
+
+func (s *Service) rowCountJob(jobID string) *rowCountJob {
+	s.rowCountMu.Lock()
+	defer s.rowCountMu.Unlock()
+	return s.rowCountJobs[strings.TrimSpace(jobID)]
+}
+
+func (s *Service) expireRowCount(job *rowCountJob) {
+	time.AfterFunc(s.rowCountRetention, func() {
+		s.rowCountMu.Lock()
+		if s.rowCountJobs[job.id] == job {
+			delete(s.rowCountJobs, job.id)
+		}
+		s.rowCountMu.Unlock()
+	})
+}
+
+// runningRowCountsLocked counts the jobs still running on connectionID.
+// The caller holds rowCountMu.
+func (s *Service) runningRowCountsLocked(connectionID string) int {
+	running := 0
+	for _, job := range s.rowCountJobs {
+		if job.connectionID == connectionID && job.progress().Status == rowCountRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// cancelRowCountsForConnection runs before Disconnect waits for the
+// connection, since every running count holds it until the count returns.
+func (s *Service) cancelRowCountsForConnection(connectionID string) {
+	s.rowCountMu.Lock()
+	jobs := make([]*rowCountJob, 0)
+	for id, job := range s.rowCountJobs {
+		if job.connectionID == connectionID {
+			delete(s.rowCountJobs, id)
+			jobs = append(jobs, job)
+		}
+	}
+	s.rowCountMu.Unlock()
+
+	for _, job := range jobs {
+		job.finish(rowCountCancelled, 0, nil)
+		job.cancel()
+	}
+}
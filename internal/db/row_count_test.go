@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"rollingthunder/pkg/database"
+)
+
+type rowCountTestDriver struct {
+	*routingTestDriver
+	estimate   database.RowCount
+	estimated  bool
+	exact      int
+	release    chan struct{}
+	cancelled  chan struct{}
+	exactCalls int
+}
+
+func (d *rowCountTestDriver) Capabilities() database.Capabilities {
+	capabilities := d.routingTestDriver.Capabilities()
+	capabilities.RowEstimates = true
+	return capabilities
+}
+
+func (d *rowCountTestDriver) EstimateCollectionData(
+	context.Context,
+	database.Table,
+) (database.RowCount, bool, error) {
+	return d.estimate, d.estimated, nil
+}
+
+func (d *rowCountTestDriver) CountCollectionData(table database.Table) (int, error) {
+	d.exactCalls++
+	return d.exact, nil
+}
+
+// CountCollectionDataContext blocks until the test releases it or the job
+// is cancelled.
+func (d *rowCountTestDriver) CountCollectionDataContext(
+	ctx context.Context,
+	_ database.Table,
+) (int, error) {
+	select {
+	case <-d.release:
+		return d.exact, nil
+	case <-ctx.Done():
+		close(d.cancelled)
+		return 0, ctx.Err()
+	}
+}
+
+func newRowCountTestService() (*Service, *rowCountTestDriver) {
+	driver := &rowCountTestDriver{
+		routingTestDriver: &routingTestDriver{name: "alpha"},
+		exact:             1234,
+		release:           make(chan struct{}),
+		cancelled:         make(chan struct{}),
+	}
+	service := newRoutingTestService(nil, "alpha")
+	service.connections["alpha"] = &Connection{
+		ID:          "alpha",
+		Name:        "alpha",
+		Driver:      driver,
+		ConnectedAt: time.Now(),
+	}
+	return service, driver
+}
+
+func waitForRowCount(t *testing.T, service *Service, jobID string, status string) database.RowCountJob {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		result := service.GetRowCount(jobID)
+		if len(result.Errors) != 0 {
+			t.Fatalf("GetRowCount errors = %+v", result.Errors)
+		}
+		if result.Data.Status == status {
+			return result.Data
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("row count status = %q, want %q", result.Data.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEstimateCollectionDataPrefersStatistics(t *testing.T) {
+	service, driver := newRowCountTestService()
+	driver.estimate = database.RowCount{Rows: 1200, Estimated: true, Source: database.RowCountStatistics}
+	driver.estimated = true
+
+	result := service.EstimateCollectionData("alpha", database.Table{Name: "events"})
+	if len(result.Errors) != 0 || result.Data != driver.estimate {
+		t.Fatalf("EstimateCollectionData = %+v", result)
+	}
+	if driver.exactCalls != 0 {
+		t.Fatalf("exact counts = %d, want 0", driver.exactCalls)
+	}
+
+	driver.estimated = false
+	result = service.EstimateCollectionData("alpha", database.Table{Name: "events"})
+	want := database.RowCount{Rows: 1234, Source: database.RowCountExact}
+	if len(result.Errors) != 0 || result.Data != want {
+		t.Fatalf("EstimateCollectionData without statistics = %+v, want %+v", result, want)
+	}
+}
+
+func TestRowCountJobReportsTheExactCount(t *testing.T) {
+	service, driver := newRowCountTestService()
+
+	started := service.StartRowCount("alpha", database.Table{Name: "events"})
+	if len(started.Errors) != 0 || started.Data.Status != rowCountRunning || !started.Data.Cancellable {
+		t.Fatalf("StartRowCount = %+v", started)
+	}
+	close(driver.release)
+
+	done := waitForRowCount(t, service, started.Data.JobID, rowCountDone)
+	if done.Rows != 1234 || done.Cancellable {
+		t.Fatalf("finished row count = %+v", done)
+	}
+}
+
+func TestCancelRowCountStopsTheQuery(t *testing.T) {
+	service, driver := newRowCountTestService()
+
+	started := service.StartRowCount("alpha", database.Table{Name: "events"})
+	if len(started.Errors) != 0 {
+		t.Fatalf("StartRowCount errors = %+v", started.Errors)
+	}
+	if result := service.CancelRowCount(started.Data.JobID); len(result.Errors) != 0 {
+		t.Fatalf("CancelRowCount errors = %+v", result.Errors)
+	}
+	select {
+	case <-driver.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not reach the count query")
+	}
+	cancelled := waitForRowCount(t, service, started.Data.JobID, rowCountCancelled)
+	if cancelled.Rows != 0 || cancelled.Error != "" {
+		t.Fatalf("cancelled row count = %+v", cancelled)
+	}
+}
+
+func TestDisconnectCancelsRunningRowCounts(t *testing.T) {
+	service, driver := newRowCountTestService()
+
+	started := service.StartRowCount("alpha", database.Table{Name: "events"})
+	if len(started.Errors) != 0 {
+		t.Fatalf("StartRowCount errors = %+v", started.Errors)
+	}
+	disconnected := make(chan struct{})
+	go func() {
+		service.DisconnectConnection("alpha")
+		close(disconnected)
+	}()
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		t.Fatal("disconnect waited for the running count")
+	}
+	select {
+	case <-driver.cancelled:
+	default:
+		t.Fatal("disconnect left the count running")
+	}
+	if result := service.GetRowCount(started.Data.JobID); len(result.Errors) == 0 ||
+		result.Errors[0].Code != errorCodeRowCountNotFound {
+		t.Fatalf("GetRowCount after disconnect = %+v", result)
+	}
+}
+
+func TestRowCountJobsAreLimitedPerConnection(t *testing.T) {
+	service, driver := newRowCountTestService()
+	defer close(driver.release)
+
+	for index := 0; index < maxRowCountsPerConnection; index++ {
+		if result := service.StartRowCount("alpha", database.Table{Name: "events"}); len(result.Errors) != 0 {
+			t.Fatalf("StartRowCount %d errors = %+v", index, result.Errors)
+		}
+	}
+	if result := service.StartRowCount("alpha", database.Table{Name: "events"}); len(result.Errors) == 0 {
+		t.Fatal("StartRowCount accepted a count past the limit")
+	}
+}
+
+func TestConcurrentRowCountStartsRespectTheLimit(t *testing.T) {
+	service, driver := newRowCountTestService()
+	defer close(driver.release)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted int
+	)
+	start := make(chan struct{})
+	for range maxRowCountsPerConnection * 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if result := service.StartRowCount("alpha", database.Table{Name: "events"}); len(result.Errors) == 0 {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if accepted != maxRowCountsPerConnection {
+		t.Fatalf("accepted %d concurrent counts, want %d", accepted, maxRowCountsPerConnection)
+	}
+}
+
+func TestRowCountJobsRequireCancellableCounts(t *testing.T) {
+	service := newRoutingTestService(
+		map[string]*routingTestDriver{"alpha": {name: "alpha"}},
+		"alpha",
+	)
+
+	result := service.StartRowCount("alpha", database.Table{Name: "events"})
+	if len(result.Errors) != 1 || result.Errors[0].Code != errorCodeRowCountUnsupported {
+		t.Fatalf("StartRowCount = %+v, want an unsupported error", result)
+	}
+	disconnected := make(chan struct{})
+	go func() {
+		service.DisconnectConnection("alpha")
+		close(disconnected)
+	}()
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		t.Fatal("a refused count kept the connection pinned")
+	}
+}
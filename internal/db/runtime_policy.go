@@ -13,6 +13,9 @@ const (
 	activityTimeout              = 10 * time.Second
 	objectMetadataTimeout        = 20 * time.Second
 	explainQueryTimeout          = 30 * time.Second
+	querySourcesTimeout          = 30 * time.Second
 	restoreRollbackTimeout       = 30 * time.Second
 	objectChangeTimeout          = 60 * time.Second
+	localAPIHeaderTimeout        = 10 * time.Second
+	localAPIShutdownTimeout      = 5 * time.Second
 )
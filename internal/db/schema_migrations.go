@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/response"
@@ -114,7 +115,7 @@ func retargetTableDefinition(
 	qualifiedTarget := qualifiedMigrationName(driver, targetSchema, table)
 
 	switch engine {
-	case "postgres":
+	case "postgres", database.DriverCockroachDB, database.DriverYugabyteDB:
 		sourcePrefix := driver.QuoteIdentifier(sourceSchema) + "."
 		targetPrefix := driver.QuoteIdentifier(targetSchema) + "."
 		if sourcePrefix != targetPrefix {
@@ -153,7 +154,7 @@ func retargetTableDefinition(
 				), true
 			}
 		}
-	case "sqlite":
+	case "sqlite", "duckdb":
 		needles := []string{
 			"CREATE TABLE " + quotedTable,
 			"CREATE TABLE IF NOT EXISTS " + quotedTable,
@@ -180,7 +181,7 @@ func retargetTableDefinition(
 
 func migrationColumnType(column database.Structure, engine string) string {
 	if column.TypeName != nil && strings.TrimSpace(*column.TypeName) != "" &&
-		engine == "postgres" {
+		postgresFamily(engine) {
 		name := strings.TrimSpace(*column.TypeName)
 		if column.TypeSchema != nil && strings.TrimSpace(*column.TypeSchema) != "" {
 			name = `"` + strings.ReplaceAll(
@@ -200,7 +201,7 @@ func migrationColumnType(column database.Structure, engine string) string {
 	}
 	if column.IsAutoInc {
 		switch engine {
-		case "postgres":
+		case "postgres", database.DriverCockroachDB, database.DriverYugabyteDB:
 			switch strings.ToLower(dataType) {
 			case "smallint", "int2":
 				return "smallserial"
@@ -223,7 +224,7 @@ func migrationColumnDefinition(
 	engine string,
 ) database.ColumnDefinition {
 	defaultValue := ""
-	if column.Default != nil && !(engine == "postgres" && column.IsAutoInc) {
+	if column.Default != nil && !(postgresFamily(engine) && column.IsAutoInc) {
 		defaultValue = *column.Default
 	}
 	return database.ColumnDefinition{
@@ -244,11 +245,24 @@ func canAutomateAddedColumn(
 		return true
 	}
 	return column.IsAutoInc &&
-		(engine == database.DriverPostgres ||
+		(postgresFamily(engine) ||
 			engine == database.DriverMySQL ||
 			engine == database.DriverMariaDB)
 }
 
+// postgresFamily reports engines served by the PostgreSQL driver, which
+// share its quoting, serial types, and DROP ... CASCADE.
+func postgresFamily(engine string) bool {
+	switch engine {
+	case database.DriverPostgres,
+		database.DriverCockroachDB,
+		database.DriverYugabyteDB:
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizedMigrationValue(value string) string {
 	return strings.Join(strings.Fields(strings.ToLower(strings.TrimSpace(value))), " ")
 }
@@ -912,7 +926,7 @@ func (s *Service) buildSchemaMigration(
 					Schema: request.TargetSchema,
 					Name:   name,
 				},
-				Cascade: target.Engine == "postgres",
+				Cascade: postgresFamily(target.Engine),
 			},
 		)
 		if planErr != nil {
@@ -1097,6 +1111,7 @@ func (s *Service) ApplySchemaMigration(
 	if !ok {
 		return unsupportedObjectChange[database.SchemaMigrationResult]()
 	}
+	startedAt := time.Now()
 	if err := changeDriver.ApplyObjectChange(ctx, built.plan); err != nil {
 		return serviceErrorWithCode[database.SchemaMigrationResult](
 			http.StatusBadRequest,
@@ -1106,6 +1121,14 @@ func (s *Service) ApplySchemaMigration(
 			"If the engine auto-commits DDL, compare schemas again before retrying.",
 		)
 	}
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationSchemaMigration,
+		StartedAt:    startedAt,
+		ConnectionID: request.Migration.TargetConnectionID,
+		Target:       request.Migration.TargetSchema,
+		Summary:      built.plan.Summary,
+		Statements:   auditStatements(built.plan.Statements),
+	})
 	return response.BaseResponse[database.SchemaMigrationResult]{
 		Data: database.SchemaMigrationResult{
 			Applied:        true,
@@ -3,6 +3,7 @@ package db
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/response"
@@ -184,6 +185,7 @@ func (s *Service) ApplySecurityChange(
 			"Review the refreshed SQL before applying the security change.",
 		)
 	}
+	startedAt := time.Now()
 	if err := securityDriver.ApplySecurityChange(ctx, plan); err != nil {
 		return serviceErrorWithCode[database.SecurityChangeResult](
 			http.StatusForbidden,
@@ -193,6 +195,15 @@ func (s *Service) ApplySecurityChange(
 			"Verify that the connected account may manage users and grants.",
 		)
 	}
+	// The preview statements already mask passwords; Redact runs on top.
+	s.recordAudit(database.AuditEntry{
+		Operation:    database.AuditOperationSecurityChange,
+		StartedAt:    startedAt,
+		ConnectionID: connectionID,
+		Target:       request.Change.Principal.Name,
+		Summary:      plan.Summary,
+		Statements:   auditStatements(plan.PreviewStatements),
+	})
 	return response.BaseResponse[database.SecurityChangeResult]{
 		Data: database.SecurityChangeResult{
 			Applied:        true,
@@ -2,7 +2,9 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -12,8 +14,6 @@ import (
 	"rollingthunder/internal/updater"
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/response"
-
-	"github.com/google/uuid"
 )
 
 // Connection represents an active database connection
@@ -79,6 +79,7 @@ type Service struct {
 	sqliteSaveDialog    saveFileDialogFunc
 	oracleTNSOpenDialog openFileDialogFunc
 	oracleWalletDialog  openFileDialogFunc
+	dataFileOpenDialog  openFileDialogFunc
 	importOpenDialog    openFileDialogFunc
 	restoreOpenDialog   openFileDialogFunc
 	sqlOpenDialog       openFileDialogFunc
@@ -96,6 +97,7 @@ type Service struct {
 	commandContext      commandFactory
 	newDriver           driverFactory
 	newTunnel           tunnelFactory
+	newConnectionID     connectionIDFactory
 	connectionTimeout   time.Duration
 	connectionAttempts  map[string]*connectionAttempt
 	connectionAttemptMu sync.Mutex
@@ -103,6 +105,12 @@ type Service struct {
 	queryAttemptMu      sync.RWMutex
 	transactions        map[string]*transactionSession
 	transactionMu       sync.RWMutex
+	queryCursors        map[string]*queryCursorSession
+	queryCursorMu       sync.Mutex
+	keysetPages         map[string]*database.KeysetPage
+	keysetPageMu        sync.Mutex
+	rowCountJobs        map[string]*rowCountJob
+	rowCountMu          sync.Mutex
 	connectionStorage   *ConnectionStorage
 	credentialStore     CredentialStore
 	healthInterval      time.Duration
@@ -111,6 +119,24 @@ type Service struct {
 	healthDone          chan struct{}
 	diagnostics         *diagnostics.Manager
 	updateChecker       *updater.Checker
+	localAPI            *localAPIServer
+	localAPIMu          sync.Mutex
+	backupCatalog       *BackupCatalogStorage
+	backupCatalogMu     sync.Mutex
+	backupRuns          map[string]scheduledBackupRun
+	backupScheduleMu    sync.Mutex
+	auditLog            *AuditLogStorage
+	auditMu             sync.Mutex
+	// auditWriteError is the last failure to append to the audit log.
+	auditWriteError string
+	// backupScheduleInterval is how often due schedules are checked; zero
+	// disables the scheduler, as in headless runs.
+	backupScheduleInterval time.Duration
+	backupSchedulerCancel  context.CancelFunc
+	backupSchedulerDone    chan struct{}
+	// queryCursorIdleTimeout closes result cursors left unread this long.
+	queryCursorIdleTimeout time.Duration
+	rowCountRetention      time.Duration
 }
 
 func NewService() *Service {
@@ -134,40 +160,51 @@ func NewServiceWithDiagnosticsAndVersion(
 		diagnosticManager = diagnostics.NewManager()
 	}
 	return &Service{
-		connections:         make(map[string]*Connection),
-		saveDialog:          defaultSaveFileDialog,
-		sqliteOpenDialog:    defaultOpenFileDialog,
-		sqliteSaveDialog:    defaultSaveFileDialog,
-		oracleTNSOpenDialog: defaultOpenFileDialog,
-		oracleWalletDialog:  defaultOpenDirectoryDialog,
-		importOpenDialog:    defaultOpenFileDialog,
-		restoreOpenDialog:   defaultOpenFileDialog,
-		sqlOpenDialog:       defaultOpenFileDialog,
-		importFiles:         make(map[string]importFileGrant),
-		restoreFiles:        make(map[string]restoreFileGrant),
-		sqlFiles:            make(map[string]sqlFileGrant),
-		exportJobs:          make(map[string]*exportJob),
-		maintenanceJobs:     make(map[string]*maintenanceJob),
-		lookPath:            defaultExecutableLookup,
-		commandContext:      defaultCommandFactory,
-		newDriver:           NewDriver,
-		newTunnel:           newSSHTunnel,
-		connectionTimeout:   defaultConnectionTimeout,
-		connectionAttempts:  make(map[string]*connectionAttempt),
-		queryAttempts:       make(map[string]*queryAttempt),
-		transactions:        make(map[string]*transactionSession),
-		connectionStorage:   NewConnectionStorage(),
-		credentialStore:     newOperatingSystemCredentialStore(),
-		healthInterval:      defaultHealthMonitorInterval,
-		healthTimeout:       defaultHealthCheckTimeout,
-		diagnostics:         diagnosticManager,
-		updateChecker:       updater.NewChecker(currentVersion),
+		connections:            make(map[string]*Connection),
+		saveDialog:             defaultSaveFileDialog,
+		sqliteOpenDialog:       defaultOpenFileDialog,
+		sqliteSaveDialog:       defaultSaveFileDialog,
+		oracleTNSOpenDialog:    defaultOpenFileDialog,
+		oracleWalletDialog:     defaultOpenDirectoryDialog,
+		dataFileOpenDialog:     defaultOpenFileDialog,
+		importOpenDialog:       defaultOpenFileDialog,
+		restoreOpenDialog:      defaultOpenFileDialog,
+		sqlOpenDialog:          defaultOpenFileDialog,
+		importFiles:            make(map[string]importFileGrant),
+		restoreFiles:           make(map[string]restoreFileGrant),
+		sqlFiles:               make(map[string]sqlFileGrant),
+		exportJobs:             make(map[string]*exportJob),
+		maintenanceJobs:        make(map[string]*maintenanceJob),
+		lookPath:               defaultExecutableLookup,
+		commandContext:         defaultCommandFactory,
+		newDriver:              NewDriver,
+		newTunnel:              newSSHTunnel,
+		newConnectionID:        randomConnectionID,
+		connectionTimeout:      defaultConnectionTimeout,
+		connectionAttempts:     make(map[string]*connectionAttempt),
+		queryAttempts:          make(map[string]*queryAttempt),
+		transactions:           make(map[string]*transactionSession),
+		queryCursors:           make(map[string]*queryCursorSession),
+		rowCountJobs:           make(map[string]*rowCountJob),
+		connectionStorage:      NewConnectionStorage(),
+		credentialStore:        newOperatingSystemCredentialStore(),
+		healthInterval:         defaultHealthMonitorInterval,
+		healthTimeout:          defaultHealthCheckTimeout,
+		diagnostics:            diagnosticManager,
+		updateChecker:          updater.NewChecker(currentVersion),
+		backupCatalog:          NewBackupCatalogStorage(),
+		backupRuns:             make(map[string]scheduledBackupRun),
+		auditLog:               NewAuditLogStorage(),
+		backupScheduleInterval: defaultBackupScheduleInterval,
+		queryCursorIdleTimeout: defaultQueryCursorIdleTimeout,
+		rowCountRetention:      defaultRowCountRetention,
 	}
 }
 
 func (s *Service) Start(ctx context.Context) {
 	s.ctx = ctx
 	s.startHealthMonitor(ctx)
+	s.startBackupScheduler(ctx)
 }
 
 func serviceError[T any](detail string) response.BaseResponse[T] {
@@ -293,7 +330,7 @@ func (s *Service) Connect(req ConnectRequest) response.BaseResponse[ConnectRespo
 	}
 
 	// Generate connection ID and store in registry
-	connID := uuid.New().String()
+	connID := s.newConnectionID(req)
 	connectedAt := time.Now()
 	healthTimestamp := connectedAt.UTC().Format(time.RFC3339Nano)
 	conn := &Connection{
@@ -443,7 +480,17 @@ func (s *Service) GetCollectionData(connectionID string, table database.Table) r
 	}
 	defer release()
 
-	structures, results, err := driver.GetCollectionData(table)
+	keyset, request, err := s.keysetTable(connectionID, driver, table)
+	if err != nil {
+		return serviceErrorWithCode[database.TableData](
+			http.StatusBadRequest,
+			errorCodeInvalidRequest,
+			"Invalid page token",
+			err.Error(),
+			"Reload the table from the first page.",
+		)
+	}
+	structures, results, err := driver.GetCollectionData(request)
 	if err != nil {
 		return serviceError[database.TableData](err.Error())
 	}
@@ -461,10 +508,96 @@ func (s *Service) GetCollectionData(connectionID string, table database.Table) r
 	if len(results) > 0 {
 		resp.Data.Data = results
 	}
+	if keyset != nil {
+		resp.Data.NextPage = keyset.NextToken(results, request.Limit)
+	}
 
 	return resp
 }
 
+// keysetTable returns the request to send to the driver. On engines with
+// unique primary keys it orders the page by the full key so the last row
+// can be turned into a continuation token, and a token from the previous
+// page replaces Offset with a seek. A token issued for a different filter
+// or sort is ignored and Offset applies as before. The first page reads
+// the table structure for the key columns; continuation pages reuse the
+// order it produced.
+func (s *Service) keysetTable(
+	connectionID string,
+	driver database.Driver,
+	table database.Table,
+) (*database.KeysetPage, database.Table, error) {
+	after := table.After
+	table.After = ""
+	capabilities := driver.Capabilities()
+	if table.Limit <= 0 || !capabilities.KeysetPagination || !capabilities.FilterGroups {
+		return nil, table, nil
+	}
+	view, err := database.KeysetViewKey(table)
+	if err != nil {
+		return nil, table, nil
+	}
+	cacheKey := connectionID + "\x00" + view
+	var keyset *database.KeysetPage
+	if after != "" {
+		s.keysetPageMu.Lock()
+		keyset = s.keysetPages[cacheKey]
+		s.keysetPageMu.Unlock()
+	}
+	if keyset == nil {
+		structures, err := driver.GetCollectionStructures(table)
+		if err != nil {
+			return nil, table, nil
+		}
+		var ok bool
+		keyset, ok = database.NewKeysetPage(table, structures)
+		if !ok {
+			return nil, table, nil
+		}
+		s.rememberKeysetPage(cacheKey, keyset)
+	}
+	request, err := keyset.Table(after)
+	if errors.Is(err, database.ErrKeysetTokenMismatch) {
+		request, err = keyset.Table("")
+		after = ""
+	}
+	if err != nil {
+		return nil, table, err
+	}
+	if after == "" {
+		// Without a usable token the page is found by offset, as for page
+		// jumps; the keyset order still yields a token for the next page.
+		request.Offset = table.Offset
+	}
+	return keyset, request, nil
+}
+
+// maxKeysetPages bounds the remembered keyset orders; they are cheap to
+// rebuild, so a full cache simply starts over.
+const maxKeysetPages = 64
+
+func (s *Service) rememberKeysetPage(key string, keyset *database.KeysetPage) {
+	s.keysetPageMu.Lock()
+	defer s.keysetPageMu.Unlock()
+	if s.keysetPages == nil || len(s.keysetPages) >= maxKeysetPages {
+		s.keysetPages = make(map[string]*database.KeysetPage)
+	}
+	s.keysetPages[key] = keyset
+}
+
+// forgetKeysetPages drops the keyset orders of a connection whose tables
+// may have changed or gone away.
+func (s *Service) forgetKeysetPages(connectionID string) {
+	prefix := connectionID + "\x00"
+	s.keysetPageMu.Lock()
+	defer s.keysetPageMu.Unlock()
+	for key := range s.keysetPages {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.keysetPages, key)
+		}
+	}
+}
+
 // InsertRow inserts a new row into the table
 func (s *Service) InsertRow(connectionID string, table database.Table, data map[string]interface{}) response.BaseResponse[bool] {
 	driver, release, err := s.writeDriverFor(connectionID)
@@ -738,10 +871,13 @@ func (s *Service) DisconnectConnection(connectionID string) response.BaseRespons
 	}
 	s.mu.Unlock()
 
+	s.cancelRowCountsForConnection(connectionID)
 	// Wait for in-flight work on this connection before closing its driver.
 	conn.mu.Lock()
 	conn.closed = true
 	s.rollbackTransactionsForConnection(connectionID)
+	s.closeQueryCursorsForConnection(connectionID)
+	s.forgetKeysetPages(connectionID)
 	err := conn.Driver.Close()
 	if conn.Tunnel != nil {
 		if tunnelErr := conn.Tunnel.Close(); err == nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -162,10 +163,34 @@ func (d *routingTestDriver) ExecuteQuery(
 	if rows == nil {
 		rows = []map[string]interface{}{{"source": d.name}}
 	}
-	return database.QueryResult{
-		Rows:     rows,
-		RowLimit: options.MaxRows,
-	}, nil
+	result := queryResultFromMaps(rows)
+	result.RowLimit = options.MaxRows
+	return result, nil
+}
+
+// queryResultFromMaps builds an ordered result from keyed test rows, taking
+// the columns from the first row in name order.
+func queryResultFromMaps(rows []map[string]interface{}) database.QueryResult {
+	result := database.QueryResult{Rows: make([][]interface{}, 0, len(rows))}
+	if len(rows) == 0 {
+		return result
+	}
+	names := make([]string, 0, len(rows[0]))
+	for name := range rows[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result.Columns = append(result.Columns, database.QueryColumn{Name: name})
+	}
+	for _, row := range rows {
+		values := make([]interface{}, len(names))
+		for index, name := range names {
+			values[index] = row[name]
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result
 }
 
 func (d *routingTestDriver) BeginTransaction(
@@ -352,7 +377,7 @@ func TestExecuteQueryUsesOwningConnection(t *testing.T) {
 	if len(response.Errors) != 0 {
 		t.Fatalf("ExecuteQuery returned errors: %+v", response.Errors)
 	}
-	if len(response.Data.Rows) != 1 || response.Data.Rows[0]["source"] != "alpha" {
+	if len(response.Data.Rows) != 1 || response.Data.RowMaps()[0]["source"] != "alpha" {
 		t.Fatalf("ExecuteQuery returned data from the wrong connection: %+v", response.Data)
 	}
 	if response.Data.RowLimit != database.DefaultQueryResultLimit {
@@ -380,7 +405,7 @@ func TestExecuteQueryUsesOwningConnection(t *testing.T) {
 	if len(response.Errors) != 0 {
 		t.Fatalf("ExecuteQuery returned errors after switching: %+v", response.Errors)
 	}
-	if len(response.Data.Rows) != 1 || response.Data.Rows[0]["source"] != "bravo" {
+	if len(response.Data.Rows) != 1 || response.Data.RowMaps()[0]["source"] != "bravo" {
 		t.Fatalf("ExecuteQuery followed the global active connection: %+v", response.Data)
 	}
 }
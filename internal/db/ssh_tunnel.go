@@ -304,8 +304,12 @@ func validateSSHConfig(config database.Config) error {
 	if !config.SSHEnabled {
 		return fmt.Errorf("SSH tunnel is not enabled")
 	}
-	if config.Driver == database.DriverSQLite {
-		return fmt.Errorf("SQLite connections cannot use an SSH tunnel")
+	if probe, err := database.ProbeDriver(config.Driver, nil); err == nil &&
+		probe.Capabilities.FileDatabase {
+		return fmt.Errorf(
+			"%s connections open a local file and cannot use an SSH tunnel",
+			probe.Capabilities.DisplayName,
+		)
 	}
 	if strings.TrimSpace(config.SSHHost) == "" {
 		return fmt.Errorf("SSH host is required")
@@ -103,6 +103,14 @@ func TestValidateSSHConfigRejectsUnsafeOrIncompleteProfiles(t *testing.T) {
 			},
 			match: "SQLite",
 		},
+		{
+			name: "duckdb",
+			config: database.Config{
+				Driver:     "duckdb",
+				SSHEnabled: true,
+			},
+			match: "DuckDB",
+		},
 		{
 			name: "missing ssh host",
 			config: database.Config{
@@ -127,6 +135,9 @@ func TestValidateSSHConfigRejectsUnsafeOrIncompleteProfiles(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			if _, err := database.ProbeDriver(test.config.Driver, nil); test.name == "duckdb" && err != nil {
+				t.Skip("DuckDB is registered only in cgo builds")
+			}
 			err := validateSSHConfig(test.config)
 			if err == nil || !strings.Contains(err.Error(), test.match) {
 				t.Fatalf("validateSSHConfig() error = %v", err)
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"rollingthunder/pkg/database"
+)
+
+// structureCountingDriver counts the table structure reads keyset paging
+// makes.
+type structureCountingDriver struct {
+	database.Driver
+	reads atomic.Int64
+}
+
+func (d *structureCountingDriver) GetCollectionStructures(
+	table database.Table,
+) (database.Structures, error) {
+	d.reads.Add(1)
+	return d.Driver.GetCollectionStructures(table)
+}
+
+func TestGetCollectionDataContinuesWithPageTokens(t *testing.T) {
+	driver := sqliteMigrationDriver(t, filepath.Join(t.TempDir(), "pages.sqlite"))
+	if _, err := driver.ExecuteQuery(
+		context.Background(),
+		`CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT NOT NULL);
+		INSERT INTO events (id, kind) VALUES
+			(1, 'b'), (2, 'a'), (3, 'b'), (4, 'a'), (5, 'c');`,
+		database.QueryOptions{MaxRows: 10},
+	); err != nil {
+		t.Fatalf("create fixture: %v", err)
+	}
+	counting := &structureCountingDriver{Driver: driver}
+	service := schemaMigrationService(counting, driver)
+	table := database.Table{
+		Schema: "main",
+		Name:   "events",
+		Limit:  2,
+		Sorts:  []database.Sort{{Column: "kind", Direction: database.SortDescending}},
+	}
+
+	var ids []string
+	pages := 0
+	for {
+		result := service.GetCollectionData("source", table)
+		if len(result.Errors) != 0 {
+			t.Fatalf("GetCollectionData errors = %+v", result.Errors)
+		}
+		for _, row := range result.Data.Data {
+			ids = append(ids, fmt.Sprint(row["id"]))
+		}
+		pages++
+		if result.Data.NextPage == "" {
+			break
+		}
+		table.After = result.Data.NextPage
+		// The token, not the offset, decides where the next page starts.
+		table.Offset = 99
+	}
+	if pages != 3 || fmt.Sprint(ids) != "[5 1 3 2 4]" {
+		t.Fatalf("pages = %d, ids = %v", pages, ids)
+	}
+	if reads := counting.reads.Load(); reads != 1 {
+		t.Fatalf("structure reads = %d, want 1 for the first page only", reads)
+	}
+
+	// A page jump sends an offset without a token.
+	jump := database.Table{Schema: "main", Name: "events", Limit: 2, Offset: 4}
+	result := service.GetCollectionData("source", jump)
+	if len(result.Errors) != 0 || len(result.Data.Data) != 1 ||
+		fmt.Sprint(result.Data.Data[0]["id"]) != "5" {
+		t.Fatalf("offset-only page result = %+v", result)
+	}
+
+	// A token issued for another sort falls back to the offset.
+	table.Sorts = nil
+	table.Offset = 2
+	result = service.GetCollectionData("source", table)
+	if len(result.Errors) != 0 || len(result.Data.Data) != 2 ||
+		fmt.Sprint(result.Data.Data[0]["id"]) != "3" {
+		t.Fatalf("stale token result = %+v", result)
+	}
+
+	table.After = "not a token"
+	result = service.GetCollectionData("source", table)
+	if len(result.Errors) == 0 || result.Errors[0].Code != errorCodeInvalidRequest {
+		t.Fatalf("malformed token result = %+v", result.Errors)
+	}
+}
@@ -0,0 +1,267 @@
+// Package mcp serves Rolling Thunder's guarded database tools to AI assistants
+// over the Model Context Protocol stdio transport. Tools run through
+// internal/db.Service, so assistants never see profile credentials and every
+// statement passes the same access-mode and result-limit rules as the editor.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"rollingthunder/internal/db"
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+const (
+	latestProtocolVersion = "2025-06-18"
+	maxMessageBytes       = 8 << 20
+
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+)
+
+var supportedProtocolVersions = map[string]bool{
+	"2024-11-05":          true,
+	"2025-03-26":          true,
+	latestProtocolVersion: true,
+}
+
+var errProfileNotAllowed = errors.New("profile is not exposed to this MCP server")
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// Server answers MCP requests for an allowlist of saved profiles. Profiles
+// are connected lazily on first use and reused for the rest of the session.
+type Server struct {
+	headless *db.Headless
+	allowed  []string
+	tools    []tool
+}
+
+func NewServer(headless *db.Headless, profiles []string) *Server {
+	server := &Server{headless: headless}
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			server.allowed = append(server.allowed, profile)
+		}
+	}
+	server.tools = server.registerTools()
+	return server
+}
+
+// Serve reads newline-delimited JSON-RPC messages until input closes or ctx
+// is cancelled. Requests are answered in order on output.
+func (server *Server) Serve(ctx context.Context, input io.Reader, output io.Writer) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	encoder := json.NewEncoder(output)
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					return err
+				default:
+					return nil
+				}
+			}
+			if len(strings.TrimSpace(string(line))) == 0 {
+				continue
+			}
+			reply := server.handle(line)
+			if reply == nil {
+				continue
+			}
+			if err := encoder.Encode(reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (server *Server) handle(line []byte) *rpcResponse {
+	var request rpcRequest
+	if err := json.Unmarshal(line, &request); err != nil {
+		return errorReply(json.RawMessage("null"), jsonRPCParseError, "parse error: "+err.Error())
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return errorReply(idOrNull(request.ID), jsonRPCInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+	// Notifications such as notifications/initialized never receive a reply.
+	if len(request.ID) == 0 {
+		return nil
+	}
+
+	switch request.Method {
+	case "initialize":
+		return resultReply(request.ID, server.initialize(request.Params))
+	case "ping":
+		return resultReply(request.ID, struct{}{})
+	case "tools/list":
+		return resultReply(request.ID, map[string]interface{}{"tools": server.tools})
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return errorReply(request.ID, jsonRPCInvalidParams, err.Error())
+		}
+		for _, tool := range server.tools {
+			if tool.Name == params.Name {
+				return resultReply(request.ID, tool.call(params.Arguments))
+			}
+		}
+		return errorReply(request.ID, jsonRPCInvalidParams, fmt.Sprintf("unknown tool %q", params.Name))
+	default:
+		return errorReply(request.ID, jsonRPCMethodNotFound, "method not found: "+request.Method)
+	}
+}
+
+func (server *Server) initialize(params json.RawMessage) map[string]interface{} {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(params, &request)
+	version := latestProtocolVersion
+	if supportedProtocolVersions[request.ProtocolVersion] {
+		version = request.ProtocolVersion
+	}
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]string{
+			"name":    application.Identifier,
+			"title":   application.Name,
+			"version": server.headless.Version(),
+		},
+		"instructions": "Inspect schemas and run SQL through saved Rolling Thunder profiles. " +
+			"Read-only profiles reject write statements, and query results are capped at " +
+			fmt.Sprintf("%d rows per result set.", database.DefaultQueryResultLimit),
+	}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func resultReply(id json.RawMessage, result interface{}) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func errorReply(id json.RawMessage, code int, message string) *rpcResponse {
+	return &rpcResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &rpcError{Code: code, Message: message},
+	}
+}
+
+// connect resolves an allowlisted profile to a live connection ID.
+func (server *Server) connect(profile string) (string, *toolResult) {
+	profile = strings.TrimSpace(profile)
+	allowed := false
+	for _, name := range server.allowed {
+		if strings.EqualFold(name, profile) {
+			profile = name
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", textError(fmt.Sprintf(
+			"%v: %q. Call list_profiles for the available profiles.",
+			errProfileNotAllowed,
+			profile,
+		))
+	}
+	connected := server.headless.ConnectProfile(profile)
+	if len(connected.Errors) > 0 || !connected.Data.Connected {
+		return "", envelopeResult(connected)
+	}
+	return connected.Data.ConnectionID, nil
+}
+
+func textError(message string) *toolResult {
+	return &toolResult{
+		Content: []toolContent{{Type: "text", Text: message}},
+		IsError: true,
+	}
+}
+
+// envelopeResult converts a Service response to tool output. Errors keep
+// their stable codes and hints so assistants can explain what was blocked.
+func envelopeResult[T any](result response.BaseResponse[T]) *toolResult {
+	var payload interface{} = result.Data
+	if len(result.Errors) > 0 {
+		payload = map[string]interface{}{"errors": result.Errors}
+	}
+	encoded, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return textError(err.Error())
+	}
+	return &toolResult{
+		Content: []toolContent{{Type: "text", Text: string(encoded)}},
+		IsError: len(result.Errors) > 0,
+	}
+}
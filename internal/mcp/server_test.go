@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"rollingthunder/internal/db"
+	"rollingthunder/pkg/database"
+)
+
+type memoryCredentialStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (store *memoryCredentialStore) Set(profileID string, password string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.values[profileID] = password
+	return nil
+}
+
+func (store *memoryCredentialStore) Get(profileID string) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	password, ok := store.values[profileID]
+	if !ok {
+		return "", db.ErrCredentialNotFound
+	}
+	return password, nil
+}
+
+func (store *memoryCredentialStore) Delete(profileID string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.values, profileID)
+	return nil
+}
+
+func newTestHeadless(t *testing.T) *db.Headless {
+	t.Helper()
+	dir := t.TempDir()
+	headless := db.NewHeadless(db.HeadlessOptions{
+		ConnectionStorage: &db.ConnectionStorage{
+			FilePath: filepath.Join(dir, "connections.json"),
+		},
+		CredentialStore: &memoryCredentialStore{values: make(map[string]string)},
+		Version:         "test",
+	})
+	headless.Start(context.Background())
+	t.Cleanup(func() { headless.Shutdown(context.Background()) })
+
+	path := filepath.Join(dir, "shared.sqlite3")
+	for _, profile := range []struct{ name, access string }{
+		{"writer", database.ConnectionAccessReadWrite},
+		{"reader", database.ConnectionAccessReadOnly},
+		{"hidden", database.ConnectionAccessReadWrite},
+	} {
+		saved := headless.Service().SaveConnection(database.Config{
+			Name:       profile.name,
+			Driver:     database.DriverSQLite,
+			Db:         path,
+			AccessMode: profile.access,
+		})
+		if len(saved.Errors) > 0 {
+			t.Fatalf("SaveConnection(%s) = %+v", profile.name, saved)
+		}
+	}
+	return headless
+}
+
+type exchange struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func serve(t *testing.T, server *Server, messages ...string) []exchange {
+	t.Helper()
+	var output strings.Builder
+	input := strings.NewReader(strings.Join(messages, "\n") + "\n")
+	if err := server.Serve(context.Background(), input, &output); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	replies := make([]exchange, 0, len(messages))
+	scanner := bufio.NewScanner(strings.NewReader(output.String()))
+	for scanner.Scan() {
+		var reply exchange
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			t.Fatalf("decode reply %s: %v", scanner.Text(), err)
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+func callTool(id int, name string, arguments map[string]interface{}) string {
+	encoded, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": arguments},
+	})
+	return string(encoded)
+}
+
+func decodeToolResult(t *testing.T, reply exchange) toolResult {
+	t.Helper()
+	if reply.Error != nil {
+		t.Fatalf("reply %d error = %+v", reply.ID, reply.Error)
+	}
+	var result toolResult
+	if err := json.Unmarshal(reply.Result, &result); err != nil {
+		t.Fatalf("decode tool result: %v", err)
+	}
+	return result
+}
+
+func TestServerHandshakeAndToolList(t *testing.T) {
+	server := NewServer(newTestHeadless(t), []string{"writer"})
+	replies := serve(
+		t,
+		server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+	)
+	if len(replies) != 3 {
+		t.Fatalf("replies = %+v, notifications must not be answered", replies)
+	}
+	var initialized struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(replies[0].Result, &initialized); err != nil ||
+		initialized.ProtocolVersion != "2025-03-26" {
+		t.Fatalf("initialize = %s", replies[0].Result)
+	}
+	var listed struct {
+		Tools []tool `json:"tools"`
+	}
+	if err := json.Unmarshal(replies[1].Result, &listed); err != nil || len(listed.Tools) != 6 {
+		t.Fatalf("tools/list = %s", replies[1].Result)
+	}
+	if replies[2].Error == nil || replies[2].Error.Code != jsonRPCMethodNotFound {
+		t.Fatalf("unknown method reply = %+v", replies[2])
+	}
+}
+
+func TestRunQueryRespectsAccessModeAndRowCap(t *testing.T) {
+	server := NewServer(newTestHeadless(t), []string{"writer", "reader"})
+	replies := serve(
+		t,
+		server,
+		callTool(1, "run_query", map[string]interface{}{
+			"profile": "writer",
+			"sql": "CREATE TABLE points (id INTEGER PRIMARY KEY); " +
+				"INSERT INTO points VALUES (1), (2), (3)",
+		}),
+		callTool(2, "run_query", map[string]interface{}{
+			"profile": "reader",
+			"sql":     "DELETE FROM points WHERE id = 1",
+		}),
+		callTool(3, "run_query", map[string]interface{}{
+			"profile": "reader",
+			"sql":     "SELECT id FROM points ORDER BY id",
+			"maxRows": 2,
+		}),
+		callTool(4, "run_query", map[string]interface{}{
+			"profile": "hidden",
+			"sql":     "SELECT 1",
+		}),
+		callTool(5, "describe_table", map[string]interface{}{
+			"profile": "reader",
+			"schema":  "main",
+			"table":   "points",
+		}),
+	)
+	if len(replies) != 5 {
+		t.Fatalf("replies = %+v", replies)
+	}
+	if created := decodeToolResult(t, replies[0]); created.IsError {
+		t.Fatalf("writer query = %+v", created)
+	}
+	blocked := decodeToolResult(t, replies[1])
+	if !blocked.IsError || !strings.Contains(blocked.Content[0].Text, "read-only") {
+		t.Fatalf("read-only write = %+v", blocked)
+	}
+
+	selected := decodeToolResult(t, replies[2])
+	var rows database.QueryResult
+	if selected.IsError || json.Unmarshal([]byte(selected.Content[0].Text), &rows) != nil {
+		t.Fatalf("read-only select = %+v", selected)
+	}
+	if len(rows.Rows) != 2 || !rows.Truncated || rows.RowLimit != 2 {
+		t.Fatalf("capped rows = %+v", rows)
+	}
+
+	if hidden := decodeToolResult(t, replies[3]); !hidden.IsError {
+		t.Fatalf("profiles outside the allowlist must be rejected: %+v", hidden)
+	}
+	if described := decodeToolResult(t, replies[4]); described.IsError {
+		t.Fatalf("describe_table = %+v", described)
+	}
+}
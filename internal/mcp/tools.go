@@ -0,0 +1,357 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/response"
+)
+
+type tool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations map[string]bool        `json:"annotations,omitempty"`
+	call        func(json.RawMessage) *toolResult
+}
+
+type profileSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Driver      string `json:"driver"`
+	Environment string `json:"environment"`
+	AccessMode  string `json:"accessMode"`
+}
+
+type objectListArguments struct {
+	Profile string                `json:"profile"`
+	Schema  string                `json:"schema"`
+	Kinds   []database.ObjectKind `json:"kinds"`
+	Search  string                `json:"search"`
+}
+
+type objectDetailArguments struct {
+	Profile string `json:"profile"`
+	database.ObjectReference
+}
+
+type tableArguments struct {
+	Profile string `json:"profile"`
+	Schema  string `json:"schema"`
+	Table   string `json:"table"`
+}
+
+type queryArguments struct {
+	Profile string `json:"profile"`
+	SQL     string `json:"sql"`
+	MaxRows int    `json:"maxRows"`
+}
+
+var readOnlyAnnotations = map[string]bool{
+	"readOnlyHint":  true,
+	"openWorldHint": false,
+}
+
+func objectSchema(required []string, properties map[string]interface{}) map[string]interface{} {
+	properties["profile"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Saved profile name or ID from list_profiles.",
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             append([]string{"profile"}, required...),
+		"additionalProperties": false,
+	}
+}
+
+func stringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func (server *Server) registerTools() []tool {
+	objectKinds := []string{
+		string(database.ObjectKindTable),
+		string(database.ObjectKindView),
+		string(database.ObjectKindMaterializedView),
+		string(database.ObjectKindFunction),
+		string(database.ObjectKindProcedure),
+		string(database.ObjectKindTrigger),
+		string(database.ObjectKindSequence),
+		string(database.ObjectKindType),
+		string(database.ObjectKindEnum),
+		string(database.ObjectKindDomain),
+		string(database.ObjectKindConstraint),
+		string(database.ObjectKindExtension),
+		string(database.ObjectKindIndex),
+	}
+	return []tool{
+		{
+			Name:        "list_profiles",
+			Title:       "List profiles",
+			Description: "List the saved connection profiles this server may use, with engine and access mode.",
+			InputSchema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{},
+				"additionalProperties": false,
+			},
+			Annotations: readOnlyAnnotations,
+			call:        server.listProfiles,
+		},
+		{
+			Name:        "list_objects",
+			Title:       "List database objects",
+			Description: "List tables, views, routines, and other objects, optionally filtered by schema, kind, or name.",
+			InputSchema: objectSchema(nil, map[string]interface{}{
+				"schema": stringProperty("Schema or database to list."),
+				"kinds": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "string", "enum": objectKinds},
+				},
+				"search": stringProperty("Case-insensitive name filter."),
+			}),
+			Annotations: readOnlyAnnotations,
+			call:        server.listObjects,
+		},
+		{
+			Name:        "get_object",
+			Title:       "Get object detail",
+			Description: "Return the definition, properties, and dependencies of one database object.",
+			InputSchema: objectSchema([]string{"kind", "name"}, map[string]interface{}{
+				"kind":         map[string]interface{}{"type": "string", "enum": objectKinds},
+				"schema":       stringProperty("Object schema."),
+				"name":         stringProperty("Object name."),
+				"signature":    stringProperty("Routine argument signature for overloaded functions."),
+				"parentSchema": stringProperty("Owning table schema for triggers, indexes, and constraints."),
+				"parentName":   stringProperty("Owning table name for triggers, indexes, and constraints."),
+			}),
+			Annotations: readOnlyAnnotations,
+			call:        server.getObject,
+		},
+		{
+			Name:        "describe_table",
+			Title:       "Describe table",
+			Description: "Return the columns, types, nullability, defaults, and keys of a table or view.",
+			InputSchema: objectSchema([]string{"table"}, map[string]interface{}{
+				"schema": stringProperty("Table schema."),
+				"table":  stringProperty("Table or view name."),
+			}),
+			Annotations: readOnlyAnnotations,
+			call:        server.describeTable,
+		},
+		{
+			Name:        "explain_query",
+			Title:       "Explain query",
+			Description: "Return the engine's estimated plan for one SQL statement without executing it.",
+			InputSchema: objectSchema([]string{"sql"}, map[string]interface{}{
+				"sql": stringProperty("A single SQL statement."),
+			}),
+			Annotations: readOnlyAnnotations,
+			call:        server.explainQuery,
+		},
+		{
+			Name:  "run_query",
+			Title: "Run SQL",
+			Description: fmt.Sprintf(
+				"Execute SQL and return up to %d rows per result set. Read-only profiles reject write statements.",
+				database.DefaultQueryResultLimit,
+			),
+			InputSchema: objectSchema([]string{"sql"}, map[string]interface{}{
+				"sql": stringProperty("SQL to execute. Up to " +
+					fmt.Sprint(database.MaxQueryStatements) + " statements separated by semicolons."),
+				"maxRows": map[string]interface{}{
+					"type":    "integer",
+					"minimum": 1,
+					"maximum": database.DefaultQueryResultLimit,
+				},
+			}),
+			Annotations: map[string]bool{
+				"readOnlyHint":  false,
+				"openWorldHint": false,
+			},
+			call: server.runQuery,
+		},
+	}
+}
+
+func decodeArguments(raw json.RawMessage, target interface{}) *toolResult {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return textError("invalid tool arguments: " + err.Error())
+	}
+	return nil
+}
+
+func (server *Server) listProfiles(raw json.RawMessage) *toolResult {
+	if failed := decodeArguments(raw, &struct{}{}); failed != nil {
+		return failed
+	}
+	saved := server.headless.SavedConnections()
+	if len(saved.Errors) > 0 {
+		return envelopeResult(saved)
+	}
+	profiles := make([]profileSummary, 0, len(server.allowed))
+	for _, connection := range saved.Data {
+		for _, allowed := range server.allowed {
+			if connection.ID != allowed &&
+				!strings.EqualFold(strings.TrimSpace(connection.Config.Name), allowed) {
+				continue
+			}
+			profiles = append(profiles, profileSummary{
+				ID:          connection.ID,
+				Name:        connection.Config.Name,
+				Driver:      connection.Config.Driver,
+				Environment: connection.Config.Environment,
+				AccessMode: database.NormalizeConnectionAccessMode(
+					connection.Config.AccessMode,
+					connection.Config.Environment,
+				),
+			})
+			break
+		}
+	}
+	return envelopeResult(response.BaseResponse[[]profileSummary]{Data: profiles})
+}
+
+func (server *Server) listObjects(raw json.RawMessage) *toolResult {
+	var arguments objectListArguments
+	if failed := decodeArguments(raw, &arguments); failed != nil {
+		return failed
+	}
+	connectionID, failed := server.connect(arguments.Profile)
+	if failed != nil {
+		return failed
+	}
+	result := server.headless.Service().GetDatabaseObjects(
+		connectionID,
+		database.ObjectFilter{
+			Schema: arguments.Schema,
+			Kinds:  arguments.Kinds,
+			Search: arguments.Search,
+		},
+	)
+	if len(result.Data) > database.DefaultQueryResultLimit {
+		result.Data = result.Data[:database.DefaultQueryResultLimit]
+	}
+	return envelopeResult(result)
+}
+
+func (server *Server) getObject(raw json.RawMessage) *toolResult {
+	var arguments objectDetailArguments
+	if failed := decodeArguments(raw, &arguments); failed != nil {
+		return failed
+	}
+	connectionID, failed := server.connect(arguments.Profile)
+	if failed != nil {
+		return failed
+	}
+	return envelopeResult(server.headless.Service().GetDatabaseObject(
+		connectionID,
+		arguments.ObjectReference,
+	))
+}
+
+func (server *Server) describeTable(raw json.RawMessage) *toolResult {
+	var arguments tableArguments
+	if failed := decodeArguments(raw, &arguments); failed != nil {
+		return failed
+	}
+	connectionID, failed := server.connect(arguments.Profile)
+	if failed != nil {
+		return failed
+	}
+	return envelopeResult(server.headless.Service().GetCollectionStructures(
+		connectionID,
+		database.Table{Schema: arguments.Schema, Name: arguments.Table},
+	))
+}
+
+// guardStatement rejects write statements on read-only profiles before the
+// request reaches the Service. The Service enforces the same rule; checking
+// here keeps the refusal explicit even for explain, which never writes.
+func (server *Server) guardStatement(connectionID string, sql string) *toolResult {
+	access := server.headless.Service().GetConnectionWriteAccess(connectionID)
+	if len(access.Errors) > 0 {
+		return envelopeResult(access)
+	}
+	if access.Data.AccessMode != database.ConnectionAccessReadOnly {
+		return nil
+	}
+	if keyword := database.FindWriteStatement(sql); keyword != "" {
+		return textError(fmt.Sprintf(
+			"%s is not allowed: this profile is read-only. Only read statements can run through this server.",
+			strings.ToUpper(keyword),
+		))
+	}
+	return nil
+}
+
+func (server *Server) explainQuery(raw json.RawMessage) *toolResult {
+	var arguments queryArguments
+	if failed := decodeArguments(raw, &arguments); failed != nil {
+		return failed
+	}
+	connectionID, failed := server.connect(arguments.Profile)
+	if failed != nil {
+		return failed
+	}
+	if failed := server.guardStatement(connectionID, arguments.SQL); failed != nil {
+		return failed
+	}
+	return envelopeResult(server.headless.Service().ExplainQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        arguments.SQL,
+	}))
+}
+
+func (server *Server) runQuery(raw json.RawMessage) *toolResult {
+	var arguments queryArguments
+	if failed := decodeArguments(raw, &arguments); failed != nil {
+		return failed
+	}
+	connectionID, failed := server.connect(arguments.Profile)
+	if failed != nil {
+		return failed
+	}
+	if failed := server.guardStatement(connectionID, arguments.SQL); failed != nil {
+		return failed
+	}
+	result := server.headless.Service().ExecuteQuery(database.QueryRequest{
+		ConnectionID: connectionID,
+		Query:        arguments.SQL,
+	})
+	if len(result.Errors) == 0 {
+		result.Data = capQueryResult(result.Data, arguments.MaxRows)
+	}
+	return envelopeResult(result)
+}
+
+// capQueryResult trims every result set to limit rows. The Service already
+// stops reading at DefaultQueryResultLimit; assistants may ask for fewer.
+func capQueryResult(result database.QueryResult, limit int) database.QueryResult {
+	if limit <= 0 || limit > database.DefaultQueryResultLimit {
+		limit = database.DefaultQueryResultLimit
+	}
+	for index := range result.ResultSets {
+		set := &result.ResultSets[index]
+		if len(set.Rows) > limit {
+			set.Rows = set.Rows[:limit]
+			set.Truncated = true
+		}
+		set.RowLimit = limit
+	}
+	if len(result.Rows) > limit {
+		result.Rows = result.Rows[:limit]
+		result.Truncated = true
+	}
+	result.RowLimit = limit
+	return result
+}
@@ -5,9 +5,12 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
 
+	"rollingthunder/internal/cli"
 	"rollingthunder/internal/db"
 	"rollingthunder/internal/diagnostics"
 	"rollingthunder/pkg/application"
@@ -48,6 +51,18 @@ func main() {
 	if versionErr != nil {
 		appVersion = "0.0.1"
 	}
+	db.LoadDriverPlugins()
+	if cli.IsCommand(os.Args[1:]) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := cli.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, func() *db.Headless {
+			return db.NewHeadless(db.HeadlessOptions{
+				Diagnostics: diagnosticManager,
+				Version:     appVersion,
+			})
+		})
+		stop()
+		os.Exit(code)
+	}
 	db := db.NewServiceWithDiagnosticsAndVersion(
 		diagnosticManager,
 		appVersion,
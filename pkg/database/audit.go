@@ -0,0 +1,179 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationQuery           AuditOperation = "query"
+	AuditOperationTransaction     AuditOperation = "transaction"
+	AuditOperationTableChanges    AuditOperation = "table_changes"
+	AuditOperationObjectChange    AuditOperation = "object_change"
+	AuditOperationSchemaMigration AuditOperation = "schema_migration"
+	AuditOperationDataSync        AuditOperation = "data_sync"
+	AuditOperationSecurityChange  AuditOperation = "security_change"
+	AuditOperationRestore         AuditOperation = "restore"
+	AuditOperationSessionCancel   AuditOperation = "session_cancel"
+)
+
+const (
+	// DefaultAuditLogLimit and MaxAuditLogLimit bound how many entries one
+	// audit log page returns.
+	DefaultAuditLogLimit = 200
+	MaxAuditLogLimit     = 5000
+)
+
+// AuditStatement is one statement of an audited write. SQL has secrets and
+// literal values redacted before it is recorded.
+type AuditStatement struct {
+	SQL          string `json:"sql"`
+	RowsAffected *int64 `json:"rowsAffected,omitempty"`
+}
+
+// AuditEntry records one successful write. Entries form a hash chain:
+// Hash is the SHA-256 of the entry encoded with an empty Hash, and
+// PreviousHash repeats the Hash of the entry before it, so editing,
+// removing, or reordering an entry breaks every later link.
+type AuditEntry struct {
+	Sequence      int64            `json:"sequence"`
+	ID            string           `json:"id"`
+	Operation     AuditOperation   `json:"operation"`
+	StartedAt     time.Time        `json:"startedAt"`
+	RecordedAt    time.Time        `json:"recordedAt"`
+	DurationMS    int64            `json:"durationMs"`
+	ConnectionID  string           `json:"connectionId"`
+	ProfileID     string           `json:"profileId,omitempty"`
+	ProfileName   string           `json:"profileName"`
+	Environment   string           `json:"environment,omitempty"`
+	Engine        string           `json:"engine"`
+	Database      string           `json:"database,omitempty"`
+	DatabaseUser  string           `json:"databaseUser,omitempty"`
+	OSUser        string           `json:"osUser,omitempty"`
+	TransactionID string           `json:"transactionId,omitempty"`
+	Target        string           `json:"target,omitempty"`
+	Summary       string           `json:"summary"`
+	Statements    []AuditStatement `json:"statements"`
+	RowsAffected  *int64           `json:"rowsAffected,omitempty"`
+	PreviousHash  string           `json:"previousHash"`
+	Hash          string           `json:"hash"`
+}
+
+// ComputeHash returns the chain hash of the entry as currently filled in.
+func (entry AuditEntry) ComputeHash() (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("encode audit entry %d: %w", entry.Sequence, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerifyAuditLink checks that entry is intact and directly follows
+// previous, which is nil for the first entry of the log.
+func VerifyAuditLink(previous *AuditEntry, entry AuditEntry) error {
+	sequence, previousHash := int64(1), ""
+	if previous != nil {
+		sequence, previousHash = previous.Sequence+1, previous.Hash
+	}
+	if entry.Sequence != sequence {
+		return fmt.Errorf("entry %d follows entry %d", entry.Sequence, sequence-1)
+	}
+	if entry.PreviousHash != previousHash {
+		return fmt.Errorf("entry %d does not link to the entry before it", entry.Sequence)
+	}
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != entry.Hash {
+		return fmt.Errorf("entry %d was modified after it was recorded", entry.Sequence)
+	}
+	return nil
+}
+
+// AuditLogFilter selects audit entries. Empty fields match everything;
+// Search matches the summary, target, and statements case-insensitively.
+type AuditLogFilter struct {
+	ProfileID   string           `json:"profileId,omitempty"`
+	Environment string           `json:"environment,omitempty"`
+	Operations  []AuditOperation `json:"operations,omitempty"`
+	Since       *time.Time       `json:"since,omitempty"`
+	Until       *time.Time       `json:"until,omitempty"`
+	Search      string           `json:"search,omitempty"`
+	Limit       int              `json:"limit,omitempty"`
+}
+
+func (filter AuditLogFilter) Matches(entry AuditEntry) bool {
+	if filter.ProfileID != "" && entry.ProfileID != filter.ProfileID {
+		return false
+	}
+	if filter.Environment != "" && !strings.EqualFold(entry.Environment, filter.Environment) {
+		return false
+	}
+	if len(filter.Operations) > 0 {
+		matched := false
+		for _, operation := range filter.Operations {
+			matched = matched || operation == entry.Operation
+		}
+		if !matched {
+			return false
+		}
+	}
+	if filter.Since != nil && entry.RecordedAt.Before(*filter.Since) {
+		return false
+	}
+	if filter.Until != nil && entry.RecordedAt.After(*filter.Until) {
+		return false
+	}
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+	if search == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(entry.Summary), search) ||
+		strings.Contains(strings.ToLower(entry.Target), search) {
+		return true
+	}
+	for _, statement := range entry.Statements {
+		if strings.Contains(strings.ToLower(statement.SQL), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLogPage holds the newest matching entries first. Total counts every
+// match, including those beyond the page limit.
+type AuditLogPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Total   int          `json:"total"`
+	// WriteError is the last failure to record a write during this
+	// session. The write itself had already succeeded.
+	WriteError string `json:"writeError,omitempty"`
+}
+
+// AuditVerification is the result of walking the whole chain. Head is the
+// hash of the newest entry; keeping a copy elsewhere also makes removal of
+// trailing entries detectable.
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int64  `json:"entries"`
+	Head     string `json:"head,omitempty"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+// AuditExportRequest exports the entries matching Filter, oldest first,
+// ignoring its Limit. JSON writes one stored entry per line so the hashes
+// can be checked again; CSV flattens the statements into one column.
+type AuditExportRequest struct {
+	Filter AuditLogFilter `json:"filter"`
+	Format ExportFormat   `json:"format"`
+}
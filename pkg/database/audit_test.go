@@ -0,0 +1,62 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLinkRequiresSequenceAndPreviousHash(t *testing.T) {
+	first := AuditEntry{Sequence: 1, Operation: AuditOperationQuery, Summary: "Ran UPDATE"}
+	first.Hash, _ = first.ComputeHash()
+	if err := VerifyAuditLink(nil, first); err != nil {
+		t.Fatalf("VerifyAuditLink(first) = %v", err)
+	}
+	second := AuditEntry{Sequence: 2, Operation: AuditOperationRestore, PreviousHash: first.Hash}
+	second.Hash, _ = second.ComputeHash()
+	if err := VerifyAuditLink(&first, second); err != nil {
+		t.Fatalf("VerifyAuditLink(second) = %v", err)
+	}
+	if err := VerifyAuditLink(nil, second); err == nil {
+		t.Fatal("an entry without its predecessor must not verify")
+	}
+	forged := first
+	forged.Summary = "Ran SELECT"
+	if err := VerifyAuditLink(nil, forged); err == nil {
+		t.Fatal("an edited entry must not verify")
+	}
+}
+
+func TestAuditLogFilterMatchesEveryCondition(t *testing.T) {
+	recorded := time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+	entry := AuditEntry{
+		Operation:   AuditOperationQuery,
+		ProfileID:   "profile-1",
+		Environment: "production",
+		RecordedAt:  recorded,
+		Summary:     "Ran DELETE",
+		Statements:  []AuditStatement{{SQL: "DELETE FROM orders WHERE id = ?"}},
+	}
+	before, after := recorded.Add(-time.Hour), recorded.Add(time.Hour)
+	for _, filter := range []AuditLogFilter{
+		{},
+		{ProfileID: "profile-1", Environment: "Production"},
+		{Operations: []AuditOperation{AuditOperationRestore, AuditOperationQuery}},
+		{Since: &before, Until: &after},
+		{Search: "ORDERS"},
+	} {
+		if !filter.Matches(entry) {
+			t.Fatalf("%+v did not match", filter)
+		}
+	}
+	for _, filter := range []AuditLogFilter{
+		{ProfileID: "profile-2"},
+		{Operations: []AuditOperation{AuditOperationRestore}},
+		{Since: &after},
+		{Until: &before},
+		{Search: "customers"},
+	} {
+		if filter.Matches(entry) {
+			t.Fatalf("%+v matched", filter)
+		}
+	}
+}
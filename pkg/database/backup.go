@@ -14,6 +14,9 @@ const (
 	BackupFormatMySQLSQL        BackupFormat = "mysql_sql"
 	BackupFormatOracleDataPump  BackupFormat = "oracle_datapump"
 	BackupFormatSQLServerNative BackupFormat = "sqlserver_native"
+	// BackupFormatLogical is written by Rolling Thunder itself from table
+	// DDL and exported rows, so it needs no client tools or server files.
+	BackupFormatLogical BackupFormat = "rollingthunder_logical"
 )
 
 type BackupDirectory struct {
@@ -35,6 +38,9 @@ type BackupCapabilities struct {
 	RequiresDirectory bool              `json:"requiresDirectory"`
 	ServerSideFiles   bool              `json:"serverSideFiles"`
 	Directories       []BackupDirectory `json:"directories"`
+	// LogicalAvailable reports that BackupRequest.Format may select the
+	// built-in logical format instead of the engine's native one.
+	LogicalAvailable bool `json:"logicalAvailable"`
 }
 
 type BackupRequest struct {
@@ -45,6 +51,8 @@ type BackupRequest struct {
 	ServerPath   string `json:"serverPath,omitempty"`
 	SchemaOnly   bool   `json:"schemaOnly"`
 	DataOnly     bool   `json:"dataOnly"`
+	// Format is empty for the connection's default backup format.
+	Format BackupFormat `json:"format,omitempty"`
 }
 
 func (request BackupRequest) Validate() error {
@@ -60,6 +68,9 @@ func (request BackupRequest) Validate() error {
 	if strings.ContainsAny(request.ServerPath, "\x00\r\n") {
 		return ErrBackupServerPathInvalid
 	}
+	if request.Format != "" && request.Format != BackupFormatLogical {
+		return ErrBackupFormatUnsupported
+	}
 	return nil
 }
 
@@ -68,6 +79,11 @@ type BackupResult struct {
 	Bytes     int64        `json:"bytes"`
 	Format    BackupFormat `json:"format"`
 	Cancelled bool         `json:"cancelled"`
+	// CatalogID identifies the backup catalog entry for this backup.
+	// Warning is set instead when the backup succeeded but the catalog
+	// could not be updated.
+	CatalogID string `json:"catalogId,omitempty"`
+	Warning   string `json:"warning,omitempty"`
 }
 
 type RestoreFileSelection struct {
@@ -183,4 +199,5 @@ const (
 	ErrBackupScopeConflict      backupError = "schema-only and data-only cannot both be enabled"
 	ErrBackupDirectoryInvalid   backupError = "backup directory contains invalid characters"
 	ErrBackupServerPathInvalid  backupError = "server backup path contains invalid characters"
+	ErrBackupFormatUnsupported  backupError = "only the logical backup format can be requested explicitly"
 )
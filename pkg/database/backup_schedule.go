@@ -0,0 +1,330 @@
+package database
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BackupOutcome string
+
+const (
+	BackupOutcomeSucceeded BackupOutcome = "succeeded"
+	BackupOutcomeFailed    BackupOutcome = "failed"
+	BackupOutcomeCancelled BackupOutcome = "cancelled"
+)
+
+type BackupTrigger string
+
+const (
+	BackupTriggerManual    BackupTrigger = "manual"
+	BackupTriggerScheduled BackupTrigger = "scheduled"
+)
+
+// BackupRetention limits how many successful backups of one schedule are
+// kept on disk. KeepLast keeps the newest N backups; KeepDailyDays also
+// keeps the newest backup of each of the last D calendar days. A backup
+// is deleted only when neither rule keeps it, and a zero policy keeps
+// everything.
+type BackupRetention struct {
+	KeepLast      int `json:"keepLast"`
+	KeepDailyDays int `json:"keepDailyDays"`
+}
+
+func (retention BackupRetention) enabled() bool {
+	return retention.KeepLast > 0 || retention.KeepDailyDays > 0
+}
+
+// Expired returns the IDs of successful, still-present entries that the
+// policy no longer keeps. Days are counted in now's location.
+func (retention BackupRetention) Expired(
+	entries []BackupCatalogEntry,
+	now time.Time,
+) []string {
+	if !retention.enabled() {
+		return nil
+	}
+	candidates := make([]BackupCatalogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Outcome == BackupOutcomeSucceeded && entry.PrunedAt == nil {
+			candidates = append(candidates, entry)
+		}
+	}
+	sort.SliceStable(candidates, func(left, right int) bool {
+		return candidates[left].StartedAt.After(candidates[right].StartedAt)
+	})
+	kept := make(map[string]struct{}, len(candidates))
+	for index := 0; index < retention.KeepLast && index < len(candidates); index++ {
+		kept[candidates[index].ID] = struct{}{}
+	}
+	if retention.KeepDailyDays > 0 {
+		location := now.Location()
+		year, month, day := now.Date()
+		oldest := time.Date(year, month, day-retention.KeepDailyDays+1, 0, 0, 0, 0, location)
+		days := make(map[string]struct{}, retention.KeepDailyDays)
+		for _, entry := range candidates {
+			started := entry.StartedAt.In(location)
+			if started.Before(oldest) {
+				continue
+			}
+			day := started.Format(time.DateOnly)
+			if _, seen := days[day]; seen {
+				continue
+			}
+			days[day] = struct{}{}
+			kept[entry.ID] = struct{}{}
+		}
+	}
+	expired := make([]string, 0, len(candidates))
+	for _, entry := range candidates {
+		if _, keep := kept[entry.ID]; !keep {
+			expired = append(expired, entry.ID)
+		}
+	}
+	return expired
+}
+
+// BackupSchedule runs a backup of a saved connection profile into a local
+// directory while the application is open. Directory names the Oracle
+// Data Pump DIRECTORY object, exactly as in BackupRequest.
+type BackupSchedule struct {
+	ID          string          `json:"id"`
+	ProfileID   string          `json:"profileId"`
+	Name        string          `json:"name"`
+	Cron        string          `json:"cron"`
+	Enabled     bool            `json:"enabled"`
+	Destination string          `json:"destination"`
+	Format      BackupFormat    `json:"format,omitempty"`
+	Schema      string          `json:"schema,omitempty"`
+	Directory   string          `json:"directory,omitempty"`
+	SchemaOnly  bool            `json:"schemaOnly"`
+	DataOnly    bool            `json:"dataOnly"`
+	Retention   BackupRetention `json:"retention"`
+}
+
+func (schedule BackupSchedule) Validate() error {
+	if strings.TrimSpace(schedule.ProfileID) == "" {
+		return fmt.Errorf("backup schedule needs a saved connection profile")
+	}
+	if _, err := ParseCronSchedule(schedule.Cron); err != nil {
+		return err
+	}
+	destination := strings.TrimSpace(schedule.Destination)
+	if destination == "" || !filepath.IsAbs(destination) {
+		return fmt.Errorf("backup schedule destination must be an absolute directory")
+	}
+	if schedule.Retention.KeepLast < 0 || schedule.Retention.KeepDailyDays < 0 {
+		return fmt.Errorf("backup retention counts cannot be negative")
+	}
+	return BackupRequest{
+		ConnectionID: schedule.ProfileID,
+		Schema:       schedule.Schema,
+		Directory:    schedule.Directory,
+		SchemaOnly:   schedule.SchemaOnly,
+		DataOnly:     schedule.DataOnly,
+		Format:       schedule.Format,
+	}.Validate()
+}
+
+// BackupScheduleStatus is a schedule with the state the scheduler keeps in
+// memory and the newest catalog entry it produced.
+type BackupScheduleStatus struct {
+	Schedule  BackupSchedule      `json:"schedule"`
+	NextRunAt *time.Time          `json:"nextRunAt,omitempty"`
+	LastRun   *BackupCatalogEntry `json:"lastRun,omitempty"`
+}
+
+// BackupCatalogEntry records one backup attempt. SHA256 is the digest of
+// the file as written; catalog restores refuse a file that no longer
+// matches it. Native SQL Server backups stay on the server and have no
+// local digest.
+type BackupCatalogEntry struct {
+	ID          string        `json:"id"`
+	ProfileID   string        `json:"profileId,omitempty"`
+	ProfileName string        `json:"profileName"`
+	ScheduleID  string        `json:"scheduleId,omitempty"`
+	Trigger     BackupTrigger `json:"trigger"`
+	Engine      string        `json:"engine"`
+	Format      BackupFormat  `json:"format"`
+	Path        string        `json:"path,omitempty"`
+	Bytes       int64         `json:"bytes"`
+	SHA256      string        `json:"sha256,omitempty"`
+	ServerSide  bool          `json:"serverSide,omitempty"`
+	StartedAt   time.Time     `json:"startedAt"`
+	DurationMS  int64         `json:"durationMs"`
+	Outcome     BackupOutcome `json:"outcome"`
+	Error       string        `json:"error,omitempty"`
+	PrunedAt    *time.Time    `json:"prunedAt,omitempty"`
+}
+
+// Restorable reports whether the entry still names a local backup file.
+func (entry BackupCatalogEntry) Restorable() bool {
+	return entry.Outcome == BackupOutcomeSucceeded &&
+		entry.PrunedAt == nil &&
+		!entry.ServerSide &&
+		entry.Path != "" &&
+		entry.SHA256 != ""
+}
+
+// CronSchedule is a parsed five-field cron expression: minute, hour, day
+// of month, month, and day of week. Fields accept *, numbers, ranges,
+// lists, and /step; @hourly, @daily, @weekly, and @monthly are shorthands.
+// As in cron, a restricted day of month and day of week match either.
+type CronSchedule struct {
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	anyDay     bool
+	anyWeekday bool
+}
+
+var cronShorthands = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+func ParseCronSchedule(expression string) (CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if expanded, ok := cronShorthands[strings.ToLower(expression)]; ok {
+		expression = expanded
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf(
+			"cron expression %q needs five fields: minute hour day month weekday",
+			expression,
+		)
+	}
+	var (
+		schedule CronSchedule
+		err      error
+	)
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return CronSchedule{}, fmt.Errorf("cron minute: %w", err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return CronSchedule{}, fmt.Errorf("cron hour: %w", err)
+	}
+	if schedule.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return CronSchedule{}, fmt.Errorf("cron day of month: %w", err)
+	}
+	if schedule.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return CronSchedule{}, fmt.Errorf("cron month: %w", err)
+	}
+	if schedule.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return CronSchedule{}, fmt.Errorf("cron weekday: %w", err)
+	}
+	// 7 is an alias for Sunday.
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays = schedule.weekdays&^(1<<7) | 1
+	}
+	schedule.anyDay = strings.HasPrefix(fields[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(fields[4], "*")
+	return schedule, nil
+}
+
+func parseCronField(field string, minimum int, maximum int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = value
+		}
+		low, high := minimum, maximum
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			first, last, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = cronValue(first, minimum, maximum); err != nil {
+				return 0, err
+			}
+			if high, err = cronValue(last, minimum, maximum); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("range %q is reversed", rangePart)
+			}
+		default:
+			value, err := cronValue(rangePart, minimum, maximum)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(text string, minimum int, maximum int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if value < minimum || value > maximum {
+		return 0, fmt.Errorf("value %d is outside %d-%d", value, minimum, maximum)
+	}
+	return value, nil
+}
+
+func (schedule CronSchedule) matchesDay(moment time.Time) bool {
+	day := schedule.days&(1<<uint(moment.Day())) != 0
+	weekday := schedule.weekdays&(1<<uint(moment.Weekday())) != 0
+	switch {
+	case schedule.anyDay && schedule.anyWeekday:
+		return true
+	case schedule.anyDay:
+		return weekday
+	case schedule.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// Next returns the first matching minute strictly after after, in after's
+// location. The zero time means nothing matches within five years, which
+// only happens for dates such as February 30.
+func (schedule CronSchedule) Next(after time.Time) time.Time {
+	moment := after.Truncate(time.Minute).Add(time.Minute)
+	limit := moment.AddDate(5, 0, 0)
+	for moment.Before(limit) {
+		if schedule.months&(1<<uint(moment.Month())) == 0 {
+			moment = time.Date(moment.Year(), moment.Month()+1, 1, 0, 0, 0, 0, moment.Location())
+			continue
+		}
+		if !schedule.matchesDay(moment) {
+			moment = time.Date(moment.Year(), moment.Month(), moment.Day()+1, 0, 0, 0, 0, moment.Location())
+			continue
+		}
+		if schedule.hours&(1<<uint(moment.Hour())) == 0 {
+			moment = time.Date(moment.Year(), moment.Month(), moment.Day(), moment.Hour()+1, 0, 0, 0, moment.Location())
+			continue
+		}
+		if schedule.minutes&(1<<uint(moment.Minute())) == 0 {
+			moment = moment.Add(time.Minute)
+			continue
+		}
+		return moment
+	}
+	return time.Time{}
+}
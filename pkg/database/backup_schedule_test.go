@@ -0,0 +1,90 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	location := time.FixedZone("UTC+2", 2*60*60)
+	after := time.Date(2026, time.March, 31, 23, 59, 30, 0, location)
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, time.April, 1, 0, 0, 0, 0, location)},
+		{"30 2 * * *", time.Date(2026, time.April, 1, 2, 30, 0, 0, location)},
+		{"0 3 * * 7", time.Date(2026, time.April, 5, 3, 0, 0, 0, location)},
+		{"0 0 15 * 1", time.Date(2026, time.April, 6, 0, 0, 0, 0, location)},
+		{"0 9-17/4 * 6 *", time.Date(2026, time.June, 1, 9, 0, 0, 0, location)},
+		{"@monthly", time.Date(2026, time.April, 1, 0, 0, 0, 0, location)},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.expression)
+		if err != nil {
+			t.Fatalf("ParseCronSchedule(%q) error = %v", test.expression, err)
+		}
+		if got := schedule.Next(after); !got.Equal(test.want) {
+			t.Fatalf("%q next = %s, want %s", test.expression, got, test.want)
+		}
+	}
+	impossible, err := ParseCronSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := impossible.Next(after); !next.IsZero() {
+		t.Fatalf("February 30 next = %s", next)
+	}
+}
+
+func TestParseCronScheduleRejectsInvalidFields(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := ParseCronSchedule(expression); err == nil {
+			t.Fatalf("ParseCronSchedule(%q) accepted an invalid expression", expression)
+		}
+	}
+}
+
+func TestBackupRetentionExpired(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+	entry := func(id string, age time.Duration) BackupCatalogEntry {
+		return BackupCatalogEntry{ID: id, Outcome: BackupOutcomeSucceeded, StartedAt: now.Add(-age)}
+	}
+	entries := []BackupCatalogEntry{
+		entry("today-late", time.Hour),
+		entry("today-early", 10*time.Hour),
+		entry("yesterday", 24*time.Hour),
+		entry("last-week", 7*24*time.Hour),
+		{ID: "failed", Outcome: BackupOutcomeFailed, StartedAt: now},
+	}
+	expired := BackupRetention{KeepLast: 1, KeepDailyDays: 2}.Expired(entries, now)
+	if len(expired) != 2 || expired[0] != "today-early" || expired[1] != "last-week" {
+		t.Fatalf("expired = %v", expired)
+	}
+	if expired := (BackupRetention{}).Expired(entries, now); len(expired) != 0 {
+		t.Fatalf("zero policy expired %v", expired)
+	}
+}
+
+func TestBackupScheduleValidation(t *testing.T) {
+	schedule := BackupSchedule{
+		ProfileID:   "profile",
+		Cron:        "@daily",
+		Destination: "/var/backups",
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	invalid := []BackupSchedule{
+		{Cron: "@daily", Destination: "/var/backups"},
+		{ProfileID: "profile", Cron: "daily", Destination: "/var/backups"},
+		{ProfileID: "profile", Cron: "@daily", Destination: "backups"},
+		{ProfileID: "profile", Cron: "@daily", Destination: "/var/backups", Retention: BackupRetention{KeepLast: -1}},
+		{ProfileID: "profile", Cron: "@daily", Destination: "/var/backups", SchemaOnly: true, DataOnly: true},
+	}
+	for _, schedule := range invalid {
+		if err := schedule.Validate(); err == nil {
+			t.Fatalf("invalid schedule accepted: %+v", schedule)
+		}
+	}
+}
@@ -23,3 +23,47 @@ func TestBackupRequestValidation(t *testing.T) {
 		t.Fatalf("unsafe directory error = %v", err)
 	}
 }
+
+func TestBackupRequestFormatValidation(t *testing.T) {
+	if err := (BackupRequest{
+		ConnectionID: "connection",
+		Format:       BackupFormatLogical,
+	}).Validate(); err != nil {
+		t.Fatalf("logical backup request rejected: %v", err)
+	}
+	if err := (BackupRequest{
+		ConnectionID: "connection",
+		Format:       BackupFormatPostgresCustom,
+	}).Validate(); err != ErrBackupFormatUnsupported {
+		t.Fatalf("native format request error = %v", err)
+	}
+}
+
+func TestLogicalBackupManifestValidation(t *testing.T) {
+	checksum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	manifest := LogicalBackupManifest{
+		Format:  BackupFormatLogical,
+		Version: LogicalBackupVersion,
+		Engine:  DriverSQLite,
+		Tables: []LogicalBackupTable{{
+			Name:    "items",
+			Columns: []LogicalBackupColumn{{Name: "id", DataType: "INTEGER"}},
+			DDL:     &LogicalBackupEntry{Path: "tables/0001/ddl.sql", SHA256: checksum},
+			Rows:    &LogicalBackupEntry{Path: "tables/0001/rows.json", SHA256: checksum},
+		}},
+	}
+	if err := manifest.Validate(); err != nil {
+		t.Fatalf("valid manifest rejected: %v", err)
+	}
+	for _, path := range []string{"../manifest.json", "tables/../../etc/passwd", "/tables/0001/rows.json"} {
+		manifest.Tables[0].Rows.Path = path
+		if err := manifest.Validate(); err == nil {
+			t.Fatalf("entry path %q was accepted", path)
+		}
+	}
+	manifest.Tables[0].Rows.Path = "tables/0001/rows.json"
+	manifest.SchemaOnly = true
+	if err := manifest.Validate(); err == nil {
+		t.Fatal("schema-only manifest with row entries was accepted")
+	}
+}
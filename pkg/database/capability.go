@@ -35,6 +35,21 @@ type Dialect struct {
 
 // Capabilities is the feature contract advertised by a connected driver.
 // A false value means the UI must not expose the corresponding workflow.
+// RowMutations marks engines whose UpdateRow and DeleteRow are carried out
+// as ALTER TABLE mutations instead of row-level UPDATE and DELETE.
+// ServerOutput marks engines that only collect procedural output after it
+// is switched on for the session, which the query editor offers per tab.
+// QueryCursors marks drivers that can hold a read-only result open and
+// page through it past the query row limit.
+// FilterGroups marks drivers that honour Table.Where, and RegexFilters
+// those that can evaluate the regex filter operator. JSONPaths marks
+// drivers that filter and sort by Filter.Path and Sort.Path.
+// KeysetPagination marks engines whose primary keys are unique, so table
+// pages can seek past the previous page's last key instead of using OFFSET.
+// RowEstimates marks drivers that can read an approximate row count from
+// table statistics instead of running COUNT(*).
+// ReturningRows marks drivers that can make a data-changing statement
+// return the rows it changed, which dry runs use to sample them.
 type Capabilities struct {
 	Engine              string  `json:"engine"`
 	DisplayName         string  `json:"displayName"`
@@ -64,6 +79,7 @@ type Capabilities struct {
 	Transactions        bool    `json:"transactions"`
 	TransactionalDDL    bool    `json:"transactionalDDL"`
 	AtomicTableChanges  bool    `json:"atomicTableChanges"`
+	RowMutations        bool    `json:"rowMutations"`
 	SQLInsertExport     bool    `json:"sqlInsertExport"`
 	FileDatabase        bool    `json:"fileDatabase"`
 	AttachedDatabases   bool    `json:"attachedDatabases"`
@@ -72,6 +88,14 @@ type Capabilities struct {
 	ManageSecurity      bool    `json:"manageSecurity"`
 	ActivityMonitor     bool    `json:"activityMonitor"`
 	SSHConnections      bool    `json:"sshConnections"`
+	ServerOutput        bool    `json:"serverOutput"`
+	QueryCursors        bool    `json:"queryCursors"`
+	FilterGroups        bool    `json:"filterGroups"`
+	RegexFilters        bool    `json:"regexFilters"`
+	JSONPaths           bool    `json:"jsonPaths"`
+	KeysetPagination    bool    `json:"keysetPagination"`
+	RowEstimates        bool    `json:"rowEstimates"`
+	ReturningRows       bool    `json:"returningRows"`
 }
 
 func (capabilities Capabilities) Validate() error {
@@ -127,6 +151,12 @@ func (capabilities Capabilities) Validate() error {
 	if capabilities.AttachedDatabases && !capabilities.Databases {
 		return fmt.Errorf("attached databases require database support")
 	}
+	// Row mutations rewrite table parts in the background and cannot be
+	// rolled back, so a driver that edits rows this way cannot also claim
+	// atomic change sets.
+	if capabilities.RowMutations && capabilities.AtomicTableChanges {
+		return fmt.Errorf("row mutations cannot be applied as atomic table changes")
+	}
 	return nil
 }
 
@@ -0,0 +1,159 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+)
+
+// ClickHouse tracks running queries rather than sessions, so each query in
+// system.processes is reported as one session keyed by its query_id.
+const clickHouseActivityQuery = `
+	SELECT
+		query_id,
+		user,
+		current_database,
+		toString(address),
+		if(client_name != '', client_name, http_user_agent),
+		substring(query, 1, 4000),
+		toInt64(elapsed * 1000),
+		is_cancelled,
+		query_id = queryID()
+	FROM system.processes
+	ORDER BY elapsed DESC, query_id`
+
+func (c *ClickHouse) GetDatabaseActivity(
+	ctx context.Context,
+) (database.DatabaseActivity, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.DatabaseActivity{}, err
+	}
+	rows, err := c.conn.QueryContext(ctx, clickHouseActivityQuery)
+	if err != nil {
+		return database.DatabaseActivity{}, fmt.Errorf(
+			"read ClickHouse processes: %w",
+			err,
+		)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	sessions := make([]database.DatabaseSession, 0)
+	currentID := ""
+	for rows.Next() {
+		var (
+			id           string
+			user         string
+			databaseName string
+			client       string
+			application  string
+			query        string
+			durationMS   int64
+			cancelled    uint8
+			isCurrent    bool
+		)
+		if err := rows.Scan(
+			&id,
+			&user,
+			&databaseName,
+			&client,
+			&application,
+			&query,
+			&durationMS,
+			&cancelled,
+			&isCurrent,
+		); err != nil {
+			return database.DatabaseActivity{}, err
+		}
+		if isCurrent && currentID == "" {
+			currentID = id
+		}
+		state := "running"
+		if cancelled == 1 {
+			state = "cancelling"
+		}
+		started := now.Add(-time.Duration(durationMS) * time.Millisecond)
+		sessions = append(sessions, database.DatabaseSession{
+			ID:           id,
+			User:         user,
+			Database:     databaseName,
+			Client:       client,
+			Application:  application,
+			Command:      "Query",
+			State:        state,
+			Query:        query,
+			BlockedBy:    []string{},
+			DurationMS:   durationMS,
+			QueryStarted: &started,
+			IsCurrent:    isCurrent,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return database.DatabaseActivity{}, err
+	}
+	return database.DatabaseActivity{
+		Supported:           true,
+		Engine:              database.DriverClickHouse,
+		CurrentSessionID:    currentID,
+		CanCancelQuery:      true,
+		CanTerminateSession: false,
+		Sessions:            sessions,
+		CapturedAt:          now,
+		Message: "ClickHouse lists running queries. KILL QUERY cancels a query; " +
+			"there is no separate session to terminate.",
+	}, nil
+}
+
+func (c *ClickHouse) CancelDatabaseSession(
+	ctx context.Context,
+	sessionID string,
+	terminate bool,
+) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	queryID := strings.TrimSpace(sessionID)
+	if queryID == "" {
+		return fmt.Errorf("invalid ClickHouse query ID %q", sessionID)
+	}
+	if terminate {
+		return fmt.Errorf(
+			"ClickHouse has no sessions to terminate; cancel query %s instead",
+			queryID,
+		)
+	}
+	var total, protected uint64
+	if err := c.conn.QueryRowContext(
+		ctx,
+		`SELECT
+			count(),
+			countIf(query_id = queryID() OR startsWith(client_name, ?))
+		 FROM system.processes
+		 WHERE query_id = ?`,
+		clientProduct+"/",
+		queryID,
+	).Scan(&total, &protected); err != nil {
+		return fmt.Errorf("verify ClickHouse query %s: %w", queryID, err)
+	}
+	if total == 0 {
+		return fmt.Errorf("ClickHouse query %s is no longer running", queryID)
+	}
+	if protected > 0 {
+		return fmt.Errorf(
+			"Rolling Thunder protects its own database sessions; cancel the query from its query tab",
+		)
+	}
+	if _, err := c.conn.ExecContext(
+		ctx,
+		"KILL QUERY WHERE query_id = ?",
+		queryID,
+	); err != nil {
+		return fmt.Errorf("ClickHouse refused to cancel query %s: %w", queryID, err)
+	}
+	return nil
+}
+
+var _ database.ActivityDriver = (*ClickHouse)(nil)
@@ -0,0 +1,452 @@
+package clickhouse
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	clickhousedriver "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+const (
+	defaultConnectionTimeout  = 15 * time.Second
+	defaultConnectionLifetime = 5 * time.Minute
+	defaultMaxIdleConnections = 2
+	defaultMaxOpenConnections = 8
+	defaultDatabaseName       = "default"
+
+	// clientProduct is sent in the native handshake and shows up as the
+	// client_name of every query in system.processes.
+	clientProduct        = application.SettingsDirectoryName
+	clientProductVersion = "desktop"
+)
+
+type Config struct {
+	Host          string
+	Port          string
+	User          string
+	Password      string
+	Db            string
+	SSLMode       string
+	SSLRootCert   string
+	SSLCert       string
+	SSLKey        string
+	TLSServerName string
+}
+
+type ClickHouse struct {
+	cfg      Config
+	ctx      context.Context
+	conn     *sql.DB
+	database string
+}
+
+func NewClickHouse(ctx context.Context, cfg Config) *ClickHouse {
+	return &ClickHouse{cfg: cfg, ctx: ctx}
+}
+
+// buildOptions uses the native TCP protocol. Without an explicit port it
+// picks 9000, or 9440 when TLS is enabled, matching the server defaults.
+func buildOptions(cfg Config) (*clickhousedriver.Options, error) {
+	host := strings.TrimSpace(cfg.Host)
+	if host == "" {
+		host = database.DefaultHost
+	}
+	mode := strings.ToLower(strings.TrimSpace(cfg.SSLMode))
+	port := strings.TrimSpace(cfg.Port)
+	if port == "" {
+		port = database.DefaultClickHousePort
+		if mode != "" && mode != "disable" {
+			port = database.DefaultClickHouseTLSPort
+		}
+	}
+	if value, err := strconv.Atoi(port); err != nil || value < 1 || value > 65535 {
+		return nil, fmt.Errorf("ClickHouse port must be a number from 1 to 65535")
+	}
+	databaseName := strings.TrimSpace(cfg.Db)
+	if databaseName == "" {
+		databaseName = defaultDatabaseName
+	}
+	user := strings.TrimSpace(cfg.User)
+	if user == "" {
+		user = "default"
+	}
+	serverName := strings.TrimSpace(cfg.TLSServerName)
+	if serverName == "" {
+		serverName = host
+	}
+	tlsConfig, err := buildTLSConfig(cfg, serverName)
+	if err != nil {
+		return nil, err
+	}
+	options := &clickhousedriver.Options{
+		Protocol: clickhousedriver.Native,
+		Addr:     []string{net.JoinHostPort(host, port)},
+		Auth: clickhousedriver.Auth{
+			Database: databaseName,
+			Username: user,
+			Password: cfg.Password,
+		},
+		TLS:             tlsConfig,
+		DialTimeout:     defaultConnectionTimeout,
+		ConnMaxLifetime: defaultConnectionLifetime,
+		MaxIdleConns:    defaultMaxIdleConnections,
+		MaxOpenConns:    defaultMaxOpenConnections,
+	}
+	options.ClientInfo.Products = append(
+		options.ClientInfo.Products,
+		struct{ Name, Version string }{clientProduct, clientProductVersion},
+	)
+	return options, nil
+}
+
+func buildTLSConfig(cfg Config, serverName string) (*tls.Config, error) {
+	mode := strings.ToLower(strings.TrimSpace(cfg.SSLMode))
+	if mode == "" || mode == "disable" {
+		if cfg.SSLRootCert != "" || cfg.SSLCert != "" || cfg.SSLKey != "" {
+			return nil, fmt.Errorf(
+				"TLS certificate paths require an SSL mode other than disable",
+			)
+		}
+		return nil, nil
+	}
+	if mode != "require" && mode != "verify-ca" && mode != "verify-full" {
+		return nil, fmt.Errorf("unsupported ClickHouse SSL mode %q", cfg.SSLMode)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if mode == "require" {
+		// This mode guarantees encryption but intentionally does not
+		// authenticate the server certificate.
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec
+	}
+
+	if strings.TrimSpace(cfg.SSLRootCert) != "" {
+		pem, err := os.ReadFile(cfg.SSLRootCert)
+		if err != nil {
+			return nil, fmt.Errorf("read ClickHouse CA certificate: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil || roots == nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ClickHouse CA certificate contains no valid certificates")
+		}
+		tlsConfig.RootCAs = roots
+	} else if mode == "verify-ca" {
+		return nil, fmt.Errorf("verify-ca requires a CA certificate path")
+	}
+
+	certPath := strings.TrimSpace(cfg.SSLCert)
+	keyPath := strings.TrimSpace(cfg.SSLKey)
+	if (certPath == "") != (keyPath == "") {
+		return nil, fmt.Errorf(
+			"ClickHouse client certificate and key must be provided together",
+		)
+	}
+	if certPath != "" {
+		certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load ClickHouse client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if mode == "verify-ca" {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("ClickHouse server did not provide a certificate")
+			}
+			intermediates := x509.NewCertPool()
+			for _, certificate := range state.PeerCertificates[1:] {
+				intermediates.AddCert(certificate)
+			}
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         tlsConfig.RootCAs,
+				Intermediates: intermediates,
+			})
+			return err
+		}
+	}
+	return tlsConfig, nil
+}
+
+func (c *ClickHouse) Connect(ctx context.Context) error {
+	if ctx == nil {
+		ctx = c.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.conn != nil {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("close previous ClickHouse connection: %w", err)
+		}
+	}
+	options, err := buildOptions(c.cfg)
+	if err != nil {
+		return err
+	}
+	connection := clickhousedriver.OpenDB(options)
+	connection.SetConnMaxLifetime(defaultConnectionLifetime)
+	connection.SetMaxIdleConns(defaultMaxIdleConnections)
+	connection.SetMaxOpenConns(defaultMaxOpenConnections)
+	if err := connection.PingContext(ctx); err != nil {
+		_ = connection.Close()
+		return err
+	}
+	var current string
+	if err := connection.QueryRowContext(
+		ctx,
+		"SELECT currentDatabase()",
+	).Scan(&current); err != nil {
+		_ = connection.Close()
+		return fmt.Errorf("read ClickHouse database name: %w", err)
+	}
+	c.conn = connection
+	c.database = current
+	c.ctx = context.WithoutCancel(ctx)
+	return nil
+}
+
+func (c *ClickHouse) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *ClickHouse) Ping(ctx context.Context) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	return c.conn.PingContext(ctx)
+}
+
+func (c *ClickHouse) ensureConnected() error {
+	if c.conn == nil {
+		return errors.New("ClickHouse connection is not open")
+	}
+	return nil
+}
+
+func (c *ClickHouse) defaultDatabase(value string) string {
+	if strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	if c.database != "" {
+		return c.database
+	}
+	return defaultDatabaseName
+}
+
+func (c *ClickHouse) GetDatabaseInfo() (database.Info, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.Info{}, err
+	}
+	var version string
+	if err := c.conn.QueryRow("SELECT version()").Scan(&version); err != nil {
+		return database.Info{}, err
+	}
+	return database.Info{
+		Engine:   "ClickHouse",
+		Version:  version,
+		Database: c.defaultDatabase(""),
+	}, nil
+}
+
+func (c *ClickHouse) CountCollectionData(table database.Table) (int, error) {
+	return c.CountCollectionDataContext(c.ctx, table)
+}
+
+func (c *ClickHouse) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
+	if err := c.ensureConnected(); err != nil {
+		return 0, err
+	}
+	table.Schema = c.defaultDatabase(table.Schema)
+	structures, err := c.GetCollectionStructures(table)
+	if err != nil {
+		return 0, err
+	}
+	return sqladapter.CountTable(ctx, c.conn, table, structures, c.adapterDialect())
+}
+
+func (c *ClickHouse) GetCollectionData(
+	table database.Table,
+) (database.Structures, []map[string]interface{}, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, nil, err
+	}
+	table.Schema = c.defaultDatabase(table.Schema)
+	structures, err := c.GetCollectionStructures(table)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := sqladapter.GetTableData(
+		c.ctx,
+		c.conn,
+		table,
+		structures,
+		c.adapterDialect(),
+	)
+	return structures, rows, err
+}
+
+func (c *ClickHouse) InsertRow(
+	table database.Table,
+	data map[string]interface{},
+) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	table.Schema = c.defaultDatabase(table.Schema)
+	structures, err := c.GetCollectionStructures(table)
+	if err != nil {
+		return err
+	}
+	return sqladapter.InsertRowWithStructures(
+		c.conn,
+		table,
+		data,
+		structures,
+		c.adapterDialect(),
+	)
+}
+
+// rowReturningKeywords lists the statements that answer with a result
+// block. Everything else must go through ExecContext: the native protocol
+// ends such statements without a data block, and clickhouse-go reports that
+// as a broken connection, which database/sql would answer by running the
+// statement again on a fresh connection.
+var rowReturningKeywords = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"EXPLAIN":  true,
+	"EXISTS":   true,
+	"CHECK":    true,
+}
+
+// leadingKeyword returns the first keyword of a statement after comments,
+// whitespace, and opening parentheses.
+func leadingKeyword(query string) string {
+	rest := query
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(rest, "--"), strings.HasPrefix(rest, "#"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				return ""
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				return ""
+			}
+			rest = rest[end+2:]
+		default:
+			end := strings.IndexFunc(rest, func(character rune) bool {
+				return !(character >= 'a' && character <= 'z' ||
+					character >= 'A' && character <= 'Z' ||
+					character == '_')
+			})
+			if end < 0 {
+				end = len(rest)
+			}
+			return strings.ToUpper(rest[:end])
+		}
+	}
+}
+
+func (c *ClickHouse) ExecuteQuery(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.QueryResult{}, err
+	}
+	if rowReturningKeywords[leadingKeyword(query)] {
+		return sqladapter.ExecuteQuery(ctx, c.conn, query, options)
+	}
+	if _, err := c.conn.ExecContext(ctx, query, options.Args...); err != nil {
+		return database.QueryResult{}, err
+	}
+	return database.QueryResult{
+		Rows:    make([][]interface{}, 0),
+		Columns: make([]database.QueryColumn, 0),
+		ResultSets: []database.QueryResultSet{{
+			Columns:  make([]database.QueryColumn, 0),
+			Rows:     make([][]interface{}, 0),
+			RowLimit: options.MaxRows,
+		}},
+		RowLimit:       options.MaxRows,
+		StatementCount: 1,
+	}, nil
+}
+
+// OpenQueryCursor reads the statement's result blocks as pages are
+// fetched. Only row-returning statements can be held open.
+func (c *ClickHouse) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	if !rowReturningKeywords[leadingKeyword(query)] {
+		return nil, fmt.Errorf("only row-returning statements can be opened as a cursor")
+	}
+	return sqladapter.OpenCursor(ctx, c.conn, query, options, nil)
+}
+
+func (c *ClickHouse) ExportTable(
+	ctx context.Context,
+	request database.TableExportRequest,
+	writer io.Writer,
+) (database.ExportStats, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.ExportStats{}, err
+	}
+	request.Table.Schema = c.defaultDatabase(request.Table.Schema)
+	structures, err := c.GetCollectionStructures(request.Table)
+	if err != nil {
+		return database.ExportStats{}, err
+	}
+	return sqladapter.ExportTable(
+		ctx,
+		c.conn,
+		request,
+		structures,
+		c.adapterDialect(),
+		writer,
+	)
+}
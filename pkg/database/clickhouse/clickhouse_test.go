@@ -0,0 +1,442 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/drivertest"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+func TestClickHouseCapabilityContract(t *testing.T) {
+	driver := NewClickHouse(context.Background(), Config{})
+	drivertest.RunCapabilityContract(t, driver, "clickhouse")
+
+	capabilities := driver.Capabilities()
+	if capabilities.Transactions || capabilities.AtomicTableChanges {
+		t.Fatalf("ClickHouse must not advertise transactions: %+v", capabilities)
+	}
+	if !capabilities.RowMutations {
+		t.Fatal("ClickHouse row edits must be advertised as mutations")
+	}
+	if _, ok := database.Driver(driver).(database.TransactionalDriver); ok {
+		t.Fatal("ClickHouse must not implement TransactionalDriver")
+	}
+	if _, ok := database.Driver(driver).(database.TableChangeDriver); ok {
+		t.Fatal("ClickHouse must not implement TableChangeDriver")
+	}
+}
+
+func TestClickHouseQuoting(t *testing.T) {
+	if got := quoteIdentifier("odd`name\\"); got != "`odd\\`name\\\\`" {
+		t.Fatalf("quoteIdentifier() = %q", got)
+	}
+	if got := quoteLiteral(`it's \n`); got != `'it\'s \\n'` {
+		t.Fatalf("quoteLiteral() = %q", got)
+	}
+	if got := quoteQualified("analytics", "events"); got != "`analytics`.`events`" {
+		t.Fatalf("quoteQualified() = %q", got)
+	}
+
+	driver := NewClickHouse(context.Background(), Config{})
+	structures := database.Structures{
+		{Name: "id", DataType: "UInt64", IsPrimary: true},
+		{Name: "name", DataType: "Nullable(String)", Nullable: true},
+	}
+	query, args, err := sqladapter.BuildTableSelect(database.Table{
+		Schema:  "analytics",
+		Name:    "events",
+		Filters: []database.Filter{{Column: "name", Operator: database.FilterContains, Value: "storm"}},
+		Sorts:   []database.Sort{{Column: "id", Direction: database.SortDescending}},
+		Limit:   10,
+		Offset:  20,
+	}, structures, "*", driver.adapterDialect(), true)
+	if err != nil {
+		t.Fatalf("BuildTableSelect() error = %v", err)
+	}
+	for _, fragment := range []string{
+		"FROM `analytics`.`events`",
+		"toString(`name`) LIKE ?",
+		"ORDER BY `id` DESC",
+		"LIMIT 10 OFFSET 20",
+	} {
+		if !strings.Contains(query, fragment) {
+			t.Fatalf("query %q does not contain %q", query, fragment)
+		}
+	}
+	if len(args) != 1 || args[0] != "%storm%" {
+		t.Fatalf("args = %#v", args)
+	}
+}
+
+func TestClickHouseLeadingKeyword(t *testing.T) {
+	for query, want := range map[string]string{
+		"select 1":                             "SELECT",
+		"  -- note\n/* block */ (WITH x AS 1)": "WITH",
+		"# hash comment\nshow tables":          "SHOW",
+		"ALTER TABLE t DELETE WHERE id = 1":    "ALTER",
+		"/* unterminated":                      "",
+		"\n\tinsert into t values (1)":         "INSERT",
+		"DESC t":                               "DESC",
+		"system_flush_logs":                    "SYSTEM_FLUSH_LOGS",
+	} {
+		if got := leadingKeyword(query); got != want {
+			t.Errorf("leadingKeyword(%q) = %q, want %q", query, got, want)
+		}
+	}
+	if rowReturningKeywords["ALTER"] || rowReturningKeywords["INSERT"] {
+		t.Fatal("mutating statements must not be routed through QueryContext")
+	}
+}
+
+func TestClickHouseBuildOptions(t *testing.T) {
+	options, err := buildOptions(Config{})
+	if err != nil {
+		t.Fatalf("buildOptions() error = %v", err)
+	}
+	if options.Addr[0] != net.JoinHostPort(database.DefaultHost, "9000") || options.TLS != nil {
+		t.Fatalf("default address = %v, TLS = %v", options.Addr, options.TLS)
+	}
+	if options.Auth.Database != "default" || options.Auth.Username != "default" {
+		t.Fatalf("default auth = %+v", options.Auth)
+	}
+	if len(options.ClientInfo.Products) != 1 ||
+		options.ClientInfo.Products[0].Name != clientProduct {
+		t.Fatalf("client products = %+v", options.ClientInfo.Products)
+	}
+
+	options, err = buildOptions(Config{Host: "ch.internal", SSLMode: "require"})
+	if err != nil {
+		t.Fatalf("buildOptions(require) error = %v", err)
+	}
+	if options.Addr[0] != "ch.internal:9440" || options.TLS == nil ||
+		options.TLS.ServerName != "ch.internal" {
+		t.Fatalf("TLS address = %v, TLS = %+v", options.Addr, options.TLS)
+	}
+
+	if _, err := buildOptions(Config{Port: "90000"}); err == nil {
+		t.Fatal("buildOptions() accepted an out-of-range port")
+	}
+	if _, err := buildOptions(Config{SSLMode: "verify-ca"}); err == nil {
+		t.Fatal("buildOptions() accepted verify-ca without a CA certificate")
+	}
+	if _, err := buildOptions(Config{SSLRootCert: "/tmp/ca.pem"}); err == nil {
+		t.Fatal("buildOptions() accepted certificate paths with TLS disabled")
+	}
+}
+
+func TestClickHouseUpdateMutation(t *testing.T) {
+	structures := database.Structures{
+		{Name: "id", DataType: "UInt64", IsPrimary: true},
+		{Name: "name", DataType: "String"},
+		{Name: "score", DataType: "Float64"},
+		{Name: "name_length", DataType: "UInt64", IsGenerated: true},
+	}
+	statement, args, err := buildUpdateMutation(
+		database.Table{Schema: "analytics", Name: "players"},
+		map[string]interface{}{
+			"ID":          7,
+			"score":       9.5,
+			"name":        "Ada",
+			"name_length": 3,
+			"_isNew":      false,
+		},
+		"id",
+		structures,
+	)
+	if err != nil {
+		t.Fatalf("buildUpdateMutation() error = %v", err)
+	}
+	want := "ALTER TABLE `analytics`.`players` UPDATE `name` = ?, `score` = ? WHERE `id` = ?"
+	if statement != want {
+		t.Fatalf("statement = %q, want %q", statement, want)
+	}
+	if !slices.Equal(args, []interface{}{"Ada", 9.5, 7}) {
+		t.Fatalf("args = %#v", args)
+	}
+
+	if _, _, err := buildUpdateMutation(
+		database.Table{Name: "players"},
+		map[string]interface{}{"id": 7, "name_length": 3},
+		"id",
+		structures,
+	); err == nil {
+		t.Fatal("buildUpdateMutation() accepted an update with no mutable columns")
+	}
+	if _, _, err := buildUpdateMutation(
+		database.Table{Name: "players"},
+		map[string]interface{}{"name": "Ada"},
+		"id",
+		structures,
+	); err == nil {
+		t.Fatal("buildUpdateMutation() accepted a row without its key")
+	}
+}
+
+func TestClickHouseCreateTable(t *testing.T) {
+	statement, err := buildCreateTable("analytics", "events", []database.ColumnDefinition{
+		{Name: "id", Type: "UInt64", PrimaryKey: true},
+		{Name: "kind", Type: "LowCardinality(String)", Default: "'click'"},
+		{Name: "note", Type: "String", Nullable: true},
+	})
+	if err != nil {
+		t.Fatalf("buildCreateTable() error = %v", err)
+	}
+	want := "CREATE TABLE `analytics`.`events` (`id` UInt64, " +
+		"`kind` LowCardinality(String) DEFAULT 'click', `note` Nullable(String)) " +
+		"ENGINE = MergeTree ORDER BY (`id`)"
+	if statement != want {
+		t.Fatalf("statement = %q, want %q", statement, want)
+	}
+
+	statement, err = buildCreateTable("", "log", []database.ColumnDefinition{
+		{Name: "line", Type: "String"},
+	})
+	if err != nil || !strings.HasSuffix(statement, "ORDER BY tuple()") {
+		t.Fatalf("buildCreateTable(no key) = %q, %v", statement, err)
+	}
+	if _, err := buildCreateTable("", "t", []database.ColumnDefinition{
+		{Name: "id", Type: "UInt64", PrimaryKey: true, Nullable: true},
+	}); err == nil {
+		t.Fatal("buildCreateTable() accepted a nullable key column")
+	}
+	if _, err := buildCreateTable("", "t", []database.ColumnDefinition{
+		{Name: "email", Type: "String", Unique: true},
+	}); err == nil {
+		t.Fatal("buildCreateTable() accepted a UNIQUE column")
+	}
+}
+
+func TestClickHouseTypeAndKeyParsing(t *testing.T) {
+	for dataType, want := range map[string]struct {
+		inner    string
+		nullable bool
+	}{
+		"String":                           {"String", false},
+		"Nullable(DateTime64(3))":          {"DateTime64(3)", true},
+		"LowCardinality(Nullable(String))": {"String", true},
+	} {
+		inner, nullable := unwrapType(dataType)
+		if inner != want.inner || nullable != want.nullable {
+			t.Errorf("unwrapType(%q) = %q, %v", dataType, inner, nullable)
+		}
+	}
+	got := splitKeyExpression("id, toDate(created_at), cityHash64(`a,b`, 'x,y')")
+	want := []string{"id", "toDate(created_at)", "cityHash64(`a,b`, 'x,y')"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("splitKeyExpression() = %#v", got)
+	}
+	if got := splitKeyExpression(""); len(got) != 0 {
+		t.Fatalf("splitKeyExpression(empty) = %#v", got)
+	}
+}
+
+func TestClickHouseSQLLiteral(t *testing.T) {
+	instant := time.Date(2026, 3, 4, 5, 6, 7, 800, time.UTC)
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "NULL"},
+		{int64(42), "42"},
+		{true, "true"},
+		{"it's", `'it\'s'`},
+		{[]string{"a", "b"}, "['a', 'b']"},
+		{map[string]int{"b": 2, "a": 1}, "map('a', 1, 'b', 2)"},
+		{instant, "parseDateTime64BestEffort('2026-03-04T05:06:07.0000008Z', 9)"},
+	} {
+		got, err := clickHouseSQLLiteral(test.value, database.Structure{})
+		if err != nil {
+			t.Fatalf("clickHouseSQLLiteral(%#v) error = %v", test.value, err)
+		}
+		if got != test.want {
+			t.Errorf("clickHouseSQLLiteral(%#v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestClickHouseExplainPlan(t *testing.T) {
+	raw := `[
+  {
+    "Plan": {
+      "Node Type": "Expression",
+      "Description": "(Project names + Projection)",
+      "Plans": [
+        {
+          "Node Type": "ReadFromMergeTree",
+          "Description": "analytics.events",
+          "Indexes": [
+            {
+              "Type": "PrimaryKey",
+              "Keys": ["id"],
+              "Condition": "(id in [2, +Inf))",
+              "Initial Parts": 4,
+              "Selected Parts": 1,
+              "Initial Granules": 12,
+              "Selected Granules": 2
+            }
+          ]
+        }
+      ]
+    }
+  }
+]`
+	plan, err := parseExplainPlan(raw)
+	if err != nil {
+		t.Fatalf("parseExplainPlan() error = %v", err)
+	}
+	if plan.Engine != "ClickHouse" || len(plan.Roots) != 1 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	root := plan.Roots[0]
+	if root.NodeType != "Expression" || len(root.Children) != 1 {
+		t.Fatalf("root = %+v", root)
+	}
+	read := root.Children[0]
+	if read.Relation != "analytics.events" || read.ParentID != root.ID {
+		t.Fatalf("read = %+v", read)
+	}
+	index := read.Details["Index PrimaryKey"]
+	if !strings.Contains(index, "Selected Granules: 2") || !strings.Contains(index, "Keys: id") {
+		t.Fatalf("index detail = %q", index)
+	}
+	if _, err := parseExplainPlan(`[]`); err == nil {
+		t.Fatal("parseExplainPlan() accepted an empty plan")
+	}
+}
+
+func TestClickHouseLiveConformance(t *testing.T) {
+	host := os.Getenv("ROLLINGTHUNDER_CLICKHOUSE_TEST_HOST")
+	if host == "" {
+		t.Skip("set ROLLINGTHUNDER_CLICKHOUSE_TEST_HOST to run live ClickHouse conformance")
+	}
+	driver := NewClickHouse(context.Background(), Config{
+		Host:     host,
+		Port:     os.Getenv("ROLLINGTHUNDER_CLICKHOUSE_TEST_PORT"),
+		User:     os.Getenv("ROLLINGTHUNDER_CLICKHOUSE_TEST_USER"),
+		Password: os.Getenv("ROLLINGTHUNDER_CLICKHOUSE_TEST_PASSWORD"),
+		Db:       os.Getenv("ROLLINGTHUNDER_CLICKHOUSE_TEST_DATABASE"),
+	})
+	ctx := context.Background()
+	if err := driver.Connect(ctx); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := driver.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+	drivertest.RunCapabilityContract(t, driver, "clickhouse")
+
+	databaseName := driver.defaultDatabase("")
+	table := database.Table{
+		Schema: databaseName,
+		Name:   "rollingthunder_contract_" + strings.ReplaceAll(t.Name(), "/", "_"),
+	}
+	_ = driver.DropTable(table)
+	if err := driver.CreateTable(table, []database.ColumnDefinition{
+		{Name: "id", Type: "UInt64", PrimaryKey: true},
+		{Name: "name", Type: "String"},
+		{Name: "note", Type: "String", Nullable: true},
+	}); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := driver.DropTable(table); err != nil {
+			t.Errorf("DropTable() error = %v", err)
+		}
+	})
+
+	for id, name := range map[uint64]string{1: "alpha", 2: "beta", 3: "gamma"} {
+		if err := driver.InsertRow(table, map[string]interface{}{
+			"id": id, "name": name,
+		}); err != nil {
+			t.Fatalf("InsertRow(%d) error = %v", id, err)
+		}
+	}
+	collections, err := driver.GetCollections(databaseName)
+	if err != nil || !slices.Contains(collections, table.Name) {
+		t.Fatalf("GetCollections() = %v, %v", collections, err)
+	}
+	structures, err := driver.GetCollectionStructures(table)
+	if err != nil || len(structures) != 3 || !structures[0].IsPrimary || !structures[2].Nullable {
+		t.Fatalf("GetCollectionStructures() = %+v, %v", structures, err)
+	}
+	count, err := driver.CountCollectionData(table)
+	if err != nil || count != 3 {
+		t.Fatalf("CountCollectionData() = %d, %v", count, err)
+	}
+
+	if err := driver.UpdateRow(table, map[string]interface{}{
+		"id": uint64(2), "name": "bravo",
+	}, "id"); err != nil {
+		t.Fatalf("UpdateRow() error = %v", err)
+	}
+	if err := driver.DeleteRow(table, "id", uint64(3)); err != nil {
+		t.Fatalf("DeleteRow() error = %v", err)
+	}
+	if err := driver.DeleteRow(table, "id", uint64(99)); err == nil {
+		t.Fatal("DeleteRow() accepted a key that matches no rows")
+	}
+	_, rows, err := driver.GetCollectionData(database.Table{
+		Schema: table.Schema,
+		Name:   table.Name,
+		Sorts:  []database.Sort{{Column: "id", Direction: database.SortAscending}},
+		Limit:  10,
+	})
+	if err != nil || len(rows) != 2 || rows[1]["name"] != "bravo" {
+		t.Fatalf("GetCollectionData() = %v, %v", rows, err)
+	}
+
+	// DDL must run exactly once even though the native protocol answers it
+	// without a result block.
+	result, err := driver.ExecuteQuery(ctx,
+		"ALTER TABLE "+quoteQualified(table.Schema, table.Name)+
+			" ADD COLUMN extra UInt8 DEFAULT 0",
+		database.QueryOptions{},
+	)
+	if err != nil || len(result.ResultSets) != 1 {
+		t.Fatalf("ExecuteQuery(alter) = %+v, %v", result, err)
+	}
+
+	var output bytes.Buffer
+	stats, err := driver.ExportTable(ctx, database.TableExportRequest{
+		Table: database.Table{Schema: table.Schema, Name: table.Name},
+		Scope: database.ExportScopeAll,
+		Options: database.ExportOptions{
+			Format: database.ExportFormatCSV,
+			CSV:    database.CSVOptions{Delimiter: ",", IncludeHeader: true},
+		},
+	}, &output)
+	if err != nil || stats.Rows != 2 || !strings.Contains(output.String(), "bravo") {
+		t.Fatalf("ExportTable() = %+v, %q, %v", stats, output.String(), err)
+	}
+
+	plan, err := driver.ExplainQuery(ctx,
+		"SELECT name FROM "+quoteQualified(table.Schema, table.Name)+" WHERE id > 1",
+	)
+	if err != nil || len(plan.Roots) == 0 {
+		t.Fatalf("ExplainQuery() = %+v, %v", plan, err)
+	}
+	activity, err := driver.GetDatabaseActivity(ctx)
+	if err != nil || !activity.Supported || activity.CurrentSessionID == "" {
+		t.Fatalf("GetDatabaseActivity() = %+v, %v", activity, err)
+	}
+	if err := driver.CancelDatabaseSession(ctx, activity.CurrentSessionID, false); err == nil {
+		t.Fatal("CancelDatabaseSession() cancelled the application's own query")
+	}
+	objects, err := driver.ListObjects(ctx, database.ObjectFilter{
+		Schema: databaseName,
+		Search: table.Name,
+	})
+	if err != nil || len(objects) != 1 || objects[0].Reference.Kind != database.ObjectKindTable {
+		t.Fatalf("ListObjects() = %+v, %v", objects, err)
+	}
+}
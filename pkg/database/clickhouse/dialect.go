@@ -0,0 +1,103 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+var clickHouseDialect = database.Dialect{
+	Name:                 "clickhouse",
+	IdentifierOpen:       "`",
+	IdentifierClose:      "`",
+	PlaceholderStyle:     database.PlaceholderQuestion,
+	PaginationStyle:      database.PaginationLimitOffset,
+	SupportsNullOrdering: true,
+}
+
+// Capabilities advertises ClickHouse databases through the schema slot, as
+// the MySQL driver does. There are no transactions or foreign keys, and row
+// edits are ALTER TABLE mutations that rewrite whole data parts.
+func (c *ClickHouse) Capabilities() database.Capabilities {
+	return database.Capabilities{
+		Engine:             database.DriverClickHouse,
+		DisplayName:        "ClickHouse",
+		Dialect:            clickHouseDialect,
+		Schemas:            false,
+		Databases:          true,
+		Tables:             true,
+		Views:              true,
+		MaterializedViews:  true,
+		Constraints:        false,
+		ObjectDefinitions:  true,
+		ExplainPlans:       true,
+		Transactions:       false,
+		TransactionalDDL:   false,
+		AtomicTableChanges: false,
+		RowMutations:       true,
+		SQLInsertExport:    true,
+		GeneratedColumns:   true,
+		Upsert:             false,
+		ActivityMonitor:    true,
+		SSHConnections:     true,
+		QueryCursors:       true,
+		FilterGroups:       true,
+		RegexFilters:       true,
+	}
+}
+
+// ClickHouse accepts backslash escapes inside both quoted identifiers and
+// string literals, so backslashes are escaped before the quote character.
+func quoteIdentifier(identifier string) string {
+	escaped := strings.ReplaceAll(identifier, `\`, `\\`)
+	return "`" + strings.ReplaceAll(escaped, "`", "\\`") + "`"
+}
+
+func quoteLiteral(value string) string {
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(escaped, "'", `\'`) + "'"
+}
+
+func quoteQualified(databaseName, name string) string {
+	if strings.TrimSpace(databaseName) == "" {
+		return quoteIdentifier(name)
+	}
+	return quoteIdentifier(databaseName) + "." + quoteIdentifier(name)
+}
+
+func (c *ClickHouse) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier)
+}
+
+func (c *ClickHouse) Placeholder(int) string {
+	return "?"
+}
+
+func (c *ClickHouse) PaginationClause(limit, offset int) (string, error) {
+	if limit < 0 {
+		return "", fmt.Errorf("pagination limit cannot be negative")
+	}
+	if offset < 0 {
+		return "", fmt.Errorf("pagination offset cannot be negative")
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset), nil
+}
+
+func (c *ClickHouse) adapterDialect() sqladapter.Dialect {
+	return sqladapter.Dialect{
+		QuoteIdentifier:      quoteIdentifier,
+		QuoteQualified:       quoteQualified,
+		Placeholder:          c.Placeholder,
+		Pagination:           c.PaginationClause,
+		SupportsNullOrdering: true,
+		TextExpression: func(identifier string) string {
+			return "toString(" + identifier + ")"
+		},
+		RegexExpression: func(text, pattern string) string {
+			return "match(" + text + ", " + pattern + ")"
+		},
+		InsertExport: clickHouseInsertExportDialect(),
+	}
+}
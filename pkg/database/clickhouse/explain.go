@@ -0,0 +1,153 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+type clickHousePlanNode map[string]json.RawMessage
+
+// ExplainQuery reads EXPLAIN json = 1. ClickHouse reports no row
+// estimates in the plan; indexes = 1 adds the parts and granules each
+// index selects, which is the closest measure of how much data a read will
+// touch.
+func (c *ClickHouse) ExplainQuery(
+	ctx context.Context,
+	query string,
+) (database.ExplainPlan, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.ExplainPlan{}, err
+	}
+	rows, err := c.conn.QueryContext(ctx, "EXPLAIN json = 1, indexes = 1 "+query)
+	if err != nil {
+		return database.ExplainPlan{}, err
+	}
+	defer rows.Close()
+	lines := make([]string, 0, 1)
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return database.ExplainPlan{}, err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return database.ExplainPlan{}, err
+	}
+	return parseExplainPlan(strings.Join(lines, "\n"))
+}
+
+func parseExplainPlan(raw string) (database.ExplainPlan, error) {
+	var document []struct {
+		Plan clickHousePlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &document); err != nil {
+		return database.ExplainPlan{}, fmt.Errorf("read ClickHouse explain plan: %w", err)
+	}
+	roots := make([]database.ExplainPlanNode, 0, len(document))
+	counter := 0
+	for _, entry := range document {
+		if entry.Plan == nil {
+			continue
+		}
+		roots = append(roots, buildPlanNode(entry.Plan, "", &counter))
+	}
+	if len(roots) == 0 {
+		return database.ExplainPlan{}, fmt.Errorf("ClickHouse returned an empty explain plan")
+	}
+	return database.ExplainPlan{
+		Engine:  "ClickHouse",
+		Summary: roots[0].Summary,
+		Roots:   roots,
+		Raw:     raw,
+	}, nil
+}
+
+func buildPlanNode(
+	source clickHousePlanNode,
+	parentID string,
+	counter *int,
+) database.ExplainPlanNode {
+	*counter++
+	node := database.ExplainPlanNode{
+		ID:       fmt.Sprintf("clickhouse-%d", *counter),
+		ParentID: parentID,
+		Details:  make(map[string]string, len(source)),
+	}
+	var children []clickHousePlanNode
+	for key, value := range source {
+		switch key {
+		case "Node Type":
+			_ = json.Unmarshal(value, &node.NodeType)
+		case "Plans":
+			_ = json.Unmarshal(value, &children)
+		case "Indexes":
+			var indexes []map[string]json.RawMessage
+			if err := json.Unmarshal(value, &indexes); err == nil {
+				for _, index := range indexes {
+					var kind string
+					_ = json.Unmarshal(index["Type"], &kind)
+					delete(index, "Type")
+					node.Details["Index "+kind] = planDetail(index)
+				}
+			}
+		default:
+			node.Details[key] = planValue(value)
+		}
+	}
+	description := node.Details["Description"]
+	if strings.HasPrefix(node.NodeType, "ReadFrom") {
+		node.Relation = description
+	}
+	node.Summary = node.NodeType
+	if description != "" {
+		node.Summary += " (" + description + ")"
+	}
+	node.Children = make([]database.ExplainPlanNode, 0, len(children))
+	for _, child := range children {
+		node.Children = append(node.Children, buildPlanNode(child, node.ID, counter))
+	}
+	return node
+}
+
+// planValue flattens a JSON value into display text. Strings lose their
+// quotes, lists are joined, and objects become "key: value" pairs.
+func planValue(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(value, &list); err == nil {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			parts = append(parts, planValue(item))
+		}
+		return strings.Join(parts, ", ")
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err == nil {
+		return planDetail(object)
+	}
+	return string(value)
+}
+
+func planDetail(object map[string]json.RawMessage) string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+": "+planValue(object[key]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var _ database.ExplainPlanDriver = (*ClickHouse)(nil)
@@ -0,0 +1,99 @@
+package clickhouse
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+func clickHouseSQLLiteral(
+	value interface{},
+	column database.Structure,
+) (string, error) {
+	if value == nil {
+		return "NULL", nil
+	}
+	if number, ok, err := sqladapter.SQLNumericLiteral(value); ok {
+		return number, err
+	}
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return "true", nil
+		}
+		return "false", nil
+	case string:
+		return quoteLiteral(typed), nil
+	case []byte:
+		return quoteLiteral(string(typed)), nil
+	case time.Time:
+		// The best-effort parser keeps the offset, so the value lands on
+		// the same instant whatever the server or column time zone is.
+		return "parseDateTime64BestEffort(" +
+			quoteLiteral(typed.Format(time.RFC3339Nano)) + ", 9)", nil
+	case *big.Int:
+		return typed.String(), nil
+	case fmt.Stringer:
+		return quoteLiteral(typed.String()), nil
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Pointer:
+		if reflected.IsNil() {
+			return "NULL", nil
+		}
+		return clickHouseSQLLiteral(reflected.Elem().Interface(), column)
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, reflected.Len())
+		for index := range reflected.Len() {
+			item, err := clickHouseSQLLiteral(reflected.Index(index).Interface(), column)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		entries := make([]string, 0, reflected.Len())
+		for _, key := range reflected.MapKeys() {
+			keyLiteral, err := clickHouseSQLLiteral(key.Interface(), column)
+			if err != nil {
+				return "", err
+			}
+			valueLiteral, err := clickHouseSQLLiteral(
+				reflected.MapIndex(key).Interface(),
+				column,
+			)
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, keyLiteral+", "+valueLiteral)
+		}
+		sort.Strings(entries)
+		return "map(" + strings.Join(entries, ", ") + ")", nil
+	}
+	return "", fmt.Errorf(
+		"unsupported ClickHouse SQL literal type %T for %s",
+		value,
+		column.Name,
+	)
+}
+
+// ClickHouse has no transactions, so exported INSERT batches are not
+// wrapped in BEGIN and COMMIT.
+func clickHouseInsertExportDialect() *sqladapter.InsertExportDialect {
+	return &sqladapter.InsertExportDialect{
+		EngineLabel:      "ClickHouse",
+		QuoteIdentifier:  quoteIdentifier,
+		QuoteQualified:   quoteQualified,
+		Literal:          clickHouseSQLLiteral,
+		MultiRowValues:   true,
+		MaximumBatchSize: 1000,
+	}
+}
@@ -0,0 +1,237 @@
+package clickhouse
+
+import (
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// GetSchemas lists databases. ClickHouse has no schema level below the
+// database, so databases fill the schema slot of the shared browser.
+func (c *ClickHouse) GetSchemas() ([]string, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	rows, err := c.conn.Query(`
+		SELECT name
+		FROM system.databases
+		WHERE name NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')
+		ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	databases := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		databases = append(databases, name)
+	}
+	return databases, rows.Err()
+}
+
+// viewEngines are the system.tables engines that hold no browsable rows of
+// their own or are listed separately as views.
+const viewEngines = `('View', 'MaterializedView', 'LiveView', 'WindowView', 'Dictionary')`
+
+func (c *ClickHouse) GetCollections(schema ...string) ([]string, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	databaseName := ""
+	if len(schema) > 0 {
+		databaseName = schema[0]
+	}
+	rows, err := c.conn.Query(`
+		SELECT name
+		FROM system.tables
+		WHERE database = ? AND NOT is_temporary
+			AND engine NOT IN `+viewEngines+`
+		ORDER BY name`,
+		c.defaultDatabase(databaseName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// unwrapType removes the LowCardinality and Nullable wrappers from a column
+// type and reports whether the column accepts NULL.
+func unwrapType(dataType string) (string, bool) {
+	inner := strings.TrimSpace(dataType)
+	if strings.HasPrefix(inner, "LowCardinality(") && strings.HasSuffix(inner, ")") {
+		inner = inner[len("LowCardinality(") : len(inner)-1]
+	}
+	if strings.HasPrefix(inner, "Nullable(") && strings.HasSuffix(inner, ")") {
+		return inner[len("Nullable(") : len(inner)-1], true
+	}
+	return inner, false
+}
+
+// GetCollectionStructures reads system.columns. Primary-key columns are
+// those of the sparse primary index, which defaults to the sorting key;
+// ClickHouse does not enforce their uniqueness.
+func (c *ClickHouse) GetCollectionStructures(
+	table database.Table,
+) (database.Structures, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	rows, err := c.conn.Query(`
+		SELECT name, type, default_kind, default_expression, comment,
+			is_in_primary_key
+		FROM system.columns
+		WHERE database = ? AND table = ?
+		ORDER BY position`,
+		c.defaultDatabase(table.Schema),
+		table.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	structures := make(database.Structures, 0)
+	for rows.Next() {
+		var (
+			name              string
+			dataType          string
+			defaultKind       string
+			defaultExpression string
+			comment           string
+			inPrimaryKey      uint8
+		)
+		if err := rows.Scan(
+			&name,
+			&dataType,
+			&defaultKind,
+			&defaultExpression,
+			&comment,
+			&inPrimaryKey,
+		); err != nil {
+			return nil, err
+		}
+		_, nullable := unwrapType(dataType)
+		structure := database.Structure{
+			Name:       name,
+			DataType:   dataType,
+			NativeType: dataType,
+			Nullable:   nullable,
+			IsPrimary:  inPrimaryKey == 1,
+		}
+		if structure.IsPrimary {
+			structure.IsPrimaryLabel = "PRI"
+		}
+		switch defaultKind {
+		case "DEFAULT":
+			expression := defaultExpression
+			structure.Default = &expression
+		case "MATERIALIZED", "ALIAS":
+			structure.IsGenerated = true
+			structure.Generation = defaultKind + " " + defaultExpression
+		}
+		if comment != "" {
+			text := comment
+			structure.Comment = &text
+		}
+		structures = append(structures, structure)
+	}
+	return structures, rows.Err()
+}
+
+// splitKeyExpression splits a key expression such as
+// "id, toDate(created_at)" at top-level commas.
+func splitKeyExpression(expression string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	quoted := rune(0)
+	start := 0
+	for index, character := range expression {
+		switch {
+		case quoted != 0:
+			if character == quoted {
+				quoted = 0
+			}
+		case character == '\'' || character == '`' || character == '"':
+			quoted = character
+		case character == '(':
+			depth++
+		case character == ')':
+			depth--
+		case character == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(expression[start:index]))
+			start = index + 1
+		}
+	}
+	if last := strings.TrimSpace(expression[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// GetIndices reports the sparse primary index and any data-skipping
+// indices. Neither enforces uniqueness.
+func (c *ClickHouse) GetIndices(table database.Table) (database.Indices, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	databaseName := c.defaultDatabase(table.Schema)
+	indices := make(database.Indices, 0)
+	var primaryKey string
+	err := c.conn.QueryRow(`
+		SELECT primary_key
+		FROM system.tables
+		WHERE database = ? AND name = ?`,
+		databaseName,
+		table.Name,
+	).Scan(&primaryKey)
+	if err != nil {
+		return nil, err
+	}
+	if columns := splitKeyExpression(primaryKey); len(columns) > 0 {
+		indices = append(indices, database.Index{
+			Name:      "PRIMARY",
+			Columns:   columns,
+			IsPrimary: true,
+			Algorithm: "sparse",
+		})
+	}
+	rows, err := c.conn.Query(`
+		SELECT name, type, expr
+		FROM system.data_skipping_indices
+		WHERE database = ? AND table = ?
+		ORDER BY name`,
+		databaseName,
+		table.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, kind, expression string
+		if err := rows.Scan(&name, &kind, &expression); err != nil {
+			return nil, err
+		}
+		indices = append(indices, database.Index{
+			Name:      name,
+			Columns:   splitKeyExpression(expression),
+			Algorithm: kind,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
@@ -0,0 +1,185 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rollingthunder/pkg/database"
+
+	clickhousedriver "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// ClickHouse has no row-level UPDATE or DELETE that the grid can rely on.
+// Row edits become ALTER TABLE ... UPDATE and ALTER TABLE ... DELETE
+// mutations, which rewrite every data part that holds a matching row.
+// mutations_sync = 2 makes each statement wait until all replicas have
+// applied the mutation, so the grid reloads the edited data and a failed
+// mutation is reported instead of being left running in the background.
+func (c *ClickHouse) mutationContext() context.Context {
+	return clickhousedriver.Context(
+		c.ctx,
+		clickhousedriver.WithSettings(clickhousedriver.Settings{
+			"mutations_sync": 2,
+		}),
+	)
+}
+
+func columnByName(
+	structures database.Structures,
+	name string,
+) (database.Structure, bool) {
+	for _, structure := range structures {
+		if strings.EqualFold(structure.Name, strings.TrimSpace(name)) {
+			return structure, true
+		}
+	}
+	return database.Structure{}, false
+}
+
+// requireSingleRow guards mutations, which otherwise apply to every
+// matching row without reporting how many there were.
+func (c *ClickHouse) requireSingleRow(
+	table database.Table,
+	keyColumn string,
+	keyValue interface{},
+	action string,
+) error {
+	var count uint64
+	if err := c.conn.QueryRowContext(
+		c.ctx,
+		"SELECT count() FROM "+quoteQualified(table.Schema, table.Name)+
+			" WHERE "+quoteIdentifier(keyColumn)+" = ?",
+		keyValue,
+	).Scan(&count); err != nil {
+		return err
+	}
+	if count != 1 {
+		return fmt.Errorf(
+			"row %s would affect %d rows instead of exactly one",
+			action,
+			count,
+		)
+	}
+	return nil
+}
+
+func buildUpdateMutation(
+	table database.Table,
+	data map[string]interface{},
+	primaryKey string,
+	structures database.Structures,
+) (string, []interface{}, error) {
+	key, ok := columnByName(structures, primaryKey)
+	if !ok {
+		return "", nil, fmt.Errorf("unknown primary key column %q", primaryKey)
+	}
+	var (
+		keyValue interface{}
+		found    bool
+	)
+	columns := make([]string, 0, len(data))
+	for column, value := range data {
+		if strings.EqualFold(column, key.Name) {
+			keyValue = value
+			found = true
+			continue
+		}
+		if column == "_isNew" || strings.HasPrefix(column, "temp_") ||
+			strings.HasPrefix(column, "__rolling_thunder_") {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	if !found {
+		return "", nil, fmt.Errorf("primary key %q is missing", primaryKey)
+	}
+	sort.Strings(columns)
+	assignments := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns)+1)
+	for _, column := range columns {
+		structure, exists := columnByName(structures, column)
+		if !exists {
+			return "", nil, fmt.Errorf("unknown update column %q", column)
+		}
+		// Key columns order the data inside each part and cannot be
+		// rewritten by a mutation; MATERIALIZED and ALIAS columns are
+		// computed by the server.
+		if structure.IsPrimary || structure.IsGenerated {
+			continue
+		}
+		assignments = append(assignments, quoteIdentifier(structure.Name)+" = ?")
+		args = append(args, data[column])
+	}
+	if len(assignments) == 0 {
+		return "", nil, errors.New("no mutable columns to update")
+	}
+	args = append(args, keyValue)
+	return "ALTER TABLE " + quoteQualified(table.Schema, table.Name) +
+		" UPDATE " + strings.Join(assignments, ", ") +
+		" WHERE " + quoteIdentifier(key.Name) + " = ?", args, nil
+}
+
+func (c *ClickHouse) UpdateRow(
+	table database.Table,
+	data map[string]interface{},
+	primaryKey string,
+) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	primaryKey = strings.TrimSpace(primaryKey)
+	if primaryKey == "" {
+		return errors.New("a primary key is required for row updates")
+	}
+	table.Schema = c.defaultDatabase(table.Schema)
+	structures, err := c.GetCollectionStructures(table)
+	if err != nil {
+		return err
+	}
+	statement, args, err := buildUpdateMutation(table, data, primaryKey, structures)
+	if err != nil {
+		return err
+	}
+	key, _ := columnByName(structures, primaryKey)
+	if err := c.requireSingleRow(table, key.Name, args[len(args)-1], "update"); err != nil {
+		return err
+	}
+	_, err = c.conn.ExecContext(c.mutationContext(), statement, args...)
+	return err
+}
+
+func (c *ClickHouse) DeleteRow(
+	table database.Table,
+	primaryKey string,
+	primaryValue interface{},
+) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	primaryKey = strings.TrimSpace(primaryKey)
+	if primaryKey == "" {
+		return errors.New("a primary key is required for row deletion")
+	}
+	table.Schema = c.defaultDatabase(table.Schema)
+	structures, err := c.GetCollectionStructures(table)
+	if err != nil {
+		return err
+	}
+	key, ok := columnByName(structures, primaryKey)
+	if !ok {
+		return fmt.Errorf("unknown primary key column %q", primaryKey)
+	}
+	if err := c.requireSingleRow(table, key.Name, primaryValue, "delete"); err != nil {
+		return err
+	}
+	_, err = c.conn.ExecContext(
+		c.mutationContext(),
+		"ALTER TABLE "+quoteQualified(table.Schema, table.Name)+
+			" DELETE WHERE "+quoteIdentifier(key.Name)+" = ?",
+		primaryValue,
+	)
+	return err
+}
@@ -0,0 +1,323 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+func objectID(reference database.ObjectReference) string {
+	parts := []string{
+		string(reference.Kind),
+		reference.Schema,
+		reference.Name,
+		reference.ParentName,
+	}
+	for index, part := range parts {
+		parts[index] = base64.RawURLEncoding.EncodeToString([]byte(part))
+	}
+	return "clickhouse:" + strings.Join(parts, ".")
+}
+
+type catalogObject struct {
+	object     database.DatabaseObject
+	definition string
+}
+
+func objectKindForEngine(engine string) (database.ObjectKind, bool) {
+	switch engine {
+	case "View":
+		return database.ObjectKindView, true
+	case "MaterializedView":
+		return database.ObjectKindMaterializedView, true
+	case "Dictionary", "LiveView", "WindowView":
+		return database.ObjectKindUnknown, false
+	default:
+		return database.ObjectKindTable, true
+	}
+}
+
+// catalogTablesQuery lists tables and views with the storage details that
+// matter for a column store: engine, keys, and on-disk size.
+const catalogTablesQuery = `
+	SELECT database, name, engine, create_table_query, comment,
+		sorting_key, partition_key, total_rows, total_bytes
+	FROM system.tables
+	WHERE database NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')
+		AND NOT is_temporary
+	ORDER BY database, name`
+
+const catalogIndicesQuery = `
+	SELECT database, table, name, type, expr, granularity
+	FROM system.data_skipping_indices
+	WHERE database NOT IN ('system', 'INFORMATION_SCHEMA', 'information_schema')
+	ORDER BY database, table, name`
+
+type objectMatcher struct {
+	schema  string
+	allowed map[database.ObjectKind]bool
+	search  string
+}
+
+func newObjectMatcher(filter database.ObjectFilter) objectMatcher {
+	allowed := make(map[database.ObjectKind]bool, len(filter.Kinds))
+	for _, kind := range filter.Kinds {
+		allowed[kind] = true
+	}
+	return objectMatcher{
+		schema:  filter.Schema,
+		allowed: allowed,
+		search:  strings.ToLower(strings.TrimSpace(filter.Search)),
+	}
+}
+
+func (matcher objectMatcher) matches(
+	reference database.ObjectReference,
+	text string,
+) bool {
+	if matcher.schema != "" && reference.Schema != matcher.schema {
+		return false
+	}
+	if len(matcher.allowed) > 0 && !matcher.allowed[reference.Kind] {
+		return false
+	}
+	return matcher.search == "" ||
+		strings.Contains(strings.ToLower(text), matcher.search)
+}
+
+func (c *ClickHouse) catalogObjects(
+	ctx context.Context,
+	filter database.ObjectFilter,
+) ([]catalogObject, error) {
+	matcher := newObjectMatcher(filter)
+	objects := make([]catalogObject, 0)
+	rows, err := c.conn.QueryContext(ctx, catalogTablesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			databaseName string
+			name         string
+			engine       string
+			definition   string
+			comment      string
+			sortingKey   string
+			partitionKey string
+			totalRows    sql.NullInt64
+			totalBytes   sql.NullInt64
+		)
+		if err := rows.Scan(
+			&databaseName,
+			&name,
+			&engine,
+			&definition,
+			&comment,
+			&sortingKey,
+			&partitionKey,
+			&totalRows,
+			&totalBytes,
+		); err != nil {
+			return nil, err
+		}
+		kind, listed := objectKindForEngine(engine)
+		if !listed {
+			continue
+		}
+		reference := database.ObjectReference{
+			Kind:   kind,
+			Schema: databaseName,
+			Name:   name,
+		}
+		if !matcher.matches(reference, name+" "+comment) {
+			continue
+		}
+		reference.ID = objectID(reference)
+		properties := []database.ObjectProperty{
+			{Name: "Database", Value: databaseName, Category: "identity"},
+			{Name: "Engine", Value: engine, Category: "storage"},
+		}
+		if sortingKey != "" {
+			properties = append(properties, database.ObjectProperty{
+				Name: "Sorting key", Value: sortingKey, Category: "storage",
+			})
+		}
+		if partitionKey != "" {
+			properties = append(properties, database.ObjectProperty{
+				Name: "Partition key", Value: partitionKey, Category: "storage",
+			})
+		}
+		if totalRows.Valid {
+			properties = append(properties, database.ObjectProperty{
+				Name:     "Rows",
+				Value:    strconv.FormatInt(totalRows.Int64, 10),
+				Category: "statistics",
+			})
+		}
+		if totalBytes.Valid {
+			properties = append(properties, database.ObjectProperty{
+				Name:     "Compressed bytes",
+				Value:    strconv.FormatInt(totalBytes.Int64, 10),
+				Category: "statistics",
+			})
+		}
+		objects = append(objects, catalogObject{
+			object: database.DatabaseObject{
+				Reference:   reference,
+				DisplayName: name,
+				Description: comment,
+				CanOpenData: true,
+				Properties:  properties,
+			},
+			definition: strings.TrimSpace(definition),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	indexRows, err := c.conn.QueryContext(ctx, catalogIndicesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer indexRows.Close()
+	for indexRows.Next() {
+		var (
+			databaseName string
+			table        string
+			name         string
+			kind         string
+			expression   string
+			granularity  uint64
+		)
+		if err := indexRows.Scan(
+			&databaseName,
+			&table,
+			&name,
+			&kind,
+			&expression,
+			&granularity,
+		); err != nil {
+			return nil, err
+		}
+		reference := database.ObjectReference{
+			Kind:         database.ObjectKindIndex,
+			Schema:       databaseName,
+			Name:         name,
+			ParentSchema: databaseName,
+			ParentName:   table,
+		}
+		if !matcher.matches(reference, name+" "+table) {
+			continue
+		}
+		reference.ID = objectID(reference)
+		objects = append(objects, catalogObject{
+			object: database.DatabaseObject{
+				Reference:   reference,
+				DisplayName: name,
+				Properties: []database.ObjectProperty{
+					{Name: "Database", Value: databaseName, Category: "identity"},
+					{Name: "Table", Value: table, Category: "identity"},
+					{Name: "Type", Value: kind, Category: "storage"},
+					{
+						Name:     "Granularity",
+						Value:    strconv.FormatUint(granularity, 10),
+						Category: "storage",
+					},
+				},
+			},
+			definition: fmt.Sprintf(
+				"ALTER TABLE %s ADD INDEX %s %s TYPE %s GRANULARITY %d;",
+				quoteQualified(databaseName, table),
+				quoteIdentifier(name),
+				expression,
+				kind,
+				granularity,
+			),
+		})
+	}
+	return objects, indexRows.Err()
+}
+
+func (c *ClickHouse) ListObjects(
+	ctx context.Context,
+	filter database.ObjectFilter,
+) ([]database.DatabaseObject, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+	entries, err := c.catalogObjects(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]database.DatabaseObject, 0, len(entries))
+	for _, entry := range entries {
+		objects = append(objects, entry.object)
+	}
+	sort.SliceStable(objects, func(left, right int) bool {
+		if objects[left].Reference.Schema != objects[right].Reference.Schema {
+			return objects[left].Reference.Schema < objects[right].Reference.Schema
+		}
+		if objects[left].Reference.Kind != objects[right].Reference.Kind {
+			return objects[left].Reference.Kind < objects[right].Reference.Kind
+		}
+		return objects[left].Reference.Name < objects[right].Reference.Name
+	})
+	return objects, nil
+}
+
+func (c *ClickHouse) GetObjectDetail(
+	ctx context.Context,
+	reference database.ObjectReference,
+) (database.ObjectDetail, error) {
+	if err := c.ensureConnected(); err != nil {
+		return database.ObjectDetail{}, err
+	}
+	filter := database.ObjectFilter{Schema: reference.Schema}
+	if reference.Kind != database.ObjectKindUnknown {
+		filter.Kinds = []database.ObjectKind{reference.Kind}
+	}
+	entries, err := c.catalogObjects(ctx, filter)
+	if err != nil {
+		return database.ObjectDetail{}, err
+	}
+	for _, entry := range entries {
+		candidate := entry.object.Reference
+		if reference.ID != "" && candidate.ID != reference.ID {
+			continue
+		}
+		if reference.ID == "" && (candidate.Name != reference.Name ||
+			(reference.ParentName != "" && candidate.ParentName != reference.ParentName)) {
+			continue
+		}
+		detail := database.ObjectDetail{
+			Object:     entry.object,
+			Definition: entry.definition,
+			Comment:    entry.object.Description,
+			Properties: entry.object.Properties,
+		}
+		if candidate.Kind != database.ObjectKindIndex {
+			detail.Columns, err = c.GetCollectionStructures(database.Table{
+				Schema: candidate.Schema,
+				Name:   candidate.Name,
+			})
+			if err != nil {
+				return database.ObjectDetail{}, err
+			}
+		}
+		return detail, nil
+	}
+	return database.ObjectDetail{}, fmt.Errorf(
+		"ClickHouse object %s was not found",
+		reference.QualifiedName(),
+	)
+}
+
+var _ database.ObjectDriver = (*ClickHouse)(nil)
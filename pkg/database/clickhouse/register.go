@@ -0,0 +1,42 @@
+package clickhouse
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverClickHouse, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewClickHouse(ctx, Config{
+		Host:          cfg.Host,
+		Port:          cfg.Port,
+		User:          cfg.User,
+		Password:      cfg.Password,
+		Db:            cfg.Db,
+		SSLMode:       cfg.SSLMode,
+		SSLRootCert:   cfg.SSLRootCert,
+		SSLCert:       cfg.SSLCert,
+		SSLKey:        cfg.SSLKey,
+		TLSServerName: cfg.TLSServerName,
+	}), nil
+}
+
+// probe reports backups as unavailable. ClickHouse BACKUP writes to a disk
+// or object store configured on the server, which the client cannot pick.
+func probe(database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*ClickHouse)(nil).Capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available: false,
+			Engine:    capabilities.Engine,
+			Message: "ClickHouse backups are written to a server-side backup disk " +
+				"or object store. Run BACKUP DATABASE from the SQL editor against a " +
+				"destination configured on the server.",
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// CreateTable creates a MergeTree table ordered by the primary-key columns.
+// ClickHouse columns are non-nullable unless wrapped in Nullable, and there
+// are no UNIQUE constraints to declare.
+func (c *ClickHouse) CreateTable(
+	table database.Table,
+	columns []database.ColumnDefinition,
+) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	statement, err := buildCreateTable(
+		c.defaultDatabase(table.Schema),
+		table.Name,
+		columns,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = c.conn.Exec(statement)
+	return err
+}
+
+func buildCreateTable(
+	databaseName string,
+	tableName string,
+	columns []database.ColumnDefinition,
+) (string, error) {
+	if strings.TrimSpace(tableName) == "" {
+		return "", fmt.Errorf("table name is required")
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("at least one column is required")
+	}
+	definitions := make([]string, 0, len(columns))
+	orderBy := make([]string, 0)
+	for _, column := range columns {
+		name := strings.TrimSpace(column.Name)
+		dataType := strings.TrimSpace(column.Type)
+		if name == "" {
+			continue
+		}
+		if dataType == "" {
+			return "", fmt.Errorf("data type is required for column %q", name)
+		}
+		if err := database.ValidateDDLFragment(
+			dataType,
+			"column data type",
+		); err != nil {
+			return "", err
+		}
+		if column.Unique {
+			return "", fmt.Errorf(
+				"ClickHouse does not enforce UNIQUE constraints; remove UNIQUE from column %q",
+				name,
+			)
+		}
+		if _, nullable := unwrapType(dataType); column.Nullable && !nullable {
+			if column.PrimaryKey {
+				return "", fmt.Errorf(
+					"primary-key column %q cannot be nullable in ClickHouse",
+					name,
+				)
+			}
+			dataType = "Nullable(" + dataType + ")"
+		}
+		definition := quoteIdentifier(name) + " " + dataType
+		if strings.TrimSpace(column.Default) != "" {
+			if err := database.ValidateDDLFragment(
+				column.Default,
+				"column default",
+			); err != nil {
+				return "", err
+			}
+			definition += " DEFAULT " + strings.TrimSpace(column.Default)
+		}
+		if column.PrimaryKey {
+			orderBy = append(orderBy, quoteIdentifier(name))
+		}
+		definitions = append(definitions, definition)
+	}
+	if len(definitions) == 0 {
+		return "", fmt.Errorf("at least one named column is required")
+	}
+	order := "tuple()"
+	if len(orderBy) > 0 {
+		order = "(" + strings.Join(orderBy, ", ") + ")"
+	}
+	return "CREATE TABLE " + quoteQualified(databaseName, tableName) +
+		" (" + strings.Join(definitions, ", ") + ") ENGINE = MergeTree ORDER BY " +
+		order, nil
+}
+
+func (c *ClickHouse) DropTable(table database.Table) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	_, err := c.conn.Exec(
+		"DROP TABLE IF EXISTS " +
+			quoteQualified(c.defaultDatabase(table.Schema), table.Name),
+	)
+	return err
+}
+
+func (c *ClickHouse) TruncateTable(table database.Table) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	_, err := c.conn.Exec(
+		"TRUNCATE TABLE " +
+			quoteQualified(c.defaultDatabase(table.Schema), table.Name),
+	)
+	return err
+}
+
+// GetTableDDL returns the statement ClickHouse keeps in system.tables,
+// which includes the engine, sorting key, and settings.
+func (c *ClickHouse) GetTableDDL(table database.Table) (string, error) {
+	if err := c.ensureConnected(); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return "", fmt.Errorf("table name is required")
+	}
+	var definition string
+	if err := c.conn.QueryRow(`
+		SELECT create_table_query
+		FROM system.tables
+		WHERE database = ? AND name = ?`,
+		c.defaultDatabase(table.Schema),
+		table.Name,
+	).Scan(&definition); err != nil {
+		return "", fmt.Errorf("read ClickHouse table %q definition: %w", table.Name, err)
+	}
+	return strings.TrimSpace(definition) + ";", nil
+}
+
+func (c *ClickHouse) GetDataTypes() []database.DataType {
+	return []database.DataType{
+		{Name: "UInt8", Category: "Numeric", Description: "Unsigned 8-bit integer"},
+		{Name: "UInt16", Category: "Numeric", Description: "Unsigned 16-bit integer"},
+		{Name: "UInt32", Category: "Numeric", Description: "Unsigned 32-bit integer"},
+		{Name: "UInt64", Category: "Numeric", Description: "Unsigned 64-bit integer"},
+		{Name: "Int8", Category: "Numeric", Description: "8-bit integer"},
+		{Name: "Int16", Category: "Numeric", Description: "16-bit integer"},
+		{Name: "Int32", Category: "Numeric", Description: "32-bit integer"},
+		{Name: "Int64", Category: "Numeric", Description: "64-bit integer"},
+		{Name: "Int128", Category: "Numeric", Description: "128-bit integer"},
+		{Name: "Float32", Category: "Numeric", Description: "Single-precision number"},
+		{Name: "Float64", Category: "Numeric", Description: "Double-precision number"},
+		{Name: "Decimal(18, 4)", Category: "Numeric", Description: "Exact fixed-point number"},
+		{Name: "Bool", Category: "Boolean", Description: "True or false"},
+		{Name: "String", Category: "Character", Description: "Arbitrary-length bytes or text"},
+		{Name: "FixedString(16)", Category: "Character", Description: "Fixed-length bytes"},
+		{Name: "LowCardinality(String)", Category: "Character", Description: "Dictionary-encoded text"},
+		{Name: "UUID", Category: "Identifier", Description: "Universally unique identifier"},
+		{Name: "Date", Category: "Date/Time", Description: "Calendar date"},
+		{Name: "Date32", Category: "Date/Time", Description: "Calendar date with extended range"},
+		{Name: "DateTime", Category: "Date/Time", Description: "Date and time to the second"},
+		{Name: "DateTime64(3)", Category: "Date/Time", Description: "Date and time with sub-second precision"},
+		{Name: "Enum8('a' = 1, 'b' = 2)", Category: "Enumerated", Description: "Enumerated text values"},
+		{Name: "Array(String)", Category: "Structured", Description: "Array of values"},
+		{Name: "Map(String, String)", Category: "Structured", Description: "Key-value map"},
+		{Name: "Tuple(String, UInt64)", Category: "Structured", Description: "Fixed tuple of values"},
+		{Name: "JSON", Category: "Structured", Description: "Semi-structured JSON object"},
+		{Name: "IPv4", Category: "Network", Description: "IPv4 address"},
+		{Name: "IPv6", Category: "Network", Description: "IPv6 address"},
+	}
+}
@@ -8,20 +8,28 @@ import (
 )
 
 const (
-	DriverPostgres  = "postgres"
-	DriverMySQL     = "mysql"
-	DriverMariaDB   = "mariadb"
-	DriverSQLite    = "sqlite"
-	DriverOracle    = "oracle"
-	DriverSQLServer = "sqlserver"
+	DriverPostgres    = "postgres"
+	DriverCockroachDB = "cockroachdb"
+	DriverYugabyteDB  = "yugabytedb"
+	DriverMySQL       = "mysql"
+	DriverMariaDB     = "mariadb"
+	DriverSQLite      = "sqlite"
+	DriverOracle      = "oracle"
+	DriverSQLServer   = "sqlserver"
+	DriverDuckDB      = "duckdb"
+	DriverClickHouse  = "clickhouse"
 
-	DefaultHost          = "127.0.0.1"
-	DefaultPostgresPort  = "5432"
-	DefaultMySQLPort     = "3306"
-	DefaultOraclePort    = "1521"
-	DefaultSQLServerPort = "1433"
-	DefaultSSHPort       = "22"
-	DefaultSSLMode       = "disable"
+	DefaultHost              = "127.0.0.1"
+	DefaultPostgresPort      = "5432"
+	DefaultCockroachDBPort   = "26257"
+	DefaultYugabyteDBPort    = "5433"
+	DefaultMySQLPort         = "3306"
+	DefaultOraclePort        = "1521"
+	DefaultSQLServerPort     = "1433"
+	DefaultClickHousePort    = "9000"
+	DefaultClickHouseTLSPort = "9440"
+	DefaultSSHPort           = "22"
+	DefaultSSLMode           = "disable"
 
 	ConnectionEnvironmentUnclassified = "unclassified"
 	ConnectionEnvironmentDevelopment  = "development"
@@ -49,7 +57,7 @@ type Config struct {
 	AccessMode  string   `json:"accessMode"`  // read-write, read-only
 	Folder      string   `json:"folder,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
-	Driver      string   `json:"driver"` // postgres, mysql, sqlite, oracle, sqlserver
+	Driver      string   `json:"driver"` // postgres, cockroachdb, yugabytedb, mysql, sqlite, oracle, sqlserver, duckdb, clickhouse
 
 	// Color is retained only to decode profiles written before environment
 	// classifications were introduced. New profiles never persist or render it.
@@ -297,15 +305,7 @@ func (config Config) ValidateSafety() error {
 		return fmt.Errorf("connection access mode is not supported")
 	}
 	driver := strings.ToLower(strings.TrimSpace(config.Driver))
-	switch driver {
-	case "",
-		DriverPostgres,
-		DriverMySQL,
-		DriverMariaDB,
-		DriverSQLite,
-		DriverOracle,
-		DriverSQLServer:
-	default:
+	if driver != "" && !IsRegisteredDriver(driver) {
 		return fmt.Errorf("database driver is not supported")
 	}
 	if err := validateConfigPort("database port", config.Port); err != nil {
@@ -8,13 +8,18 @@ import (
 	"testing"
 
 	"rollingthunder/pkg/database"
+
+	"github.com/apache/arrow-go/v18/parquet/file"
 )
 
 type LiveConfig struct {
-	Driver             database.Driver
-	Schema             string
-	IntegerType        string
-	TextType           string
+	Driver      database.Driver
+	Schema      string
+	IntegerType string
+	TextType    string
+	// JSONType, when set, is the column type used to check JSON path
+	// filters and sorts on drivers that declare them.
+	JSONType           string
 	ExercisePrivileged bool
 }
 
@@ -134,6 +139,33 @@ func RunCapabilityContract(
 			return ok
 		},
 	)
+	requireCapabilityInterface(
+		t,
+		capabilities.QueryCursors,
+		"query cursors",
+		func() bool {
+			_, ok := driver.(database.CursorDriver)
+			return ok
+		},
+	)
+	requireCapabilityInterface(
+		t,
+		capabilities.RowEstimates,
+		"row estimates",
+		func() bool {
+			_, ok := driver.(database.RowEstimateDriver)
+			return ok
+		},
+	)
+	requireCapabilityInterface(
+		t,
+		capabilities.ReturningRows,
+		"returning rows",
+		func() bool {
+			_, ok := driver.(database.ReturningDriver)
+			return ok
+		},
+	)
 }
 
 func RunLiveContract(t *testing.T, config LiveConfig) {
@@ -331,7 +363,7 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		t.Fatalf("ExecuteQuery() error = %v", err)
 	}
 	if len(directQuery.Rows) != 1 ||
-		fmt.Sprint(rowValueFold(directQuery.Rows[0], "name")) != "alpha-direct" {
+		fmt.Sprint(rowValueFold(directQuery.RowMaps()[0], "name")) != "alpha-direct" {
 		t.Fatalf("ExecuteQuery() rows = %v, want directly updated row", directQuery.Rows)
 	}
 	if err := driver.DeleteRow(table, "id", 2); err != nil {
@@ -451,6 +483,10 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		t.Fatalf("DeleteRow() after commit error = %v", err)
 	}
 
+	if returning, ok := driver.(database.ReturningDriver); ok && driver.Capabilities().ReturningRows {
+		runReturningContract(ctx, t, driver, returning, qualified(driver, schema, tableName))
+	}
+
 	limitedQuery, err := driver.ExecuteQuery(
 		ctx,
 		fmt.Sprintf(
@@ -474,6 +510,39 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		)
 	}
 
+	if driver.Capabilities().QueryCursors {
+		runCursorContract(ctx, t, driver, schema, tableName)
+	}
+
+	duplicateQuery, err := driver.ExecuteQuery(
+		ctx,
+		fmt.Sprintf(
+			"SELECT a.%[1]s, b.%[1]s FROM %[2]s a JOIN %[2]s b ON a.%[1]s = b.%[1]s WHERE a.%[1]s = 1",
+			driver.QuoteIdentifier("id"),
+			qualified(driver, schema, tableName),
+		),
+		database.QueryOptions{MaxRows: 10},
+	)
+	if err != nil {
+		t.Fatalf("ExecuteQuery() with duplicate column names error = %v", err)
+	}
+	if len(duplicateQuery.Columns) != 2 ||
+		!strings.EqualFold(duplicateQuery.Columns[0].Name, "id") ||
+		!strings.EqualFold(duplicateQuery.Columns[1].Name, "id") ||
+		len(duplicateQuery.Rows) != 1 ||
+		len(duplicateQuery.Rows[0]) != 2 {
+		t.Fatalf(
+			"duplicate column ExecuteQuery() = columns:%+v rows:%v, want two id columns",
+			duplicateQuery.Columns,
+			duplicateQuery.Rows,
+		)
+	}
+	for _, column := range duplicateQuery.Columns {
+		if column.Type == "" {
+			t.Fatalf("ExecuteQuery() column %q has no native type name", column.Name)
+		}
+	}
+
 	capabilities := driver.Capabilities()
 	if capabilities.ExplainPlans {
 		explainDriver, ok := driver.(database.ExplainPlanDriver)
@@ -831,6 +900,10 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		t.Fatalf("ExportTable() output = %q", exported.String())
 	}
 
+	if capabilities.FilterGroups {
+		runFilterTreeContract(ctx, t, driver, table)
+	}
+
 	var jsonExport bytes.Buffer
 	jsonStats, err := driver.ExportTable(ctx, database.TableExportRequest{
 		Table: database.Table{Schema: schema, Name: tableName, Limit: 100},
@@ -853,6 +926,30 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		)
 	}
 
+	var parquetExport bytes.Buffer
+	parquetStats, err := driver.ExportTable(ctx, database.TableExportRequest{
+		Table: database.Table{Schema: schema, Name: tableName, Limit: 100},
+		Scope: database.ExportScopeAll,
+		Options: database.ExportOptions{
+			Format:  database.ExportFormatParquet,
+			Parquet: database.ParquetOptions{Compression: database.ParquetCompressionZstd},
+		},
+	}, &parquetExport)
+	if err != nil {
+		t.Fatalf("ExportTable(Parquet) error = %v", err)
+	}
+	parquetReader, err := file.NewParquetReader(bytes.NewReader(parquetExport.Bytes()))
+	if err != nil {
+		t.Fatalf("read Parquet export: %v", err)
+	}
+	if parquetStats.Rows != 2 || parquetReader.NumRows() != 2 {
+		t.Fatalf(
+			"ExportTable(Parquet) rows=%d, file rows=%d",
+			parquetStats.Rows,
+			parquetReader.NumRows(),
+		)
+	}
+
 	if capabilities.SQLInsertExport {
 		var sqlExport bytes.Buffer
 		sqlStats, err := driver.ExportTable(ctx, database.TableExportRequest{
@@ -879,6 +976,16 @@ func RunLiveContract(t *testing.T, config LiveConfig) {
 		}
 	}
 
+	if config.JSONType != "" && capabilities.JSONPaths {
+		runJSONPathContract(ctx, t, config)
+	}
+	if capabilities.KeysetPagination && capabilities.FilterGroups {
+		runKeysetContract(ctx, t, config)
+	}
+	if estimator, ok := driver.(database.RowEstimateDriver); ok && capabilities.RowEstimates {
+		runRowEstimateContract(ctx, t, estimator, table)
+	}
+
 	if err := driver.DropTable(relationTable); err != nil {
 		t.Fatalf("DropTable(foreign-key fixture) error = %v", err)
 	}
@@ -999,6 +1106,51 @@ func hasPrincipal(
 	return false
 }
 
+// runCursorContract pages through the two rows left in the contract table
+// one at a time, so the cursor has to resume where the previous fetch ended.
+func runCursorContract(
+	ctx context.Context,
+	t *testing.T,
+	driver database.Driver,
+	schema string,
+	tableName string,
+) {
+	t.Helper()
+	cursor, err := driver.(database.CursorDriver).OpenQueryCursor(
+		ctx,
+		fmt.Sprintf(
+			"SELECT %s FROM %s ORDER BY %s",
+			driver.QuoteIdentifier("id"),
+			qualified(driver, schema, tableName),
+			driver.QuoteIdentifier("id"),
+		),
+		database.QueryOptions{},
+	)
+	if err != nil {
+		t.Fatalf("OpenQueryCursor() error = %v", err)
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil {
+			t.Errorf("cursor Close() error = %v", err)
+		}
+	}()
+	if columns := cursor.Columns(); len(columns) != 1 ||
+		!strings.EqualFold(columns[0].Name, "id") {
+		t.Fatalf("cursor Columns() = %+v, want id", columns)
+	}
+	first, err := cursor.Fetch(ctx, 1)
+	if err != nil || len(first.Rows) != 1 || first.Done {
+		t.Fatalf("first cursor Fetch() = %+v, %v, want one row", first, err)
+	}
+	rest, err := cursor.Fetch(ctx, 10)
+	if err != nil || len(rest.Rows) != 1 || !rest.Done {
+		t.Fatalf("second cursor Fetch() = %+v, %v, want the last row", rest, err)
+	}
+	if fmt.Sprint(first.Rows[0][0]) == fmt.Sprint(rest.Rows[0][0]) {
+		t.Fatalf("cursor returned row %v twice", first.Rows[0][0])
+	}
+}
+
 func requireCapabilityInterface(
 	t *testing.T,
 	enabled bool,
@@ -1021,3 +1173,336 @@ func qualified(
 	}
 	return driver.QuoteIdentifier(schema) + "." + driver.QuoteIdentifier(name)
 }
+
+// runFilterTreeContract expects the fixture to hold exactly the rows
+// (1, alpha-updated, 12) and (3, gamma, 30).
+func runFilterTreeContract(
+	ctx context.Context,
+	t *testing.T,
+	driver database.Driver,
+	table database.Table,
+) {
+	t.Helper()
+	filtered := table
+	filtered.Limit = 100
+	filtered.Filters = []database.Filter{{
+		Column: "name", Operator: database.FilterNotContains, Value: "zzz",
+	}}
+	filtered.Where = &database.FilterGroup{
+		Logic: database.FilterOr,
+		Filters: []database.Filter{{
+			Column: "id", Operator: database.FilterIn, Value: []interface{}{1, 2},
+		}},
+		Groups: []database.FilterGroup{{
+			Filters: []database.Filter{
+				{Column: "score", Operator: database.FilterBetween, Value: []interface{}{25, 35}},
+				{Column: "name", Operator: database.FilterEndsWith, Value: "zz"},
+			},
+		}},
+	}
+	count, err := driver.CountCollectionData(filtered)
+	if err != nil || count != 1 {
+		t.Fatalf("CountCollectionData(filter tree) = %d, %v, want 1", count, err)
+	}
+
+	var exported bytes.Buffer
+	stats, err := driver.ExportTable(ctx, database.TableExportRequest{
+		Table: filtered,
+		Scope: database.ExportScopeAll,
+		Options: database.ExportOptions{
+			Format: database.ExportFormatCSV,
+			CSV: database.CSVOptions{
+				IncludeHeader: true,
+				Encoding:      database.CSVEncodingUTF8,
+			},
+		},
+	}, &exported)
+	if err != nil {
+		t.Fatalf("ExportTable(filter tree) error = %v", err)
+	}
+	if stats.Rows != 1 ||
+		!strings.Contains(exported.String(), "alpha-updated") ||
+		strings.Contains(exported.String(), "gamma") {
+		t.Fatalf("ExportTable(filter tree) rows=%d output=%q", stats.Rows, exported.String())
+	}
+
+	filtered.Where.Groups[0].Filters[1].Operator = database.FilterStartsWith
+	filtered.Where.Groups[0].Filters[1].Value = "gam"
+	count, err = driver.CountCollectionData(filtered)
+	if err != nil || count != 2 {
+		t.Fatalf("CountCollectionData(filter tree, OR) = %d, %v, want 2", count, err)
+	}
+
+	if driver.Capabilities().RegexFilters {
+		regex := table
+		regex.Where = &database.FilterGroup{Filters: []database.Filter{{
+			Column: "name", Operator: database.FilterRegex, Value: "^gam+a$",
+		}}}
+		count, err = driver.CountCollectionData(regex)
+		if err != nil || count != 1 {
+			t.Fatalf("CountCollectionData(regex) = %d, %v, want 1", count, err)
+		}
+	}
+}
+
+func runJSONPathContract(ctx context.Context, t *testing.T, config LiveConfig) {
+	t.Helper()
+	driver := config.Driver
+	table := database.Table{Schema: config.Schema, Name: "rt_conformance_documents"}
+	_ = driver.DropTable(table)
+	t.Cleanup(func() { _ = driver.DropTable(table) })
+	if err := driver.CreateTable(table, []database.ColumnDefinition{
+		{Name: "id", Type: config.IntegerType, Nullable: false, PrimaryKey: true},
+		{Name: "doc", Type: config.JSONType, Nullable: true},
+	}); err != nil {
+		t.Fatalf("CreateTable(JSON fixture) error = %v", err)
+	}
+	for _, row := range []string{
+		`(1, '{"kind": "small", "size": 5, "tags": ["a"]}')`,
+		`(2, '{"kind": "large", "size": 20, "tags": ["b"]}')`,
+		`(3, '{"kind": "large", "size": 10, "tags": ["a"]}')`,
+		`(4, '{"kind": "odd", "size": "unknown"}')`,
+	} {
+		if _, err := driver.ExecuteQuery(ctx, fmt.Sprintf(
+			"INSERT INTO %s (%s, %s) VALUES %s",
+			qualified(driver, config.Schema, table.Name),
+			driver.QuoteIdentifier("id"),
+			driver.QuoteIdentifier("doc"),
+			row,
+		), database.QueryOptions{MaxRows: 10}); err != nil {
+			t.Fatalf("insert JSON fixture error = %v", err)
+		}
+	}
+
+	byKind := table
+	byKind.Filters = []database.Filter{{
+		Column: "doc", Path: "kind", Operator: database.FilterEqual, Value: "large",
+	}}
+	count, err := driver.CountCollectionData(byKind)
+	if err != nil || count != 2 {
+		t.Fatalf("CountCollectionData(JSON path) = %d, %v, want 2", count, err)
+	}
+
+	// A numeric value compares numbers only, so the "unknown" size is skipped.
+	bySize := table
+	bySize.Filters = []database.Filter{{
+		Column: "doc", Path: "$.size", Operator: database.FilterGreaterThan, Value: 7,
+	}}
+	count, err = driver.CountCollectionData(bySize)
+	if err != nil || count != 2 {
+		t.Fatalf("CountCollectionData(numeric JSON path) = %d, %v, want 2", count, err)
+	}
+
+	byTag := table
+	byTag.Filters = []database.Filter{{
+		Column: "doc", Path: "tags[0]", Operator: database.FilterEqual, Value: "a",
+	}}
+	count, err = driver.CountCollectionData(byTag)
+	if err != nil || count != 2 {
+		t.Fatalf("CountCollectionData(JSON array path) = %d, %v, want 2", count, err)
+	}
+
+	sorted := bySize
+	sorted.Limit = 10
+	sorted.Sorts = []database.Sort{{
+		Column: "doc", Path: "size", Direction: database.SortDescending,
+	}}
+	_, rows, err := driver.GetCollectionData(sorted)
+	if err != nil {
+		t.Fatalf("GetCollectionData(JSON path sort) error = %v", err)
+	}
+	if len(rows) != 2 || fmt.Sprint(rows[0]["id"]) != "2" || fmt.Sprint(rows[1]["id"]) != "3" {
+		t.Fatalf("GetCollectionData(JSON path sort) rows = %v, want ids 2, 3", rows)
+	}
+
+	var exported bytes.Buffer
+	stats, err := driver.ExportTable(ctx, database.TableExportRequest{
+		Table: byKind,
+		Scope: database.ExportScopeAll,
+		Options: database.ExportOptions{
+			Format: database.ExportFormatCSV,
+			CSV: database.CSVOptions{
+				IncludeHeader: true,
+				Encoding:      database.CSVEncodingUTF8,
+			},
+		},
+	}, &exported)
+	if err != nil {
+		t.Fatalf("ExportTable(JSON path) error = %v", err)
+	}
+	if stats.Rows != 2 || strings.Contains(exported.String(), "small") {
+		t.Fatalf("ExportTable(JSON path) rows=%d output=%q", stats.Rows, exported.String())
+	}
+
+	invalid := table
+	invalid.Filters = []database.Filter{{
+		Column: "doc", Path: `kind') OR ('1`, Operator: database.FilterEqual, Value: "x",
+	}}
+	if _, err := driver.CountCollectionData(invalid); err == nil {
+		t.Fatal("CountCollectionData() accepted a JSON path with quotes")
+	}
+}
+
+// runReturningContract updates one row through the rewritten statement
+// inside a transaction it rolls back, as a dry run does.
+func runReturningContract(
+	ctx context.Context,
+	t *testing.T,
+	driver database.Driver,
+	returning database.ReturningDriver,
+	relation string,
+) {
+	t.Helper()
+	query, ok := returning.ReturningQuery(fmt.Sprintf(
+		"UPDATE %s SET %s = %s + 1 WHERE %s = 1",
+		relation,
+		driver.QuoteIdentifier("score"),
+		driver.QuoteIdentifier("score"),
+		driver.QuoteIdentifier("id"),
+	))
+	if !ok || (!query.Before && !query.After) {
+		t.Fatalf("ReturningQuery(UPDATE) = %+v, %t", query, ok)
+	}
+	if _, ok := returning.ReturningQuery("SELECT 1"); ok {
+		t.Fatal("ReturningQuery() rewrote a SELECT")
+	}
+	transaction, err := driver.(database.TransactionalDriver).BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("BeginTransaction() for RETURNING error = %v", err)
+	}
+	defer func() { _ = transaction.Rollback() }()
+	result, err := transaction.ExecuteQuery(ctx, query.Query, database.QueryOptions{MaxRows: 10})
+	if err != nil {
+		t.Fatalf("ExecuteQuery(%q) error = %v", query.Query, err)
+	}
+	if len(result.Rows) != 1 || len(result.Columns) == 0 {
+		t.Fatalf("returning rows = %+v, want the one updated row", result)
+	}
+}
+
+// runRowEstimateContract only checks the shape of estimates. Statistics
+// depend on when the engine last analyzed the table, so an estimate may be
+// missing or stale and its value is not compared with the real count.
+func runRowEstimateContract(
+	ctx context.Context,
+	t *testing.T,
+	estimator database.RowEstimateDriver,
+	table database.Table,
+) {
+	t.Helper()
+	filtered := table
+	filtered.Filters = []database.Filter{
+		{Column: "id", Operator: database.FilterGreaterThan, Value: 0},
+	}
+	for name, candidate := range map[string]database.Table{
+		"table":    table,
+		"filtered": filtered,
+	} {
+		count, ok, err := estimator.EstimateCollectionData(ctx, candidate)
+		if err != nil {
+			t.Fatalf("EstimateCollectionData(%s) error = %v", name, err)
+		}
+		if ok && (!count.Estimated || count.Rows < 0 ||
+			(count.Source != database.RowCountStatistics && count.Source != database.RowCountPlanner)) {
+			t.Fatalf("EstimateCollectionData(%s) = %+v, want a non-negative estimate", name, count)
+		}
+	}
+	missing := table
+	missing.Name = "rt_conformance_missing"
+	if _, ok, err := estimator.EstimateCollectionData(ctx, missing); err == nil && ok {
+		t.Fatal("EstimateCollectionData() estimated a table that does not exist")
+	}
+}
+
+// runKeysetContract pages a table with duplicate and NULL sort values two
+// rows at a time and expects the same rows, in the same order, as reading
+// it in one page.
+func runKeysetContract(ctx context.Context, t *testing.T, config LiveConfig) {
+	t.Helper()
+	driver := config.Driver
+	table := database.Table{Schema: config.Schema, Name: "rt_conformance_pages"}
+	_ = driver.DropTable(table)
+	t.Cleanup(func() { _ = driver.DropTable(table) })
+	if err := driver.CreateTable(table, []database.ColumnDefinition{
+		{Name: "id", Type: config.IntegerType, Nullable: false, PrimaryKey: true},
+		{Name: "name", Type: config.TextType, Nullable: false},
+		{Name: "score", Type: config.IntegerType, Nullable: true},
+	}); err != nil {
+		t.Fatalf("CreateTable(keyset fixture) error = %v", err)
+	}
+	for _, row := range []string{
+		"(1, 'b', 10)", "(2, 'a', NULL)", "(3, 'c', 10)", "(4, 'a', 5)",
+		"(5, 'b', NULL)", "(6, 'c', 20)", "(7, 'a', 10)",
+	} {
+		if _, err := driver.ExecuteQuery(ctx, fmt.Sprintf(
+			"INSERT INTO %s (%s, %s, %s) VALUES %s",
+			qualified(driver, config.Schema, table.Name),
+			driver.QuoteIdentifier("id"),
+			driver.QuoteIdentifier("name"),
+			driver.QuoteIdentifier("score"),
+			row,
+		), database.QueryOptions{MaxRows: 10}); err != nil {
+			t.Fatalf("insert keyset fixture error = %v", err)
+		}
+	}
+	structures, err := driver.GetCollectionStructures(table)
+	if err != nil {
+		t.Fatalf("GetCollectionStructures(keyset fixture) error = %v", err)
+	}
+
+	for _, sorts := range [][]database.Sort{
+		nil,
+		{{Column: "score", Direction: database.SortAscending, Nulls: database.NullsLast}},
+		{{Column: "score", Direction: database.SortDescending, Nulls: database.NullsFirst}},
+		{
+			{Column: "name", Direction: database.SortDescending, Nulls: database.NullsLast},
+			{Column: "score", Direction: database.SortAscending, Nulls: database.NullsFirst},
+		},
+	} {
+		view := table
+		view.Sorts = sorts
+		view.Filters = []database.Filter{{Column: "id", Operator: database.FilterNotEqual, Value: 3}}
+		keyset, ok := database.NewKeysetPage(view, structures)
+		if !ok {
+			t.Fatalf("NewKeysetPage(%+v) = false for a table with a primary key", sorts)
+		}
+
+		full, err := keyset.Table("")
+		if err != nil {
+			t.Fatalf("KeysetPage.Table() error = %v", err)
+		}
+		full.Limit = 100
+		_, rows, err := driver.GetCollectionData(full)
+		if err != nil {
+			t.Fatalf("GetCollectionData(keyset order %+v) error = %v", sorts, err)
+		}
+		want := make([]string, len(rows))
+		for index, row := range rows {
+			want[index] = fmt.Sprint(row["id"])
+		}
+
+		got := make([]string, 0, len(want))
+		token := ""
+		for page := 0; page < 10; page++ {
+			request, err := keyset.Table(token)
+			if err != nil {
+				t.Fatalf("KeysetPage.Table(token) error = %v", err)
+			}
+			request.Limit = 2
+			_, rows, err := driver.GetCollectionData(request)
+			if err != nil {
+				t.Fatalf("GetCollectionData(keyset page %+v) error = %v", sorts, err)
+			}
+			for _, row := range rows {
+				got = append(got, fmt.Sprint(row["id"]))
+			}
+			token = keyset.NextToken(rows, request.Limit)
+			if token == "" {
+				break
+			}
+		}
+		if len(want) != 6 || strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("keyset pages for %+v = %v, want %v", sorts, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+// MaxDryRunSampleRows caps the changed rows a dry run returns per
+// statement.
+const MaxDryRunSampleRows = 100
+
+// DryRunRequest runs a batch in a transaction that is always rolled back.
+// SampleRows asks for up to that many changed rows per statement from
+// drivers that can return them.
+type DryRunRequest struct {
+	ConnectionID string          `json:"connectionId"`
+	Query        string          `json:"query"`
+	AttemptID    string          `json:"attemptId"`
+	Variables    []QueryVariable `json:"variables,omitempty"`
+	SampleRows   int             `json:"sampleRows,omitempty"`
+}
+
+// DryRunStatement reports what one statement would have done. Before and
+// After hold sampled rows as they were before and after the change, in
+// Columns order. A deleted row only has a before image and an inserted one
+// only an after image.
+type DryRunStatement struct {
+	Index           int             `json:"index"`
+	Statement       string          `json:"statement"`
+	RowsAffected    *int64          `json:"rowsAffected,omitempty"`
+	CommandTag      string          `json:"commandTag,omitempty"`
+	ElapsedMS       int64           `json:"elapsedMs"`
+	Columns         []QueryColumn   `json:"columns,omitempty"`
+	Before          [][]interface{} `json:"before,omitempty"`
+	After           [][]interface{} `json:"after,omitempty"`
+	SampleTruncated bool            `json:"sampleTruncated,omitempty"`
+	Messages        []QueryMessage  `json:"messages,omitempty"`
+}
+
+// DryRunResult lists every statement of the batch. UnfilteredMutations
+// repeats what the unfiltered-mutation guard would have asked to confirm.
+type DryRunResult struct {
+	Statements          []DryRunStatement `json:"statements"`
+	RolledBack          bool              `json:"rolledBack"`
+	UnfilteredMutations []string          `json:"unfilteredMutations,omitempty"`
+}
+
+// NonTransactionalTableDriver finds the tables a batch names whose storage
+// ignores ROLLBACK, such as MySQL MyISAM tables, so a dry run can refuse
+// instead of changing them for real.
+type NonTransactionalTableDriver interface {
+	NonTransactionalTables(ctx context.Context, statements []string) ([]string, error)
+}
+
+// ReturningQuery is a data-changing statement rewritten to also return the
+// rows it changed. Before and After say which images the rows hold; when
+// both are set, the first half of the columns is the before image.
+// CountQuery reads how many rows the statement changed, for engines that
+// report no count once a statement returns rows.
+type ReturningQuery struct {
+	Query      string
+	Before     bool
+	After      bool
+	CountQuery string
+}
+
+// ReturningDriver adds a RETURNING or OUTPUT clause to statement. ok is
+// false for statements the driver cannot rewrite, such as those that
+// already return rows.
+type ReturningDriver interface {
+	ReturningQuery(statement string) (query ReturningQuery, ok bool)
+}
+
+// ReturningAllQuery appends RETURNING * to a single INSERT, UPDATE, or
+// DELETE for engines that support the PostgreSQL form. UPDATE returns the
+// new row values and DELETE the removed ones.
+func ReturningAllQuery(statement string) (ReturningQuery, bool) {
+	words := TopLevelSQLWords(statement)
+	if len(words) == 0 || !ReportsAffectedRows(statement) {
+		return ReturningQuery{}, false
+	}
+	query := ReturningQuery{
+		// The clause goes on its own line so a trailing line comment
+		// cannot swallow it.
+		Query: strings.TrimRight(strings.TrimSpace(statement), ";") + "\nRETURNING *",
+	}
+	switch words[0].Word {
+	case "INSERT", "UPDATE":
+		query.After = true
+	case "DELETE":
+		query.Before = true
+	default:
+		return ReturningQuery{}, false
+	}
+	return query, true
+}
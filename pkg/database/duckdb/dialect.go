@@ -0,0 +1,136 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+var duckDBDialect = database.Dialect{
+	Name:                 "duckdb",
+	IdentifierOpen:       `"`,
+	IdentifierClose:      `"`,
+	PlaceholderStyle:     database.PlaceholderQuestion,
+	PaginationStyle:      database.PaginationLimitOffset,
+	SupportsNullOrdering: true,
+}
+
+func (d *DuckDB) Capabilities() database.Capabilities {
+	return database.Capabilities{
+		Engine:             database.DriverDuckDB,
+		DisplayName:        "DuckDB",
+		Dialect:            duckDBDialect,
+		Schemas:            true,
+		Databases:          true,
+		Tables:             true,
+		Views:              true,
+		Sequences:          true,
+		Constraints:        true,
+		ObjectDefinitions:  true,
+		ExplainPlans:       true,
+		Transactions:       true,
+		TransactionalDDL:   true,
+		AtomicTableChanges: true,
+		SQLInsertExport:    true,
+		FileDatabase:       true,
+		AttachedDatabases:  true,
+		Upsert:             false,
+		SSHConnections:     false,
+		QueryCursors:       true,
+		FilterGroups:       true,
+		RegexFilters:       true,
+		JSONPaths:          true,
+		KeysetPagination:   true,
+	}
+}
+
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// splitSchema separates a schema reference into its catalog and schema.
+// Schemas of the primary database are listed by name alone; schemas of an
+// attached database are listed as "catalog.schema", so the reference is
+// enough to address a table anywhere in the session.
+func splitSchema(reference string) (string, string) {
+	catalog, schema, found := strings.Cut(reference, ".")
+	if !found {
+		return "", reference
+	}
+	return catalog, schema
+}
+
+func schemaReference(catalog, schema, primary string) string {
+	if catalog == "" || catalog == primary {
+		return schema
+	}
+	return catalog + "." + schema
+}
+
+func quoteSchema(reference string) string {
+	catalog, schema := splitSchema(reference)
+	if catalog == "" {
+		return quoteIdentifier(schema)
+	}
+	return quoteIdentifier(catalog) + "." + quoteIdentifier(schema)
+}
+
+func quoteQualified(schema, name string) string {
+	if strings.TrimSpace(schema) == "" {
+		return quoteIdentifier(name)
+	}
+	return quoteSchema(schema) + "." + quoteIdentifier(name)
+}
+
+func (d *DuckDB) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier)
+}
+
+func (d *DuckDB) Placeholder(int) string {
+	return "?"
+}
+
+func (d *DuckDB) PaginationClause(limit, offset int) (string, error) {
+	if limit < 0 {
+		return "", fmt.Errorf("pagination limit cannot be negative")
+	}
+	if offset < 0 {
+		return "", fmt.Errorf("pagination offset cannot be negative")
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset), nil
+}
+
+func (d *DuckDB) adapterDialect() sqladapter.Dialect {
+	return sqladapter.Dialect{
+		QuoteIdentifier:      quoteIdentifier,
+		QuoteQualified:       quoteQualified,
+		Placeholder:          d.Placeholder,
+		Pagination:           d.PaginationClause,
+		SupportsNullOrdering: true,
+		TextExpression: func(identifier string) string {
+			return "CAST(" + identifier + " AS VARCHAR)"
+		},
+		RegexExpression: func(text, pattern string) string {
+			return "regexp_matches(" + text + ", " + pattern + ")"
+		},
+		JSONPathExpression: func(
+			column string,
+			path database.JSONPath,
+			numeric bool,
+			bind func(interface{}) string,
+		) string {
+			if numeric {
+				// json_type keeps a quoted "12" from reading as a number.
+				return "(CASE WHEN json_type(" + column + ", " + bind(path.String()) +
+					") IN ('BIGINT', 'UBIGINT', 'DOUBLE') THEN CAST(json_extract_string(" +
+					column + ", " + bind(path.String()) + ") AS DOUBLE) END)"
+			}
+			return "json_extract_string(" + column + ", " + bind(path.String()) + ")"
+		},
+		InsertExport: duckDBInsertExportDialect(),
+	}
+}
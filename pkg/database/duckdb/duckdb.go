@@ -0,0 +1,434 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	duckdbdriver "github.com/duckdb/duckdb-go/v2"
+)
+
+const defaultSchemaName = "main"
+
+type Config struct {
+	Db string
+}
+
+type DuckDB struct {
+	cfg     Config
+	ctx     context.Context
+	conn    *sql.DB
+	path    string
+	catalog string
+}
+
+func NewDuckDB(ctx context.Context, cfg Config) *DuckDB {
+	return &DuckDB{cfg: cfg, ctx: ctx}
+}
+
+// databasePath resolves the profile path. ":memory:" opens a private
+// in-memory database, which is useful for querying Parquet or CSV files
+// without creating a database file first.
+func databasePath(configuredPath string) (string, error) {
+	configuredPath = strings.TrimSpace(configuredPath)
+	if configuredPath == "" {
+		return "", fmt.Errorf("DuckDB database file is required")
+	}
+	if configuredPath == ":memory:" {
+		return configuredPath, nil
+	}
+	absolute, err := filepath.Abs(configuredPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve DuckDB database path: %w", err)
+	}
+	parent := filepath.Dir(absolute)
+	if info, err := os.Stat(parent); err != nil {
+		return "", fmt.Errorf("access DuckDB database directory: %w", err)
+	} else if !info.IsDir() {
+		return "", fmt.Errorf("DuckDB parent path is not a directory")
+	}
+	return absolute, nil
+}
+
+func (d *DuckDB) Connect(ctx context.Context) error {
+	if ctx == nil {
+		ctx = d.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if d.conn != nil {
+		if err := d.Close(); err != nil {
+			return fmt.Errorf("close previous DuckDB database: %w", err)
+		}
+	}
+	path, err := databasePath(d.cfg.Db)
+	if err != nil {
+		return err
+	}
+	dsn := path
+	if path == ":memory:" {
+		dsn = ""
+	}
+	connection, err := sql.Open("duckdb", dsn)
+	if err != nil {
+		return fmt.Errorf("open DuckDB database: %w", err)
+	}
+	if err := connection.PingContext(ctx); err != nil {
+		_ = connection.Close()
+		return fmt.Errorf("open DuckDB database (the file may be locked by another process): %w", err)
+	}
+	var catalog string
+	if err := connection.QueryRowContext(
+		ctx,
+		"SELECT current_database()",
+	).Scan(&catalog); err != nil {
+		_ = connection.Close()
+		return fmt.Errorf("read DuckDB database name: %w", err)
+	}
+	d.conn = connection
+	d.path = path
+	d.catalog = catalog
+	d.ctx = context.WithoutCancel(ctx)
+	return nil
+}
+
+func (d *DuckDB) Close() error {
+	if d.conn == nil {
+		return nil
+	}
+	err := d.conn.Close()
+	d.conn = nil
+	d.catalog = ""
+	return err
+}
+
+func (d *DuckDB) Ping(ctx context.Context) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	return d.conn.PingContext(ctx)
+}
+
+func (d *DuckDB) ensureConnected() error {
+	if d.conn == nil {
+		return errors.New("DuckDB database is not open")
+	}
+	return nil
+}
+
+func (d *DuckDB) defaultSchema(schema string) string {
+	if strings.TrimSpace(schema) != "" {
+		return schema
+	}
+	return defaultSchemaName
+}
+
+// catalogFor returns the catalog named by a schema reference, falling back
+// to the primary database for unqualified schemas.
+func (d *DuckDB) catalogFor(reference string) (string, string) {
+	catalog, schema := splitSchema(d.defaultSchema(reference))
+	if catalog == "" {
+		catalog = d.catalog
+	}
+	return catalog, schema
+}
+
+func (d *DuckDB) GetDatabaseInfo() (database.Info, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.Info{}, err
+	}
+	var version string
+	if err := d.conn.QueryRow("SELECT version()").Scan(&version); err != nil {
+		return database.Info{}, err
+	}
+	return database.Info{
+		Engine:   "DuckDB",
+		Version:  version,
+		Database: d.path,
+	}, nil
+}
+
+func (d *DuckDB) CountCollectionData(table database.Table) (int, error) {
+	return d.CountCollectionDataContext(d.ctx, table)
+}
+
+func (d *DuckDB) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
+	if err := d.ensureConnected(); err != nil {
+		return 0, err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	structures, err := d.GetCollectionStructures(table)
+	if err != nil {
+		return 0, err
+	}
+	return sqladapter.CountTable(ctx, d.conn, table, structures, d.adapterDialect())
+}
+
+func (d *DuckDB) GetCollectionData(
+	table database.Table,
+) (database.Structures, []map[string]interface{}, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, nil, err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	structures, err := d.GetCollectionStructures(table)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := sqladapter.GetTableData(
+		d.ctx,
+		d.conn,
+		table,
+		structures,
+		d.adapterDialect(),
+	)
+	if err != nil {
+		return structures, nil, err
+	}
+	uuidColumns := make(map[string]bool)
+	for _, structure := range structures {
+		if structure.DataType == "UUID" {
+			uuidColumns[structure.Name] = true
+		}
+	}
+	for _, row := range rows {
+		for column, value := range row {
+			if raw, ok := value.([]byte); ok && uuidColumns[column] && len(raw) == 16 {
+				row[column] = uuidText(raw)
+				continue
+			}
+			row[column] = normalizeValue(value)
+		}
+	}
+	return structures, rows, nil
+}
+
+func (d *DuckDB) InsertRow(
+	table database.Table,
+	data map[string]interface{},
+) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	structures, err := d.GetCollectionStructures(table)
+	if err != nil {
+		return err
+	}
+	return sqladapter.InsertRowWithStructures(
+		d.conn,
+		table,
+		data,
+		structures,
+		d.adapterDialect(),
+	)
+}
+
+func (d *DuckDB) UpdateRow(
+	table database.Table,
+	data map[string]interface{},
+	primaryKey string,
+) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	structures, err := d.GetCollectionStructures(table)
+	if err != nil {
+		return err
+	}
+	return sqladapter.UpdateRowWithStructures(
+		d.conn,
+		table,
+		data,
+		primaryKey,
+		structures,
+		d.adapterDialect(),
+	)
+}
+
+func (d *DuckDB) DeleteRow(
+	table database.Table,
+	primaryKey string,
+	primaryValue interface{},
+) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	return sqladapter.DeleteRow(
+		d.conn,
+		table,
+		primaryKey,
+		primaryValue,
+		d.adapterDialect(),
+	)
+}
+
+func (d *DuckDB) ExecuteQuery(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.QueryResult{}, err
+	}
+	result, err := sqladapter.ExecuteQuery(ctx, d.conn, query, options)
+	return normalizeResult(result), err
+}
+
+func (d *DuckDB) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	return sqladapter.OpenCursor(ctx, d.conn, query, options, normalizeRow)
+}
+
+type duckDBTransaction struct {
+	tx *sql.Tx
+}
+
+func (transaction *duckDBTransaction) ExecuteQuery(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	result, err := sqladapter.ExecuteQuery(ctx, transaction.tx, query, options)
+	return normalizeResult(result), err
+}
+
+func (transaction *duckDBTransaction) Commit() error {
+	return transaction.tx.Commit()
+}
+
+func (transaction *duckDBTransaction) Rollback() error {
+	return transaction.tx.Rollback()
+}
+
+func (d *DuckDB) BeginTransaction(
+	ctx context.Context,
+) (database.Transaction, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	transaction, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &duckDBTransaction{tx: transaction}, nil
+}
+
+func (d *DuckDB) ApplyTableChanges(
+	ctx context.Context,
+	changes database.TableChangeSet,
+) (database.TableChangeResult, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.TableChangeResult{}, err
+	}
+	changes.Table.Schema = d.defaultSchema(changes.Table.Schema)
+	structures, err := d.GetCollectionStructures(changes.Table)
+	if err != nil {
+		return database.TableChangeResult{}, err
+	}
+	return sqladapter.ApplyTableChanges(
+		ctx,
+		d.conn,
+		changes,
+		structures,
+		d.adapterDialect(),
+	)
+}
+
+func (d *DuckDB) ExportTable(
+	ctx context.Context,
+	request database.TableExportRequest,
+	writer io.Writer,
+) (database.ExportStats, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.ExportStats{}, err
+	}
+	request.Table.Schema = d.defaultSchema(request.Table.Schema)
+	structures, err := d.GetCollectionStructures(request.Table)
+	if err != nil {
+		return database.ExportStats{}, err
+	}
+	return sqladapter.ExportTable(
+		ctx,
+		d.conn,
+		request,
+		structures,
+		d.adapterDialect(),
+		writer,
+	)
+}
+
+// normalizeResult rewrites DuckDB-specific values so results survive JSON
+// encoding: MAP keys may be any type and DECIMAL is a struct of big.Int
+// parts.
+func normalizeResult(result database.QueryResult) database.QueryResult {
+	for _, row := range result.Rows {
+		normalizeRow(row)
+	}
+	for _, resultSet := range result.ResultSets {
+		for _, row := range resultSet.Rows {
+			normalizeRow(row)
+		}
+	}
+	return result
+}
+
+func normalizeRow(row []interface{}) {
+	for index, value := range row {
+		row[index] = normalizeValue(value)
+	}
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case duckdbdriver.Decimal:
+		return typed.String()
+	case duckdbdriver.Map:
+		normalized := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			normalized[fmt.Sprint(key)] = normalizeValue(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = normalizeValue(item)
+		}
+		return typed
+	case []interface{}:
+		for index, item := range typed {
+			typed[index] = normalizeValue(item)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+func uuidText(raw []byte) string {
+	encoded := hex.EncodeToString(raw)
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" +
+		encoded[16:20] + "-" + encoded[20:32]
+}
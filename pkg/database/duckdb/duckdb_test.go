@@ -0,0 +1,157 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/drivertest"
+)
+
+func connectDuckDB(t *testing.T, path string) *DuckDB {
+	t.Helper()
+	driver := NewDuckDB(context.Background(), Config{Db: path})
+	if err := driver.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := driver.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+	return driver
+}
+
+func TestDuckDBCapabilityContract(t *testing.T) {
+	driver := NewDuckDB(context.Background(), Config{Db: ":memory:"})
+	drivertest.RunCapabilityContract(t, driver, "duckdb")
+}
+
+func TestDuckDBLiveConformance(t *testing.T) {
+	driver := connectDuckDB(t, filepath.Join(t.TempDir(), "conformance.duckdb"))
+	drivertest.RunLiveContract(t, drivertest.LiveConfig{
+		Driver:      driver,
+		Schema:      "main",
+		IntegerType: "INTEGER",
+		TextType:    "VARCHAR",
+		JSONType:    "JSON",
+	})
+}
+
+func TestDuckDBBrowsesAttachedDatabases(t *testing.T) {
+	driver := connectDuckDB(t, filepath.Join(t.TempDir(), "primary.duckdb"))
+	attached := filepath.Join(t.TempDir(), "warehouse.duckdb")
+	ctx := context.Background()
+	if _, err := driver.ExecuteQuery(ctx,
+		"ATTACH "+quoteLiteral(attached)+" AS warehouse; "+
+			"CREATE SCHEMA warehouse.staging; "+
+			"CREATE TABLE warehouse.staging.orders (id INTEGER PRIMARY KEY, total DECIMAL(10, 2)); "+
+			"INSERT INTO warehouse.staging.orders VALUES (1, 9.50), (2, 12.25)",
+		database.QueryOptions{},
+	); err != nil {
+		t.Fatalf("ExecuteQuery(attach) error = %v", err)
+	}
+
+	schemas, err := driver.GetSchemas()
+	if err != nil {
+		t.Fatalf("GetSchemas() error = %v", err)
+	}
+	if !slices.Contains(schemas, "main") || !slices.Contains(schemas, "warehouse.staging") {
+		t.Fatalf("GetSchemas() = %v", schemas)
+	}
+	collections, err := driver.GetCollections("warehouse.staging")
+	if err != nil || !slices.Equal(collections, []string{"orders"}) {
+		t.Fatalf("GetCollections(warehouse.staging) = %v, %v", collections, err)
+	}
+	structures, rows, err := driver.GetCollectionData(database.Table{
+		Schema: "warehouse.staging",
+		Name:   "orders",
+		Sorts:  []database.Sort{{Column: "total", Direction: database.SortDescending}},
+		Limit:  1,
+	})
+	if err != nil {
+		t.Fatalf("GetCollectionData() error = %v", err)
+	}
+	if len(structures) != 2 || !structures[0].IsPrimary || len(rows) != 1 || rows[0]["total"] != "12.25" {
+		t.Fatalf("GetCollectionData() = %+v, %v", structures, rows)
+	}
+	objects, err := driver.ListObjects(ctx, database.ObjectFilter{Schema: "warehouse.staging"})
+	if err != nil || len(objects) == 0 {
+		t.Fatalf("ListObjects(warehouse.staging) = %+v, %v", objects, err)
+	}
+}
+
+func TestDuckDBQueriesChosenDataFiles(t *testing.T) {
+	driver := connectDuckDB(t, ":memory:")
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "team's extracts")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	csvPath := filepath.Join(dir, "events.csv")
+	if err := os.WriteFile(csvPath, []byte("id,kind\n1,open\n2,close\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	parquetPath := filepath.Join(dir, "events.parquet")
+	if _, err := driver.ExecuteQuery(ctx,
+		"COPY (SELECT * FROM read_csv("+quoteLiteral(csvPath)+")) TO "+
+			quoteLiteral(parquetPath)+" (FORMAT parquet)",
+		database.QueryOptions{},
+	); err != nil {
+		t.Fatalf("write Parquet fixture error = %v", err)
+	}
+
+	for _, path := range []string{csvPath, parquetPath} {
+		file, err := InspectDataFile(path)
+		if err != nil {
+			t.Fatalf("InspectDataFile(%s) error = %v", path, err)
+		}
+		result, err := driver.ExecuteQuery(ctx, file.Query+" ORDER BY id", database.QueryOptions{})
+		if err != nil {
+			t.Fatalf("ExecuteQuery(%s) error = %v", file.Query, err)
+		}
+		if len(result.Rows) != 2 || result.RowMaps()[1]["kind"] != "close" {
+			t.Fatalf("ExecuteQuery(%s) rows = %v", file.Query, result.Rows)
+		}
+	}
+	if _, err := InspectDataFile(filepath.Join(dir, "notes.md")); err == nil {
+		t.Fatal("InspectDataFile() accepted a missing file")
+	}
+	if _, err := DataFileFormat("report.xlsx"); err == nil {
+		t.Fatal("DataFileFormat() accepted a spreadsheet")
+	}
+}
+
+func TestDuckDBExplainReadsJSONPlan(t *testing.T) {
+	driver := connectDuckDB(t, ":memory:")
+	ctx := context.Background()
+	if _, err := driver.ExecuteQuery(ctx,
+		"CREATE TABLE readings (id INTEGER PRIMARY KEY, sensor VARCHAR)",
+		database.QueryOptions{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := driver.ExplainQuery(ctx, "SELECT sensor FROM readings WHERE id > 1")
+	if err != nil {
+		t.Fatalf("ExplainQuery() error = %v", err)
+	}
+	var relation string
+	var walk func(nodes []database.ExplainPlanNode)
+	walk = func(nodes []database.ExplainPlanNode) {
+		for _, node := range nodes {
+			if node.Relation != "" {
+				relation = node.Relation
+			}
+			walk(node.Children)
+		}
+	}
+	walk(plan.Roots)
+	if plan.Engine != "DuckDB" || relation != "readings" || plan.Raw == "" {
+		t.Fatalf("ExplainQuery() = %+v", plan)
+	}
+}
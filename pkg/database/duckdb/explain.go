@@ -0,0 +1,132 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+type duckDBPlanNode struct {
+	Name      string                     `json:"name"`
+	Children  []duckDBPlanNode           `json:"children"`
+	ExtraInfo map[string]json.RawMessage `json:"extra_info"`
+}
+
+// ExplainQuery reads the physical plan that EXPLAIN (FORMAT JSON) returns.
+// DuckDB reports estimates as strings inside extra_info, so the numeric
+// fields are parsed from there.
+func (d *DuckDB) ExplainQuery(
+	ctx context.Context,
+	query string,
+) (database.ExplainPlan, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.ExplainPlan{}, err
+	}
+	rows, err := d.conn.QueryContext(ctx, "EXPLAIN (FORMAT JSON) "+query)
+	if err != nil {
+		return database.ExplainPlan{}, err
+	}
+	defer rows.Close()
+	raw := ""
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return database.ExplainPlan{}, err
+		}
+		if key == "physical_plan" {
+			raw = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return database.ExplainPlan{}, err
+	}
+	var plan []duckDBPlanNode
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return database.ExplainPlan{}, fmt.Errorf("read DuckDB explain plan: %w", err)
+	}
+	if len(plan) == 0 {
+		return database.ExplainPlan{}, fmt.Errorf("DuckDB returned an empty explain plan")
+	}
+	counter := 0
+	roots := make([]database.ExplainPlanNode, 0, len(plan))
+	for _, node := range plan {
+		roots = append(roots, buildPlanNode(node, "", &counter))
+	}
+	return database.ExplainPlan{
+		Engine:  "DuckDB",
+		Summary: roots[0].Summary,
+		Roots:   roots,
+		Raw:     raw,
+	}, nil
+}
+
+func buildPlanNode(
+	source duckDBPlanNode,
+	parentID string,
+	counter *int,
+) database.ExplainPlanNode {
+	*counter++
+	node := database.ExplainPlanNode{
+		ID:       fmt.Sprintf("duckdb-%d", *counter),
+		ParentID: parentID,
+		NodeType: strings.TrimSpace(source.Name),
+		Details:  make(map[string]string, len(source.ExtraInfo)),
+		Children: make([]database.ExplainPlanNode, 0, len(source.Children)),
+	}
+	for key, value := range source.ExtraInfo {
+		node.Details[key] = planDetail(value)
+	}
+	node.Relation = node.Details["Table"]
+	if estimate, err := strconv.ParseFloat(
+		node.Details["Estimated Cardinality"],
+		64,
+	); err == nil {
+		node.EstimatedRows = estimate
+	}
+	node.Summary = node.NodeType
+	if node.Relation != "" {
+		node.Summary += " on " + node.Relation
+	} else if condition := node.Details["Conditions"]; condition != "" {
+		node.Summary += " (" + condition + ")"
+	}
+	for _, child := range source.Children {
+		node.Children = append(node.Children, buildPlanNode(child, node.ID, counter))
+	}
+	return node
+}
+
+// planDetail flattens an extra_info value. Most are strings; projections
+// and conditions may be lists of strings.
+func planDetail(value json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		return text
+	}
+	var list []string
+	if err := json.Unmarshal(value, &list); err == nil {
+		return strings.Join(list, ", ")
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err == nil {
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, key+": "+planDetail(object[key]))
+		}
+		return strings.Join(parts, "; ")
+	}
+	return string(value)
+}
+
+var _ database.ExplainPlanDriver = (*DuckDB)(nil)
@@ -0,0 +1,66 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	duckdbdriver "github.com/duckdb/duckdb-go/v2"
+)
+
+func duckDBSQLLiteral(
+	value interface{},
+	column database.Structure,
+) (string, error) {
+	if value == nil {
+		return "NULL", nil
+	}
+	if number, ok, err := sqladapter.SQLNumericLiteral(value); ok {
+		return number, err
+	}
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		return quoteLiteral(typed), nil
+	case []byte:
+		if len(typed) == 16 && column.DataType == "UUID" {
+			return quoteLiteral(uuidText(typed)) + "::UUID", nil
+		}
+		return "from_hex(" + quoteLiteral(hex.EncodeToString(typed)) + ")", nil
+	case time.Time:
+		return quoteLiteral(typed.Format(time.RFC3339Nano)), nil
+	case *big.Int:
+		return typed.String(), nil
+	case duckdbdriver.Decimal:
+		return typed.String(), nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported DuckDB SQL literal type %T for %s",
+			value,
+			column.Name,
+		)
+	}
+}
+
+func duckDBInsertExportDialect() *sqladapter.InsertExportDialect {
+	return &sqladapter.InsertExportDialect{
+		EngineLabel:      "DuckDB",
+		QuoteIdentifier:  quoteIdentifier,
+		QuoteQualified:   quoteQualified,
+		Literal:          duckDBSQLLiteral,
+		BeginStatement:   "BEGIN TRANSACTION;",
+		CommitStatement:  "COMMIT;",
+		MultiRowValues:   true,
+		MaximumBatchSize: 1000,
+	}
+}
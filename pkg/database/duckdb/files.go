@@ -0,0 +1,71 @@
+package duckdb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataFile describes a Parquet or CSV file chosen for querying, together
+// with a ready-to-run query that reads it through DuckDB's table functions.
+type DataFile struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Query  string `json:"query"`
+}
+
+const (
+	DataFormatParquet = "parquet"
+	DataFormatCSV     = "csv"
+)
+
+// DataFileFormat recognises the extensions DuckDB reads without extra
+// options, including gzip- and zstd-compressed CSV files.
+func DataFileFormat(path string) (string, error) {
+	name := strings.ToLower(filepath.Base(path))
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".zst")
+	switch filepath.Ext(name) {
+	case ".parquet":
+		return DataFormatParquet, nil
+	case ".csv", ".tsv", ".txt":
+		return DataFormatCSV, nil
+	default:
+		return "", fmt.Errorf("%s is not a Parquet or CSV file", filepath.Base(path))
+	}
+}
+
+// InspectDataFile checks that path is a readable Parquet or CSV file and
+// builds the query that reads it. The path is embedded as a SQL string
+// literal, so quotes in file names cannot change the statement.
+func InspectDataFile(path string) (DataFile, error) {
+	absolute, err := filepath.Abs(strings.TrimSpace(path))
+	if err != nil {
+		return DataFile{}, fmt.Errorf("resolve data file path: %w", err)
+	}
+	absolute = filepath.Clean(absolute)
+	info, err := os.Stat(absolute)
+	if err != nil {
+		return DataFile{}, fmt.Errorf("access data file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return DataFile{}, fmt.Errorf("%s is not a regular file", filepath.Base(absolute))
+	}
+	format, err := DataFileFormat(absolute)
+	if err != nil {
+		return DataFile{}, err
+	}
+	function := "read_csv"
+	if format == DataFormatParquet {
+		function = "read_parquet"
+	}
+	return DataFile{
+		Path:   absolute,
+		Format: format,
+		Query:  "SELECT * FROM " + function + "(" + quoteLiteral(filepath.ToSlash(absolute)) + ")",
+	}, nil
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,350 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// GetSchemas lists the schemas of the primary database followed by those of
+// attached databases, which are qualified with their catalog name.
+func (d *DuckDB) GetSchemas() ([]string, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	rows, err := d.conn.Query(`
+		SELECT schema_object.database_name, schema_object.schema_name
+		FROM duckdb_schemas() schema_object
+		JOIN duckdb_databases() database_object
+			ON database_object.database_name = schema_object.database_name
+		WHERE NOT database_object.internal
+			AND schema_object.schema_name NOT IN ('information_schema', 'pg_catalog')
+		ORDER BY
+			schema_object.database_name <> current_database(),
+			schema_object.database_name,
+			schema_object.schema_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	schemas := make([]string, 0)
+	for rows.Next() {
+		var catalog, schema string
+		if err := rows.Scan(&catalog, &schema); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schemaReference(catalog, schema, d.catalog))
+	}
+	return schemas, rows.Err()
+}
+
+func (d *DuckDB) GetCollections(schema ...string) ([]string, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	target := ""
+	if len(schema) > 0 {
+		target = schema[0]
+	}
+	catalog, schemaName := d.catalogFor(target)
+	rows, err := d.conn.Query(`
+		SELECT table_name
+		FROM duckdb_tables()
+		WHERE database_name = ? AND schema_name = ? AND NOT internal
+		ORDER BY table_name`,
+		catalog,
+		schemaName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+type constraintMetadata struct {
+	kind              string
+	name              string
+	columns           []string
+	referencedTable   string
+	referencedColumns []string
+}
+
+func textList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, fmt.Sprint(item))
+	}
+	return result
+}
+
+func (d *DuckDB) constraints(
+	table database.Table,
+) ([]constraintMetadata, error) {
+	catalog, schema := d.catalogFor(table.Schema)
+	rows, err := d.conn.Query(`
+		SELECT
+			constraint_type,
+			constraint_name,
+			constraint_column_names,
+			referenced_table,
+			referenced_column_names
+		FROM duckdb_constraints()
+		WHERE database_name = ? AND schema_name = ? AND table_name = ?
+			AND constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+		ORDER BY constraint_index`,
+		catalog,
+		schema,
+		table.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	constraints := make([]constraintMetadata, 0)
+	for rows.Next() {
+		var (
+			constraint        constraintMetadata
+			columns           interface{}
+			referencedTable   sql.NullString
+			referencedColumns interface{}
+		)
+		if err := rows.Scan(
+			&constraint.kind,
+			&constraint.name,
+			&columns,
+			&referencedTable,
+			&referencedColumns,
+		); err != nil {
+			return nil, err
+		}
+		constraint.columns = textList(columns)
+		constraint.referencedTable = referencedTable.String
+		constraint.referencedColumns = textList(referencedColumns)
+		constraints = append(constraints, constraint)
+	}
+	return constraints, rows.Err()
+}
+
+func (d *DuckDB) GetCollectionStructures(
+	table database.Table,
+) (database.Structures, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return nil, fmt.Errorf("table name is required")
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	constraints, err := d.constraints(table)
+	if err != nil {
+		return nil, err
+	}
+	primary := make(map[string]string)
+	unique := make(map[string]bool)
+	foreign := make(map[string][2]string)
+	for _, constraint := range constraints {
+		switch constraint.kind {
+		case "PRIMARY KEY":
+			for _, column := range constraint.columns {
+				primary[column] = constraint.name
+			}
+		case "UNIQUE":
+			if len(constraint.columns) == 1 {
+				unique[constraint.columns[0]] = true
+			}
+		case "FOREIGN KEY":
+			for index, column := range constraint.columns {
+				if index < len(constraint.referencedColumns) {
+					foreign[column] = [2]string{
+						constraint.referencedTable,
+						constraint.referencedColumns[index],
+					}
+				}
+			}
+		}
+	}
+
+	catalog, schema := d.catalogFor(table.Schema)
+	rows, err := d.conn.Query(`
+		SELECT
+			column_name,
+			data_type,
+			is_nullable,
+			column_default,
+			character_maximum_length,
+			comment
+		FROM duckdb_columns()
+		WHERE database_name = ? AND schema_name = ? AND table_name = ?
+		ORDER BY column_index`,
+		catalog,
+		schema,
+		table.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	structures := make(database.Structures, 0)
+	for rows.Next() {
+		var (
+			name         string
+			dataType     string
+			nullable     bool
+			defaultValue sql.NullString
+			maxLength    sql.NullInt64
+			comment      sql.NullString
+		)
+		if err := rows.Scan(
+			&name,
+			&dataType,
+			&nullable,
+			&defaultValue,
+			&maxLength,
+			&comment,
+		); err != nil {
+			return nil, err
+		}
+		constraintName, isPrimary := primary[name]
+		structure := database.Structure{
+			Name:           name,
+			DataType:       dataType,
+			NativeType:     dataType,
+			Nullable:       nullable,
+			IsPrimary:      isPrimary,
+			IsPrimaryLabel: constraintName,
+			IsUnique:       unique[name],
+		}
+		if defaultValue.Valid {
+			value := defaultValue.String
+			structure.Default = &value
+		}
+		if maxLength.Valid && maxLength.Int64 > 0 {
+			length := int(maxLength.Int64)
+			structure.Length = &length
+		}
+		if comment.Valid && strings.TrimSpace(comment.String) != "" {
+			value := comment.String
+			structure.Comment = &value
+		}
+		if relation, exists := foreign[name]; exists {
+			foreignSchema := table.Schema
+			foreignTable := relation[0]
+			foreignColumn := relation[1]
+			label := fmt.Sprintf(
+				"%s.%s(%s)",
+				foreignSchema,
+				foreignTable,
+				foreignColumn,
+			)
+			structure.ForeignKey = &label
+			structure.ForeignSchema = &foreignSchema
+			structure.ForeignTable = &foreignTable
+			structure.ForeignColumn = &foreignColumn
+		}
+		structures = append(structures, structure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(structures) == 0 {
+		return nil, fmt.Errorf(
+			"DuckDB table %s was not found",
+			quoteQualified(table.Schema, table.Name),
+		)
+	}
+	return structures, nil
+}
+
+// GetIndices reports the primary key and unique constraints, which DuckDB
+// backs with ART indexes, together with indexes created by CREATE INDEX.
+func (d *DuckDB) GetIndices(
+	table database.Table,
+) (database.Indices, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	constraints, err := d.constraints(table)
+	if err != nil {
+		return nil, err
+	}
+	indices := make(database.Indices, 0)
+	for _, constraint := range constraints {
+		if constraint.kind == "FOREIGN KEY" {
+			continue
+		}
+		indices = append(indices, database.Index{
+			Name:      constraint.name,
+			Columns:   constraint.columns,
+			IsUnique:  true,
+			IsPrimary: constraint.kind == "PRIMARY KEY",
+			Algorithm: "ART",
+		})
+	}
+
+	catalog, schema := d.catalogFor(table.Schema)
+	rows, err := d.conn.Query(`
+		SELECT index_name, is_unique, is_primary, expressions
+		FROM duckdb_indexes()
+		WHERE database_name = ? AND schema_name = ? AND table_name = ?
+		ORDER BY index_name`,
+		catalog,
+		schema,
+		table.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name        string
+			isUnique    bool
+			isPrimary   bool
+			expressions sql.NullString
+		)
+		if err := rows.Scan(&name, &isUnique, &isPrimary, &expressions); err != nil {
+			return nil, err
+		}
+		indices = append(indices, database.Index{
+			Name:      name,
+			Columns:   indexColumns(expressions.String),
+			IsUnique:  isUnique,
+			IsPrimary: isPrimary,
+			Algorithm: "ART",
+		})
+	}
+	return indices, rows.Err()
+}
+
+// indexColumns splits the "[a, b]" expression list that duckdb_indexes()
+// reports for CREATE INDEX statements.
+func indexColumns(expressions string) []string {
+	expressions = strings.TrimSuffix(strings.TrimPrefix(expressions, "["), "]")
+	columns := make([]string, 0)
+	for _, expression := range strings.Split(expressions, ",") {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+		if strings.HasPrefix(expression, `"`) && strings.HasSuffix(expression, `"`) {
+			expression = strings.ReplaceAll(expression[1:len(expression)-1], `""`, `"`)
+		}
+		columns = append(columns, expression)
+	}
+	return columns
+}
@@ -0,0 +1,226 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+func objectID(reference database.ObjectReference) string {
+	parts := []string{
+		string(reference.Kind),
+		reference.Schema,
+		reference.Name,
+		reference.ParentName,
+	}
+	for index, part := range parts {
+		parts[index] = base64.RawURLEncoding.EncodeToString([]byte(part))
+	}
+	return "duckdb:" + strings.Join(parts, ".")
+}
+
+type catalogObject struct {
+	object     database.DatabaseObject
+	definition string
+}
+
+// catalogObjectsQuery lists every user object of the primary and attached
+// databases. Constraints are reported by name with the clause DuckDB stored
+// for them.
+const catalogObjectsQuery = `
+	SELECT object.kind, object.database_name, object.schema_name, object.name,
+		object.parent_name, object.definition, object.comment
+	FROM (
+		SELECT 'table' AS kind, database_name, schema_name, table_name AS name,
+			'' AS parent_name, sql AS definition, comment
+		FROM duckdb_tables()
+		WHERE NOT internal
+		UNION ALL
+		SELECT 'view', database_name, schema_name, view_name, '', sql, comment
+		FROM duckdb_views()
+		WHERE NOT internal
+		UNION ALL
+		SELECT 'sequence', database_name, schema_name, sequence_name, '', sql, comment
+		FROM duckdb_sequences()
+		UNION ALL
+		SELECT 'index', database_name, schema_name, index_name, table_name, sql, comment
+		FROM duckdb_indexes()
+		UNION ALL
+		SELECT 'constraint', database_name, schema_name, constraint_name, table_name,
+			constraint_text, NULL
+		FROM duckdb_constraints()
+		WHERE constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY', 'CHECK')
+	) object
+	JOIN duckdb_databases() database_object
+		ON database_object.database_name = object.database_name
+	WHERE NOT database_object.internal
+	ORDER BY object.database_name, object.schema_name, object.kind,
+		object.parent_name, object.name`
+
+func (d *DuckDB) catalogObjects(
+	ctx context.Context,
+	filter database.ObjectFilter,
+) ([]catalogObject, error) {
+	rows, err := d.conn.QueryContext(ctx, catalogObjectsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	allowed := make(map[database.ObjectKind]bool, len(filter.Kinds))
+	for _, kind := range filter.Kinds {
+		allowed[kind] = true
+	}
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+	objects := make([]catalogObject, 0)
+	for rows.Next() {
+		var (
+			kind       string
+			catalog    string
+			schema     string
+			name       string
+			parentName string
+			definition sql.NullString
+			comment    sql.NullString
+		)
+		if err := rows.Scan(
+			&kind,
+			&catalog,
+			&schema,
+			&name,
+			&parentName,
+			&definition,
+			&comment,
+		); err != nil {
+			return nil, err
+		}
+		reference := database.ObjectReference{
+			Kind:       database.ObjectKind(kind),
+			Schema:     schemaReference(catalog, schema, d.catalog),
+			Name:       name,
+			ParentName: parentName,
+		}
+		if parentName != "" {
+			reference.ParentSchema = reference.Schema
+		}
+		if filter.Schema != "" && reference.Schema != filter.Schema {
+			continue
+		}
+		if len(allowed) > 0 && !allowed[reference.Kind] {
+			continue
+		}
+		if search != "" && !strings.Contains(
+			strings.ToLower(name+" "+parentName+" "+comment.String),
+			search,
+		) {
+			continue
+		}
+		reference.ID = objectID(reference)
+		properties := []database.ObjectProperty{
+			{Name: "Database", Value: catalog, Category: "identity"},
+			{Name: "Schema", Value: schema, Category: "identity"},
+		}
+		if parentName != "" {
+			properties = append(properties, database.ObjectProperty{
+				Name:     "Table",
+				Value:    parentName,
+				Category: "identity",
+			})
+		}
+		objects = append(objects, catalogObject{
+			object: database.DatabaseObject{
+				Reference:   reference,
+				DisplayName: name,
+				Description: comment.String,
+				CanOpenData: reference.Kind == database.ObjectKindTable ||
+					reference.Kind == database.ObjectKindView,
+				Properties: properties,
+			},
+			definition: strings.TrimSpace(definition.String),
+		})
+	}
+	return objects, rows.Err()
+}
+
+func (d *DuckDB) ListObjects(
+	ctx context.Context,
+	filter database.ObjectFilter,
+) ([]database.DatabaseObject, error) {
+	if err := d.ensureConnected(); err != nil {
+		return nil, err
+	}
+	entries, err := d.catalogObjects(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]database.DatabaseObject, 0, len(entries))
+	for _, entry := range entries {
+		objects = append(objects, entry.object)
+	}
+	sort.SliceStable(objects, func(left, right int) bool {
+		if objects[left].Reference.Schema != objects[right].Reference.Schema {
+			return objects[left].Reference.Schema < objects[right].Reference.Schema
+		}
+		if objects[left].Reference.Kind != objects[right].Reference.Kind {
+			return objects[left].Reference.Kind < objects[right].Reference.Kind
+		}
+		return objects[left].Reference.Name < objects[right].Reference.Name
+	})
+	return objects, nil
+}
+
+func (d *DuckDB) GetObjectDetail(
+	ctx context.Context,
+	reference database.ObjectReference,
+) (database.ObjectDetail, error) {
+	if err := d.ensureConnected(); err != nil {
+		return database.ObjectDetail{}, err
+	}
+	filter := database.ObjectFilter{Schema: reference.Schema}
+	if reference.Kind != database.ObjectKindUnknown {
+		filter.Kinds = []database.ObjectKind{reference.Kind}
+	}
+	entries, err := d.catalogObjects(ctx, filter)
+	if err != nil {
+		return database.ObjectDetail{}, err
+	}
+	for _, entry := range entries {
+		candidate := entry.object.Reference
+		if reference.ID != "" && candidate.ID != reference.ID {
+			continue
+		}
+		if reference.ID == "" && (candidate.Name != reference.Name ||
+			(reference.ParentName != "" && candidate.ParentName != reference.ParentName)) {
+			continue
+		}
+		detail := database.ObjectDetail{
+			Object:     entry.object,
+			Definition: entry.definition,
+			Comment:    entry.object.Description,
+			Properties: entry.object.Properties,
+		}
+		if candidate.Kind == database.ObjectKindTable ||
+			candidate.Kind == database.ObjectKindView {
+			detail.Columns, err = d.GetCollectionStructures(database.Table{
+				Schema: candidate.Schema,
+				Name:   candidate.Name,
+			})
+			if err != nil {
+				return database.ObjectDetail{}, err
+			}
+		}
+		return detail, nil
+	}
+	return database.ObjectDetail{}, fmt.Errorf(
+		"DuckDB object %s was not found",
+		reference.QualifiedName(),
+	)
+}
+
+var _ database.ObjectDriver = (*DuckDB)(nil)
@@ -0,0 +1,35 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+// DuckDB links its engine through cgo, so the driver files build only with
+// cgo enabled. CGO_ENABLED=0 builds leave the engine unregistered and keep
+// the data-file helpers in files.go.
+func init() {
+	database.RegisterDriver(database.DriverDuckDB, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewDuckDB(ctx, Config{Db: cfg.Db}), nil
+}
+
+// probe reports backups as unavailable: a DuckDB file is locked by the
+// process that opens it, so copying it needs every connection closed first.
+func probe(database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*DuckDB)(nil).Capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available: false,
+			Engine:    capabilities.Engine,
+			Message: "DuckDB backups are not built in yet. Disconnect and copy " +
+				"the database file, or use EXPORT DATABASE from the SQL editor.",
+		},
+	}
+}
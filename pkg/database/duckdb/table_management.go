@@ -0,0 +1,167 @@
+//go:build cgo
+
+package duckdb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+func (d *DuckDB) CreateTable(
+	table database.Table,
+	columns []database.ColumnDefinition,
+) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("at least one column is required")
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	definitions := make([]string, 0, len(columns)+1)
+	primaryKeys := make([]string, 0)
+	for _, column := range columns {
+		name := strings.TrimSpace(column.Name)
+		dataType := strings.TrimSpace(column.Type)
+		if name == "" {
+			continue
+		}
+		if dataType == "" {
+			return fmt.Errorf("data type is required for column %q", name)
+		}
+		if err := database.ValidateDDLFragment(
+			dataType,
+			"column data type",
+		); err != nil {
+			return err
+		}
+		definition := quoteIdentifier(name) + " " + dataType
+		if !column.Nullable {
+			definition += " NOT NULL"
+		}
+		if strings.TrimSpace(column.Default) != "" {
+			if err := database.ValidateDDLFragment(
+				column.Default,
+				"column default",
+			); err != nil {
+				return err
+			}
+			definition += " DEFAULT " + strings.TrimSpace(column.Default)
+		}
+		if column.Unique {
+			definition += " UNIQUE"
+		}
+		if column.PrimaryKey {
+			primaryKeys = append(primaryKeys, quoteIdentifier(name))
+		}
+		definitions = append(definitions, definition)
+	}
+	if len(definitions) == 0 {
+		return fmt.Errorf("at least one named column is required")
+	}
+	if len(primaryKeys) > 0 {
+		definitions = append(
+			definitions,
+			"PRIMARY KEY ("+strings.Join(primaryKeys, ", ")+")",
+		)
+	}
+	_, err := d.conn.Exec(
+		"CREATE TABLE " + quoteQualified(table.Schema, table.Name) +
+			" (" + strings.Join(definitions, ", ") + ")",
+	)
+	return err
+}
+
+func (d *DuckDB) DropTable(table database.Table) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	_, err := d.conn.Exec(
+		"DROP TABLE IF EXISTS " + quoteQualified(table.Schema, table.Name),
+	)
+	return err
+}
+
+func (d *DuckDB) TruncateTable(table database.Table) error {
+	if err := d.ensureConnected(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return fmt.Errorf("table name is required")
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	_, err := d.conn.Exec(
+		"TRUNCATE " + quoteQualified(table.Schema, table.Name),
+	)
+	return err
+}
+
+// GetTableDDL returns the CREATE statement DuckDB stores in its catalog. It
+// names the table without its catalog, like the statement the user ran.
+func (d *DuckDB) GetTableDDL(table database.Table) (string, error) {
+	if err := d.ensureConnected(); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(table.Name) == "" {
+		return "", fmt.Errorf("table name is required")
+	}
+	table.Schema = d.defaultSchema(table.Schema)
+	catalog, schema := d.catalogFor(table.Schema)
+	var definition sql.NullString
+	err := d.conn.QueryRow(`
+		SELECT sql
+		FROM duckdb_tables()
+		WHERE database_name = ? AND schema_name = ? AND table_name = ?`,
+		catalog,
+		schema,
+		table.Name,
+	).Scan(&definition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("DuckDB table %q was not found", table.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !definition.Valid || strings.TrimSpace(definition.String) == "" {
+		return "", fmt.Errorf("DuckDB table %q has no stored DDL", table.Name)
+	}
+	return strings.TrimSpace(definition.String), nil
+}
+
+func (d *DuckDB) GetDataTypes() []database.DataType {
+	return []database.DataType{
+		{Name: "BOOLEAN", Category: "Boolean", Description: "True or false"},
+		{Name: "TINYINT", Category: "Numeric", Description: "8-bit integer"},
+		{Name: "SMALLINT", Category: "Numeric", Description: "16-bit integer"},
+		{Name: "INTEGER", Category: "Numeric", Description: "32-bit integer"},
+		{Name: "BIGINT", Category: "Numeric", Description: "64-bit integer"},
+		{Name: "HUGEINT", Category: "Numeric", Description: "128-bit integer"},
+		{Name: "UBIGINT", Category: "Numeric", Description: "Unsigned 64-bit integer"},
+		{Name: "DECIMAL", Category: "Numeric", Description: "Exact fixed-point number"},
+		{Name: "REAL", Category: "Numeric", Description: "Single-precision number"},
+		{Name: "DOUBLE", Category: "Numeric", Description: "Double-precision number"},
+		{Name: "VARCHAR", Category: "Character", Description: "Variable-length UTF-8 text"},
+		{Name: "BLOB", Category: "Binary", Description: "Variable-length bytes"},
+		{Name: "DATE", Category: "Date/Time", Description: "Calendar date"},
+		{Name: "TIME", Category: "Date/Time", Description: "Time of day"},
+		{Name: "TIMESTAMP", Category: "Date/Time", Description: "Date and time"},
+		{Name: "TIMESTAMPTZ", Category: "Date/Time", Description: "Date and time with time zone"},
+		{Name: "INTERVAL", Category: "Date/Time", Description: "Time span"},
+		{Name: "UUID", Category: "Identifier", Description: "Universally unique identifier"},
+		{Name: "JSON", Category: "Structured", Description: "JSON document"},
+		{Name: "LIST", Category: "Structured", Description: "Variable-length list, written as TYPE[]"},
+		{Name: "STRUCT", Category: "Structured", Description: "Named fields"},
+		{Name: "MAP", Category: "Structured", Description: "Key-value pairs"},
+	}
+}
@@ -22,9 +22,12 @@ import (
 type ExportFormat string
 
 const (
-	ExportFormatCSV  ExportFormat = "csv"
-	ExportFormatJSON ExportFormat = "json"
-	ExportFormatSQL  ExportFormat = "sql"
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatJSON    ExportFormat = "json"
+	ExportFormatSQL     ExportFormat = "sql"
+	ExportFormatParquet ExportFormat = "parquet"
+	ExportFormatXLSX    ExportFormat = "xlsx"
+	ExportFormatNDJSON  ExportFormat = "ndjson"
 )
 
 type ExportScope string
@@ -73,10 +76,12 @@ func (options SQLInsertOptions) EffectiveBatchSize() int {
 }
 
 type ExportOptions struct {
-	Format ExportFormat     `json:"format"`
-	CSV    CSVOptions       `json:"csv"`
-	JSON   JSONOptions      `json:"json"`
-	SQL    SQLInsertOptions `json:"sql"`
+	Format  ExportFormat     `json:"format"`
+	CSV     CSVOptions       `json:"csv"`
+	JSON    JSONOptions      `json:"json"`
+	SQL     SQLInsertOptions `json:"sql"`
+	Parquet ParquetOptions   `json:"parquet"`
+	XLSX    XLSXOptions      `json:"xlsx"`
 }
 
 type TableExportRequest struct {
@@ -89,9 +94,15 @@ type TableExportRequest struct {
 	Options            ExportOptions `json:"options"`
 }
 
+// RowsExportRequest exports rows the caller already holds. With CursorID
+// set, the rows are followed by everything that open result cursor has not
+// delivered yet, and the cursor is closed by the export. Structures describes
+// the result columns for formats that carry a schema.
 type RowsExportRequest struct {
 	Columns       []string                 `json:"columns"`
+	Structures    Structures               `json:"structures,omitempty"`
 	Rows          []map[string]interface{} `json:"rows"`
+	CursorID      string                   `json:"cursorId,omitempty"`
 	JobID         string                   `json:"jobId"`
 	ExpectedRows  int64                    `json:"expectedRows"`
 	SuggestedName string                   `json:"suggestedName"`
@@ -108,6 +119,7 @@ type ExportResult struct {
 	Bytes     int64        `json:"bytes"`
 	Cancelled bool         `json:"cancelled"`
 	Format    ExportFormat `json:"format"`
+	Tables    int          `json:"tables,omitempty"`
 }
 
 type ExportProgress struct {
@@ -118,6 +130,11 @@ type ExportProgress struct {
 	TotalRows   int64  `json:"totalRows"`
 	ElapsedMS   int64  `json:"elapsedMs"`
 	Cancellable bool   `json:"cancellable"`
+	// Table, TableIndex, and TableCount describe the table an archive export
+	// is writing; TableIndex counts from 1.
+	Table      string `json:"table,omitempty"`
+	TableIndex int    `json:"tableIndex,omitempty"`
+	TableCount int    `json:"tableCount,omitempty"`
 }
 
 type RowStream interface {
@@ -169,7 +186,7 @@ func ValidateExportOptions(options ExportOptions) error {
 		}
 		_, err := normalizeCSVEncoding(options.CSV.Encoding)
 		return err
-	case ExportFormatJSON:
+	case ExportFormatJSON, ExportFormatNDJSON, ExportFormatXLSX:
 		return nil
 	case ExportFormatSQL:
 		if options.SQL.BatchSize < 0 || options.SQL.BatchSize > MaxSQLInsertBatchSize {
@@ -179,6 +196,9 @@ func ValidateExportOptions(options ExportOptions) error {
 			)
 		}
 		return nil
+	case ExportFormatParquet:
+		_, err := parquetCodec(options.Parquet.Compression)
+		return err
 	default:
 		return fmt.Errorf("unsupported export format %q", options.Format)
 	}
@@ -543,9 +563,12 @@ func normalizeJSONValue(value interface{}) (interface{}, error) {
 	}
 }
 
+// jsonSink writes one JSON array, or with lines set one compact object per
+// line for NDJSON consumers.
 type jsonSink struct {
 	writer *bufio.Writer
 	pretty bool
+	lines  bool
 	rows   int64
 }
 
@@ -560,6 +583,10 @@ func newJSONSink(writer io.Writer, options JSONOptions) (*jsonSink, error) {
 	return sink, nil
 }
 
+func newNDJSONSink(writer io.Writer) *jsonSink {
+	return &jsonSink{writer: bufio.NewWriter(writer), lines: true}
+}
+
 func (s *jsonSink) writeString(value string) error {
 	written, err := s.writer.WriteString(value)
 	if err != nil {
@@ -584,6 +611,17 @@ func (s *jsonSink) writeBytes(value []byte) error {
 
 func (s *jsonSink) writeValues(columns []string, values []interface{}) error {
 	row := orderedJSONRow{columns: columns, values: values}
+	if s.lines {
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if err := s.writeBytes(append(encoded, '\n')); err != nil {
+			return err
+		}
+		s.rows++
+		return nil
+	}
 	var (
 		encoded []byte
 		err     error
@@ -625,6 +663,9 @@ func (s *jsonSink) writeValues(columns []string, values []interface{}) error {
 }
 
 func (s *jsonSink) close() error {
+	if s.lines {
+		return s.writer.Flush()
+	}
 	if s.pretty && s.rows > 0 {
 		if err := s.writeString("\n"); err != nil {
 			return err
@@ -658,6 +699,33 @@ func WriteJSONStreamContext(
 	if err != nil {
 		return ExportStats{}, fmt.Errorf("start JSON export: %w", err)
 	}
+	return writeJSONSinkStream(ctx, sink, columns, rows)
+}
+
+// WriteNDJSONStreamContext writes one JSON object per line. Values follow
+// the JSON export rules, so dates use ISO 8601 and binary values carry a
+// base64: prefix.
+func WriteNDJSONStreamContext(
+	ctx context.Context,
+	writer io.Writer,
+	rows RowStream,
+) (ExportStats, error) {
+	if err := CheckExportContext(ctx); err != nil {
+		return ExportStats{}, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return ExportStats{}, fmt.Errorf("read export columns: %w", err)
+	}
+	return writeJSONSinkStream(ctx, newNDJSONSink(writer), columns, rows)
+}
+
+func writeJSONSinkStream(
+	ctx context.Context,
+	sink *jsonSink,
+	columns []string,
+	rows RowStream,
+) (ExportStats, error) {
 	for rows.Next() {
 		if err := CheckExportContext(ctx); err != nil {
 			return ExportStats{}, err
@@ -742,6 +810,18 @@ func WriteExportStreamContext(
 	writer io.Writer,
 	rows RowStream,
 	options ExportOptions,
+) (ExportStats, error) {
+	return WriteTypedExportStreamContext(ctx, writer, rows, nil, options)
+}
+
+// WriteTypedExportStreamContext is WriteExportStreamContext for a source
+// whose column types are known. Only formats that carry a schema use them.
+func WriteTypedExportStreamContext(
+	ctx context.Context,
+	writer io.Writer,
+	rows RowStream,
+	structures Structures,
+	options ExportOptions,
 ) (ExportStats, error) {
 	if err := ValidateExportOptions(options); err != nil {
 		return ExportStats{}, err
@@ -752,6 +832,12 @@ func WriteExportStreamContext(
 		return WriteCSVStreamContext(ctx, writer, rows, options.CSV)
 	case ExportFormatJSON:
 		return WriteJSONStreamContext(ctx, writer, rows, options.JSON)
+	case ExportFormatNDJSON:
+		return WriteNDJSONStreamContext(ctx, writer, rows)
+	case ExportFormatParquet:
+		return WriteParquetStreamContext(ctx, writer, rows, structures, options.Parquet)
+	case ExportFormatXLSX:
+		return WriteXLSXStreamContext(ctx, writer, rows, structures, options.XLSX)
 	case ExportFormatSQL:
 		return ExportStats{}, fmt.Errorf(
 			"SQL INSERT export requires a driver-specific table serializer",
@@ -776,6 +862,19 @@ func WriteExportRowsContext(
 	columns []string,
 	rows []map[string]interface{},
 	options ExportOptions,
+) (ExportStats, error) {
+	return WriteTypedExportRowsContext(ctx, writer, columns, rows, nil, options)
+}
+
+// WriteTypedExportRowsContext is WriteExportRowsContext for rows whose
+// column types are known.
+func WriteTypedExportRowsContext(
+	ctx context.Context,
+	writer io.Writer,
+	columns []string,
+	rows []map[string]interface{},
+	structures Structures,
+	options ExportOptions,
 ) (ExportStats, error) {
 	if err := ValidateExportOptions(options); err != nil {
 		return ExportStats{}, err
@@ -786,6 +885,17 @@ func WriteExportRowsContext(
 		return WriteCSVRowsContext(ctx, writer, columns, rows, options.CSV)
 	case ExportFormatJSON:
 		return WriteJSONRowsContext(ctx, writer, columns, rows, options.JSON)
+	case ExportFormatNDJSON, ExportFormatParquet, ExportFormatXLSX:
+		if len(columns) == 0 && len(rows) > 0 {
+			return ExportStats{}, fmt.Errorf("query result columns are required")
+		}
+		return WriteTypedExportStreamContext(
+			ctx,
+			writer,
+			&heldRows{columns: columns, rows: rows},
+			structures,
+			options,
+		)
 	case ExportFormatSQL:
 		return ExportStats{}, fmt.Errorf("SQL INSERT export requires a table source")
 	default:
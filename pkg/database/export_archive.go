@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+type ExportArchiveFormat string
+
+const (
+	ExportArchiveZip     ExportArchiveFormat = "zip"
+	ExportArchiveTarZstd ExportArchiveFormat = "tar.zst"
+)
+
+// ExportArchiveManifestPath is written last, once every table's row count is
+// known.
+const ExportArchiveManifestPath = "manifest.json"
+
+// ArchiveExportRequest exports several tables into one archive. Tables lists
+// them explicitly; when it is empty every table in Schema is exported, or in
+// the driver's default namespace when Schema is empty too.
+type ArchiveExportRequest struct {
+	Tables        []Table             `json:"tables,omitempty"`
+	Schema        string              `json:"schema,omitempty"`
+	Archive       ExportArchiveFormat `json:"archive"`
+	IncludeDDL    bool                `json:"includeDdl"`
+	JobID         string              `json:"jobId"`
+	SuggestedName string              `json:"suggestedName"`
+	Options       ExportOptions       `json:"options"`
+}
+
+type ExportArchiveManifest struct {
+	Engine    string                       `json:"engine"`
+	Format    ExportFormat                 `json:"format"`
+	CreatedAt time.Time                    `json:"createdAt"`
+	Tables    []ExportArchiveManifestTable `json:"tables"`
+}
+
+type ExportArchiveManifestTable struct {
+	Schema string `json:"schema,omitempty"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Rows   int64  `json:"rows"`
+	DDL    string `json:"ddl,omitempty"`
+}
+
+func ValidateArchiveExportRequest(request ArchiveExportRequest) error {
+	switch request.Archive {
+	case ExportArchiveZip, ExportArchiveTarZstd:
+	default:
+		return fmt.Errorf("unsupported export archive %q", request.Archive)
+	}
+	for _, table := range request.Tables {
+		if table.Name == "" {
+			return fmt.Errorf("every archived table needs a name")
+		}
+	}
+	return ValidateExportOptions(request.Options)
+}
@@ -292,6 +292,27 @@ func TestWriteJSONRowsSupportsCompactAndEmptyDocuments(t *testing.T) {
 	}
 }
 
+func TestWriteNDJSONWritesOneCompactObjectPerLine(t *testing.T) {
+	var output bytes.Buffer
+	stats, err := WriteExportRows(
+		&output,
+		[]string{"id", "payload", "at"},
+		[]map[string]interface{}{
+			{"id": 1, "payload": []byte{0xff, 0x00}, "at": time.Date(2026, time.May, 1, 9, 30, 0, 0, time.UTC)},
+			{"id": 2, "payload": nil, "at": nil},
+		},
+		ExportOptions{Format: ExportFormatNDJSON, JSON: JSONOptions{Pretty: true}},
+	)
+	if err != nil {
+		t.Fatalf("WriteExportRows() error = %v", err)
+	}
+	want := "{\"id\":1,\"payload\":\"base64:/wA=\",\"at\":\"2026-05-01T09:30:00Z\"}\n" +
+		"{\"id\":2,\"payload\":null,\"at\":null}\n"
+	if stats.Rows != 2 || output.String() != want {
+		t.Fatalf("NDJSON = %q, rows = %d", output.String(), stats.Rows)
+	}
+}
+
 func TestWriteJSONStreamPropagatesRowErrors(t *testing.T) {
 	if _, err := WriteJSONStream(
 		&bytes.Buffer{},
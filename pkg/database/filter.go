@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -15,12 +16,30 @@ const (
 	FilterGreaterEqual FilterOperator = "gte"
 	FilterLessEqual    FilterOperator = "lte"
 	FilterContains     FilterOperator = "contains"
+	FilterNotContains  FilterOperator = "not_contains"
+	FilterStartsWith   FilterOperator = "starts_with"
+	FilterEndsWith     FilterOperator = "ends_with"
+	FilterILike        FilterOperator = "ilike"
+	FilterRegex        FilterOperator = "regex"
+	FilterIn           FilterOperator = "in"
+	FilterNotIn        FilterOperator = "not_in"
+	FilterBetween      FilterOperator = "between"
 	FilterIsNull       FilterOperator = "is_null"
 	FilterIsNotNull    FilterOperator = "is_not_null"
 )
 
+// MaxFilterListValues bounds IN and NOT IN lists so a single filter stays
+// well inside every engine's bind parameter limit.
+const MaxFilterListValues = 1000
+
+// MaxFilterDepth is how deeply filter groups may nest.
+const MaxFilterDepth = 8
+
+// Filter compares a column, or with Path set the JSON value at that path
+// inside the column, against Value.
 type Filter struct {
 	Column   string
+	Path     string `json:",omitempty"`
 	Operator FilterOperator
 	Value    interface{}
 }
@@ -29,6 +48,11 @@ func (filter Filter) Validate() error {
 	if strings.TrimSpace(filter.Column) == "" {
 		return fmt.Errorf("filter column cannot be empty")
 	}
+	if filter.Path != "" {
+		if _, err := ParseJSONPath(filter.Path); err != nil {
+			return err
+		}
+	}
 
 	switch filter.Operator {
 	case FilterEqual,
@@ -37,13 +61,41 @@ func (filter Filter) Validate() error {
 		FilterLessThan,
 		FilterGreaterEqual,
 		FilterLessEqual,
-		FilterContains:
+		FilterContains,
+		FilterNotContains,
+		FilterStartsWith,
+		FilterEndsWith,
+		FilterILike,
+		FilterRegex:
 		if filter.Value == nil {
 			return fmt.Errorf(
 				"filter %q requires a value",
 				filter.Operator,
 			)
 		}
+	case FilterIn, FilterNotIn:
+		values, ok := filter.Values()
+		if !ok || len(values) == 0 {
+			return fmt.Errorf(
+				"filter %q requires a list of values",
+				filter.Operator,
+			)
+		}
+		if len(values) > MaxFilterListValues {
+			return fmt.Errorf(
+				"filter %q accepts at most %d values",
+				filter.Operator,
+				MaxFilterListValues,
+			)
+		}
+	case FilterBetween:
+		values, ok := filter.Values()
+		if !ok || len(values) != 2 || values[0] == nil || values[1] == nil {
+			return fmt.Errorf(
+				"filter %q requires a lower and an upper bound",
+				filter.Operator,
+			)
+		}
 	case FilterIsNull, FilterIsNotNull:
 	default:
 		return fmt.Errorf("unsupported filter operator %q", filter.Operator)
@@ -51,3 +103,125 @@ func (filter Filter) Validate() error {
 
 	return nil
 }
+
+// Values returns the list held by an in, not_in or between filter. The
+// value arrives as []interface{} from JSON, but Go callers may pass any
+// slice.
+func (filter Filter) Values() ([]interface{}, bool) {
+	if values, ok := filter.Value.([]interface{}); ok {
+		return values, true
+	}
+	if _, ok := filter.Value.([]byte); ok {
+		return nil, false
+	}
+	value := reflect.ValueOf(filter.Value)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]interface{}, value.Len())
+	for index := range values {
+		values[index] = value.Index(index).Interface()
+	}
+	return values, true
+}
+
+// LikePattern returns the LIKE argument for the pattern operators. An
+// ilike value is already a pattern and is passed through unchanged.
+func (filter Filter) LikePattern() string {
+	value := fmt.Sprint(filter.Value)
+	switch filter.Operator {
+	case FilterStartsWith:
+		return value + "%"
+	case FilterEndsWith:
+		return "%" + value
+	case FilterContains, FilterNotContains:
+		return "%" + value + "%"
+	default:
+		return value
+	}
+}
+
+type FilterLogic string
+
+const (
+	FilterAnd FilterLogic = "and"
+	FilterOr  FilterLogic = "or"
+)
+
+// FilterGroup joins its filters and nested groups with a single logic
+// operator. An empty Logic means AND, which is how Table.Filters applies.
+type FilterGroup struct {
+	Logic   FilterLogic
+	Filters []Filter
+	Groups  []FilterGroup
+}
+
+func (group FilterGroup) Validate() error {
+	return group.validate(1)
+}
+
+func (group FilterGroup) validate(depth int) error {
+	if depth > MaxFilterDepth {
+		return fmt.Errorf("filter groups cannot nest deeper than %d levels", MaxFilterDepth)
+	}
+	switch group.Logic {
+	case "", FilterAnd, FilterOr:
+	default:
+		return fmt.Errorf("unsupported filter logic %q", group.Logic)
+	}
+	for _, filter := range group.Filters {
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, child := range group.Groups {
+		if err := child.validate(depth + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render validates the tree and writes it as one SQL boolean expression.
+// condition renders a single filter and is called in tree order, so
+// drivers that number their placeholders can count as they go. A tree
+// without any filters renders as an empty string.
+func (group FilterGroup) Render(
+	condition func(Filter) (string, error),
+) (string, error) {
+	if err := group.Validate(); err != nil {
+		return "", err
+	}
+	return group.render(condition)
+}
+
+func (group FilterGroup) render(
+	condition func(Filter) (string, error),
+) (string, error) {
+	parts := make([]string, 0, len(group.Filters)+len(group.Groups))
+	for _, filter := range group.Filters {
+		part, err := condition(filter)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	for _, child := range group.Groups {
+		part, err := child.render(condition)
+		if err != nil {
+			return "", err
+		}
+		if part == "" {
+			continue
+		}
+		if len(child.Filters)+len(child.Groups) > 1 {
+			part = "(" + part + ")"
+		}
+		parts = append(parts, part)
+	}
+	separator := " AND "
+	if group.Logic == FilterOr {
+		separator = " OR "
+	}
+	return strings.Join(parts, separator), nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterValidateRequiresListsForListOperators(t *testing.T) {
+	for _, filter := range []Filter{
+		{Column: "id", Operator: FilterIn, Value: "1,2"},
+		{Column: "id", Operator: FilterIn, Value: []interface{}{}},
+		{Column: "id", Operator: FilterBetween, Value: []interface{}{1}},
+		{Column: "id", Operator: FilterBetween, Value: []interface{}{1, nil}},
+	} {
+		if err := filter.Validate(); err == nil {
+			t.Fatalf("Validate(%+v) accepted the filter", filter)
+		}
+	}
+	valid := Filter{Column: "id", Operator: FilterNotIn, Value: []int{1, 2}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate(%+v) error = %v", valid, err)
+	}
+}
+
+func TestFilterGroupRejectsUnknownLogicAndDeepNesting(t *testing.T) {
+	if err := (FilterGroup{Logic: "xor"}).Validate(); err == nil {
+		t.Fatal("Validate() accepted unknown logic")
+	}
+
+	group := FilterGroup{Filters: []Filter{{Column: "id", Operator: FilterIsNull}}}
+	for range MaxFilterDepth {
+		group = FilterGroup{Groups: []FilterGroup{group}}
+	}
+	err := group.Validate()
+	if err == nil || !strings.Contains(err.Error(), "nest") {
+		t.Fatalf("Validate() error = %v, want nesting error", err)
+	}
+}
+
+func TestFilterGroupRenderSkipsEmptyGroups(t *testing.T) {
+	rendered, err := (FilterGroup{
+		Logic:  FilterOr,
+		Groups: []FilterGroup{{}, {Filters: []Filter{{Column: "id", Operator: FilterIsNull}}}},
+	}).Render(func(filter Filter) (string, error) {
+		return filter.Column + " IS NULL", nil
+	})
+	if err != nil || rendered != "id IS NULL" {
+		t.Fatalf("Render() = %q, %v", rendered, err)
+	}
+}
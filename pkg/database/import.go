@@ -1,5 +1,10 @@
 package database
 
+import (
+	"context"
+	"errors"
+)
+
 const DefaultImportPreviewRows = 50
 
 type ImportFileSelection struct {
@@ -14,6 +19,9 @@ type ImportOptions struct {
 	Delimiter   string `json:"delimiter,omitempty"`
 	Header      bool   `json:"header"`
 	EmptyAsNull bool   `json:"emptyAsNull"`
+	// Sheet names the worksheet to read from an Excel workbook. The first
+	// worksheet is used when it is empty.
+	Sheet string `json:"sheet,omitempty"`
 }
 
 type ImportPreviewRequest struct {
@@ -35,6 +43,7 @@ type ImportPreview struct {
 	Columns []ImportColumn           `json:"columns"`
 	Rows    []map[string]interface{} `json:"rows"`
 	Sampled int                      `json:"sampled"`
+	Sheets  []string                 `json:"sheets,omitempty"`
 }
 
 type ImportRequest struct {
@@ -53,4 +62,37 @@ type ImportResult struct {
 	RowsInserted int      `json:"rowsInserted"`
 	TableCreated bool     `json:"tableCreated"`
 	Warnings     []string `json:"warnings"`
+	// BulkLoaded reports that the rows went through the engine's bulk-load
+	// path rather than batched INSERT statements.
+	BulkLoaded bool `json:"bulkLoaded,omitempty"`
+}
+
+// ErrBulkLoadUnavailable is returned by BulkLoad, before any row is read,
+// when the server refuses the fast path. The import then falls back to
+// INSERT statements in the same transaction.
+var ErrBulkLoadUnavailable = errors.New("bulk load is not available on this connection")
+
+// BulkLoadColumn names a target column and the import type its values were
+// coerced to, such as "integer", "date", or "text".
+type BulkLoadColumn struct {
+	Name string
+	Type string
+}
+
+// BulkLoadRows yields coerced rows in column order and returns io.EOF after
+// the last one.
+type BulkLoadRows interface {
+	Next() ([]interface{}, error)
+}
+
+// BulkLoader is implemented by transactions that can load many rows faster
+// than batched INSERT statements, such as PostgreSQL COPY or SQL Server bulk
+// copy. It returns the number of rows written.
+type BulkLoader interface {
+	BulkLoad(
+		ctx context.Context,
+		table Table,
+		columns []BulkLoadColumn,
+		rows BulkLoadRows,
+	) (int64, error)
 }
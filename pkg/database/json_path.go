@@ -0,0 +1,146 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxJSONPathDepth bounds how many keys and indexes a JSON path may hold.
+const MaxJSONPathDepth = 32
+
+// JSONPath addresses a value inside a JSON column, written as dotted keys
+// with bracketed array indexes: customer.id or items[0].sku. Keys cannot
+// contain quotes, backslashes, dots, brackets or control characters, so the
+// rendered path is always a well-formed JSON path literal.
+type JSONPath []JSONPathSegment
+
+// JSONPathSegment is an object key or, when Key is empty, an array index.
+type JSONPathSegment struct {
+	Key   string
+	Index int
+}
+
+func ParseJSONPath(path string) (JSONPath, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$.")
+	if rest == "" {
+		return nil, fmt.Errorf("JSON path cannot be empty")
+	}
+	parsed := make(JSONPath, 0, 4)
+	for rest != "" {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path %q has an unclosed index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 || rest[1] == '+' {
+				return nil, fmt.Errorf("JSON path %q has an invalid array index", path)
+			}
+			parsed = append(parsed, JSONPathSegment{Index: index})
+			rest = rest[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("JSON path %q has an empty key", path)
+			}
+			if strings.ContainsAny(key, `"'\]`) || strings.IndexFunc(key, isControlRune) >= 0 {
+				return nil, fmt.Errorf("JSON path key %q contains unsupported characters", key)
+			}
+			parsed = append(parsed, JSONPathSegment{Key: key})
+			rest = rest[end:]
+		}
+		if len(parsed) > MaxJSONPathDepth {
+			return nil, fmt.Errorf("JSON path %q is deeper than %d levels", path, MaxJSONPathDepth)
+		}
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("JSON path %q has an empty key", path)
+			}
+		} else if rest != "" && rest[0] != '[' {
+			return nil, fmt.Errorf("JSON path %q is malformed", path)
+		}
+	}
+	return parsed, nil
+}
+
+func isControlRune(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// String renders the path in the SQL/JSON path syntax shared by MySQL,
+// SQLite, SQL Server, Oracle and DuckDB, with every key quoted.
+func (path JSONPath) String() string {
+	var builder strings.Builder
+	builder.WriteString("$")
+	for _, segment := range path {
+		if segment.Key == "" {
+			builder.WriteString("[" + strconv.Itoa(segment.Index) + "]")
+			continue
+		}
+		builder.WriteString(`."` + segment.Key + `"`)
+	}
+	return builder.String()
+}
+
+// Elements returns the path as the text array PostgreSQL's #> and #>>
+// operators take, where array indexes are written as numbers.
+func (path JSONPath) Elements() []string {
+	elements := make([]string, len(path))
+	for index, segment := range path {
+		if segment.Key == "" {
+			elements[index] = strconv.Itoa(segment.Index)
+		} else {
+			elements[index] = segment.Key
+		}
+	}
+	return elements
+}
+
+// HasIndexes reports whether any segment addresses an array element.
+func (path JSONPath) HasIndexes() bool {
+	for _, segment := range path {
+		if segment.Key == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsJSONNumber reports whether a filter value should be compared with the
+// JSON value as a number rather than as text.
+func IsJSONNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, json.Number:
+		return true
+	}
+	return false
+}
+
+// NumericJSONFilter reports whether every value of a JSON path filter is a
+// number. Pattern operators always compare text.
+func (filter Filter) NumericJSONFilter() bool {
+	switch filter.Operator {
+	case FilterEqual, FilterNotEqual,
+		FilterGreaterThan, FilterLessThan,
+		FilterGreaterEqual, FilterLessEqual:
+		return IsJSONNumber(filter.Value)
+	case FilterIn, FilterNotIn, FilterBetween:
+		values, _ := filter.Values()
+		for _, value := range values {
+			if !IsJSONNumber(value) {
+				return false
+			}
+		}
+		return len(values) > 0
+	}
+	return false
+}
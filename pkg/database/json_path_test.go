@@ -0,0 +1,63 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONPathRendersQuotedKeysAndIndexes(t *testing.T) {
+	for _, input := range []string{"customer.items[2].sku", "$.customer.items[2].sku"} {
+		path, err := ParseJSONPath(input)
+		if err != nil {
+			t.Fatalf("ParseJSONPath(%q) error = %v", input, err)
+		}
+		if rendered := path.String(); rendered != `$."customer"."items"[2]."sku"` {
+			t.Fatalf("String() = %q", rendered)
+		}
+		if elements := path.Elements(); !reflect.DeepEqual(elements, []string{"customer", "items", "2", "sku"}) {
+			t.Fatalf("Elements() = %v", elements)
+		}
+		if !path.HasIndexes() {
+			t.Fatal("HasIndexes() = false")
+		}
+	}
+}
+
+func TestParseJSONPathRejectsUnsafeOrMalformedPaths(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"$.",
+		"a..b",
+		"a.",
+		"a[",
+		"a[-1]",
+		"a[x]",
+		"a[1]b",
+		`a"b`,
+		"a'b",
+		`a\b`,
+		"a]b",
+		"a\nb",
+	} {
+		if _, err := ParseJSONPath(input); err == nil {
+			t.Fatalf("ParseJSONPath(%q) accepted the path", input)
+		}
+	}
+}
+
+func TestNumericJSONFilterFollowsTheValueType(t *testing.T) {
+	for _, test := range []struct {
+		filter Filter
+		want   bool
+	}{
+		{Filter{Operator: FilterGreaterThan, Value: 7}, true},
+		{Filter{Operator: FilterEqual, Value: "7"}, false},
+		{Filter{Operator: FilterBetween, Value: []interface{}{1.5, 3}}, true},
+		{Filter{Operator: FilterIn, Value: []interface{}{1, "two"}}, false},
+		{Filter{Operator: FilterContains, Value: 7}, false},
+	} {
+		if got := test.filter.NumericJSONFilter(); got != test.want {
+			t.Fatalf("NumericJSONFilter(%+v) = %t, want %t", test.filter, got, test.want)
+		}
+	}
+}
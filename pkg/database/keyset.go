@@ -0,0 +1,322 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrKeysetTokenMismatch reports a continuation token issued for a
+// different table, filter or sort than the page being requested.
+var ErrKeysetTokenMismatch = errors.New("continuation token does not match the current table view")
+
+// KeysetPage pages a table by seeking past the last row of the previous
+// page rather than skipping Offset rows. It is only possible when the
+// order ends in the primary key, which makes every position unique.
+type KeysetPage struct {
+	table       Table
+	sorts       []Sort
+	fingerprint string
+}
+
+type keysetToken struct {
+	Fingerprint string        `json:"k"`
+	Values      []interface{} `json:"v"`
+}
+
+// NewKeysetPage returns the keyset order for table, or false when the
+// table has no primary key or is sorted by a JSON path, in which case the
+// caller keeps using Offset.
+func NewKeysetPage(table Table, structures Structures) (*KeysetPage, bool) {
+	columns := make(map[string]Structure, len(structures))
+	for _, structure := range structures {
+		columns[structure.Name] = structure
+	}
+	remaining := make(map[string]struct{})
+	for _, structure := range structures {
+		if structure.IsPrimary {
+			remaining[structure.Name] = struct{}{}
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, false
+	}
+
+	sorts := make([]Sort, 0, len(table.Sorts)+len(remaining))
+	seen := make(map[string]struct{}, len(table.Sorts))
+	add := func(sort Sort) {
+		if _, duplicate := seen[sort.Column]; duplicate {
+			return
+		}
+		seen[sort.Column] = struct{}{}
+		delete(remaining, sort.Column)
+		sorts = append(sorts, sort)
+	}
+	for _, sort := range table.Sorts {
+		if sort.Path != "" {
+			return nil, false
+		}
+		structure, ok := resolveKeysetColumn(columns, structures, sort.Column)
+		if !ok {
+			return nil, false
+		}
+		direction := SortDirection(strings.ToLower(string(sort.Direction)))
+		if direction == "" {
+			direction = SortAscending
+		}
+		nulls := NullsPosition(strings.ToLower(string(sort.Nulls)))
+		if nulls == "" {
+			nulls = NullsLast
+		}
+		if (direction != SortAscending && direction != SortDescending) ||
+			(nulls != NullsFirst && nulls != NullsLast) {
+			return nil, false
+		}
+		add(Sort{Column: structure.Name, Direction: direction, Nulls: nulls})
+		if len(remaining) == 0 {
+			break
+		}
+	}
+	for _, structure := range structures {
+		if _, needed := remaining[structure.Name]; needed {
+			add(Sort{Column: structure.Name, Direction: SortAscending, Nulls: NullsLast})
+		}
+	}
+
+	table.Sorts = sorts
+	table.Offset = 0
+	fingerprint, err := keysetFingerprint(table)
+	if err != nil {
+		return nil, false
+	}
+	return &KeysetPage{table: table, sorts: sorts, fingerprint: fingerprint}, true
+}
+
+func resolveKeysetColumn(
+	columns map[string]Structure,
+	structures Structures,
+	name string,
+) (Structure, bool) {
+	name = strings.TrimSpace(name)
+	if structure, ok := columns[name]; ok {
+		return structure, true
+	}
+	for _, structure := range structures {
+		if strings.EqualFold(structure.Name, name) {
+			return structure, true
+		}
+	}
+	return Structure{}, false
+}
+
+// KeysetViewKey identifies the table view a keyset order is built for: the
+// table, filter, and requested sort before the primary key completes it.
+// Callers that keep a KeysetPage between pages key it by this, and the
+// token still has to match the page it was issued for.
+func KeysetViewKey(table Table) (string, error) {
+	return keysetFingerprint(table)
+}
+
+func keysetFingerprint(table Table) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Schema string
+		Name   string
+		Where  FilterGroup
+		Sorts  []Sort
+	}{table.Schema, table.Name, table.FilterTree(), table.Sorts})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// Table returns the table to read. Its sorts are the full keyset order,
+// and with a token it also seeks past the row the token was issued for.
+func (page *KeysetPage) Table(token string) (Table, error) {
+	table := page.table
+	if token == "" {
+		return table, nil
+	}
+	values, err := page.decode(token)
+	if err != nil {
+		return Table{}, err
+	}
+	seek := page.seekGroup(values)
+	if table.Where == nil {
+		table.Where = &seek
+	} else {
+		table.Where = &FilterGroup{Logic: FilterAnd, Groups: []FilterGroup{*table.Where, seek}}
+	}
+	if err := table.FilterTree().Validate(); err != nil {
+		return Table{}, err
+	}
+	return table, nil
+}
+
+// seekGroup matches the rows ordered after values. For sorts s1..sn it is
+// (s1 after v1) OR (s1 = v1 AND s2 after v2) OR ..., where "after" follows
+// the sort's direction and NULL position.
+func (page *KeysetPage) seekGroup(values []interface{}) FilterGroup {
+	seek := FilterGroup{Logic: FilterOr}
+	for index, sort := range page.sorts {
+		branch := FilterGroup{Logic: FilterAnd}
+		for previous, value := range values[:index] {
+			column := page.sorts[previous].Column
+			if value == nil {
+				branch.Filters = append(branch.Filters, Filter{Column: column, Operator: FilterIsNull})
+			} else {
+				branch.Filters = append(branch.Filters, Filter{Column: column, Operator: FilterEqual, Value: value})
+			}
+		}
+
+		value := values[index]
+		operator := FilterGreaterThan
+		if sort.Direction == SortDescending {
+			operator = FilterLessThan
+		}
+		switch {
+		case value == nil && sort.Nulls == NullsLast:
+			// Nothing orders after NULL in this column.
+			continue
+		case value == nil:
+			branch.Filters = append(branch.Filters, Filter{Column: sort.Column, Operator: FilterIsNotNull})
+		case sort.Nulls == NullsLast:
+			branch.Groups = append(branch.Groups, FilterGroup{
+				Logic: FilterOr,
+				Filters: []Filter{
+					{Column: sort.Column, Operator: operator, Value: value},
+					{Column: sort.Column, Operator: FilterIsNull},
+				},
+			})
+		default:
+			branch.Filters = append(branch.Filters, Filter{Column: sort.Column, Operator: operator, Value: value})
+		}
+		seek.Groups = append(seek.Groups, branch)
+	}
+	return seek
+}
+
+// NextToken encodes the keyset position of the last row of a page. It
+// returns an empty token when the page was not full, or when a key value
+// cannot round-trip through the token.
+func (page *KeysetPage) NextToken(rows []map[string]interface{}, limit int) string {
+	if limit <= 0 || len(rows) < limit {
+		return ""
+	}
+	last := rows[len(rows)-1]
+	values := make([]interface{}, len(page.sorts))
+	for index, sort := range page.sorts {
+		value, ok := keysetRowValue(last, sort.Column)
+		if !ok {
+			return ""
+		}
+		encoded, ok := encodeKeysetValue(value)
+		if !ok {
+			return ""
+		}
+		values[index] = encoded
+	}
+	encoded, err := json.Marshal(keysetToken{Fingerprint: page.fingerprint, Values: values})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func keysetRowValue(row map[string]interface{}, column string) (interface{}, bool) {
+	if value, ok := row[column]; ok {
+		return value, true
+	}
+	for key, value := range row {
+		if strings.EqualFold(key, column) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (page *KeysetPage) decode(token string) ([]interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continuation token")
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var decoded keysetToken
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid continuation token")
+	}
+	if decoded.Fingerprint != page.fingerprint {
+		return nil, ErrKeysetTokenMismatch
+	}
+	// The order ends in the primary key, which is never NULL, so the last
+	// value always yields a branch of the seek.
+	if len(decoded.Values) != len(page.sorts) || decoded.Values[len(decoded.Values)-1] == nil {
+		return nil, fmt.Errorf("invalid continuation token")
+	}
+	values := make([]interface{}, len(decoded.Values))
+	for index, value := range decoded.Values {
+		values[index], err = decodeKeysetValue(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Times and byte strings are wrapped so they decode back to the Go types
+// the drivers bind natively; every other key value is a JSON scalar.
+const (
+	keysetTimeKey  = "$time"
+	keysetBytesKey = "$bytes"
+)
+
+func encodeKeysetValue(value interface{}) (interface{}, bool) {
+	switch typed := value.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return typed, true
+	case float32:
+		return typed, !math.IsNaN(float64(typed)) && !math.IsInf(float64(typed), 0)
+	case float64:
+		return typed, !math.IsNaN(typed) && !math.IsInf(typed, 0)
+	case time.Time:
+		return map[string]string{keysetTimeKey: typed.Format(time.RFC3339Nano)}, true
+	case []byte:
+		return map[string]string{keysetBytesKey: base64.StdEncoding.EncodeToString(typed)}, true
+	}
+	return nil, false
+}
+
+func decodeKeysetValue(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer, nil
+		}
+		if unsigned, err := strconv.ParseUint(string(typed), 10, 64); err == nil {
+			return unsigned, nil
+		}
+		return typed.Float64()
+	case map[string]interface{}:
+		if text, ok := typed[keysetTimeKey].(string); ok && len(typed) == 1 {
+			return time.Parse(time.RFC3339Nano, text)
+		}
+		if text, ok := typed[keysetBytesKey].(string); ok && len(typed) == 1 {
+			return base64.StdEncoding.DecodeString(text)
+		}
+		return nil, fmt.Errorf("invalid continuation token")
+	case []interface{}:
+		return nil, fmt.Errorf("invalid continuation token")
+	}
+	return value, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func keysetStructures() Structures {
+	return Structures{
+		{Name: "tenant", IsPrimary: true},
+		{Name: "id", IsPrimary: true},
+		{Name: "score", Nullable: true},
+	}
+}
+
+func TestNewKeysetPageEndsTheOrderInThePrimaryKey(t *testing.T) {
+	page, ok := NewKeysetPage(Table{
+		Offset: 40,
+		Sorts:  []Sort{{Column: "SCORE", Direction: SortDescending}, {Column: "id"}},
+	}, keysetStructures())
+	if !ok {
+		t.Fatal("NewKeysetPage() = false")
+	}
+	table, err := page.Table("")
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	want := []Sort{
+		{Column: "score", Direction: SortDescending, Nulls: NullsLast},
+		{Column: "id", Direction: SortAscending, Nulls: NullsLast},
+		{Column: "tenant", Direction: SortAscending, Nulls: NullsLast},
+	}
+	if !reflect.DeepEqual(table.Sorts, want) || table.Offset != 0 {
+		t.Fatalf("Table() sorts = %+v offset = %d", table.Sorts, table.Offset)
+	}
+
+	if _, ok := NewKeysetPage(Table{}, Structures{{Name: "id"}}); ok {
+		t.Fatal("NewKeysetPage() accepted a table without a primary key")
+	}
+	if _, ok := NewKeysetPage(Table{Sorts: []Sort{{Column: "score", Path: "a"}}}, keysetStructures()); ok {
+		t.Fatal("NewKeysetPage() accepted a JSON path sort")
+	}
+}
+
+func TestKeysetTokenSeeksPastTheLastRow(t *testing.T) {
+	page, _ := NewKeysetPage(Table{
+		Sorts: []Sort{{Column: "score", Direction: SortDescending, Nulls: NullsFirst}},
+	}, keysetStructures())
+	at := time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC)
+
+	token := page.NextToken([]map[string]interface{}{
+		{"score": nil, "tenant": at, "id": int64(7)},
+	}, 1)
+	if token == "" {
+		t.Fatal("NextToken() returned no token for a full page")
+	}
+	if page.NextToken([]map[string]interface{}{{"score": 1}}, 2) != "" {
+		t.Fatal("NextToken() returned a token for the last page")
+	}
+
+	table, err := page.Table(token)
+	if err != nil {
+		t.Fatalf("Table(token) error = %v", err)
+	}
+	want := FilterGroup{Logic: FilterOr, Groups: []FilterGroup{
+		{Logic: FilterAnd, Filters: []Filter{
+			{Column: "score", Operator: FilterIsNotNull},
+		}},
+		{Logic: FilterAnd, Filters: []Filter{
+			{Column: "score", Operator: FilterIsNull},
+		}, Groups: []FilterGroup{{Logic: FilterOr, Filters: []Filter{
+			{Column: "tenant", Operator: FilterGreaterThan, Value: at},
+			{Column: "tenant", Operator: FilterIsNull},
+		}}}},
+		{Logic: FilterAnd, Filters: []Filter{
+			{Column: "score", Operator: FilterIsNull},
+			{Column: "tenant", Operator: FilterEqual, Value: at},
+		}, Groups: []FilterGroup{{Logic: FilterOr, Filters: []Filter{
+			{Column: "id", Operator: FilterGreaterThan, Value: int64(7)},
+			{Column: "id", Operator: FilterIsNull},
+		}}}},
+	}}
+	if table.Where == nil || !reflect.DeepEqual(*table.Where, want) {
+		t.Fatalf("seek = %+v", table.Where)
+	}
+}
+
+func TestKeysetTokenIsBoundToTheTableView(t *testing.T) {
+	page, _ := NewKeysetPage(Table{Name: "events"}, keysetStructures())
+	token := page.NextToken([]map[string]interface{}{{"tenant": "a", "id": 1}}, 1)
+
+	filtered, _ := NewKeysetPage(Table{
+		Name:    "events",
+		Filters: []Filter{{Column: "score", Operator: FilterIsNull}},
+	}, keysetStructures())
+	if _, err := filtered.Table(token); !errors.Is(err, ErrKeysetTokenMismatch) {
+		t.Fatalf("Table(token) error = %v, want mismatch", err)
+	}
+	if _, err := page.Table("%%%"); err == nil || errors.Is(err, ErrKeysetTokenMismatch) {
+		t.Fatalf("Table(garbage) error = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// A logical backup is a zip archive with one DDL entry and one JSON rows
+// entry per table, plus a manifest that lists the tables in restore order.
+// Parents come before the tables whose foreign keys reference them.
+const (
+	LogicalBackupVersion      = 1
+	LogicalBackupExtension    = ".rtbackup"
+	LogicalBackupManifestPath = "manifest.json"
+)
+
+// LogicalBackupEntry describes one archive member by its uncompressed size
+// and SHA-256 digest.
+type LogicalBackupEntry struct {
+	Path   string `json:"path"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+type LogicalBackupColumn struct {
+	Name          string `json:"name"`
+	DataType      string `json:"dataType"`
+	Generated     bool   `json:"generated,omitempty"`
+	AutoIncrement bool   `json:"autoIncrement,omitempty"`
+}
+
+type LogicalBackupTable struct {
+	Schema   string                `json:"schema,omitempty"`
+	Name     string                `json:"name"`
+	Columns  []LogicalBackupColumn `json:"columns"`
+	DDL      *LogicalBackupEntry   `json:"ddl,omitempty"`
+	Rows     *LogicalBackupEntry   `json:"rows,omitempty"`
+	RowCount int64                 `json:"rowCount"`
+}
+
+type LogicalBackupManifest struct {
+	Format     BackupFormat         `json:"format"`
+	Version    int                  `json:"version"`
+	Engine     string               `json:"engine"`
+	Database   string               `json:"database"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	SchemaOnly bool                 `json:"schemaOnly"`
+	DataOnly   bool                 `json:"dataOnly"`
+	Tables     []LogicalBackupTable `json:"tables"`
+}
+
+// Validate checks the manifest before any entry is read. Entry paths must
+// be clean relative paths so a restore never follows one outside the
+// archive's own table directory.
+func (manifest LogicalBackupManifest) Validate() error {
+	if manifest.Format != BackupFormatLogical {
+		return fmt.Errorf("backup manifest format %q is not %q", manifest.Format, BackupFormatLogical)
+	}
+	if manifest.Version < 1 || manifest.Version > LogicalBackupVersion {
+		return fmt.Errorf("logical backup version %d is not supported", manifest.Version)
+	}
+	if strings.TrimSpace(manifest.Engine) == "" {
+		return fmt.Errorf("logical backup manifest has no engine")
+	}
+	if manifest.SchemaOnly && manifest.DataOnly {
+		return ErrBackupScopeConflict
+	}
+	tables := make(map[string]struct{}, len(manifest.Tables))
+	paths := make(map[string]struct{}, len(manifest.Tables)*2)
+	for _, table := range manifest.Tables {
+		if strings.TrimSpace(table.Name) == "" {
+			return fmt.Errorf("logical backup manifest has a table without a name")
+		}
+		key := table.Schema + "\x00" + table.Name
+		if _, exists := tables[key]; exists {
+			return fmt.Errorf("logical backup lists table %q more than once", table.Name)
+		}
+		tables[key] = struct{}{}
+		if len(table.Columns) == 0 {
+			return fmt.Errorf("logical backup table %q has no columns", table.Name)
+		}
+		if (table.DDL == nil) != manifest.DataOnly {
+			return fmt.Errorf("logical backup table %q does not match the backup scope", table.Name)
+		}
+		if (table.Rows == nil) != manifest.SchemaOnly {
+			return fmt.Errorf("logical backup table %q does not match the backup scope", table.Name)
+		}
+		if table.RowCount < 0 || (table.Rows == nil && table.RowCount != 0) {
+			return fmt.Errorf("logical backup table %q has an invalid row count", table.Name)
+		}
+		for _, entry := range []*LogicalBackupEntry{table.DDL, table.Rows} {
+			if entry == nil {
+				continue
+			}
+			if err := entry.validate(); err != nil {
+				return fmt.Errorf("logical backup table %q: %w", table.Name, err)
+			}
+			if _, exists := paths[entry.Path]; exists {
+				return fmt.Errorf("logical backup entry %q is listed more than once", entry.Path)
+			}
+			paths[entry.Path] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (entry LogicalBackupEntry) validate() error {
+	if entry.Path == "" ||
+		path.Clean(entry.Path) != entry.Path ||
+		!strings.HasPrefix(entry.Path, "tables/") {
+		return fmt.Errorf("entry path %q is not inside the tables directory", entry.Path)
+	}
+	if entry.Bytes < 0 {
+		return fmt.Errorf("entry %q has a negative size", entry.Path)
+	}
+	digest, err := hex.DecodeString(entry.SHA256)
+	if err != nil || len(digest) != 32 {
+		return fmt.Errorf("entry %q has an invalid SHA-256 checksum", entry.Path)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"rollingthunder/pkg/database"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+var loadDataReaders atomic.Int64
+
+// BulkLoad streams rows as tab-separated text through LOAD DATA LOCAL
+// INFILE. The server must allow local_infile; otherwise the import falls
+// back to INSERT statements.
+func (transaction *mysqlTransaction) BulkLoad(
+	ctx context.Context,
+	table database.Table,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	var localInfile int64
+	if err := transaction.tx.QueryRowxContext(
+		ctx,
+		"SELECT @@GLOBAL.local_infile",
+	).Scan(&localInfile); err != nil || localInfile == 0 {
+		return 0, database.ErrBulkLoadUnavailable
+	}
+
+	reader, writer := io.Pipe()
+	name := fmt.Sprintf("rollingthunder-import-%d", loadDataReaders.Add(1))
+	mysqldriver.RegisterReaderHandler(name, func() io.Reader { return reader })
+	defer mysqldriver.DeregisterReaderHandler(name)
+
+	var (
+		sent      int64
+		sourceErr error
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sent, sourceErr = writeLoadDataRows(writer, columns, rows)
+		if sourceErr != nil {
+			_ = writer.CloseWithError(sourceErr)
+			return
+		}
+		_ = writer.Close()
+	}()
+
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = quoteMySQLIdentifier(column.Name)
+	}
+	target := quoteMySQLQualifiedIdentifier(table.Schema, table.Name)
+	result, loadErr := transaction.tx.ExecContext(
+		ctx,
+		"LOAD DATA LOCAL INFILE 'Reader::"+name+"' INTO TABLE "+target+
+			" CHARACTER SET binary FIELDS TERMINATED BY '\\t' ESCAPED BY '\\\\'"+
+			" LINES TERMINATED BY '\\n' ("+strings.Join(names, ", ")+")",
+	)
+	// Unblock the writer if the server stopped reading early.
+	_ = reader.CloseWithError(io.ErrClosedPipe)
+	<-done
+	if sourceErr != nil && !errors.Is(sourceErr, io.ErrClosedPipe) {
+		return 0, sourceErr
+	}
+	if loadErr != nil {
+		return 0, fmt.Errorf("LOAD DATA into %s: %w", target, loadErr)
+	}
+	loaded, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	// LOCAL loads downgrade conversion and duplicate-key errors to warnings
+	// and skip the row, where an INSERT would have failed.
+	var warnings int64
+	if err := transaction.tx.QueryRowxContext(ctx, "SELECT @@warning_count").Scan(&warnings); err != nil {
+		return 0, err
+	}
+	if warnings > 0 {
+		var level, message string
+		var code int64
+		if err := transaction.tx.QueryRowxContext(ctx, "SHOW WARNINGS LIMIT 1").Scan(
+			&level,
+			&code,
+			&message,
+		); err != nil {
+			return 0, fmt.Errorf("LOAD DATA into %s reported %d warnings", target, warnings)
+		}
+		return 0, fmt.Errorf("LOAD DATA into %s: %s %d: %s", target, level, code, message)
+	}
+	if loaded != sent {
+		return 0, fmt.Errorf("LOAD DATA into %s wrote %d of %d rows", target, loaded, sent)
+	}
+	return loaded, nil
+}
+
+func writeLoadDataRows(
+	writer io.Writer,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	buffered := bufio.NewWriterSize(writer, 64*1024)
+	var count int64
+	for {
+		values, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		for index, value := range values {
+			if index > 0 {
+				_ = buffered.WriteByte('\t')
+			}
+			_, _ = buffered.WriteString(loadDataField(value, columns[index].Type))
+		}
+		if err := buffered.WriteByte('\n'); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, buffered.Flush()
+}
+
+var loadDataEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x00", `\0`,
+)
+
+// loadDataField formats a value the way the INSERT path would bind it: times
+// in UTC to match the connection's location, booleans as 1 and 0.
+func loadDataField(value interface{}, columnType string) string {
+	switch typed := value.(type) {
+	case nil:
+		return `\N`
+	case bool:
+		if typed {
+			return "1"
+		}
+		return "0"
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64)
+	case time.Time:
+		if columnType == "date" {
+			return typed.UTC().Format(time.DateOnly)
+		}
+		return typed.UTC().Format("2006-01-02 15:04:05.999999")
+	case []byte:
+		return loadDataEscaper.Replace(string(typed))
+	case string:
+		return loadDataEscaper.Replace(typed)
+	default:
+		return loadDataEscaper.Replace(fmt.Sprint(typed))
+	}
+}
+
+var _ database.BulkLoader = (*mysqlTransaction)(nil)
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"rollingthunder/pkg/database"
+)
+
+type loadDataTestRows struct {
+	rows [][]interface{}
+}
+
+func (rows *loadDataTestRows) Next() ([]interface{}, error) {
+	if len(rows.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := rows.rows[0]
+	rows.rows = rows.rows[1:]
+	return row, nil
+}
+
+func TestWriteLoadDataRowsEscapesTabSeparatedFields(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	var output bytes.Buffer
+	sent, err := writeLoadDataRows(
+		&output,
+		[]database.BulkLoadColumn{
+			{Name: "id", Type: "integer"},
+			{Name: "note", Type: "text"},
+			{Name: "taken_on", Type: "date"},
+			{Name: "seen_at", Type: "datetime"},
+			{Name: "active", Type: "boolean"},
+		},
+		&loadDataTestRows{rows: [][]interface{}{
+			{
+				int64(1),
+				"tab\there\nnew\\line",
+				time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 5, 1, 9, 30, 0, 500_000_000, local),
+				true,
+			},
+			{int64(2), `\N`, nil, nil, false},
+		}},
+	)
+	if err != nil {
+		t.Fatalf("writeLoadDataRows() error = %v", err)
+	}
+	if sent != 2 {
+		t.Fatalf("sent = %d, want 2", sent)
+	}
+	want := "1\ttab\\there\\nnew\\\\line\t2026-05-01\t2026-05-01 07:30:00.5\t1\n" +
+		"2\t\\\\N\t\\N\t\\N\t0\n"
+	if output.String() != want {
+		t.Fatalf("LOAD DATA rows = %q, want %q", output.String(), want)
+	}
+}
@@ -47,6 +47,12 @@ func (m *MySQL) Capabilities() database.Capabilities {
 		ManageSecurity:      true,
 		ActivityMonitor:     true,
 		SSHConnections:      true,
+		QueryCursors:        true,
+		FilterGroups:        true,
+		RegexFilters:        true,
+		JSONPaths:           true,
+		KeysetPagination:    true,
+		RowEstimates:        true,
 	}
 }
 
@@ -85,71 +91,99 @@ type mysqlQuery struct {
 }
 
 func buildMySQLFilterClause(
-	filters []database.Filter,
+	tree database.FilterGroup,
 	structures database.Structures,
 ) (string, []interface{}, error) {
-	if len(filters) == 0 {
-		return "", nil, nil
-	}
-
 	availableColumns := make(map[string]struct{}, len(structures))
 	for _, structure := range structures {
 		availableColumns[structure.Name] = struct{}{}
 	}
 
-	conditions := make([]string, 0, len(filters))
-	args := make([]interface{}, 0, len(filters))
-	for _, filter := range filters {
-		if err := filter.Validate(); err != nil {
-			return "", nil, err
-		}
+	args := make([]interface{}, 0)
+	clause, err := tree.Render(func(filter database.Filter) (string, error) {
 		column := strings.TrimSpace(filter.Column)
 		if _, exists := availableColumns[column]; !exists {
-			return "", nil, fmt.Errorf(
+			return "", fmt.Errorf(
 				"cannot filter by unknown column %q",
 				column,
 			)
 		}
 		quoted := quoteMySQLIdentifier(column)
+		if filter.Path != "" {
+			path, err := database.ParseJSONPath(filter.Path)
+			if err != nil {
+				return "", err
+			}
+			quoted, args = mysqlJSONPathValue(quoted, path, filter.NumericJSONFilter(), args)
+		}
 
 		switch filter.Operator {
 		case database.FilterEqual:
-			conditions = append(conditions, quoted+" = ?")
 			args = append(args, filter.Value)
+			return quoted + " = ?", nil
 		case database.FilterNotEqual:
-			conditions = append(conditions, quoted+" <> ?")
 			args = append(args, filter.Value)
+			return quoted + " <> ?", nil
 		case database.FilterGreaterThan:
-			conditions = append(conditions, quoted+" > ?")
 			args = append(args, filter.Value)
+			return quoted + " > ?", nil
 		case database.FilterLessThan:
-			conditions = append(conditions, quoted+" < ?")
 			args = append(args, filter.Value)
+			return quoted + " < ?", nil
 		case database.FilterGreaterEqual:
-			conditions = append(conditions, quoted+" >= ?")
 			args = append(args, filter.Value)
+			return quoted + " >= ?", nil
 		case database.FilterLessEqual:
-			conditions = append(conditions, quoted+" <= ?")
 			args = append(args, filter.Value)
-		case database.FilterContains:
-			conditions = append(
-				conditions,
-				"CAST("+quoted+" AS CHAR) LIKE ?",
-			)
-			args = append(args, "%"+fmt.Sprint(filter.Value)+"%")
+			return quoted + " <= ?", nil
+		case database.FilterContains,
+			database.FilterStartsWith,
+			database.FilterEndsWith:
+			args = append(args, filter.LikePattern())
+			return "CAST(" + quoted + " AS CHAR) LIKE ?", nil
+		case database.FilterNotContains:
+			args = append(args, filter.LikePattern())
+			return "CAST(" + quoted + " AS CHAR) NOT LIKE ?", nil
+		case database.FilterILike:
+			// A binary or case-sensitive collation would otherwise decide
+			// the match, so both sides are folded explicitly.
+			args = append(args, filter.LikePattern())
+			return "LOWER(CAST(" + quoted + " AS CHAR)) LIKE LOWER(?)", nil
+		case database.FilterRegex:
+			args = append(args, fmt.Sprint(filter.Value))
+			return "CAST(" + quoted + " AS CHAR) REGEXP ?", nil
+		case database.FilterIn, database.FilterNotIn:
+			values, _ := filter.Values()
+			args = append(args, values...)
+			operator := " IN ("
+			if filter.Operator == database.FilterNotIn {
+				operator = " NOT IN ("
+			}
+			return quoted + operator +
+				strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", nil
+		case database.FilterBetween:
+			values, _ := filter.Values()
+			args = append(args, values...)
+			return quoted + " BETWEEN ? AND ?", nil
 		case database.FilterIsNull:
-			conditions = append(conditions, quoted+" IS NULL")
+			return quoted + " IS NULL", nil
 		case database.FilterIsNotNull:
-			conditions = append(conditions, quoted+" IS NOT NULL")
+			return quoted + " IS NOT NULL", nil
 		}
+		return "", fmt.Errorf("unsupported filter operator %q", filter.Operator)
+	})
+	if err != nil || clause == "" {
+		return "", nil, err
 	}
-	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+	return " WHERE " + clause, args, nil
 }
 
+// buildMySQLOrderClause appends the arguments of JSON sort paths to args.
 func buildMySQLOrderClause(
 	sorts []database.Sort,
 	structures database.Structures,
-) (string, error) {
+	args []interface{},
+) (string, []interface{}, error) {
 	availableColumns := make(map[string]database.Structure, len(structures))
 	for _, structure := range structures {
 		availableColumns[structure.Name] = structure
@@ -160,12 +194,16 @@ func buildMySQLOrderClause(
 	for _, sort := range sorts {
 		column := strings.TrimSpace(sort.Column)
 		if column == "" {
-			return "", fmt.Errorf("sort column cannot be empty")
+			return "", nil, fmt.Errorf("sort column cannot be empty")
 		}
 		if _, exists := availableColumns[column]; !exists {
-			return "", fmt.Errorf("cannot sort by unknown column %q", column)
+			return "", nil, fmt.Errorf("cannot sort by unknown column %q", column)
+		}
+		sortKey := column
+		if sort.Path != "" {
+			sortKey = column + "\x00" + sort.Path
 		}
-		if _, duplicate := seen[column]; duplicate {
+		if _, duplicate := seen[sortKey]; duplicate {
 			continue
 		}
 
@@ -175,7 +213,7 @@ func buildMySQLOrderClause(
 		}
 		if direction != database.SortAscending &&
 			direction != database.SortDescending {
-			return "", fmt.Errorf(
+			return "", nil, fmt.Errorf(
 				"invalid sort direction %q for column %q",
 				sort.Direction,
 				column,
@@ -186,7 +224,7 @@ func buildMySQLOrderClause(
 			nulls = database.NullsLast
 		}
 		if nulls != database.NullsFirst && nulls != database.NullsLast {
-			return "", fmt.Errorf(
+			return "", nil, fmt.Errorf(
 				"invalid null position %q for column %q",
 				sort.Nulls,
 				column,
@@ -194,6 +232,16 @@ func buildMySQLOrderClause(
 		}
 
 		quoted := quoteMySQLIdentifier(column)
+		if sort.Path != "" {
+			path, err := database.ParseJSONPath(sort.Path)
+			if err != nil {
+				return "", nil, err
+			}
+			// JSON_EXTRACT keeps numbers ordered as numbers. Each use of
+			// the expression binds the path again.
+			quoted = "JSON_EXTRACT(" + quoted + ", ?)"
+			args = append(args, path.String(), path.String())
+		}
 		nullDirection := "ASC"
 		if nulls == database.NullsFirst {
 			nullDirection = "DESC"
@@ -203,7 +251,7 @@ func buildMySQLOrderClause(
 			fmt.Sprintf("(%s IS NULL) %s", quoted, nullDirection),
 			fmt.Sprintf("%s %s", quoted, strings.ToUpper(string(direction))),
 		)
-		seen[column] = struct{}{}
+		seen[sortKey] = struct{}{}
 	}
 
 	for _, structure := range structures {
@@ -240,7 +288,26 @@ func buildMySQLOrderClause(
 		}
 	}
 	if len(parts) == 0 {
-		return "", fmt.Errorf("table has no columns available for stable ordering")
+		return "", nil, fmt.Errorf("table has no columns available for stable ordering")
+	}
+	return " ORDER BY " + strings.Join(parts, ", "), args, nil
+}
+
+// mysqlJSONPathValue reads the value at path as text, or as a number when
+// numeric is set. JSON_TYPE guards the number so a string at the path reads
+// as NULL instead of 0. Each ? the expression holds is appended to args.
+func mysqlJSONPathValue(
+	column string,
+	path database.JSONPath,
+	numeric bool,
+	args []interface{},
+) (string, []interface{}) {
+	extract := "JSON_EXTRACT(" + column + ", ?)"
+	if !numeric {
+		return "JSON_UNQUOTE(" + extract + ")", append(args, path.String())
 	}
-	return " ORDER BY " + strings.Join(parts, ", "), nil
+	expression := "(CASE WHEN JSON_TYPE(" + extract + ") IN " +
+		"('INTEGER', 'UNSIGNED INTEGER', 'DOUBLE', 'DECIMAL') THEN " +
+		extract + " + 0 END)"
+	return expression, append(args, path.String(), path.String())
 }
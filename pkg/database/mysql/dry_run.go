@@ -0,0 +1,129 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"rollingthunder/pkg/database"
+)
+
+// nonTransactionalTablesQuery lists the base tables whose engine ignores
+// ROLLBACK, such as MyISAM, MEMORY, and ARCHIVE.
+const nonTransactionalTablesQuery = `
+	SELECT t.TABLE_SCHEMA AS rt_schema, t.TABLE_NAME AS rt_name, t.ENGINE AS rt_engine
+	FROM information_schema.TABLES t
+	JOIN information_schema.ENGINES e ON e.ENGINE = t.ENGINE
+	WHERE t.TABLE_TYPE = 'BASE TABLE'
+		AND COALESCE(e.TRANSACTIONS, 'NO') <> 'YES'
+		AND t.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+	ORDER BY t.TABLE_SCHEMA, t.TABLE_NAME`
+
+type mysqlStorageRow struct {
+	Schema string `db:"rt_schema"`
+	Name   string `db:"rt_name"`
+	Engine string `db:"rt_engine"`
+}
+
+// NonTransactionalTables returns the non-transactional tables that
+// statements name. Any identifier counts, so a column or alias that shares
+// a table's name also matches; a dry run refuses rather than risk a write
+// that rollback cannot undo.
+func (m *MySQL) NonTransactionalTables(
+	ctx context.Context,
+	statements []string,
+) ([]string, error) {
+	if err := m.ensureConnected(); err != nil {
+		return nil, err
+	}
+	var rows []mysqlStorageRow
+	if err := m.conn.SelectContext(ctx, &rows, nonTransactionalTablesQuery); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	names := mysqlStatementNames(statements)
+	tables := make([]string, 0)
+	for _, row := range rows {
+		if _, named := names[strings.ToLower(row.Name)]; named {
+			tables = append(tables, row.Schema+"."+row.Name+" ("+row.Engine+")")
+		}
+	}
+	return tables, nil
+}
+
+// mysqlStatementNames returns the lower-cased words and quoted identifiers
+// of statements, skipping string literals and comments.
+func mysqlStatementNames(statements []string) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, statement := range statements {
+		for index := 0; index < len(statement); {
+			current := statement[index]
+			switch {
+			case strings.HasPrefix(statement[index:], "--"), current == '#':
+				end := strings.IndexByte(statement[index:], '\n')
+				if end < 0 {
+					end = len(statement) - index
+				}
+				index += end
+			case strings.HasPrefix(statement[index:], "/*"):
+				end := strings.Index(statement[index+2:], "*/")
+				if end < 0 {
+					index = len(statement)
+				} else {
+					index += end + 4
+				}
+			case current == '\'':
+				_, index = mysqlQuoted(statement, index)
+			case current == '`', current == '"':
+				// Double quotes name an identifier under ANSI_QUOTES; taking
+				// them as one only makes the check stricter.
+				var name string
+				name, index = mysqlQuoted(statement, index)
+				names[strings.ToLower(name)] = struct{}{}
+			case isMySQLNamePart(rune(current)):
+				start := index
+				for index < len(statement) && isMySQLNamePart(rune(statement[index])) {
+					index++
+				}
+				names[strings.ToLower(statement[start:index])] = struct{}{}
+			default:
+				index++
+			}
+		}
+	}
+	return names
+}
+
+// mysqlQuoted returns the contents of the quoted text starting at start and
+// the index just past it. A doubled quote or, outside backticks, a
+// backslash escapes the next character.
+func mysqlQuoted(statement string, start int) (string, int) {
+	quote := statement[start]
+	var text strings.Builder
+	for index := start + 1; index < len(statement); index++ {
+		current := statement[index]
+		switch {
+		case current == '\\' && quote != '`' && index+1 < len(statement):
+			index++
+			text.WriteByte(statement[index])
+		case current == quote && index+1 < len(statement) && statement[index+1] == quote:
+			index++
+			text.WriteByte(quote)
+		case current == quote:
+			return text.String(), index + 1
+		default:
+			text.WriteByte(current)
+		}
+	}
+	return text.String(), len(statement)
+}
+
+func isMySQLNamePart(value rune) bool {
+	return value == '_' || value == '$' || value >= utf8.RuneSelf ||
+		unicode.IsLetter(value) || unicode.IsDigit(value)
+}
+
+var _ database.NonTransactionalTableDriver = (*MySQL)(nil)
@@ -19,6 +19,7 @@ import (
 
 	"rollingthunder/pkg/application"
 	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
 
 	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -340,24 +341,38 @@ func normalizeMySQLRow(row map[string]interface{}) {
 }
 
 func (m *MySQL) CountCollectionData(table database.Table) (int, error) {
-	structures, err := m.GetCollectionStructures(table)
-	if err != nil {
-		return 0, err
-	}
-	filterClause, args, err := buildMySQLFilterClause(table.Filters, structures)
+	return m.CountCollectionDataContext(context.Background(), table)
+}
+
+func (m *MySQL) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
+	from, args, err := m.filteredRelation(table)
 	if err != nil {
 		return 0, err
 	}
 	var count int
-	query := "SELECT COUNT(*) FROM " +
-		quoteMySQLQualifiedIdentifier(m.defaultDatabase(table.Schema), table.Name) +
-		filterClause
-	if err := m.conn.Get(&count, query, args...); err != nil {
+	if err := m.conn.GetContext(ctx, &count, "SELECT COUNT(*) FROM "+from, args...); err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// filteredRelation returns the quoted table followed by its WHERE clause.
+func (m *MySQL) filteredRelation(table database.Table) (string, []interface{}, error) {
+	structures, err := m.GetCollectionStructures(table)
+	if err != nil {
+		return "", nil, err
+	}
+	filterClause, args, err := buildMySQLFilterClause(table.FilterTree(), structures)
+	if err != nil {
+		return "", nil, err
+	}
+	return quoteMySQLQualifiedIdentifier(m.defaultDatabase(table.Schema), table.Name) +
+		filterClause, args, nil
+}
+
 func (m *MySQL) GetCollectionData(
 	table database.Table,
 ) (database.Structures, []map[string]interface{}, error) {
@@ -371,11 +386,11 @@ func (m *MySQL) GetCollectionData(
 	if err != nil {
 		return nil, nil, err
 	}
-	filterClause, args, err := buildMySQLFilterClause(table.Filters, structures)
+	filterClause, args, err := buildMySQLFilterClause(table.FilterTree(), structures)
 	if err != nil {
 		return nil, nil, err
 	}
-	orderClause, err := buildMySQLOrderClause(table.Sorts, structures)
+	orderClause, args, err := buildMySQLOrderClause(table.Sorts, structures, args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -564,23 +579,19 @@ func requireOneMySQLRow(result sql.Result, action string) error {
 	return nil
 }
 
-type mysqlMappedRows interface {
+type mysqlQueryRows interface {
 	Next() bool
-	Columns() ([]string, error)
-	MapScan(map[string]interface{}) error
+	SliceScan() ([]interface{}, error)
 	Err() error
 }
 
 func collectMySQLQueryResults(
-	rows mysqlMappedRows,
+	rows mysqlQueryRows,
+	columns []database.QueryColumn,
 	maxRows int,
 ) (database.QueryResult, error) {
-	columns, err := rows.Columns()
-	if err != nil {
-		return database.QueryResult{}, fmt.Errorf("read MySQL query columns: %w", err)
-	}
 	result := database.QueryResult{
-		Rows:     make([]map[string]interface{}, 0),
+		Rows:     make([][]interface{}, 0),
 		RowLimit: maxRows,
 		Columns:  columns,
 	}
@@ -589,11 +600,13 @@ func collectMySQLQueryResults(
 			result.Truncated = true
 			break
 		}
-		row := make(map[string]interface{})
-		if err := rows.MapScan(row); err != nil {
+		row, err := rows.SliceScan()
+		if err != nil {
 			return database.QueryResult{}, fmt.Errorf("scan MySQL query row: %w", err)
 		}
-		normalizeMySQLRow(row)
+		for index, value := range row {
+			row[index] = normalizeMySQLValue(value)
+		}
 		result.Rows = append(result.Rows, row)
 	}
 	if err := rows.Err(); err != nil {
@@ -604,20 +617,105 @@ func collectMySQLQueryResults(
 
 type mysqlQueryRunner interface {
 	QueryxContext(context.Context, string, ...interface{}) (*sqlx.Rows, error)
+	sqladapter.StatementRunner
 }
 
+// executeMySQLQuery runs one statement and then reads its warnings, so the
+// runner must stay on one session: a transaction or a pinned connection. A
+// failed statement still returns the warnings that explain it.
 func executeMySQLQuery(
 	ctx context.Context,
 	runner mysqlQueryRunner,
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
+	result, err := runMySQLStatement(ctx, runner, query, options)
+	if err != nil {
+		return database.QueryResult{Messages: mysqlWarnings(ctx, runner)}, err
+	}
+	result.Messages = mysqlWarnings(ctx, runner)
+	return result, nil
+}
+
+func runMySQLStatement(
+	ctx context.Context,
+	runner mysqlQueryRunner,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	if database.ReportsAffectedRows(query) {
+		return sqladapter.ExecuteRowCount(ctx, runner, query, options)
+	}
 	rows, err := runner.QueryxContext(ctx, query, options.Args...)
 	if err != nil {
 		return database.QueryResult{}, err
 	}
 	defer rows.Close()
-	return collectMySQLQueryResults(rows, options.MaxRows)
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return database.QueryResult{}, fmt.Errorf("read MySQL query columns: %w", err)
+	}
+	return collectMySQLQueryResults(rows, sqladapter.QueryColumns(columnTypes), options.MaxRows)
+}
+
+// mysqlWarnings returns the notes, warnings, and errors MySQL kept for the
+// previous statement on the session. Reading @@warning_count leaves that
+// list in place, so SHOW WARNINGS runs only when there is something to
+// show. A failed lookup only loses the messages, never the statement's
+// result.
+func mysqlWarnings(ctx context.Context, runner mysqlQueryRunner) []database.QueryMessage {
+	if ctx.Err() != nil || mysqlWarningCount(ctx, runner) == 0 {
+		return nil
+	}
+	rows, err := runner.QueryxContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var messages database.QueryMessageLog
+	for rows.Next() {
+		var (
+			level string
+			code  sql.NullInt64
+			text  string
+		)
+		if err := rows.Scan(&level, &code, &text); err != nil {
+			return nil
+		}
+		codeText := ""
+		if code.Valid {
+			codeText = strconv.FormatInt(code.Int64, 10)
+		}
+		messages.Add(mysqlWarningSeverity(level), codeText, text)
+	}
+	if rows.Err() != nil {
+		return nil
+	}
+	return messages.Messages()
+}
+
+func mysqlWarningCount(ctx context.Context, runner mysqlQueryRunner) int64 {
+	rows, err := runner.QueryxContext(ctx, "SELECT @@warning_count")
+	if err != nil {
+		return 0
+	}
+	defer rows.Close()
+	var count int64
+	if !rows.Next() || rows.Scan(&count) != nil {
+		return 0
+	}
+	return count
+}
+
+func mysqlWarningSeverity(level string) database.QueryMessageSeverity {
+	switch strings.ToLower(level) {
+	case "note":
+		return database.QueryMessageNotice
+	case "error":
+		return database.QueryMessageError
+	default:
+		return database.QueryMessageWarning
+	}
 }
 
 func (m *MySQL) ExecuteQuery(
@@ -625,7 +723,12 @@ func (m *MySQL) ExecuteQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
-	return executeMySQLQuery(ctx, m.conn, query, options)
+	conn, err := m.conn.Connx(ctx)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	defer conn.Close()
+	return executeMySQLQuery(ctx, conn, query, options)
 }
 
 type mysqlTransaction struct {
@@ -658,6 +761,20 @@ func (m *MySQL) BeginTransaction(
 	return &mysqlTransaction{tx: transaction}, nil
 }
 
+// OpenQueryCursor leaves the statement's rows unread on the wire between
+// fetches, so MySQL streams the result instead of buffering all of it.
+func (m *MySQL) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	return sqladapter.OpenCursor(ctx, m.conn.DB, query, options, func(row []interface{}) {
+		for index, value := range row {
+			row[index] = normalizeMySQLValue(value)
+		}
+	})
+}
+
 type mysqlExportRows struct {
 	rows *sqlx.Rows
 }
@@ -754,11 +871,11 @@ func buildMySQLExportQuery(
 	if table.Offset < 0 {
 		return mysqlQuery{}, fmt.Errorf("table offset cannot be negative")
 	}
-	filterClause, args, err := buildMySQLFilterClause(table.Filters, structures)
+	filterClause, args, err := buildMySQLFilterClause(table.FilterTree(), structures)
 	if err != nil {
 		return mysqlQuery{}, err
 	}
-	orderClause, err := buildMySQLOrderClause(table.Sorts, structures)
+	orderClause, args, err := buildMySQLOrderClause(table.Sorts, structures, args)
 	if err != nil {
 		return mysqlQuery{}, err
 	}
@@ -856,7 +973,13 @@ func (m *MySQL) ExportTable(
 			request.Options.SQL,
 		)
 	}
-	return database.WriteExportStreamContext(ctx, writer, stream, request.Options)
+	return database.WriteTypedExportStreamContext(
+		ctx,
+		writer,
+		stream,
+		structures,
+		request.Options,
+	)
 }
 
 type mysqlInsertSink struct {
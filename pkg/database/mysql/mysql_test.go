@@ -49,6 +49,7 @@ func TestMySQLLiveConformance(t *testing.T) {
 		Schema:             config.Db,
 		IntegerType:        "INT",
 		TextType:           "VARCHAR(255)",
+		JSONType:           "JSON",
 		ExercisePrivileged: os.Getenv("ROLLINGTHUNDER_TEST_PRIVILEGED") == "1",
 	})
 }
@@ -58,10 +59,10 @@ func TestMySQLDialectBuilders(t *testing.T) {
 		{Name: "id", DataType: "int", IsPrimary: true},
 		{Name: "name", DataType: "varchar(255)"},
 	}
-	filter, args, err := buildMySQLFilterClause([]database.Filter{
+	filter, args, err := buildMySQLFilterClause(database.FilterGroup{Filters: []database.Filter{
 		{Column: "name", Operator: database.FilterContains, Value: "storm"},
 		{Column: "id", Operator: database.FilterGreaterEqual, Value: 5},
-	}, structures)
+	}}, structures)
 	if err != nil {
 		t.Fatalf("buildMySQLFilterClause() error = %v", err)
 	}
@@ -72,9 +73,9 @@ func TestMySQLDialectBuilders(t *testing.T) {
 		t.Fatalf("args = %#v", args)
 	}
 
-	order, err := buildMySQLOrderClause([]database.Sort{{
+	order, _, err := buildMySQLOrderClause([]database.Sort{{
 		Column: "name", Direction: database.SortDescending, Nulls: database.NullsFirst,
-	}}, structures)
+	}}, structures, nil)
 	if err != nil {
 		t.Fatalf("buildMySQLOrderClause() error = %v", err)
 	}
@@ -257,3 +258,20 @@ func TestMySQLInsertExportCanUseDuplicateKeyUpdate(t *testing.T) {
 		t.Fatalf("upsert statement = %q", sink.upsertStatement)
 	}
 }
+
+func TestMySQLStatementNamesSkipLiteralsAndComments(t *testing.T) {
+	names := mysqlStatementNames([]string{
+		"UPDATE `Shop Events` SET note = 'audit_log' -- hits\n" +
+			"WHERE id = 1 # legacy_rows\n/* archive */ AND `it``s` = \"Cache\"",
+	})
+	for _, want := range []string{"shop events", "note", "it`s", "cache"} {
+		if _, ok := names[want]; !ok {
+			t.Fatalf("names = %v, want %q", names, want)
+		}
+	}
+	for _, skipped := range []string{"audit_log", "hits", "legacy_rows", "archive"} {
+		if _, ok := names[skipped]; ok {
+			t.Fatalf("names = %v, did not want %q", names, skipped)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverMySQL, open, probe)
+	database.RegisterDriver(database.DriverMariaDB, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewMySQL(ctx, Config{
+		Host:          cfg.Host,
+		Port:          cfg.Port,
+		User:          cfg.User,
+		Password:      cfg.Password,
+		Db:            cfg.Db,
+		SSLMode:       cfg.SSLMode,
+		SSLRootCert:   cfg.SSLRootCert,
+		SSLCert:       cfg.SSLCert,
+		SSLKey:        cfg.SSLKey,
+		TLSServerName: cfg.TLSServerName,
+	}), nil
+}
+
+// probe accepts either the MySQL or MariaDB client tools; both produce and
+// restore the same plain SQL dump format.
+func probe(lookPath database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*MySQL)(nil).Capabilities()
+	_, backupTool := database.LookupExecutable(lookPath, "mysqldump", "mariadb-dump")
+	_, restoreTool := database.LookupExecutable(lookPath, "mysql", "mariadb")
+	available := backupTool != ""
+	message := ""
+	if !available {
+		message = "Install MySQL or MariaDB client tools so mysqldump is available in PATH."
+	} else if restoreTool == "" {
+		message = "Backups are available, but the mysql or mariadb client is required to restore them."
+	}
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available:     available,
+			Engine:        capabilities.Engine,
+			Format:        database.BackupFormatMySQLSQL,
+			Extension:     ".sql",
+			BackupTool:    backupTool,
+			RestoreTool:   restoreTool,
+			RestoreReady:  restoreTool != "",
+			Message:       message,
+			SupportsScope: true,
+		},
+	}
+}
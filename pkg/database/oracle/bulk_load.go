@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// oracleBulkBatchRows bounds how many rows one array-bound INSERT sends.
+const oracleBulkBatchRows = 1_000
+
+// BulkLoad binds each column as an array so one INSERT round trip writes a
+// whole batch of rows.
+func (transaction *oracleTransaction) BulkLoad(
+	ctx context.Context,
+	table database.Table,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	target := quoteIdentifier(table.Name)
+	if table.Schema != "" {
+		target = quoteIdentifier(table.Schema) + "." + target
+	}
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = quoteIdentifier(column.Name)
+		placeholders[index] = fmt.Sprintf(":%d", index+1)
+	}
+	statement := "INSERT INTO " + target + " (" + strings.Join(names, ", ") +
+		") VALUES (" + strings.Join(placeholders, ", ") + ")"
+
+	var loaded int64
+	batch := make([][]interface{}, 0, oracleBulkBatchRows)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := transaction.tx.ExecContext(
+			ctx,
+			statement,
+			oracleBulkBindArrays(columns, batch)...,
+		); err != nil {
+			return fmt.Errorf("rows %d–%d: %w", loaded+1, loaded+int64(len(batch)), err)
+		}
+		loaded += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		values, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		batch = append(batch, values)
+		if len(batch) == oracleBulkBatchRows {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+	return loaded, nil
+}
+
+// oracleBulkBindArrays turns a batch of rows into one bind array per column.
+// Oracle stores an empty string as NULL, so empty strings outside text
+// columns become nil and keep each array to a single bind type.
+func oracleBulkBindArrays(
+	columns []database.BulkLoadColumn,
+	batch [][]interface{},
+) []interface{} {
+	arrays := make([]interface{}, len(columns))
+	for columnIndex, column := range columns {
+		values := make([]interface{}, len(batch))
+		for rowIndex, row := range batch {
+			value := row[columnIndex]
+			if text, ok := value.(string); ok && text == "" && column.Type != "text" {
+				value = nil
+			}
+			values[rowIndex] = value
+		}
+		arrays[columnIndex] = values
+	}
+	return arrays
+}
+
+var _ database.BulkLoader = (*oracleTransaction)(nil)
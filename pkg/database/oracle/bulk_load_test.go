@@ -0,0 +1,29 @@
+package oracle
+
+import (
+	"reflect"
+	"testing"
+
+	"rollingthunder/pkg/database"
+)
+
+func TestOracleBulkBindArraysBindsOneArrayPerColumn(t *testing.T) {
+	got := oracleBulkBindArrays(
+		[]database.BulkLoadColumn{
+			{Name: "ID", Type: "integer"},
+			{Name: "NOTE", Type: "text"},
+		},
+		[][]interface{}{
+			{int64(1), "dry"},
+			{"", ""},
+			{int64(3), nil},
+		},
+	)
+	want := []interface{}{
+		[]interface{}{int64(1), nil, int64(3)},
+		[]interface{}{"dry", "", nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("bind arrays = %#v, want %#v", got, want)
+	}
+}
@@ -51,6 +51,13 @@ func (o *Oracle) Capabilities() database.Capabilities {
 		ManageSecurity:      true,
 		ActivityMonitor:     true,
 		SSHConnections:      true,
+		ServerOutput:        true,
+		QueryCursors:        true,
+		FilterGroups:        true,
+		RegexFilters:        true,
+		JSONPaths:           true,
+		KeysetPagination:    true,
+		RowEstimates:        true,
 	}
 }
 
@@ -102,6 +109,23 @@ func (o *Oracle) adapterDialect() sqladapter.Dialect {
 		TextExpression: func(identifier string) string {
 			return "TO_CHAR(" + identifier + ")"
 		},
+		RegexExpression: func(text, pattern string) string {
+			return "REGEXP_LIKE(" + text + ", " + pattern + ")"
+		},
+		// JSON_VALUE only takes a literal path. ParseJSONPath rejects quotes
+		// in keys, so the rendered path cannot end the literal early.
+		JSONPathExpression: func(
+			column string,
+			path database.JSONPath,
+			numeric bool,
+			_ func(interface{}) string,
+		) string {
+			if numeric {
+				return "JSON_VALUE(" + column + ", '" + path.String() +
+					"' RETURNING NUMBER NULL ON ERROR)"
+			}
+			return "JSON_VALUE(" + column + ", '" + path.String() + "')"
+		},
 		InsertExport: oracleInsertExportDialect(),
 	}
 }
@@ -407,6 +407,13 @@ func (o *Oracle) defaultSchema(schema string) string {
 }
 
 func (o *Oracle) CountCollectionData(table database.Table) (int, error) {
+	return o.CountCollectionDataContext(o.ctx, table)
+}
+
+func (o *Oracle) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
 	if err := o.ensureConnected(); err != nil {
 		return 0, err
 	}
@@ -415,7 +422,7 @@ func (o *Oracle) CountCollectionData(table database.Table) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	return sqladapter.CountTable(o.conn, table, structures, o.adapterDialect())
+	return sqladapter.CountTable(ctx, o.conn, table, structures, o.adapterDialect())
 }
 
 func (o *Oracle) GetCollectionData(
@@ -509,7 +516,28 @@ func (o *Oracle) ExecuteQuery(
 	if err := o.ensureConnected(); err != nil {
 		return database.QueryResult{}, err
 	}
-	return sqladapter.ExecuteQuery(ctx, o.conn, query, options)
+	if !options.ServerOutput {
+		return executeOracleQuery(ctx, o.conn, query, options)
+	}
+	conn, err := o.conn.Conn(ctx)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	defer conn.Close()
+	return executeOracleQuery(ctx, conn, query, options)
+}
+
+// OpenQueryCursor keeps the statement's rows open and lets go-ora fetch
+// them from the server in prefetch-sized round trips as pages are read.
+func (o *Oracle) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	if err := o.ensureConnected(); err != nil {
+		return nil, err
+	}
+	return sqladapter.OpenCursor(ctx, o.conn, query, options, nil)
 }
 
 type oracleTransaction struct {
@@ -521,7 +549,7 @@ func (transaction *oracleTransaction) ExecuteQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
-	return sqladapter.ExecuteQuery(ctx, transaction.tx, query, options)
+	return executeOracleQuery(ctx, transaction.tx, query, options)
 }
 
 func (transaction *oracleTransaction) Commit() error {
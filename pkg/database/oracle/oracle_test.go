@@ -314,6 +314,7 @@ func TestOracleLiveConformance(t *testing.T) {
 			Schema:             schema,
 			IntegerType:        "NUMBER(10)",
 			TextType:           "VARCHAR2(255)",
+			JSONType:           "VARCHAR2(4000)",
 			ExercisePrivileged: os.Getenv("ROLLINGTHUNDER_ORACLE_TEST_PRIVILEGED") == "1",
 		})
 	})
@@ -326,6 +327,9 @@ func TestOracleLiveConformance(t *testing.T) {
 	t.Run("connection_resilience", func(t *testing.T) {
 		runOracleConnectionResilienceConformance(t, config)
 	})
+	t.Run("server_output", func(t *testing.T) {
+		runOracleServerOutputConformance(t, admin)
+	})
 	t.Run("tns_alias", func(t *testing.T) {
 		runOracleTNSConformance(t, config)
 	})
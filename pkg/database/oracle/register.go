@@ -0,0 +1,55 @@
+package oracle
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverOracle, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewOracle(ctx, Config{
+		Host:           cfg.Host,
+		Port:           cfg.Port,
+		User:           cfg.User,
+		Password:       cfg.Password,
+		Db:             cfg.Db,
+		SSLMode:        cfg.SSLMode,
+		SSLRootCert:    cfg.SSLRootCert,
+		SSLCert:        cfg.SSLCert,
+		SSLKey:         cfg.SSLKey,
+		TLSServerName:  cfg.TLSServerName,
+		ConnectionMode: cfg.OracleConnectionMode,
+		TNSConfigPath:  cfg.OracleTNSConfigPath,
+		TNSAlias:       cfg.OracleTNSAlias,
+		WalletPath:     cfg.OracleWalletPath,
+		WalletPassword: cfg.OracleWalletPassword,
+	}), nil
+}
+
+// probe advertises Data Pump. The usable DIRECTORY objects are resolved per
+// connection because they depend on the account's grants.
+func probe(database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*Oracle)(nil).Capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available:         true,
+			Engine:            capabilities.Engine,
+			Format:            database.BackupFormatOracleDataPump,
+			Extension:         ".dmp",
+			BackupTool:        "DBMS_DATAPUMP",
+			RestoreTool:       "DBMS_DATAPUMP",
+			RestoreReady:      true,
+			BuiltIn:           true,
+			SupportsScope:     false,
+			RequiresDirectory: true,
+			Directories:       []database.BackupDirectory{},
+			Message: "Oracle Data Pump stages encrypted-at-rest responsibility " +
+				"with the configured database server directory.",
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+// EstimateCollectionData reads ALL_TABLES.NUM_ROWS, which is as current as
+// the last optimizer statistics gathering and NULL before the first one.
+// A filtered table is estimated by the cardinality of its plan.
+func (o *Oracle) EstimateCollectionData(
+	ctx context.Context,
+	table database.Table,
+) (database.RowCount, bool, error) {
+	if err := o.ensureConnected(); err != nil {
+		return database.RowCount{}, false, err
+	}
+	table.Schema = o.defaultSchema(table.Schema)
+	if table.Filtered() {
+		structures, err := o.GetCollectionStructures(table)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		table.Sorts = nil
+		query, args, err := sqladapter.BuildTableSelect(
+			table,
+			structures,
+			"*",
+			o.adapterDialect(),
+			false,
+		)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		plan, err := o.ExplainQueryWithArgs(ctx, query, args)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		count, ok := database.PlannerRowCount(plan)
+		return count, ok, nil
+	}
+
+	var rows sql.NullInt64
+	err := o.conn.QueryRowContext(
+		ctx,
+		`SELECT num_rows
+		FROM all_tables
+		WHERE owner = :1
+			AND table_name = :2`,
+		table.Schema,
+		table.Name,
+	).Scan(&rows)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !rows.Valid) {
+		return database.RowCount{}, false, nil
+	}
+	if err != nil {
+		return database.RowCount{}, false, err
+	}
+	return database.RowCount{
+		Rows:      rows.Int64,
+		Estimated: true,
+		Source:    database.RowCountStatistics,
+	}, true, nil
+}
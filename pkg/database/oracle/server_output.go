@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	go_ora "github.com/sijms/go-ora/v2"
+)
+
+const (
+	oracleEnableServerOutput  = `BEGIN DBMS_OUTPUT.ENABLE(NULL); END;`
+	oracleDisableServerOutput = `BEGIN DBMS_OUTPUT.DISABLE; END;`
+	oracleServerOutputLines   = `BEGIN DBMS_OUTPUT.GET_LINES(:lines, :line_count); END;`
+	oracleServerOutputBatch   = 500
+)
+
+// executeOracleQuery runs one statement and, when the tab asked for server
+// output, returns the DBMS_OUTPUT lines it wrote as messages. The buffer
+// belongs to the session, so the runner must stay on one session, and it
+// is switched off again afterwards so pooled sessions do not keep
+// collecting output nobody reads.
+func executeOracleQuery(
+	ctx context.Context,
+	runner sqladapter.QueryRunner,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	if !options.ServerOutput {
+		return sqladapter.ExecuteQuery(ctx, runner, query, options)
+	}
+	if _, err := runner.ExecContext(ctx, oracleEnableServerOutput); err != nil {
+		return database.QueryResult{}, fmt.Errorf("enable DBMS_OUTPUT: %w", err)
+	}
+	defer func() {
+		_, _ = runner.ExecContext(context.WithoutCancel(ctx), oracleDisableServerOutput)
+	}()
+
+	result, err := sqladapter.ExecuteQuery(ctx, runner, query, options)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	messages, err := readOracleServerOutput(ctx, runner)
+	if err != nil {
+		return database.QueryResult{}, fmt.Errorf("read DBMS_OUTPUT: %w", err)
+	}
+	result.Messages = messages
+	return result, nil
+}
+
+func readOracleServerOutput(
+	ctx context.Context,
+	runner sqladapter.StatementRunner,
+) ([]database.QueryMessage, error) {
+	var messages database.QueryMessageLog
+	for {
+		var lines []sql.NullString
+		count := oracleServerOutputBatch
+		if _, err := runner.ExecContext(
+			ctx,
+			oracleServerOutputLines,
+			sql.Named("lines", go_ora.Out{Dest: &lines, Size: oracleServerOutputBatch}),
+			sql.Named("line_count", go_ora.Out{Dest: &count, In: true}),
+		); err != nil {
+			return nil, err
+		}
+		for index := 0; index < count && index < len(lines); index++ {
+			messages.Add(database.QueryMessageInfo, "", lines[index].String)
+		}
+		if count < oracleServerOutputBatch {
+			return messages.Messages(), nil
+		}
+	}
+}
@@ -302,7 +302,7 @@ func runOracleEdgeTypeConformance(
 	if len(result.Rows) != 1 {
 		t.Fatalf("canonical Oracle edge-type rows = %d, want 1", len(result.Rows))
 	}
-	row := result.Rows[0]
+	row := result.RowMaps()[0]
 	if got := liveText(liveRowValue(row, "unicode_text")); got != unicodeValue {
 		t.Fatalf("Oracle Unicode round trip = %q, want %q", got, unicodeValue)
 	}
@@ -420,6 +420,48 @@ func runOracleConnectionResilienceConformance(
 	})
 }
 
+func runOracleServerOutputConformance(t *testing.T, driver *Oracle) {
+	t.Helper()
+	const block = `BEGIN
+		DBMS_OUTPUT.PUT_LINE('first line');
+		DBMS_OUTPUT.PUT_LINE('second line');
+	END;`
+	assertLines := func(t *testing.T, result database.QueryResult) {
+		t.Helper()
+		if len(result.Messages) != 2 ||
+			result.Messages[0].Text != "first line" ||
+			result.Messages[1].Text != "second line" ||
+			result.Messages[1].Sequence != 2 {
+			t.Fatalf("DBMS_OUTPUT messages = %+v", result.Messages)
+		}
+	}
+	options := database.QueryOptions{ServerOutput: true}
+
+	result, err := driver.ExecuteQuery(context.Background(), block, options)
+	if err != nil {
+		t.Fatalf("ExecuteQuery() error = %v", err)
+	}
+	assertLines(t, result)
+	quiet, err := driver.ExecuteQuery(context.Background(), block, database.QueryOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteQuery() without server output error = %v", err)
+	}
+	if len(quiet.Messages) != 0 {
+		t.Fatalf("messages without server output = %+v", quiet.Messages)
+	}
+
+	transaction, err := driver.BeginTransaction(context.Background())
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	defer transaction.Rollback()
+	result, err = transaction.ExecuteQuery(context.Background(), block, options)
+	if err != nil {
+		t.Fatalf("transaction ExecuteQuery() error = %v", err)
+	}
+	assertLines(t, result)
+}
+
 func runOracleTNSConformance(
 	t *testing.T,
 	config Config,
@@ -454,7 +496,7 @@ func runOracleTNSConformance(
 		t.Fatalf("Oracle live TNS query = %+v, %v", result, err)
 	}
 	if strings.TrimSpace(liveText(
-		liveRowValue(result.Rows[0], "service_name"),
+		liveRowValue(result.RowMaps()[0], "service_name"),
 	)) == "" {
 		t.Fatalf("Oracle live TNS service metadata = %+v", result.Rows[0])
 	}
@@ -511,7 +553,7 @@ func assertSecureOracleConnection(
 		database.QueryOptions{MaxRows: 1},
 	)
 	if err != nil || len(result.Rows) != 1 ||
-		liveText(liveRowValue(result.Rows[0], "secure_probe")) != "1" {
+		liveText(liveRowValue(result.RowMaps()[0], "secure_probe")) != "1" {
 		t.Fatalf("secure Oracle probe = %+v, %v", result, err)
 	}
 }
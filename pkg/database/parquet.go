@@ -0,0 +1,760 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+)
+
+type ParquetCompression string
+
+const (
+	ParquetCompressionSnappy ParquetCompression = "snappy"
+	ParquetCompressionZstd   ParquetCompression = "zstd"
+	ParquetCompressionNone   ParquetCompression = "none"
+)
+
+type ParquetOptions struct {
+	Compression ParquetCompression `json:"compression"`
+}
+
+// parquetRowGroupRows is how many rows are buffered before a row group is
+// written, which bounds the memory a Parquet export holds at once.
+const parquetRowGroupRows = 64 * 1024
+
+func parquetCodec(value ParquetCompression) (compress.Compression, error) {
+	switch value {
+	case "", ParquetCompressionSnappy:
+		return compress.Codecs.Snappy, nil
+	case ParquetCompressionZstd:
+		return compress.Codecs.Zstd, nil
+	case ParquetCompressionNone:
+		return compress.Codecs.Uncompressed, nil
+	default:
+		return compress.Codecs.Uncompressed, fmt.Errorf(
+			"unsupported Parquet compression %q",
+			value,
+		)
+	}
+}
+
+type parquetKind int
+
+const (
+	parquetString parquetKind = iota
+	parquetJSON
+	parquetBinary
+	parquetBoolean
+	parquetInt64
+	parquetDouble
+	parquetDecimal
+	parquetTimestamp
+	parquetLocalTimestamp
+)
+
+type parquetColumn struct {
+	name      string
+	kind      parquetKind
+	optional  bool
+	precision int32
+	scale     int32
+}
+
+var (
+	parquetTypeParameters = regexp.MustCompile(`\(\s*(\d+)\s*(?:,\s*(-?\d+)\s*)?\)`)
+	parquetIntegerTypes   = map[string]bool{
+		"tinyint": true, "smallint": true, "mediumint": true, "int": true,
+		"integer": true, "bigint": true, "int2": true, "int4": true,
+		"int8": true, "int16": true, "int32": true, "int64": true,
+		"uint8": true, "uint16": true, "uint32": true, "utinyint": true,
+		"usmallint": true, "uinteger": true, "serial": true,
+		"smallserial": true, "bigserial": true, "long": true,
+	}
+	parquetFloatTypes = map[string]bool{
+		"real": true, "float": true, "float4": true, "float8": true,
+		"float32": true, "float64": true, "double": true,
+		"double precision": true, "binary_float": true, "binary_double": true,
+	}
+	parquetBinaryTypes = map[string]bool{
+		"bytea": true, "blob": true, "tinyblob": true, "mediumblob": true,
+		"longblob": true, "binary": true, "varbinary": true, "image": true,
+		"raw": true, "long raw": true, "bytes": true,
+	}
+	parquetLocalTimestampTypes = map[string]bool{
+		"timestamp": true, "timestamp without time zone": true,
+		"datetime": true, "datetime2": true, "smalldatetime": true,
+		"datetime64": true, "timestamp_s": true, "timestamp_ms": true,
+		"timestamp_ns": true,
+		// Oracle dates carry a time of day, so every DATE is written as a
+		// timestamp rather than risk dropping it.
+		"date": true, "date32": true,
+	}
+)
+
+// parquetColumnFor maps a declared column type to its Parquet column. It
+// reports false when the declaration does not pin down a Parquet type, such
+// as a NUMERIC without precision, so the type is inferred from the values.
+func parquetColumnFor(structure Structure) (parquetColumn, bool) {
+	column := parquetColumn{name: structure.Name, optional: structure.Nullable}
+	declared := strings.ToLower(strings.TrimSpace(structure.DataType))
+	if declared == "" {
+		declared = strings.ToLower(strings.TrimSpace(structure.NativeType))
+	}
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		if strings.HasPrefix(declared, wrapper) && strings.HasSuffix(declared, ")") {
+			declared = declared[len(wrapper) : len(declared)-1]
+			column.optional = column.optional || wrapper == "nullable("
+		}
+	}
+	precision, scale := -1, 0
+	if match := parquetTypeParameters.FindStringSubmatch(declared); match != nil {
+		precision, _ = strconv.Atoi(match[1])
+		if match[2] != "" {
+			scale, _ = strconv.Atoi(match[2])
+		}
+	}
+	base := strings.Join(
+		strings.Fields(parquetTypeParameters.ReplaceAllString(declared, " ")),
+		" ",
+	)
+	unsigned := strings.HasSuffix(base, " unsigned")
+	base = strings.TrimSuffix(base, " unsigned")
+
+	switch {
+	case base == "boolean" || base == "bool" || (base == "bit" && precision <= 1):
+		column.kind = parquetBoolean
+	case (base == "bigint" && unsigned) || base == "uint64" || base == "ubigint":
+		column.kind, column.precision = parquetDecimal, 20
+	case base == "hugeint" || base == "int128":
+		column.kind, column.precision = parquetDecimal, 38
+	case parquetIntegerTypes[base]:
+		column.kind = parquetInt64
+	case parquetFloatTypes[base]:
+		column.kind = parquetDouble
+	case base == "numeric" || base == "decimal" || base == "number" || base == "dec":
+		if precision < 1 || precision > 38 || scale < 0 || scale > precision {
+			return column, false
+		}
+		column.kind = parquetDecimal
+		column.precision, column.scale = int32(precision), int32(scale)
+	case base == "timestamptz" || base == "datetimeoffset" ||
+		(strings.HasPrefix(base, "timestamp") && strings.HasSuffix(base, " time zone") &&
+			!strings.HasSuffix(base, "without time zone")):
+		column.kind = parquetTimestamp
+	case parquetLocalTimestampTypes[base]:
+		column.kind = parquetLocalTimestamp
+	case base == "json" || base == "jsonb":
+		column.kind = parquetJSON
+	case parquetBinaryTypes[base]:
+		column.kind = parquetBinary
+	default:
+		column.kind = parquetString
+	}
+	return column, true
+}
+
+type parquetValueClass int
+
+const (
+	parquetClassNone parquetValueClass = iota
+	parquetClassBoolean
+	parquetClassInteger
+	parquetClassFloat
+	parquetClassTime
+	parquetClassJSON
+	parquetClassBinary
+	parquetClassText
+)
+
+func parquetClassOf(value interface{}) parquetValueClass {
+	switch typed := value.(type) {
+	case nil:
+		return parquetClassNone
+	case bool:
+		return parquetClassBoolean
+	case time.Time:
+		return parquetClassTime
+	case json.RawMessage:
+		return parquetClassJSON
+	case []byte:
+		if utf8.Valid(typed) {
+			return parquetClassText
+		}
+		return parquetClassBinary
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return parquetClassInteger
+	case reflect.Float32, reflect.Float64:
+		return parquetClassFloat
+	default:
+		return parquetClassText
+	}
+}
+
+// inferParquetColumn picks a column type from the buffered values. Columns
+// whose values disagree fall back to text; integers mixed with floats widen
+// to doubles.
+func inferParquetColumn(name string, rows [][]interface{}, index int) parquetColumn {
+	class := parquetClassNone
+	for _, row := range rows {
+		next := parquetClassOf(row[index])
+		switch {
+		case next == parquetClassNone || next == class:
+		case class == parquetClassNone:
+			class = next
+		case (class == parquetClassInteger && next == parquetClassFloat) ||
+			(class == parquetClassFloat && next == parquetClassInteger):
+			class = parquetClassFloat
+		default:
+			class = parquetClassText
+		}
+	}
+	column := parquetColumn{name: name, optional: true}
+	switch class {
+	case parquetClassBoolean:
+		column.kind = parquetBoolean
+	case parquetClassInteger:
+		column.kind = parquetInt64
+	case parquetClassFloat:
+		column.kind = parquetDouble
+	case parquetClassTime:
+		column.kind = parquetTimestamp
+	case parquetClassJSON:
+		column.kind = parquetJSON
+	case parquetClassBinary:
+		column.kind = parquetBinary
+	default:
+		column.kind = parquetString
+	}
+	return column
+}
+
+func (column parquetColumn) node() (schema.Node, error) {
+	repetition := parquet.Repetitions.Required
+	if column.optional {
+		repetition = parquet.Repetitions.Optional
+	}
+	var (
+		logical  schema.LogicalType = schema.NoLogicalType{}
+		physical                    = parquet.Types.ByteArray
+	)
+	switch column.kind {
+	case parquetBoolean:
+		physical = parquet.Types.Boolean
+	case parquetInt64:
+		logical, physical = schema.NewIntLogicalType(64, true), parquet.Types.Int64
+	case parquetDouble:
+		physical = parquet.Types.Double
+	case parquetDecimal:
+		logical = schema.NewDecimalLogicalType(column.precision, column.scale)
+	case parquetTimestamp:
+		logical = schema.NewTimestampLogicalType(true, schema.TimeUnitMicros)
+		physical = parquet.Types.Int64
+	case parquetLocalTimestamp:
+		logical = schema.NewTimestampLogicalType(false, schema.TimeUnitMicros)
+		physical = parquet.Types.Int64
+	case parquetJSON:
+		logical = schema.JSONLogicalType{}
+	case parquetString:
+		logical = schema.StringLogicalType{}
+	}
+	return schema.NewPrimitiveNodeLogical(column.name, repetition, logical, physical, -1, -1)
+}
+
+func parquetText(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case []byte:
+		return string(typed), nil
+	}
+	return formatCSVValue(value, "")
+}
+
+func parquetIntegerValue(value interface{}) (int64, error) {
+	switch typed := value.(type) {
+	case bool:
+		if typed {
+			return 1, nil
+		}
+		return 0, nil
+	case string, []byte, json.Number, fmt.Stringer:
+		text, err := parquetText(value)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if reflected.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows a 64-bit integer", reflected.Uint())
+		}
+		return int64(reflected.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		number := reflected.Float()
+		if number != math.Trunc(number) || math.Abs(number) > math.MaxInt64 {
+			return 0, fmt.Errorf("%v is not an integer", number)
+		}
+		return int64(number), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to an integer", value)
+}
+
+func parquetFloatValue(value interface{}) (float64, error) {
+	switch typed := value.(type) {
+	case string, []byte, json.Number, fmt.Stringer:
+		text, err := parquetText(typed)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to a number", value)
+}
+
+func parquetBooleanValue(value interface{}) (bool, error) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, nil
+	case []byte:
+		// MySQL returns BIT(1) as a single raw byte.
+		if len(typed) == 1 && typed[0] <= 1 {
+			return typed[0] == 1, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(string(typed)))
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(typed))
+	}
+	number, err := parquetIntegerValue(value)
+	if err != nil || (number != 0 && number != 1) {
+		return false, fmt.Errorf("cannot convert %v to a boolean", value)
+	}
+	return number == 1, nil
+}
+
+var parquetTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parquetMicros converts a value to microseconds since the Unix epoch. Local
+// timestamps keep the wall clock the database returned, whatever zone the
+// driver attached to it.
+func parquetMicros(value interface{}, local bool) (int64, error) {
+	moment, ok := value.(time.Time)
+	if !ok {
+		text, err := parquetText(value)
+		if err != nil {
+			return 0, err
+		}
+		text = strings.TrimSpace(text)
+		parsed := false
+		for _, layout := range parquetTimeLayouts {
+			if moment, err = time.Parse(layout, text); err == nil {
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return 0, fmt.Errorf("cannot parse %q as a timestamp", text)
+		}
+	}
+	if local {
+		moment = time.Date(
+			moment.Year(), moment.Month(), moment.Day(),
+			moment.Hour(), moment.Minute(), moment.Second(), moment.Nanosecond(),
+			time.UTC,
+		)
+	}
+	return moment.UnixMicro(), nil
+}
+
+func parquetBytesValue(value interface{}) (parquet.ByteArray, error) {
+	if typed, ok := value.([]byte); ok {
+		return parquet.ByteArray(typed), nil
+	}
+	text, err := parquetText(value)
+	return parquet.ByteArray(text), err
+}
+
+func parquetJSONValue(value interface{}) (parquet.ByteArray, error) {
+	normalized, err := normalizeJSONValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if text, ok := normalized.(string); ok && json.Valid([]byte(text)) {
+		return parquet.ByteArray(text), nil
+	}
+	encoded, err := json.Marshal(normalized)
+	return parquet.ByteArray(encoded), err
+}
+
+// parquetDecimalValue encodes value as the big-endian two's complement unscaled
+// integer Parquet stores for a DECIMAL, rounding half away from zero to the
+// column's scale.
+func parquetDecimalValue(value interface{}, precision, scale int32) (parquet.ByteArray, error) {
+	var text string
+	switch typed := value.(type) {
+	case float32:
+		text = strconv.FormatFloat(float64(typed), 'f', -1, 32)
+	case float64:
+		text = strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		var err error
+		if text, err = parquetText(value); err != nil {
+			return nil, err
+		}
+	}
+	rational, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok {
+		return nil, fmt.Errorf("cannot parse %q as a decimal", text)
+	}
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	rational.Mul(rational, new(big.Rat).SetInt(factor))
+	unscaled, remainder := new(big.Int).QuoRem(rational.Num(), rational.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		doubled := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+		if doubled.Cmp(rational.Denom()) >= 0 {
+			unscaled.Add(unscaled, big.NewInt(int64(rational.Sign())))
+		}
+	}
+	if digits := len(new(big.Int).Abs(unscaled).String()); digits > int(precision) {
+		return nil, fmt.Errorf("%s does not fit DECIMAL(%d,%d)", text, precision, scale)
+	}
+	if unscaled.Sign() >= 0 {
+		encoded := unscaled.Bytes()
+		if len(encoded) == 0 || encoded[0]&0x80 != 0 {
+			encoded = append([]byte{0}, encoded...)
+		}
+		return encoded, nil
+	}
+	width := unscaled.BitLen()/8 + 1
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(width*8))
+	return modulus.Add(modulus, unscaled).Bytes(), nil
+}
+
+// writeParquetColumn writes one column of a buffered row group. Optional
+// columns get a definition level per row and hold only the non-null values.
+func writeParquetColumn[T any](
+	column parquetColumn,
+	rows [][]interface{},
+	index int,
+	convert func(interface{}) (T, error),
+	write func([]T, []int16) (int64, error),
+) error {
+	values := make([]T, 0, len(rows))
+	var levels []int16
+	if column.optional {
+		levels = make([]int16, len(rows))
+	}
+	for rowIndex, row := range rows {
+		value := row[index]
+		if value == nil {
+			if !column.optional {
+				return fmt.Errorf("column %q is NOT NULL but row %d is null", column.name, rowIndex+1)
+			}
+			continue
+		}
+		converted, err := convert(value)
+		if err != nil {
+			return fmt.Errorf("column %q: %w", column.name, err)
+		}
+		values = append(values, converted)
+		if levels != nil {
+			levels[rowIndex] = 1
+		}
+	}
+	_, err := write(values, levels)
+	return err
+}
+
+func (column parquetColumn) write(
+	chunk file.ColumnChunkWriter,
+	rows [][]interface{},
+	index int,
+) error {
+	switch writer := chunk.(type) {
+	case *file.BooleanColumnChunkWriter:
+		return writeParquetColumn(column, rows, index, parquetBooleanValue,
+			func(values []bool, levels []int16) (int64, error) {
+				return writer.WriteBatch(values, levels, nil)
+			})
+	case *file.Int64ColumnChunkWriter:
+		convert := parquetIntegerValue
+		if column.kind == parquetTimestamp || column.kind == parquetLocalTimestamp {
+			local := column.kind == parquetLocalTimestamp
+			convert = func(value interface{}) (int64, error) {
+				return parquetMicros(value, local)
+			}
+		}
+		return writeParquetColumn(column, rows, index, convert,
+			func(values []int64, levels []int16) (int64, error) {
+				return writer.WriteBatch(values, levels, nil)
+			})
+	case *file.Float64ColumnChunkWriter:
+		return writeParquetColumn(column, rows, index, parquetFloatValue,
+			func(values []float64, levels []int16) (int64, error) {
+				return writer.WriteBatch(values, levels, nil)
+			})
+	case *file.ByteArrayColumnChunkWriter:
+		convert := parquetBytesValue
+		switch column.kind {
+		case parquetDecimal:
+			convert = func(value interface{}) (parquet.ByteArray, error) {
+				return parquetDecimalValue(value, column.precision, column.scale)
+			}
+		case parquetJSON:
+			convert = parquetJSONValue
+		}
+		return writeParquetColumn(column, rows, index, convert,
+			func(values []parquet.ByteArray, levels []int16) (int64, error) {
+				return writer.WriteBatch(values, levels, nil)
+			})
+	default:
+		return fmt.Errorf("unexpected Parquet column writer %T", chunk)
+	}
+}
+
+type parquetSink struct {
+	output     io.Writer
+	props      *parquet.WriterProperties
+	names      []string
+	structures map[string]Structure
+	columns    []parquetColumn
+	writer     *file.Writer
+	rows       [][]interface{}
+}
+
+func newParquetSink(
+	writer io.Writer,
+	columns []string,
+	structures Structures,
+	options ParquetOptions,
+) (*parquetSink, error) {
+	codec, err := parquetCodec(options.Compression)
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]Structure, len(structures))
+	for _, structure := range structures {
+		declared[structure.Name] = structure
+	}
+	return &parquetSink{
+		// Hide any Close method: the Parquet writer closes a closable sink,
+		// but the caller owns the destination.
+		output: struct{ io.Writer }{writer},
+		props: parquet.NewWriterProperties(
+			parquet.WithCompression(codec),
+			parquet.WithMaxRowGroupLength(parquetRowGroupRows),
+		),
+		names:      uniqueParquetNames(columns),
+		structures: declared,
+		rows:       make([][]interface{}, 0, parquetRowGroupRows),
+	}, nil
+}
+
+// uniqueParquetNames suffixes repeated result column names, which a Parquet
+// schema cannot hold twice.
+func uniqueParquetNames(columns []string) []string {
+	names := make([]string, len(columns))
+	used := make(map[string]bool, len(columns))
+	for index, column := range columns {
+		name := column
+		if name == "" {
+			name = fmt.Sprintf("column_%d", index+1)
+		}
+		for suffix := 2; used[name]; suffix++ {
+			name = fmt.Sprintf("%s_%d", column, suffix)
+		}
+		used[name] = true
+		names[index] = name
+	}
+	return names
+}
+
+// open fixes the file schema from the declared structures, inferring any
+// remaining column from the first buffered row group.
+func (s *parquetSink) open(columns []string) error {
+	fields := make(schema.FieldList, len(columns))
+	s.columns = make([]parquetColumn, len(columns))
+	for index, name := range columns {
+		structure, declared := s.structures[name]
+		column, typed := parquetColumnFor(structure)
+		if !declared || !typed {
+			column = inferParquetColumn(name, s.rows, index)
+		}
+		column.name = s.names[index]
+		node, err := column.node()
+		if err != nil {
+			return fmt.Errorf("describe Parquet column %q: %w", name, err)
+		}
+		s.columns[index] = column
+		fields[index] = node
+	}
+	root, err := schema.NewGroupNode("schema", parquet.Repetitions.Required, fields, -1)
+	if err != nil {
+		return fmt.Errorf("build Parquet schema: %w", err)
+	}
+	s.writer = file.NewParquetWriter(s.output, root, file.WithWriterProps(s.props))
+	return nil
+}
+
+func (s *parquetSink) flush(columns []string) error {
+	if s.writer == nil {
+		if err := s.open(columns); err != nil {
+			return err
+		}
+	}
+	if len(s.rows) == 0 {
+		return nil
+	}
+	group := s.writer.AppendRowGroup()
+	for index, column := range s.columns {
+		chunk, err := group.NextColumn()
+		if err != nil {
+			return err
+		}
+		if err := column.write(chunk, s.rows, index); err != nil {
+			return err
+		}
+	}
+	if err := group.Close(); err != nil {
+		return err
+	}
+	clear(s.rows)
+	s.rows = s.rows[:0]
+	return nil
+}
+
+func (s *parquetSink) writeValues(columns []string, values []interface{}) error {
+	s.rows = append(s.rows, values)
+	if len(s.rows) < parquetRowGroupRows {
+		return nil
+	}
+	return s.flush(columns)
+}
+
+func (s *parquetSink) close(columns []string) error {
+	if err := s.flush(columns); err != nil {
+		return err
+	}
+	return s.writer.Close()
+}
+
+// WriteParquetStreamContext writes rows as a Parquet file, one row group per
+// parquetRowGroupRows rows. Columns described by structures take their
+// Parquet type and nullability from it; the rest are typed from the values
+// of the first row group and are always nullable.
+func WriteParquetStreamContext(
+	ctx context.Context,
+	writer io.Writer,
+	rows RowStream,
+	structures Structures,
+	options ParquetOptions,
+) (ExportStats, error) {
+	if err := CheckExportContext(ctx); err != nil {
+		return ExportStats{}, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return ExportStats{}, fmt.Errorf("read export columns: %w", err)
+	}
+	sink, err := newParquetSink(writer, columns, structures, options)
+	if err != nil {
+		return ExportStats{}, err
+	}
+
+	var count int64
+	for rows.Next() {
+		if err := CheckExportContext(ctx); err != nil {
+			return ExportStats{}, err
+		}
+		values, err := rows.Values()
+		if err != nil {
+			return ExportStats{}, fmt.Errorf("read export row: %w", err)
+		}
+		if len(values) != len(columns) {
+			return ExportStats{}, fmt.Errorf(
+				"export row has %d values for %d columns",
+				len(values),
+				len(columns),
+			)
+		}
+		if err := sink.writeValues(columns, values); err != nil {
+			return ExportStats{}, fmt.Errorf("write Parquet row group: %w", err)
+		}
+		count++
+		ReportExportProgress(ctx, count)
+	}
+	if err := rows.Err(); err != nil {
+		return ExportStats{}, fmt.Errorf("read export rows: %w", err)
+	}
+	if err := CheckExportContext(ctx); err != nil {
+		return ExportStats{}, err
+	}
+	if err := sink.close(columns); err != nil {
+		return ExportStats{}, fmt.Errorf("finish Parquet export: %w", err)
+	}
+	return ExportStats{Rows: count}, nil
+}
+
+// heldRows streams rows the caller already holds as maps.
+type heldRows struct {
+	columns []string
+	rows    []map[string]interface{}
+	index   int
+}
+
+func (r *heldRows) Columns() ([]string, error) { return r.columns, nil }
+
+func (r *heldRows) Next() bool {
+	if r.index >= len(r.rows) {
+		return false
+	}
+	r.index++
+	return true
+}
+
+func (r *heldRows) Values() ([]interface{}, error) {
+	row := r.rows[r.index-1]
+	values := make([]interface{}, len(r.columns))
+	for index, column := range r.columns {
+		values[index] = row[column]
+	}
+	return values, nil
+}
+
+func (r *heldRows) Err() error { return nil }
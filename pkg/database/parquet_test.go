@@ -0,0 +1,128 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+)
+
+func TestWriteParquetUsesDeclaredTypesAndInfersTheRest(t *testing.T) {
+	var output bytes.Buffer
+	stats, err := WriteTypedExportRowsContext(
+		context.Background(),
+		&output,
+		[]string{"id", "price", "placed_at", "note", "score"},
+		[]map[string]interface{}{
+			{
+				"id":        int64(1),
+				"price":     "12.50",
+				"placed_at": time.Date(2026, time.May, 1, 9, 30, 0, 0, time.UTC),
+				"note":      nil,
+				"score":     int64(3),
+			},
+			{
+				"id":        int64(2),
+				"price":     "-0.05",
+				"placed_at": "2026-05-02T10:00:00Z",
+				"note":      "gift",
+				"score":     2.5,
+			},
+		},
+		Structures{
+			{Name: "id", DataType: "bigint"},
+			{Name: "price", DataType: "numeric(12,2)", Nullable: true},
+			{Name: "placed_at", DataType: "timestamp with time zone", Nullable: true},
+			{Name: "note", DataType: "text", Nullable: true},
+		},
+		ExportOptions{
+			Format:  ExportFormatParquet,
+			Parquet: ParquetOptions{Compression: ParquetCompressionZstd},
+		},
+	)
+	if err != nil {
+		t.Fatalf("WriteTypedExportRowsContext() error = %v", err)
+	}
+	if stats.Rows != 2 {
+		t.Fatalf("rows = %d", stats.Rows)
+	}
+
+	reader, err := file.NewParquetReader(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		t.Fatalf("NewParquetReader() error = %v", err)
+	}
+	defer reader.Close()
+	fileSchema := reader.MetaData().Schema
+	if reader.NumRows() != 2 || fileSchema.NumColumns() != 5 {
+		t.Fatalf("rows = %d, columns = %d", reader.NumRows(), fileSchema.NumColumns())
+	}
+	id := fileSchema.Column(0)
+	if id.PhysicalType() != parquet.Types.Int64 || id.MaxDefinitionLevel() != 0 {
+		t.Fatalf("id column = %s, max definition %d", id.PhysicalType(), id.MaxDefinitionLevel())
+	}
+	price, ok := fileSchema.Column(1).LogicalType().(schema.DecimalLogicalType)
+	if !ok || price.Precision() != 12 || price.Scale() != 2 {
+		t.Fatalf("price logical type = %s", fileSchema.Column(1).LogicalType())
+	}
+	placed, ok := fileSchema.Column(2).LogicalType().(schema.TimestampLogicalType)
+	if !ok || !placed.IsAdjustedToUTC() {
+		t.Fatalf("placed_at logical type = %s", fileSchema.Column(2).LogicalType())
+	}
+	if score := fileSchema.Column(4); score.PhysicalType() != parquet.Types.Double {
+		t.Fatalf("inferred score type = %s", score.PhysicalType())
+	}
+
+	chunk, err := reader.RowGroup(0).Column(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make([]parquet.ByteArray, 2)
+	levels := make([]int16, 2)
+	if _, read, err := chunk.(*file.ByteArrayColumnChunkReader).ReadBatch(2, values, levels, nil); err != nil || read != 2 {
+		t.Fatalf("ReadBatch() = %d, %v", read, err)
+	}
+	// 1250 and -5 as minimal big-endian two's complement.
+	if !bytes.Equal(values[0], []byte{0x04, 0xe2}) || !bytes.Equal(values[1], []byte{0xfb}) {
+		t.Fatalf("decimal bytes = %x, %x", values[0], values[1])
+	}
+}
+
+func TestWriteParquetRejectsNullsInRequiredColumnsAndOverflow(t *testing.T) {
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "is NOT NULL"},
+		{"123.45", "does not fit DECIMAL(4,2)"},
+	} {
+		_, err := WriteTypedExportRowsContext(
+			context.Background(),
+			&bytes.Buffer{},
+			[]string{"amount"},
+			[]map[string]interface{}{{"amount": test.value}},
+			Structures{{Name: "amount", DataType: "decimal(4,2)"}},
+			ExportOptions{Format: ExportFormatParquet},
+		)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Fatalf("value %#v error = %v, want %q", test.value, err, test.want)
+		}
+	}
+}
+
+func TestValidateParquetExportOptions(t *testing.T) {
+	if err := ValidateExportOptions(ExportOptions{Format: ExportFormatParquet}); err != nil {
+		t.Fatalf("default compression error = %v", err)
+	}
+	err := ValidateExportOptions(ExportOptions{
+		Format:  ExportFormatParquet,
+		Parquet: ParquetOptions{Compression: "lz4"},
+	})
+	if err == nil {
+		t.Fatal("unknown Parquet compression was accepted")
+	}
+}
@@ -0,0 +1,281 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	handshakeTimeout    = 10 * time.Second
+	defaultCallTimeout  = 2 * time.Minute
+	closeTimeout        = 5 * time.Second
+	maxMessageBytes     = 64 << 20
+	stderrTailBytes     = 4 << 10
+	protocolEnvironment = "ROLLINGTHUNDER_PLUGIN_PROTOCOL"
+)
+
+// ErrPluginExited is returned for every call made after the plugin process
+// stopped. The host keeps running; reconnecting launches a new process.
+var ErrPluginExited = errors.New("driver plugin process exited")
+
+type response struct {
+	result json.RawMessage
+	err    error
+}
+
+// client owns one plugin process. Calls are multiplexed by request ID, so a
+// slow query does not block metadata requests on the same connection.
+type client struct {
+	path string
+	cmd  *exec.Cmd
+
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan response
+	exitErr error
+
+	failOnce sync.Once
+
+	stderr *tailBuffer
+	done   chan struct{}
+}
+
+// tailBuffer keeps the last bytes a plugin wrote to stderr so a crash can be
+// reported with the plugin's own explanation.
+type tailBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (buffer *tailBuffer) Write(p []byte) (int, error) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	buffer.data = append(buffer.data, p...)
+	if len(buffer.data) > stderrTailBytes {
+		buffer.data = buffer.data[len(buffer.data)-stderrTailBytes:]
+	}
+	return len(p), nil
+}
+
+func (buffer *tailBuffer) String() string {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	return strings.TrimSpace(string(buffer.data))
+}
+
+func startClient(path string) (*client, error) {
+	cmd := exec.Command(path)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", protocolEnvironment, ProtocolVersion))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &tailBuffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start driver plugin: %w", err)
+	}
+	c := &client{
+		path:    path,
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[uint64]chan response),
+		stderr:  stderr,
+		done:    make(chan struct{}),
+	}
+	go c.readLoop(stdout)
+	return c, nil
+}
+
+func (c *client) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	var readErr error
+	for scanner.Scan() {
+		var reply message
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&reply); err != nil || reply.ID == nil {
+			readErr = fmt.Errorf("driver plugin wrote an invalid message")
+			break
+		}
+		c.mu.Lock()
+		waiter := c.pending[*reply.ID]
+		delete(c.pending, *reply.ID)
+		c.mu.Unlock()
+		if waiter == nil {
+			continue
+		}
+		if reply.Error != nil {
+			waiter <- response{err: reply.Error}
+		} else {
+			waiter <- response{result: reply.Result}
+		}
+	}
+	if readErr == nil {
+		readErr = scanner.Err()
+	}
+	c.fail(readErr)
+}
+
+// fail stops the process and releases every waiting call. It is the single
+// place a plugin crash is converted into errors for the host. The read loop
+// and the shutdown timeout can both get here, so only the first caller kills
+// and reaps the process, and its cause is the one reported.
+func (c *client) fail(cause error) {
+	c.failOnce.Do(func() {
+		_ = c.cmd.Process.Kill()
+		waitErr := c.cmd.Wait()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		detail := ErrPluginExited
+		if cause == nil {
+			cause = waitErr
+		}
+		if tail := c.stderr.String(); tail != "" {
+			c.exitErr = fmt.Errorf("%w: %s", detail, tail)
+		} else if cause != nil {
+			c.exitErr = fmt.Errorf("%w: %v", detail, cause)
+		} else {
+			c.exitErr = detail
+		}
+		for id, waiter := range c.pending {
+			waiter <- response{err: c.exitErr}
+			delete(c.pending, id)
+		}
+		close(c.done)
+	})
+}
+
+func (c *client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultCallTimeout)
+		defer cancel()
+	}
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encode %s parameters: %w", method, err)
+	}
+
+	c.mu.Lock()
+	if c.exitErr != nil {
+		err := c.exitErr
+		c.mu.Unlock()
+		return err
+	}
+	c.nextID++
+	id := c.nextID
+	waiter := make(chan response, 1)
+	c.pending[id] = waiter
+	c.mu.Unlock()
+
+	if err := c.send(message{JSONRPC: "2.0", ID: &id, Method: method, Params: encodedParams}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		exitErr := c.exitErr
+		c.mu.Unlock()
+		if exitErr != nil {
+			return exitErr
+		}
+		return fmt.Errorf("send %s to driver plugin: %w", method, err)
+	}
+
+	select {
+	case reply := <-waiter:
+		if reply.err != nil {
+			return reply.err
+		}
+		if result == nil || len(reply.result) == 0 {
+			return nil
+		}
+		decoder := json.NewDecoder(bytes.NewReader(reply.result))
+		decoder.UseNumber()
+		if err := decoder.Decode(result); err != nil {
+			return fmt.Errorf("decode %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		cancelParams, _ := json.Marshal(CancelParams{ID: id})
+		_ = c.send(message{JSONRPC: "2.0", Method: MethodCancel, Params: cancelParams})
+		return ctx.Err()
+	}
+}
+
+func (c *client) send(request message) error {
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.stdin.Write(append(encoded, '\n'))
+	return err
+}
+
+// shutdown asks the plugin to close its connection and then stops the
+// process, killing it if it does not exit in time.
+func (c *client) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	closeErr := c.call(ctx, MethodClose, struct{}{}, nil)
+	_ = c.stdin.Close()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.fail(errors.New("driver plugin did not exit after close"))
+	}
+	if errors.Is(closeErr, ErrPluginExited) {
+		return nil
+	}
+	return closeErr
+}
+
+func (c *client) handshake() (HandshakeResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	var result HandshakeResult
+	if err := c.call(ctx, MethodHandshake, HandshakeParams{
+		ProtocolVersions: []int{ProtocolVersion},
+	}, &result); err != nil {
+		return HandshakeResult{}, fmt.Errorf("driver plugin handshake: %w", err)
+	}
+	if result.ProtocolVersion != ProtocolVersion {
+		return HandshakeResult{}, fmt.Errorf(
+			"driver plugin speaks protocol version %d; this build requires %d",
+			result.ProtocolVersion,
+			ProtocolVersion,
+		)
+	}
+	if strings.TrimSpace(result.Driver) == "" {
+		return HandshakeResult{}, fmt.Errorf("driver plugin did not report a driver name")
+	}
+	if err := result.Capabilities.Validate(); err != nil {
+		return HandshakeResult{}, fmt.Errorf("driver plugin capabilities: %w", err)
+	}
+	return result, nil
+}
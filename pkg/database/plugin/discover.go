@@ -0,0 +1,233 @@
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// AllowlistFile names the file in the plugins directory that lists the
+// executables the host may launch. Nothing else in the directory is run.
+const AllowlistFile = "allowlist.json"
+
+const allowlistVersion = 1
+
+type Allowlist struct {
+	Version int             `json:"version"`
+	Plugins []AllowedPlugin `json:"plugins"`
+}
+
+// AllowedPlugin pins one executable by SHA-256. File is a name inside the
+// plugins directory; paths are rejected so the allowlist cannot point at
+// binaries elsewhere on disk.
+type AllowedPlugin struct {
+	Driver string `json:"driver"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// Status reports what happened to one allowlist entry during discovery.
+type Status struct {
+	Driver       string                 `json:"driver"`
+	File         string                 `json:"file"`
+	Loaded       bool                   `json:"loaded"`
+	Error        string                 `json:"error,omitempty"`
+	Capabilities *database.Capabilities `json:"capabilities,omitempty"`
+}
+
+// Discover reads the allowlist in dir, verifies and handshakes each listed
+// plugin, and registers the ones that pass with database.RegisterDriver. A
+// missing allowlist means no plugins are installed and is not an error.
+func Discover(dir string) ([]Status, error) {
+	allowlist, err := ReadAllowlist(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(allowlist.Plugins))
+	for _, entry := range allowlist.Plugins {
+		status := Status{Driver: strings.ToLower(strings.TrimSpace(entry.Driver)), File: entry.File}
+		capabilities, err := register(dir, entry)
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Loaded = true
+			status.Capabilities = &capabilities
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func ReadAllowlist(dir string) (Allowlist, error) {
+	content, err := os.ReadFile(filepath.Join(dir, AllowlistFile))
+	if err != nil {
+		return Allowlist{}, err
+	}
+	var allowlist Allowlist
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&allowlist); err != nil {
+		return Allowlist{}, fmt.Errorf("read plugin allowlist: %w", err)
+	}
+	if allowlist.Version != allowlistVersion {
+		return Allowlist{}, fmt.Errorf(
+			"plugin allowlist version %d is not supported; expected %d",
+			allowlist.Version,
+			allowlistVersion,
+		)
+	}
+	return allowlist, nil
+}
+
+func register(dir string, entry AllowedPlugin) (database.Capabilities, error) {
+	name := strings.ToLower(strings.TrimSpace(entry.Driver))
+	if name == "" {
+		return database.Capabilities{}, fmt.Errorf("plugin driver name is required")
+	}
+	if database.IsRegisteredDriver(name) {
+		return database.Capabilities{}, fmt.Errorf("driver %s is already registered", name)
+	}
+	// Handshake once so invalid capabilities are rejected before the driver is
+	// offered in the connection form.
+	described, err := openVerified(dir, entry, database.Config{Driver: name})
+	if err != nil {
+		return database.Capabilities{}, err
+	}
+	_ = described.Close()
+	if described.Name() != name {
+		return database.Capabilities{}, fmt.Errorf(
+			"plugin reports driver %q but the allowlist lists %q",
+			described.Name(),
+			name,
+		)
+	}
+	capabilities := described.Capabilities()
+
+	database.RegisterDriver(
+		name,
+		func(_ context.Context, config database.Config) (database.Driver, error) {
+			return openVerified(dir, entry, config)
+		},
+		func(database.ExecutableLookup) database.DriverProbe {
+			return database.DriverProbe{Capabilities: capabilities}
+		},
+	)
+	return capabilities, nil
+}
+
+// openVerified stages entry and launches the staged copy, which is removed
+// when the driver closes.
+func openVerified(dir string, entry AllowedPlugin, config database.Config) (*Driver, error) {
+	path, cleanup, err := StageExecutable(dir, entry)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := Open(path, config)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	driver.cleanup = cleanup
+	return driver, nil
+}
+
+// StageExecutable copies the plugin entry names into a private directory,
+// checks the copy's checksum, and returns its path with a function that
+// removes it. Launching the copy runs exactly the bytes that were verified,
+// even if the file in dir is replaced between the check and the launch. It
+// runs before every launch, so replacing an allowlisted binary disables it.
+func StageExecutable(dir string, entry AllowedPlugin) (string, func(), error) {
+	file := strings.TrimSpace(entry.File)
+	if file == "" || file != filepath.Base(file) || file == "." || file == ".." {
+		return "", nil, fmt.Errorf("plugin file %q must be a file name inside the plugins directory", entry.File)
+	}
+	if file == AllowlistFile {
+		return "", nil, fmt.Errorf("the allowlist cannot be used as a plugin")
+	}
+	expected := strings.ToLower(strings.TrimSpace(entry.SHA256))
+	if len(expected) != sha256.Size*2 {
+		return "", nil, fmt.Errorf("plugin %s needs a SHA-256 checksum", file)
+	}
+	path := filepath.Join(dir, file)
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("plugin %s: %w", file, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", nil, fmt.Errorf("plugin %s is not a regular file", file)
+	}
+
+	// MkdirTemp creates the directory with mode 0700, so no other user can
+	// swap the copy before it is launched.
+	private, err := os.MkdirTemp("", "rollingthunder-plugin-")
+	if err != nil {
+		return "", nil, fmt.Errorf("stage plugin %s: %w", file, err)
+	}
+	cleanup := func() { _ = os.RemoveAll(private) }
+	staged := filepath.Join(private, file)
+	if err := copyExecutable(path, staged); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("stage plugin %s: %w", file, err)
+	}
+	actual, err := fileChecksum(staged)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("plugin %s: %w", file, err)
+	}
+	if actual != expected {
+		cleanup()
+		return "", nil, fmt.Errorf("plugin %s does not match its allowlisted checksum", file)
+	}
+	return staged, cleanup, nil
+}
+
+// copyExecutable copies source to a new file at target that only the
+// current user can read, write, or run.
+func copyExecutable(source string, target string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	info, err := input.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", filepath.Base(source))
+	}
+	output, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o700)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(output, input); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,432 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+const rowsBatchSize = 500
+
+// Driver is the host side of a driver plugin. Every instance owns its own
+// plugin process, so a crash affects only the connection that caused it.
+type Driver struct {
+	path      string
+	config    database.Config
+	handshake HandshakeResult
+	client    *client
+	// cleanup removes the staged executable the process was launched from.
+	cleanup func()
+}
+
+var (
+	_ database.Driver            = (*Driver)(nil)
+	_ database.DriverWithSchema  = (*Driver)(nil)
+	_ database.ObjectDriver      = (*Driver)(nil)
+	_ database.ExplainPlanDriver = (*Driver)(nil)
+	_ database.HealthDriver      = (*Driver)(nil)
+)
+
+// Open launches the plugin executable at path and performs the handshake.
+// The database connection itself is opened by Connect.
+func Open(path string, config database.Config) (*Driver, error) {
+	c, err := startClient(path)
+	if err != nil {
+		return nil, err
+	}
+	handshake, err := c.handshake()
+	if err != nil {
+		_ = c.shutdown()
+		return nil, err
+	}
+	handshake.Capabilities = sanitizeCapabilities(handshake.Capabilities, handshake.Features)
+	return &Driver{
+		path:      path,
+		config:    config,
+		handshake: handshake,
+		client:    c,
+	}, nil
+}
+
+// sanitizeCapabilities clears flags for workflows the protocol does not carry
+// yet, so the UI never offers a feature whose host-side interface is missing.
+func sanitizeCapabilities(capabilities database.Capabilities, features Features) database.Capabilities {
+	capabilities.Schemas = capabilities.Schemas && features.Schemas
+	capabilities.ObjectDefinitions = capabilities.ObjectDefinitions && features.Objects
+	capabilities.ObjectDependencies = capabilities.ObjectDependencies && features.Objects
+	capabilities.ExplainPlans = capabilities.ExplainPlans && features.ExplainPlans
+	capabilities.ManageViews = false
+	capabilities.ManageRoutines = false
+	capabilities.ManageTriggers = false
+	capabilities.TriggerToggle = false
+	capabilities.ManageIndexes = false
+	capabilities.AlterTableStructure = false
+	capabilities.Transactions = false
+	capabilities.TransactionalDDL = false
+	capabilities.AtomicTableChanges = false
+	capabilities.SQLInsertExport = false
+	capabilities.ManageSecurity = false
+	capabilities.ActivityMonitor = false
+	capabilities.QueryCursors = false
+	capabilities.RowEstimates = false
+	capabilities.ReturningRows = false
+	return capabilities
+}
+
+// Name returns the driver name the plugin reported during the handshake.
+func (d *Driver) Name() string {
+	return d.handshake.Driver
+}
+
+func (d *Driver) Capabilities() database.Capabilities {
+	return d.handshake.Capabilities
+}
+
+func (d *Driver) QuoteIdentifier(identifier string) string {
+	dialect := d.handshake.Capabilities.Dialect
+	escaped := strings.ReplaceAll(
+		identifier,
+		dialect.IdentifierClose,
+		dialect.IdentifierClose+dialect.IdentifierClose,
+	)
+	return dialect.IdentifierOpen + escaped + dialect.IdentifierClose
+}
+
+func (d *Driver) Placeholder(position int) string {
+	if position < 1 {
+		position = 1
+	}
+	switch d.handshake.Capabilities.Dialect.PlaceholderStyle {
+	case database.PlaceholderDollar:
+		return fmt.Sprintf("$%d", position)
+	case database.PlaceholderColon:
+		return fmt.Sprintf(":%d", position)
+	case database.PlaceholderAt:
+		return fmt.Sprintf("@p%d", position)
+	default:
+		return "?"
+	}
+}
+
+func (d *Driver) PaginationClause(limit, offset int) (string, error) {
+	if limit < 0 {
+		return "", fmt.Errorf("pagination limit cannot be negative")
+	}
+	if offset < 0 {
+		return "", fmt.Errorf("pagination offset cannot be negative")
+	}
+	if d.handshake.Capabilities.Dialect.PaginationStyle == database.PaginationOffsetFetch {
+		return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit), nil
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset), nil
+}
+
+func (d *Driver) Connect(ctx context.Context) error {
+	return d.client.call(ctx, MethodConnect, ConnectParams{Config: d.config}, nil)
+}
+
+func (d *Driver) Close() error {
+	err := d.client.shutdown()
+	if d.cleanup != nil {
+		d.cleanup()
+	}
+	return err
+}
+
+func (d *Driver) Ping(ctx context.Context) error {
+	if !d.handshake.Features.Ping {
+		_, err := d.GetDatabaseInfo()
+		return err
+	}
+	return d.client.call(ctx, MethodPing, struct{}{}, nil)
+}
+
+func (d *Driver) CountCollectionData(table database.Table) (int, error) {
+	return d.CountCollectionDataContext(context.Background(), table)
+}
+
+func (d *Driver) CountCollectionDataContext(ctx context.Context, table database.Table) (int, error) {
+	if err := d.checkTableFilters(table); err != nil {
+		return 0, err
+	}
+	var result ValueResult[int]
+	err := d.client.call(ctx, MethodCountCollectionData, TableParams{Table: table}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetCollectionData(table database.Table) (database.Structures, []map[string]interface{}, error) {
+	if err := d.checkTableFilters(table); err != nil {
+		return nil, nil, err
+	}
+	var result CollectionDataResult
+	if err := d.client.call(context.Background(), MethodGetCollectionData, TableParams{Table: table}, &result); err != nil {
+		return nil, nil, err
+	}
+	return result.Structures, result.Rows, nil
+}
+
+func (d *Driver) GetCollections(schema ...string) ([]string, error) {
+	var result ValueResult[[]string]
+	err := d.client.call(context.Background(), MethodGetCollections, CollectionsParams{Schemas: schema}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetCollectionStructures(table database.Table) (database.Structures, error) {
+	var result ValueResult[database.Structures]
+	err := d.client.call(context.Background(), MethodGetCollectionStructures, TableParams{Table: table}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetIndices(table database.Table) (database.Indices, error) {
+	var result ValueResult[database.Indices]
+	err := d.client.call(context.Background(), MethodGetIndices, TableParams{Table: table}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetDatabaseInfo() (database.Info, error) {
+	var result ValueResult[database.Info]
+	err := d.client.call(context.Background(), MethodGetDatabaseInfo, struct{}{}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetSchemas() ([]string, error) {
+	if !d.handshake.Features.Schemas {
+		return nil, unsupported(MethodGetSchemas)
+	}
+	var result ValueResult[[]string]
+	err := d.client.call(context.Background(), MethodGetSchemas, struct{}{}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) InsertRow(table database.Table, data map[string]interface{}) error {
+	return d.client.call(context.Background(), MethodInsertRow, RowParams{Table: table, Data: data}, nil)
+}
+
+func (d *Driver) UpdateRow(table database.Table, data map[string]interface{}, primaryKey string) error {
+	return d.client.call(context.Background(), MethodUpdateRow, RowParams{
+		Table:      table,
+		Data:       data,
+		PrimaryKey: primaryKey,
+	}, nil)
+}
+
+func (d *Driver) DeleteRow(table database.Table, primaryKey string, primaryValue interface{}) error {
+	return d.client.call(context.Background(), MethodDeleteRow, RowParams{
+		Table:        table,
+		PrimaryKey:   primaryKey,
+		PrimaryValue: primaryValue,
+	}, nil)
+}
+
+func (d *Driver) ExecuteQuery(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	var result ValueResult[database.QueryResult]
+	err := d.client.call(ctx, MethodExecuteQuery, QueryParams{
+		Query:        query,
+		MaxRows:      options.MaxRows,
+		Args:         options.Args,
+		ServerOutput: options.ServerOutput,
+	}, &result)
+	return result.Value, err
+}
+
+// ExportTable pulls rows from the plugin in batches and formats them on the
+// host, so plugins never write to the user's filesystem.
+func (d *Driver) ExportTable(
+	ctx context.Context,
+	request database.TableExportRequest,
+	writer io.Writer,
+) (database.ExportStats, error) {
+	if request.Options.Format == database.ExportFormatSQL {
+		return database.ExportStats{}, fmt.Errorf(
+			"SQL INSERT export is not available for driver plugins",
+		)
+	}
+	if err := database.ValidateExportOptions(request.Options); err != nil {
+		return database.ExportStats{}, err
+	}
+	if err := d.checkTableFilters(request.Table); err != nil {
+		return database.ExportStats{}, err
+	}
+	if !d.handshake.Features.RowStreams {
+		return database.ExportStats{}, unsupported(MethodOpenRows)
+	}
+	var opened OpenRowsResult
+	if err := d.client.call(ctx, MethodOpenRows, OpenRowsParams{Request: request}, &opened); err != nil {
+		return database.ExportStats{}, err
+	}
+	rows := &remoteRows{ctx: ctx, client: d.client, id: opened.StreamID, columns: opened.Columns}
+	defer rows.close()
+	return database.WriteExportStreamContext(ctx, writer, rows, request.Options)
+}
+
+func (d *Driver) CreateTable(table database.Table, columns []database.ColumnDefinition) error {
+	return d.client.call(context.Background(), MethodCreateTable, CreateTableParams{
+		Table:   table,
+		Columns: columns,
+	}, nil)
+}
+
+func (d *Driver) DropTable(table database.Table) error {
+	return d.client.call(context.Background(), MethodDropTable, TableParams{Table: table}, nil)
+}
+
+func (d *Driver) TruncateTable(table database.Table) error {
+	return d.client.call(context.Background(), MethodTruncateTable, TableParams{Table: table}, nil)
+}
+
+func (d *Driver) GetTableDDL(table database.Table) (string, error) {
+	var result ValueResult[string]
+	err := d.client.call(context.Background(), MethodGetTableDDL, TableParams{Table: table}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetDataTypes() []database.DataType {
+	return append([]database.DataType(nil), d.handshake.DataTypes...)
+}
+
+func (d *Driver) ListObjects(ctx context.Context, filter database.ObjectFilter) ([]database.DatabaseObject, error) {
+	if !d.handshake.Features.Objects {
+		return nil, unsupported(MethodListObjects)
+	}
+	var result ValueResult[[]database.DatabaseObject]
+	err := d.client.call(ctx, MethodListObjects, ObjectListParams{Filter: filter}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) GetObjectDetail(
+	ctx context.Context,
+	reference database.ObjectReference,
+) (database.ObjectDetail, error) {
+	if !d.handshake.Features.Objects {
+		return database.ObjectDetail{}, unsupported(MethodGetObjectDetail)
+	}
+	var result ValueResult[database.ObjectDetail]
+	err := d.client.call(ctx, MethodGetObjectDetail, ObjectDetailParams{Reference: reference}, &result)
+	return result.Value, err
+}
+
+func (d *Driver) ExplainQuery(ctx context.Context, query string) (database.ExplainPlan, error) {
+	if !d.handshake.Features.ExplainPlans {
+		return database.ExplainPlan{}, unsupported(MethodExplainQuery)
+	}
+	var result ValueResult[database.ExplainPlan]
+	err := d.client.call(ctx, MethodExplainQuery, ExplainParams{Query: query}, &result)
+	return result.Value, err
+}
+
+// checkTableFilters refuses filter trees and JSON paths the plugin has not
+// declared it understands. A plugin that ignored Table.Where or a filter's
+// Path would return rows the user filtered out.
+func (d *Driver) checkTableFilters(table database.Table) error {
+	if table.Where != nil && !d.handshake.Capabilities.FilterGroups {
+		return fmt.Errorf("driver plugin %s does not support filter groups", d.handshake.Driver)
+	}
+	if !d.handshake.Capabilities.JSONPaths && tableUsesJSONPaths(table) {
+		return fmt.Errorf("driver plugin %s does not support JSON path filters", d.handshake.Driver)
+	}
+	return nil
+}
+
+func tableUsesJSONPaths(table database.Table) bool {
+	for _, sort := range table.Sorts {
+		if sort.Path != "" {
+			return true
+		}
+	}
+	return filterGroupUsesJSONPaths(table.FilterTree())
+}
+
+func filterGroupUsesJSONPaths(group database.FilterGroup) bool {
+	for _, filter := range group.Filters {
+		if filter.Path != "" {
+			return true
+		}
+	}
+	for _, child := range group.Groups {
+		if filterGroupUsesJSONPaths(child) {
+			return true
+		}
+	}
+	return false
+}
+
+func unsupported(method string) error {
+	return &RemoteError{
+		Code:    codeUnsupported,
+		Message: fmt.Sprintf("driver plugin does not implement %s", method),
+	}
+}
+
+// remoteRows adapts the rows.next batches to database.RowStream.
+type remoteRows struct {
+	ctx     context.Context
+	client  *client
+	id      string
+	columns []string
+	batch   [][]interface{}
+	current []interface{}
+	done    bool
+	closed  bool
+	err     error
+}
+
+func (rows *remoteRows) Columns() ([]string, error) {
+	return rows.columns, nil
+}
+
+func (rows *remoteRows) Next() bool {
+	for len(rows.batch) == 0 {
+		if rows.done || rows.err != nil {
+			return false
+		}
+		var result NextRowsResult
+		if err := rows.client.call(rows.ctx, MethodNextRows, NextRowsParams{
+			StreamID: rows.id,
+			MaxRows:  rowsBatchSize,
+		}, &result); err != nil {
+			rows.err = err
+			return false
+		}
+		rows.batch = result.Rows
+		rows.done = result.Done
+		if result.Done {
+			rows.closed = true
+		}
+	}
+	rows.current = rows.batch[0]
+	rows.batch = rows.batch[1:]
+	return true
+}
+
+func (rows *remoteRows) Values() ([]interface{}, error) {
+	if len(rows.current) != len(rows.columns) {
+		return nil, fmt.Errorf(
+			"driver plugin returned %d values for %d columns",
+			len(rows.current),
+			len(rows.columns),
+		)
+	}
+	return rows.current, nil
+}
+
+func (rows *remoteRows) Err() error {
+	return rows.err
+}
+
+func (rows *remoteRows) close() {
+	if rows.closed || errors.Is(rows.err, ErrPluginExited) {
+		return
+	}
+	rows.closed = true
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	_ = rows.client.call(ctx, MethodCloseRows, StreamParams{StreamID: rows.id}, nil)
+}
@@ -0,0 +1,349 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/drivertest"
+	"rollingthunder/pkg/database/sqlite"
+)
+
+const testPluginEnvironment = "ROLLINGTHUNDER_TEST_DRIVER_PLUGIN"
+
+// TestMain lets the test binary double as a plugin executable: when the host
+// side of a test launches it with testPluginEnvironment set, it serves the
+// built-in SQLite driver instead of running tests.
+func TestMain(m *testing.M) {
+	if name := os.Getenv(testPluginEnvironment); name != "" {
+		Run(Plugin{
+			Driver: name,
+			Open: func(ctx context.Context, config database.Config) (database.Driver, error) {
+				return &testSQLite{SQLite: sqlite.NewSQLite(ctx, sqlite.Config{Db: config.Db})}, nil
+			},
+		})
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// testSQLite adds row streams and crash triggers to the built-in driver.
+type testSQLite struct {
+	*sqlite.SQLite
+}
+
+func (driver *testSQLite) ExecuteQuery(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	switch query {
+	case "-- panic":
+		panic("query handler failed")
+	case "-- exit":
+		fmt.Fprintln(os.Stderr, "plugin lost its database handle")
+		os.Exit(3)
+	}
+	return driver.SQLite.ExecuteQuery(ctx, query, options)
+}
+
+func (driver *testSQLite) OpenTableRows(
+	ctx context.Context,
+	request database.TableExportRequest,
+) (database.RowStream, error) {
+	result, err := driver.SQLite.ExecuteQuery(
+		ctx,
+		"SELECT * FROM "+driver.QuoteIdentifier(request.Table.Name),
+		database.QueryOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sliceRows{
+		columns: database.QueryColumnNames(result.Columns),
+		rows:    result.Rows,
+		index:   -1,
+	}, nil
+}
+
+type sliceRows struct {
+	columns []string
+	rows    [][]interface{}
+	index   int
+}
+
+func (rows *sliceRows) Columns() ([]string, error) { return rows.columns, nil }
+func (rows *sliceRows) Next() bool                 { rows.index++; return rows.index < len(rows.rows) }
+func (rows *sliceRows) Err() error                 { return nil }
+
+func (rows *sliceRows) Values() ([]interface{}, error) {
+	return rows.rows[rows.index], nil
+}
+
+// installTestPlugin copies the test binary into a plugins directory and
+// writes an allowlist entry for it.
+func installTestPlugin(t *testing.T, driver string) (string, AllowedPlugin) {
+	t.Helper()
+	t.Setenv(testPluginEnvironment, driver)
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.Open(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	dir := t.TempDir()
+	target, err := os.OpenFile(filepath.Join(dir, "sqlite-plugin"), os.O_CREATE|os.O_WRONLY, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(target, hash), source); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Close(); err != nil {
+		t.Fatal(err)
+	}
+	entry := AllowedPlugin{Driver: driver, File: "sqlite-plugin", SHA256: hex.EncodeToString(hash.Sum(nil))}
+	writeAllowlist(t, dir, entry)
+	return dir, entry
+}
+
+func writeAllowlist(t *testing.T, dir string, entries ...AllowedPlugin) {
+	t.Helper()
+	content, err := json.Marshal(Allowlist{Version: allowlistVersion, Plugins: entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AllowlistFile), content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func connectPlugin(t *testing.T, driverName string) database.Driver {
+	t.Helper()
+	driver, err := database.OpenDriver(context.Background(), driverName, database.Config{
+		Driver: driverName,
+		Db:     filepath.Join(t.TempDir(), "plugin.sqlite3"),
+	})
+	if err != nil {
+		t.Fatalf("OpenDriver() error = %v", err)
+	}
+	t.Cleanup(func() { _ = driver.Close() })
+	if err := driver.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return driver
+}
+
+func TestPluginDriverPassesCapabilityContract(t *testing.T) {
+	dir, entry := installTestPlugin(t, "sqlite-plugin-contract")
+	statuses, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(statuses) != 1 || !statuses[0].Loaded {
+		t.Fatalf("Discover() = %+v", statuses)
+	}
+	if _, err := database.ProbeDriver(entry.Driver, nil); err != nil {
+		t.Fatalf("ProbeDriver() error = %v", err)
+	}
+
+	driver := connectPlugin(t, entry.Driver)
+	drivertest.RunCapabilityContract(t, driver, "sqlite")
+	if capabilities := driver.Capabilities(); capabilities.Transactions ||
+		capabilities.AlterTableStructure ||
+		!capabilities.ExplainPlans ||
+		!capabilities.ObjectDefinitions {
+		t.Fatalf("capabilities were not narrowed to the protocol: %+v", capabilities)
+	}
+
+	ctx := context.Background()
+	if _, err := driver.ExecuteQuery(ctx,
+		"CREATE TABLE points (id INTEGER PRIMARY KEY, label TEXT); "+
+			"INSERT INTO points (id, label) VALUES (1, 'one'), (2, 'two')",
+		database.QueryOptions{},
+	); err != nil {
+		t.Fatalf("ExecuteQuery(create) error = %v", err)
+	}
+	table := database.Table{Name: "points"}
+	if err := driver.InsertRow(table, map[string]interface{}{"id": 3, "label": "three"}); err != nil {
+		t.Fatalf("InsertRow() error = %v", err)
+	}
+	count, err := driver.CountCollectionData(table)
+	if err != nil || count != 3 {
+		t.Fatalf("CountCollectionData() = %d, %v", count, err)
+	}
+	result, err := driver.ExecuteQuery(ctx, "SELECT label FROM points WHERE id = ?", database.QueryOptions{
+		Args: []interface{}{2},
+	})
+	if err != nil || len(result.Rows) != 1 || result.RowMaps()[0]["label"] != "two" {
+		t.Fatalf("ExecuteQuery(select) = %+v, %v", result, err)
+	}
+
+	var exported bytes.Buffer
+	stats, err := driver.ExportTable(ctx, database.TableExportRequest{
+		Table: table,
+		Options: database.ExportOptions{
+			Format: database.ExportFormatCSV,
+			CSV:    database.CSVOptions{Delimiter: ",", IncludeHeader: true},
+		},
+	}, &exported)
+	if err != nil || stats.Rows != 3 || !strings.HasPrefix(exported.String(), "id,label\n1,one\n") {
+		t.Fatalf("ExportTable() = %+v %q, %v", stats, exported.String(), err)
+	}
+
+	objects, err := driver.(database.ObjectDriver).ListObjects(ctx, database.ObjectFilter{})
+	if err != nil || len(objects) == 0 {
+		t.Fatalf("ListObjects() = %+v, %v", objects, err)
+	}
+}
+
+func TestPluginCrashDoesNotReachTheHost(t *testing.T) {
+	dir, entry := installTestPlugin(t, "sqlite-plugin-crash")
+	if _, err := Discover(dir); err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	driver := connectPlugin(t, entry.Driver)
+	ctx := context.Background()
+
+	if _, err := driver.ExecuteQuery(ctx, "-- panic", database.QueryOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "panicked") {
+		t.Fatalf("panicking request error = %v", err)
+	}
+	if _, err := driver.GetDatabaseInfo(); err != nil {
+		t.Fatalf("plugin must keep serving after a recovered panic: %v", err)
+	}
+
+	_, err := driver.ExecuteQuery(ctx, "-- exit", database.QueryOptions{})
+	if !errors.Is(err, ErrPluginExited) || !strings.Contains(err.Error(), "lost its database handle") {
+		t.Fatalf("crashed request error = %v", err)
+	}
+	if _, err := driver.GetCollections(); !errors.Is(err, ErrPluginExited) {
+		t.Fatalf("calls after a crash error = %v", err)
+	}
+	if err := driver.Close(); err != nil {
+		t.Fatalf("Close() after a crash = %v", err)
+	}
+
+	// A fresh instance launches a new process.
+	replacement := connectPlugin(t, entry.Driver)
+	if _, err := replacement.GetDatabaseInfo(); err != nil {
+		t.Fatalf("replacement driver error = %v", err)
+	}
+}
+
+func TestConcurrentFailReportsTheFirstCause(t *testing.T) {
+	t.Setenv(testPluginEnvironment, "sqlite-plugin-fail")
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := startClient(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cause := errors.New("stopped by the test")
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.fail(cause)
+		}()
+	}
+	wg.Wait()
+	<-c.done
+
+	err = c.call(context.Background(), MethodHandshake, HandshakeParams{}, nil)
+	if !errors.Is(err, ErrPluginExited) || !strings.Contains(err.Error(), cause.Error()) {
+		t.Fatalf("call after fail error = %v", err)
+	}
+}
+
+func TestDiscoverOnlyLaunchesAllowlistedFiles(t *testing.T) {
+	dir, entry := installTestPlugin(t, "sqlite-plugin-allowlist")
+	tampered := entry
+	tampered.Driver = "sqlite-plugin-tampered"
+	tampered.SHA256 = strings.Repeat("0", 64)
+	outside := entry
+	outside.Driver = "sqlite-plugin-outside"
+	outside.File = "../sqlite-plugin"
+	writeAllowlist(t, dir, tampered, outside)
+
+	statuses, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("Discover() = %+v", statuses)
+	}
+	for _, status := range statuses {
+		if status.Loaded || database.IsRegisteredDriver(status.Driver) {
+			t.Fatalf("unverified plugin was registered: %+v", status)
+		}
+	}
+	if !strings.Contains(statuses[0].Error, "checksum") ||
+		!strings.Contains(statuses[1].Error, "inside the plugins directory") {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+
+	if statuses, err := Discover(t.TempDir()); err != nil || statuses != nil {
+		t.Fatalf("Discover(empty) = %+v, %v", statuses, err)
+	}
+}
+
+func TestStagedPluginRunsTheVerifiedCopy(t *testing.T) {
+	dir, entry := installTestPlugin(t, "sqlite-plugin-staged")
+	staged, cleanup, err := StageExecutable(dir, entry)
+	if err != nil {
+		t.Fatalf("StageExecutable() error = %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(staged) == filepath.Clean(dir) {
+		t.Fatalf("StageExecutable() = %q, want a copy outside the plugins directory", staged)
+	}
+	if info, err := os.Stat(filepath.Dir(staged)); err != nil ||
+		(runtime.GOOS != "windows" && info.Mode().Perm() != 0o700) {
+		t.Fatalf("staging directory = %v, %v; want mode 0700", info, err)
+	}
+
+	// Replacing the allowlisted file after the check does not change what runs.
+	if err := os.WriteFile(filepath.Join(dir, entry.File), []byte("replaced"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if actual, err := fileChecksum(staged); err != nil || actual != entry.SHA256 {
+		t.Fatalf("staged checksum = %q, %v; want %q", actual, err, entry.SHA256)
+	}
+	driver, err := Open(staged, database.Config{Driver: entry.Driver})
+	if err != nil {
+		t.Fatalf("Open(staged) error = %v", err)
+	}
+	driver.cleanup = cleanup
+	if err := driver.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(staged); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("staged copy after Close() = %v, want removed", err)
+	}
+	if _, _, err := StageExecutable(dir, entry); err == nil ||
+		!strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("StageExecutable(replaced) error = %v, want a checksum mismatch", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package plugin runs database drivers as separate executables. The host and
+// the plugin exchange newline-delimited JSON-RPC 2.0 messages over the
+// plugin's stdin and stdout. Methods mirror database.Driver, ObjectDriver,
+// ExplainPlanDriver, and RowStream; docs/PLUGINS.md describes the wire format.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"rollingthunder/pkg/database"
+)
+
+// ProtocolVersion is bumped for any incompatible change to a method name,
+// parameter shape, or result shape. Additive optional fields keep the version.
+const ProtocolVersion = 2
+
+const (
+	MethodHandshake = "plugin.handshake"
+	MethodCancel    = "$/cancel"
+
+	MethodConnect                 = "driver.connect"
+	MethodClose                   = "driver.close"
+	MethodPing                    = "driver.ping"
+	MethodCountCollectionData     = "driver.countCollectionData"
+	MethodGetCollectionData       = "driver.getCollectionData"
+	MethodGetCollections          = "driver.getCollections"
+	MethodGetCollectionStructures = "driver.getCollectionStructures"
+	MethodGetIndices              = "driver.getIndices"
+	MethodGetDatabaseInfo         = "driver.getDatabaseInfo"
+	MethodGetSchemas              = "driver.getSchemas"
+	MethodInsertRow               = "driver.insertRow"
+	MethodUpdateRow               = "driver.updateRow"
+	MethodDeleteRow               = "driver.deleteRow"
+	MethodExecuteQuery            = "driver.executeQuery"
+	MethodCreateTable             = "driver.createTable"
+	MethodDropTable               = "driver.dropTable"
+	MethodTruncateTable           = "driver.truncateTable"
+	MethodGetTableDDL             = "driver.getTableDDL"
+
+	MethodListObjects     = "objects.list"
+	MethodGetObjectDetail = "objects.detail"
+	MethodExplainQuery    = "plan.explain"
+
+	MethodOpenRows  = "rows.open"
+	MethodNextRows  = "rows.next"
+	MethodCloseRows = "rows.close"
+)
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeDriverError    = -32000
+	codeUnsupported    = -32001
+	codeCancelled      = -32002
+)
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RemoteError    `json:"error,omitempty"`
+}
+
+// RemoteError is an error reported by the plugin process.
+type RemoteError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (err *RemoteError) Error() string {
+	return err.Message
+}
+
+// Unsupported reports whether the plugin does not implement the method.
+func (err *RemoteError) Unsupported() bool {
+	return err.Code == codeUnsupported || err.Code == codeMethodNotFound
+}
+
+type HandshakeParams struct {
+	ProtocolVersions []int `json:"protocolVersions"`
+}
+
+// HandshakeResult describes the engine before any connection is opened. The
+// Features flags declare which optional interfaces the plugin implements.
+type HandshakeResult struct {
+	ProtocolVersion int                   `json:"protocolVersion"`
+	Driver          string                `json:"driver"`
+	Capabilities    database.Capabilities `json:"capabilities"`
+	DataTypes       []database.DataType   `json:"dataTypes"`
+	Features        Features              `json:"features"`
+}
+
+type Features struct {
+	Schemas      bool `json:"schemas"`
+	Objects      bool `json:"objects"`
+	ExplainPlans bool `json:"explainPlans"`
+	RowStreams   bool `json:"rowStreams"`
+	Ping         bool `json:"ping"`
+}
+
+type ConnectParams struct {
+	Config database.Config `json:"config"`
+}
+
+type TableParams struct {
+	Table database.Table `json:"table"`
+}
+
+type CollectionsParams struct {
+	Schemas []string `json:"schemas"`
+}
+
+type CollectionDataResult struct {
+	Structures database.Structures      `json:"structures"`
+	Rows       []map[string]interface{} `json:"rows"`
+}
+
+type RowParams struct {
+	Table        database.Table         `json:"table"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	PrimaryKey   string                 `json:"primaryKey,omitempty"`
+	PrimaryValue interface{}            `json:"primaryValue,omitempty"`
+}
+
+type QueryParams struct {
+	Query        string        `json:"query"`
+	MaxRows      int           `json:"maxRows"`
+	Args         []interface{} `json:"args,omitempty"`
+	ServerOutput bool          `json:"serverOutput,omitempty"`
+}
+
+type CreateTableParams struct {
+	Table   database.Table              `json:"table"`
+	Columns []database.ColumnDefinition `json:"columns"`
+}
+
+type ObjectListParams struct {
+	Filter database.ObjectFilter `json:"filter"`
+}
+
+type ObjectDetailParams struct {
+	Reference database.ObjectReference `json:"reference"`
+}
+
+type ExplainParams struct {
+	Query string `json:"query"`
+}
+
+type OpenRowsParams struct {
+	Request database.TableExportRequest `json:"request"`
+}
+
+type OpenRowsResult struct {
+	StreamID string   `json:"streamId"`
+	Columns  []string `json:"columns"`
+}
+
+type NextRowsParams struct {
+	StreamID string `json:"streamId"`
+	MaxRows  int    `json:"maxRows"`
+}
+
+// NextRowsResult carries positional values in the column order returned by
+// rows.open. Done is set once the stream is exhausted.
+type NextRowsResult struct {
+	Rows [][]interface{} `json:"rows"`
+	Done bool            `json:"done"`
+}
+
+type StreamParams struct {
+	StreamID string `json:"streamId"`
+}
+
+type CancelParams struct {
+	ID uint64 `json:"id"`
+}
+
+type ValueResult[T any] struct {
+	Value T `json:"value"`
+}
+
+func negotiateVersion(versions []int) (int, error) {
+	for _, version := range versions {
+		if version == ProtocolVersion {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf(
+		"plugin protocol versions %v are not supported; this build speaks version %d",
+		versions,
+		ProtocolVersion,
+	)
+}
@@ -0,0 +1,583 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"rollingthunder/pkg/database"
+)
+
+// Plugin describes the driver a plugin executable serves. Open follows the
+// database.DriverFactory contract: it builds the driver without connecting.
+// It is also called once with an empty Config to answer the handshake, so it
+// must not fail just because connection settings are missing.
+type Plugin struct {
+	Driver string
+	Open   database.DriverFactory
+}
+
+// RowStreamDriver lets a plugin stream table rows to the host for exports.
+// Streams that implement io.Closer are closed when the host is done.
+type RowStreamDriver interface {
+	OpenTableRows(ctx context.Context, request database.TableExportRequest) (database.RowStream, error)
+}
+
+// Run serves plugin on the process's stdin and stdout. Plugin executables
+// call it from main; anything the driver logs should go to stderr.
+func Run(plugin Plugin) {
+	if err := Serve(context.Background(), plugin, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Serve answers host requests until in is closed or driver.close is handled.
+// Requests run concurrently; a panic in one request is reported to the host
+// as an error for that request instead of ending the process.
+func Serve(ctx context.Context, plugin Plugin, in io.Reader, out io.Writer) error {
+	if plugin.Open == nil {
+		return fmt.Errorf("plugin %q has no driver factory", plugin.Driver)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	server := &server{
+		ctx:      ctx,
+		plugin:   plugin,
+		out:      out,
+		inflight: make(map[uint64]context.CancelFunc),
+		streams:  make(map[string]database.RowStream),
+	}
+	defer server.closeDriver()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	var requests sync.WaitGroup
+	defer requests.Wait()
+	for scanner.Scan() {
+		var request message
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&request); err != nil {
+			server.reply(nil, nil, &RemoteError{Code: codeParseError, Message: "invalid JSON-RPC message"})
+			continue
+		}
+		if request.Method == MethodCancel {
+			server.cancel(request.Params)
+			continue
+		}
+		if request.ID == nil {
+			continue
+		}
+		if request.Method == MethodClose {
+			requests.Wait()
+			server.closeDriver()
+			server.reply(request.ID, struct{}{}, nil)
+			return nil
+		}
+		requestCtx, cancelRequest := context.WithCancel(ctx)
+		server.track(*request.ID, cancelRequest)
+		requests.Add(1)
+		go func(request message) {
+			defer requests.Done()
+			defer server.untrack(*request.ID)
+			result, err := server.dispatch(requestCtx, request)
+			server.reply(request.ID, result, err)
+		}(request)
+	}
+	return scanner.Err()
+}
+
+type server struct {
+	ctx    context.Context
+	plugin Plugin
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	mu         sync.Mutex
+	driver     database.Driver
+	inflight   map[uint64]context.CancelFunc
+	streams    map[string]database.RowStream
+	nextStream uint64
+}
+
+func (s *server) reply(id *uint64, result interface{}, err error) {
+	response := message{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		var remote *RemoteError
+		if !errors.As(err, &remote) {
+			code := codeDriverError
+			if errors.Is(err, context.Canceled) {
+				code = codeCancelled
+			}
+			remote = &RemoteError{Code: code, Message: err.Error()}
+		}
+		response.Error = remote
+	} else {
+		encoded, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			response.Error = &RemoteError{Code: codeDriverError, Message: marshalErr.Error()}
+		} else {
+			response.Result = encoded
+		}
+	}
+	encoded, _ := json.Marshal(response)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, _ = s.out.Write(append(encoded, '\n'))
+}
+
+func (s *server) track(id uint64, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight[id] = cancel
+}
+
+func (s *server) untrack(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inflight[id]; ok {
+		cancel()
+		delete(s.inflight, id)
+	}
+}
+
+func (s *server) cancel(params json.RawMessage) {
+	var cancel CancelParams
+	if json.Unmarshal(params, &cancel) != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancelRequest, ok := s.inflight[cancel.ID]; ok {
+		cancelRequest()
+	}
+}
+
+func (s *server) connected() (database.Driver, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.driver == nil {
+		return nil, fmt.Errorf("driver plugin is not connected")
+	}
+	return s.driver, nil
+}
+
+func (s *server) closeDriver() {
+	s.mu.Lock()
+	driver := s.driver
+	s.driver = nil
+	streams := s.streams
+	s.streams = make(map[string]database.RowStream)
+	s.mu.Unlock()
+	for _, stream := range streams {
+		closeStream(stream)
+	}
+	if driver != nil {
+		_ = driver.Close()
+	}
+}
+
+func closeStream(stream database.RowStream) {
+	if closer, ok := stream.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+func (s *server) dispatch(ctx context.Context, request message) (result interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Fprintf(os.Stderr, "panic in %s: %v\n%s", request.Method, recovered, debug.Stack())
+			result = nil
+			err = fmt.Errorf("driver plugin panicked in %s: %v", request.Method, recovered)
+		}
+	}()
+
+	if request.Method == MethodHandshake {
+		return s.handshake(ctx, request.Params)
+	}
+	if request.Method == MethodConnect {
+		var params ConnectParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		return struct{}{}, s.connect(ctx, params.Config)
+	}
+
+	driver, err := s.connected()
+	if err != nil {
+		return nil, err
+	}
+	switch request.Method {
+	case MethodPing:
+		health, ok := driver.(database.HealthDriver)
+		if !ok {
+			return nil, unsupported(request.Method)
+		}
+		return struct{}{}, health.Ping(ctx)
+	case MethodCountCollectionData:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			count, err := driver.CountCollectionData(table)
+			return ValueResult[int]{Value: count}, err
+		})
+	case MethodGetCollectionData:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			structures, rows, err := driver.GetCollectionData(table)
+			wireRows(rows)
+			return CollectionDataResult{Structures: structures, Rows: rows}, err
+		})
+	case MethodGetCollections:
+		var params CollectionsParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		collections, err := driver.GetCollections(params.Schemas...)
+		return ValueResult[[]string]{Value: collections}, err
+	case MethodGetCollectionStructures:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			structures, err := driver.GetCollectionStructures(table)
+			return ValueResult[database.Structures]{Value: structures}, err
+		})
+	case MethodGetIndices:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			indices, err := driver.GetIndices(table)
+			return ValueResult[database.Indices]{Value: indices}, err
+		})
+	case MethodGetDatabaseInfo:
+		info, err := driver.GetDatabaseInfo()
+		return ValueResult[database.Info]{Value: info}, err
+	case MethodGetSchemas:
+		schemaDriver, ok := driver.(database.DriverWithSchema)
+		if !ok {
+			return nil, unsupported(request.Method)
+		}
+		schemas, err := schemaDriver.GetSchemas()
+		return ValueResult[[]string]{Value: schemas}, err
+	case MethodInsertRow, MethodUpdateRow, MethodDeleteRow:
+		var params RowParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		params.Data = argValues(params.Data)
+		params.PrimaryValue = argValue(params.PrimaryValue)
+		switch request.Method {
+		case MethodInsertRow:
+			err = driver.InsertRow(params.Table, params.Data)
+		case MethodUpdateRow:
+			err = driver.UpdateRow(params.Table, params.Data, params.PrimaryKey)
+		default:
+			err = driver.DeleteRow(params.Table, params.PrimaryKey, params.PrimaryValue)
+		}
+		return struct{}{}, err
+	case MethodExecuteQuery:
+		var params QueryParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		queryResult, err := driver.ExecuteQuery(ctx, params.Query, database.QueryOptions{
+			MaxRows:      params.MaxRows,
+			Args:         argList(params.Args),
+			ServerOutput: params.ServerOutput,
+		})
+		wireQueryResult(&queryResult)
+		return ValueResult[database.QueryResult]{Value: queryResult}, err
+	case MethodCreateTable:
+		var params CreateTableParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		return struct{}{}, driver.CreateTable(params.Table, params.Columns)
+	case MethodDropTable:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			return struct{}{}, driver.DropTable(table)
+		})
+	case MethodTruncateTable:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			return struct{}{}, driver.TruncateTable(table)
+		})
+	case MethodGetTableDDL:
+		return withTable(request.Params, func(table database.Table) (interface{}, error) {
+			ddl, err := driver.GetTableDDL(table)
+			return ValueResult[string]{Value: ddl}, err
+		})
+	case MethodListObjects:
+		objectDriver, ok := driver.(database.ObjectDriver)
+		if !ok {
+			return nil, unsupported(request.Method)
+		}
+		var params ObjectListParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		objects, err := objectDriver.ListObjects(ctx, params.Filter)
+		return ValueResult[[]database.DatabaseObject]{Value: objects}, err
+	case MethodGetObjectDetail:
+		objectDriver, ok := driver.(database.ObjectDriver)
+		if !ok {
+			return nil, unsupported(request.Method)
+		}
+		var params ObjectDetailParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		detail, err := objectDriver.GetObjectDetail(ctx, params.Reference)
+		return ValueResult[database.ObjectDetail]{Value: detail}, err
+	case MethodExplainQuery:
+		explainDriver, ok := driver.(database.ExplainPlanDriver)
+		if !ok {
+			return nil, unsupported(request.Method)
+		}
+		var params ExplainParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		plan, err := explainDriver.ExplainQuery(ctx, params.Query)
+		return ValueResult[database.ExplainPlan]{Value: plan}, err
+	case MethodOpenRows:
+		return s.openRows(driver, request.Params)
+	case MethodNextRows:
+		return s.nextRows(ctx, request.Params)
+	case MethodCloseRows:
+		var params StreamParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		stream := s.streams[params.StreamID]
+		delete(s.streams, params.StreamID)
+		s.mu.Unlock()
+		if stream != nil {
+			closeStream(stream)
+		}
+		return struct{}{}, nil
+	default:
+		return nil, &RemoteError{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("method %s is not supported", request.Method),
+		}
+	}
+}
+
+func (s *server) handshake(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var handshake HandshakeParams
+	if err := decodeParams(params, &handshake); err != nil {
+		return nil, err
+	}
+	version, err := negotiateVersion(handshake.ProtocolVersions)
+	if err != nil {
+		return nil, &RemoteError{Code: codeInvalidRequest, Message: err.Error()}
+	}
+	prototype, err := s.plugin.Open(ctx, database.Config{Driver: s.plugin.Driver})
+	if err != nil {
+		return nil, fmt.Errorf("describe driver: %w", err)
+	}
+	_, schemas := prototype.(database.DriverWithSchema)
+	_, objects := prototype.(database.ObjectDriver)
+	_, explain := prototype.(database.ExplainPlanDriver)
+	_, rows := prototype.(RowStreamDriver)
+	_, ping := prototype.(database.HealthDriver)
+	return HandshakeResult{
+		ProtocolVersion: version,
+		Driver:          s.plugin.Driver,
+		Capabilities:    prototype.Capabilities(),
+		DataTypes:       prototype.GetDataTypes(),
+		Features: Features{
+			Schemas:      schemas,
+			Objects:      objects,
+			ExplainPlans: explain,
+			RowStreams:   rows,
+			Ping:         ping,
+		},
+	}, nil
+}
+
+func (s *server) connect(ctx context.Context, config database.Config) error {
+	s.mu.Lock()
+	connected := s.driver != nil
+	s.mu.Unlock()
+	if connected {
+		return fmt.Errorf("driver plugin is already connected")
+	}
+	driver, err := s.plugin.Open(s.ctx, config)
+	if err != nil {
+		return err
+	}
+	if err := driver.Connect(ctx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.driver = driver
+	return nil
+}
+
+func (s *server) openRows(driver database.Driver, params json.RawMessage) (interface{}, error) {
+	streamDriver, ok := driver.(RowStreamDriver)
+	if !ok {
+		return nil, unsupported(MethodOpenRows)
+	}
+	var open OpenRowsParams
+	if err := decodeParams(params, &open); err != nil {
+		return nil, err
+	}
+	// Streams outlive the rows.open request, so they use the server context.
+	stream, err := streamDriver.OpenTableRows(s.ctx, open.Request)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := stream.Columns()
+	if err != nil {
+		closeStream(stream)
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextStream++
+	id := strconv.FormatUint(s.nextStream, 10)
+	s.streams[id] = stream
+	return OpenRowsResult{StreamID: id, Columns: columns}, nil
+}
+
+func (s *server) nextRows(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var next NextRowsParams
+	if err := decodeParams(params, &next); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	stream := s.streams[next.StreamID]
+	s.mu.Unlock()
+	if stream == nil {
+		return nil, &RemoteError{Code: codeInvalidParams, Message: "unknown row stream " + next.StreamID}
+	}
+	limit := next.MaxRows
+	if limit <= 0 {
+		limit = rowsBatchSize
+	}
+	result := NextRowsResult{Rows: make([][]interface{}, 0, limit)}
+	for len(result.Rows) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !stream.Next() {
+			if err := stream.Err(); err != nil {
+				return nil, err
+			}
+			result.Done = true
+			break
+		}
+		values, err := stream.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make([]interface{}, len(values))
+		for index, value := range values {
+			row[index] = wireValue(value)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if result.Done {
+		s.mu.Lock()
+		delete(s.streams, next.StreamID)
+		s.mu.Unlock()
+		closeStream(stream)
+	}
+	return result, nil
+}
+
+func withTable(params json.RawMessage, call func(database.Table) (interface{}, error)) (interface{}, error) {
+	var table TableParams
+	if err := decodeParams(params, &table); err != nil {
+		return nil, err
+	}
+	return call(table.Table)
+}
+
+func decodeParams(params json.RawMessage, target interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		return &RemoteError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// argValue turns JSON numbers back into Go numbers before they reach a
+// database driver, which would otherwise bind them as text.
+func argValue(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if integer, err := number.Int64(); err == nil {
+		return integer
+	}
+	if float, err := number.Float64(); err == nil {
+		return float
+	}
+	return number.String()
+}
+
+func argList(values []interface{}) []interface{} {
+	for index, value := range values {
+		values[index] = argValue(value)
+	}
+	return values
+}
+
+func argValues(values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		values[key] = argValue(value)
+	}
+	return values
+}
+
+// wireValue encodes binary values the way exports do, so text stored as
+// bytes stays readable and real binary is marked instead of silently
+// becoming an unprefixed base64 string.
+func wireValue(value interface{}) interface{} {
+	bytesValue, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	if utf8.Valid(bytesValue) {
+		return string(bytesValue)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(bytesValue)
+}
+
+func wireRows(rows []map[string]interface{}) {
+	for _, row := range rows {
+		for key, value := range row {
+			row[key] = wireValue(value)
+		}
+	}
+}
+
+func wireValueRows(rows [][]interface{}) {
+	for _, row := range rows {
+		for index, value := range row {
+			row[index] = wireValue(value)
+		}
+	}
+}
+
+func wireQueryResult(result *database.QueryResult) {
+	wireValueRows(result.Rows)
+	for index := range result.ResultSets {
+		wireValueRows(result.ResultSets[index].Rows)
+	}
+}
@@ -48,39 +48,50 @@ func splitBlockedSessions(value string) []string {
 	return result
 }
 
+const postgresActivityQuery = `
+	SELECT
+		pid::bigint AS session_id,
+		COALESCE(usename, '') AS session_user,
+		COALESCE(datname, '') AS database_name,
+		COALESCE(client_addr::text, 'local') AS client_address,
+		COALESCE(application_name, '') AS application_name,
+		COALESCE(state, '') AS session_state,
+		LEFT(COALESCE(query, ''), 4000) AS query_text,
+		TRIM(BOTH ' ' FROM CONCAT_WS(':', wait_event_type, wait_event)) AS wait_event,
+		COALESCE(array_to_string(pg_blocking_pids(pid), ','), '') AS blocked_by,
+		GREATEST(
+			0,
+			(EXTRACT(EPOCH FROM (
+				clock_timestamp() - COALESCE(query_start, backend_start)
+			)) * 1000)::bigint
+		) AS duration_ms,
+		xact_start AS transaction_started,
+		query_start AS query_started,
+		backend_start AS started_at,
+		(pid = pg_backend_pid() OR application_name = $1) AS is_current
+	FROM pg_catalog.pg_stat_activity
+	WHERE backend_type = 'client backend'
+	ORDER BY
+		CASE WHEN state = 'active' THEN 0 ELSE 1 END,
+		query_start NULLS LAST,
+		pid`
+
 func (p *Postgres) GetDatabaseActivity(
 	ctx context.Context,
 ) (database.DatabaseActivity, error) {
 	if p.conn == nil {
 		return database.DatabaseActivity{}, fmt.Errorf("PostgreSQL connection is not open")
 	}
-	const query = `
-		SELECT
-			pid::bigint AS session_id,
-			COALESCE(usename, '') AS session_user,
-			COALESCE(datname, '') AS database_name,
-			COALESCE(client_addr::text, 'local') AS client_address,
-			COALESCE(application_name, '') AS application_name,
-			COALESCE(state, '') AS session_state,
-			LEFT(COALESCE(query, ''), 4000) AS query_text,
-			TRIM(BOTH ' ' FROM CONCAT_WS(':', wait_event_type, wait_event)) AS wait_event,
-			COALESCE(array_to_string(pg_blocking_pids(pid), ','), '') AS blocked_by,
-			GREATEST(
-				0,
-				(EXTRACT(EPOCH FROM (
-					clock_timestamp() - COALESCE(query_start, backend_start)
-				)) * 1000)::bigint
-			) AS duration_ms,
-			xact_start AS transaction_started,
-			query_start AS query_started,
-			backend_start AS started_at,
-			(pid = pg_backend_pid() OR application_name = $1) AS is_current
-		FROM pg_catalog.pg_stat_activity
-		WHERE backend_type = 'client backend'
-		ORDER BY
-			CASE WHEN state = 'active' THEN 0 ELSE 1 END,
-			query_start NULLS LAST,
-			pid`
+	if p.flavor == flavorCockroachDB {
+		return p.cockroachActivity(ctx)
+	}
+	query := postgresActivityQuery
+	if p.flavor == flavorYugabyteDB {
+		var err error
+		if query, err = p.yugabyteActivityQuery(ctx); err != nil {
+			return database.DatabaseActivity{}, err
+		}
+	}
 	var rows []postgresActivityRow
 	if err := p.conn.SelectContext(
 		ctx,
@@ -119,7 +130,7 @@ func (p *Postgres) GetDatabaseActivity(
 	}
 	return database.DatabaseActivity{
 		Supported:           true,
-		Engine:              "postgres",
+		Engine:              p.Capabilities().Engine,
 		CurrentSessionID:    currentID,
 		CanCancelQuery:      true,
 		CanTerminateSession: true,
@@ -133,9 +144,12 @@ func (p *Postgres) CancelDatabaseSession(
 	sessionID string,
 	terminate bool,
 ) error {
+	if p.flavor == flavorCockroachDB {
+		return p.cancelCockroachSession(ctx, sessionID, terminate)
+	}
 	id, err := strconv.ParseInt(strings.TrimSpace(sessionID), 10, 64)
 	if err != nil || id <= 0 {
-		return fmt.Errorf("invalid PostgreSQL session ID %q", sessionID)
+		return fmt.Errorf("invalid %s session ID %q", p.engine, sessionID)
 	}
 	var protected bool
 	if err := p.conn.GetContext(
@@ -147,7 +161,7 @@ func (p *Postgres) CancelDatabaseSession(
 		application.DatabaseClientName,
 	); err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("PostgreSQL session %d no longer exists", id)
+			return fmt.Errorf("%s session %d no longer exists", p.engine, id)
 		}
 		return err
 	}
@@ -171,7 +185,8 @@ func (p *Postgres) CancelDatabaseSession(
 	}
 	if !cancelled {
 		return fmt.Errorf(
-			"PostgreSQL refused to %s session %d",
+			"%s refused to %s session %d",
+			p.engine,
 			map[bool]string{true: "terminate", false: "cancel"}[terminate],
 			id,
 		)
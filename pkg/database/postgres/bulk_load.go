@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"rollingthunder/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// copyFromRows adapts import rows to pgx's CopyFromSource.
+type copyFromRows struct {
+	rows   database.BulkLoadRows
+	values []interface{}
+	err    error
+}
+
+func (source *copyFromRows) Next() bool {
+	source.values, source.err = source.rows.Next()
+	if errors.Is(source.err, io.EOF) {
+		source.err = nil
+		return false
+	}
+	return source.err == nil
+}
+
+func (source *copyFromRows) Values() ([]interface{}, error) {
+	return source.values, nil
+}
+
+func (source *copyFromRows) Err() error {
+	return source.err
+}
+
+// BulkLoad streams rows with COPY FROM STDIN on the transaction's own
+// connection, so a failed import still rolls back every row.
+func (transaction *postgresTransaction) BulkLoad(
+	ctx context.Context,
+	table database.Table,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	identifier := pgx.Identifier{table.Name}
+	if table.Schema != "" {
+		identifier = pgx.Identifier{table.Schema, table.Name}
+	}
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.Name
+	}
+	var copied int64
+	err := transaction.conn.Raw(func(driverConn interface{}) error {
+		conn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return database.ErrBulkLoadUnavailable
+		}
+		source := &copyFromRows{rows: rows}
+		var err error
+		copied, err = conn.Conn().CopyFrom(ctx, identifier, names, source)
+		if source.err != nil {
+			return source.err
+		}
+		if err != nil {
+			return fmt.Errorf("COPY %s: %w", identifier.Sanitize(), err)
+		}
+		return nil
+	})
+	return copied, err
+}
+
+var _ database.BulkLoader = (*postgresTransaction)(nil)
@@ -0,0 +1,373 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"rollingthunder/pkg/application"
+	"rollingthunder/pkg/database"
+)
+
+// CockroachDB rejects EXPLAIN (FORMAT JSON). Its default EXPLAIN returns one
+// row per line of a text tree, for example:
+//
+//	distribution: local
+//	vectorized: true
+//
+//	• filter
+//	│ estimated row count: 1
+//	│ filter: id > 1
+//	│
+//	└── • scan
+//	      estimated row count: 3 (100% of the table)
+//	      table: orders@orders_pkey
+//	      spans: FULL SCAN
+func (p *Postgres) explainCockroachQuery(
+	ctx context.Context,
+	query string,
+	args []interface{},
+) (database.ExplainPlan, error) {
+	rows, err := p.conn.QueryxContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return database.ExplainPlan{}, err
+	}
+	defer rows.Close()
+	lines := make([]string, 0)
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return database.ExplainPlan{}, err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return database.ExplainPlan{}, err
+	}
+	return parseCockroachPlan(lines)
+}
+
+type cockroachPlanNode struct {
+	column   int
+	node     database.ExplainPlanNode
+	children []*cockroachPlanNode
+}
+
+func parseCockroachPlan(lines []string) (database.ExplainPlan, error) {
+	var (
+		roots    []*cockroachPlanNode
+		stack    []*cockroachPlanNode
+		settings = make(map[string]string)
+	)
+	for _, line := range lines {
+		if bullet := strings.Index(line, "•"); bullet >= 0 {
+			node := &cockroachPlanNode{
+				column: utf8.RuneCountInString(line[:bullet]),
+				node: database.ExplainPlanNode{
+					NodeType: strings.TrimSpace(line[bullet+len("•"):]),
+					Details:  make(map[string]string),
+				},
+			}
+			for len(stack) > 0 && stack[len(stack)-1].column >= node.column {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				roots = append(roots, node)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+			continue
+		}
+		text := strings.TrimLeft(line, " │├└─")
+		key, value, found := strings.Cut(text, ":")
+		if !found || strings.TrimSpace(key) == "" {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(stack) == 0 {
+			settings[key] = value
+			continue
+		}
+		applyCockroachPlanAttribute(&stack[len(stack)-1].node, key, value)
+	}
+	if len(roots) == 0 {
+		return database.ExplainPlan{}, fmt.Errorf("CockroachDB returned an empty explain plan")
+	}
+	result := make([]database.ExplainPlanNode, 0, len(roots))
+	for index, root := range roots {
+		// Plan-wide settings such as distribution and vectorization are
+		// printed before the tree; they are kept on the root.
+		for key, value := range settings {
+			root.node.Details[key] = value
+		}
+		result = append(result, root.build("", fmt.Sprintf("crdb-%d", index)))
+	}
+	return database.ExplainPlan{
+		Engine:  "CockroachDB",
+		Summary: result[0].Summary,
+		Roots:   result,
+		Raw:     strings.Join(lines, "\n"),
+	}, nil
+}
+
+func (source *cockroachPlanNode) build(
+	parentID string,
+	id string,
+) database.ExplainPlanNode {
+	node := source.node
+	node.ID = id
+	node.ParentID = parentID
+	node.Summary = node.NodeType
+	if node.Relation != "" {
+		node.Summary += " on " + node.Relation
+	}
+	if len(node.Details) == 0 {
+		node.Details = nil
+	}
+	node.Children = make([]database.ExplainPlanNode, 0, len(source.children))
+	for index, child := range source.children {
+		node.Children = append(
+			node.Children,
+			child.build(id, fmt.Sprintf("%s-%d", id, index)),
+		)
+	}
+	return node
+}
+
+// applyCockroachPlanAttribute keeps every attribute as a detail and lifts
+// the table and row estimate into the shared plan fields. The table is
+// reported as table@index.
+func applyCockroachPlanAttribute(
+	node *database.ExplainPlanNode,
+	key string,
+	value string,
+) {
+	switch key {
+	case "table":
+		table, index, _ := strings.Cut(value, "@")
+		node.Relation = table
+		if index != "" {
+			node.Details["Index Name"] = index
+		}
+	case "estimated row count":
+		estimate := strings.ReplaceAll(strings.Fields(value + " ")[0], ",", "")
+		if rows, err := strconv.ParseFloat(estimate, 64); err == nil {
+			node.EstimatedRows = rows
+		}
+		node.Details[key] = value
+	default:
+		node.Details[key] = value
+	}
+}
+
+type cockroachActivityRow struct {
+	ID                 string       `db:"session_id"`
+	User               string       `db:"session_user"`
+	Database           string       `db:"database_name"`
+	Client             string       `db:"client_address"`
+	Application        string       `db:"application_name"`
+	State              string       `db:"session_state"`
+	Query              string       `db:"query_text"`
+	BlockedBy          string       `db:"blocked_by"`
+	DurationMS         int64        `db:"duration_ms"`
+	TransactionStarted sql.NullTime `db:"transaction_started"`
+	QueryStarted       sql.NullTime `db:"query_started"`
+	StartedAt          sql.NullTime `db:"started_at"`
+	IsCurrent          bool         `db:"is_current"`
+}
+
+// cockroachActivityQuery reads sessions and running queries across the
+// cluster. A session is blocked by another when its transaction is queued
+// for a lock the other session's transaction holds.
+const cockroachActivityQuery = `
+	WITH blockers AS (
+		SELECT
+			waiting.session_id,
+			string_agg(DISTINCT holding.session_id, ',') AS blocked_by
+		FROM crdb_internal.cluster_locks requested
+		JOIN crdb_internal.cluster_locks held
+			ON held.range_id = requested.range_id
+			AND held.lock_key = requested.lock_key
+			AND held.granted
+			AND held.txn_id <> requested.txn_id
+		JOIN crdb_internal.cluster_transactions waiting
+			ON waiting.id = requested.txn_id
+		JOIN crdb_internal.cluster_transactions holding
+			ON holding.id = held.txn_id
+		WHERE NOT requested.granted
+		GROUP BY waiting.session_id
+	)
+	SELECT
+		sessions.session_id AS session_id,
+		COALESCE(sessions.user_name, '') AS session_user,
+		COALESCE(queries.database, '') AS database_name,
+		COALESCE(sessions.client_address, '') AS client_address,
+		COALESCE(sessions.application_name, '') AS application_name,
+		CASE WHEN queries.query_id IS NULL THEN 'idle'
+			ELSE COALESCE(queries.phase, 'executing') END AS session_state,
+		LEFT(COALESCE(queries.query, sessions.last_active_query, ''), 4000) AS query_text,
+		COALESCE(blockers.blocked_by, '') AS blocked_by,
+		GREATEST(
+			0,
+			(EXTRACT(EPOCH FROM (
+				now() - COALESCE(queries.start, sessions.session_start)
+			)) * 1000)::INT8
+		) AS duration_ms,
+		transactions.start AS transaction_started,
+		queries.start AS query_started,
+		sessions.session_start AS started_at,
+		(sessions.session_id = $1 OR sessions.application_name = $2) AS is_current
+	FROM crdb_internal.cluster_sessions sessions
+	LEFT JOIN crdb_internal.cluster_queries queries
+		ON queries.session_id = sessions.session_id
+	LEFT JOIN crdb_internal.cluster_transactions transactions
+		ON transactions.session_id = sessions.session_id
+	LEFT JOIN blockers ON blockers.session_id = sessions.session_id
+	WHERE sessions.status <> 'CLOSED'
+	ORDER BY
+		CASE WHEN queries.query_id IS NULL THEN 1 ELSE 0 END,
+		queries.start NULLS LAST,
+		sessions.session_id`
+
+func (p *Postgres) cockroachActivity(
+	ctx context.Context,
+) (database.DatabaseActivity, error) {
+	var currentID string
+	if err := p.conn.GetContext(ctx, &currentID, "SHOW session_id"); err != nil {
+		return database.DatabaseActivity{}, err
+	}
+	var rows []cockroachActivityRow
+	if err := p.conn.SelectContext(
+		ctx,
+		&rows,
+		cockroachActivityQuery,
+		currentID,
+		application.DatabaseClientName,
+	); err != nil {
+		return database.DatabaseActivity{}, err
+	}
+	sessions := make([]database.DatabaseSession, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		// A session can appear once per running query; keep the first.
+		if seen[row.ID] {
+			continue
+		}
+		seen[row.ID] = true
+		blockedBy := splitBlockedSessions(row.BlockedBy)
+		sessions = append(sessions, database.DatabaseSession{
+			ID:                 row.ID,
+			User:               row.User,
+			Database:           row.Database,
+			Client:             row.Client,
+			Application:        row.Application,
+			Command:            row.State,
+			State:              row.State,
+			Query:              row.Query,
+			Waiting:            len(blockedBy) > 0,
+			BlockedBy:          blockedBy,
+			DurationMS:         row.DurationMS,
+			TransactionStarted: nullableActivityTime(row.TransactionStarted),
+			QueryStarted:       nullableActivityTime(row.QueryStarted),
+			StartedAt:          nullableActivityTime(row.StartedAt),
+			IsCurrent:          row.IsCurrent,
+		})
+	}
+	return database.DatabaseActivity{
+		Supported:           true,
+		Engine:              database.DriverCockroachDB,
+		CurrentSessionID:    currentID,
+		CanCancelQuery:      true,
+		CanTerminateSession: true,
+		Sessions:            sessions,
+		CapturedAt:          time.Now(),
+	}, nil
+}
+
+// validCockroachSessionID accepts the 128-bit hexadecimal IDs that
+// crdb_internal reports for sessions and queries.
+func validCockroachSessionID(value string) bool {
+	decoded, err := hex.DecodeString(value)
+	return err == nil && len(decoded) == 16
+}
+
+func (p *Postgres) cancelCockroachSession(
+	ctx context.Context,
+	sessionID string,
+	terminate bool,
+) error {
+	id := strings.ToLower(strings.TrimSpace(sessionID))
+	if !validCockroachSessionID(id) {
+		return fmt.Errorf("invalid CockroachDB session ID %q", sessionID)
+	}
+	var currentID string
+	if err := p.conn.GetContext(ctx, &currentID, "SHOW session_id"); err != nil {
+		return err
+	}
+	var target struct {
+		Protected bool   `db:"protected"`
+		QueryID   string `db:"query_id"`
+	}
+	if err := p.conn.GetContext(
+		ctx,
+		&target,
+		`SELECT
+			(sessions.session_id = $2 OR sessions.application_name = $3) AS protected,
+			COALESCE(queries.query_id, '') AS query_id
+		 FROM crdb_internal.cluster_sessions sessions
+		 LEFT JOIN crdb_internal.cluster_queries queries
+			ON queries.session_id = sessions.session_id
+		 WHERE sessions.session_id = $1 AND sessions.status <> 'CLOSED'
+		 LIMIT 1`,
+		id,
+		currentID,
+		application.DatabaseClientName,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("CockroachDB session %s no longer exists", id)
+		}
+		return err
+	}
+	if target.Protected {
+		return fmt.Errorf(
+			"Rolling Thunder protects its own database sessions; cancel the query from its query tab",
+		)
+	}
+	if terminate {
+		_, err := p.conn.ExecContext(ctx, "CANCEL SESSION $1", id)
+		return err
+	}
+	if target.QueryID == "" {
+		return fmt.Errorf("CockroachDB session %s has no running query to cancel", id)
+	}
+	_, err := p.conn.ExecContext(ctx, "CANCEL QUERY $1", target.QueryID)
+	return err
+}
+
+// cockroachTableDDL returns SHOW CREATE TABLE, which keeps computed
+// columns, hash-sharded and partial indexes, and zone configurations that
+// the column-based reconstruction cannot express.
+func (p *Postgres) cockroachTableDDL(table database.Table, schema string) (string, error) {
+	var result struct {
+		Name      string `db:"table_name"`
+		Statement string `db:"create_statement"`
+	}
+	if err := p.conn.Get(
+		&result,
+		"SHOW CREATE TABLE "+quotePostgresQualifiedIdentifier(
+			schema,
+			strings.TrimSpace(table.Name),
+		),
+	); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSpace(result.Statement), ";") + ";", nil
+}
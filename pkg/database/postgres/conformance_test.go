@@ -13,6 +13,16 @@ func TestPostgresSharedCapabilityContract(t *testing.T) {
 	drivertest.RunCapabilityContract(t, driver, "postgres")
 }
 
+func TestCockroachDBSharedCapabilityContract(t *testing.T) {
+	driver := NewPostgres(context.Background(), Config{Flavor: "cockroachdb"})
+	drivertest.RunCapabilityContract(t, driver, "cockroachdb")
+}
+
+func TestYugabyteDBSharedCapabilityContract(t *testing.T) {
+	driver := NewPostgres(context.Background(), Config{Flavor: "yugabytedb"})
+	drivertest.RunCapabilityContract(t, driver, "yugabytedb")
+}
+
 func TestPostgresLiveConformance(t *testing.T) {
 	host := os.Getenv("ROLLINGTHUNDER_POSTGRES_TEST_HOST")
 	if host == "" {
@@ -46,6 +56,58 @@ func TestPostgresLiveConformance(t *testing.T) {
 		Schema:             "public",
 		IntegerType:        "integer",
 		TextType:           "text",
+		JSONType:           "jsonb",
+		ExercisePrivileged: os.Getenv("ROLLINGTHUNDER_TEST_PRIVILEGED") == "1",
+	})
+}
+
+func TestCockroachDBLiveConformance(t *testing.T) {
+	runFlavorLiveConformance(t, "COCKROACHDB", flavorCockroachDB)
+}
+
+func TestYugabyteDBLiveConformance(t *testing.T) {
+	runFlavorLiveConformance(t, "YUGABYTEDB", flavorYugabyteDB)
+}
+
+// runFlavorLiveConformance connects through the flavor's registered entry
+// point and checks that version() detection lands on the same flavor.
+func runFlavorLiveConformance(t *testing.T, envName string, want flavor) {
+	t.Helper()
+	prefix := "ROLLINGTHUNDER_" + envName + "_TEST_"
+	host := os.Getenv(prefix + "HOST")
+	if host == "" {
+		t.Skipf(
+			"set %sHOST to run live %s conformance",
+			prefix,
+			want.displayName(),
+		)
+	}
+	driver := NewPostgres(context.Background(), Config{
+		Host:     host,
+		Port:     os.Getenv(prefix + "PORT"),
+		User:     os.Getenv(prefix + "USER"),
+		Password: os.Getenv(prefix + "PASSWORD"),
+		Db:       os.Getenv(prefix + "DATABASE"),
+		SSLMode:  os.Getenv(prefix + "SSL_MODE"),
+		Flavor:   string(want),
+	})
+	if err := driver.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := driver.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+	if got := driver.Capabilities().Engine; got != string(want) {
+		t.Fatalf("detected engine = %q, want %q", got, want)
+	}
+	drivertest.RunLiveContract(t, drivertest.LiveConfig{
+		Driver:             driver,
+		Schema:             "public",
+		IntegerType:        "integer",
+		TextType:           "text",
+		JSONType:           "jsonb",
 		ExercisePrivileged: os.Getenv("ROLLINGTHUNDER_TEST_PRIVILEGED") == "1",
 	})
 }
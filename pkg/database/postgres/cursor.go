@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const postgresCursorName = "rollingthunder_cursor"
+
+// postgresCursor pages through a DECLARE CURSOR in a read-only transaction
+// on a connection taken out of the pool. The server keeps the position, so
+// only the rows of the current page ever cross the wire.
+type postgresCursor struct {
+	conn    *sqlx.Conn
+	tx      *sqlx.Tx
+	columns []database.QueryColumn
+	done    bool
+}
+
+func (p *Postgres) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	conn, err := p.conn.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	cursor := &postgresCursor{conn: conn, tx: tx}
+	if _, err := tx.ExecContext(
+		ctx,
+		"DECLARE "+postgresCursorName+" NO SCROLL CURSOR FOR "+query,
+		options.Args...,
+	); err != nil {
+		_ = cursor.Close()
+		return nil, err
+	}
+	// FETCH 0 returns no rows but still describes them.
+	rows, err := tx.QueryxContext(ctx, "FETCH FORWARD 0 FROM "+postgresCursorName)
+	if err != nil {
+		_ = cursor.Close()
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	_ = rows.Close()
+	if err != nil {
+		_ = cursor.Close()
+		return nil, fmt.Errorf("read query result columns: %w", err)
+	}
+	cursor.columns = sqladapter.QueryColumns(columnTypes)
+	return cursor, nil
+}
+
+func (cursor *postgresCursor) Columns() []database.QueryColumn {
+	return cursor.columns
+}
+
+func (cursor *postgresCursor) Fetch(
+	ctx context.Context,
+	maxRows int,
+) (database.QueryCursorBatch, error) {
+	if maxRows <= 0 {
+		return database.QueryCursorBatch{}, fmt.Errorf("cursor fetch requires a positive row count")
+	}
+	if cursor.done {
+		return database.QueryCursorBatch{Rows: make([][]interface{}, 0), Done: true}, nil
+	}
+	rows, err := cursor.tx.QueryxContext(
+		ctx,
+		fmt.Sprintf("FETCH FORWARD %d FROM %s", maxRows, postgresCursorName),
+	)
+	if err != nil {
+		return database.QueryCursorBatch{}, err
+	}
+	defer rows.Close()
+	result, err := collectQueryResults(rows, cursor.columns, 0)
+	if err != nil {
+		return database.QueryCursorBatch{}, err
+	}
+	cursor.done = len(result.Rows) < maxRows
+	return database.QueryCursorBatch{Rows: result.Rows, Done: cursor.done}, nil
+}
+
+// Close ends the transaction, which closes the cursor with it. The
+// transaction is already gone when the context it was opened with ended.
+func (cursor *postgresCursor) Close() error {
+	err := cursor.tx.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		err = nil
+	}
+	return errors.Join(err, cursor.conn.Close())
+}
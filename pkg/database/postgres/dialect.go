@@ -15,43 +15,13 @@ var postgresDialect = database.Dialect{
 	SupportsNullOrdering: true,
 }
 
+// Capabilities reflects the detected flavor once connected. The nil
+// receiver used by the driver probe reports the plain PostgreSQL set.
 func (p *Postgres) Capabilities() database.Capabilities {
-	return database.Capabilities{
-		Engine:              "postgres",
-		DisplayName:         "PostgreSQL",
-		Dialect:             postgresDialect,
-		Schemas:             true,
-		Databases:           false,
-		Tables:              true,
-		Views:               true,
-		MaterializedViews:   true,
-		Functions:           true,
-		Procedures:          true,
-		Triggers:            true,
-		Sequences:           true,
-		CustomTypes:         true,
-		Domains:             true,
-		Constraints:         true,
-		Extensions:          true,
-		ObjectDefinitions:   true,
-		ObjectDependencies:  true,
-		ManageViews:         true,
-		ManageRoutines:      true,
-		ManageTriggers:      true,
-		TriggerToggle:       true,
-		ManageIndexes:       true,
-		AlterTableStructure: true,
-		ExplainPlans:        true,
-		Transactions:        true,
-		TransactionalDDL:    true,
-		AtomicTableChanges:  true,
-		SQLInsertExport:     true,
-		GeneratedColumns:    true,
-		Upsert:              true,
-		ManageSecurity:      true,
-		ActivityMonitor:     true,
-		SSHConnections:      true,
+	if p == nil {
+		return flavorPostgres.capabilities()
 	}
+	return p.flavor.capabilities()
 }
 
 func (p *Postgres) QuoteIdentifier(identifier string) string {
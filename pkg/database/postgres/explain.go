@@ -21,6 +21,9 @@ func (p *Postgres) ExplainQueryWithArgs(
 	query string,
 	args []interface{},
 ) (database.ExplainPlan, error) {
+	if p.flavor == flavorCockroachDB {
+		return p.explainCockroachQuery(ctx, query, args)
+	}
 	var raw string
 	if err := p.conn.GetContext(
 		ctx,
@@ -30,22 +33,47 @@ func (p *Postgres) ExplainQueryWithArgs(
 	); err != nil {
 		return database.ExplainPlan{}, err
 	}
+	if p.flavor == flavorYugabyteDB {
+		return parseYugabytePlan(raw)
+	}
+	return parseJSONPlan(raw, "PostgreSQL", "pg-0", postgresPlanDetails)
+}
 
+// postgresPlanDetails are the node keys shown besides costs and row counts.
+var postgresPlanDetails = []string{
+	"Join Type",
+	"Index Name",
+	"Index Cond",
+	"Filter",
+	"Hash Cond",
+	"Merge Cond",
+	"Sort Key",
+	"Group Key",
+	"Strategy",
+	"Parallel Aware",
+}
+
+func parseJSONPlan(
+	raw string,
+	engine string,
+	rootID string,
+	detailKeys []string,
+) (database.ExplainPlan, error) {
 	var documents []map[string]interface{}
 	if err := json.Unmarshal([]byte(raw), &documents); err != nil {
-		return database.ExplainPlan{}, fmt.Errorf("decode PostgreSQL explain plan: %w", err)
+		return database.ExplainPlan{}, fmt.Errorf("decode %s explain plan: %w", engine, err)
 	}
 	if len(documents) == 0 {
-		return database.ExplainPlan{}, fmt.Errorf("PostgreSQL returned an empty explain plan")
+		return database.ExplainPlan{}, fmt.Errorf("%s returned an empty explain plan", engine)
 	}
 	rootMap, ok := documents[0]["Plan"].(map[string]interface{})
 	if !ok {
-		return database.ExplainPlan{}, fmt.Errorf("PostgreSQL explain plan has no root node")
+		return database.ExplainPlan{}, fmt.Errorf("%s explain plan has no root node", engine)
 	}
-	root := postgresPlanNode(rootMap, "", "pg-0")
+	root := postgresPlanNode(rootMap, "", rootID, detailKeys)
 	pretty, _ := json.MarshalIndent(documents, "", "  ")
 	return database.ExplainPlan{
-		Engine:  p.engine,
+		Engine:  engine,
 		Summary: root.Summary,
 		Roots:   []database.ExplainPlanNode{root},
 		Raw:     string(pretty),
@@ -56,6 +84,7 @@ func postgresPlanNode(
 	source map[string]interface{},
 	parentID string,
 	id string,
+	detailKeys []string,
 ) database.ExplainPlanNode {
 	nodeType := stringValue(source["Node Type"])
 	relation := stringValue(source["Relation Name"])
@@ -79,18 +108,7 @@ func postgresPlanNode(
 		Details:       make(map[string]string),
 		Children:      make([]database.ExplainPlanNode, 0),
 	}
-	for _, key := range []string{
-		"Join Type",
-		"Index Name",
-		"Index Cond",
-		"Filter",
-		"Hash Cond",
-		"Merge Cond",
-		"Sort Key",
-		"Group Key",
-		"Strategy",
-		"Parallel Aware",
-	} {
+	for _, key := range detailKeys {
 		if value, exists := source[key]; exists {
 			node.Details[key] = formatPlanValue(value)
 		}
@@ -107,7 +125,7 @@ func postgresPlanNode(
 			childID := fmt.Sprintf("%s-%d", id, index)
 			node.Children = append(
 				node.Children,
-				postgresPlanNode(child, id, childID),
+				postgresPlanNode(child, id, childID, detailKeys),
 			)
 		}
 	}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -13,96 +14,134 @@ type postgresQuery struct {
 }
 
 func buildPostgresFilterClause(
-	filters []database.Filter,
+	tree database.FilterGroup,
 	structures database.Structures,
 	startPlaceholder int,
 ) (string, []interface{}, error) {
-	if len(filters) == 0 {
-		return "", nil, nil
-	}
 	if startPlaceholder < 1 {
 		startPlaceholder = 1
 	}
 
-	availableColumns := make(map[string]struct{}, len(structures))
+	availableColumns := make(map[string]database.Structure, len(structures))
 	for _, structure := range structures {
-		availableColumns[structure.Name] = struct{}{}
+		availableColumns[structure.Name] = structure
 	}
 
-	conditions := make([]string, 0, len(filters))
-	args := make([]interface{}, 0, len(filters))
-	placeholder := startPlaceholder
-
-	for _, filter := range filters {
-		if err := filter.Validate(); err != nil {
-			return "", nil, err
-		}
+	args := make([]interface{}, 0)
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", startPlaceholder+len(args)-1)
+	}
 
+	clause, err := tree.Render(func(filter database.Filter) (string, error) {
 		column := strings.TrimSpace(filter.Column)
-		if _, exists := availableColumns[column]; !exists {
-			return "", nil, fmt.Errorf(
+		structure, exists := availableColumns[column]
+		if !exists {
+			return "", fmt.Errorf(
 				"cannot filter by unknown column %q",
 				column,
 			)
 		}
 		quotedColumn := quotePostgresIdentifier(column)
+		if filter.Path != "" {
+			path, err := database.ParseJSONPath(filter.Path)
+			if err != nil {
+				return "", err
+			}
+			if containment, ok := postgresJSONContainment(structure, path, filter); ok {
+				return quotedColumn + " @> " + bind(containment) + "::jsonb", nil
+			}
+			quotedColumn = postgresJSONPathValue(
+				quotedColumn,
+				bind(path.Elements()),
+				filter.NumericJSONFilter(),
+			)
+		}
 
 		switch filter.Operator {
 		case database.FilterEqual:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s = $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
+			return quotedColumn + " = " + bind(filter.Value), nil
 		case database.FilterNotEqual:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s <> $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
+			return quotedColumn + " <> " + bind(filter.Value), nil
 		case database.FilterGreaterThan:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s > $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
+			return quotedColumn + " > " + bind(filter.Value), nil
 		case database.FilterLessThan:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s < $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
+			return quotedColumn + " < " + bind(filter.Value), nil
 		case database.FilterGreaterEqual:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s >= $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
+			return quotedColumn + " >= " + bind(filter.Value), nil
 		case database.FilterLessEqual:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s <= $%d", quotedColumn, placeholder),
-			)
-			args = append(args, filter.Value)
-			placeholder++
-		case database.FilterContains:
-			conditions = append(
-				conditions,
-				fmt.Sprintf("%s::text ILIKE $%d", quotedColumn, placeholder),
-			)
-			args = append(args, "%"+fmt.Sprint(filter.Value)+"%")
-			placeholder++
+			return quotedColumn + " <= " + bind(filter.Value), nil
+		case database.FilterContains,
+			database.FilterStartsWith,
+			database.FilterEndsWith,
+			database.FilterILike:
+			return quotedColumn + "::text ILIKE " + bind(filter.LikePattern()), nil
+		case database.FilterNotContains:
+			return quotedColumn + "::text NOT ILIKE " + bind(filter.LikePattern()), nil
+		case database.FilterRegex:
+			return quotedColumn + "::text ~ " + bind(fmt.Sprint(filter.Value)), nil
+		case database.FilterIn, database.FilterNotIn:
+			values, _ := filter.Values()
+			placeholders := make([]string, len(values))
+			for index, value := range values {
+				placeholders[index] = bind(value)
+			}
+			operator := " IN ("
+			if filter.Operator == database.FilterNotIn {
+				operator = " NOT IN ("
+			}
+			return quotedColumn + operator + strings.Join(placeholders, ", ") + ")", nil
+		case database.FilterBetween:
+			values, _ := filter.Values()
+			return quotedColumn + " BETWEEN " + bind(values[0]) +
+				" AND " + bind(values[1]), nil
 		case database.FilterIsNull:
-			conditions = append(conditions, quotedColumn+" IS NULL")
+			return quotedColumn + " IS NULL", nil
 		case database.FilterIsNotNull:
-			conditions = append(conditions, quotedColumn+" IS NOT NULL")
+			return quotedColumn + " IS NOT NULL", nil
 		}
+		return "", fmt.Errorf("unsupported filter operator %q", filter.Operator)
+	})
+	if err != nil || clause == "" {
+		return "", nil, err
+	}
+	return " WHERE " + clause, args, nil
+}
+
+// postgresJSONPathValue reads the value at a bound text[] path. Numeric
+// comparisons only see JSON numbers, so a stray string in the column does
+// not fail the cast for the whole query.
+func postgresJSONPathValue(column, path string, numeric bool) string {
+	if numeric {
+		return fmt.Sprintf(
+			"(CASE WHEN jsonb_typeof(%[1]s::jsonb #> %[2]s::text[]) = 'number' "+
+				"THEN (%[1]s::jsonb #>> %[2]s::text[])::numeric END)",
+			column,
+			path,
+		)
 	}
+	return fmt.Sprintf("(%s #>> %s::text[])", column, path)
+}
 
-	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+// postgresJSONContainment turns equality on a jsonb key path into the
+// document for @>, which a GIN index on the column can answer.
+func postgresJSONContainment(
+	structure database.Structure,
+	path database.JSONPath,
+	filter database.Filter,
+) (string, bool) {
+	if filter.Operator != database.FilterEqual ||
+		!strings.EqualFold(structure.DataType, "jsonb") ||
+		path.HasIndexes() {
+		return "", false
+	}
+	var document interface{} = filter.Value
+	for index := len(path) - 1; index >= 0; index-- {
+		document = map[string]interface{}{path[index].Key: document}
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
 }
@@ -9,7 +9,7 @@ import (
 
 func TestBuildPostgresFilterClauseUsesTypedParameters(t *testing.T) {
 	clause, args, err := buildPostgresFilterClause(
-		[]database.Filter{
+		database.FilterGroup{Filters: []database.Filter{
 			{
 				Column:   "status",
 				Operator: database.FilterEqual,
@@ -24,7 +24,7 @@ func TestBuildPostgresFilterClauseUsesTypedParameters(t *testing.T) {
 				Column:   "deleted_at",
 				Operator: database.FilterIsNull,
 			},
-		},
+		}},
 		database.Structures{
 			{Name: "status"},
 			{Name: "customer name"},
@@ -48,13 +48,13 @@ func TestBuildPostgresFilterClauseUsesTypedParameters(t *testing.T) {
 
 func TestBuildPostgresFilterClauseRejectsUnknownColumns(t *testing.T) {
 	_, _, err := buildPostgresFilterClause(
-		[]database.Filter{
+		database.FilterGroup{Filters: []database.Filter{
 			{
 				Column:   "missing",
 				Operator: database.FilterEqual,
 				Value:    "value",
 			},
-		},
+		}},
 		database.Structures{{Name: "id"}},
 		1,
 	)
@@ -65,13 +65,13 @@ func TestBuildPostgresFilterClauseRejectsUnknownColumns(t *testing.T) {
 
 func TestBuildPostgresFilterClauseRejectsInvalidOperators(t *testing.T) {
 	_, _, err := buildPostgresFilterClause(
-		[]database.Filter{
+		database.FilterGroup{Filters: []database.Filter{
 			{
 				Column:   "id",
 				Operator: database.FilterOperator("raw_sql"),
 				Value:    "1 OR true",
 			},
-		},
+		}},
 		database.Structures{{Name: "id"}},
 		1,
 	)
@@ -79,3 +79,70 @@ func TestBuildPostgresFilterClauseRejectsInvalidOperators(t *testing.T) {
 		t.Fatal("expected invalid-operator error")
 	}
 }
+
+func TestBuildPostgresFilterClauseNumbersPlaceholdersThroughGroups(t *testing.T) {
+	clause, args, err := buildPostgresFilterClause(
+		database.FilterGroup{
+			Logic: database.FilterOr,
+			Filters: []database.Filter{
+				{Column: "id", Operator: database.FilterIn, Value: []interface{}{1, 2}},
+			},
+			Groups: []database.FilterGroup{{
+				Filters: []database.Filter{
+					{Column: "email", Operator: database.FilterRegex, Value: `@example\.com$`},
+					{Column: "email", Operator: database.FilterNotContains, Value: "test"},
+				},
+			}},
+		},
+		database.Structures{{Name: "id"}, {Name: "email"}},
+		2,
+	)
+	if err != nil {
+		t.Fatalf("build filter clause: %v", err)
+	}
+
+	const expected = ` WHERE "id" IN ($2, $3) OR ("email"::text ~ $4 AND "email"::text NOT ILIKE $5)`
+	if clause != expected {
+		t.Fatalf("clause = %q, want %q", clause, expected)
+	}
+	wantArgs := []interface{}{1, 2, `@example\.com$`, "%test%"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", args, wantArgs)
+	}
+}
+
+func TestBuildPostgresFilterClauseBindsJSONPaths(t *testing.T) {
+	clause, args, err := buildPostgresFilterClause(
+		database.FilterGroup{Filters: []database.Filter{
+			{Column: "doc", Path: "customer.id", Operator: database.FilterEqual, Value: "c-1"},
+			{Column: "doc", Path: "items[0].qty", Operator: database.FilterGreaterThan, Value: 2},
+			{Column: "payload", Path: "kind", Operator: database.FilterContains, Value: "ord"},
+		}},
+		database.Structures{
+			{Name: "doc", DataType: "jsonb"},
+			{Name: "payload", DataType: "json"},
+		},
+		1,
+	)
+	if err != nil {
+		t.Fatalf("build filter clause: %v", err)
+	}
+
+	const expected = ` WHERE "doc" @> $1::jsonb AND ` +
+		`(CASE WHEN jsonb_typeof("doc"::jsonb #> $2::text[]) = 'number' ` +
+		`THEN ("doc"::jsonb #>> $2::text[])::numeric END) > $3 AND ` +
+		`("payload" #>> $4::text[])::text ILIKE $5`
+	if clause != expected {
+		t.Fatalf("clause = %q, want %q", clause, expected)
+	}
+	wantArgs := []interface{}{
+		`{"customer":{"id":"c-1"}}`,
+		[]string{"items", "0", "qty"},
+		2,
+		[]string{"kind"},
+		"%ord%",
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", args, wantArgs)
+	}
+}
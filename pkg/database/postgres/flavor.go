@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// flavor identifies the server behind the PostgreSQL wire protocol.
+// CockroachDB and YugabyteDB accept the same connections and most of the
+// SQL, but emulate or omit parts of the catalog, tooling, and explain output
+// the PostgreSQL workflows rely on.
+type flavor string
+
+const (
+	flavorPostgres    flavor = database.DriverPostgres
+	flavorCockroachDB flavor = database.DriverCockroachDB
+	flavorYugabyteDB  flavor = database.DriverYugabyteDB
+)
+
+func parseFlavor(value string) flavor {
+	switch flavor(strings.ToLower(strings.TrimSpace(value))) {
+	case flavorCockroachDB:
+		return flavorCockroachDB
+	case flavorYugabyteDB:
+		return flavorYugabyteDB
+	default:
+		return flavorPostgres
+	}
+}
+
+func (f flavor) displayName() string {
+	switch f {
+	case flavorCockroachDB:
+		return "CockroachDB"
+	case flavorYugabyteDB:
+		return "YugabyteDB"
+	default:
+		return "PostgreSQL"
+	}
+}
+
+func (f flavor) defaultPort() string {
+	switch f {
+	case flavorCockroachDB:
+		return database.DefaultCockroachDBPort
+	case flavorYugabyteDB:
+		return database.DefaultYugabyteDBPort
+	default:
+		return database.DefaultPostgresPort
+	}
+}
+
+// detectFlavor reads version(). CockroachDB reports its own release, as in
+// "CockroachDB CCL v24.1.0 (...)", while YugabyteDB appends its release to
+// the PostgreSQL version, as in "PostgreSQL 15.2-YB-2.25.0.0-b0 on ...".
+// The second result is the release to show in place of server_version,
+// which both engines set to the PostgreSQL version they emulate.
+func detectFlavor(version string) (flavor, string) {
+	version = strings.TrimSpace(version)
+	fields := strings.Fields(version)
+	switch {
+	case strings.HasPrefix(version, "CockroachDB"):
+		for _, field := range fields {
+			if len(field) > 1 && field[0] == 'v' && field[1] >= '0' && field[1] <= '9' {
+				return flavorCockroachDB, field
+			}
+		}
+		return flavorCockroachDB, ""
+	case strings.Contains(version, "-YB-"):
+		for _, field := range fields {
+			postgresVersion, release, found := strings.Cut(field, "-YB-")
+			if found {
+				return flavorYugabyteDB, release + " (PostgreSQL " + postgresVersion + ")"
+			}
+		}
+		return flavorYugabyteDB, ""
+	default:
+		return flavorPostgres, ""
+	}
+}
+
+// hiddenSchemas are system schemas that information_schema reports but
+// that hold no user objects.
+func (f flavor) hiddenSchemas() []string {
+	schemas := []string{"pg_catalog", "information_schema"}
+	if f == flavorCockroachDB {
+		schemas = append(schemas, "crdb_internal", "pg_extension")
+	}
+	return schemas
+}
+
+func postgresCapabilities() database.Capabilities {
+	return database.Capabilities{
+		Engine:              database.DriverPostgres,
+		DisplayName:         "PostgreSQL",
+		Dialect:             postgresDialect,
+		Schemas:             true,
+		Databases:           false,
+		Tables:              true,
+		Views:               true,
+		MaterializedViews:   true,
+		Functions:           true,
+		Procedures:          true,
+		Triggers:            true,
+		Sequences:           true,
+		CustomTypes:         true,
+		Domains:             true,
+		Constraints:         true,
+		Extensions:          true,
+		ObjectDefinitions:   true,
+		ObjectDependencies:  true,
+		ManageViews:         true,
+		ManageRoutines:      true,
+		ManageTriggers:      true,
+		TriggerToggle:       true,
+		ManageIndexes:       true,
+		AlterTableStructure: true,
+		ExplainPlans:        true,
+		Transactions:        true,
+		TransactionalDDL:    true,
+		AtomicTableChanges:  true,
+		SQLInsertExport:     true,
+		GeneratedColumns:    true,
+		Upsert:              true,
+		ManageSecurity:      true,
+		ActivityMonitor:     true,
+		SSHConnections:      true,
+		QueryCursors:        true,
+		FilterGroups:        true,
+		RegexFilters:        true,
+		JSONPaths:           true,
+		KeysetPagination:    true,
+		RowEstimates:        true,
+		ReturningRows:       true,
+	}
+}
+
+// cockroachCapabilities hides the workflows that depend on catalogs
+// CockroachDB only emulates. pg_proc, pg_trigger, and pg_depend do not
+// describe its routines, triggers, and dependencies completely, it has no
+// domains or extensions, and its privilege model has no PostgreSQL role
+// attributes. Schema changes inside a transaction are applied only after
+// commit, so DDL is not treated as transactional; row changes are. Row
+// estimates read pg_class.reltuples, which is emulated as well.
+func cockroachCapabilities() database.Capabilities {
+	capabilities := postgresCapabilities()
+	capabilities.Engine = database.DriverCockroachDB
+	capabilities.DisplayName = "CockroachDB"
+	capabilities.Functions = false
+	capabilities.Procedures = false
+	capabilities.Triggers = false
+	capabilities.Domains = false
+	capabilities.Extensions = false
+	capabilities.ObjectDependencies = false
+	capabilities.ManageRoutines = false
+	capabilities.ManageTriggers = false
+	capabilities.TriggerToggle = false
+	capabilities.TransactionalDDL = false
+	capabilities.ManageSecurity = false
+	capabilities.RowEstimates = false
+	return capabilities
+}
+
+// yugabyteCapabilities keeps the PostgreSQL catalog workflows, which YSQL
+// reuses from its PostgreSQL fork. DDL runs outside the distributed
+// transaction unless the cluster enables transactional DDL, so a failed
+// migration can leave earlier statements applied.
+func yugabyteCapabilities() database.Capabilities {
+	capabilities := postgresCapabilities()
+	capabilities.Engine = database.DriverYugabyteDB
+	capabilities.DisplayName = "YugabyteDB"
+	capabilities.TransactionalDDL = false
+	return capabilities
+}
+
+func (f flavor) capabilities() database.Capabilities {
+	switch f {
+	case flavorCockroachDB:
+		return cockroachCapabilities()
+	case flavorYugabyteDB:
+		return yugabyteCapabilities()
+	default:
+		return postgresCapabilities()
+	}
+}
+
+// objectKindSupported drops catalog rows for object kinds the flavor does
+// not advertise, such as the empty pg_trigger rows CockroachDB emulates.
+func objectKindSupported(
+	kind database.ObjectKind,
+	capabilities database.Capabilities,
+) bool {
+	switch kind {
+	case database.ObjectKindMaterializedView:
+		return capabilities.MaterializedViews
+	case database.ObjectKindFunction:
+		return capabilities.Functions
+	case database.ObjectKindProcedure:
+		return capabilities.Procedures
+	case database.ObjectKindTrigger:
+		return capabilities.Triggers
+	case database.ObjectKindSequence:
+		return capabilities.Sequences
+	case database.ObjectKindDomain:
+		return capabilities.Domains
+	case database.ObjectKindExtension:
+		return capabilities.Extensions
+	case database.ObjectKindEnum, database.ObjectKindType:
+		return capabilities.CustomTypes
+	default:
+		return true
+	}
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"testing"
+
+	"rollingthunder/pkg/database"
+)
+
+func TestDetectFlavorFromVersion(t *testing.T) {
+	tests := []struct {
+		version     string
+		wantFlavor  flavor
+		wantRelease string
+	}{
+		{
+			version:    "PostgreSQL 16.4 on x86_64-pc-linux-gnu, compiled by gcc",
+			wantFlavor: flavorPostgres,
+		},
+		{
+			version:     "CockroachDB CCL v24.1.5 (x86_64-pc-linux-gnu, built 2024/09/12 12:00:00, go1.22.5)",
+			wantFlavor:  flavorCockroachDB,
+			wantRelease: "v24.1.5",
+		},
+		{
+			version:     "PostgreSQL 15.2-YB-2.25.0.0-b0 on x86_64-pc-linux-gnu, compiled by clang",
+			wantFlavor:  flavorYugabyteDB,
+			wantRelease: "2.25.0.0-b0 (PostgreSQL 15.2)",
+		},
+	}
+	for _, test := range tests {
+		gotFlavor, gotRelease := detectFlavor(test.version)
+		if gotFlavor != test.wantFlavor || gotRelease != test.wantRelease {
+			t.Fatalf(
+				"detectFlavor(%q) = %q, %q; want %q, %q",
+				test.version,
+				gotFlavor,
+				gotRelease,
+				test.wantFlavor,
+				test.wantRelease,
+			)
+		}
+	}
+}
+
+func TestFlavorCapabilitiesAreValid(t *testing.T) {
+	for _, selected := range []flavor{flavorPostgres, flavorCockroachDB, flavorYugabyteDB} {
+		capabilities := selected.capabilities()
+		if err := capabilities.Validate(); err != nil {
+			t.Fatalf("%s capabilities are invalid: %v", selected, err)
+		}
+		if capabilities.Engine != string(selected) {
+			t.Fatalf("%s engine = %q", selected, capabilities.Engine)
+		}
+	}
+	cockroach := flavorCockroachDB.capabilities()
+	if cockroach.Triggers || cockroach.Functions || cockroach.ManageSecurity ||
+		cockroach.TransactionalDDL {
+		t.Fatalf("CockroachDB advertises emulated catalog workflows: %+v", cockroach)
+	}
+	if objectKindSupported(database.ObjectKindTrigger, cockroach) {
+		t.Fatal("CockroachDB trigger rows were not filtered")
+	}
+	yugabyte := flavorYugabyteDB.capabilities()
+	if !yugabyte.Triggers || !yugabyte.ManageSecurity || yugabyte.TransactionalDDL {
+		t.Fatalf("YugabyteDB capabilities = %+v", yugabyte)
+	}
+}
+
+func TestFlavorDefaultPorts(t *testing.T) {
+	tests := map[string]uint16{
+		"":            5432,
+		"cockroachdb": 26257,
+		"yugabytedb":  5433,
+	}
+	for selected, want := range tests {
+		config, err := buildPostgresPoolConfig(Config{
+			Host:   "127.0.0.1",
+			Db:     "rolling",
+			Flavor: selected,
+		})
+		if err != nil {
+			t.Fatalf("buildPostgresPoolConfig(%q) error = %v", selected, err)
+		}
+		if config.ConnConfig.Port != want {
+			t.Fatalf("%q port = %d, want %d", selected, config.ConnConfig.Port, want)
+		}
+	}
+}
+
+func TestParseCockroachPlanBuildsTree(t *testing.T) {
+	plan, err := parseCockroachPlan([]string{
+		"distribution: local",
+		"vectorized: true",
+		"",
+		"• hash join",
+		"│ estimated row count: 1,200",
+		"│ equality: (id) = (customer_id)",
+		"│",
+		"├── • scan",
+		"│     estimated row count: 50 (100% of the table)",
+		"│     table: customers@customers_pkey",
+		"│     spans: FULL SCAN",
+		"│",
+		"└── • filter",
+		"    │ filter: total > 10",
+		"    │",
+		"    └── • scan",
+		"          table: orders@orders_pkey",
+	})
+	if err != nil {
+		t.Fatalf("parseCockroachPlan() error = %v", err)
+	}
+	if plan.Engine != "CockroachDB" || len(plan.Roots) != 1 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	root := plan.Roots[0]
+	if root.NodeType != "hash join" || root.EstimatedRows != 1200 ||
+		root.Details["distribution"] != "local" || len(root.Children) != 2 {
+		t.Fatalf("root = %+v", root)
+	}
+	customers := root.Children[0]
+	if customers.ID != "crdb-0-0" || customers.ParentID != "crdb-0" ||
+		customers.Relation != "customers" ||
+		customers.Details["Index Name"] != "customers_pkey" ||
+		customers.EstimatedRows != 50 {
+		t.Fatalf("customers scan = %+v", customers)
+	}
+	filter := root.Children[1]
+	if filter.NodeType != "filter" || filter.Details["filter"] != "total > 10" ||
+		len(filter.Children) != 1 {
+		t.Fatalf("filter = %+v", filter)
+	}
+	if orders := filter.Children[0]; orders.Summary != "scan on orders" ||
+		orders.ID != "crdb-0-1-0" {
+		t.Fatalf("orders scan = %+v", orders)
+	}
+}
+
+func TestParseCockroachPlanRejectsEmptyOutput(t *testing.T) {
+	if _, err := parseCockroachPlan([]string{"distribution: local"}); err == nil {
+		t.Fatal("parseCockroachPlan() accepted a plan without nodes")
+	}
+}
+
+func TestParseYugabytePlanKeepsStorageFilters(t *testing.T) {
+	plan, err := parseYugabytePlan(`[{"Plan": {
+		"Node Type": "Seq Scan",
+		"Relation Name": "orders",
+		"Plan Rows": 12,
+		"Storage Filter": "(total > 10)",
+		"Estimated Seeks": 1
+	}}]`)
+	if err != nil {
+		t.Fatalf("parseYugabytePlan() error = %v", err)
+	}
+	if plan.Engine != "YugabyteDB" || len(plan.Roots) != 1 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	root := plan.Roots[0]
+	if root.ID != "yb-0" || root.Relation != "orders" ||
+		root.Details["Storage Filter"] != "(total > 10)" ||
+		root.Details["Estimated Seeks"] != "1" {
+		t.Fatalf("root = %+v", root)
+	}
+}
+
+func TestValidCockroachSessionID(t *testing.T) {
+	if !validCockroachSessionID("17d2ac9c5d5e2a4a0000000000000001") {
+		t.Fatal("valid session ID was rejected")
+	}
+	for _, value := range []string{"", "1234", "invalid-session-id", "17d2ac9c5d5e2a4a00000000000000zz"} {
+		if validCockroachSessionID(value) {
+			t.Fatalf("validCockroachSessionID(%q) = true", value)
+		}
+	}
+}
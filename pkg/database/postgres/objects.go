@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -328,12 +329,17 @@ func (p *Postgres) ListObjects(
 
 	allowedKinds := objectKindSet(filter.Kinds)
 	capabilities := p.Capabilities()
+	hiddenSchemas := p.flavor.hiddenSchemas()
 	objects := make([]database.DatabaseObject, 0, len(rows))
 	for _, row := range rows {
 		object, err := postgresObjectFromRow(row, capabilities)
 		if err != nil {
 			return nil, err
 		}
+		if !objectKindSupported(object.Reference.Kind, capabilities) ||
+			slices.Contains(hiddenSchemas, object.Reference.Schema) {
+			continue
+		}
 		if len(allowedKinds) > 0 {
 			if _, allowed := allowedKinds[object.Reference.Kind]; !allowed {
 				continue
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"rollingthunder/pkg/application"
 	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
@@ -26,21 +30,28 @@ type Config struct {
 	SSLCert       string
 	SSLKey        string
 	TLSServerName string
+	// Flavor is the registered driver name the connection was opened with.
+	// Connect replaces it with the flavor the server reports.
+	Flavor string
 }
 
 type Postgres struct {
-	cfg    Config
-	ctx    context.Context
-	conn   *sqlx.DB
-	pool   *pgxpool.Pool
-	engine string
+	cfg     Config
+	ctx     context.Context
+	conn    *sqlx.DB
+	pool    *pgxpool.Pool
+	engine  string
+	flavor  flavor
+	release string
 }
 
 func NewPostgres(ctx context.Context, cfg Config) *Postgres {
+	selected := parseFlavor(cfg.Flavor)
 	return &Postgres{
 		cfg:    cfg,
 		ctx:    ctx,
-		engine: "PostgreSQL",
+		engine: selected.displayName(),
+		flavor: selected,
 	}
 }
 
@@ -84,7 +95,7 @@ func buildPostgresPoolConfig(config Config) (*pgxpool.Config, error) {
 	}
 	port := strings.TrimSpace(config.Port)
 	if port == "" {
-		port = database.DefaultPostgresPort
+		port = parseFlavor(config.Flavor).defaultPort()
 	}
 
 	setting := func(name string, value string) string {
@@ -118,6 +129,9 @@ func buildPostgresPoolConfig(config Config) (*pgxpool.Config, error) {
 		return nil, err
 	}
 	applyPostgresTLSServerName(poolConfig, config.TLSServerName)
+	tracer := &statementTracer{}
+	poolConfig.ConnConfig.Tracer = tracer
+	poolConfig.ConnConfig.OnNotice = tracer.notice
 	return poolConfig, nil
 }
 
@@ -145,6 +159,13 @@ func (p *Postgres) Connect(ctx context.Context) error {
 		pool.Close()
 		return err
 	}
+	var version string
+	if err := pool.QueryRow(ctx, "SELECT version()").Scan(&version); err != nil {
+		pool.Close()
+		return fmt.Errorf("read server version: %w", err)
+	}
+	p.flavor, p.release = detectFlavor(version)
+	p.engine = p.flavor.displayName()
 
 	p.pool = pool
 	p.conn = sqlx.NewDb(stdlib.OpenDBFromPool(pool), "pgx")
@@ -200,10 +221,10 @@ func (p *Postgres) GetSchemas() ([]string, error) {
 	query := `
 		SELECT schema_name
 		FROM information_schema.schemata
-		WHERE schema_name NOT IN ('pg_catalog', 'information_schema')
+		WHERE schema_name <> ALL($1::text[])
 		ORDER BY schema_name
 	`
-	err := p.conn.Select(&schemas, query)
+	err := p.conn.Select(&schemas, query, p.flavor.hiddenSchemas())
 
 	return schemas, err
 }
@@ -459,6 +480,9 @@ func (p *Postgres) GetDatabaseInfo() (database.Info, error) {
 	if err != nil {
 		return database.Info{}, err
 	}
+	if p.release != "" {
+		version = p.release
+	}
 	err = p.conn.Get(&db, "SELECT current_database()")
 
 	return database.Info{
@@ -469,26 +493,37 @@ func (p *Postgres) GetDatabaseInfo() (database.Info, error) {
 }
 
 func (p *Postgres) CountCollectionData(table database.Table) (int, error) {
+	return p.CountCollectionDataContext(context.Background(), table)
+}
+
+func (p *Postgres) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
 	var result int
-	columns, err := p.getCollectionStructures(table)
+	from, args, err := p.filteredRelation(table)
 	if err != nil {
 		return 0, err
 	}
+	err = p.conn.GetContext(ctx, &result, "SELECT COUNT(*) FROM "+from, args...)
+	return result, err
+}
+
+// filteredRelation returns the quoted table followed by its WHERE clause.
+func (p *Postgres) filteredRelation(table database.Table) (string, []interface{}, error) {
+	columns, err := p.getCollectionStructures(table)
+	if err != nil {
+		return "", nil, err
+	}
 	filterClause, args, err := buildPostgresFilterClause(
-		table.Filters,
+		table.FilterTree(),
 		structuresFromColumns(columns),
 		1,
 	)
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
-	query := fmt.Sprintf(
-		"SELECT COUNT(*) FROM %s",
-		quotePostgresQualifiedIdentifier(table.Schema, table.Name),
-	)
-	query += filterClause
-	err = p.conn.Get(&result, query, args...)
-	return result, err
+	return quotePostgresQualifiedIdentifier(table.Schema, table.Name) + filterClause, args, nil
 }
 
 func structuresFromColumns(columns Columns) database.Structures {
@@ -534,13 +569,8 @@ func (p *Postgres) GetCollectionData(table database.Table) (database.Structures,
 	}
 	structures := structuresFromColumns(columns)
 
-	orderClause, err := buildPostgresOrderClause(table.Sorts, structures)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	filterClause, args, err := buildPostgresFilterClause(
-		table.Filters,
+		table.FilterTree(),
 		structures,
 		1,
 	)
@@ -548,6 +578,11 @@ func (p *Postgres) GetCollectionData(table database.Table) (database.Structures,
 		return nil, nil, err
 	}
 
+	orderClause, args, err := buildPostgresOrderClause(table.Sorts, structures, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	query := fmt.Sprintf(
 		`SELECT * FROM %s`,
 		quotePostgresQualifiedIdentifier(table.Schema, table.Name),
@@ -705,20 +740,19 @@ func (p *Postgres) DeleteRow(table database.Table, primaryKey string, primaryVal
 	return nil
 }
 
-type mappedRows interface {
+type queryRows interface {
 	Next() bool
-	Columns() ([]string, error)
-	MapScan(dest map[string]interface{}) error
+	SliceScan() ([]interface{}, error)
 	Err() error
 }
 
-func collectQueryResults(rows mappedRows, maxRows int) (database.QueryResult, error) {
-	columns, err := rows.Columns()
-	if err != nil {
-		return database.QueryResult{}, fmt.Errorf("read query result columns: %w", err)
-	}
+func collectQueryResults(
+	rows queryRows,
+	columns []database.QueryColumn,
+	maxRows int,
+) (database.QueryResult, error) {
 	result := database.QueryResult{
-		Rows:     make([]map[string]interface{}, 0),
+		Rows:     make([][]interface{}, 0),
 		RowLimit: maxRows,
 		Columns:  columns,
 	}
@@ -729,8 +763,8 @@ func collectQueryResults(rows mappedRows, maxRows int) (database.QueryResult, er
 			break
 		}
 
-		row := make(map[string]interface{})
-		if err := rows.MapScan(row); err != nil {
+		row, err := rows.SliceScan()
+		if err != nil {
 			return database.QueryResult{}, fmt.Errorf("error scanning row: %w", err)
 		}
 		result.Rows = append(result.Rows, row)
@@ -756,15 +790,203 @@ func executePostgresQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
-	rows, err := runner.QueryxContext(ctx, query, options.Args...)
+	trace := &statementTrace{}
+	rows, err := runner.QueryxContext(
+		context.WithValue(ctx, statementTraceKey{}, trace),
+		query,
+		options.Args...,
+	)
 	if err != nil {
 		return database.QueryResult{}, err
 	}
 	defer rows.Close()
 
-	return collectQueryResults(rows, options.MaxRows)
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return database.QueryResult{}, fmt.Errorf("read query result columns: %w", err)
+	}
+	result, err := collectQueryResults(rows, sqladapter.QueryColumns(columnTypes), options.MaxRows)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	_ = rows.Close()
+	applyCommandTag(&result, trace.tag)
+	result.Messages = trace.messages.Messages()
+	return result, nil
+}
+
+type statementTraceKey struct{}
+
+// statementTrace carries what pgx reports about one statement back to the
+// executePostgresQuery call that started it. database/sql exposes neither
+// the completion tag nor the notices the server sends along the way.
+type statementTrace struct {
+	tag      pgconn.CommandTag
+	messages database.QueryMessageLog
+}
+
+// statementTracer ties each connection to the statement it is running, so
+// notices, which pgx delivers per connection, reach the right result.
+type statementTracer struct {
+	active sync.Map
+}
+
+func (tracer *statementTracer) TraceQueryStart(
+	ctx context.Context,
+	conn *pgx.Conn,
+	_ pgx.TraceQueryStartData,
+) context.Context {
+	if trace, ok := ctx.Value(statementTraceKey{}).(*statementTrace); ok {
+		tracer.active.Store(conn.PgConn(), trace)
+	}
+	return ctx
+}
+
+func (tracer *statementTracer) TraceQueryEnd(
+	ctx context.Context,
+	conn *pgx.Conn,
+	data pgx.TraceQueryEndData,
+) {
+	trace, ok := ctx.Value(statementTraceKey{}).(*statementTrace)
+	if !ok {
+		return
+	}
+	tracer.active.Delete(conn.PgConn())
+	if data.Err == nil {
+		trace.tag = data.CommandTag
+	}
+}
+
+func (tracer *statementTracer) notice(conn *pgconn.PgConn, notice *pgconn.Notice) {
+	value, ok := tracer.active.Load(conn)
+	if !ok {
+		return
+	}
+	value.(*statementTrace).messages.Add(
+		postgresNoticeSeverity(notice.SeverityUnlocalized),
+		notice.Code,
+		postgresNoticeText(notice),
+	)
+}
+
+func postgresNoticeSeverity(severity string) database.QueryMessageSeverity {
+	switch strings.ToUpper(severity) {
+	case "DEBUG":
+		return database.QueryMessageDebug
+	case "LOG", "INFO":
+		return database.QueryMessageInfo
+	case "WARNING":
+		return database.QueryMessageWarning
+	default:
+		return database.QueryMessageNotice
+	}
+}
+
+func postgresNoticeText(notice *pgconn.Notice) string {
+	text := notice.Message
+	if notice.Detail != "" {
+		text += "\nDETAIL: " + notice.Detail
+	}
+	if notice.Hint != "" {
+		text += "\nHINT: " + notice.Hint
+	}
+	return text
+}
+
+func applyCommandTag(result *database.QueryResult, tag pgconn.CommandTag) {
+	result.CommandTag = tag.String()
+	command, _, _ := strings.Cut(result.CommandTag, " ")
+	switch command {
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "COPY":
+		affected := tag.RowsAffected()
+		result.RowsAffected = &affected
+	}
 }
 
+const querySourcesQuery = `
+SELECT a.attrelid, a.attnum, n.nspname, c.relname, a.attname
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE a.attrelid = ANY($1) AND a.attnum > 0`
+
+type querySourceKey struct {
+	table     uint32
+	attribute int16
+}
+
+// DescribeQuerySources reports where each result column of query comes
+// from. database/sql drops the table OID and attribute number pgx receives
+// with every result, so queries run without them and callers that need the
+// sources describe the statement on demand instead. Columns that are
+// computed, or that come from temporary objects another session created,
+// keep empty sources.
+func (p *Postgres) DescribeQuerySources(
+	ctx context.Context,
+	query string,
+) ([]database.QueryColumn, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+	description, err := conn.Conn().PgConn().Prepare(ctx, "", query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("describe query: %w", err)
+	}
+	columns := make([]database.QueryColumn, len(description.Fields))
+	tables := make([]uint32, 0, len(description.Fields))
+	for index, field := range description.Fields {
+		columns[index].Name = field.Name
+		if field.TableOID != 0 {
+			tables = append(tables, field.TableOID)
+		}
+	}
+	if len(tables) == 0 {
+		return columns, nil
+	}
+	rows, err := conn.Query(ctx, querySourcesQuery, tables)
+	if err != nil {
+		return nil, fmt.Errorf("read query column sources: %w", err)
+	}
+	defer rows.Close()
+	sources := make(map[querySourceKey]database.QueryColumn)
+	for rows.Next() {
+		var (
+			key    querySourceKey
+			source database.QueryColumn
+		)
+		if err := rows.Scan(
+			&key.table,
+			&key.attribute,
+			&source.SourceSchema,
+			&source.SourceTable,
+			&source.SourceColumn,
+		); err != nil {
+			return nil, fmt.Errorf("read query column sources: %w", err)
+		}
+		sources[key] = source
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read query column sources: %w", err)
+	}
+	for index, field := range description.Fields {
+		source, ok := sources[querySourceKey{
+			table:     field.TableOID,
+			attribute: int16(field.TableAttributeNumber),
+		}]
+		if !ok {
+			continue
+		}
+		columns[index].SourceSchema = source.SourceSchema
+		columns[index].SourceTable = source.SourceTable
+		columns[index].SourceColumn = source.SourceColumn
+	}
+	return columns, nil
+}
+
+var _ database.QuerySourceDriver = (*Postgres)(nil)
+
 // ExecuteQuery executes a raw SQL query and returns a bounded result set.
 func (p *Postgres) ExecuteQuery(
 	ctx context.Context,
@@ -774,8 +996,11 @@ func (p *Postgres) ExecuteQuery(
 	return executePostgresQuery(ctx, p.conn, query, options)
 }
 
+// postgresTransaction pins its pooled connection so bulk loads can reach the
+// underlying pgx connection that runs the transaction.
 type postgresTransaction struct {
-	tx *sqlx.Tx
+	conn *sqlx.Conn
+	tx   *sqlx.Tx
 }
 
 func (transaction *postgresTransaction) ExecuteQuery(
@@ -787,21 +1012,36 @@ func (transaction *postgresTransaction) ExecuteQuery(
 }
 
 func (transaction *postgresTransaction) Commit() error {
-	return transaction.tx.Commit()
+	err := transaction.tx.Commit()
+	_ = transaction.conn.Close()
+	return err
 }
 
 func (transaction *postgresTransaction) Rollback() error {
-	return transaction.tx.Rollback()
+	err := transaction.tx.Rollback()
+	_ = transaction.conn.Close()
+	return err
 }
 
 func (p *Postgres) BeginTransaction(
 	ctx context.Context,
 ) (database.Transaction, error) {
-	transaction, err := p.conn.BeginTxx(ctx, nil)
+	conn, err := p.conn.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := conn.BeginTxx(ctx, nil)
 	if err != nil {
+		_ = conn.Close()
 		return nil, err
 	}
-	return &postgresTransaction{tx: transaction}, nil
+	return &postgresTransaction{conn: conn, tx: transaction}, nil
+}
+
+// ReturningQuery reads the changed rows with RETURNING; the command tag
+// still carries the full count when only a sample of them is read.
+func (p *Postgres) ReturningQuery(statement string) (database.ReturningQuery, bool) {
+	return database.ReturningAllQuery(statement)
 }
 
 type sqlxExportRows struct {
@@ -848,18 +1088,18 @@ func buildPostgresExportQueryWithProjection(
 		return postgresQuery{}, fmt.Errorf("table offset cannot be negative")
 	}
 
-	orderClause, err := buildPostgresOrderClause(table.Sorts, structures)
-	if err != nil {
-		return postgresQuery{}, err
-	}
 	filterClause, args, err := buildPostgresFilterClause(
-		table.Filters,
+		table.FilterTree(),
 		structures,
 		1,
 	)
 	if err != nil {
 		return postgresQuery{}, err
 	}
+	orderClause, args, err := buildPostgresOrderClause(table.Sorts, structures, args)
+	if err != nil {
+		return postgresQuery{}, err
+	}
 
 	query := fmt.Sprintf(
 		"SELECT %s FROM %s",
@@ -950,7 +1190,13 @@ func (p *Postgres) ExportTable(
 		)
 	}
 
-	return database.WriteExportStreamContext(ctx, writer, exportRows, request.Options)
+	return database.WriteTypedExportStreamContext(
+		ctx,
+		writer,
+		exportRows,
+		structuresFromColumns(columns),
+		request.Options,
+	)
 }
 
 // CreateTable creates a new table in the database
@@ -1155,6 +1401,9 @@ func (p *Postgres) GetTableDDL(table database.Table) (string, error) {
 	if schema == "" {
 		schema = "public"
 	}
+	if p.flavor == flavorCockroachDB {
+		return p.cockroachTableDDL(table, schema)
+	}
 
 	// Get columns
 	columns, err := p.GetCollectionStructures(table)
@@ -5,10 +5,12 @@ import (
 	"testing"
 
 	"rollingthunder/pkg/database"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
-type fakeMappedRows struct {
-	rows        []map[string]interface{}
+type fakeQueryRows struct {
+	rows        [][]interface{}
 	index       int
 	nextCalls   int
 	scanCalls   int
@@ -16,18 +18,7 @@ type fakeMappedRows struct {
 	resultError error
 }
 
-func (f *fakeMappedRows) Columns() ([]string, error) {
-	if len(f.rows) == 0 {
-		return []string{}, nil
-	}
-	columns := make([]string, 0, len(f.rows[0]))
-	for column := range f.rows[0] {
-		columns = append(columns, column)
-	}
-	return columns, nil
-}
-
-func (f *fakeMappedRows) Next() bool {
+func (f *fakeQueryRows) Next() bool {
 	f.nextCalls++
 	if f.index >= len(f.rows) {
 		return false
@@ -36,31 +27,26 @@ func (f *fakeMappedRows) Next() bool {
 	return true
 }
 
-func (f *fakeMappedRows) MapScan(dest map[string]interface{}) error {
+func (f *fakeQueryRows) SliceScan() ([]interface{}, error) {
 	f.scanCalls++
 	if f.scanError != nil {
-		return f.scanError
-	}
-	for key, value := range f.rows[f.index-1] {
-		dest[key] = value
+		return nil, f.scanError
 	}
-	return nil
+	return append([]interface{}(nil), f.rows[f.index-1]...), nil
 }
 
-func (f *fakeMappedRows) Err() error {
+func (f *fakeQueryRows) Err() error {
 	return f.resultError
 }
 
+var fakeQueryColumns = []database.QueryColumn{{Name: "id", Type: "INT4"}}
+
 func TestCollectQueryResultsStopsAfterLimitAndDetectsMoreRows(t *testing.T) {
-	rows := &fakeMappedRows{
-		rows: []map[string]interface{}{
-			{"id": 1},
-			{"id": 2},
-			{"id": 3},
-		},
+	rows := &fakeQueryRows{
+		rows: [][]interface{}{{1}, {2}, {3}},
 	}
 
-	result, err := collectQueryResults(rows, 2)
+	result, err := collectQueryResults(rows, fakeQueryColumns, 2)
 	if err != nil {
 		t.Fatalf("collect query results: %v", err)
 	}
@@ -82,14 +68,11 @@ func TestCollectQueryResultsStopsAfterLimitAndDetectsMoreRows(t *testing.T) {
 }
 
 func TestCollectQueryResultsDoesNotMarkExactLimitAsTruncated(t *testing.T) {
-	rows := &fakeMappedRows{
-		rows: []map[string]interface{}{
-			{"id": 1},
-			{"id": 2},
-		},
+	rows := &fakeQueryRows{
+		rows: [][]interface{}{{1}, {2}},
 	}
 
-	result, err := collectQueryResults(rows, 2)
+	result, err := collectQueryResults(rows, fakeQueryColumns, 2)
 	if err != nil {
 		t.Fatalf("collect query results: %v", err)
 	}
@@ -102,15 +85,11 @@ func TestCollectQueryResultsDoesNotMarkExactLimitAsTruncated(t *testing.T) {
 }
 
 func TestCollectQueryResultsSupportsUnlimitedInternalUse(t *testing.T) {
-	rows := &fakeMappedRows{
-		rows: []map[string]interface{}{
-			{"id": 1},
-			{"id": 2},
-			{"id": 3},
-		},
+	rows := &fakeQueryRows{
+		rows: [][]interface{}{{1}, {2}, {3}},
 	}
 
-	result, err := collectQueryResults(rows, 0)
+	result, err := collectQueryResults(rows, fakeQueryColumns, 0)
 	if err != nil {
 		t.Fatalf("collect query results: %v", err)
 	}
@@ -125,10 +104,11 @@ func TestCollectQueryResultsSupportsUnlimitedInternalUse(t *testing.T) {
 func TestCollectQueryResultsReturnsScanAndIterationErrors(t *testing.T) {
 	scanFailure := errors.New("scan failed")
 	if _, err := collectQueryResults(
-		&fakeMappedRows{
-			rows:      []map[string]interface{}{{"id": 1}},
+		&fakeQueryRows{
+			rows:      [][]interface{}{{1}},
 			scanError: scanFailure,
 		},
+		fakeQueryColumns,
 		10,
 	); !errors.Is(err, scanFailure) {
 		t.Fatalf("expected scan error, got %v", err)
@@ -136,7 +116,8 @@ func TestCollectQueryResultsReturnsScanAndIterationErrors(t *testing.T) {
 
 	iterationFailure := errors.New("iteration failed")
 	if _, err := collectQueryResults(
-		&fakeMappedRows{resultError: iterationFailure},
+		&fakeQueryRows{resultError: iterationFailure},
+		fakeQueryColumns,
 		10,
 	); !errors.Is(err, iterationFailure) {
 		t.Fatalf("expected iteration error, got %v", err)
@@ -236,3 +217,56 @@ func TestBuildPostgresExportQueryUsesPageBoundsForSelectedRows(t *testing.T) {
 		t.Fatalf("query = %q, want %q", query.SQL, expected)
 	}
 }
+
+func TestApplyCommandTagReportsMutationCounts(t *testing.T) {
+	var updated database.QueryResult
+	applyCommandTag(&updated, pgconn.NewCommandTag("UPDATE 42"))
+	if updated.CommandTag != "UPDATE 42" || updated.RowsAffected == nil || *updated.RowsAffected != 42 {
+		t.Fatalf("UPDATE tag = %q, %v", updated.CommandTag, updated.RowsAffected)
+	}
+
+	var inserted database.QueryResult
+	applyCommandTag(&inserted, pgconn.NewCommandTag("INSERT 0 1"))
+	if inserted.RowsAffected == nil || *inserted.RowsAffected != 1 {
+		t.Fatalf("INSERT tag rows affected = %v", inserted.RowsAffected)
+	}
+
+	var selected database.QueryResult
+	applyCommandTag(&selected, pgconn.NewCommandTag("SELECT 3"))
+	if selected.CommandTag != "SELECT 3" || selected.RowsAffected != nil {
+		t.Fatalf("SELECT tag = %q, %v", selected.CommandTag, selected.RowsAffected)
+	}
+}
+
+func TestStatementTracerRoutesNoticesToTheRunningStatement(t *testing.T) {
+	tracer := &statementTracer{}
+	running, idle := &pgconn.PgConn{}, &pgconn.PgConn{}
+	trace := &statementTrace{}
+	tracer.active.Store(running, trace)
+
+	tracer.notice(running, &pgconn.Notice{
+		SeverityUnlocalized: "NOTICE",
+		Code:                "00000",
+		Message:             "checked 3 rows",
+	})
+	tracer.notice(idle, &pgconn.Notice{SeverityUnlocalized: "NOTICE", Message: "other session"})
+	tracer.notice(running, &pgconn.Notice{
+		SeverityUnlocalized: "WARNING",
+		Code:                "01000",
+		Message:             "slow path",
+		Hint:                "add an index",
+	})
+
+	messages := trace.messages.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("messages = %#v, want 2", messages)
+	}
+	if messages[0].Sequence != 1 || messages[0].Severity != database.QueryMessageNotice ||
+		messages[0].Code != "00000" || messages[0].Text != "checked 3 rows" {
+		t.Fatalf("first message = %#v", messages[0])
+	}
+	if messages[1].Sequence != 2 || messages[1].Severity != database.QueryMessageWarning ||
+		messages[1].Text != "slow path\nHINT: add an index" {
+		t.Fatalf("second message = %#v", messages[1])
+	}
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverPostgres, openFlavor(flavorPostgres), probe)
+	database.RegisterDriver(database.DriverCockroachDB, openFlavor(flavorCockroachDB), probeCockroachDB)
+	database.RegisterDriver(database.DriverYugabyteDB, openFlavor(flavorYugabyteDB), probeYugabyteDB)
+}
+
+// openFlavor only picks the default port and the capabilities shown before
+// connecting; Connect switches to whichever flavor the server reports.
+func openFlavor(selected flavor) database.DriverFactory {
+	return func(ctx context.Context, cfg database.Config) (database.Driver, error) {
+		return NewPostgres(ctx, Config{
+			Host:          cfg.Host,
+			Port:          cfg.Port,
+			User:          cfg.User,
+			Password:      cfg.Password,
+			Db:            cfg.Db,
+			SSLMode:       cfg.SSLMode,
+			SSLRootCert:   cfg.SSLRootCert,
+			SSLCert:       cfg.SSLCert,
+			SSLKey:        cfg.SSLKey,
+			TLSServerName: cfg.TLSServerName,
+			Flavor:        string(selected),
+		}), nil
+	}
+}
+
+// probe reports pg_dump/pg_restore readiness. Backups need pg_dump; restores
+// additionally need pg_restore, which is reported separately.
+func probe(lookPath database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*Postgres)(nil).Capabilities()
+	_, backupTool := database.LookupExecutable(lookPath, "pg_dump")
+	_, restoreTool := database.LookupExecutable(lookPath, "pg_restore")
+	available := backupTool != ""
+	message := ""
+	if !available {
+		message = "Install PostgreSQL client tools so pg_dump is available in PATH."
+	} else if restoreTool == "" {
+		message = "Backups are available, but pg_restore is required to restore them."
+	}
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available:     available,
+			Engine:        capabilities.Engine,
+			Format:        database.BackupFormatPostgresCustom,
+			Extension:     ".dump",
+			BackupTool:    backupTool,
+			RestoreTool:   restoreTool,
+			RestoreReady:  restoreTool != "",
+			Message:       message,
+			SupportsScope: true,
+		},
+	}
+}
+
+// pg_dump reads catalogs CockroachDB does not implement, so backups go
+// through its own BACKUP statement instead.
+func probeCockroachDB(database.ExecutableLookup) database.DriverProbe {
+	capabilities := flavorCockroachDB.capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available: false,
+			Engine:    capabilities.Engine,
+			Message: "CockroachDB backups are written by the cluster. Run BACKUP " +
+				"DATABASE ... INTO an external storage location from the SQL editor.",
+		},
+	}
+}
+
+// YugabyteDB ships ysql_dump, a pg_dump build that understands its
+// tablet and colocation options; stock pg_dump output does not restore
+// cleanly.
+func probeYugabyteDB(database.ExecutableLookup) database.DriverProbe {
+	capabilities := flavorYugabyteDB.capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available: false,
+			Engine:    capabilities.Engine,
+			Message: "YugabyteDB backups need ysql_dump from the YugabyteDB " +
+				"distribution or a distributed snapshot; run them outside Rolling Thunder.",
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+
+	"rollingthunder/pkg/database"
+)
+
+// EstimateCollectionData reads pg_class.reltuples, which VACUUM and ANALYZE
+// keep close to the live row count. A filtered table is estimated by the
+// planner instead.
+func (p *Postgres) EstimateCollectionData(
+	ctx context.Context,
+	table database.Table,
+) (database.RowCount, bool, error) {
+	if p.flavor == flavorCockroachDB {
+		return database.RowCount{}, false, nil
+	}
+	if table.Filtered() {
+		from, args, err := p.filteredRelation(table)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		plan, err := p.ExplainQueryWithArgs(ctx, "SELECT * FROM "+from, args)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		count, ok := database.PlannerRowCount(plan)
+		return count, ok, nil
+	}
+
+	var statistics struct {
+		Tuples float64 `db:"reltuples"`
+		Pages  int64   `db:"relpages"`
+	}
+	err := p.conn.GetContext(
+		ctx,
+		&statistics,
+		`SELECT reltuples::float8 AS reltuples, relpages::bigint AS relpages
+		FROM pg_class
+		WHERE oid = to_regclass($1)`,
+		quotePostgresQualifiedIdentifier(table.Schema, table.Name),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.RowCount{}, false, nil
+	}
+	if err != nil {
+		return database.RowCount{}, false, err
+	}
+	// reltuples is -1 before the first VACUUM or ANALYZE on PostgreSQL 14
+	// and later, and 0 with no pages on older servers.
+	if statistics.Tuples < 0 || (statistics.Tuples == 0 && statistics.Pages == 0) {
+		return database.RowCount{}, false, nil
+	}
+	return database.RowCount{
+		Rows:      int64(math.Round(statistics.Tuples)),
+		Estimated: true,
+		Source:    database.RowCountStatistics,
+	}, true, nil
+}
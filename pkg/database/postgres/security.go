@@ -37,6 +37,16 @@ func (p *Postgres) GetSecurityOverview(
 	if p.conn == nil {
 		return database.SecurityOverview{}, fmt.Errorf("PostgreSQL connection is not open")
 	}
+	if !p.Capabilities().ManageSecurity {
+		return database.SecurityOverview{
+			Supported:  false,
+			Engine:     p.Capabilities().Engine,
+			Principals: []database.DatabasePrincipal{},
+			Grants:     []database.DatabaseGrant{},
+			Message: p.engine + " roles do not carry PostgreSQL role attributes; " +
+				"review them with SHOW ROLES and SHOW GRANTS in the SQL editor.",
+		}, nil
+	}
 	var currentUser string
 	if err := p.conn.GetContext(ctx, &currentUser, "SELECT current_user"); err != nil {
 		return database.SecurityOverview{}, err
@@ -240,6 +250,12 @@ func (p *Postgres) BuildSecurityChange(
 	if err := request.Validate(); err != nil {
 		return database.SecurityChangePlan{}, err
 	}
+	if !p.Capabilities().ManageSecurity {
+		return database.SecurityChangePlan{}, fmt.Errorf(
+			"%s security changes are not supported",
+			p.engine,
+		)
+	}
 	switch request.Action {
 	case database.SecurityCreatePrincipal, database.SecurityAlterPrincipal:
 		options := request.Principal
@@ -369,6 +385,9 @@ func (p *Postgres) ApplySecurityChange(
 	if err := plan.Validate(); err != nil {
 		return err
 	}
+	if !p.Capabilities().ManageSecurity {
+		return fmt.Errorf("%s security changes are not supported", p.engine)
+	}
 	transaction, err := p.conn.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -14,10 +14,13 @@ func quotePostgresQualifiedIdentifier(schema, name string) string {
 	return quotePostgresIdentifier(schema) + "." + quotePostgresIdentifier(name)
 }
 
+// buildPostgresOrderClause binds JSON sort paths after args, the arguments
+// the statement already holds, and returns the extended list.
 func buildPostgresOrderClause(
 	sorts []database.Sort,
 	structures database.Structures,
-) (string, error) {
+	args []interface{},
+) (string, []interface{}, error) {
 	availableColumns := make(map[string]database.Structure, len(structures))
 	for _, structure := range structures {
 		availableColumns[structure.Name] = structure
@@ -29,21 +32,36 @@ func buildPostgresOrderClause(
 	for _, sort := range sorts {
 		column := strings.TrimSpace(sort.Column)
 		if column == "" {
-			return "", fmt.Errorf("sort column cannot be empty")
+			return "", nil, fmt.Errorf("sort column cannot be empty")
 		}
 		if _, exists := availableColumns[column]; !exists {
-			return "", fmt.Errorf("cannot sort by unknown column %q", column)
+			return "", nil, fmt.Errorf("cannot sort by unknown column %q", column)
 		}
-		if _, duplicate := seen[column]; duplicate {
+		sortKey := column
+		if sort.Path != "" {
+			sortKey = column + "\x00" + sort.Path
+		}
+		if _, duplicate := seen[sortKey]; duplicate {
 			continue
 		}
+		expression := quotePostgresIdentifier(column)
+		if sort.Path != "" {
+			path, err := database.ParseJSONPath(sort.Path)
+			if err != nil {
+				return "", nil, err
+			}
+			// Ordering jsonb keeps numbers numeric, which the text form
+			// would not.
+			args = append(args, path.Elements())
+			expression = fmt.Sprintf("(%s::jsonb #> $%d::text[])", expression, len(args))
+		}
 
 		direction := database.SortDirection(strings.ToLower(string(sort.Direction)))
 		if direction == "" {
 			direction = database.SortAscending
 		}
 		if direction != database.SortAscending && direction != database.SortDescending {
-			return "", fmt.Errorf("invalid sort direction %q for column %q", sort.Direction, column)
+			return "", nil, fmt.Errorf("invalid sort direction %q for column %q", sort.Direction, column)
 		}
 
 		nulls := database.NullsPosition(strings.ToLower(string(sort.Nulls)))
@@ -51,19 +69,19 @@ func buildPostgresOrderClause(
 			nulls = database.NullsLast
 		}
 		if nulls != database.NullsFirst && nulls != database.NullsLast {
-			return "", fmt.Errorf("invalid null position %q for column %q", sort.Nulls, column)
+			return "", nil, fmt.Errorf("invalid null position %q for column %q", sort.Nulls, column)
 		}
 
 		parts = append(
 			parts,
 			fmt.Sprintf(
 				"%s %s NULLS %s",
-				quotePostgresIdentifier(column),
+				expression,
 				strings.ToUpper(string(direction)),
 				strings.ToUpper(string(nulls)),
 			),
 		)
-		seen[column] = struct{}{}
+		seen[sortKey] = struct{}{}
 	}
 
 	for _, structure := range structures {
@@ -88,7 +106,7 @@ func buildPostgresOrderClause(
 		parts = append(parts, "tableoid ASC", "ctid ASC")
 	}
 
-	return " ORDER BY " + strings.Join(parts, ", "), nil
+	return " ORDER BY " + strings.Join(parts, ", "), args, nil
 }
 
 func hasPrimaryKey(structures database.Structures) bool {
@@ -13,7 +13,7 @@ func TestBuildPostgresOrderClauseUsesPrimaryKeyByDefault(t *testing.T) {
 		{Name: "name"},
 	}
 
-	clause, err := buildPostgresOrderClause(nil, structures)
+	clause, _, err := buildPostgresOrderClause(nil, structures, nil)
 	if err != nil {
 		t.Fatalf("build order clause: %v", err)
 	}
@@ -39,7 +39,7 @@ func TestBuildPostgresOrderClauseAddsStableTieBreaker(t *testing.T) {
 		},
 	}
 
-	clause, err := buildPostgresOrderClause(sorts, structures)
+	clause, _, err := buildPostgresOrderClause(sorts, structures, nil)
 	if err != nil {
 		t.Fatalf("build order clause: %v", err)
 	}
@@ -63,7 +63,7 @@ func TestBuildPostgresOrderClauseSupportsMultipleSorts(t *testing.T) {
 		{Column: "tenant_id", Direction: database.SortDescending, Nulls: database.NullsLast},
 	}
 
-	clause, err := buildPostgresOrderClause(sorts, structures)
+	clause, _, err := buildPostgresOrderClause(sorts, structures, nil)
 	if err != nil {
 		t.Fatalf("build order clause: %v", err)
 	}
@@ -80,7 +80,7 @@ func TestBuildPostgresOrderClauseFallsBackToCtid(t *testing.T) {
 	structures := database.Structures{{Name: "name"}}
 	sorts := []database.Sort{{Column: "name", Direction: database.SortAscending}}
 
-	clause, err := buildPostgresOrderClause(sorts, structures)
+	clause, _, err := buildPostgresOrderClause(sorts, structures, nil)
 	if err != nil {
 		t.Fatalf("build order clause: %v", err)
 	}
@@ -121,7 +121,7 @@ func TestBuildPostgresOrderClauseRejectsInvalidInput(t *testing.T) {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
-			if _, err := buildPostgresOrderClause([]database.Sort{testCase.sort}, structures); err == nil {
+			if _, _, err := buildPostgresOrderClause([]database.Sort{testCase.sort}, structures, nil); err == nil {
 				t.Fatal("expected validation error")
 			}
 		})
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"rollingthunder/pkg/database"
+)
+
+// yugabytePlanDetails adds the DocDB storage keys YugabyteDB reports to
+// the PostgreSQL ones. Storage filters are pushed down to the tablet
+// servers; older releases call them remote filters. The seek and next
+// estimates come from the cost-based optimizer.
+var yugabytePlanDetails = append(
+	append([]string(nil), postgresPlanDetails...),
+	"Storage Filter",
+	"Storage Index Filter",
+	"Remote Filter",
+	"Remote Index Filter",
+	"Partial Aggregate",
+	"Estimated Seeks",
+	"Estimated Nexts",
+	"Estimated Docdb Result Width",
+)
+
+func parseYugabytePlan(raw string) (database.ExplainPlan, error) {
+	return parseJSONPlan(raw, "YugabyteDB", "yb-0", yugabytePlanDetails)
+}
+
+// YSQL keeps pg_stat_activity for the node it is connected to, but locks
+// are held in DocDB, so pg_blocking_pids does not see distributed waits.
+// When active session history is enabled, the latest sample for each
+// backend shows what the session is waiting on across the cluster.
+const yugabyteActivityTemplate = `
+	SELECT
+		activity.pid::bigint AS session_id,
+		COALESCE(activity.usename, '') AS session_user,
+		COALESCE(activity.datname, '') AS database_name,
+		COALESCE(activity.client_addr::text, 'local') AS client_address,
+		COALESCE(activity.application_name, '') AS application_name,
+		COALESCE(activity.state, '') AS session_state,
+		LEFT(COALESCE(activity.query, ''), 4000) AS query_text,
+		COALESCE(
+			NULLIF(CONCAT_WS(':', sample.wait_event_component,
+				sample.wait_event_class, sample.wait_event), ''),
+			TRIM(BOTH ' ' FROM CONCAT_WS(':', activity.wait_event_type, activity.wait_event))
+		) AS wait_event,
+		'' AS blocked_by,
+		GREATEST(
+			0,
+			(EXTRACT(EPOCH FROM (
+				clock_timestamp() - COALESCE(activity.query_start, activity.backend_start)
+			)) * 1000)::bigint
+		) AS duration_ms,
+		activity.xact_start AS transaction_started,
+		activity.query_start AS query_started,
+		activity.backend_start AS started_at,
+		(activity.pid = pg_backend_pid() OR activity.application_name = $1) AS is_current
+	FROM pg_catalog.pg_stat_activity activity
+	%s
+	WHERE activity.backend_type = 'client backend'
+	ORDER BY
+		CASE WHEN activity.state = 'active' THEN 0 ELSE 1 END,
+		activity.query_start NULLS LAST,
+		activity.pid`
+
+const yugabyteSessionHistoryJoin = `LEFT JOIN LATERAL (
+		SELECT history.wait_event_component, history.wait_event_class, history.wait_event
+		FROM pg_catalog.yb_active_session_history history
+		WHERE history.pid = activity.pid
+			AND activity.state = 'active'
+			AND history.sample_time > clock_timestamp() - interval '5 seconds'
+		ORDER BY history.sample_time DESC
+		LIMIT 1
+	) sample ON true`
+
+const yugabyteNoSessionHistoryJoin = `CROSS JOIN (
+		SELECT NULL::text AS wait_event_component,
+			NULL::text AS wait_event_class,
+			NULL::text AS wait_event
+	) sample`
+
+// yugabyteActivityQuery leaves out the session history join when the
+// connected node does not expose yb_active_session_history.
+func (p *Postgres) yugabyteActivityQuery(ctx context.Context) (string, error) {
+	var available bool
+	if err := p.conn.GetContext(
+		ctx,
+		&available,
+		"SELECT to_regclass('pg_catalog.yb_active_session_history') IS NOT NULL",
+	); err != nil {
+		return "", err
+	}
+	join := yugabyteNoSessionHistoryJoin
+	if available {
+		join = yugabyteSessionHistoryJoin
+	}
+	return fmt.Sprintf(yugabyteActivityTemplate, join), nil
+}
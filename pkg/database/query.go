@@ -1,12 +1,19 @@
 package database
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 const DefaultQueryResultLimit = 1000
 
+// QueryOptions tunes one statement. ServerOutput asks engines with
+// session-scoped procedural output, such as Oracle's DBMS_OUTPUT, to
+// collect it into the result's messages.
 type QueryOptions struct {
-	MaxRows int
-	Args    []interface{}
+	MaxRows      int
+	Args         []interface{}
+	ServerOutput bool
 }
 
 type QueryVariable struct {
@@ -22,24 +29,143 @@ type QueryRequest struct {
 	TransactionID           string          `json:"transactionId,omitempty"`
 	AllowUnfilteredMutation bool            `json:"allowUnfilteredMutation"`
 	Variables               []QueryVariable `json:"variables,omitempty"`
+	ServerOutput            bool            `json:"serverOutput,omitempty"`
 }
 
+// QueryColumn describes one result column. Type is the engine's own type
+// name. Nullability, size, and the source table and column are filled only
+// when the driver reports them; PostgreSQL reports sources only through
+// QuerySourceDriver.
+type QueryColumn struct {
+	Name         string `json:"name"`
+	Type         string `json:"type,omitempty"`
+	Nullable     *bool  `json:"nullable,omitempty"`
+	Length       *int64 `json:"length,omitempty"`
+	Precision    *int64 `json:"precision,omitempty"`
+	Scale        *int64 `json:"scale,omitempty"`
+	SourceSchema string `json:"sourceSchema,omitempty"`
+	SourceTable  string `json:"sourceTable,omitempty"`
+	SourceColumn string `json:"sourceColumn,omitempty"`
+}
+
+// QueryResultSet holds one statement's rows as value arrays in column
+// order, so columns that share a name each keep their value.
+// RowsAffected is set when the engine reports a count for a data-changing
+// statement, and CommandTag carries the engine's own completion tag, such
+// as PostgreSQL's "UPDATE 42". Only PostgreSQL reports a tag; MySQL,
+// SQLite, SQL Server, Oracle, ClickHouse, and DuckDB leave it empty, and
+// plugin drivers pass on whatever their engine gives them. WallClockMS is
+// measured by the app around the driver call, so it includes network round
+// trips and row transfer, not just the server's execution time. None of
+// the bundled drivers report server-side timing, so it is not available.
 type QueryResultSet struct {
-	Index     int                      `json:"index"`
-	Statement string                   `json:"statement"`
-	Columns   []string                 `json:"columns"`
-	Rows      []map[string]interface{} `json:"rows"`
-	Truncated bool                     `json:"truncated"`
-	RowLimit  int                      `json:"rowLimit"`
+	Index        int             `json:"index"`
+	Statement    string          `json:"statement"`
+	Columns      []QueryColumn   `json:"columns"`
+	Rows         [][]interface{} `json:"rows"`
+	Truncated    bool            `json:"truncated"`
+	RowLimit     int             `json:"rowLimit"`
+	RowsAffected *int64          `json:"rowsAffected,omitempty"`
+	CommandTag   string          `json:"commandTag,omitempty"`
+	WallClockMS  int64           `json:"wallClockMs"`
+	Messages     []QueryMessage  `json:"messages,omitempty"`
 }
 
 type QueryResult struct {
-	Rows           []map[string]interface{} `json:"rows"`
-	Truncated      bool                     `json:"truncated"`
-	RowLimit       int                      `json:"rowLimit"`
-	Columns        []string                 `json:"columns"`
-	ResultSets     []QueryResultSet         `json:"resultSets"`
-	StatementCount int                      `json:"statementCount"`
+	Rows           [][]interface{}  `json:"rows"`
+	Truncated      bool             `json:"truncated"`
+	RowLimit       int              `json:"rowLimit"`
+	Columns        []QueryColumn    `json:"columns"`
+	RowsAffected   *int64           `json:"rowsAffected,omitempty"`
+	CommandTag     string           `json:"commandTag,omitempty"`
+	Messages       []QueryMessage   `json:"messages,omitempty"`
+	ResultSets     []QueryResultSet `json:"resultSets"`
+	StatementCount int              `json:"statementCount"`
+}
+
+type QueryMessageSeverity string
+
+const (
+	QueryMessageDebug   QueryMessageSeverity = "debug"
+	QueryMessageInfo    QueryMessageSeverity = "info"
+	QueryMessageNotice  QueryMessageSeverity = "notice"
+	QueryMessageWarning QueryMessageSeverity = "warning"
+	QueryMessageError   QueryMessageSeverity = "error"
+)
+
+// QueryMessage is an informational message the server sent while running
+// a statement: a PostgreSQL NOTICE, a SQL Server PRINT, an Oracle
+// DBMS_OUTPUT line, or a MySQL warning. Sequence starts at 1 and follows
+// the order the messages arrived in. Code is the engine's own message
+// number or SQLSTATE when it sends one.
+type QueryMessage struct {
+	Sequence int                  `json:"sequence"`
+	Severity QueryMessageSeverity `json:"severity"`
+	Code     string               `json:"code,omitempty"`
+	Text     string               `json:"text"`
+}
+
+// QueryMessageLog collects the messages of one statement. Some drivers
+// deliver them from callbacks, so it is safe for concurrent use.
+type QueryMessageLog struct {
+	mu       sync.Mutex
+	messages []QueryMessage
+}
+
+func (log *QueryMessageLog) Add(severity QueryMessageSeverity, code string, text string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.messages = append(log.messages, QueryMessage{
+		Sequence: len(log.messages) + 1,
+		Severity: severity,
+		Code:     code,
+		Text:     text,
+	})
+}
+
+// Messages returns the collected messages in arrival order, or nil when
+// the server sent none.
+func (log *QueryMessageLog) Messages() []QueryMessage {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if len(log.messages) == 0 {
+		return nil
+	}
+	return append([]QueryMessage(nil), log.messages...)
+}
+
+// QueryColumnNames returns the column names in result order.
+func QueryColumnNames(columns []QueryColumn) []string {
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.Name
+	}
+	return names
+}
+
+// RowMaps returns the rows keyed by column name, for callers that read
+// known, uniquely named columns. When names repeat, the last column wins.
+func (set QueryResultSet) RowMaps() []map[string]interface{} {
+	return queryRowMaps(set.Columns, set.Rows)
+}
+
+// RowMaps returns the first result set's rows keyed by column name.
+func (result QueryResult) RowMaps() []map[string]interface{} {
+	return queryRowMaps(result.Columns, result.Rows)
+}
+
+func queryRowMaps(columns []QueryColumn, values [][]interface{}) []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(values))
+	for rowIndex, row := range values {
+		mapped := make(map[string]interface{}, len(columns))
+		for index, column := range columns {
+			if index < len(row) {
+				mapped[column.Name] = row[index]
+			}
+		}
+		rows[rowIndex] = mapped
+	}
+	return rows
 }
 
 type Transaction interface {
@@ -55,3 +181,10 @@ type Transaction interface {
 type TransactionalDriver interface {
 	BeginTransaction(ctx context.Context) (Transaction, error)
 }
+
+// QuerySourceDriver resolves the source table and column of each result
+// column on demand, for engines that only learn them by describing the
+// statement separately.
+type QuerySourceDriver interface {
+	DescribeQuerySources(ctx context.Context, query string) ([]QueryColumn, error)
+}
@@ -0,0 +1,39 @@
+package database
+
+import "context"
+
+// QueryCursor is an open read-only result that is paged through on demand
+// instead of being cut off at the query row limit. It keeps a dedicated
+// connection busy until Close, so callers must always close it.
+type QueryCursor interface {
+	Columns() []QueryColumn
+	// Fetch returns up to maxRows further rows. Done reports that the
+	// result is exhausted; a cursor that is done only needs closing.
+	Fetch(ctx context.Context, maxRows int) (QueryCursorBatch, error)
+	Close() error
+}
+
+type QueryCursorBatch struct {
+	Rows [][]interface{}
+	Done bool
+}
+
+// CursorDriver opens cursors for single read-only statements. The context
+// bounds the whole life of the cursor, not just the call that opens it.
+type CursorDriver interface {
+	OpenQueryCursor(
+		ctx context.Context,
+		query string,
+		options QueryOptions,
+	) (QueryCursor, error)
+}
+
+// QueryCursorPage is one page read from an open cursor. Offset is the
+// position of the page's first row in the whole result.
+type QueryCursorPage struct {
+	CursorID string          `json:"cursorId"`
+	Columns  []QueryColumn   `json:"columns"`
+	Rows     [][]interface{} `json:"rows"`
+	Offset   int64           `json:"offset"`
+	Done     bool            `json:"done"`
+}
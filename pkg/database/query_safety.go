@@ -84,6 +84,7 @@ func FindWriteStatement(query string) string {
 type sqlSafetyToken struct {
 	word  string
 	depth int
+	start int
 }
 
 func AnalyzeQuerySafety(query string) QuerySafetyAnalysis {
@@ -158,6 +159,71 @@ func isMutationCommandToken(
 	return false
 }
 
+// ddlKeywords start statements that change schema or privileges. Engines
+// without transactional DDL commit them implicitly, so a rollback cannot
+// undo them. Routine calls and procedural blocks are included because they
+// can run such statements where the caller cannot see them.
+var ddlKeywords = map[string]struct{}{
+	"ALTER":    {},
+	"ANALYZE":  {},
+	"BEGIN":    {},
+	"CALL":     {},
+	"COMMENT":  {},
+	"CREATE":   {},
+	"DECLARE":  {},
+	"DO":       {},
+	"DROP":     {},
+	"EXEC":     {},
+	"EXECUTE":  {},
+	"GRANT":    {},
+	"RENAME":   {},
+	"REVOKE":   {},
+	"TRUNCATE": {},
+}
+
+// FindDDLStatement returns the keyword that starts the first top-level
+// statement a rollback may not undo on an engine without transactional
+// DDL.
+func FindDDLStatement(query string) string {
+	statementStart := true
+	for _, token := range tokenizeSQLForSafety(query) {
+		if token.depth != 0 {
+			continue
+		}
+		if token.word == ";" {
+			statementStart = true
+			continue
+		}
+		if !statementStart {
+			continue
+		}
+		statementStart = false
+		if _, ok := ddlKeywords[token.word]; ok {
+			return token.word
+		}
+	}
+	return ""
+}
+
+// SQLWord is a word of a statement that sits outside parentheses, quotes,
+// and comments. Offset is its byte position in the statement.
+type SQLWord struct {
+	Word   string
+	Offset int
+}
+
+// TopLevelSQLWords returns the upper-cased top-level words of statement,
+// so a driver can place an engine-specific clause between them.
+func TopLevelSQLWords(statement string) []SQLWord {
+	words := make([]SQLWord, 0)
+	for _, token := range tokenizeSQLForSafety(statement) {
+		if token.depth == 0 && token.word != ";" {
+			words = append(words, SQLWord{Word: token.word, Offset: token.start})
+		}
+	}
+	return words
+}
+
 func FindTransactionControl(query string) string {
 	tokens := tokenizeSQLForSafety(query)
 	if len(tokens) > 0 && tokens[0].depth == 0 {
@@ -447,6 +513,73 @@ func skipDDLOracleAlternativeQuote(
 	return len(value), false
 }
 
+// rowCountKeywords start statements that answer with an affected-row count.
+var rowCountKeywords = map[string]struct{}{
+	"DELETE":  {},
+	"INSERT":  {},
+	"MERGE":   {},
+	"REPLACE": {},
+	"UPDATE":  {},
+	"UPSERT":  {},
+}
+
+// ReportsAffectedRows reports whether statement is a single data-changing
+// statement, possibly behind leading CTEs, that returns no rows, so a
+// driver can execute it and read the affected-row count instead of opening
+// a result set. RETURNING and OUTPUT clauses, including a column that
+// happens to be named OUTPUT, keep the statement on the row-returning path.
+func ReportsAffectedRows(statement string) bool {
+	tokens := tokenizeSQLForSafety(statement)
+	command := rowCountCommandIndex(tokens)
+	if command < 0 {
+		return false
+	}
+	for index := command + 1; index < len(tokens); index++ {
+		switch token := tokens[index]; {
+		case token.word == "RETURNING", token.word == "OUTPUT":
+			return false
+		case token.word == ";" && index+1 < len(tokens):
+			return false
+		}
+	}
+	return true
+}
+
+// rowCountCommandIndex returns the index of the keyword that starts the
+// statement's data-changing command, looking past leading CTEs, or -1 when
+// the statement is not one. After a CTE body the next top-level word is
+// either another CTE's name, which AS or a column list follows, or the
+// command itself.
+func rowCountCommandIndex(tokens []sqlSafetyToken) int {
+	if len(tokens) == 0 {
+		return -1
+	}
+	if _, ok := rowCountKeywords[tokens[0].word]; ok {
+		return 0
+	}
+	if tokens[0].word != "WITH" {
+		return -1
+	}
+	for index := 1; index < len(tokens); index++ {
+		token := tokens[index]
+		if token.depth != 0 || tokens[index-1].depth == 0 {
+			continue
+		}
+		if token.word == ";" {
+			return -1
+		}
+		if _, ok := rowCountKeywords[token.word]; !ok {
+			continue
+		}
+		if index+1 < len(tokens) &&
+			(tokens[index+1].word == "AS" || tokens[index+1].depth != 0) {
+			continue
+		}
+		return index
+	}
+	return -1
+}
+
 // LeadingSQLKeywords returns the first top-level words in a statement. It is
 // intended for validating reviewed DDL templates, not for parsing arbitrary
 // SQL grammar.
@@ -547,7 +680,7 @@ func tokenizeSQLForSafety(query string) []sqlSafetyToken {
 			}
 			index++
 		case current == ';':
-			tokens = append(tokens, sqlSafetyToken{word: ";", depth: depth})
+			tokens = append(tokens, sqlSafetyToken{word: ";", depth: depth, start: index})
 			index++
 		case isSQLWordStart(rune(current)):
 			start := index
@@ -559,6 +692,7 @@ func tokenizeSQLForSafety(query string) []sqlSafetyToken {
 			tokens = append(tokens, sqlSafetyToken{
 				word:  strings.ToUpper(query[start:index]),
 				depth: depth,
+				start: start,
 			})
 		default:
 			index++
@@ -166,6 +166,36 @@ func TestFindTransactionControl(t *testing.T) {
 	}
 }
 
+func TestReportsAffectedRows(t *testing.T) {
+	for _, query := range []string{
+		"UPDATE events SET name = 'storm' WHERE id = 1",
+		"  -- archive\n delete from events where id = 2;",
+		"INSERT INTO events (id, name) SELECT id, name FROM staging",
+		"INSERT INTO notes (body) VALUES ('RETURNING soon')",
+		"MERGE INTO events USING staging ON events.id = staging.id WHEN MATCHED THEN DELETE",
+		"WITH gone AS (SELECT 1) DELETE FROM events",
+		"WITH RECURSIVE ids (id) AS (SELECT 1), moved AS (DELETE FROM staging RETURNING *) " +
+			"UPDATE events SET name = 'x' WHERE id IN (SELECT id FROM ids)",
+	} {
+		if !ReportsAffectedRows(query) {
+			t.Fatalf("ReportsAffectedRows(%q) = false, want true", query)
+		}
+	}
+	for _, query := range []string{
+		"SELECT * FROM events",
+		"CREATE TABLE events (id int)",
+		"WITH gone AS (DELETE FROM events RETURNING id) SELECT * FROM gone",
+		"WITH gone AS (SELECT 1) DELETE FROM events RETURNING id",
+		"DELETE FROM events WHERE id = 1 RETURNING id",
+		"UPDATE events SET name = 'x' OUTPUT inserted.id WHERE id = 1",
+		"UPDATE events SET name = 'x'; SELECT 1",
+	} {
+		if ReportsAffectedRows(query) {
+			t.Fatalf("ReportsAffectedRows(%q) = true, want false", query)
+		}
+	}
+}
+
 func TestCountSQLStatementsIgnoresRoutineBodySemicolons(t *testing.T) {
 	query := `
 		CREATE FUNCTION public.answer() RETURNS integer AS $body$
@@ -296,3 +326,33 @@ func TestValidateDDLFragmentRejectsDefinitionEscapes(t *testing.T) {
 		}
 	}
 }
+
+func TestFindDDLStatementOnlyChecksStatementStarts(t *testing.T) {
+	for query, want := range map[string]string{
+		"UPDATE users SET name = 'create' WHERE id = 1":          "",
+		"SELECT 1; ALTER TABLE users ADD email TEXT":             "ALTER",
+		"DELETE FROM users WHERE id IN (SELECT 1); DROP TABLE t": "DROP",
+		"/* CREATE */ INSERT INTO users (id) VALUES (1)":         "",
+		"EXEC dbo.rebuild_indexes":                               "EXEC",
+		"BEGIN DBMS_OUTPUT.PUT_LINE('x'); END;":                  "BEGIN",
+	} {
+		if got := FindDDLStatement(query); got != want {
+			t.Fatalf("FindDDLStatement(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestReturningAllQueryAppendsClauseOnItsOwnLine(t *testing.T) {
+	query, ok := ReturningAllQuery("DELETE FROM users WHERE id = 1 -- cleanup")
+	if !ok || query.Query != "DELETE FROM users WHERE id = 1 -- cleanup\nRETURNING *" ||
+		!query.Before || query.After {
+		t.Fatalf("ReturningAllQuery(DELETE) = %+v, %t", query, ok)
+	}
+	query, ok = ReturningAllQuery("UPDATE users SET name = 'a' WHERE id = 1;")
+	if !ok || query.Query != "UPDATE users SET name = 'a' WHERE id = 1\nRETURNING *" || !query.After {
+		t.Fatalf("ReturningAllQuery(UPDATE) = %+v, %t", query, ok)
+	}
+	if _, ok := ReturningAllQuery("INSERT INTO users (id) VALUES (1) RETURNING id"); ok {
+		t.Fatal("ReturningAllQuery() rewrote a statement that already returns rows")
+	}
+}
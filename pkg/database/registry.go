@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExecutableLookup resolves a client tool on PATH. It matches exec.LookPath
+// so tests can substitute a fixture.
+type ExecutableLookup func(string) (string, error)
+
+// DriverFactory opens a driver for a profile that already passed
+// Config.ValidateSafety. Each engine maps the shared Config to its own options.
+type DriverFactory func(context.Context, Config) (Driver, error)
+
+// DriverProbe describes a registered engine without opening a connection, so
+// the connection form and backup workflow can be offered before connecting.
+type DriverProbe struct {
+	Driver       string             `json:"driver"`
+	Capabilities Capabilities       `json:"capabilities"`
+	Backup       BackupCapabilities `json:"backup"`
+}
+
+// CapabilitiesProbe reports the static capabilities of an engine. Backup
+// support may depend on client tools, which are resolved with lookPath.
+type CapabilitiesProbe func(lookPath ExecutableLookup) DriverProbe
+
+type registeredDriver struct {
+	factory DriverFactory
+	probe   CapabilitiesProbe
+}
+
+var (
+	driverRegistryMu sync.RWMutex
+	driverRegistry   = make(map[string]registeredDriver)
+)
+
+// RegisterDriver makes an engine available under name. Engine packages call
+// it from init, so importing a driver package is enough to enable it. Like
+// database/sql.Register, it panics on an empty name, a nil factory or probe,
+// or a duplicate registration.
+func RegisterDriver(name string, factory DriverFactory, probe CapabilitiesProbe) {
+	name = normalizeDriverName(name)
+	if name == "" {
+		panic("database: RegisterDriver called with an empty driver name")
+	}
+	if factory == nil || probe == nil {
+		panic("database: RegisterDriver " + name + " requires a factory and capabilities probe")
+	}
+	driverRegistryMu.Lock()
+	defer driverRegistryMu.Unlock()
+	if _, exists := driverRegistry[name]; exists {
+		panic("database: RegisterDriver called twice for driver " + name)
+	}
+	driverRegistry[name] = registeredDriver{factory: factory, probe: probe}
+}
+
+func normalizeDriverName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func registeredDriverFor(name string) (registeredDriver, bool) {
+	driverRegistryMu.RLock()
+	defer driverRegistryMu.RUnlock()
+	driver, ok := driverRegistry[normalizeDriverName(name)]
+	return driver, ok
+}
+
+// IsRegisteredDriver reports whether name can be opened by OpenDriver.
+func IsRegisteredDriver(name string) bool {
+	_, ok := registeredDriverFor(name)
+	return ok
+}
+
+// RegisteredDrivers returns the registered driver names in sorted order.
+func RegisteredDrivers() []string {
+	driverRegistryMu.RLock()
+	defer driverRegistryMu.RUnlock()
+	names := make([]string, 0, len(driverRegistry))
+	for name := range driverRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenDriver creates a driver through its registered factory.
+func OpenDriver(ctx context.Context, name string, config Config) (Driver, error) {
+	registered, ok := registeredDriverFor(name)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type: %s", name)
+	}
+	driver, err := registered.factory(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil {
+		return nil, fmt.Errorf("database driver %s returned no driver", name)
+	}
+	return driver, nil
+}
+
+// ProbeDriver runs the registered capabilities probe for name. The returned
+// capabilities are validated so a malformed in-house driver is rejected before
+// the UI can expose a workflow it does not implement.
+func ProbeDriver(name string, lookPath ExecutableLookup) (DriverProbe, error) {
+	registered, ok := registeredDriverFor(name)
+	if !ok {
+		return DriverProbe{}, fmt.Errorf("unsupported database type: %s", name)
+	}
+	probe := registered.probe(lookPath)
+	probe.Driver = normalizeDriverName(name)
+	if err := probe.Capabilities.Validate(); err != nil {
+		return DriverProbe{}, fmt.Errorf("database driver %s: %w", name, err)
+	}
+	if probe.Backup.Engine == "" {
+		probe.Backup.Engine = probe.Capabilities.Engine
+	}
+	return probe, nil
+}
+
+// LookupExecutable returns the full path and base name of the first tool in
+// names that lookPath can resolve.
+func LookupExecutable(lookPath ExecutableLookup, names ...string) (string, string) {
+	if lookPath == nil {
+		return "", ""
+	}
+	for _, name := range names {
+		path, err := lookPath(name)
+		if err == nil && strings.TrimSpace(path) != "" {
+			return path, filepath.Base(path)
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Engine packages import this package, so its tests cannot import them.
+// TestMain registers stand-ins for the built-in names that config tests use.
+func TestMain(m *testing.M) {
+	for _, name := range []string{
+		DriverPostgres,
+		DriverMySQL,
+		DriverMariaDB,
+		DriverSQLite,
+		DriverOracle,
+		DriverSQLServer,
+	} {
+		RegisterDriver(name, unavailableDriverFactory, stubDriverProbe(name))
+	}
+	os.Exit(m.Run())
+}
+
+func unavailableDriverFactory(context.Context, Config) (Driver, error) {
+	return nil, errors.New("test driver cannot connect")
+}
+
+func stubDriverProbe(engine string) CapabilitiesProbe {
+	return func(lookPath ExecutableLookup) DriverProbe {
+		_, tool := LookupExecutable(lookPath, engine+"-dump")
+		return DriverProbe{
+			Capabilities: Capabilities{
+				Engine:      engine,
+				DisplayName: strings.ToUpper(engine),
+				Dialect: Dialect{
+					Name:             engine,
+					IdentifierOpen:   `"`,
+					IdentifierClose:  `"`,
+					PlaceholderStyle: PlaceholderQuestion,
+					PaginationStyle:  PaginationLimitOffset,
+				},
+			},
+			Backup: BackupCapabilities{
+				Available:  tool != "",
+				Format:     BackupFormat(engine),
+				BackupTool: tool,
+			},
+		}
+	}
+}
+
+func TestRegisterDriverRejectsDuplicatesAndUnknownDrivers(t *testing.T) {
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("duplicate registration must panic")
+			}
+		}()
+		RegisterDriver(" Postgres ", unavailableDriverFactory, stubDriverProbe("postgres"))
+	}()
+
+	if !IsRegisteredDriver("SQLITE") || IsRegisteredDriver("in-house") {
+		t.Fatal("driver names must be matched case-insensitively")
+	}
+	if _, err := OpenDriver(context.Background(), "in-house", Config{}); err == nil ||
+		!strings.Contains(err.Error(), "unsupported database type") {
+		t.Fatalf("OpenDriver(unknown) error = %v", err)
+	}
+	if err := (Config{Driver: "in-house"}).ValidateSafety(); err == nil {
+		t.Fatal("unregistered drivers must fail profile validation")
+	}
+}
+
+func TestRegisteredDriverIsUsableEverywhere(t *testing.T) {
+	RegisterDriver("in-house-registry-test", unavailableDriverFactory, stubDriverProbe("in-house"))
+
+	if err := (Config{Driver: "in-house-registry-test"}).ValidateSafety(); err != nil {
+		t.Fatalf("registered driver validation = %v", err)
+	}
+	found := false
+	for _, name := range RegisteredDrivers() {
+		found = found || name == "in-house-registry-test"
+	}
+	if !found {
+		t.Fatalf("RegisteredDrivers() = %v", RegisteredDrivers())
+	}
+
+	lookPath := func(name string) (string, error) {
+		if name == "in-house-dump" {
+			return "/opt/tools/in-house-dump", nil
+		}
+		return "", errors.New("not found")
+	}
+	probe, err := ProbeDriver("in-house-registry-test", lookPath)
+	if err != nil {
+		t.Fatalf("ProbeDriver() error = %v", err)
+	}
+	if probe.Driver != "in-house-registry-test" ||
+		!probe.Backup.Available ||
+		probe.Backup.BackupTool != "in-house-dump" ||
+		probe.Backup.Engine != "in-house" {
+		t.Fatalf("probe = %+v", probe)
+	}
+}
+
+func TestProbeDriverRejectsInvalidCapabilities(t *testing.T) {
+	RegisterDriver(
+		"broken-registry-test",
+		unavailableDriverFactory,
+		func(ExecutableLookup) DriverProbe { return DriverProbe{} },
+	)
+	if _, err := ProbeDriver("broken-registry-test", nil); err == nil {
+		t.Fatal("a probe without an engine must be rejected")
+	}
+}
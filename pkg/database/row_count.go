@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"math"
+)
+
+// Row count sources. An exact count comes from COUNT(*); statistics and
+// planner counts are estimates that can be stale or far off.
+const (
+	RowCountExact      = "exact"
+	RowCountStatistics = "statistics"
+	RowCountPlanner    = "planner"
+)
+
+// RowCount is the number of rows in a table, or in the rows its filters
+// match.
+type RowCount struct {
+	Rows      int64  `json:"rows"`
+	Estimated bool   `json:"estimated"`
+	Source    string `json:"source"`
+}
+
+// RowEstimateDriver reads a row count from catalog statistics without
+// scanning the table. Filtered tables are estimated by the query planner.
+// ok is false when the engine has no estimate to offer, for example
+// because the table was never analyzed.
+type RowEstimateDriver interface {
+	EstimateCollectionData(ctx context.Context, table Table) (count RowCount, ok bool, err error)
+}
+
+// ContextCountDriver runs the exact count under ctx, so an abandoned
+// count also stops on the server.
+type ContextCountDriver interface {
+	CountCollectionDataContext(ctx context.Context, table Table) (int, error)
+}
+
+// RowCountJob reports an exact count running in the background. Rows is
+// set once Status is done.
+type RowCountJob struct {
+	JobID       string `json:"jobId"`
+	Status      string `json:"status"`
+	Rows        int64  `json:"rows"`
+	Error       string `json:"error,omitempty"`
+	ElapsedMS   int64  `json:"elapsedMs"`
+	Cancellable bool   `json:"cancellable"`
+}
+
+// PlannerRowCount returns the rows the root of plan is expected to produce.
+func PlannerRowCount(plan ExplainPlan) (RowCount, bool) {
+	if len(plan.Roots) == 0 {
+		return RowCount{}, false
+	}
+	return RowCount{
+		Rows:      int64(math.Round(plan.Roots[0].EstimatedRows)),
+		Estimated: true,
+		Source:    RowCountPlanner,
+	}, true
+}
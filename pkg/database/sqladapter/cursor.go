@@ -0,0 +1,101 @@
+package sqladapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"rollingthunder/pkg/database"
+)
+
+// RowNormalizer rewrites a scanned row in place into the values the
+// driver's regular query path would have returned.
+type RowNormalizer func(row []interface{})
+
+// Cursor holds one *sql.Rows open on a connection taken out of the pool,
+// so paging through it never competes with other queries for the result.
+type Cursor struct {
+	conn      *sql.Conn
+	rows      *sql.Rows
+	columns   []database.QueryColumn
+	normalize RowNormalizer
+	done      bool
+}
+
+// OpenCursor runs query on a dedicated connection and keeps its rows open.
+// The rows are closed by database/sql when ctx ends, so ctx must outlive
+// every Fetch.
+func OpenCursor(
+	ctx context.Context,
+	db *sql.DB,
+	query string,
+	options database.QueryOptions,
+	normalize RowNormalizer,
+) (*Cursor, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, query, options.Args...)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		_ = rows.Close()
+		_ = conn.Close()
+		return nil, fmt.Errorf("read query result columns: %w", err)
+	}
+	return &Cursor{
+		conn:      conn,
+		rows:      rows,
+		columns:   QueryColumns(columnTypes),
+		normalize: normalize,
+	}, nil
+}
+
+func (cursor *Cursor) Columns() []database.QueryColumn {
+	return cursor.columns
+}
+
+func (cursor *Cursor) Fetch(
+	ctx context.Context,
+	maxRows int,
+) (database.QueryCursorBatch, error) {
+	batch := database.QueryCursorBatch{Rows: make([][]interface{}, 0)}
+	if maxRows <= 0 {
+		return batch, fmt.Errorf("cursor fetch requires a positive row count")
+	}
+	if cursor.done {
+		batch.Done = true
+		return batch, nil
+	}
+	for len(batch.Rows) < maxRows {
+		if err := ctx.Err(); err != nil {
+			return database.QueryCursorBatch{}, err
+		}
+		if !cursor.rows.Next() {
+			if err := cursor.rows.Err(); err != nil {
+				return database.QueryCursorBatch{}, fmt.Errorf("read query result rows: %w", err)
+			}
+			cursor.done = true
+			batch.Done = true
+			break
+		}
+		row, err := ScanRow(cursor.rows, len(cursor.columns))
+		if err != nil {
+			return database.QueryCursorBatch{}, fmt.Errorf("scan query result row: %w", err)
+		}
+		if cursor.normalize != nil {
+			cursor.normalize(row)
+		}
+		batch.Rows = append(batch.Rows, row)
+	}
+	return batch, nil
+}
+
+func (cursor *Cursor) Close() error {
+	return errors.Join(cursor.rows.Close(), cursor.conn.Close())
+}
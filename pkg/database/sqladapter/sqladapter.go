@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 
 	"rollingthunder/pkg/database"
 )
@@ -14,29 +15,96 @@ type QueryRunner interface {
 		string,
 		...interface{},
 	) (*sql.Rows, error)
+	StatementRunner
 }
 
-func scanCurrentRow(
-	rows *sql.Rows,
-	columns []string,
-) (map[string]interface{}, error) {
-	values := make([]interface{}, len(columns))
-	destinations := make([]interface{}, len(columns))
+type StatementRunner interface {
+	ExecContext(
+		context.Context,
+		string,
+		...interface{},
+	) (sql.Result, error)
+}
+
+// ExecuteRowCount runs a statement that returns no rows and reports the
+// affected-row count when the driver provides one.
+func ExecuteRowCount(
+	ctx context.Context,
+	runner StatementRunner,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	execResult, err := runner.ExecContext(ctx, query, options.Args...)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	set := database.QueryResultSet{
+		Columns:  make([]database.QueryColumn, 0),
+		Rows:     make([][]interface{}, 0),
+		RowLimit: options.MaxRows,
+	}
+	if affected, countErr := execResult.RowsAffected(); countErr == nil && affected >= 0 {
+		set.RowsAffected = &affected
+	}
+	return database.QueryResult{
+		Rows:           set.Rows,
+		Columns:        set.Columns,
+		RowLimit:       options.MaxRows,
+		RowsAffected:   set.RowsAffected,
+		ResultSets:     []database.QueryResultSet{set},
+		StatementCount: 1,
+	}, nil
+}
+
+// maxReportedLength separates real column lengths from the sentinels
+// drivers return for unbounded text and binary types.
+const maxReportedLength = math.MaxInt32
+
+// maxDecimalPrecision bounds DecimalSize; larger values are modifiers the
+// driver decoded from an unconstrained numeric column.
+const maxDecimalPrecision = 1000
+
+// QueryColumns converts database/sql column metadata into result columns.
+func QueryColumns(types []*sql.ColumnType) []database.QueryColumn {
+	columns := make([]database.QueryColumn, len(types))
+	for index, columnType := range types {
+		column := database.QueryColumn{
+			Name: columnType.Name(),
+			Type: columnType.DatabaseTypeName(),
+		}
+		if nullable, ok := columnType.Nullable(); ok {
+			column.Nullable = &nullable
+		}
+		if length, ok := columnType.Length(); ok && length > 0 && length < maxReportedLength {
+			column.Length = &length
+		}
+		if precision, scale, ok := columnType.DecimalSize(); ok &&
+			precision > 0 && precision <= maxDecimalPrecision &&
+			scale >= 0 && scale <= precision {
+			column.Precision = &precision
+			column.Scale = &scale
+		}
+		columns[index] = column
+	}
+	return columns
+}
+
+// ScanRow reads the current row as values in column order.
+func ScanRow(rows *sql.Rows, width int) ([]interface{}, error) {
+	values := make([]interface{}, width)
+	destinations := make([]interface{}, width)
 	for index := range values {
 		destinations[index] = &values[index]
 	}
 	if err := rows.Scan(destinations...); err != nil {
 		return nil, err
 	}
-	result := make(map[string]interface{}, len(columns))
-	for index, column := range columns {
-		value := values[index]
+	for index, value := range values {
 		if raw, ok := value.(sql.RawBytes); ok {
-			value = append([]byte(nil), raw...)
+			values[index] = append([]byte(nil), raw...)
 		}
-		result[column] = value
 	}
-	return result, nil
+	return values, nil
 }
 
 func ExecuteQuery(
@@ -45,6 +113,9 @@ func ExecuteQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
+	if database.ReportsAffectedRows(query) {
+		return ExecuteRowCount(ctx, runner, query, options)
+	}
 	rows, err := runner.QueryContext(ctx, query, options.Args...)
 	if err != nil {
 		return database.QueryResult{}, err
@@ -52,13 +123,13 @@ func ExecuteQuery(
 	defer rows.Close()
 
 	result := database.QueryResult{
-		Rows:       make([]map[string]interface{}, 0),
+		Rows:       make([][]interface{}, 0),
 		ResultSets: make([]database.QueryResultSet, 0),
 		RowLimit:   options.MaxRows,
 	}
 	resultIndex := 0
 	for {
-		columns, columnErr := rows.Columns()
+		columnTypes, columnErr := rows.ColumnTypes()
 		if columnErr != nil {
 			return database.QueryResult{}, fmt.Errorf(
 				"read query result columns: %w",
@@ -67,8 +138,8 @@ func ExecuteQuery(
 		}
 		current := database.QueryResultSet{
 			Index:    resultIndex,
-			Columns:  append([]string(nil), columns...),
-			Rows:     make([]map[string]interface{}, 0),
+			Columns:  QueryColumns(columnTypes),
+			Rows:     make([][]interface{}, 0),
 			RowLimit: options.MaxRows,
 		}
 		for rows.Next() {
@@ -76,7 +147,7 @@ func ExecuteQuery(
 				current.Truncated = true
 				break
 			}
-			row, scanErr := scanCurrentRow(rows, columns)
+			row, scanErr := ScanRow(rows, len(columnTypes))
 			if scanErr != nil {
 				return database.QueryResult{}, fmt.Errorf(
 					"scan query result row: %w",
@@ -93,8 +164,8 @@ func ExecuteQuery(
 		}
 		result.ResultSets = append(result.ResultSets, current)
 		if resultIndex == 0 {
-			result.Columns = append([]string(nil), current.Columns...)
-			result.Rows = append(result.Rows, current.Rows...)
+			result.Columns = current.Columns
+			result.Rows = current.Rows
 			result.Truncated = current.Truncated
 		}
 		resultIndex++
@@ -12,6 +12,10 @@ import (
 	"rollingthunder/pkg/database"
 )
 
+// Dialect holds the engine syntax the shared table helpers need.
+// JSONPathExpression reads the scalar at a path inside a column, as a number
+// when its bool is set and as text otherwise; its callback binds an argument
+// and returns the placeholder. Leaving it nil rejects JSON paths.
 type Dialect struct {
 	QuoteIdentifier            func(string) string
 	QuoteQualified             func(string, string) string
@@ -22,6 +26,8 @@ type Dialect struct {
 	SupportsNullOrdering       bool
 	TextExpression             func(string) string
 	NullOrderExpression        func(string, database.NullsPosition) string
+	RegexExpression            func(string, string) string
+	JSONPathExpression         func(string, database.JSONPath, bool, func(interface{}) string) string
 	InsertExport               *InsertExportDialect
 	IdentityInsertStatements   func(database.Table) (string, string)
 }
@@ -47,91 +53,150 @@ func resolveColumn(
 	return resolved, nil
 }
 
+// BuildFilterClause renders a filter tree as a WHERE clause. Every value is
+// bound through the dialect's placeholders.
 func BuildFilterClause(
-	filters []database.Filter,
+	tree database.FilterGroup,
 	structures database.Structures,
 	dialect Dialect,
 ) (string, []interface{}, error) {
-	if len(filters) == 0 {
-		return "", nil, nil
-	}
 	columns := structureNameSet(structures)
-	clauses := make([]string, 0, len(filters))
-	args := make([]interface{}, 0, len(filters))
-	for _, filter := range filters {
-		if err := filter.Validate(); err != nil {
-			return "", nil, err
-		}
+	args := make([]interface{}, 0)
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		return dialect.Placeholder(len(args))
+	}
+	clause, err := tree.Render(func(filter database.Filter) (string, error) {
 		column, err := resolveColumn(columns, filter.Column)
 		if err != nil {
-			return "", nil, err
+			return "", err
 		}
 		quoted := dialect.QuoteIdentifier(column)
+		text := quoted
+		if filter.Path != "" {
+			quoted, err = jsonPathExpression(dialect, quoted, filter.Path, filter.NumericJSONFilter(), bind)
+			if err != nil {
+				return "", err
+			}
+			text = quoted
+		} else if dialect.TextExpression != nil {
+			text = dialect.TextExpression(quoted)
+		}
 		switch filter.Operator {
 		case database.FilterIsNull:
-			clauses = append(clauses, quoted+" IS NULL")
+			return quoted + " IS NULL", nil
 		case database.FilterIsNotNull:
-			clauses = append(clauses, quoted+" IS NOT NULL")
-		default:
-			operator := map[database.FilterOperator]string{
-				database.FilterEqual:        "=",
-				database.FilterNotEqual:     "<>",
-				database.FilterGreaterThan:  ">",
-				database.FilterLessThan:     "<",
-				database.FilterGreaterEqual: ">=",
-				database.FilterLessEqual:    "<=",
-				database.FilterContains:     "LIKE",
-			}[filter.Operator]
-			if operator == "" {
-				return "", nil, fmt.Errorf(
-					"unsupported filter operator %q",
-					filter.Operator,
-				)
+			return quoted + " IS NOT NULL", nil
+		case database.FilterContains,
+			database.FilterStartsWith,
+			database.FilterEndsWith:
+			return text + " LIKE " + bind(filter.LikePattern()), nil
+		case database.FilterNotContains:
+			return text + " NOT LIKE " + bind(filter.LikePattern()), nil
+		case database.FilterILike:
+			return "LOWER(" + text + ") LIKE LOWER(" +
+				bind(filter.LikePattern()) + ")", nil
+		case database.FilterRegex:
+			if dialect.RegexExpression == nil {
+				return "", errors.New("this engine does not support regex filters")
 			}
-			value := filter.Value
-			if filter.Operator == database.FilterContains {
-				value = "%" + fmt.Sprint(value) + "%"
-				if dialect.TextExpression != nil {
-					quoted = dialect.TextExpression(quoted)
-				}
+			return dialect.RegexExpression(text, bind(fmt.Sprint(filter.Value))), nil
+		case database.FilterIn, database.FilterNotIn:
+			values, _ := filter.Values()
+			placeholders := make([]string, len(values))
+			for index, value := range values {
+				placeholders[index] = bind(value)
 			}
-			args = append(args, value)
-			clauses = append(
-				clauses,
-				quoted+" "+operator+" "+dialect.Placeholder(len(args)),
+			operator := " IN ("
+			if filter.Operator == database.FilterNotIn {
+				operator = " NOT IN ("
+			}
+			return quoted + operator + strings.Join(placeholders, ", ") + ")", nil
+		case database.FilterBetween:
+			values, _ := filter.Values()
+			return quoted + " BETWEEN " + bind(values[0]) +
+				" AND " + bind(values[1]), nil
+		}
+		operator := map[database.FilterOperator]string{
+			database.FilterEqual:        "=",
+			database.FilterNotEqual:     "<>",
+			database.FilterGreaterThan:  ">",
+			database.FilterLessThan:     "<",
+			database.FilterGreaterEqual: ">=",
+			database.FilterLessEqual:    "<=",
+		}[filter.Operator]
+		if operator == "" {
+			return "", fmt.Errorf(
+				"unsupported filter operator %q",
+				filter.Operator,
 			)
 		}
+		return quoted + " " + operator + " " + bind(filter.Value), nil
+	})
+	if err != nil || clause == "" {
+		return "", nil, err
+	}
+	return " WHERE " + clause, args, nil
+}
+
+func jsonPathExpression(
+	dialect Dialect,
+	column string,
+	rawPath string,
+	numeric bool,
+	bind func(interface{}) string,
+) (string, error) {
+	if dialect.JSONPathExpression == nil {
+		return "", errors.New("this engine does not support JSON path filters or sorts")
 	}
-	return " WHERE " + strings.Join(clauses, " AND "), args, nil
+	path, err := database.ParseJSONPath(rawPath)
+	if err != nil {
+		return "", err
+	}
+	return dialect.JSONPathExpression(column, path, numeric, bind), nil
 }
 
+// BuildOrderClause renders sorts as an ORDER BY clause. JSON paths are
+// bound after args, which holds the arguments already bound by the
+// statement, and the extended list is returned.
 func BuildOrderClause(
 	sorts []database.Sort,
 	structures database.Structures,
 	dialect Dialect,
-) (string, error) {
+	args []interface{},
+) (string, []interface{}, error) {
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		return dialect.Placeholder(len(args))
+	}
 	columns := structureNameSet(structures)
 	parts := make([]string, 0, len(sorts))
 	for _, item := range sorts {
 		column, err := resolveColumn(columns, item.Column)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		direction := strings.ToUpper(string(item.Direction))
 		if direction == "" {
 			direction = "ASC"
 		}
 		if direction != "ASC" && direction != "DESC" {
-			return "", fmt.Errorf("unsupported sort direction %q", item.Direction)
+			return "", nil, fmt.Errorf("unsupported sort direction %q", item.Direction)
 		}
 		quoted := dialect.QuoteIdentifier(column)
+		if item.Path != "" {
+			quoted, err = jsonPathExpression(dialect, quoted, item.Path, false, bind)
+			if err != nil {
+				return "", nil, err
+			}
+		}
 		part := quoted + " " + direction
 		if item.Nulls != "" {
 			switch item.Nulls {
 			case database.NullsFirst:
 			case database.NullsLast:
 			default:
-				return "", fmt.Errorf("unsupported NULL position %q", item.Nulls)
+				return "", nil, fmt.Errorf("unsupported NULL position %q", item.Nulls)
 			}
 			if dialect.SupportsNullOrdering {
 				if item.Nulls == database.NullsFirst {
@@ -145,15 +210,15 @@ func BuildOrderClause(
 					dialect.NullOrderExpression(quoted, item.Nulls),
 				)
 			} else {
-				return "", fmt.Errorf("this engine does not support explicit NULL ordering")
+				return "", nil, fmt.Errorf("this engine does not support explicit NULL ordering")
 			}
 		}
 		parts = append(parts, part)
 	}
 	if len(parts) == 0 {
-		return "", nil
+		return "", args, nil
 	}
-	return " ORDER BY " + strings.Join(parts, ", "), nil
+	return " ORDER BY " + strings.Join(parts, ", "), args, nil
 }
 
 func BuildTableSelect(
@@ -166,11 +231,11 @@ func BuildTableSelect(
 	if strings.TrimSpace(table.Name) == "" {
 		return "", nil, errors.New("table name is required")
 	}
-	filters, args, err := BuildFilterClause(table.Filters, structures, dialect)
+	filters, args, err := BuildFilterClause(table.FilterTree(), structures, dialect)
 	if err != nil {
 		return "", nil, err
 	}
-	order, err := BuildOrderClause(table.Sorts, structures, dialect)
+	order, args, err := BuildOrderClause(table.Sorts, structures, dialect, args)
 	if err != nil {
 		return "", nil, err
 	}
@@ -213,6 +278,7 @@ func BuildTableSelect(
 }
 
 func CountTable(
+	ctx context.Context,
 	db *sql.DB,
 	table database.Table,
 	structures database.Structures,
@@ -230,7 +296,7 @@ func CountTable(
 		return 0, err
 	}
 	var count int
-	err = db.QueryRow(query, args...).Scan(&count)
+	err = db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
 
@@ -254,7 +320,7 @@ func GetTableData(
 	result, err := ExecuteQuery(ctx, db, query, database.QueryOptions{
 		Args: args,
 	})
-	return result.Rows, err
+	return result.RowMaps(), err
 }
 
 func mutableDataKeys(data map[string]interface{}) []string {
@@ -838,10 +904,11 @@ func ExportTable(
 			*dialect.InsertExport,
 		)
 	}
-	return database.WriteExportStreamContext(
+	return database.WriteTypedExportStreamContext(
 		ctx,
 		writer,
 		stream,
+		structures,
 		request.Options,
 	)
 }
@@ -210,3 +210,111 @@ func TestSelectedRowsRequiresASelection(t *testing.T) {
 		t.Fatal("empty selected-row export was accepted")
 	}
 }
+
+func TestBuildFilterClauseRendersNestedGroups(t *testing.T) {
+	tree := database.Table{
+		Filters: []database.Filter{
+			{Column: "Status", Operator: database.FilterNotIn, Value: []string{"void", "draft"}},
+		},
+		Where: &database.FilterGroup{
+			Logic: database.FilterOr,
+			Filters: []database.Filter{
+				{Column: "total", Operator: database.FilterBetween, Value: []interface{}{10, 20}},
+			},
+			Groups: []database.FilterGroup{{
+				Filters: []database.Filter{
+					{Column: "name", Operator: database.FilterStartsWith, Value: "Ada"},
+					{Column: "name", Operator: database.FilterILike, Value: "%LOVELACE"},
+				},
+			}},
+		},
+	}.FilterTree()
+
+	clause, args, err := BuildFilterClause(
+		tree,
+		database.Structures{{Name: "status"}, {Name: "total"}, {Name: "name"}},
+		testDialect(),
+	)
+	if err != nil {
+		t.Fatalf("BuildFilterClause() error = %v", err)
+	}
+	const want = " WHERE [status] NOT IN (@p1, @p2) AND " +
+		"([total] BETWEEN @p3 AND @p4 OR " +
+		"([name] LIKE @p5 AND LOWER([name]) LIKE LOWER(@p6)))"
+	if clause != want {
+		t.Fatalf("clause = %q, want %q", clause, want)
+	}
+	if fmt.Sprint(args) != "[void draft 10 20 Ada% %LOVELACE]" {
+		t.Fatalf("args = %v", args)
+	}
+}
+
+func TestBuildFilterClauseRejectsRegexWithoutDialectSupport(t *testing.T) {
+	_, _, err := BuildFilterClause(
+		database.FilterGroup{Filters: []database.Filter{
+			{Column: "name", Operator: database.FilterRegex, Value: "^a"},
+		}},
+		database.Structures{{Name: "name"}},
+		testDialect(),
+	)
+	if err == nil || !strings.Contains(err.Error(), "regex") {
+		t.Fatalf("BuildFilterClause() error = %v, want regex error", err)
+	}
+}
+
+func TestBuildFilterAndOrderClausesBindJSONPaths(t *testing.T) {
+	dialect := testDialect()
+	dialect.JSONPathExpression = func(
+		column string,
+		path database.JSONPath,
+		numeric bool,
+		bind func(interface{}) string,
+	) string {
+		value := "JSON_VALUE(" + column + ", " + bind(path.String()) + ")"
+		if numeric {
+			return "TRY_CAST(" + value + " AS float)"
+		}
+		return value
+	}
+	structures := database.Structures{{Name: "doc"}}
+
+	clause, args, err := BuildFilterClause(
+		database.FilterGroup{Filters: []database.Filter{
+			{Column: "doc", Path: "total", Operator: database.FilterGreaterEqual, Value: 10},
+		}},
+		structures,
+		dialect,
+	)
+	if err != nil {
+		t.Fatalf("BuildFilterClause() error = %v", err)
+	}
+	if clause != " WHERE TRY_CAST(JSON_VALUE([doc], @p1) AS float) >= @p2" ||
+		fmt.Sprint(args) != `[$."total" 10]` {
+		t.Fatalf("clause = %q, args = %v", clause, args)
+	}
+
+	order, args, err := BuildOrderClause(
+		[]database.Sort{{Column: "doc", Path: "customer.name"}},
+		structures,
+		dialect,
+		args,
+	)
+	if err != nil {
+		t.Fatalf("BuildOrderClause() error = %v", err)
+	}
+	if !strings.Contains(order, "JSON_VALUE([doc], @p3)") ||
+		fmt.Sprint(args) != `[$."total" 10 $."customer"."name"]` {
+		t.Fatalf("order = %q, args = %v", order, args)
+	}
+
+	_, _, err = BuildFilterClause(
+		database.FilterGroup{Filters: []database.Filter{
+			{Column: "doc", Path: "total", Operator: database.FilterEqual, Value: "x"},
+		}},
+		structures,
+		testDialect(),
+	)
+	if err == nil || !strings.Contains(err.Error(), "JSON") {
+		t.Fatalf("BuildFilterClause() error = %v, want JSON path error", err)
+	}
+}
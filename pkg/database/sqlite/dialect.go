@@ -46,6 +46,11 @@ func (s *SQLite) Capabilities() database.Capabilities {
 		ManageSecurity:      false,
 		ActivityMonitor:     false,
 		SSHConnections:      false,
+		FilterGroups:        true,
+		JSONPaths:           true,
+		KeysetPagination:    true,
+		RowEstimates:        true,
+		ReturningRows:       true,
 	}
 }
 
@@ -88,69 +93,110 @@ type sqliteQuery struct {
 }
 
 func buildSQLiteFilterClause(
-	filters []database.Filter,
+	tree database.FilterGroup,
 	structures database.Structures,
 ) (string, []interface{}, error) {
-	if len(filters) == 0 {
-		return "", nil, nil
-	}
 	available := make(map[string]struct{}, len(structures))
 	for _, structure := range structures {
 		available[structure.Name] = struct{}{}
 	}
-	conditions := make([]string, 0, len(filters))
-	args := make([]interface{}, 0, len(filters))
-	for _, filter := range filters {
-		if err := filter.Validate(); err != nil {
-			return "", nil, err
-		}
+	args := make([]interface{}, 0)
+	clause, err := tree.Render(func(filter database.Filter) (string, error) {
 		column := strings.TrimSpace(filter.Column)
 		if _, exists := available[column]; !exists {
-			return "", nil, fmt.Errorf(
+			return "", fmt.Errorf(
 				"cannot filter by unknown column %q",
 				column,
 			)
 		}
 		quoted := quoteSQLiteIdentifier(column)
+		if filter.Path != "" {
+			path, err := database.ParseJSONPath(filter.Path)
+			if err != nil {
+				return "", err
+			}
+			quoted, args = sqliteJSONPathValue(quoted, path, filter.NumericJSONFilter(), args)
+		}
 		switch filter.Operator {
 		case database.FilterEqual:
-			conditions = append(conditions, quoted+" = ?")
 			args = append(args, filter.Value)
+			return quoted + " = ?", nil
 		case database.FilterNotEqual:
-			conditions = append(conditions, quoted+" <> ?")
 			args = append(args, filter.Value)
+			return quoted + " <> ?", nil
 		case database.FilterGreaterThan:
-			conditions = append(conditions, quoted+" > ?")
 			args = append(args, filter.Value)
+			return quoted + " > ?", nil
 		case database.FilterLessThan:
-			conditions = append(conditions, quoted+" < ?")
 			args = append(args, filter.Value)
+			return quoted + " < ?", nil
 		case database.FilterGreaterEqual:
-			conditions = append(conditions, quoted+" >= ?")
 			args = append(args, filter.Value)
+			return quoted + " >= ?", nil
 		case database.FilterLessEqual:
-			conditions = append(conditions, quoted+" <= ?")
 			args = append(args, filter.Value)
-		case database.FilterContains:
-			conditions = append(
-				conditions,
-				"CAST("+quoted+" AS TEXT) LIKE ? COLLATE NOCASE",
-			)
-			args = append(args, "%"+fmt.Sprint(filter.Value)+"%")
+			return quoted + " <= ?", nil
+		case database.FilterContains,
+			database.FilterStartsWith,
+			database.FilterEndsWith,
+			database.FilterILike:
+			args = append(args, filter.LikePattern())
+			return "CAST(" + quoted + " AS TEXT) LIKE ? COLLATE NOCASE", nil
+		case database.FilterNotContains:
+			args = append(args, filter.LikePattern())
+			return "CAST(" + quoted + " AS TEXT) NOT LIKE ? COLLATE NOCASE", nil
+		case database.FilterRegex:
+			return "", fmt.Errorf("SQLite does not support regex filters")
+		case database.FilterIn, database.FilterNotIn:
+			values, _ := filter.Values()
+			args = append(args, values...)
+			operator := " IN ("
+			if filter.Operator == database.FilterNotIn {
+				operator = " NOT IN ("
+			}
+			return quoted + operator +
+				strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", nil
+		case database.FilterBetween:
+			values, _ := filter.Values()
+			args = append(args, values...)
+			return quoted + " BETWEEN ? AND ?", nil
 		case database.FilterIsNull:
-			conditions = append(conditions, quoted+" IS NULL")
+			return quoted + " IS NULL", nil
 		case database.FilterIsNotNull:
-			conditions = append(conditions, quoted+" IS NOT NULL")
+			return quoted + " IS NOT NULL", nil
 		}
+		return "", fmt.Errorf("unsupported filter operator %q", filter.Operator)
+	})
+	if err != nil || clause == "" {
+		return "", nil, err
+	}
+	return " WHERE " + clause, args, nil
+}
+
+// sqliteJSONPathValue reads the value at path as text, or as a number when
+// numeric is set. json_extract hands back native values, so the text form
+// casts it and the numeric form checks json_type first. Each ? the
+// expression holds is appended to args.
+func sqliteJSONPathValue(
+	column string,
+	path database.JSONPath,
+	numeric bool,
+	args []interface{},
+) (string, []interface{}) {
+	if !numeric {
+		return "CAST(json_extract(" + column + ", ?) AS TEXT)", append(args, path.String())
 	}
-	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+	expression := "(CASE WHEN json_type(" + column + ", ?) IN ('integer', 'real') THEN " +
+		"json_extract(" + column + ", ?) END)"
+	return expression, append(args, path.String(), path.String())
 }
 
 func buildSQLiteOrderClause(
 	sorts []database.Sort,
 	structures database.Structures,
 	hasRowID bool,
-) (string, error) {
+	args []interface{},
+) (string, []interface{}, error) {
 	available := make(map[string]database.Structure, len(structures))
 	for _, structure := range structures {
 		available[structure.Name] = structure
@@ -160,12 +206,16 @@ func buildSQLiteOrderClause(
 	for _, sort := range sorts {
 		column := strings.TrimSpace(sort.Column)
 		if column == "" {
-			return "", fmt.Errorf("sort column cannot be empty")
+			return "", nil, fmt.Errorf("sort column cannot be empty")
 		}
 		if _, exists := available[column]; !exists {
-			return "", fmt.Errorf("cannot sort by unknown column %q", column)
+			return "", nil, fmt.Errorf("cannot sort by unknown column %q", column)
 		}
-		if _, duplicate := seen[column]; duplicate {
+		sortKey := column
+		if sort.Path != "" {
+			sortKey = column + "\x00" + sort.Path
+		}
+		if _, duplicate := seen[sortKey]; duplicate {
 			continue
 		}
 		direction := database.SortDirection(strings.ToLower(string(sort.Direction)))
@@ -174,7 +224,7 @@ func buildSQLiteOrderClause(
 		}
 		if direction != database.SortAscending &&
 			direction != database.SortDescending {
-			return "", fmt.Errorf(
+			return "", nil, fmt.Errorf(
 				"invalid sort direction %q for column %q",
 				sort.Direction,
 				column,
@@ -185,19 +235,29 @@ func buildSQLiteOrderClause(
 			nulls = database.NullsLast
 		}
 		if nulls != database.NullsFirst && nulls != database.NullsLast {
-			return "", fmt.Errorf(
+			return "", nil, fmt.Errorf(
 				"invalid null position %q for column %q",
 				sort.Nulls,
 				column,
 			)
 		}
+		expression := quoteSQLiteIdentifier(column)
+		if sort.Path != "" {
+			path, err := database.ParseJSONPath(sort.Path)
+			if err != nil {
+				return "", nil, err
+			}
+			// json_extract returns native values, so numbers sort as numbers.
+			expression = "json_extract(" + expression + ", ?)"
+			args = append(args, path.String())
+		}
 		parts = append(parts, fmt.Sprintf(
 			"%s %s NULLS %s",
-			quoteSQLiteIdentifier(column),
+			expression,
 			strings.ToUpper(string(direction)),
 			strings.ToUpper(string(nulls)),
 		))
-		seen[column] = struct{}{}
+		seen[sortKey] = struct{}{}
 	}
 	for _, structure := range structures {
 		if !structure.IsPrimary {
@@ -223,7 +283,7 @@ func buildSQLiteOrderClause(
 		}
 	}
 	if len(parts) == 0 {
-		return "", fmt.Errorf("table has no columns available for stable ordering")
+		return "", nil, fmt.Errorf("table has no columns available for stable ordering")
 	}
-	return " ORDER BY " + strings.Join(parts, ", "), nil
+	return " ORDER BY " + strings.Join(parts, ", "), args, nil
 }
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverSQLite, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewSQLite(ctx, Config{Db: cfg.Db}), nil
+}
+
+// probe needs no client tools: SQLite backups use the online backup API of
+// the embedded engine.
+func probe(database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*SQLite)(nil).Capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available:     true,
+			Engine:        capabilities.Engine,
+			Format:        database.BackupFormatSQLiteNative,
+			Extension:     ".sqlite3",
+			BackupTool:    "Built in",
+			RestoreTool:   "Built in",
+			RestoreReady:  true,
+			BuiltIn:       true,
+			SupportsScope: false,
+		},
+	}
+}
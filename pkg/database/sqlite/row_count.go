@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// EstimateCollectionData reads the row counts ANALYZE stores in
+// sqlite_stat1. Databases that were never analyzed have no such table, and
+// SQLite's planner does not expose row estimates for filtered reads.
+func (s *SQLite) EstimateCollectionData(
+	ctx context.Context,
+	table database.Table,
+) (database.RowCount, bool, error) {
+	if err := s.ensureConnected(); err != nil {
+		return database.RowCount{}, false, err
+	}
+	if table.Filtered() {
+		return database.RowCount{}, false, nil
+	}
+	schema := quoteSQLiteIdentifier(normalizeSQLiteSchema(table.Schema))
+	var analyzed int
+	if err := s.conn.GetContext(
+		ctx,
+		&analyzed,
+		fmt.Sprintf(
+			"SELECT COUNT(*) FROM %s.sqlite_schema WHERE type = 'table' AND name = 'sqlite_stat1'",
+			schema,
+		),
+	); err != nil {
+		return database.RowCount{}, false, err
+	}
+	if analyzed == 0 {
+		return database.RowCount{}, false, nil
+	}
+
+	// Each index has a row whose stat starts with the number of rows it
+	// covers; a table without indexes has a single row with a NULL idx.
+	// Partial indexes cover fewer rows, so the largest count wins.
+	var stats []string
+	if err := s.conn.SelectContext(
+		ctx,
+		&stats,
+		fmt.Sprintf("SELECT stat FROM %s.sqlite_stat1 WHERE tbl = ?", schema),
+		table.Name,
+	); err != nil {
+		return database.RowCount{}, false, err
+	}
+	rows, ok := int64(0), false
+	for _, stat := range stats {
+		fields := strings.Fields(stat)
+		if len(fields) == 0 {
+			continue
+		}
+		count, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		rows, ok = max(rows, count), true
+	}
+	if !ok {
+		return database.RowCount{}, false, nil
+	}
+	return database.RowCount{
+		Rows:      rows,
+		Estimated: true,
+		Source:    database.RowCountStatistics,
+	}, true, nil
+}
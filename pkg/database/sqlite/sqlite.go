@@ -15,6 +15,7 @@ import (
 
 	"rollingthunder/pkg/application"
 	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -334,11 +335,18 @@ func (s *SQLite) GetDatabaseInfo() (database.Info, error) {
 }
 
 func (s *SQLite) CountCollectionData(table database.Table) (int, error) {
+	return s.CountCollectionDataContext(context.Background(), table)
+}
+
+func (s *SQLite) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
 	structures, err := s.GetCollectionStructures(table)
 	if err != nil {
 		return 0, err
 	}
-	filter, args, err := buildSQLiteFilterClause(table.Filters, structures)
+	filter, args, err := buildSQLiteFilterClause(table.FilterTree(), structures)
 	if err != nil {
 		return 0, err
 	}
@@ -349,7 +357,7 @@ func (s *SQLite) CountCollectionData(table database.Table) (int, error) {
 		) +
 		filter
 	var count int
-	if err := s.conn.Get(&count, query, args...); err != nil {
+	if err := s.conn.GetContext(ctx, &count, query, args...); err != nil {
 		return 0, err
 	}
 	return count, nil
@@ -381,11 +389,11 @@ func (s *SQLite) GetCollectionData(
 	if err != nil {
 		return nil, nil, err
 	}
-	filter, args, err := buildSQLiteFilterClause(table.Filters, structures)
+	filter, args, err := buildSQLiteFilterClause(table.FilterTree(), structures)
 	if err != nil {
 		return nil, nil, err
 	}
-	order, err := buildSQLiteOrderClause(table.Sorts, structures, hasRowID)
+	order, args, err := buildSQLiteOrderClause(table.Sorts, structures, hasRowID, args)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -578,23 +586,19 @@ func requireOneSQLiteRow(result sql.Result, action string) error {
 	return nil
 }
 
-type sqliteMappedRows interface {
+type sqliteQueryRows interface {
 	Next() bool
-	Columns() ([]string, error)
-	MapScan(map[string]interface{}) error
+	SliceScan() ([]interface{}, error)
 	Err() error
 }
 
 func collectSQLiteQueryResults(
-	rows sqliteMappedRows,
+	rows sqliteQueryRows,
+	columns []database.QueryColumn,
 	maxRows int,
 ) (database.QueryResult, error) {
-	columns, err := rows.Columns()
-	if err != nil {
-		return database.QueryResult{}, fmt.Errorf("read SQLite query columns: %w", err)
-	}
 	result := database.QueryResult{
-		Rows:     make([]map[string]interface{}, 0),
+		Rows:     make([][]interface{}, 0),
 		RowLimit: maxRows,
 		Columns:  columns,
 	}
@@ -603,8 +607,8 @@ func collectSQLiteQueryResults(
 			result.Truncated = true
 			break
 		}
-		row := make(map[string]interface{})
-		if err := rows.MapScan(row); err != nil {
+		row, err := rows.SliceScan()
+		if err != nil {
 			return database.QueryResult{}, fmt.Errorf("scan SQLite query row: %w", err)
 		}
 		result.Rows = append(result.Rows, row)
@@ -617,6 +621,7 @@ func collectSQLiteQueryResults(
 
 type sqliteQueryRunner interface {
 	QueryxContext(context.Context, string, ...interface{}) (*sqlx.Rows, error)
+	sqladapter.StatementRunner
 }
 
 func executeSQLiteQuery(
@@ -625,12 +630,19 @@ func executeSQLiteQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
+	if database.ReportsAffectedRows(query) {
+		return sqladapter.ExecuteRowCount(ctx, runner, query, options)
+	}
 	rows, err := runner.QueryxContext(ctx, query, options.Args...)
 	if err != nil {
 		return database.QueryResult{}, err
 	}
 	defer rows.Close()
-	return collectSQLiteQueryResults(rows, options.MaxRows)
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return database.QueryResult{}, fmt.Errorf("read SQLite query columns: %w", err)
+	}
+	return collectSQLiteQueryResults(rows, sqladapter.QueryColumns(columnTypes), options.MaxRows)
 }
 
 func (s *SQLite) ExecuteQuery(
@@ -671,6 +683,17 @@ func (s *SQLite) BeginTransaction(
 	return &sqliteTransaction{tx: transaction}, nil
 }
 
+// ReturningQuery reads the changed rows with RETURNING, which SQLite has
+// supported since 3.35. changes() counts them when only a sample is read.
+func (s *SQLite) ReturningQuery(statement string) (database.ReturningQuery, bool) {
+	query, ok := database.ReturningAllQuery(statement)
+	if !ok {
+		return database.ReturningQuery{}, false
+	}
+	query.CountQuery = "SELECT changes()"
+	return query, true
+}
+
 type sqliteExportRows struct {
 	rows *sqlx.Rows
 }
@@ -773,11 +796,11 @@ func buildSQLiteExportQuery(
 	if table.Offset < 0 {
 		return sqliteQuery{}, fmt.Errorf("table offset cannot be negative")
 	}
-	filter, args, err := buildSQLiteFilterClause(table.Filters, structures)
+	filter, args, err := buildSQLiteFilterClause(table.FilterTree(), structures)
 	if err != nil {
 		return sqliteQuery{}, err
 	}
-	order, err := buildSQLiteOrderClause(table.Sorts, structures, hasRowID)
+	order, args, err := buildSQLiteOrderClause(table.Sorts, structures, hasRowID, args)
 	if err != nil {
 		return sqliteQuery{}, err
 	}
@@ -873,7 +896,13 @@ func (s *SQLite) ExportTable(
 			request.Options.SQL,
 		)
 	}
-	return database.WriteExportStreamContext(ctx, writer, stream, request.Options)
+	return database.WriteTypedExportStreamContext(
+		ctx,
+		writer,
+		stream,
+		structures,
+		request.Options,
+	)
 }
 
 type sqliteInsertSink struct {
@@ -1182,3 +1211,4 @@ func (s *SQLite) GetDataTypes() []database.DataType {
 var _ database.Driver = (*SQLite)(nil)
 var _ database.DriverWithSchema = (*SQLite)(nil)
 var _ database.TransactionalDriver = (*SQLite)(nil)
+var _ database.ReturningDriver = (*SQLite)(nil)
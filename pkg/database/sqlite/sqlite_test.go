@@ -63,6 +63,7 @@ func TestSQLiteLiveConformance(t *testing.T) {
 		Schema:      "main",
 		IntegerType: "INTEGER",
 		TextType:    "TEXT",
+		JSONType:    "TEXT",
 	})
 }
 
@@ -119,6 +120,58 @@ func TestSQLiteAttachedDatabaseAndGeneratedMetadata(t *testing.T) {
 	}
 }
 
+func TestSQLiteEstimatesRowsFromAnalyzeStatistics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "estimates.sqlite3")
+	driver := NewSQLite(context.Background(), Config{Db: path})
+	if err := driver.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { _ = driver.Close() })
+
+	if _, err := driver.conn.Exec(`
+		CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT NOT NULL);
+		CREATE INDEX events_kind ON events(kind);
+		CREATE TABLE audit (note TEXT)`); err != nil {
+		t.Fatalf("CREATE TABLE error = %v", err)
+	}
+	for index := 0; index < 40; index++ {
+		if _, err := driver.conn.Exec(
+			"INSERT INTO events (kind) VALUES (?)",
+			[]string{"click", "view"}[index%2],
+		); err != nil {
+			t.Fatalf("INSERT error = %v", err)
+		}
+	}
+	if _, err := driver.conn.Exec(
+		"INSERT INTO audit (note) VALUES ('a'), ('b'), ('c')",
+	); err != nil {
+		t.Fatalf("INSERT audit error = %v", err)
+	}
+
+	ctx := context.Background()
+	if _, ok, err := driver.EstimateCollectionData(ctx, table("main", "events")); err != nil || ok {
+		t.Fatalf("EstimateCollectionData() before ANALYZE = ok:%t err:%v, want no estimate", ok, err)
+	}
+	if _, err := driver.conn.Exec("ANALYZE"); err != nil {
+		t.Fatalf("ANALYZE error = %v", err)
+	}
+	for name, want := range map[string]int64{"events": 40, "audit": 3} {
+		count, ok, err := driver.EstimateCollectionData(ctx, table("main", name))
+		if err != nil || !ok {
+			t.Fatalf("EstimateCollectionData(%s) = ok:%t err:%v", name, ok, err)
+		}
+		if count.Rows != want || !count.Estimated || count.Source != database.RowCountStatistics {
+			t.Fatalf("EstimateCollectionData(%s) = %+v, want %d estimated rows", name, count, want)
+		}
+	}
+
+	filtered := table("main", "events")
+	filtered.Filters = []database.Filter{{Column: "kind", Operator: database.FilterEqual, Value: "view"}}
+	if _, ok, err := driver.EstimateCollectionData(ctx, filtered); err != nil || ok {
+		t.Fatalf("EstimateCollectionData(filtered) = ok:%t err:%v, want no estimate", ok, err)
+	}
+}
+
 func TestSQLiteViewDetailIncludesStructureAndDependency(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "views.sqlite3")
 	driver := NewSQLite(context.Background(), Config{Db: path})
@@ -0,0 +1,64 @@
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"rollingthunder/pkg/database"
+
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// BulkLoad sends rows through the TDS bulk copy protocol inside the import
+// transaction. Constraints are checked, triggers fire, and explicit NULLs are
+// kept instead of column defaults, as an INSERT would.
+func (transaction *sqlServerTransaction) BulkLoad(
+	ctx context.Context,
+	table database.Table,
+	columns []database.BulkLoadColumn,
+	rows database.BulkLoadRows,
+) (int64, error) {
+	target := quoteIdentifier(table.Name)
+	if table.Schema != "" {
+		target = quoteIdentifier(table.Schema) + "." + target
+	}
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.Name
+	}
+	statement, err := transaction.tx.PrepareContext(ctx, mssql.CopyIn(
+		target,
+		mssql.BulkOptions{
+			CheckConstraints: true,
+			FireTriggers:     true,
+			KeepNulls:        true,
+		},
+		names...,
+	))
+	if err != nil {
+		return 0, fmt.Errorf("prepare bulk copy into %s: %w", target, err)
+	}
+	defer statement.Close()
+
+	for {
+		values, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if _, err := statement.ExecContext(ctx, values...); err != nil {
+			return 0, fmt.Errorf("bulk copy into %s: %w", target, err)
+		}
+	}
+	result, err := statement.ExecContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("bulk copy into %s: %w", target, err)
+	}
+	return result.RowsAffected()
+}
+
+var _ database.BulkLoader = (*sqlServerTransaction)(nil)
@@ -50,6 +50,12 @@ func (s *SQLServer) Capabilities() database.Capabilities {
 		ManageSecurity:      true,
 		ActivityMonitor:     true,
 		SSHConnections:      true,
+		QueryCursors:        true,
+		FilterGroups:        true,
+		JSONPaths:           true,
+		KeysetPagination:    true,
+		RowEstimates:        true,
+		ReturningRows:       true,
 	}
 }
 
@@ -101,6 +107,18 @@ func (s *SQLServer) adapterDialect() sqladapter.Dialect {
 		TextExpression: func(identifier string) string {
 			return "CONVERT(nvarchar(max), " + identifier + ")"
 		},
+		JSONPathExpression: func(
+			column string,
+			path database.JSONPath,
+			numeric bool,
+			bind func(interface{}) string,
+		) string {
+			value := "JSON_VALUE(" + column + ", " + bind(path.String()) + ")"
+			if numeric {
+				return "TRY_CAST(" + value + " AS float)"
+			}
+			return value
+		},
 		NullOrderExpression: func(
 			identifier string,
 			position database.NullsPosition,
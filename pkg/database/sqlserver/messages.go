@@ -0,0 +1,151 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+
+	"github.com/golang-sql/sqlexp"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// executeSQLServerQuery runs a batch through go-mssqldb's message queue.
+// PRINT and informational RAISERROR output only reach the client that
+// way, interleaved with result sets, row counts, and errors in the order
+// the server sent them.
+func executeSQLServerQuery(
+	ctx context.Context,
+	runner sqladapter.QueryRunner,
+	query string,
+	options database.QueryOptions,
+) (database.QueryResult, error) {
+	queue := &sqlexp.ReturnMessage{}
+	args := append(append([]interface{}(nil), options.Args...), queue)
+	rows, err := runner.QueryContext(ctx, query, args...)
+	if err != nil {
+		return database.QueryResult{}, err
+	}
+	defer rows.Close()
+
+	var (
+		messages database.QueryMessageLog
+		affected *int64
+	)
+	result := database.QueryResult{
+		Rows:       make([][]interface{}, 0),
+		ResultSets: make([]database.QueryResultSet, 0),
+		RowLimit:   options.MaxRows,
+	}
+	for active := true; active; {
+		switch message := queue.Message(ctx).(type) {
+		case sqlexp.MsgNext:
+			set, err := readSQLServerResultSet(rows, len(result.ResultSets), options.MaxRows)
+			if err != nil {
+				return database.QueryResult{}, err
+			}
+			result.ResultSets = append(result.ResultSets, set)
+		case sqlexp.MsgNextResultSet:
+			active = rows.NextResultSet()
+		case sqlexp.MsgRowsAffected:
+			count := message.Count
+			affected = &count
+		case sqlexp.MsgNotice:
+			addSQLServerMessage(&messages, message.Message)
+		case sqlexp.MsgError:
+			return database.QueryResult{}, message.Error
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return database.QueryResult{}, err
+	}
+	if err := rows.Err(); err != nil {
+		return database.QueryResult{}, fmt.Errorf("read query result rows: %w", err)
+	}
+
+	if len(result.ResultSets) > 0 {
+		first := result.ResultSets[0]
+		result.Columns = first.Columns
+		result.Rows = first.Rows
+		result.Truncated = first.Truncated
+	} else {
+		result.Columns = make([]database.QueryColumn, 0)
+		result.ResultSets = append(result.ResultSets, database.QueryResultSet{
+			Columns:  result.Columns,
+			Rows:     result.Rows,
+			RowLimit: options.MaxRows,
+		})
+	}
+	if affected != nil && database.ReportsAffectedRows(query) {
+		result.RowsAffected = affected
+		result.ResultSets[0].RowsAffected = affected
+	}
+	result.Messages = messages.Messages()
+	result.StatementCount = len(result.ResultSets)
+	return result, nil
+}
+
+// readSQLServerResultSet reads the rows of the current result set. Rows
+// past the limit are still read so the queue can move on to the messages
+// that follow them.
+func readSQLServerResultSet(
+	rows *sql.Rows,
+	index int,
+	maxRows int,
+) (database.QueryResultSet, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return database.QueryResultSet{}, fmt.Errorf("read query result columns: %w", err)
+	}
+	set := database.QueryResultSet{
+		Index:    index,
+		Columns:  sqladapter.QueryColumns(columnTypes),
+		Rows:     make([][]interface{}, 0),
+		RowLimit: maxRows,
+	}
+	for rows.Next() {
+		if maxRows > 0 && len(set.Rows) >= maxRows {
+			set.Truncated = true
+			continue
+		}
+		row, err := sqladapter.ScanRow(rows, len(columnTypes))
+		if err != nil {
+			return database.QueryResultSet{}, fmt.Errorf("scan query result row: %w", err)
+		}
+		set.Rows = append(set.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return database.QueryResultSet{}, fmt.Errorf("read query result rows: %w", err)
+	}
+	return set, nil
+}
+
+func addSQLServerMessage(messages *database.QueryMessageLog, message fmt.Stringer) {
+	info, ok := message.(mssql.Error)
+	if !ok {
+		messages.Add(database.QueryMessageInfo, "", message.String())
+		return
+	}
+	messages.Add(
+		sqlServerMessageSeverity(info.Class),
+		strconv.FormatInt(int64(info.Number), 10),
+		info.Message,
+	)
+}
+
+// sqlServerMessageSeverity maps a message class onto the shared levels.
+// Class 0 is PRINT output and classes up to 10 are informational
+// RAISERROR messages; higher classes normally arrive as errors instead.
+func sqlServerMessageSeverity(class uint8) database.QueryMessageSeverity {
+	switch {
+	case class == 0:
+		return database.QueryMessageInfo
+	case class <= 10:
+		return database.QueryMessageNotice
+	default:
+		return database.QueryMessageError
+	}
+}
@@ -0,0 +1,54 @@
+package sqlserver
+
+import (
+	"context"
+
+	"rollingthunder/pkg/database"
+)
+
+func init() {
+	database.RegisterDriver(database.DriverSQLServer, open, probe)
+}
+
+func open(ctx context.Context, cfg database.Config) (database.Driver, error) {
+	return NewSQLServer(ctx, Config{
+		Host:          cfg.Host,
+		Port:          cfg.Port,
+		User:          cfg.User,
+		Password:      cfg.Password,
+		Db:            cfg.Db,
+		SSLMode:       cfg.SSLMode,
+		SSLRootCert:   cfg.SSLRootCert,
+		SSLCert:       cfg.SSLCert,
+		SSLKey:        cfg.SSLKey,
+		TLSServerName: cfg.TLSServerName,
+		AuthMode:      cfg.SQLServerAuthMode,
+		EntraClientID: cfg.SQLServerEntraClientID,
+		EntraTenantID: cfg.SQLServerEntraTenantID,
+	}), nil
+}
+
+// probe advertises native .bak backups. Files stay on the database server,
+// so backup directories are read per connection.
+func probe(database.ExecutableLookup) database.DriverProbe {
+	capabilities := (*SQLServer)(nil).Capabilities()
+	return database.DriverProbe{
+		Capabilities: capabilities,
+		Backup: database.BackupCapabilities{
+			Available:       true,
+			Engine:          capabilities.Engine,
+			Format:          database.BackupFormatSQLServerNative,
+			Extension:       ".bak",
+			BackupTool:      "BACKUP DATABASE",
+			RestoreTool:     "RESTORE DATABASE",
+			RestoreReady:    true,
+			BuiltIn:         true,
+			SupportsScope:   false,
+			ServerSideFiles: true,
+			Directories:     []database.BackupDirectory{},
+			Message: "Native .bak files stay on the SQL Server host. " +
+				"The configured database account and SQL Server service account " +
+				"must be allowed to use the selected server path.",
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package sqlserver
+
+import (
+	"strings"
+
+	"rollingthunder/pkg/database"
+)
+
+// ReturningQuery places an OUTPUT clause where T-SQL expects it: after the
+// SET list of an UPDATE, after the target of a DELETE, and before the
+// VALUES or SELECT of an INSERT. The driver drops the affected-row count
+// once a statement has OUTPUT, so @@ROWCOUNT is read in its place.
+func (s *SQLServer) ReturningQuery(statement string) (database.ReturningQuery, bool) {
+	words := database.TopLevelSQLWords(statement)
+	if len(words) == 0 || !database.ReportsAffectedRows(statement) {
+		return database.ReturningQuery{}, false
+	}
+	query := database.ReturningQuery{CountQuery: "SELECT @@ROWCOUNT"}
+	var (
+		clause   string
+		offset   int
+		found    bool
+		appendOK = true
+	)
+	switch words[0].Word {
+	case "UPDATE":
+		clause = "OUTPUT deleted.*, inserted.*"
+		query.Before, query.After = true, true
+		set := sqlServerWordIndex(words, 1, "SET")
+		if set < 0 {
+			return database.ReturningQuery{}, false
+		}
+		offset, found = sqlServerClauseOffset(words, set+1, "FROM", "WHERE", "OPTION")
+	case "DELETE":
+		clause = "OUTPUT deleted.*"
+		query.Before = true
+		target := 1
+		if target < len(words) && words[target].Word == "TOP" {
+			target++
+		}
+		if target < len(words) && words[target].Word == "FROM" {
+			target++
+		}
+		if target >= len(words) {
+			return database.ReturningQuery{}, false
+		}
+		offset, found = sqlServerClauseOffset(words, target+1, "FROM", "WHERE", "OPTION")
+	case "INSERT":
+		clause = "OUTPUT inserted.*"
+		query.After = true
+		appendOK = false
+		offset, found = sqlServerClauseOffset(words, 1, "VALUES", "SELECT", "DEFAULT", "EXEC", "EXECUTE")
+	default:
+		return database.ReturningQuery{}, false
+	}
+	switch {
+	case found:
+		query.Query = statement[:offset] + clause + "\n" + statement[offset:]
+	case appendOK:
+		// On its own line so a trailing line comment cannot swallow it.
+		query.Query = strings.TrimRight(strings.TrimSpace(statement), ";") + "\n" + clause
+	default:
+		return database.ReturningQuery{}, false
+	}
+	return query, true
+}
+
+func sqlServerWordIndex(words []database.SQLWord, from int, keyword string) int {
+	for index := from; index < len(words); index++ {
+		if words[index].Word == keyword {
+			return index
+		}
+	}
+	return -1
+}
+
+// sqlServerClauseOffset returns the offset of the first of keywords at or
+// after words[from].
+func sqlServerClauseOffset(words []database.SQLWord, from int, keywords ...string) (int, bool) {
+	for index := from; index < len(words); index++ {
+		for _, keyword := range keywords {
+			if words[index].Word == keyword {
+				return words[index].Offset, true
+			}
+		}
+	}
+	return 0, false
+}
+
+var _ database.ReturningDriver = (*SQLServer)(nil)
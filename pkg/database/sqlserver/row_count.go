@@ -0,0 +1,66 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+
+	"rollingthunder/pkg/database"
+	"rollingthunder/pkg/database/sqladapter"
+)
+
+// EstimateCollectionData sums the row counts sys.partitions keeps for the
+// heap or clustered index. They are maintained as rows change but are not
+// transactionally exact. A filtered table is estimated by the planner.
+func (s *SQLServer) EstimateCollectionData(
+	ctx context.Context,
+	table database.Table,
+) (database.RowCount, bool, error) {
+	if err := s.ensureConnected(); err != nil {
+		return database.RowCount{}, false, err
+	}
+	table.Schema = s.defaultSchema(table.Schema)
+	dialect := s.adapterDialect()
+	if table.Filtered() {
+		structures, err := s.GetCollectionStructures(table)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		table.Sorts = nil
+		query, args, err := sqladapter.BuildTableSelect(
+			table,
+			structures,
+			"*",
+			dialect,
+			false,
+		)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		plan, err := s.ExplainQueryWithArgs(ctx, query, args)
+		if err != nil {
+			return database.RowCount{}, false, err
+		}
+		count, ok := database.PlannerRowCount(plan)
+		return count, ok, nil
+	}
+
+	var rows sql.NullInt64
+	if err := s.conn.QueryRowContext(
+		ctx,
+		`SELECT SUM(partition_object.rows)
+		FROM sys.partitions partition_object
+		WHERE partition_object.object_id = OBJECT_ID(@p1)
+			AND partition_object.index_id IN (0, 1)`,
+		dialect.QuoteQualified(table.Schema, table.Name),
+	).Scan(&rows); err != nil {
+		return database.RowCount{}, false, err
+	}
+	if !rows.Valid {
+		return database.RowCount{}, false, nil
+	}
+	return database.RowCount{
+		Rows:      rows.Int64,
+		Estimated: true,
+		Source:    database.RowCountStatistics,
+	}, true, nil
+}
@@ -353,6 +353,13 @@ func (s *SQLServer) defaultSchema(schema string) string {
 }
 
 func (s *SQLServer) CountCollectionData(table database.Table) (int, error) {
+	return s.CountCollectionDataContext(s.ctx, table)
+}
+
+func (s *SQLServer) CountCollectionDataContext(
+	ctx context.Context,
+	table database.Table,
+) (int, error) {
 	if err := s.ensureConnected(); err != nil {
 		return 0, err
 	}
@@ -361,7 +368,7 @@ func (s *SQLServer) CountCollectionData(table database.Table) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	return sqladapter.CountTable(s.conn, table, structures, s.adapterDialect())
+	return sqladapter.CountTable(ctx, s.conn, table, structures, s.adapterDialect())
 }
 
 func (s *SQLServer) GetCollectionData(
@@ -463,7 +470,18 @@ func (s *SQLServer) ExecuteQuery(
 	if err := s.ensureConnected(); err != nil {
 		return database.QueryResult{}, err
 	}
-	return sqladapter.ExecuteQuery(ctx, s.conn, query, options)
+	return executeSQLServerQuery(ctx, s.conn, query, options)
+}
+
+func (s *SQLServer) OpenQueryCursor(
+	ctx context.Context,
+	query string,
+	options database.QueryOptions,
+) (database.QueryCursor, error) {
+	if err := s.ensureConnected(); err != nil {
+		return nil, err
+	}
+	return sqladapter.OpenCursor(ctx, s.conn, query, options, nil)
 }
 
 type sqlServerTransaction struct {
@@ -475,7 +493,7 @@ func (transaction *sqlServerTransaction) ExecuteQuery(
 	query string,
 	options database.QueryOptions,
 ) (database.QueryResult, error) {
-	return sqladapter.ExecuteQuery(ctx, transaction.tx, query, options)
+	return executeSQLServerQuery(ctx, transaction.tx, query, options)
 }
 
 func (transaction *sqlServerTransaction) Commit() error {
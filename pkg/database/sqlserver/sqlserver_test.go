@@ -4,12 +4,15 @@ import (
 	"context"
 	"net/url"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"rollingthunder/pkg/database"
 	"rollingthunder/pkg/database/drivertest"
 	"rollingthunder/pkg/database/sqladapter"
+
+	mssql "github.com/microsoft/go-mssqldb"
 )
 
 func TestSQLServerCapabilityContract(t *testing.T) {
@@ -588,8 +591,28 @@ func TestSQLServerLiveConformance(t *testing.T) {
 		Schema:             driver.currentSchema,
 		IntegerType:        "INT",
 		TextType:           "NVARCHAR(255)",
+		JSONType:           "NVARCHAR(MAX)",
 		ExercisePrivileged: os.Getenv("ROLLINGTHUNDER_TEST_PRIVILEGED") == "1",
 	})
+	t.Run("server messages", func(t *testing.T) {
+		result, err := driver.ExecuteQuery(
+			context.Background(),
+			"PRINT N'checking'; RAISERROR (N'halfway', 10, 1); SELECT 1 AS one;",
+			database.QueryOptions{MaxRows: 10},
+		)
+		if err != nil {
+			t.Fatalf("ExecuteQuery() error = %v", err)
+		}
+		if len(result.Rows) != 1 || len(result.Messages) != 2 {
+			t.Fatalf("result = %+v", result)
+		}
+		if result.Messages[0].Text != "checking" ||
+			result.Messages[0].Severity != database.QueryMessageInfo ||
+			result.Messages[1].Text != "halfway" ||
+			result.Messages[1].Sequence != 2 {
+			t.Fatalf("messages = %+v", result.Messages)
+		}
+	})
 	if os.Getenv("ROLLINGTHUNDER_TEST_PRIVILEGED") == "1" {
 		t.Run("server security parity", func(t *testing.T) {
 			runSQLServerSecurityConformance(t, driver)
@@ -685,3 +708,43 @@ func TestSQLServerLiveConformance(t *testing.T) {
 		}
 	})
 }
+
+func TestAddSQLServerMessageKeepsClassAndNumber(t *testing.T) {
+	var messages database.QueryMessageLog
+	addSQLServerMessage(&messages, mssql.Error{Number: 0, Class: 0, Message: "checking"})
+	addSQLServerMessage(&messages, mssql.Error{Number: 50000, Class: 10, Message: "halfway"})
+
+	got := messages.Messages()
+	want := []database.QueryMessage{
+		{Sequence: 1, Severity: database.QueryMessageInfo, Code: "0", Text: "checking"},
+		{Sequence: 2, Severity: database.QueryMessageNotice, Code: "50000", Text: "halfway"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("messages = %#v, want %#v", got, want)
+	}
+}
+
+func TestSQLServerReturningQueryPlacesOutputClause(t *testing.T) {
+	driver := NewSQLServer(context.Background(), Config{})
+	for statement, want := range map[string]string{
+		"UPDATE dbo.orders SET status = 'paid' WHERE id = @p1":     "UPDATE dbo.orders SET status = 'paid' OUTPUT deleted.*, inserted.*\nWHERE id = @p1",
+		"UPDATE o SET total = (SELECT 1 FROM t) FROM dbo.orders o": "UPDATE o SET total = (SELECT 1 FROM t) OUTPUT deleted.*, inserted.*\nFROM dbo.orders o",
+		"DELETE FROM dbo.orders -- everything":                     "DELETE FROM dbo.orders -- everything\nOUTPUT deleted.*",
+		"DELETE TOP (10) FROM dbo.orders WHERE id > 3":             "DELETE TOP (10) FROM dbo.orders OUTPUT deleted.*\nWHERE id > 3",
+		"INSERT INTO dbo.orders (id, total) VALUES (1, 2)":         "INSERT INTO dbo.orders (id, total) OUTPUT inserted.*\nVALUES (1, 2)",
+	} {
+		query, ok := driver.ReturningQuery(statement)
+		if !ok || query.Query != want || query.CountQuery == "" {
+			t.Fatalf("ReturningQuery(%q) = %+v, %t, want %q", statement, query, ok, want)
+		}
+	}
+	for _, statement := range []string{
+		"SELECT * FROM dbo.orders",
+		"DELETE FROM dbo.orders OUTPUT deleted.id",
+		"MERGE dbo.orders AS target USING source ON 1 = 0 WHEN NOT MATCHED THEN INSERT (id) VALUES (1)",
+	} {
+		if query, ok := driver.ReturningQuery(statement); ok {
+			t.Fatalf("ReturningQuery(%q) = %+v, want no rewrite", statement, query)
+		}
+	}
+}
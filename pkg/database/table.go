@@ -14,22 +14,48 @@ const (
 	NullsLast  NullsPosition = "last"
 )
 
+// Sort orders by a column, or with Path set by the JSON value at that path
+// inside the column.
 type Sort struct {
 	Column    string
+	Path      string `json:",omitempty"`
 	Direction SortDirection
 	Nulls     NullsPosition
 }
 
+// Table addresses a table and the page of it to read. Filters are always
+// ANDed; Where holds an optional filter tree that is ANDed with them.
+// After is a continuation token from TableData.NextPage; when the service
+// accepts it, the page starts after that token's row and Offset is ignored.
 type Table struct {
 	Schema  string
 	Name    string
 	Offset  int
 	Limit   int
 	Filters []Filter
+	Where   *FilterGroup `json:",omitempty"`
 	Sorts   []Sort
+	After   string `json:",omitempty"`
+}
+
+// FilterTree returns Filters and Where combined into a single group.
+func (table Table) FilterTree() FilterGroup {
+	tree := FilterGroup{Logic: FilterAnd, Filters: table.Filters}
+	if table.Where != nil {
+		tree.Groups = []FilterGroup{*table.Where}
+	}
+	return tree
+}
+
+// Filtered reports whether any filter narrows the rows read from the table.
+func (table Table) Filtered() bool {
+	return len(table.Filters) > 0 || table.Where != nil
 }
 
 type TableData struct {
 	Structures Structures               `json:"structures"`
 	Data       []map[string]interface{} `json:"data"`
+	// NextPage is an opaque token for the page after this one, empty when
+	// the table cannot be paged by key or this was the last page.
+	NextPage string `json:"next_page,omitempty"`
 }
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type XLSXOptions struct {
+	IncludeHeader bool `json:"includeHeader"`
+	// StyleHeader bolds the header row and freezes it above the data.
+	StyleHeader bool `json:"styleHeader"`
+}
+
+// xlsxMaxDigits is the number of significant digits a worksheet number keeps.
+// Longer values are written as text rather than silently rounded.
+const xlsxMaxDigits = 15
+
+type xlsxColumn struct {
+	kind     parquetKind
+	declared bool
+	dateOnly bool
+}
+
+// xlsxSink streams rows into worksheets. excelize spills each worksheet to a
+// temporary file once it outgrows memory, and a new worksheet is started
+// whenever one reaches the row limit.
+type xlsxSink struct {
+	file          *excelize.File
+	stream        *excelize.StreamWriter
+	options       XLSXOptions
+	columns       []string
+	types         []xlsxColumn
+	sheets        int
+	row           int
+	headerStyle   int
+	dateStyle     int
+	dateTimeStyle int
+}
+
+func newXLSXSink(columns []string, structures Structures, options XLSXOptions) (*xlsxSink, error) {
+	if len(columns) > excelize.MaxColumns {
+		return nil, fmt.Errorf(
+			"a worksheet holds at most %d columns, got %d",
+			excelize.MaxColumns,
+			len(columns),
+		)
+	}
+	declared := make(map[string]Structure, len(structures))
+	for _, structure := range structures {
+		declared[structure.Name] = structure
+	}
+	types := make([]xlsxColumn, len(columns))
+	for index, name := range columns {
+		structure, ok := declared[name]
+		if !ok {
+			continue
+		}
+		if column, typed := parquetColumnFor(structure); typed {
+			types[index] = xlsxColumn{
+				kind:     column.kind,
+				declared: true,
+				dateOnly: strings.EqualFold(strings.TrimSpace(structure.DataType), "date"),
+			}
+		}
+	}
+
+	file := excelize.NewFile()
+	sink := &xlsxSink{file: file, options: options, columns: columns, types: types}
+	var err error
+	if sink.headerStyle, err = file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	dateFormat, dateTimeFormat := "yyyy-mm-dd", "yyyy-mm-dd hh:mm:ss"
+	if sink.dateStyle, err = file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if sink.dateTimeStyle, err = file.NewStyle(&excelize.Style{CustomNumFmt: &dateTimeFormat}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *xlsxSink) startSheet() error {
+	if s.stream != nil {
+		if err := s.stream.Flush(); err != nil {
+			return err
+		}
+	}
+	s.sheets++
+	name := fmt.Sprintf("Sheet%d", s.sheets)
+	if s.sheets > 1 {
+		if _, err := s.file.NewSheet(name); err != nil {
+			return err
+		}
+	}
+	stream, err := s.file.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+	s.stream, s.row = stream, 0
+	if !s.options.IncludeHeader {
+		return nil
+	}
+	header := make([]interface{}, len(s.columns))
+	for index, column := range s.columns {
+		header[index] = column
+	}
+	var rowOptions []excelize.RowOpts
+	if s.options.StyleHeader {
+		if err := stream.SetPanes(&excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}); err != nil {
+			return err
+		}
+		rowOptions = append(rowOptions, excelize.RowOpts{StyleID: s.headerStyle})
+	}
+	return s.writeRow(header, rowOptions...)
+}
+
+func (s *xlsxSink) writeRow(values []interface{}, options ...excelize.RowOpts) error {
+	s.row++
+	cell, err := excelize.CoordinatesToCellName(1, s.row)
+	if err != nil {
+		return err
+	}
+	return s.stream.SetRow(cell, values, options...)
+}
+
+func (s *xlsxSink) writeValues(values []interface{}) error {
+	if s.stream == nil || s.row >= excelize.TotalRows {
+		if err := s.startSheet(); err != nil {
+			return err
+		}
+	}
+	cells := make([]interface{}, len(values))
+	for index, value := range values {
+		cell, err := s.cellValue(value, s.types[index])
+		if err != nil {
+			return fmt.Errorf("column %q: %w", s.columns[index], err)
+		}
+		cells[index] = cell
+	}
+	return s.writeRow(cells)
+}
+
+// cellValue converts a value to a native number, boolean, or date cell where
+// that loses nothing. Declared numeric, boolean, and timestamp columns also
+// convert the text some drivers return for them.
+func (s *xlsxSink) cellValue(value interface{}, column xlsxColumn) (interface{}, error) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return typed, nil
+	case time.Time:
+		return s.timeCell(typed, column), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		text := fmt.Sprint(typed)
+		if xlsxDigits(text) > xlsxMaxDigits {
+			return text, nil
+		}
+		return typed, nil
+	case uint64:
+		if typed > 1e15-1 {
+			return strconv.FormatUint(typed, 10), nil
+		}
+		return typed, nil
+	case float32:
+		return xlsxFloat(float64(typed)), nil
+	case float64:
+		return xlsxFloat(typed), nil
+	}
+
+	text, err := formatCSVValue(value, "")
+	if err != nil {
+		return nil, err
+	}
+	if !column.declared {
+		return text, nil
+	}
+	switch column.kind {
+	case parquetInt64, parquetDouble, parquetDecimal:
+		if number, ok := xlsxNumber(text); ok {
+			return number, nil
+		}
+	case parquetBoolean:
+		if boolean, err := parquetBooleanValue(value); err == nil {
+			return boolean, nil
+		}
+	case parquetTimestamp, parquetLocalTimestamp:
+		for _, layout := range parquetTimeLayouts {
+			if moment, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+				return s.timeCell(moment, column), nil
+			}
+		}
+	}
+	return text, nil
+}
+
+func (s *xlsxSink) timeCell(value time.Time, column xlsxColumn) excelize.Cell {
+	style := s.dateTimeStyle
+	if column.dateOnly {
+		style = s.dateStyle
+	}
+	return excelize.Cell{StyleID: style, Value: value}
+}
+
+func xlsxFloat(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return value
+}
+
+// xlsxNumber parses a decimal string that a worksheet number holds exactly.
+func xlsxNumber(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	if xlsxDigits(text) > xlsxMaxDigits {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	return number, true
+}
+
+// xlsxDigits counts the significant digits of a plain decimal string.
+func xlsxDigits(text string) int {
+	digits, leading := 0, true
+	for _, character := range strings.TrimLeft(text, "+-") {
+		switch {
+		case character == '.':
+		case character == '0' && leading:
+		case character >= '0' && character <= '9':
+			leading = false
+			digits++
+		default:
+			// Exponents and anything else are left to ParseFloat.
+			return digits
+		}
+	}
+	// Trailing zeros after the decimal point do not need precision.
+	if dot := strings.IndexByte(text, '.'); dot >= 0 && !leading {
+		fraction := text[dot+1:]
+		digits -= len(fraction) - len(strings.TrimRight(fraction, "0"))
+	}
+	return digits
+}
+
+func (s *xlsxSink) close(writer io.Writer) error {
+	if s.stream == nil {
+		if err := s.startSheet(); err != nil {
+			return err
+		}
+	}
+	if err := s.stream.Flush(); err != nil {
+		return err
+	}
+	return s.file.Write(writer)
+}
+
+// WriteXLSXStreamContext writes rows as an Excel workbook. A worksheet holds
+// at most 1,048,576 rows including its header, so longer exports continue on
+// Sheet2, Sheet3, and so on.
+func WriteXLSXStreamContext(
+	ctx context.Context,
+	writer io.Writer,
+	rows RowStream,
+	structures Structures,
+	options XLSXOptions,
+) (ExportStats, error) {
+	if err := CheckExportContext(ctx); err != nil {
+		return ExportStats{}, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return ExportStats{}, fmt.Errorf("read export columns: %w", err)
+	}
+	sink, err := newXLSXSink(columns, structures, options)
+	if err != nil {
+		return ExportStats{}, err
+	}
+	defer sink.file.Close()
+
+	var count int64
+	for rows.Next() {
+		if err := CheckExportContext(ctx); err != nil {
+			return ExportStats{}, err
+		}
+		values, err := rows.Values()
+		if err != nil {
+			return ExportStats{}, fmt.Errorf("read export row: %w", err)
+		}
+		if len(values) != len(columns) {
+			return ExportStats{}, fmt.Errorf(
+				"export row has %d values for %d columns",
+				len(values),
+				len(columns),
+			)
+		}
+		if err := sink.writeValues(values); err != nil {
+			return ExportStats{}, fmt.Errorf("write worksheet row: %w", err)
+		}
+		count++
+		ReportExportProgress(ctx, count)
+	}
+	if err := rows.Err(); err != nil {
+		return ExportStats{}, fmt.Errorf("read export rows: %w", err)
+	}
+	if err := CheckExportContext(ctx); err != nil {
+		return ExportStats{}, err
+	}
+	if err := sink.close(writer); err != nil {
+		return ExportStats{}, fmt.Errorf("finish Excel export: %w", err)
+	}
+	return ExportStats{Rows: count}, nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteXLSXWritesNativeCellsAndStyledHeader(t *testing.T) {
+	var output bytes.Buffer
+	stats, err := WriteTypedExportRowsContext(
+		context.Background(),
+		&output,
+		[]string{"id", "price", "placed_on", "paid", "code", "big"},
+		[]map[string]interface{}{
+			{
+				"id":        int64(1),
+				"price":     "12.50",
+				"placed_on": time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC),
+				"paid":      true,
+				"code":      "007",
+				"big":       int64(1234567890123456789),
+			},
+			{
+				"id":        int64(2),
+				"price":     nil,
+				"placed_on": "2026-05-02T00:00:00Z",
+				"paid":      "f",
+				"code":      "x",
+				"big":       "12345678901234567890.5",
+			},
+		},
+		Structures{
+			{Name: "id", DataType: "bigint"},
+			{Name: "price", DataType: "numeric(12,2)", Nullable: true},
+			{Name: "placed_on", DataType: "date"},
+			{Name: "paid", DataType: "boolean"},
+			{Name: "big", DataType: "numeric"},
+		},
+		ExportOptions{
+			Format: ExportFormatXLSX,
+			XLSX:   XLSXOptions{IncludeHeader: true, StyleHeader: true},
+		},
+	)
+	if err != nil {
+		t.Fatalf("WriteTypedExportRowsContext() error = %v", err)
+	}
+	if stats.Rows != 2 {
+		t.Fatalf("rows = %d", stats.Rows)
+	}
+
+	workbook, err := excelize.OpenReader(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer workbook.Close()
+	if sheets := workbook.GetSheetList(); len(sheets) != 1 || sheets[0] != "Sheet1" {
+		t.Fatalf("sheets = %v", sheets)
+	}
+	for cell, want := range map[string]excelize.CellType{
+		"A1": excelize.CellTypeInlineString,
+		"A2": excelize.CellTypeUnset,
+		"B2": excelize.CellTypeUnset,
+		"C2": excelize.CellTypeUnset,
+		"C3": excelize.CellTypeUnset,
+		"D2": excelize.CellTypeBool,
+		"D3": excelize.CellTypeBool,
+		"E2": excelize.CellTypeInlineString,
+		"F2": excelize.CellTypeInlineString,
+		"F3": excelize.CellTypeInlineString,
+	} {
+		got, err := workbook.GetCellType("Sheet1", cell)
+		if err != nil || got != want {
+			t.Fatalf("cell %s type = %v, %v, want %v", cell, got, err, want)
+		}
+	}
+	for cell, want := range map[string]string{
+		"B2": "12.5",
+		"C2": "2026-05-01",
+		"C3": "2026-05-02",
+		"D3": "FALSE",
+		"E2": "007",
+		"F2": "1234567890123456789",
+	} {
+		got, err := workbook.GetCellValue("Sheet1", cell)
+		if err != nil || got != want {
+			t.Fatalf("cell %s = %q, %v, want %q", cell, got, err, want)
+		}
+	}
+	panes, err := workbook.GetPanes("Sheet1")
+	if err != nil || !panes.Freeze || panes.YSplit != 1 {
+		t.Fatalf("panes = %+v, %v", panes, err)
+	}
+	style, err := workbook.GetCellStyle("Sheet1", "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	definition, err := workbook.GetStyle(style)
+	if err != nil || definition.Font == nil || !definition.Font.Bold {
+		t.Fatalf("header style = %+v, %v", definition, err)
+	}
+}
+
+func TestXLSXDigits(t *testing.T) {
+	for text, want := range map[string]int{
+		"0":                  0,
+		"100.00":             3,
+		"-0.0012":            2,
+		"123456789012345":    15,
+		"1234567890123456.5": 17,
+		"1.5e300":            2,
+	} {
+		if got := xlsxDigits(text); got != want {
+			t.Fatalf("xlsxDigits(%q) = %d, want %d", text, got, want)
+		}
+	}
+}